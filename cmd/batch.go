@@ -0,0 +1,308 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/history"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// rosterMembersKey is the key of the members of the roster file.
+	rosterMembersKey = "members"
+	// rosterNameKey is the key of the name of a member.
+	rosterNameKey = "name"
+	// rosterAssigneeKey is the key of the Jira account ID or username of a
+	// member.
+	rosterAssigneeKey = "assignee"
+	// defaultBatchInterval is the default minimum time between the updates
+	// of two members.
+	defaultBatchInterval = 10 * time.Second
+)
+
+var (
+	// errEmptyRoster is returned when the roster file has no members.
+	errEmptyRoster = errors.New("no members in the roster, add [[members]] entries to the roster file")
+	// errInvalidMember is returned when a member of the roster misses its
+	// name or assignee, or has an invalid setting.
+	errInvalidMember = errors.New("invalid roster member")
+	// errBatchFlag is returned when a flag unsupported by the batch mode is
+	// set.
+	errBatchFlag = errors.New("cannot be used with batch")
+	// errBatchFailed is returned when the update of any member failed.
+	errBatchFailed = errors.New("the updates of some members failed")
+)
+
+// rosterSettings are the settings which cannot be set for the members, as
+// they apply to the whole batch.
+var rosterSettings = []string{"assignees", profilesKey, "profile", scheduleKey, "interval", "timeout"}
+
+var batchCmd = &cobra.Command{
+	Use:     "batch ROSTER",
+	Short:   "Generate and deliver the sprint updates of the members of a roster.",
+	Long:    "Generate the sprint update of every member of the roster file, one after the other, and deliver it to the targets of the member. The members are tables under \"members\", like [[members]], holding the name and the Jira account of the member, and the settings of the member, like the delivery targets, which take precedence over the flags and the configuration file. When no targets are set, the updates are posted to Discourse.",
+	Example: fmt.Sprintf("%s batch team.toml --sprint SE.253 --interval 30s", program),
+	Args:    cobra.ExactArgs(1),
+	Run:     runBatchCmd,
+}
+
+func init() {
+	addGenerationFlags(batchCmd.Flags())
+	addDeliveryFlags(batchCmd.Flags())
+	batchCmd.Flags().DurationP("interval", "", defaultBatchInterval, "minimum time between the updates of two members, limiting the rate of the requests to jira and the delivery targets")
+	rootCmd.AddCommand(batchCmd)
+}
+
+// rosterMember is a member of the roster file.
+type rosterMember struct {
+	// Name is the name of the member, printed in the progress messages, and
+	// naming the state file and the history directory of the member.
+	Name string
+	// Settings are the settings of the member, including the assignee and
+	// the delivery targets, by their keys.
+	Settings map[string]interface{}
+}
+
+// readRoster returns the members of the roster file, checking that every
+// member has a name and an assignee, and that their settings are known.
+func readRoster(path string) ([]rosterMember, error) {
+	file := viper.New()
+	file.SetConfigFile(expandPath(path))
+	if err := file.ReadInConfig(); err != nil {
+		return nil, err
+	}
+
+	entries, ok := file.Get(rosterMembersKey).([]interface{})
+	if !ok || len(entries) == 0 {
+		return nil, errEmptyRoster
+	}
+
+	schema := configSchema()
+	members := make([]rosterMember, 0, len(entries))
+
+	for i, entry := range entries {
+		settings, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: %s[%d] is not a table", errInvalidMember, rosterMembersKey, i)
+		}
+
+		name, _ := settings[rosterNameKey].(string)
+		if assignee, _ := settings[rosterAssigneeKey].(string); name == "" || assignee == "" {
+			return nil, fmt.Errorf("%w: %s[%d] needs a %s and an %s", errInvalidMember, rosterMembersKey, i, rosterNameKey, rosterAssigneeKey)
+		}
+
+		member := rosterMember{Name: name, Settings: make(map[string]interface{})}
+		for key, value := range settings {
+			key = strings.ToLower(key)
+			if key == rosterNameKey {
+				continue
+			}
+
+			if err := checkMemberSetting(key, schema); err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", errInvalidMember, name, err)
+			}
+
+			member.Settings[key] = value
+		}
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// checkMemberSetting checks that the setting is known and can be set for a
+// member.
+func checkMemberSetting(key string, schema map[string]settingKind) error {
+	if _, ok := schema[key]; !ok {
+		if closest := closestSetting(key, schema); closest != "" {
+			return fmt.Errorf("%w: %s (did you mean %s?)", errUnknownSetting, key, closest)
+		}
+
+		return fmt.Errorf("%w: %s", errUnknownSetting, key)
+	}
+
+	for _, setting := range rosterSettings {
+		if key == setting {
+			return fmt.Errorf("%s cannot be set for a member", key)
+		}
+	}
+
+	return nil
+}
+
+// memberSlug returns the name of the member usable in file names, like
+// "alice-smith" for "Alice Smith".
+func memberSlug(name string) string {
+	return history.SanitizeName(strings.Join(strings.Fields(strings.ToLower(name)), "-"))
+}
+
+// memberPath returns the path of the file or directory of the member, like
+// "state.alice-smith.json" for "state.json", so the members do not share the
+// unresolved issues and the previous updates.
+func memberPath(path string, name string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + memberSlug(name) + ext
+}
+
+// applyMemberSettings overrides the settings by the settings of the member,
+// and returns the function restoring the previous settings. Unless the
+// member sets them, the state file and the history directory of the member
+// are derived from the given ones.
+func applyMemberSettings(member *rosterMember, stateFile string, historyDir string) func() {
+	settings := map[string]interface{}{
+		"state-file":  memberPath(stateFile, member.Name),
+		"history-dir": memberPath(historyDir, member.Name),
+	}
+
+	for key, value := range member.Settings {
+		settings[key] = value
+	}
+
+	previous := make(map[string]interface{}, len(settings))
+	for key, value := range settings {
+		// Overriding a setting by nil unsets the override, hence the
+		// settings which were not set are not set after restoring them.
+		if viper.IsSet(key) {
+			previous[key] = viper.Get(key)
+		} else {
+			previous[key] = nil
+		}
+
+		viper.Set(key, value)
+	}
+
+	return func() {
+		for key, value := range previous {
+			viper.Set(key, value)
+		}
+	}
+}
+
+// checkBatchFlags checks that neither the flags requiring the user, as the
+// updates are generated unattended, nor the team updates are set, as every
+// member gets an update of their own.
+func checkBatchFlags() error {
+	for _, flag := range []string{"interactive", "edit"} {
+		if viper.GetBool(flag) {
+			return fmt.Errorf("--%s %w", flag, errBatchFlag)
+		}
+	}
+
+	if viper.GetString("record") != "" {
+		return fmt.Errorf("--record %w", errBatchFlag)
+	}
+
+	if len(viper.GetStringSlice("assignees")) > 0 {
+		return fmt.Errorf("--assignees %w", errBatchFlag)
+	}
+
+	return nil
+}
+
+// runBatchCmd generates and delivers the update of every member of the
+// roster, waiting at least the interval between the updates of two members.
+// A failed update is reported without stopping the batch, so the updates of
+// the other members are attempted, and the command fails at the end.
+func runBatchCmd(cmd *cobra.Command, args []string) {
+	checkErr(checkBatchFlags())
+
+	members, err := readRoster(args[0])
+	checkErr(configError(err))
+
+	stateFile, err := stateFilePath()
+	checkErr(err)
+
+	historyDir, err := historyDirPath()
+	checkErr(err)
+
+	// The Jira secret is read once, as it can be piped only once.
+	base := newConfig()
+	_, err = prepareConfig(&base)
+	checkErr(err)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	interval := viper.GetDuration("interval")
+
+	var failed []string
+	var code int
+	var last time.Time
+
+	for i := range members {
+		if !last.IsZero() {
+			timer := time.NewTimer(time.Until(last.Add(interval)))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				checkErr(ctx.Err())
+			case <-timer.C:
+			}
+		}
+
+		last = time.Now()
+		printStatus("Generating the update of", members[i].Name)
+
+		if err = runMemberUpdate(ctx, &members[i], &base, stateFile, historyDir); err != nil {
+			printError(fmt.Errorf("%s: %w", members[i].Name, err), exitCode(err))
+
+			if code == 0 {
+				code = exitCode(err)
+			}
+
+			failed = append(failed, members[i].Name)
+		}
+	}
+
+	printStatus(fmt.Sprintf("Generated the updates of %d of %d members", len(members)-len(failed), len(members)))
+
+	if len(failed) > 0 {
+		checkErr(&exitError{code: code, err: fmt.Errorf("%w: %s", errBatchFailed, strings.Join(failed, ", "))})
+	}
+}
+
+// runMemberUpdate generates the update of the member and delivers it to the
+// targets of the member, or to Discourse if no targets are set. The Jira
+// secret is taken from the prepared base configuration.
+func runMemberUpdate(ctx context.Context, member *rosterMember, base *sprint.Config, stateFile string, historyDir string) error {
+	restore := applyMemberSettings(member, stateFile, historyDir)
+	defer restore()
+
+	targets, err := deliveryTargets()
+	if err != nil {
+		return configError(err)
+	}
+
+	if len(targets) == 0 {
+		targets = []string{targetDiscourse}
+	}
+
+	config := newConfig()
+	if viper.GetBool("sample") {
+		return runUpdate(ctx, config, nil, targets)
+	}
+
+	if _, err = setupSnapshot(&config); err != nil {
+		return err
+	}
+
+	if viper.GetString("replay") == "" {
+		config.Password = base.Password
+		config.Token = base.Token
+	}
+
+	if err = config.Validate(); err != nil {
+		return configError(err)
+	}
+
+	return runUpdate(ctx, config, nil, targets)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"gabor-boros/sprint-update/pkg/cache"
+
+	"github.com/spf13/viper"
+)
+
+// newCache returns the cache of the Jira metadata, or nil if caching is
+// disabled. When refreshing the cache, the cached metadata is removed, so it
+// is fetched again.
+func newCache() (*cache.Cache, error) {
+	ttl := viper.GetDuration("cache-ttl")
+	if ttl <= 0 {
+		return nil, nil
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	c := cache.New(filepath.Join(cacheDir, program, profileFile("metadata.json")), ttl)
+	if viper.GetBool("refresh-cache") {
+		if err = c.Clear(); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/calendar"
+	"gabor-boros/sprint-update/pkg/tempo"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// calendarICS reads the calendar from an iCalendar feed.
+	calendarICS = "ics"
+	// calendarCalDAV reads the calendar from a CalDAV server.
+	calendarCalDAV = "caldav"
+	// calendarTempo reads the leave planned in Tempo Planner.
+	calendarTempo = "tempo"
+	// calendarBambooHR reads the approved time off requests of BambooHR.
+	calendarBambooHR = "bamboohr"
+)
+
+// calendarTypes lists the supported calendar types.
+var calendarTypes = []string{calendarICS, calendarCalDAV, calendarTempo, calendarBambooHR}
+
+// errUnknownCalendarType is returned when the calendar type is not supported.
+var errUnknownCalendarType = errors.New("unknown calendar type")
+
+// newCalendar returns the configured calendar the time off is looked up in.
+// If no calendar is configured, nil is returned. The leave integrations need
+// no calendar URL.
+func newCalendar() (calendar.Source, error) {
+	switch viper.GetString("calendar-type") {
+	case calendarTempo:
+		return &tempo.Leave{
+			Client: &tempo.Client{
+				URL:        viper.GetString("tempo-url"),
+				Token:      secret("tempo-token"),
+				HTTPClient: newHTTPClient(),
+			},
+			LeaveIssueIDs: viper.GetStringSlice("tempo-leave-issue-ids"),
+		}, nil
+	case calendarBambooHR:
+		return &calendar.BambooHR{
+			Company:    viper.GetString("bamboohr-company"),
+			EmployeeID: viper.GetString("bamboohr-employee-id"),
+			Token:      secret("bamboohr-token"),
+			HTTPClient: newHTTPClient(),
+		}, nil
+	}
+
+	url := viper.GetString("calendar-url")
+	if url == "" {
+		return nil, nil
+	}
+
+	switch calendarType := viper.GetString("calendar-type"); calendarType {
+	case calendarICS:
+		return &calendar.ICS{URL: url, HTTPClient: newHTTPClient()}, nil
+	case calendarCalDAV:
+		return &calendar.CalDAV{
+			URL:        url,
+			Username:   viper.GetString("calendar-username"),
+			Password:   secret("calendar-password"),
+			HTTPClient: newHTTPClient(),
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s (available: %s)", errUnknownCalendarType, calendarType, strings.Join(calendarTypes, ", "))
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// errNoClipboard is returned when no clipboard utility is found.
+var errNoClipboard = errors.New("no clipboard utility found (install wl-clipboard, xclip, or xsel)")
+
+// clipboardCommands returns the commands copying their standard input to the
+// clipboard on the current platform, in the order of preference.
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+
+	var commands [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		commands = append(commands, []string{"wl-copy"})
+	}
+
+	return append(commands,
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"},
+	)
+}
+
+// copyToClipboard places the text on the system clipboard using the first
+// clipboard utility found.
+func copyToClipboard(text string) error {
+	for _, command := range clipboardCommands() {
+		path, err := exec.LookPath(command[0])
+		if err != nil {
+			continue
+		}
+
+		// #nosec G204 -- the commands are predefined clipboard utilities.
+		cmd := exec.Command(path, command[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		cmd.Stderr = os.Stderr
+
+		return cmd.Run()
+	}
+
+	return errNoClipboard
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"gabor-boros/sprint-update/pkg/codehost"
+	"gabor-boros/sprint-update/pkg/github"
+
+	"github.com/spf13/viper"
+)
+
+// newCodeHosts returns the configured code hosts the pull requests of the
+// sprint are listed from. The code hosts without credentials are skipped.
+func newCodeHosts() []codehost.CodeHost {
+	var hosts []codehost.CodeHost
+
+	if token := secret("github-token"); token != "" {
+		client := github.NewClient(viper.GetString("github-url"), token)
+		client.HTTPClient = newHTTPClient()
+
+		hosts = append(hosts, &codehost.GitHub{
+			Client: client,
+			Scopes: viper.GetStringSlice("github-repos"),
+		})
+	}
+
+	if token := secret("gitlab-token"); token != "" {
+		hosts = append(hosts, &codehost.GitLab{
+			BaseURL:    viper.GetString("gitlab-url"),
+			Token:      token,
+			Scopes:     viper.GetStringSlice("gitlab-projects"),
+			HTTPClient: newHTTPClient(),
+		})
+	}
+
+	if appPassword := secret("bitbucket-app-password"); appPassword != "" {
+		hosts = append(hosts, &codehost.Bitbucket{
+			BaseURL:     viper.GetString("bitbucket-url"),
+			Username:    viper.GetString("bitbucket-username"),
+			AppPassword: appPassword,
+			Scopes:      viper.GetStringSlice("bitbucket-repos"),
+			HTTPClient:  newHTTPClient(),
+		})
+	}
+
+	return hosts
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// completionTimeout is the maximum duration of listing the sprints when
+	// completing the sprint names, so the shell is not blocked for long.
+	completionTimeout = 5 * time.Second
+	// completionClosedSprints is the number of recently closed sprints
+	// offered when completing the sprint names.
+	completionClosedSprints = 5
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate the shell completion script.",
+	Long: fmt.Sprintf(`Generate the completion script of the shell, which completes the commands,
+the flags, the sprint names of the configured board, and the profile names.
+
+Bash:
+  $ source <(%[1]s completion bash)
+
+Zsh:
+  $ %[1]s completion zsh > "${fpath[1]}/_%[1]s"
+
+Fish:
+  $ %[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
+
+PowerShell:
+  PS> %[1]s completion powershell | Out-String | Invoke-Expression`, program),
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.ExactValidArgs(1),
+	DisableFlagsInUseLine: true,
+	Run:                   runCompletionCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// registerCompletions registers the completions of the flag values. It is
+// called once every command defined its flags.
+func registerCompletions() {
+	checkErr(rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles))
+
+	for _, cmd := range []*cobra.Command{rootCmd, generateCmd, postCmd, serveCmd} {
+		checkErr(cmd.RegisterFlagCompletionFunc("sprint", completeSprints))
+	}
+}
+
+// runCompletionCmd writes the completion script of the shell to stdout.
+func runCompletionCmd(_ *cobra.Command, args []string) {
+	switch args[0] {
+	case "bash":
+		checkErr(rootCmd.GenBashCompletionV2(os.Stdout, true))
+	case "zsh":
+		checkErr(rootCmd.GenZshCompletion(os.Stdout))
+	case "fish":
+		checkErr(rootCmd.GenFishCompletion(os.Stdout, true))
+	case "powershell":
+		checkErr(rootCmd.GenPowerShellCompletionWithDesc(os.Stdout))
+	}
+}
+
+// isCompleting reports whether the completion script is generated, or the
+// shell requests completions, in which case nothing but the script or the
+// completions may be written to stdout.
+func isCompleting() bool {
+	if len(os.Args) < 2 {
+		return false
+	}
+
+	switch os.Args[1] {
+	case completionCmd.Name(), cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	default:
+		return false
+	}
+}
+
+// completeProfiles completes the names of the configured profiles.
+func completeProfiles(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return matchPrefix(profiles(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSprints completes the names of the recent sprints of the board,
+// described by their states. The flags of the completed command, like --board
+// and --profile, are taken into account. As the shell cannot prompt for the
+// credentials, only the stored credentials are used, and no sprints are
+// completed if listing them fails.
+func completeSprints(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if err := viper.BindPFlags(cmd.Flags()); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	if err := applyProfile(); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	board := viper.GetInt("board")
+	if board == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	config := newConfig()
+
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+
+	sprints, err := config.ListSprints(ctx, board)
+	if err != nil {
+		cobra.CompDebugln(fmt.Sprintf("listing the sprints failed: %v", err), true)
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	recent := recentSprints(sprints, completionClosedSprints)
+
+	names := make([]string, 0, len(recent))
+	for i := range recent {
+		names = append(names, recent[i].Name+"\t"+recent[i].State)
+	}
+
+	return matchPrefix(names, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// matchPrefix returns the completions starting with the prefix, ignoring the
+// case.
+func matchPrefix(completions []string, prefix string) []string {
+	var matched []string
+	for _, completion := range completions {
+		if strings.HasPrefix(strings.ToLower(completion), strings.ToLower(prefix)) {
+			matched = append(matched, completion)
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,317 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/credentials"
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/cobra"
+)
+
+// errAborted is returned when the user declines to continue.
+var errAborted = errors.New("aborted")
+
+var (
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration file.",
+	}
+	configInitCmd = &cobra.Command{
+		Use:   "init",
+		Short: "Create the configuration file interactively.",
+		Long:  "Prompt for the Jira connection settings and defaults, validate the connection, and write the configuration file to the configuration directory of the user. Credentials are stored in the keyring if it is available.",
+		Args:  cobra.NoArgs,
+		Run:   runConfigInitCmd,
+	}
+)
+
+func init() {
+	configCmd.AddCommand(configInitCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+// prompter reads the answers of the configuration wizard.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prompts for a value, returning the default value if the answer is
+// empty.
+func (p *prompter) ask(question string, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	answer, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || answer == "") {
+		return "", err
+	}
+
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return defaultValue, nil
+	}
+
+	return answer, nil
+}
+
+// confirm prompts for a yes or no answer.
+func (p *prompter) confirm(question string) (bool, error) {
+	answer, err := p.ask(question+" (y/N)", "")
+	if err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes"), nil
+}
+
+// configSetting is a key of the generated configuration file.
+type configSetting struct {
+	key   string
+	value interface{}
+}
+
+// configFileName is the name of the configuration file in the directory of
+// the program in the configuration directory of the user.
+const configFileName = "config.toml"
+
+// configPath returns the path of the configuration file created by the
+// wizard, which is the configuration file in the configuration directory of
+// the platform, like %APPDATA%\sprint-update\config.toml on Windows or
+// ~/.config/sprint-update/config.toml on Linux.
+func configPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configDir, program, configFileName), nil
+}
+
+// configFileCandidates returns the paths the configuration file is looked up
+// at, in the order of precedence: the dotfile in the home directory, the
+// dotfile in the configuration directory of the user, and the configuration
+// file of the platform, as returned by configPath.
+func configFileCandidates() ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dotfile := "." + program + ".toml"
+
+	return []string{
+		filepath.Join(homeDir, dotfile),
+		filepath.Join(configDir, dotfile),
+		filepath.Join(configDir, program, configFileName),
+	}, nil
+}
+
+// findConfigFile returns the path of the first configuration file found, or
+// an empty string if there is none.
+func findConfigFile() (string, error) {
+	candidates, err := configFileCandidates()
+	if err != nil {
+		return "", err
+	}
+
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+
+	return "", nil
+}
+
+// defaultAuthType returns the suggested authentication method of the server.
+func defaultAuthType(serverURL string) jira.AuthType {
+	if u, err := url.Parse(serverURL); err == nil && strings.HasSuffix(u.Hostname(), ".atlassian.net") {
+		return jira.AuthToken
+	}
+
+	return jira.AuthPAT
+}
+
+// runConfigInitCmd prompts for the settings and writes the configuration file.
+func runConfigInitCmd(cmd *cobra.Command, _ []string) {
+	path, err := configPath()
+	checkErr(err)
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stderr}
+
+	if _, err = os.Stat(path); err == nil {
+		overwrite, err := p.confirm(fmt.Sprintf("%s already exists. Overwrite it?", path))
+		checkErr(err)
+
+		if !overwrite {
+			checkErr(errAborted)
+		}
+	}
+
+	config, secrets, err := promptConfig(p)
+	checkErr(err)
+
+	settings := []configSetting{
+		{"jira-url", config.ServerURL},
+		{"auth-type", string(config.AuthType)},
+		{"jira-username", config.Username},
+	}
+
+	if config.Board != 0 {
+		settings = append(settings, configSetting{"board", config.Board})
+	}
+
+	if config.TemplateFile != "" {
+		settings = append(settings, configSetting{"template", config.TemplateFile})
+	}
+
+	if config.AuthType != jira.AuthOAuth {
+		fmt.Fprintln(os.Stderr, "Checking the connection to Jira...")
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		checkErr(config.CheckConnection(ctx))
+	}
+
+	for key, value := range secrets {
+		if value == "" {
+			continue
+		}
+
+		if credentials.Available() {
+			checkErr(credentials.Set(profileKey(key), value))
+			fmt.Fprintf(os.Stderr, "Stored %s in the keyring\n", key)
+		} else {
+			fmt.Fprintf(os.Stderr, "The keyring is not available, writing %s to the config file\n", key)
+			settings = append(settings, configSetting{key, value})
+		}
+	}
+
+	checkErr(writeConfigFile(path, settings))
+	fmt.Fprintln(os.Stderr, "Config file written to", path)
+
+	if config.AuthType == jira.AuthOAuth {
+		fmt.Fprintf(os.Stderr, "Run \"%s login\" to log in to Jira\n", program)
+	}
+}
+
+// promptConfig prompts for the settings of the configuration file, and
+// returns the secrets separately, so they can be stored in the keyring.
+func promptConfig(p *prompter) (*sprint.Config, map[string]string, error) {
+	config := &sprint.Config{}
+	secrets := make(map[string]string)
+
+	var err error
+	for config.ServerURL == "" {
+		if config.ServerURL, err = p.ask("Jira URL (ex: https://example.atlassian.net)", ""); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	config.ServerURL = strings.TrimSuffix(config.ServerURL, "/")
+
+	authTypes := []string{string(jira.AuthBasic), string(jira.AuthToken), string(jira.AuthPAT), string(jira.AuthOAuth)}
+	authType, err := p.ask(fmt.Sprintf("Authentication method (%s)", strings.Join(authTypes, ", ")), string(defaultAuthType(config.ServerURL)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config.AuthType = jira.AuthType(strings.ToLower(authType))
+
+	switch config.AuthType {
+	case jira.AuthBasic:
+		if config.Username, err = p.ask("Username", ""); err != nil {
+			return nil, nil, err
+		}
+
+		if config.Password, err = readSecret(p.in, p.out, "Password"); err != nil {
+			return nil, nil, err
+		}
+
+		secrets["jira-password"] = config.Password
+	case jira.AuthToken:
+		if config.Username, err = p.ask("Email address", ""); err != nil {
+			return nil, nil, err
+		}
+
+		if config.Token, err = readSecret(p.in, p.out, "API token"); err != nil {
+			return nil, nil, err
+		}
+
+		secrets["jira-token"] = config.Token
+	case jira.AuthPAT:
+		if config.Token, err = readSecret(p.in, p.out, "Personal access token"); err != nil {
+			return nil, nil, err
+		}
+
+		secrets["jira-token"] = config.Token
+	case jira.AuthOAuth:
+	default:
+		return nil, nil, fmt.Errorf("%w: %s", jira.ErrUnknownAuthType, authType)
+	}
+
+	board, err := p.ask("Default board ID, used to detect the active sprint (optional)", "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if board != "" {
+		if config.Board, err = strconv.Atoi(board); err != nil {
+			return nil, nil, fmt.Errorf("invalid board ID: %s", board)
+		}
+	}
+
+	if config.TemplateFile, err = p.ask("Template file (optional)", ""); err != nil {
+		return nil, nil, err
+	}
+
+	if config.TemplateFile != "" {
+		if _, err = os.Stat(config.TemplateFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return config, secrets, nil
+}
+
+// writeConfigFile writes the settings to the TOML configuration file.
+func writeConfigFile(path string, settings []configSetting) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s configuration, created by \"%s config init\".\n\n", program, program)
+
+	for _, setting := range settings {
+		switch value := setting.value.(type) {
+		case string:
+			if value != "" {
+				fmt.Fprintf(&b, "%s = %s\n", setting.key, strconv.Quote(value))
+			}
+		default:
+			fmt.Fprintf(&b, "%s = %v\n", setting.key, value)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(b.String()), 0600)
+}
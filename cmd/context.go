@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"gabor-boros/sprint-update/pkg/logging"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// signalContext returns a context cancelled on SIGINT or SIGTERM, so the
+// in-flight requests are cancelled cleanly. Once the context is cancelled,
+// the signals are no longer caught, hence a second signal terminates the
+// process immediately.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return ctx, stop
+}
+
+// commandContext returns the context of the command, bounded by the
+// configured timeout, and carrying the logger configured by the --verbose,
+// --debug, and --dump-responses flags. Cancelling the context closes the
+// file the responses are dumped to.
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	logger, closeDump, err := newLogger()
+	checkErr(err)
+
+	ctx := logging.NewContext(cmd.Context(), logger)
+
+	var cancel context.CancelFunc
+	if timeout := viper.GetDuration("timeout"); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	return ctx, func() {
+		cancel()
+		closeDump()
+	}
+}
+
+// newLogger returns the logger writing to stderr at the configured level, or
+// nil if neither logging nor dumping the responses is enabled. The returned
+// function closes the dump file.
+func newLogger() (*logging.Logger, func(), error) {
+	level := logging.LevelQuiet
+	switch {
+	case viper.GetBool("debug"):
+		level = logging.LevelDebug
+	case viper.GetBool("verbose"):
+		level = logging.LevelVerbose
+	}
+
+	dumpPath := viper.GetString("dump-responses")
+	if level == logging.LevelQuiet && dumpPath == "" {
+		return nil, func() {}, nil
+	}
+
+	if dumpPath == "" {
+		return logging.New(os.Stderr, level, nil), func() {}, nil
+	}
+
+	dump, err := os.OpenFile(filepath.Clean(dumpPath), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return logging.New(os.Stderr, level, dump), func() { _ = dump.Close() }, nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/credentials"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// secretReferenceTimeout is the maximum time spent on resolving a secret
+// reference, including unlocking the secret manager.
+const secretReferenceTimeout = 2 * time.Minute
+
+// secretKeys are the configuration keys that can be stored in the keyring.
+var secretKeys = []string{
+	"jira-password",
+	"jira-token",
+	"discourse-api-key",
+	"github-token",
+	"gitlab-token",
+	"bitbucket-app-password",
+	"slack-webhook-url",
+	"slack-token",
+	"confluence-token",
+	"email-password",
+	"calendar-password",
+	"tempo-token",
+	"bamboohr-token",
+	"linear-token",
+	"azure-token",
+	"matrix-token",
+	"mattermost-webhook-url",
+	"teams-webhook-url",
+	"webhook-url",
+	"webhook-secret",
+	"notion-token",
+	"llm-api-key",
+	"oauth-client-secret",
+}
+
+var (
+	credentialsCmd = &cobra.Command{
+		Use:   "credentials",
+		Short: "Manage the credentials stored in the keyring.",
+		Long: fmt.Sprintf(
+			"Manage the credentials stored in the keyring of the operating system. Stored credentials take precedence over the config file and environment variables.\n\nSupported keys: %s",
+			strings.Join(secretKeys, ", "),
+		),
+	}
+	credentialsSetCmd = &cobra.Command{
+		Use:       "set <key>",
+		Short:     "Store a credential read from stdin in the keyring.",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: secretKeys,
+		Run:       runCredentialsSetCmd,
+	}
+	credentialsGetCmd = &cobra.Command{
+		Use:       "get <key>",
+		Short:     "Print a credential stored in the keyring.",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: secretKeys,
+		Run:       runCredentialsGetCmd,
+	}
+	credentialsDeleteCmd = &cobra.Command{
+		Use:       "delete <key>",
+		Short:     "Delete a credential from the keyring.",
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: secretKeys,
+		Run:       runCredentialsDeleteCmd,
+	}
+)
+
+func init() {
+	credentialsCmd.AddCommand(credentialsSetCmd, credentialsGetCmd, credentialsDeleteCmd)
+	rootCmd.AddCommand(credentialsCmd)
+}
+
+// secret returns the secret stored under the key in the keyring, falling back
+// to the config file and environment variables. When a profile is selected,
+// the secret stored for the profile is used. Secret references, like
+// "op://Work/Jira/token", are resolved through their secret manager.
+func secret(key string) string {
+	value := credentials.Lookup(profileKey(key), viper.GetString(key))
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretReferenceTimeout)
+	defer cancel()
+
+	resolved, err := credentials.Resolve(ctx, value)
+	checkErr(err)
+
+	return resolved
+}
+
+// runCredentialsSetCmd stores the credential read from stdin.
+func runCredentialsSetCmd(_ *cobra.Command, args []string) {
+	value, err := readSecret(bufio.NewReader(os.Stdin), os.Stderr, "Enter "+args[0])
+	checkErr(err)
+
+	checkErr(credentials.Set(profileKey(args[0]), value))
+}
+
+// runCredentialsGetCmd prints the stored credential.
+func runCredentialsGetCmd(_ *cobra.Command, args []string) {
+	value, err := credentials.Get(profileKey(args[0]))
+	checkErr(err)
+
+	fmt.Println(value)
+}
+
+// runCredentialsDeleteCmd deletes the stored credential.
+func runCredentialsDeleteCmd(_ *cobra.Command, args []string) {
+	checkErr(credentials.Delete(profileKey(args[0])))
+}
@@ -0,0 +1,600 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/confluence"
+	"gabor-boros/sprint-update/pkg/discourse"
+	"gabor-boros/sprint-update/pkg/email"
+	"gabor-boros/sprint-update/pkg/google"
+	"gabor-boros/sprint-update/pkg/history"
+	"gabor-boros/sprint-update/pkg/metrics"
+	"gabor-boros/sprint-update/pkg/notify"
+	"gabor-boros/sprint-update/pkg/notion"
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/slack"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// targetDiscourse delivers the update to Discourse.
+	targetDiscourse = "discourse"
+	// targetSlack delivers the update to Slack.
+	targetSlack = "slack"
+	// targetConfluence delivers the update to Confluence.
+	targetConfluence = "confluence"
+	// targetEmail delivers the update by email.
+	targetEmail = "email"
+	// targetMatrix delivers the update to a Matrix room.
+	targetMatrix = "matrix"
+	// targetMattermost delivers the update to Mattermost.
+	targetMattermost = "mattermost"
+	// targetTeams delivers the update to Microsoft Teams.
+	targetTeams = "teams"
+	// targetNotion delivers the update to a Notion database.
+	targetNotion = "notion"
+	// targetGoogleDocs delivers the update to a Google Docs document.
+	targetGoogleDocs = "google-docs"
+	// targetWebhook delivers the update to a webhook.
+	targetWebhook = "webhook"
+)
+
+// availableTargets are the supported delivery targets.
+var availableTargets = []string{
+	targetDiscourse,
+	targetSlack,
+	targetConfluence,
+	targetEmail,
+	targetMatrix,
+	targetMattermost,
+	targetTeams,
+	targetNotion,
+	targetGoogleDocs,
+	targetWebhook,
+}
+
+const (
+	// amendEdit amends the Discourse post of the sprint by editing it.
+	amendEdit = "edit"
+	// amendReply amends the Discourse post of the sprint by replying to it.
+	amendReply = "reply"
+)
+
+var (
+	// errUnknownTarget is returned when the requested delivery target does
+	// not exist.
+	errUnknownTarget = errors.New("unknown delivery target")
+	// errUnknownAmendMode is returned when the requested way of amending the
+	// Discourse post does not exist.
+	errUnknownAmendMode = errors.New("unknown amend mode")
+	// errDraftAndAmend is returned when both drafting and amending the
+	// Discourse post are requested.
+	errDraftAndAmend = errors.New("--draft and --amend cannot be used together")
+	// errMissingGoogleCredentials is returned when no Google credentials
+	// file is configured for the google-docs target.
+	errMissingGoogleCredentials = errors.New("google credentials file is required (set google-credentials-file or GOOGLE_APPLICATION_CREDENTIALS)")
+)
+
+// deliveryTargets returns the configured delivery targets.
+func deliveryTargets() ([]string, error) {
+	if err := checkAmend(); err != nil {
+		return nil, err
+	}
+
+	var selected []string
+	seen := make(map[string]bool)
+
+	requested := viper.GetStringSlice("to")
+	if viper.GetBool("post") {
+		requested = append(requested, targetDiscourse)
+	}
+
+	for _, target := range requested {
+		target = strings.ToLower(strings.TrimSpace(target))
+
+		if !isTarget(target) {
+			return nil, fmt.Errorf("%w: %s (available: %s)", errUnknownTarget, target, strings.Join(availableTargets, ", "))
+		}
+
+		if !seen[target] {
+			seen[target] = true
+			selected = append(selected, target)
+		}
+	}
+
+	return selected, nil
+}
+
+// checkAmend checks the configuration of amending the Discourse post of the
+// sprint.
+func checkAmend() error {
+	if !viper.GetBool("amend") {
+		return nil
+	}
+
+	if viper.GetBool("draft") {
+		return errDraftAndAmend
+	}
+
+	switch mode := viper.GetString("amend-mode"); mode {
+	case amendEdit, amendReply:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s (available: %s, %s)", errUnknownAmendMode, mode, amendEdit, amendReply)
+	}
+}
+
+// isTarget returns whether the given target is one of availableTargets.
+func isTarget(target string) bool {
+	for _, available := range availableTargets {
+		if target == available {
+			return true
+		}
+	}
+
+	return false
+}
+
+// deliver delivers the sprint update to the given targets. If the rendered
+// text was edited, the edited text is delivered to every target.
+func deliver(ctx context.Context, targets []string, config *sprint.Config, update *report.Update, text string, edited bool) error {
+	for _, target := range targets {
+		err := newNotifier(target, config, text, edited).Notify(ctx, update)
+		metrics.FromContext(ctx).ObserveDelivery(target, err)
+
+		if err != nil {
+			return &exitError{code: exitDelivery, err: fmt.Errorf("%s delivery failed: %w", target, err)}
+		}
+	}
+
+	return nil
+}
+
+// newNotifier returns the notifier delivering the update to the given target.
+// The text is the rendered update, which is posted as is to Discourse. If the
+// text was edited, it is sent to the other targets too instead of rendering
+// the update in their own format.
+func newNotifier(target string, config *sprint.Config, text string, edited bool) notify.Notifier {
+	var editedText string
+	if edited {
+		editedText = text
+	}
+
+	switch target {
+	case targetDiscourse:
+		return notify.Func(func(ctx context.Context, update *report.Update) error {
+			return postToDiscourse(ctx, config, update.Title, text)
+		})
+	case targetSlack:
+		return notify.Func(func(ctx context.Context, update *report.Update) error {
+			return postToSlack(ctx, update, editedText)
+		})
+	case targetConfluence:
+		return notify.Func(func(ctx context.Context, update *report.Update) error {
+			return publishToConfluence(ctx, config, update, editedText)
+		})
+	case targetEmail:
+		return notify.Func(func(ctx context.Context, update *report.Update) error {
+			return sendEmail(ctx, config, update, editedText)
+		})
+	case targetNotion:
+		return notify.Func(func(ctx context.Context, update *report.Update) error {
+			return publishToNotion(ctx, config, update, editedText)
+		})
+	case targetGoogleDocs:
+		return notify.Func(func(ctx context.Context, update *report.Update) error {
+			return publishToGoogleDocs(ctx, update, editedText)
+		})
+	case targetMatrix:
+		return announce("Matrix", &notify.Matrix{
+			HomeserverURL: viper.GetString("matrix-url"),
+			AccessToken:   secret("matrix-token"),
+			RoomID:        viper.GetString("matrix-room"),
+			Text:          editedText,
+			HTTPClient:    newHTTPClient(),
+		})
+	case targetWebhook:
+		return announce("the webhook", &notify.Webhook{
+			URL:        secret("webhook-url"),
+			Secret:     secret("webhook-secret"),
+			Text:       editedText,
+			Format:     config.Format,
+			HTTPClient: newHTTPClient(),
+		})
+	case targetMattermost:
+		return announce("Mattermost", &notify.Mattermost{
+			WebhookURL: secret("mattermost-webhook-url"),
+			Channel:    viper.GetString("mattermost-channel"),
+			Username:   viper.GetString("mattermost-username"),
+			Text:       editedText,
+			HTTPClient: newHTTPClient(),
+		})
+	default:
+		return announce("Microsoft Teams", &notify.Teams{
+			WebhookURL: secret("teams-webhook-url"),
+			Text:       editedText,
+			HTTPClient: newHTTPClient(),
+		})
+	}
+}
+
+// announce wraps the notifier to tell where the update was sent after the
+// successful delivery.
+func announce(name string, notifier notify.Notifier) notify.Notifier {
+	return notify.Func(func(ctx context.Context, update *report.Update) error {
+		if err := notifier.Notify(ctx, update); err != nil {
+			return err
+		}
+
+		printStatus("Sprint update sent to", name)
+		return nil
+	})
+}
+
+// postToDiscourse publishes the sprint update to the configured Discourse
+// topic or category, or saves it as a draft to review before publishing. When
+// amending, the post of the sprint posted last is edited or replied to
+// instead. The post is recorded in the history, so it can be amended later.
+func postToDiscourse(ctx context.Context, config *sprint.Config, title string, update string) error {
+	client := discourse.NewClient(
+		viper.GetString("discourse-url"),
+		secret("discourse-api-key"),
+		viper.GetString("discourse-username"),
+	)
+	client.HTTPClient = newHTTPClient()
+
+	post := &discourse.Post{
+		Title:    title,
+		Raw:      update,
+		TopicID:  viper.GetInt("discourse-topic"),
+		Category: viper.GetInt("discourse-category"),
+	}
+
+	if viper.GetBool("draft") {
+		if err := client.SaveDraft(ctx, post); err != nil {
+			return err
+		}
+
+		printStatus("Sprint update saved as a draft, review and publish it at:", client.DraftURL(post))
+		return nil
+	}
+
+	createdPost, err := amendDiscoursePost(ctx, client, config, post)
+	if err != nil {
+		return err
+	}
+
+	if createdPost == nil {
+		if createdPost, err = client.CreatePost(ctx, post); err != nil {
+			return err
+		}
+
+		printStatus("Sprint update posted:", client.PostURL(createdPost))
+	}
+
+	return config.SaveDiscoursePost(&history.DiscoursePost{
+		ID:         createdPost.ID,
+		TopicID:    createdPost.TopicID,
+		PostNumber: createdPost.PostNumber,
+		URL:        client.PostURL(createdPost),
+	})
+}
+
+// amendDiscoursePost edits or replies to the Discourse post of the sprint
+// posted last, if amending is requested. If nothing is amended, nil is
+// returned, so a new post is created.
+func amendDiscoursePost(ctx context.Context, client *discourse.Client, config *sprint.Config, post *discourse.Post) (*discourse.CreatedPost, error) {
+	if !viper.GetBool("amend") {
+		return nil, nil
+	}
+
+	previous, err := config.DiscoursePost()
+	if err != nil {
+		return nil, err
+	}
+
+	if previous == nil {
+		printStatus("No Discourse post of the sprint found to amend, creating a new post")
+		return nil, nil
+	}
+
+	if viper.GetString("amend-mode") == amendReply {
+		post.TopicID, post.ReplyToPostNumber = previous.TopicID, previous.PostNumber
+
+		createdPost, err := client.CreatePost(ctx, post)
+		if err != nil {
+			return nil, err
+		}
+
+		printStatus("Sprint update posted as a reply to", previous.URL+":", client.PostURL(createdPost))
+		return createdPost, nil
+	}
+
+	editedPost, err := client.EditPost(ctx, previous.ID, post.Raw)
+	if err != nil {
+		return nil, err
+	}
+
+	printStatus("Sprint update amended:", client.PostURL(editedPost))
+	return editedPost, nil
+}
+
+// postToSlack posts the sprint update to the configured Slack webhook or
+// channel using Block Kit formatting. If the edited text is set, it is posted
+// as a plain message instead.
+func postToSlack(ctx context.Context, update *report.Update, editedText string) error {
+	client := &slack.Client{
+		WebhookURL: secret("slack-webhook-url"),
+		Token:      secret("slack-token"),
+		Channel:    viper.GetString("slack-channel"),
+		HTTPClient: newHTTPClient(),
+	}
+
+	message := slack.NewMessage(update)
+	if editedText != "" {
+		message = &slack.Message{Text: editedText}
+	}
+
+	if err := client.PostMessage(ctx, message); err != nil {
+		return err
+	}
+
+	printStatus("Sprint update sent to Slack")
+	return nil
+}
+
+// publishToConfluence creates or updates the Confluence page of the sprint
+// update. End of sprint updates are appended to the archive page too, if set.
+// The edited text is published only if it is in the confluence format.
+func publishToConfluence(ctx context.Context, config *sprint.Config, update *report.Update, editedText string) error {
+	client := confluence.NewClient(
+		viper.GetString("confluence-url"),
+		viper.GetString("confluence-username"),
+		secret("confluence-token"),
+	)
+	client.HTTPClient = newHTTPClient()
+
+	// Fall back to the Jira credentials, as both are the same Atlassian
+	// account on the cloud.
+	if client.Username == "" {
+		client.Username = viper.GetString("jira-username")
+	}
+
+	if client.Token == "" {
+		client.Token = secret("jira-token")
+	}
+
+	if editedText != "" && config.Format != targetConfluence {
+		printStatus("The edits are not published to Confluence, as the update is not in the confluence format")
+		editedText = ""
+	}
+
+	content, err := confluenceContent(ctx, client, update, editedText)
+	if err != nil {
+		return err
+	}
+
+	spaceKey := viper.GetString("confluence-space")
+	parentID := viper.GetString("confluence-parent")
+
+	page, err := client.FindPage(ctx, spaceKey, update.Title)
+	if err != nil {
+		return err
+	}
+
+	if page == nil {
+		page, err = client.CreatePage(ctx, spaceKey, parentID, update.Title, content)
+	} else {
+		page, err = client.UpdatePage(ctx, page, content)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	printStatus("Sprint update published:", client.PageURL(page))
+
+	archiveTitle := viper.GetString("confluence-archive-page")
+	if !config.EndOfSprint || archiveTitle == "" {
+		return nil
+	}
+
+	archive, err := client.FindPage(ctx, spaceKey, archiveTitle)
+	if err != nil {
+		return err
+	}
+
+	entry := "<h1>" + html.EscapeString(update.Title) + "</h1>" + content
+	if archive == nil {
+		archive, err = client.CreatePage(ctx, spaceKey, parentID, archiveTitle, entry)
+	} else {
+		archive, err = client.UpdatePage(ctx, archive, archive.Content()+entry)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	printStatus("Sprint update archived:", client.PageURL(archive))
+	return nil
+}
+
+// publishToNotion creates a page of the sprint update in the configured Notion
+// database, setting the sprint, author, and type properties of the page. If
+// the edited text is set, it is published instead of the update.
+func publishToNotion(ctx context.Context, config *sprint.Config, update *report.Update, editedText string) error {
+	client := &notion.Client{
+		Token:      secret("notion-token"),
+		HTTPClient: newHTTPClient(),
+	}
+
+	author := update.Assignee
+	if author == "" {
+		author = viper.GetString("notion-author")
+	}
+
+	properties := map[string]string{
+		viper.GetString("notion-sprint-property"): config.TitleData().Sprint,
+		viper.GetString("notion-author-property"): author,
+		viper.GetString("notion-type-property"):   config.TitleData().Type,
+	}
+
+	blocks := notion.NewBlocks(update)
+	if editedText != "" {
+		blocks = notion.TextBlocks(editedText)
+	}
+
+	page, err := client.CreatePage(ctx, viper.GetString("notion-database"), update.Title, properties, blocks)
+	if err != nil {
+		return err
+	}
+
+	printStatus("Sprint update published:", page.URL)
+	return nil
+}
+
+// publishToGoogleDocs writes the sprint update to the document named after
+// its title in the configured Drive folder, creating the document if it does
+// not exist and replacing its content otherwise. If an archive document is
+// set, the update is appended to it instead. If the edited text is set, it is
+// written instead of the update.
+func publishToGoogleDocs(ctx context.Context, update *report.Update, editedText string) error {
+	path := viper.GetString("google-credentials-file")
+	if path == "" {
+		path = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+
+	if path == "" {
+		return errMissingGoogleCredentials
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	credentials, err := google.ParseCredentials(data)
+	if err != nil {
+		return err
+	}
+
+	client := &google.Client{
+		Credentials: credentials,
+		HTTPClient:  newHTTPClient(),
+	}
+
+	paragraphs := google.NewParagraphs(update)
+	if editedText != "" {
+		paragraphs = google.TextParagraphs(editedText)
+	}
+
+	folderID := viper.GetString("google-folder")
+	name := viper.GetString("google-document")
+	archive := name != ""
+
+	if archive {
+		paragraphs = append([]google.Paragraph{google.HeadingParagraph(google.StyleHeading1, update.Title)}, paragraphs...)
+	} else {
+		name = update.Title
+	}
+
+	doc, err := client.FindDocument(ctx, folderID, name)
+	if err != nil {
+		return err
+	}
+
+	if doc == nil {
+		if doc, err = client.CreateDocument(ctx, folderID, name); err != nil {
+			return err
+		}
+	}
+
+	if archive {
+		err = client.AppendParagraphs(ctx, doc.ID, paragraphs)
+	} else {
+		err = client.ReplaceParagraphs(ctx, doc.ID, paragraphs)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	printStatus("Sprint update published:", doc.WebViewLink)
+	return nil
+}
+
+// confluenceContent renders the sprint update as Confluence wiki markup,
+// unless the edited wiki markup is set, and converts it to the storage format
+// of the pages.
+func confluenceContent(ctx context.Context, client *confluence.Client, update *report.Update, editedText string) (string, error) {
+	wiki := editedText
+	if wiki == "" {
+		var err error
+		if wiki, err = render.RenderFormat("confluence", update); err != nil {
+			return "", err
+		}
+	}
+
+	return client.ConvertWiki(ctx, wiki)
+}
+
+// sendEmail sends the sprint update by email, rendered both as plain text and
+// HTML. If the edited text is set, only the edited text is sent.
+func sendEmail(ctx context.Context, config *sprint.Config, update *report.Update, editedText string) error {
+	subject := update.Title
+	if subjectText := viper.GetString("email-subject"); subjectText != "" {
+		subjectTmpl, err := render.ParseTitleTemplate(subjectText)
+		if err != nil {
+			return err
+		}
+
+		if subject, err = render.NewTitle(subjectTmpl, titleData(config, update)); err != nil {
+			return err
+		}
+	}
+
+	text, htmlText := editedText, ""
+	if text == "" {
+		var err error
+		if text, err = render.RenderFormat("markdown", update); err != nil {
+			return err
+		}
+
+		if htmlText, err = render.RenderFormat("html", update); err != nil {
+			return err
+		}
+	}
+
+	client := &email.Client{
+		Host:     viper.GetString("email-host"),
+		Port:     viper.GetInt("email-port"),
+		TLS:      email.TLSMode(viper.GetString("email-tls")),
+		Username: viper.GetString("email-username"),
+		Password: secret("email-password"),
+		From:     viper.GetString("email-from"),
+		To:       viper.GetStringSlice("email-to"),
+	}
+
+	settings := networkSettings()
+	tlsConfig, err := settings.TLSConfig()
+	if err != nil {
+		return err
+	}
+	client.TLSConfig = tlsConfig
+
+	if err := client.Send(ctx, &email.Message{Subject: subject, Text: text, HTML: htmlText}); err != nil {
+		return err
+	}
+
+	printStatus("Sprint update sent to", strings.Join(client.To, ", "))
+	return nil
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/google"
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/notion"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// dialTimeout is the maximum duration of checking that a host is reachable.
+const dialTimeout = 5 * time.Second
+
+// targetSettings are the settings required by the delivery targets.
+var targetSettings = map[string][]string{
+	targetDiscourse:  {"discourse-url", "discourse-username", "discourse-api-key"},
+	targetSlack:      {"slack-token", "slack-channel"},
+	targetConfluence: {"confluence-url", "confluence-space"},
+	targetEmail:      {"email-host", "email-from", "email-to"},
+	targetMatrix:     {"matrix-url", "matrix-token", "matrix-room"},
+	targetMattermost: {"mattermost-webhook-url"},
+	targetTeams:      {"teams-webhook-url"},
+	targetWebhook:    {"webhook-url"},
+	targetNotion:     {"notion-token", "notion-database"},
+	targetGoogleDocs: {"google-folder"},
+}
+
+var (
+	// errChecksFailed is returned when some of the diagnostics failed.
+	errChecksFailed = errors.New("some checks failed")
+	// errMissingSetting is returned when a required setting is not set.
+	errMissingSetting = errors.New("missing setting")
+	// errSkipped marks the checks skipped due to a failed check they depend on.
+	errSkipped = errors.New("skipped")
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the configuration.",
+	Long:  "Check the configuration, the connection to Jira, the credentials, the sprint or board, the templates, and the reachability of the delivery targets, printing the result of every check.",
+	Args:  cobra.NoArgs,
+	Run:   runDoctorCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// diagnosis is the result of a check of the doctor command.
+type diagnosis struct {
+	// name is the name of the check.
+	name string
+	// detail describes the result of a passed check.
+	detail string
+	// err is the reason of a failed check.
+	err error
+	// hint describes how to fix a failed check.
+	hint string
+}
+
+// print prints the result of the check.
+func (d *diagnosis) print() {
+	switch {
+	case errors.Is(d.err, errSkipped):
+		fmt.Printf("[SKIP] %s\n", d.name)
+	case d.err != nil:
+		fmt.Printf("[FAIL] %s: %v\n", d.name, d.err)
+		if d.hint != "" {
+			fmt.Printf("       %s\n", d.hint)
+		}
+	case d.detail != "":
+		fmt.Printf("[ OK ] %s: %s\n", d.name, d.detail)
+	default:
+		fmt.Printf("[ OK ] %s\n", d.name)
+	}
+}
+
+// runDoctorCmd runs every check and prints its result. If any check failed,
+// the command exits with an error.
+func runDoctorCmd(cmd *cobra.Command, _ []string) {
+	config := newConfig()
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	failed := false
+	record := func(d diagnosis) bool {
+		d.print()
+		if d.err != nil && !errors.Is(d.err, errSkipped) {
+			failed = true
+		}
+
+		return d.err == nil
+	}
+
+	record(diagnosis{
+		name: "Configuration",
+		err:  config.Validate(),
+		hint: "Run the command with --help to see the settings, or create the configuration file using the config init command.",
+	})
+
+	connected := record(checkJiraConnection(ctx, &config))
+	authenticated := record(checkJiraAuth(ctx, &config, connected))
+	record(checkSprint(ctx, &config, authenticated))
+
+	record(diagnosis{
+		name: "Template",
+		err:  config.CheckTemplate(),
+		hint: "Fix the template, the partials, or the title template; the error contains the line number of the problem.",
+	})
+
+	targets, err := deliveryTargets()
+	if err != nil {
+		record(diagnosis{name: "Delivery targets", err: err})
+	}
+
+	for _, target := range targets {
+		record(checkTarget(ctx, target))
+	}
+
+	if failed {
+		checkErr(errChecksFailed)
+	}
+}
+
+// checkJiraConnection checks that the Jira server is reachable.
+func checkJiraConnection(ctx context.Context, config *sprint.Config) diagnosis {
+	d := diagnosis{
+		name: "Jira connection",
+		hint: "Check the jira-url setting, and that Jira is reachable from this network, like through a VPN or the proxy setting.",
+	}
+
+	if config.ServerURL == "" && config.AuthType == jira.AuthOAuth {
+		d.detail = "the site of the OAuth token is used"
+		return d
+	}
+
+	d.detail, d.err = reachable(ctx, config.ServerURL)
+	return d
+}
+
+// checkJiraAuth checks that Jira accepts the configured credentials.
+func checkJiraAuth(ctx context.Context, config *sprint.Config, connected bool) diagnosis {
+	d := diagnosis{name: "Jira authentication"}
+	if !connected {
+		d.err = errSkipped
+		return d
+	}
+
+	d.err = config.CheckConnection(ctx)
+	return d
+}
+
+// checkSprint checks that the configured sprint exists, or that the board has
+// an active sprint.
+func checkSprint(ctx context.Context, config *sprint.Config, authenticated bool) diagnosis {
+	d := diagnosis{name: "Sprint"}
+
+	switch {
+	case !authenticated:
+		d.err = errSkipped
+	case config.SprintID != 0:
+		d.hint = "Check the sprint-id setting, and that your Jira user can see the board of the sprint."
+		if d.err = config.CheckSprint(ctx); d.err == nil {
+			d.detail = fmt.Sprintf("sprint %d is %s", config.SprintID, config.Sprint)
+		}
+	case config.Sprint == "" && config.Board == 0:
+		d.detail = "no sprint is set, the configured JQL is used"
+	case config.Sprint != "":
+		d.hint = "The sprint name must match the name of the sprint in Jira exactly, and the sprint must have issues. List the sprints of the board using the sprints command."
+		if d.err = config.CheckSprint(ctx); d.err == nil {
+			d.detail = config.Sprint
+		}
+	default:
+		d.hint = "Check the board setting, and that the board has an active sprint."
+		if d.err = config.CheckSprint(ctx); d.err == nil {
+			d.detail = fmt.Sprintf("active sprint of board %d is %s", config.Board, config.Sprint)
+		}
+	}
+
+	return d
+}
+
+// missingTargetSettings returns the settings required by the delivery target
+// that are not set, according to the given function. Slack requires the bot
+// token and the channel only if no webhook URL is set.
+func missingTargetSettings(target string, isSet func(key string) bool) []string {
+	required := targetSettings[target]
+	if target == targetSlack && isSet("slack-webhook-url") {
+		required = nil
+	}
+
+	var missing []string
+	for _, key := range required {
+		if !isSet(key) {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
+
+// settingConfigured reports whether the setting is set, looking up the
+// secrets in the keyring too.
+func settingConfigured(key string) bool {
+	for _, secretKey := range secretKeys {
+		if key == secretKey {
+			return secret(key) != ""
+		}
+	}
+
+	return viper.GetString(key) != "" || len(viper.GetStringSlice(key)) > 0
+}
+
+// checkTarget checks that the settings of the delivery target are set and its
+// server is reachable, without delivering anything.
+func checkTarget(ctx context.Context, target string) diagnosis {
+	d := diagnosis{
+		name: "Delivery to " + target,
+		hint: fmt.Sprintf("Check the %s-* settings, and that the server is reachable from this network.", target),
+	}
+
+	var serverURL string
+
+	switch target {
+	case targetDiscourse:
+		serverURL = viper.GetString("discourse-url")
+	case targetSlack:
+		if serverURL = secret("slack-webhook-url"); serverURL == "" {
+			serverURL = "https://slack.com"
+		}
+	case targetConfluence:
+		serverURL = viper.GetString("confluence-url")
+	case targetEmail:
+		serverURL = "smtp://" + net.JoinHostPort(viper.GetString("email-host"), strconv.Itoa(viper.GetInt("email-port")))
+	case targetMatrix:
+		serverURL = viper.GetString("matrix-url")
+	case targetMattermost:
+		serverURL = secret("mattermost-webhook-url")
+	case targetTeams:
+		serverURL = secret("teams-webhook-url")
+	case targetWebhook:
+		serverURL = secret("webhook-url")
+	case targetNotion:
+		serverURL = notion.DefaultBaseURL
+	case targetGoogleDocs:
+		serverURL = google.DefaultDocsURL
+	}
+
+	if missing := missingTargetSettings(target, settingConfigured); len(missing) > 0 {
+		d.err = fmt.Errorf("%w: %s", errMissingSetting, strings.Join(missing, ", "))
+		return d
+	}
+
+	d.detail, d.err = reachable(ctx, serverURL)
+	return d
+}
+
+// reachable checks that a connection can be opened to the host of the URL,
+// or to the proxy of the URL if any, and returns the address connected to.
+func reachable(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("%w: host of %q", errMissingSetting, rawURL)
+	}
+
+	settings := networkSettings()
+	proxy, err := settings.Proxy()
+	if err != nil {
+		return "", err
+	}
+
+	proxyURL, err := proxy(&http.Request{URL: u})
+	if err != nil {
+		return "", err
+	}
+
+	if proxyURL == nil {
+		return dial(ctx, u)
+	}
+
+	detail, err := dial(ctx, proxyURL)
+	if err != nil {
+		return "", fmt.Errorf("proxy %s: %w", proxyURL.Redacted(), err)
+	}
+
+	return detail + " (proxy)", nil
+}
+
+// dial opens a connection to the host of the URL, and returns the address
+// connected to. The port defaults to the port of the scheme.
+func dial(ctx context.Context, u *url.URL) (string, error) {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "socks5":
+			port = "1080"
+		default:
+			port = "443"
+		}
+	}
+
+	address := net.JoinHostPort(u.Hostname(), port)
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	return address + " is reachable", nil
+}
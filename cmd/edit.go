@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// errEmptyEdit is returned when the edited update is empty.
+var errEmptyEdit = errors.New("the edited update is empty")
+
+// editorCommand returns the command of the editor set by $VISUAL or $EDITOR,
+// falling back to the default editor of the platform.
+func editorCommand() []string {
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(key)); len(editor) > 0 {
+			return editor
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+
+	return []string{"vi"}
+}
+
+// editExtension returns the file extension of the given format, so editors
+// can highlight the update.
+func editExtension(format string) string {
+	switch format {
+	case "html", "styled-html":
+		return ".html"
+	case "json":
+		return ".json"
+	case "yaml":
+		return ".yaml"
+	case "confluence", "slack":
+		return ".txt"
+	default:
+		return ".md"
+	}
+}
+
+// editText opens the rendered update in the editor and returns the edited
+// text.
+func editText(text string, format string) (string, error) {
+	file, err := os.CreateTemp("", program+"-*"+editExtension(format))
+	if err != nil {
+		return "", err
+	}
+
+	path := file.Name()
+	defer os.Remove(path)
+
+	if _, err = file.WriteString(text); err != nil {
+		file.Close()
+		return "", err
+	}
+
+	if err = file.Close(); err != nil {
+		return "", err
+	}
+
+	editor := editorCommand()
+
+	// #nosec G204 -- the editor is chosen by the user running the command.
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err = cmd.Run(); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(string(edited)) == "" {
+		return "", errEmptyEdit
+	}
+
+	return string(edited), nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/logging"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/viper"
+)
+
+// maxSimilarSprints is the number of similar sprint names suggested when the
+// sprint has no issues.
+const maxSimilarSprints = 5
+
+// isEmpty reports whether neither the update nor its members have issues.
+func isEmpty(update *report.Update) bool {
+	if len(update.Issues) > 0 {
+		return false
+	}
+
+	for _, member := range update.Members {
+		if len(member.Issues) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkEmpty warns that the update has no issues, listing the likely causes.
+// Unless --allow-empty is set, or the user confirms rendering the update on
+// a terminal, errEmptyUpdate is returned.
+func checkEmpty(ctx context.Context, config *sprint.Config, sample bool) error {
+	if viper.GetBool("allow-empty") {
+		return nil
+	}
+
+	printStatus("Warning: no issues found for the update, the likely causes are:")
+	for _, cause := range emptyCauses(ctx, config, sample) {
+		printStatus("  -", cause)
+	}
+
+	if !isTerminal(os.Stdin.Fd()) || boolSetting("quiet") {
+		return fmt.Errorf("%w, use --allow-empty to render it anyway", errEmptyUpdate)
+	}
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stderr}
+	proceed, err := p.confirm("Render the update without issues anyway?")
+	if err != nil {
+		return err
+	}
+
+	if !proceed {
+		return errEmptyUpdate
+	}
+
+	return nil
+}
+
+// emptyCauses returns the likely causes of the update having no issues. The
+// sprints of the board similar to the configured sprint are suggested, unless
+// the sample issues are rendered.
+func emptyCauses(ctx context.Context, config *sprint.Config, sample bool) []string {
+	var causes []string
+
+	if config.Sprint != "" && config.SprintID == 0 {
+		cause := fmt.Sprintf("the sprint name %q may be misspelled", config.Sprint)
+
+		switch {
+		case sample || config.Tracker != nil:
+		case config.Board == 0:
+			cause += fmt.Sprintf(", list the sprints of the board with \"%s sprints --board <ID>\"", program)
+		default:
+			similar, err := config.SimilarSprints(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Verbose("listing similar sprints failed", "error", err)
+			}
+
+			var names []string
+			for i := range similar {
+				if similar[i].Name != config.Sprint && len(names) < maxSimilarSprints {
+					names = append(names, similar[i].Name)
+				}
+			}
+
+			if len(names) > 0 {
+				cause += ", similar sprints of the board: " + strings.Join(names, ", ")
+			}
+		}
+
+		causes = append(causes, cause)
+	}
+
+	switch {
+	case len(config.Assignees) > 0:
+		causes = append(causes, "the issues may be assigned to others than the team members "+strings.Join(config.Assignees, ", "))
+	case config.Assignee != "":
+		causes = append(causes, fmt.Sprintf("the issues may be assigned to someone else than %s", config.Assignee))
+	default:
+		causes = append(causes, "the issues may be assigned to someone else than the authenticated user, use --assignee to generate the update of a teammate")
+	}
+
+	if config.JQL != "" || len(config.JQLExtra) > 0 {
+		causes = append(causes, "the custom JQL query may match no issues")
+	}
+
+	if len(config.IncludeTypes) > 0 || len(config.ExcludeLabels) > 0 || len(config.ExcludeStatuses) > len(sprint.DefaultExcludeStatuses) {
+		causes = append(causes, "the --include-type, --exclude-label, or --exclude-status filters may leave out every issue")
+	}
+
+	if len(config.Projects) > 0 {
+		causes = append(causes, "the issues may belong to other projects than "+strings.Join(config.Projects, ", "))
+	}
+
+	return causes
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// envPrefix is the prefix of the environment variables of the settings, like
+// SPRINT_UPDATE_JIRA_URL for jira-url.
+var envPrefix = strings.ToUpper(strings.ReplaceAll(program, "-", "_"))
+
+// envKeyReplacer replaces the dashes of the setting keys, which are not valid
+// in the names of environment variables.
+var envKeyReplacer = strings.NewReplacer("-", "_")
+
+// envName returns the name of the environment variable of the setting.
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(envKeyReplacer.Replace(key))
+}
+
+// applyEnvironment sets the flags not set on the command line from their
+// environment variables. The values are parsed like on the command line, so
+// the list flags are split on commas, and invalid values are rejected. Empty
+// variables are ignored.
+func applyEnvironment(flags *pflag.FlagSet) error {
+	var err error
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if err != nil || flag.Changed || flag.Name == "help" {
+			return
+		}
+
+		value := os.Getenv(envName(flag.Name))
+		if value == "" {
+			return
+		}
+
+		if setErr := flags.Set(flag.Name, value); setErr != nil {
+			err = configError(fmt.Errorf("invalid %s: %w", envName(flag.Name), setErr))
+		}
+	})
+
+	return err
+}
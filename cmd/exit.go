@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"gabor-boros/sprint-update/pkg/git"
+	"gabor-boros/sprint-update/pkg/i18n"
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/network"
+	"gabor-boros/sprint-update/pkg/oauth"
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/signing"
+	"gabor-boros/sprint-update/pkg/sprint"
+	"gabor-boros/sprint-update/pkg/tracker"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// The exit codes of the failures, so scripts can tell them apart.
+const (
+	// exitFailure is the exit code of the failures not covered below.
+	exitFailure = 1
+	// exitConfig is the exit code of invalid flags or configuration.
+	exitConfig = 2
+	// exitAuth is the exit code of missing or rejected credentials.
+	exitAuth = 3
+	// exitSprintNotFound is the exit code of unknown sprints.
+	exitSprintNotFound = 4
+	// exitEmpty is the exit code of updates without issues, unless
+	// --allow-empty is set.
+	exitEmpty = 5
+	// exitDelivery is the exit code of failing to deliver the update.
+	exitDelivery = 6
+)
+
+// errorKinds are the kinds of the failures of the exit codes, reported by
+// --json-errors.
+var errorKinds = map[int]string{
+	exitFailure:        "error",
+	exitConfig:         "config",
+	exitAuth:           "auth",
+	exitSprintNotFound: "sprint_not_found",
+	exitEmpty:          "empty",
+	exitDelivery:       "delivery",
+}
+
+// globalFlags are the flags of the root command, which are looked up when
+// printing the errors.
+var globalFlags *pflag.FlagSet
+
+func init() {
+	globalFlags = rootCmd.PersistentFlags()
+}
+
+// errEmptyUpdate is returned when the update has no issues, unless
+// --allow-empty is set.
+var errEmptyUpdate = errors.New("the update has no issues")
+
+// configErrors are the errors of invalid configuration.
+var configErrors = []error{
+	errSampleWithoutDryRun,
+	errDocumentFormat,
+	errUnknownTarget,
+	errUnknownAmendMode,
+	errDraftAndAmend,
+	errRecordAndReplay,
+	errNoConfigFile,
+	errInvalidConfig,
+	errInvalidMaxItems,
+	errUnknownSetting,
+	errSecretSetting,
+	errTableSetting,
+	sprint.ErrMissingSprint,
+	sprint.ErrSprintNameAndID,
+	sprint.ErrAssigneeAndTeam,
+	sprint.ErrUnsupportedByTracker,
+	sprint.ErrInvalidPeriod,
+	sprint.ErrSprintAndPeriod,
+	sprint.ErrMissingLanguageModel,
+	git.ErrUnknownAuthor,
+	jira.ErrUnknownAuthType,
+	jira.ErrUnknownField,
+	jira.ErrInvalidJQL,
+	jira.ErrInvalidJQLValue,
+	report.ErrUnknownGroupBy,
+	report.ErrUnknownSortBy,
+	report.ErrUnknownFoldBy,
+	report.ErrUnknownSubtaskMode,
+	report.ErrUnknownAnnotation,
+	report.ErrUnknownSection,
+	report.ErrDuplicateSection,
+	render.ErrUnknownFormat,
+	render.ErrNoTemplate,
+	i18n.ErrUnknownLanguage,
+	network.ErrUnsupportedProxy,
+	network.ErrInvalidCACert,
+	signing.ErrUnknownTool,
+}
+
+// exitError is an error exiting with the given code.
+type exitError struct {
+	code int
+	err  error
+}
+
+// Error returns the message of the wrapped error.
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// configError marks the error as a configuration error. If the error is nil,
+// nil is returned.
+func configError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &exitError{code: exitConfig, err: err}
+}
+
+// exitCode returns the exit code of the error.
+func exitCode(err error) int {
+	var authErr *jira.AuthError
+	if errors.As(err, &authErr) || errors.Is(err, jira.ErrMissingCredentials) || errors.Is(err, oauth.ErrNotLoggedIn) {
+		return exitAuth
+	}
+
+	if errors.Is(err, jira.ErrSprintNotFound) || errors.Is(err, jira.ErrNoActiveSprint) || errors.Is(err, tracker.ErrSprintNotFound) || errors.Is(err, errAmbiguousSprint) {
+		return exitSprintNotFound
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	if errors.Is(err, errEmptyUpdate) {
+		return exitEmpty
+	}
+
+	for _, configErr := range configErrors {
+		if errors.Is(err, configErr) {
+			return exitConfig
+		}
+	}
+
+	return exitFailure
+}
+
+// checkErr prints the error and exits with its exit code if the error is not
+// nil. The error is printed as JSON when --json-errors is set, and not
+// printed at all when --quiet is set.
+func checkErr(err error) {
+	if err == nil {
+		return
+	}
+
+	code := exitCode(err)
+	printError(err, code)
+	os.Exit(code)
+}
+
+// printError prints the error of the given exit code to stderr.
+func printError(err error, code int) {
+	switch {
+	case boolSetting("json-errors"):
+		_ = json.NewEncoder(os.Stderr).Encode(map[string]interface{}{
+			"error": err.Error(),
+			"kind":  errorKinds[code],
+			"code":  code,
+		})
+	case !boolSetting("quiet"):
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+}
+
+// silenceUsage leaves the usage out of the flag errors when --quiet or
+// --json-errors is set before the invalid flag.
+func silenceUsage(cmd *cobra.Command, err error) error {
+	if boolSetting("quiet") || boolSetting("json-errors") {
+		cmd.SilenceUsage = true
+	}
+
+	return err
+}
+
+// boolSetting returns the boolean setting of the global flag. When parsing
+// the flags fails, the flags are not bound to the settings yet, hence the
+// flags parsed before the failure are looked up too.
+func boolSetting(name string) bool {
+	if flag := globalFlags.Lookup(name); flag != nil && flag.Changed {
+		return flag.Value.String() == "true"
+	}
+
+	return viper.GetBool(name)
+}
+
+// printStatus prints the progress message to stderr, unless --quiet is set.
+func printStatus(a ...interface{}) {
+	if !boolSetting("quiet") {
+		fmt.Fprintln(os.Stderr, a...)
+	}
+}
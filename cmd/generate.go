@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/logging"
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/signing"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// errSampleWithoutDryRun is returned when the sample issues are requested
+// outside of a dry run, which would save the sample to the state and history.
+var errSampleWithoutDryRun = errors.New("--sample requires --dry-run or --preview")
+
+// errDocumentFormat is returned when a document format, like pdf, is edited,
+// copied to the clipboard, or delivered, which only text can be.
+var errDocumentFormat = errors.New("the update can only be written to a file in this format")
+
+var (
+	generateCmd = &cobra.Command{
+		Use:     "generate",
+		Short:   "Generate a sprint update.",
+		Long:    "Generate a sprint update and write it to the standard output or the output file, without delivering it.",
+		Example: fmt.Sprintf("%s generate --sprint SE.253 -e", program),
+		Args:    cobra.NoArgs,
+		Run:     runGenerateCmd,
+	}
+	postCmd = &cobra.Command{
+		Use:     "post",
+		Short:   "Generate a sprint update and deliver it.",
+		Long:    "Generate a sprint update, write it to the standard output or the output file, and deliver it to the targets. When no targets are set, the update is posted to Discourse.",
+		Example: fmt.Sprintf("%s post --sprint SE.253 -e --to discourse,slack", program),
+		Args:    cobra.NoArgs,
+		Run:     runPostCmd,
+	}
+)
+
+func init() {
+	addGenerationFlags(generateCmd.Flags())
+	rootCmd.AddCommand(generateCmd)
+
+	addGenerationFlags(postCmd.Flags())
+	addDeliveryFlags(postCmd.Flags())
+	rootCmd.AddCommand(postCmd)
+}
+
+// runGenerateCmd generates the update without delivering it.
+func runGenerateCmd(cmd *cobra.Command, _ []string) {
+	generateUpdate(cmd, nil)
+}
+
+// runPostCmd generates the update and delivers it to the configured targets,
+// or to Discourse if no targets are set.
+func runPostCmd(cmd *cobra.Command, _ []string) {
+	targets, err := deliveryTargets()
+	checkErr(err)
+
+	if len(targets) == 0 {
+		targets = []string{targetDiscourse}
+	}
+
+	generateUpdate(cmd, targets)
+}
+
+// generateUpdate generates the update, writes it to the output, and delivers
+// it to the given targets.
+func generateUpdate(cmd *cobra.Command, targets []string) {
+	config := newConfig()
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	recorder, err := prepareConfig(&config)
+	checkErr(err)
+
+	checkErr(runUpdate(ctx, config, recorder, targets))
+}
+
+// checkDocumentFormat checks that the update is not edited, copied to the
+// clipboard, or delivered, if it is rendered in a document format.
+func checkDocumentFormat(formatName string, targets []string, dryRun bool) error {
+	format, err := render.LookupFormat(formatName)
+	if err != nil || !format.Document {
+		return nil
+	}
+
+	if viper.GetBool("edit") || viper.GetBool("clipboard") || (len(targets) > 0 && !dryRun) {
+		return fmt.Errorf("%w: %s", errDocumentFormat, format.Name)
+	}
+
+	return nil
+}
+
+// prepareConfig reads the Jira secret and validates the configuration, unless
+// the sample issues are rendered. When recording, the returned recorder
+// collects the Jira responses.
+func prepareConfig(config *sprint.Config) (*jira.Recorder, error) {
+	if viper.GetBool("sample") {
+		if !viper.GetBool("dry-run") && !viper.GetBool("preview") {
+			return nil, errSampleWithoutDryRun
+		}
+
+		return nil, nil
+	}
+
+	recorder, err := setupSnapshot(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if viper.GetString("replay") == "" && config.Tracker == nil {
+		if err = readJiraSecret(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return recorder, configError(config.Validate())
+}
+
+// runUpdate generates the update of the prepared configuration, writes it to
+// the output, and delivers it to the given targets.
+func runUpdate(ctx context.Context, config sprint.Config, recorder *jira.Recorder, targets []string) error {
+	dryRun := viper.GetBool("dry-run")
+	sample := viper.GetBool("sample")
+
+	if err := checkDocumentFormat(config.Format, targets, dryRun); err != nil {
+		return err
+	}
+
+	if err := signing.ValidateTool(signing.Tool(viper.GetString("sign"))); err != nil {
+		return err
+	}
+
+	outputTmpl, err := parseOutputPath(viper.GetString("output"))
+	if err != nil {
+		return err
+	}
+
+	if !sample && config.Tracker == nil && config.Sprint != "" && config.Board != 0 && viper.GetString("replay") == "" {
+		if err = resolveSprintNames(ctx, &config); err != nil {
+			return err
+		}
+	}
+
+	if !sample && config.Tracker == nil && (config.SprintID != 0 || config.Sprint == "" && config.Board != 0) {
+		jiraClient, err := config.JiraClient()
+		if err != nil {
+			return err
+		}
+
+		if err = config.ResolveSprint(ctx, jiraClient); err != nil {
+			return err
+		}
+
+		if config.SprintID != 0 {
+			printStatus("Using sprint:", config.Sprint)
+		} else {
+			printStatus("Using active sprint:", config.Sprint)
+		}
+	}
+
+	update, err := buildUpdate(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	if isEmpty(update) {
+		if err = checkEmpty(ctx, &config, sample); err != nil {
+			return err
+		}
+	}
+
+	if recorder != nil {
+		if err = saveSnapshot(recorder, &config); err != nil {
+			return err
+		}
+	}
+
+	if viper.GetBool("preview") {
+		for _, target := range targets {
+			printStatus("Preview, not delivering the update to", target)
+		}
+
+		return previewUpdate(update)
+	}
+
+	logging.FromContext(ctx).Verbose("rendering update", "format", config.Format, "template", config.TemplateName())
+
+	text, err := config.Render(update)
+	if err != nil {
+		return err
+	}
+
+	if text, err = config.RunPostRenderHooks(ctx, text); err != nil {
+		return err
+	}
+
+	edit := viper.GetBool("edit")
+	if edit {
+		if text, err = editText(text, config.Format); err != nil {
+			return err
+		}
+	}
+
+	outputPath, err := newOutputPath(outputTmpl, &config, update)
+	if err != nil {
+		return err
+	}
+
+	if err = writeOutput(outputPath, text); err != nil {
+		return err
+	}
+
+	signature, err := signUpdate(ctx, outputPath, text)
+	if err != nil {
+		return err
+	}
+
+	if viper.GetBool("clipboard") {
+		if err = copyToClipboard(text); err != nil {
+			return err
+		}
+
+		printStatus("Sprint update copied to the clipboard")
+	}
+
+	if dryRun {
+		for _, target := range targets {
+			printStatus("Dry run, not delivering the update to", target)
+		}
+
+		return nil
+	}
+
+	if err = config.SaveState(update); err != nil {
+		return err
+	}
+
+	if err = config.SaveHistory(update, text); err != nil {
+		return err
+	}
+
+	if err = config.SaveSignature(signature); err != nil {
+		return err
+	}
+
+	return deliver(ctx, targets, &config, update, text, edit)
+}
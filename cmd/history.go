@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gabor-boros/sprint-update/pkg/history"
+
+	"github.com/spf13/cobra"
+)
+
+// historyTimeLayout is the layout of the creation time of the listed updates.
+const historyTimeLayout = "2006-01-02 15:04"
+
+// errNoHistory is returned when the sprint has no archived updates.
+var errNoHistory = errors.New("no archived updates found")
+
+// errNotSigned is returned when printing the signature of an archived update
+// that was not signed.
+var errNotSigned = errors.New("the archived update is not signed")
+
+var (
+	historyCmd = &cobra.Command{
+		Use:   "history",
+		Short: "Browse the archived updates.",
+		Long:  "Browse the updates archived in the history directory. Every generated update is archived together with the issues it listed.",
+	}
+	historyListCmd = &cobra.Command{
+		Use:   "list [sprint]",
+		Short: "List the sprints, or the updates of a sprint.",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   runHistoryListCmd,
+	}
+	historyShowCmd = &cobra.Command{
+		Use:     "show <sprint> [n]",
+		Short:   "Print the last update of a sprint, or its nth update.",
+		Example: fmt.Sprintf("%[1]s history show SE.253 > update.md\n%[1]s history show SE.253 --signature > update.md.asc\ngpg --verify update.md.asc update.md", program),
+		Args:    cobra.RangeArgs(1, 2),
+		Run:     runHistoryShowCmd,
+	}
+)
+
+func init() {
+	historyShowCmd.Flags().BoolP("signature", "", false, "print the detached signature of the update instead, if it was signed")
+	historyCmd.AddCommand(historyListCmd)
+	historyCmd.AddCommand(historyShowCmd)
+	rootCmd.AddCommand(historyCmd)
+}
+
+// runHistoryListCmd prints the sprints having archived updates. If a sprint
+// is given, its updates are printed instead.
+func runHistoryListCmd(_ *cobra.Command, args []string) {
+	dir, err := historyDirPath()
+	checkErr(err)
+
+	if len(args) == 0 {
+		sprints, err := history.Sprints(dir)
+		checkErr(err)
+
+		for _, name := range sprints {
+			fmt.Println(name)
+		}
+
+		return
+	}
+
+	entries, err := history.Entries(dir, args[0])
+	checkErr(err)
+
+	for i, entry := range entries {
+		fmt.Printf("%3d. %s  %s\n", i+1, entry.Created.Local().Format(historyTimeLayout), entry.Title)
+	}
+}
+
+// runHistoryShowCmd prints the rendered text of an archived update, or its
+// signature.
+func runHistoryShowCmd(cmd *cobra.Command, args []string) {
+	number := ""
+	if len(args) == 2 {
+		number = args[1]
+	}
+
+	entry, err := historyEntry(args[0], number)
+	checkErr(err)
+
+	signature, err := cmd.Flags().GetBool("signature")
+	checkErr(err)
+
+	if !signature {
+		fmt.Print(entry.Output)
+		return
+	}
+
+	if entry.Signature == "" {
+		checkErr(errNotSigned)
+	}
+
+	fmt.Print(entry.Signature)
+}
+
+// historyEntry returns the archived update of the sprint having the given
+// number, or the last update of the sprint if the number is empty.
+func historyEntry(sprint string, number string) (*history.Entry, error) {
+	dir, err := historyDirPath()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := history.Entries(dir, sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoHistory, sprint)
+	}
+
+	n := len(entries)
+	if number != "" {
+		n, err = strconv.Atoi(number)
+		if err != nil || n < 1 || n > len(entries) {
+			return nil, fmt.Errorf("invalid update number: %s (available: 1-%d)", number, len(entries))
+		}
+	}
+
+	return &entries[n-1], nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gabor-boros/sprint-update/pkg/credentials"
+	"gabor-boros/sprint-update/pkg/oauth"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultRedirectURL is the default callback URL of the OAuth 2.0 app.
+const defaultRedirectURL = "http://localhost:8089/callback"
+
+// oauthTokenKey is the keyring key of the OAuth 2.0 token.
+const oauthTokenKey = "oauth-token"
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to Jira Cloud using OAuth 2.0.",
+	Long:  "Log in to Jira Cloud using the OAuth 2.0 (3LO) flow, and store the refresh token for subsequent runs with auth-type \"oauth\".",
+	Run:   runLoginCmd,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringP("oauth-client-id", "", "", "client ID of the OAuth 2.0 app")
+	rootCmd.PersistentFlags().StringP("oauth-client-secret", "", "", "client secret of the OAuth 2.0 app")
+	rootCmd.PersistentFlags().StringP("oauth-redirect-url", "", defaultRedirectURL, "callback URL of the OAuth 2.0 app")
+	rootCmd.PersistentFlags().StringP("oauth-token-file", "", "", "file storing the OAuth 2.0 token (default is the keyring, or $XDG_CONFIG_HOME/sprint-update/oauth-token.json)")
+
+	rootCmd.AddCommand(loginCmd)
+}
+
+// newOAuthConfig returns the OAuth 2.0 app settings from the configuration.
+func newOAuthConfig() *oauth.Config {
+	return &oauth.Config{
+		ClientID:     viper.GetString("oauth-client-id"),
+		ClientSecret: secret("oauth-client-secret"),
+		RedirectURL:  viper.GetString("oauth-redirect-url"),
+		HTTPClient:   newHTTPClient(),
+	}
+}
+
+// newTokenStore returns the store of the OAuth 2.0 token. Unless a token file
+// is configured, the token is stored in the keyring if it is available.
+func newTokenStore() (oauth.TokenStore, error) {
+	path := viper.GetString("oauth-token-file")
+	if path == "" && credentials.Available() {
+		return &oauth.KeyringStore{Key: profileKey(oauthTokenKey)}, nil
+	}
+
+	if path == "" {
+		configDir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+
+		path = filepath.Join(configDir, program, profileFile("oauth-token.json"))
+	}
+
+	return &oauth.FileStore{Path: path}, nil
+}
+
+// runLoginCmd performs the OAuth 2.0 login and stores the received token.
+func runLoginCmd(cmd *cobra.Command, _ []string) {
+	store, err := newTokenStore()
+	checkErr(err)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	token, err := newOAuthConfig().Login(ctx, viper.GetString("jira-url"), func(authURL string) {
+		fmt.Fprintln(os.Stderr, "Opening the browser to authorize sprint-update. If it does not open, visit:")
+		fmt.Fprintln(os.Stderr, authURL)
+	})
+	checkErr(err)
+
+	checkErr(store.Save(token))
+	fmt.Fprintln(os.Stderr, "Logged in to", token.SiteURL)
+}
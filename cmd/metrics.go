@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/metrics"
+
+	"github.com/spf13/viper"
+)
+
+// metricsExportTimeout is the time given to exporting the metrics to the
+// OpenTelemetry collector.
+const metricsExportTimeout = 10 * time.Second
+
+// exportMetrics writes the metrics of the daemon to the Prometheus textfile
+// and exports them to the OpenTelemetry collector, if configured. Failing to
+// export the metrics is reported without stopping the daemon, so the updates
+// are delivered regardless.
+func exportMetrics(ctx context.Context, collector *metrics.Collector) {
+	if path := viper.GetString("metrics-file"); path != "" {
+		if err := collector.WriteTextfile(path); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: failed to write the metrics file:", err)
+		}
+	}
+
+	endpoint := viper.GetString("otlp-endpoint")
+	if endpoint == "" {
+		return
+	}
+
+	header := make(http.Header)
+	for key, value := range viper.GetStringMapString("otlp-headers") {
+		header.Set(key, value)
+	}
+
+	exporter := &metrics.OTLPExporter{
+		Endpoint:       endpoint,
+		Header:         header,
+		ServiceName:    program,
+		ServiceVersion: version,
+		HTTPClient:     newHTTPClient(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, metricsExportTimeout)
+	defer cancel()
+
+	if err := exporter.Export(ctx, collector); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: failed to export the metrics:", err)
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+
+	"gabor-boros/sprint-update/pkg/network"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	// transportOnce guards creating the shared transport.
+	transportOnce sync.Once
+	// sharedTransport is the transport of every client, or nil if the
+	// connections use the defaults.
+	sharedTransport *http.Transport
+)
+
+// networkSettings returns the proxy and certificate settings from the
+// configuration.
+func networkSettings() network.Settings {
+	return network.Settings{
+		ProxyURL:           viper.GetString("proxy"),
+		CACertFile:         viper.GetString("ca-cert"),
+		InsecureSkipVerify: viper.GetBool("insecure-skip-verify"),
+	}
+}
+
+// newTransport returns the transport shared by every client, so the proxy and
+// certificate settings apply to Jira, the code hosts, the calendar, and the
+// delivery targets alike. If the defaults are used, nil is returned.
+func newTransport() *http.Transport {
+	transportOnce.Do(func() {
+		settings := networkSettings()
+		if settings.IsDefault() {
+			return
+		}
+
+		if settings.InsecureSkipVerify {
+			fmt.Fprintln(os.Stderr, "Warning: the TLS certificates of the servers are not verified, the connections can be intercepted")
+		}
+
+		transport, err := settings.Transport()
+		checkErr(err)
+
+		sharedTransport = transport
+	})
+
+	return sharedTransport
+}
+
+// newHTTPClient returns the HTTP client using the shared transport, or nil if
+// the defaults are used.
+func newHTTPClient() *http.Client {
+	transport := newTransport()
+	if transport == nil {
+		return nil
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// jiraTransport returns the shared transport as the underlying transport of
+// the Jira requests, or nil if the defaults are used.
+func jiraTransport() http.RoundTripper {
+	// Avoid returning a non-nil interface holding a nil transport.
+	if transport := newTransport(); transport != nil {
+		return transport
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gabor-boros/sprint-update/pkg/history"
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/sprint"
+)
+
+// stdoutPath is the output path referring to the standard output.
+const stdoutPath = "-"
+
+// windowsEnvPattern matches the environment variables of the paths written
+// as on Windows, like %APPDATA%.
+var windowsEnvPattern = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_]*)%`)
+
+// expandPath expands the leading "~" of the path to the home directory, and
+// the environment variables written like $HOME or %APPDATA%, leaving the
+// unset variables as is. The slashes are converted to the separator of the
+// platform, so the paths of a configuration file shared by Unix and Windows
+// users work on both.
+func expandPath(path string) string {
+	lookup := func(name string, unset string) string {
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+
+		return unset
+	}
+
+	path = windowsEnvPattern.ReplaceAllStringFunc(path, func(match string) string {
+		return lookup(strings.Trim(match, "%"), match)
+	})
+
+	path = os.Expand(path, func(name string) string {
+		return lookup(name, "$"+name)
+	})
+
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			path = homeDir + path[1:]
+		}
+	}
+
+	return filepath.FromSlash(path)
+}
+
+// parseOutputPath parses the output path as a template, so the path can
+// contain the sprint name and update type. The path is expanded first, see
+// expandPath.
+func parseOutputPath(path string) (*template.Template, error) {
+	if path == "" {
+		path = stdoutPath
+	}
+
+	return template.New("output").Option("missingkey=error").Parse(expandPath(path))
+}
+
+// newOutputPath renders the output path for the sprint update. The names are
+// sanitized, so a sprint named like "Team/Sprint 5" or "Sprint 5: Launch"
+// neither creates a directory nor a file name invalid on Windows.
+func newOutputPath(tmpl *template.Template, config *sprint.Config, update *report.Update) (string, error) {
+	var path strings.Builder
+
+	data := titleData(config, update)
+	data.Sprint = history.SanitizeName(data.Sprint)
+	data.Type = history.SanitizeName(data.Type)
+	data.Assignee = history.SanitizeName(data.Assignee)
+
+	if err := tmpl.Execute(&path, data); err != nil {
+		return "", err
+	}
+
+	return path.String(), nil
+}
+
+// titleData returns the input of the title templates, including the sprint
+// dates resolved while building the update.
+func titleData(config *sprint.Config, update *report.Update) render.TitleData {
+	data := config.TitleData()
+	data.StartDate = update.StartDate
+	data.EndDate = update.EndDate
+	data.DaysRemaining = update.DaysRemaining
+	data.Assignee = update.Assignee
+
+	return data
+}
+
+// writeOutput writes the sprint update to the file at the given path,
+// creating its parent directories as necessary. If the path is stdoutPath,
+// the update is written to the standard output.
+func writeOutput(path string, update string) error {
+	if path == stdoutPath {
+		fmt.Print(update)
+		return nil
+	}
+
+	path = filepath.Clean(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(update), 0600); err != nil {
+		return err
+	}
+
+	printStatus("Sprint update written to", path)
+	return nil
+}
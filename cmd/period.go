@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// periodDateLayout is the layout of the dates of --from and --until.
+const periodDateLayout = "2006-01-02"
+
+// errInvalidDate is returned when a date of the period cannot be parsed.
+var errInvalidDate = errors.New("invalid date")
+
+// sprintNames returns the configured sprint names. A single name read from the
+// configuration file or the environment is not split on whitespace, as sprint
+// names, like "Sprint 12", may contain spaces.
+func sprintNames() []string {
+	if name, ok := viper.Get("sprint").(string); ok {
+		if name == "" {
+			return nil
+		}
+
+		return []string{name}
+	}
+
+	return viper.GetStringSlice("sprint")
+}
+
+// periodDate returns the date of the period configured by the key, or the
+// zero time if it is not set. When endOfDay is set, the end of the day is
+// returned, so the period includes the whole day.
+func periodDate(key string, endOfDay bool) (time.Time, error) {
+	value := viper.GetString(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.ParseInLocation(periodDateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: --%s %s (expected YYYY-MM-DD)", errInvalidDate, key, value)
+	}
+
+	if endOfDay {
+		date = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+
+	return date, nil
+}
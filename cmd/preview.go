@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// defaultPreviewWidth is the width the preview is wrapped at when the width of
+// the terminal is unknown, like when the output is piped.
+const defaultPreviewWidth = 80
+
+// previewUpdate renders the update to the standard output for reading in the
+// terminal, based on the built-in markdown template. The preview is colored,
+// unless the standard output is not a terminal or NO_COLOR is set.
+func previewUpdate(update *report.Update) error {
+	text, err := render.RenderFormat("markdown", update)
+	if err != nil {
+		return err
+	}
+
+	width := terminalWidth(os.Stdout.Fd())
+	if width <= 0 {
+		width = defaultPreviewWidth
+	}
+
+	color := os.Getenv("NO_COLOR") == "" && enableColors(os.Stdout.Fd())
+
+	fmt.Print(render.Preview(text, width, color))
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// profilesKey is the configuration key of the named profiles.
+const profilesKey = "profiles"
+
+// errUnknownProfile is returned when the selected profile is not configured.
+var errUnknownProfile = errors.New("unknown profile")
+
+var (
+	profilesCmd = &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage the named profiles.",
+		Long:  "Manage the named profiles of the configuration file. Every profile is a table under \"profiles\", like [profiles.work], holding its own settings, which take precedence over the top-level settings.",
+	}
+	profilesListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the configured profiles.",
+		Args:  cobra.NoArgs,
+		Run:   runProfilesListCmd,
+	}
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringP("profile", "p", "", "named profile of the config file to use")
+
+	profilesCmd.AddCommand(profilesListCmd)
+	rootCmd.AddCommand(profilesCmd)
+}
+
+// profiles returns the names of the configured profiles.
+func profiles() []string {
+	var names []string
+	for name := range viper.GetStringMap(profilesKey) {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile merges the settings of the selected profile into the
+// configuration. The profile settings take precedence over the top-level
+// settings of the configuration file, but not over flags and environment
+// variables.
+func applyProfile() error {
+	name := activeProfile()
+	if name == "" {
+		return nil
+	}
+
+	settings := viper.Sub(profilesKey + "." + name)
+	if settings == nil {
+		return fmt.Errorf("%w: %s (available: %s)", errUnknownProfile, name, strings.Join(profiles(), ", "))
+	}
+
+	return viper.MergeConfigMap(settings.AllSettings())
+}
+
+// activeProfile returns the name of the selected profile, or an empty string
+// if no profile is selected.
+func activeProfile() string {
+	return strings.ToLower(viper.GetString("profile"))
+}
+
+// profileKey returns the keyring key of the secret in the selected profile, so
+// every profile can store its own credentials.
+func profileKey(key string) string {
+	if name := activeProfile(); name != "" {
+		return name + "." + key
+	}
+
+	return key
+}
+
+// profileFile returns the file name in the selected profile, like
+// "state.work.json" for "state.json", so every profile keeps its own state.
+func profileFile(name string) string {
+	profile := activeProfile()
+	if profile == "" {
+		return name
+	}
+
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + profile + ext
+}
+
+// runProfilesListCmd prints the configured profiles, marking the selected one.
+func runProfilesListCmd(_ *cobra.Command, _ []string) {
+	active := activeProfile()
+
+	for _, name := range profiles() {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+
+		fmt.Printf("%s %s\n", marker, name)
+	}
+}
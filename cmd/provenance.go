@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gabor-boros/sprint-update/pkg/signing"
+
+	"github.com/spf13/viper"
+)
+
+// provenanceTool returns the name and the version of the command rendered in
+// the provenance footer, like "sprint-update 1.2.0 (commit 3a7bd3e)", or an
+// empty string if the footer is not rendered. Dirty builds have no version.
+func provenanceTool() string {
+	if !viper.GetBool("provenance") {
+		return ""
+	}
+
+	if version == "" || len(commit) < 7 {
+		return program + " (dirty build)"
+	}
+
+	return fmt.Sprintf("%s %s (commit %s)", program, version, commit[:7])
+}
+
+// signUpdate signs the update written to the output path with the configured
+// tool, writing the detached signature next to the output file, and returns
+// the signature, or an empty string if the update is not signed.
+func signUpdate(ctx context.Context, outputPath string, text string) (string, error) {
+	tool := signing.Tool(viper.GetString("sign"))
+	if tool == "" {
+		return "", nil
+	}
+
+	name := ""
+	if outputPath != stdoutPath {
+		name = filepath.Base(outputPath)
+	}
+
+	signature, err := signing.Sign(ctx, tool, viper.GetString("signing-key"), name, []byte(text))
+	if err != nil {
+		return "", err
+	}
+
+	if outputPath == stdoutPath {
+		printStatus("Signed the update with", string(tool))
+		return string(signature), nil
+	}
+
+	signaturePath := filepath.Clean(outputPath) + tool.Extension()
+	if err = os.WriteFile(signaturePath, signature, 0600); err != nil {
+		return "", err
+	}
+
+	printStatus("Signature written to", signaturePath)
+	return string(signature), nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/textdiff"
+
+	"github.com/spf13/cobra"
+)
+
+// historyEntrySeparator separates the sprint from the number of its archived
+// update in the arguments of the review command, like "SE.253@2".
+const historyEntrySeparator = "@"
+
+var reviewCmd = &cobra.Command{
+	Use:   "review <old> <new>",
+	Short: "Compare two updates, like a draft and its revision.",
+	Long: fmt.Sprintf(`Compare two generated updates line by line, printing a unified diff wrapped in a Markdown code block, which renders the removed lines in red and the added lines in green, so a teammate can review a draft before it is posted.
+
+The updates are files, or archived updates given as the sprint followed by %[1]q and the number of the update, as listed by the history list command, like "SE.253%[1]s2". A sprint alone refers to its last update.`, historyEntrySeparator),
+	Example: fmt.Sprintf(`%[1]s review draft.md revised.md
+%[1]s review SE.253@1 SE.253@2
+%[1]s review SE.252 SE.253 --plain | less`, program),
+	Args: cobra.ExactArgs(2),
+	Run:  runReviewCmd,
+}
+
+func init() {
+	reviewCmd.Flags().IntP("context", "C", textdiff.DefaultContext, "number of unchanged lines printed around the changes")
+	reviewCmd.Flags().BoolP("plain", "", false, "print the diff without the Markdown code block, like for patch tools")
+	reviewCmd.Flags().StringP("output", "o", stdoutPath, "file to write the diff to")
+	rootCmd.AddCommand(reviewCmd)
+}
+
+// runReviewCmd prints the diff of the updates.
+func runReviewCmd(cmd *cobra.Command, args []string) {
+	oldName, oldText, err := reviewedUpdate(args[0])
+	checkErr(err)
+
+	newName, newText, err := reviewedUpdate(args[1])
+	checkErr(err)
+
+	contextLines, err := cmd.Flags().GetInt("context")
+	checkErr(err)
+
+	plain, err := cmd.Flags().GetBool("plain")
+	checkErr(err)
+
+	output, err := cmd.Flags().GetString("output")
+	checkErr(err)
+
+	diff := textdiff.Unified(oldName, newName, oldText, newText, contextLines)
+	if diff == "" {
+		printStatus("The updates are the same.")
+		return
+	}
+
+	removed, added := textdiff.Changes(oldText, newText)
+	printStatus(fmt.Sprintf("Removed %d and added %d lines", removed, added))
+
+	if !plain {
+		diff = markdownCodeBlock("diff", diff)
+	}
+
+	checkErr(writeOutput(output, diff))
+}
+
+// reviewedUpdate returns the name and the text of the update given as a file,
+// or as an archived update of a sprint, like "SE.253@2" for its second update
+// or "SE.253" for its last update.
+func reviewedUpdate(arg string) (string, string, error) {
+	content, err := os.ReadFile(arg)
+	if err == nil {
+		return arg, string(content), nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", "", err
+	}
+
+	sprint, number := arg, ""
+	if i := strings.LastIndex(arg, historyEntrySeparator); i > 0 {
+		sprint, number = arg[:i], arg[i+len(historyEntrySeparator):]
+	}
+
+	entry, err := historyEntry(sprint, number)
+	if err != nil {
+		return "", "", fmt.Errorf("%s is neither a file nor an archived update: %w", arg, err)
+	}
+
+	return fmt.Sprintf("%s (%s)", arg, entry.Created.Local().Format(historyTimeLayout)), entry.Output, nil
+}
+
+// markdownCodeBlock wraps the text in a fenced Markdown code block of the
+// language. The fence is longer than any run of backticks of the text, so
+// the code blocks of the text do not end the block early.
+func markdownCodeBlock(language string, text string) string {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r != '`' {
+			run = 0
+			continue
+		}
+
+		if run++; run > longest {
+			longest = run
+		}
+	}
+
+	fence := strings.Repeat("`", 3)
+	if longest >= len(fence) {
+		fence = strings.Repeat("`", longest+1)
+	}
+
+	return fence + language + "\n" + strings.TrimSuffix(text, "\n") + "\n" + fence + "\n"
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/rollup"
+
+	"github.com/spf13/cobra"
+)
+
+// errMonthAndQuarter is returned when both a month and a quarter are set for
+// the rollup.
+var errMonthAndQuarter = errors.New("--month and --quarter cannot be used together")
+
+var rollupCmd = &cobra.Command{
+	Use:   "rollup",
+	Short: "Summarize the archived updates of a month or a quarter.",
+	Long:  "Summarize the completed issues of the sprints archived in the history directory within a month or a quarter, grouped by epic, with the number of completed issues and story points. The final update of every sprint is used, which is its last end of sprint update if any.",
+	Example: fmt.Sprintf(`%[1]s rollup --quarter 2026-Q3
+%[1]s rollup --month 2026-09 --output september.md`, program),
+	Args: cobra.NoArgs,
+	Run:  runRollupCmd,
+}
+
+func init() {
+	rollupCmd.Flags().StringP("quarter", "", "", "quarter to summarize (ex: 2026-Q3, default is the current quarter)")
+	rollupCmd.Flags().StringP("month", "", "", "month to summarize instead of a quarter (ex: 2026-09)")
+	rollupCmd.Flags().StringP("format", "f", "markdown", fmt.Sprintf("output format, the values are escaped according to its rules (%s)", strings.Join(render.Formats(), ", ")))
+	rollupCmd.Flags().StringP("template", "t", "", "go template file used to render the rollup instead of the built-in markdown template")
+	rollupCmd.Flags().StringP("output", "o", stdoutPath, "file to write the rollup to")
+	rootCmd.AddCommand(rollupCmd)
+}
+
+// runRollupCmd renders the rollup of the configured period.
+func runRollupCmd(cmd *cobra.Command, _ []string) {
+	period, err := rollupPeriod(cmd)
+	checkErr(err)
+
+	dir, err := historyDirPath()
+	checkErr(err)
+
+	summary, err := rollup.Load(dir, period)
+	checkErr(err)
+
+	text, err := renderRollup(cmd, summary)
+	checkErr(err)
+
+	output, err := cmd.Flags().GetString("output")
+	checkErr(err)
+
+	checkErr(writeOutput(output, text))
+}
+
+// rollupPeriod returns the month or the quarter set by the flags, defaulting
+// to the current quarter.
+func rollupPeriod(cmd *cobra.Command) (rollup.Period, error) {
+	month, err := cmd.Flags().GetString("month")
+	if err != nil {
+		return rollup.Period{}, err
+	}
+
+	quarter, err := cmd.Flags().GetString("quarter")
+	if err != nil {
+		return rollup.Period{}, err
+	}
+
+	switch {
+	case month != "" && quarter != "":
+		return rollup.Period{}, errMonthAndQuarter
+	case month != "":
+		return rollup.Month(month)
+	case quarter != "":
+		return rollup.Quarter(quarter)
+	default:
+		return rollup.CurrentQuarter(time.Now()), nil
+	}
+}
+
+// renderRollup renders the rollup using the template set by the flags, or
+// encodes it when a structured format is set.
+func renderRollup(cmd *cobra.Command, summary *rollup.Rollup) (string, error) {
+	formatName, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return "", err
+	}
+
+	format, err := render.LookupFormat(formatName)
+	if err != nil {
+		return "", err
+	}
+
+	if format.IsStructured() {
+		return render.Encode(format, summary)
+	}
+
+	templateFile, err := cmd.Flags().GetString("template")
+	if err != nil {
+		return "", err
+	}
+
+	if templateFile != "" {
+		tmpl, err := render.ParseTemplateFile(templateFile, format)
+		if err != nil {
+			return "", err
+		}
+
+		return render.Render(tmpl, summary)
+	}
+
+	tmpl, err := render.ParseTemplate("rollup", rollup.DefaultTemplate, format)
+	if err != nil {
+		return "", err
+	}
+
+	return render.Render(tmpl, summary)
+}
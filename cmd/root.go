@@ -1,54 +1,47 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"html/template"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 
-	"github.com/andygrunwald/go-jira"
+	"gabor-boros/sprint-update/pkg/cache"
+	"gabor-boros/sprint-update/pkg/calendar"
+	"gabor-boros/sprint-update/pkg/codehost"
+	"gabor-boros/sprint-update/pkg/email"
+	"gabor-boros/sprint-update/pkg/github"
+	"gabor-boros/sprint-update/pkg/hook"
+	"gabor-boros/sprint-update/pkg/i18n"
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/llm"
+	"gabor-boros/sprint-update/pkg/oauth"
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/review"
+	"gabor-boros/sprint-update/pkg/signing"
+	"gabor-boros/sprint-update/pkg/sprint"
+	"gabor-boros/sprint-update/pkg/tempo"
+	"gabor-boros/sprint-update/pkg/tracker"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 // program defines the executable name.
 const program = "sprint-update"
 
-// sprintUpdateTemplate is a Discourse Markdown template used for generating
-// the mid- and end of sprint updates.
-const sprintUpdateTemplate string = `
-**{{ .Title  }}**
-
-**Worked on**
-
-{{- range $status, $updates := .Issues }}
-
-[details="{{ $status }}"]
-{{- range $i, $item := $updates }}
-* [{{ $item.Key }}]({{ $item.URL }}) - {{ $item.Summary }}:
-{{- end }}
-[/details]
-{{- end }}
-
-**Spillovers**
-
-No spillovers in this sprint.
-
-**Kudos**
-
-* TODO
-
-**Time off**
-
-I did not plan any time off.
-`
-
-// jiraSearchQuery represents the JQL query used to search tickets of the
-// assignee within the given sprint.
-const jiraSearchQuery string = `assignee = currentUser() AND Sprint = "%s" AND status != Recurring`
+// defaultSummaryLength is the default number of characters the issue
+// summaries are truncated to.
+const defaultSummaryLength = 55
 
 var (
 	configFile string
+	noConfig   bool
 	version    string
 	commit     string
 	date       string
@@ -56,103 +49,250 @@ var (
 		Use:     program,
 		Short:   "Generate a sprint update.",
 		Long:    "Generate a sprint update in Discourse-compatible Markdown format.",
-		Example: fmt.Sprintf("%s --sprint SE.253 -e", program),
+		Example: fmt.Sprintf("%s generate --sprint SE.253 -e", program),
 		Run:     runRootCmd,
+		// The flags of the subcommands are bound before running them.
+		PersistentPreRun: bindCommandFlags,
 	}
 )
 
-// jiraIssue represents an item in the sprint update.
-type jiraIssue struct {
-	Key     string
-	Summary string
-	URL     string
-	Status  string
-}
-
-// newJiraIssue returns a new jiraIssue from the given jira.Issue.
-func newJiraIssue(serverURL string, issue *jira.Issue) jiraIssue {
-	summary := issue.Fields.Summary
-	if len(summary) > 55 {
-		summary = summary[:52] + "..."
-	}
-
-	return jiraIssue{
-		Key:     issue.Key,
-		Summary: summary,
-		URL:     fmt.Sprintf("%s/browse/%s", serverURL, issue.Key),
-		Status:  issue.Fields.Status.Name,
-	}
-}
-
-// jiraIssues is the grouping of multiple jiraIssue by their status.
-type jiraIssues map[string][]jiraIssue
-
-// newJiraIssues returns jiraIssues grouped by issue status.
-func newJiraIssues(serverURL string, issues []jira.Issue) jiraIssues {
-	groupedIssues := make(jiraIssues)
-
-	for _, issue := range issues {
-		transformedIssue := newJiraIssue(serverURL, &issue)
-		groupedIssues[issue.Fields.Status.Name] = append(groupedIssues[issue.Fields.Status.Name], transformedIssue)
-	}
-
-	return groupedIssues
-}
-
-// sprintUpdate is the actual sprint update used as the input for the sprint
-// update template.
-type sprintUpdate struct {
-	Title  string
-	Issues jiraIssues
-}
-
 func init() {
 	cobra.OnInitialize(initConfig)
 
-	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", fmt.Sprintf("config file (default is $HOME/.%s.yaml)", program))
-
-	rootCmd.Flags().StringP("sprint", "s", "", "sprint name (ex: SE.253)")
-	rootCmd.Flags().BoolP("end-of-sprint", "e", false, "indicate end of sprint update")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", fmt.Sprintf("config file (default is $HOME/.%[1]s.toml, or %[1]s/%[2]s in the config directory of the user)", program, configFileName))
+	rootCmd.PersistentFlags().BoolVar(&noConfig, "no-config", false, "do not read the config file, only the flags and the SPRINT_UPDATE_* environment variables")
+	rootCmd.PersistentFlags().StringP("history-dir", "", "", "directory the generated updates are archived in (default is $XDG_CONFIG_HOME/sprint-update/history)")
+	rootCmd.PersistentFlags().DurationP("timeout", "", 0, "maximum duration of the command, like 2m (default is no timeout)")
+	rootCmd.PersistentFlags().StringP("proxy", "", "", "HTTP, HTTPS, or SOCKS5 proxy URL used for every request (default is $HTTPS_PROXY or $HTTP_PROXY)")
+	rootCmd.PersistentFlags().StringP("ca-cert", "", "", "PEM file of CA certificates to trust besides the system certificates")
+	rootCmd.PersistentFlags().BoolP("insecure-skip-verify", "", false, "do not verify the TLS certificates of the servers, use as a last resort only")
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "log the queries sent to jira, the retried requests, and the template used to stderr")
+	rootCmd.PersistentFlags().BoolP("debug", "", false, "log the pagination progress and every HTTP request too, implies --verbose")
+	rootCmd.PersistentFlags().StringP("dump-responses", "", "", "file to write the raw jira responses to, for troubleshooting missing issues")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "do not print the progress messages and the errors to stderr, only exit with the exit code of the failure")
+	rootCmd.PersistentFlags().BoolP("json-errors", "", false, "print the errors to stderr as JSON objects with the message, the kind, and the exit code of the failure")
+
+	addGenerationFlags(rootCmd.Flags())
+	addDeliveryFlags(rootCmd.Flags())
+	rootCmd.Flags().BoolP("post", "", false, "post the update to discourse, same as --to discourse")
+	rootCmd.Flags().BoolP("version", "", false, "show command version")
+	checkErr(rootCmd.Flags().MarkDeprecated("post", "use the post command instead"))
+	checkErr(rootCmd.Flags().MarkDeprecated("version", "use the version command instead"))
+}
 
-	rootCmd.Flags().StringP("jira-url", "", "", "jira server URL")
-	rootCmd.Flags().StringP("jira-username", "", "", "jira user username")
-	rootCmd.Flags().StringP("jira-password", "", "", "jira user password")
+// addGenerationFlags adds the flags of generating the update to the flag set.
+func addGenerationFlags(flags *pflag.FlagSet) {
+	flags.StringArrayP("sprint", "s", []string{}, "sprint name, can be repeated for a consolidated update of multiple sprints (ex: SE.253)")
+	flags.StringP("from", "", "", "start date of the period covered by the update instead of sprints (ex: 2026-09-01)")
+	flags.StringP("until", "", "", "end date of the period covered by the update (default is today)")
+	flags.IntP("sprint-id", "", 0, "jira sprint ID, used instead of the sprint name")
+	flags.IntP("board", "b", 0, "jira board ID used to detect the active sprint when no sprint is set, and to look up the sprint by name")
+	flags.BoolP("end-of-sprint", "e", false, "indicate end of sprint update")
+	flags.BoolP("allow-empty", "", false, "render the update even if no issues are found, instead of failing")
+	flags.BoolP("worklog", "", false, "list the issues you logged time on within the sprint, instead of the issues assigned to you")
+	flags.BoolP("progress-notes", "", false, "render your last comment of the sprint under the issues")
+	flags.StringP("progress-marker", "", "", "render the last comment containing the marker under the issues instead (ex: #update)")
+	flags.BoolP("spillover-reasons", "", false, "render the last comment of the spillovers next to them as the reason they spilled over")
+	flags.StringP("spillover-reason-field", "", "", "ID of the field holding the reason the issues spilled over, used instead of the last comment (ex: customfield_10050)")
+	flags.BoolP("suggest-kudos", "", false, "suggest kudos for the colleagues who commented on the issues or resolved their blockers")
+	flags.BoolP("tempo", "", false, "summarize the hours logged in tempo timesheets within the sprint")
+	flags.StringP("tempo-url", "", tempo.DefaultURL, "tempo REST API URL")
+	flags.StringP("tempo-token", "", "", "tempo API token")
+	flags.StringP("calendar-url", "", "", "iCalendar feed or CalDAV calendar URL to look up the time off in")
+	flags.StringP("calendar-type", "", calendarICS, fmt.Sprintf("calendar type (%s)", strings.Join(calendarTypes, ", ")))
+	flags.StringP("calendar-username", "", "", "CalDAV username")
+	flags.StringP("calendar-password", "", "", "CalDAV password")
+	flags.StringSliceP("tempo-leave-issue-ids", "", []string{}, "IDs of the Jira issues the leave is planned on in tempo planner (ex: 10042)")
+	flags.StringP("bamboohr-company", "", "", "company subdomain of bamboohr (ex: acme)")
+	flags.StringP("bamboohr-employee-id", "", "", "employee ID in bamboohr")
+	flags.StringP("bamboohr-token", "", "", "bamboohr API key")
+	flags.StringSliceP("time-off-keywords", "", []string{}, fmt.Sprintf("words of the calendar events marking time off (default %q)", strings.Join(calendar.DefaultTimeOffKeywords, ",")))
+	flags.StringP("assignee", "", "", "account ID or username of the teammate to generate the update for")
+	flags.StringSliceP("assignees", "a", []string{}, "team members to generate a team update for (ex: alice,bob,carol)")
+	flags.StringP("format", "f", render.DefaultFormat, fmt.Sprintf("output format (%s)", strings.Join(render.Formats(), ", ")))
+	flags.StringP("template", "t", "", "go template file used to render the update")
+	flags.StringP("mid-sprint-template", "", "", "go template file used to render the mid-sprint updates, overriding --template")
+	flags.StringP("end-of-sprint-template", "", "", "go template file used to render the end of sprint updates, overriding --template")
+	flags.StringP("stylesheet", "", "", "CSS file embedded in the styled-html documents instead of the default theme")
+	flags.StringP("templates-dir", "", "", "directory of the partial templates replacing the templates of the same name, like issueLine.tmpl")
+	flags.StringP("lang", "", i18n.DefaultLanguage, fmt.Sprintf("language of the headings of the built-in templates (%s)", strings.Join(i18n.Languages(), ", ")))
+	flags.StringP("title-template", "", "", "go template of the update title (ex: \"Sprint {{ .Sprint }} Update ({{ .Type }})\")")
+	flags.StringP("jql", "", "", "JQL query overriding the default sprint query")
+	flags.StringArrayP("jql-extra", "", []string{}, "JQL clause restricting the query, can be repeated (ex: \"labels != chore\")")
+	flags.StringSliceP("blocked-statuses", "", []string{}, "issue statuses considered as blocked (ex: Blocked,On Hold)")
+	flags.StringSliceP("annotations", "", []string{}, fmt.Sprintf("details rendered on the issue lines (%s)", strings.Join(report.Annotations(), ", ")))
+	flags.StringSliceP("sections", "", []string{}, fmt.Sprintf("sections of the update in the order they are rendered (%s)", strings.Join(report.Sections(), ", ")))
+	flags.BoolP("themes", "", false, "summarize the epics of the issues with their progress at the top of the update")
+	flags.BoolP("summary", "", false, "write a narrative summary at the top of the update using a language model")
+	flags.StringP("llm-url", "", llm.DefaultBaseURL, "base URL of the OpenAI-compatible API writing the summary (ex: http://localhost:11434/v1)")
+	flags.StringP("llm-model", "", "", "language model writing the summary (ex: gpt-4o-mini)")
+	flags.StringP("llm-api-key", "", "", "API key of the language model API")
+	flags.StringSliceP("expanded-groups", "", []string{}, "status groups expanded by default (ex: \"In Progress\")")
+	flags.IntP("details-threshold", "", 0, "number of issues up to which the status groups are listed without collapsible blocks")
+	flags.StringSliceP("plain-formats", "", []string{}, "formats listing the status groups without collapsible blocks (ex: markdown)")
+	flags.StringToStringP("max-items", "", map[string]string{}, "maximum number of issues listed in the groups, folding the rest into a link, * sets the other groups (ex: \"Done=5,*=10\")")
+	flags.StringP("fold-by", "", string(report.FoldByOrder), fmt.Sprintf("what the issues listed in the groups having more issues than their maximum are chosen by (%s)", strings.Join(report.FoldBys(), ", ")))
+	flags.StringToStringP("section-titles", "", map[string]string{}, "titles replacing the default headings of the sections (ex: \"kudos=Shoutouts\")")
+	flags.BoolP("redact", "", false, "strip the internal issue keys, URLs, and pull requests for external stakeholders")
+	flags.StringSliceP("blocked-labels", "", []string{}, "issue labels marking the issues as blocked (ex: needs-help)")
+	flags.StringSliceP("exclude-status", "", sprint.DefaultExcludeStatuses, "issue statuses left out of the update")
+	flags.StringSliceP("exclude-label", "", []string{}, "issue labels left out of the update (ex: chore)")
+	flags.StringSliceP("include-type", "", []string{}, "issue types the update is restricted to (ex: Story,Bug)")
+	flags.StringSliceP("projects", "", []string{}, "keys of the projects the update is restricted to (ex: SE,OPS)")
+	flags.BoolP("split-by-project", "", false, "split the worked on section by project, with the totals of every project")
+	flags.StringSliceP("status-order", "", []string{}, "order of the statuses or status groups (ex: \"In Progress,Done\")")
+	flags.StringP("subtasks", "", string(report.SubtasksFlat), fmt.Sprintf("how subtasks are listed (%s)", strings.Join(report.SubtaskModes(), ", ")))
+	flags.StringP("group-by", "", string(report.GroupByStatus), fmt.Sprintf("what issues are grouped by (%s)", strings.Join(report.GroupBys(), ", ")))
+	flags.StringSliceP("issue-fields", "", jira.OptionalFields, "optional issue fields requested from jira, the fields used by the enabled features are requested regardless")
+	flags.StringP("sort-by", "", string(report.SortByRank), fmt.Sprintf("what issues are sorted by within their groups (%s)", strings.Join(report.SortBys(), ", ")))
+	flags.IntP("summary-length", "", defaultSummaryLength, "number of characters the issue summaries are truncated to, 0 disables truncation")
+	flags.StringP("story-points-field", "", "", "ID of the story points field used to render the totals (ex: customfield_10016)")
+	flags.StringP("sprint-field", "", "", "ID of the sprint field of the issues, used instead of discovering it (ex: customfield_10020)")
+	flags.StringToStringP("status-emojis", "", map[string]string{}, "emojis of the statuses or status groups in the decorated format (ex: \"Done=🎉,In Progress=⏳\")")
+	flags.StringSliceP("hidden-statuses", "", []string{}, "statuses or status groups left out of the update (ex: Backlog)")
+	flags.StringP("state-file", "", "", "file keeping the issues left unresolved at the end of the sprint (default is $XDG_CONFIG_HOME/sprint-update/state.json)")
+	flags.BoolP("diff", "", false, "annotate the issues that are new, moved, or done since the previous update of the sprint")
+
+	flags.StringP("tracker", "", trackerJira, fmt.Sprintf("issue tracker the issues are fetched from (%s, %s, %s, %s)", trackerJira, trackerLinear, trackerAzure, trackerGitHub))
+	flags.StringP("linear-token", "", "", "linear personal API key")
+	flags.StringP("linear-team", "", "", "key of the linear team whose cycles are the sprints (ex: ENG)")
+	flags.StringP("azure-url", "", "", "azure devops organization URL (ex: https://dev.azure.com/contoso)")
+	flags.StringP("azure-project", "", "", "azure devops project")
+	flags.StringP("azure-team", "", "", "azure boards team whose iterations are the sprints, defaults to the team of the project")
+	flags.StringP("azure-token", "", "", "azure devops personal access token")
+	flags.StringP("github-milestone-repo", "", "", "github repository whose milestones are the sprints (ex: owner/repo)")
+	flags.StringP("github-project", "", "", "github project whose iterations are the sprints, given as owner/number (ex: octo-org/5)")
+	flags.StringP("github-iteration-field", "", tracker.DefaultGitHubIterationField, "iteration field of the github project")
+	flags.StringP("github-status-field", "", tracker.DefaultGitHubStatusField, "field of the github project the issues are grouped by")
+
+	flags.StringP("jira-url", "", "", "jira server URL")
+	flags.StringP("jira-username", "", "", "jira user username")
+	flags.StringP("jira-password", "", "", "jira user password")
+	flags.StringP("jira-token", "", "", "jira cloud API token or personal access token")
+	flags.BoolP("jira-password-stdin", "", false, "read the jira password, or the token of the token and pat auth types, from stdin")
+	flags.IntP("workers", "", jira.DefaultWorkers, "number of jira result pages fetched concurrently")
+	flags.IntP("max-attempts", "", jira.DefaultMaxAttempts, "number of attempts when jira rate limits the requests or is unavailable")
+	flags.DurationP("retry-timeout", "", jira.DefaultRetryTimeout, "total time spent on a jira request, including retries")
+	flags.DurationP("cache-ttl", "", cache.DefaultTTL, "time the jira metadata, like the field IDs and the sprints, is cached for, 0 disables caching")
+	flags.BoolP("refresh-cache", "", false, "fetch the cached jira metadata again")
+	flags.StringP("auth-type", "", string(jira.AuthBasic), fmt.Sprintf("jira authentication method (%s, %s, %s, %s)", jira.AuthBasic, jira.AuthToken, jira.AuthPAT, jira.AuthOAuth))
+
+	flags.StringP("record", "", "", "file to save the raw jira responses to")
+	flags.StringP("replay", "", "", "file of the jira responses saved by --record to generate the update from, without contacting jira")
+	flags.BoolP("interactive", "i", false, "review the issues before rendering the update")
+	flags.BoolP("dry-run", "", false, "render the update without delivering it or saving the state and history")
+	flags.BoolP("sample", "", false, "render built-in sample issues instead of fetching them from jira, requires --dry-run or --preview")
+	flags.BoolP("preview", "", false, "show the update in the terminal with colors for review, instead of writing and delivering it")
+	flags.BoolP("clipboard", "", false, "copy the rendered update to the clipboard")
+	flags.BoolP("edit", "", false, "edit the rendered update in $EDITOR before writing and delivering it")
+	flags.StringP("output", "o", stdoutPath, "file to write the update to, can be a go template (ex: \"updates/{{ .Sprint }}-{{ .Type }}.md\")")
+	flags.BoolP("provenance", "", false, "end the update with a footer of the tool version, the time, the sprint ID, and the hash of the queries")
+	flags.StringP("sign", "", "", fmt.Sprintf("sign the update, writing the detached signature next to the output file and into the history (%s, %s)", signing.ToolGPG, signing.ToolMinisign))
+	flags.StringP("signing-key", "", "", "GPG key ID or path of the minisign secret key the update is signed with, defaults to the default key of the tool")
+
+	flags.StringP("github-url", "", github.DefaultBaseURL, "github API URL")
+	flags.StringP("github-token", "", "", "github personal access token used to list the pull requests of the sprint")
+	flags.StringSliceP("github-repos", "", []string{}, "github repositories or organizations to list pull requests from (ex: owner/repo,org)")
+	flags.StringP("gitlab-url", "", codehost.DefaultGitLabURL, "gitlab URL")
+	flags.StringP("gitlab-token", "", "", "gitlab personal access token used to list the merge requests of the sprint")
+	flags.StringSliceP("gitlab-projects", "", []string{}, "gitlab projects or groups to list merge requests from (ex: group/project,group)")
+	flags.StringP("bitbucket-url", "", codehost.DefaultBitbucketURL, "bitbucket API URL")
+	flags.StringP("bitbucket-username", "", "", "bitbucket username")
+	flags.StringP("bitbucket-app-password", "", "", "bitbucket app password used to list the pull requests of the sprint")
+	flags.StringSliceP("bitbucket-repos", "", []string{}, "bitbucket repositories or workspaces to list pull requests from (ex: workspace/repo,workspace)")
+	flags.StringSliceP("git-repos", "", []string{}, "local clones or remote URLs of the git repositories to list the commits of the sprint from (ex: ../infra,git@github.com:org/repo.git)")
+	flags.StringSliceP("git-authors", "", []string{}, "names or email addresses of the commit authors, defaults to the git user.email of each repository")
+
+	flags.StringArrayP("hooks-pre-fetch", "", []string{}, "command run before fetching the issues, can be repeated")
+	flags.StringArrayP("hooks-post-fetch", "", []string{}, "command receiving the issues as JSON on stdin and printing the modified issues, can be repeated")
+	flags.StringArrayP("hooks-post-render", "", []string{}, "command receiving the rendered update on stdin and printing the modified update, can be repeated")
+}
 
-	rootCmd.Flags().BoolP("version", "", false, "show command version")
+// addDeliveryFlags adds the flags of delivering the update to the flag set.
+func addDeliveryFlags(flags *pflag.FlagSet) {
+	flags.StringSliceP("to", "", []string{}, fmt.Sprintf("targets to deliver the update to (%s)", strings.Join(availableTargets, ", ")))
+	flags.StringP("discourse-url", "", "", "discourse forum URL")
+	flags.StringP("discourse-api-key", "", "", "discourse API key")
+	flags.StringP("discourse-username", "", "", "discourse username to post as")
+	flags.IntP("discourse-topic", "", 0, "discourse topic ID to reply to")
+	flags.IntP("discourse-category", "", 0, "discourse category ID to create a new topic in")
+	flags.BoolP("draft", "", false, "save the discourse post as a draft to review in the composer instead of publishing it")
+	flags.BoolP("amend", "", false, "amend the discourse post of the previous update of the sprint instead of creating a new post")
+	flags.StringP("amend-mode", "", amendEdit, fmt.Sprintf("how the discourse post is amended (%s, %s)", amendEdit, amendReply))
+	flags.StringP("slack-webhook-url", "", "", "slack incoming webhook URL")
+	flags.StringP("slack-token", "", "", "slack bot token, used when no webhook URL is set")
+	flags.StringP("slack-channel", "", "", "slack channel the bot posts to")
+	flags.StringP("confluence-url", "", "", "confluence URL (ex: https://example.atlassian.net/wiki)")
+	flags.StringP("confluence-username", "", "", "confluence username, defaults to the jira username")
+	flags.StringP("confluence-token", "", "", "confluence API token or password, defaults to the jira token")
+	flags.StringP("confluence-space", "", "", "confluence space key to publish the update in")
+	flags.StringP("confluence-parent", "", "", "confluence page ID to create the update pages under")
+	flags.StringP("confluence-archive-page", "", "", "title of the confluence page end of sprint updates are appended to")
+	flags.StringP("email-host", "", "", "SMTP server host")
+	flags.IntP("email-port", "", 587, "SMTP server port")
+	flags.StringP("email-tls", "", string(email.TLSStartTLS), fmt.Sprintf("SMTP connection security (%s, %s, %s)", email.TLSNone, email.TLSStartTLS, email.TLSImplicit))
+	flags.StringP("email-username", "", "", "SMTP username")
+	flags.StringP("email-password", "", "", "SMTP password")
+	flags.StringP("email-from", "", "", "email sender address")
+	flags.StringSliceP("email-to", "", []string{}, "email recipient addresses")
+	flags.StringP("email-subject", "", "", "email subject template, defaults to the title of the update")
+	flags.StringP("matrix-url", "", "", "matrix homeserver URL")
+	flags.StringP("matrix-token", "", "", "matrix access token of the user sending the update")
+	flags.StringP("matrix-room", "", "", "matrix room ID to send the update to")
+	flags.StringP("mattermost-webhook-url", "", "", "mattermost incoming webhook URL")
+	flags.StringP("mattermost-channel", "", "", "mattermost channel overriding the default of the webhook")
+	flags.StringP("mattermost-username", "", "", "mattermost username overriding the default of the webhook")
+	flags.StringP("teams-webhook-url", "", "", "microsoft teams incoming webhook URL")
+	flags.StringP("webhook-url", "", "", "URL the structured update is posted to by the webhook target")
+	flags.StringP("webhook-secret", "", "", "secret key of the HMAC-SHA256 signature of the webhook requests")
+	flags.StringP("notion-token", "", "", "notion internal integration token")
+	flags.StringP("notion-database", "", "", "notion database ID to create the update pages in")
+	flags.StringP("notion-author", "", "", "author of the notion pages, defaults to the assignee of the update")
+	flags.StringP("notion-sprint-property", "", "Sprint", "notion database property set to the sprint name")
+	flags.StringP("notion-author-property", "", "Author", "notion database property set to the author")
+	flags.StringP("notion-type-property", "", "Type", "notion database property set to the type of the update")
+	flags.StringP("google-credentials-file", "", "", "google service account key or authorized user credentials file, defaults to $GOOGLE_APPLICATION_CREDENTIALS")
+	flags.StringP("google-folder", "", "", "google drive folder ID to create the update documents in")
+	flags.StringP("google-document", "", "", "name of the google docs document updates are appended to, instead of a document per update")
 }
 
 // initConfig initializes Cobra and Viper configuration.
 func initConfig() {
-	envPrefix := strings.ToUpper(program)
+	checkErr(applyEnvironment(rootCmd.PersistentFlags()))
+	checkErr(applyEnvironment(rootCmd.Flags()))
 
 	if configFile != "" {
-		viper.SetConfigName(configFile)
+		viper.SetConfigFile(configFile)
 	} else {
-		homeDir, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		configDir, err := os.UserConfigDir()
-		cobra.CheckErr(err)
+		path, err := findConfigFile()
+		checkErr(err)
 
-		viper.AddConfigPath(homeDir)
-		viper.AddConfigPath(configDir)
-		viper.SetConfigName("." + program)
-		viper.SetConfigType("toml")
+		if path != "" {
+			viper.SetConfigFile(path)
+		}
 	}
 
+	viper.SetConfigType("toml")
+
 	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(envKeyReplacer)
 	viper.AutomaticEnv()
 
-	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			cobra.CheckErr(err)
+	// Bind flags to config value
+	checkErr(viper.BindPFlags(rootCmd.PersistentFlags()))
+	checkErr(viper.BindPFlags(rootCmd.Flags()))
+
+	if !noConfig {
+		if err := viper.ReadInConfig(); err != nil {
+			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+				checkErr(configError(err))
+			}
+		} else if !isCompleting() && !isConfigGet() {
+			printStatus("Using config file:", viper.ConfigFileUsed())
 		}
-	} else {
-		fmt.Println("Using config file:", viper.ConfigFileUsed(), configFile)
 	}
 
-	// Bind flags to config value
-	cobra.CheckErr(viper.BindPFlags(rootCmd.Flags()))
+	checkErr(applyProfile())
 }
 
 // printVersion prints the version number to stdout.
@@ -164,94 +304,314 @@ func printVersion() {
 	}
 }
 
-// newJiraClient returns creates a transport and returns a new jira.Client.
-func newJiraClient(serverURL string, username string, password string) (*jira.Client, error) {
-	transport := jira.BasicAuthTransport{
-		Username: username,
-		Password: password,
+// runRootCmd is the root command run at command execution by Cobra. Running
+// the root command generates and delivers the update, like the generate and
+// post commands, which is deprecated.
+func runRootCmd(cmd *cobra.Command, _ []string) {
+	if viper.GetBool("version") {
+		printVersion()
+		os.Exit(0)
 	}
 
-	return jira.NewClient(transport.Client(), serverURL)
+	fmt.Fprintf(os.Stderr, "Running %[1]s without a command is deprecated and will be removed in the next release, use %[1]s generate or %[1]s post instead\n", program)
+
+	targets, err := deliveryTargets()
+	checkErr(err)
+
+	generateUpdate(cmd, targets)
 }
 
-// fetchIssues fetches issues from Jira returned as a result of the given JQL.
-// The maximum number of issues returned by a search is limited to 1000 entries;
-// to fetch every issue regardless the limit, we must do a basic pagination.
-//
-// Note: It is not realistic that anyone would hit the 1000 items limit, but be
-// on the safe side.
-func fetchIssues(client *jira.Client, jql string) ([]jira.Issue, error) {
-	var issues []jira.Issue
-	startAt := 0
-
-	for {
-		searchOpts := &jira.SearchOptions{
-			StartAt:    startAt,
-			MaxResults: 1000,
-		}
+// bindCommandFlags binds the flags of the executed command to the
+// configuration values, since only the flags of the root command are bound
+// when initializing the configuration. The flags not set on the command line
+// are set from the environment first.
+func bindCommandFlags(cmd *cobra.Command, _ []string) {
+	if cmd.HasParent() {
+		checkErr(applyEnvironment(cmd.LocalFlags()))
+		checkErr(viper.BindPFlags(cmd.LocalFlags()))
+	}
+}
 
-		chunk, resp, err := client.Issue.Search(jql, searchOpts)
-		if err != nil {
-			return nil, err
-		}
+// newConfig returns the sprint update configuration read from the flags,
+// the configuration file, the environment, and the keyring.
+func newConfig() sprint.Config {
+	config := sprint.Config{
+		ServerURL:               viper.GetString("jira-url"),
+		AuthType:                jira.AuthType(viper.GetString("auth-type")),
+		Username:                viper.GetString("jira-username"),
+		Password:                secret("jira-password"),
+		Token:                   secret("jira-token"),
+		SprintID:                viper.GetInt("sprint-id"),
+		Board:                   viper.GetInt("board"),
+		EndOfSprint:             viper.GetBool("end-of-sprint"),
+		Assignee:                viper.GetString("assignee"),
+		Assignees:               viper.GetStringSlice("assignees"),
+		BlockedStatuses:         viper.GetStringSlice("blocked-statuses"),
+		BlockedLabels:           viper.GetStringSlice("blocked-labels"),
+		ExcludeStatuses:         viper.GetStringSlice("exclude-status"),
+		ExcludeLabels:           viper.GetStringSlice("exclude-label"),
+		IncludeTypes:            viper.GetStringSlice("include-type"),
+		Projects:                viper.GetStringSlice("projects"),
+		SplitByProject:          viper.GetBool("split-by-project"),
+		ExpandedGroups:          viper.GetStringSlice("expanded-groups"),
+		DetailsThreshold:        viper.GetInt("details-threshold"),
+		PlainFormats:            viper.GetStringSlice("plain-formats"),
+		FoldBy:                  report.FoldBy(viper.GetString("fold-by")),
+		StatusOrder:             viper.GetStringSlice("status-order"),
+		HiddenStatuses:          viper.GetStringSlice("hidden-statuses"),
+		StoryPointsField:        viper.GetString("story-points-field"),
+		SprintField:             viper.GetString("sprint-field"),
+		Worklog:                 viper.GetBool("worklog"),
+		SuggestKudos:            viper.GetBool("suggest-kudos"),
+		ProgressNotes:           viper.GetBool("progress-notes") || viper.GetString("progress-marker") != "",
+		ProgressMarker:          viper.GetString("progress-marker"),
+		SpilloverReasons:        viper.GetBool("spillover-reasons") || viper.GetString("spillover-reason-field") != "",
+		SpilloverReasonField:    viper.GetString("spillover-reason-field"),
+		Diff:                    viper.GetBool("diff"),
+		Provenance:              provenanceTool(),
+		TimeOffKeywords:         viper.GetStringSlice("time-off-keywords"),
+		SummaryLength:           viper.GetInt("summary-length"),
+		Subtasks:                report.SubtaskMode(viper.GetString("subtasks")),
+		GroupBy:                 report.GroupBy(viper.GetString("group-by")),
+		SortBy:                  report.SortBy(viper.GetString("sort-by")),
+		Annotations:             annotations(),
+		Sections:                sections(),
+		Language:                viper.GetString("lang"),
+		Redact:                  viper.GetBool("redact"),
+		RedactAliases:           viper.GetStringMapString("redact-aliases"),
+		Workers:                 viper.GetInt("workers"),
+		MaxAttempts:             viper.GetInt("max-attempts"),
+		RetryTimeout:            viper.GetDuration("retry-timeout"),
+		JQL:                     viper.GetString("jql"),
+		JQLExtra:                viper.GetStringSlice("jql-extra"),
+		TitleTemplate:           viper.GetString("title-template"),
+		Format:                  viper.GetString("format"),
+		TemplateFile:            viper.GetString("template"),
+		MidSprintTemplateFile:   viper.GetString("mid-sprint-template"),
+		EndOfSprintTemplateFile: viper.GetString("end-of-sprint-template"),
+		StylesheetFile:          viper.GetString("stylesheet"),
+		TemplatesDir:            viper.GetString("templates-dir"),
+		CodeHosts:               newCodeHosts(),
+		GitRepositories:         viper.GetStringSlice("git-repos"),
+		GitAuthors:              viper.GetStringSlice("git-authors"),
+		Transport:               jiraTransport(),
+		Hooks: hook.Hooks{
+			PreFetch:   viper.GetStringSlice("hooks-pre-fetch"),
+			PostFetch:  viper.GetStringSlice("hooks-post-fetch"),
+			PostRender: viper.GetStringSlice("hooks-post-render"),
+		},
+	}
 
-		total := resp.Total
+	if names := sprintNames(); len(names) > 0 {
+		config.Sprint, config.Sprints = names[0], names[1:]
+	}
 
-		if total == 0 {
-			break
-		}
+	// Every optional field is requested unless the fields are configured,
+	// even if they are configured as an empty list.
+	if viper.IsSet("issue-fields") {
+		config.IssueFields = append([]string{}, viper.GetStringSlice("issue-fields")...)
+	}
 
-		// If no items were set yet, resize the slice since we know the number
-		// of total issues at this point.
-		if issues == nil {
-			issues = make([]jira.Issue, 0, total)
-		}
+	from, err := periodDate("from", false)
+	checkErr(err)
+	config.From = from
+
+	until, err := periodDate("until", true)
+	checkErr(err)
+	config.Until = until
+
+	stateFile, err := stateFilePath()
+	checkErr(err)
+	config.StateFile = stateFile
+
+	historyDir, err := historyDirPath()
+	checkErr(err)
+	config.HistoryDir = historyDir
 
-		issues = append(issues, chunk...)
-		startAt = resp.StartAt + len(chunk)
+	gitCacheDir, err := gitCacheDirPath()
+	checkErr(err)
+	config.GitCacheDir = gitCacheDir
 
-		if startAt >= total {
-			break
+	groups, err := statusGroups()
+	checkErr(err)
+	config.StatusGroups = groups
+	config.StatusEmojis = viper.GetStringMapString("status-emojis")
+	config.SectionTitles = viper.GetStringMapString("section-titles")
+
+	limits, err := maxItems()
+	checkErr(err)
+	config.MaxItems = limits
+
+	cal, err := newCalendar()
+	checkErr(err)
+	config.Calendar = cal
+	config.Tempo = newTempo()
+	config.LanguageModel = newLanguageModel(config.Sections)
+
+	config.Tracker, err = newTracker()
+	checkErr(err)
+
+	metadataCache, err := newCache()
+	checkErr(err)
+	config.Cache = metadataCache
+
+	if config.AuthType == jira.AuthOAuth {
+		store, err := newTokenStore()
+		checkErr(err)
+
+		config.OAuth = oauth.NewTokenSource(newOAuthConfig(), store)
+	}
+
+	return config
+}
+
+// errInvalidMaxItems is returned when the maximum number of issues of a group
+// is not a non-negative number.
+var errInvalidMaxItems = errors.New("invalid maximum number of issues")
+
+// maxItems returns the configured maximum numbers of issues of the groups.
+func maxItems() (map[string]int, error) {
+	limits := make(map[string]int)
+	for group, value := range viper.GetStringMapString("max-items") {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return nil, configError(fmt.Errorf("%w: %s = %q", errInvalidMaxItems, group, value))
 		}
+
+		limits[group] = limit
 	}
 
-	return issues, nil
+	return limits, nil
 }
 
-// runRootCmd is the root command run at command execution by Cobra.
-func runRootCmd(_ *cobra.Command, _ []string) {
-	var err error
+// annotations returns the configured annotations of the issue lines.
+func annotations() []report.Annotation {
+	var configured []report.Annotation
+	for _, annotation := range viper.GetStringSlice("annotations") {
+		configured = append(configured, report.Annotation(strings.ToLower(annotation)))
+	}
 
-	if viper.GetBool("version") {
-		printVersion()
-		os.Exit(0)
+	return configured
+}
+
+// sections returns the configured sections of the update body. With
+// --summary and --themes, the summary and the themes sections are rendered
+// first.
+func sections() []report.Section {
+	var configured []report.Section
+	for _, section := range viper.GetStringSlice("sections") {
+		configured = append(configured, report.Section(strings.ToLower(section)))
+	}
+
+	configured = prependSection(configured, report.SectionThemes, viper.GetBool("themes"))
+	return prependSection(configured, report.SectionSummary, viper.GetBool("summary"))
+}
+
+// prependSection renders the section first if it is enabled and not listed
+// in the sections already.
+func prependSection(sections []report.Section, section report.Section, enabled bool) []report.Section {
+	if !enabled || report.HasSection(sections, section) {
+		return sections
+	}
+
+	if len(sections) == 0 {
+		sections = report.DefaultSections
+	}
+
+	return append([]report.Section{section}, sections...)
+}
+
+// statusGroup is a display group of statuses in the configuration file.
+type statusGroup struct {
+	Name     string
+	Statuses []string
+}
+
+// statusGroups returns the configured display groups of statuses. The groups
+// are configured as a list rather than a table, since the configuration keys
+// are case-insensitive, hence the group names would be lowercased.
+func statusGroups() (map[string][]string, error) {
+	var configured []statusGroup
+	if err := viper.UnmarshalKey("status-groups", &configured); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]string, len(configured))
+	for _, group := range configured {
+		groups[group.Name] = append(groups[group.Name], group.Statuses...)
 	}
 
-	jiraServerURL := viper.GetString("jira-url")
-	jiraUsername := viper.GetString("jira-username")
-	jiraPassword := viper.GetString("jira-password")
+	return groups, nil
+}
+
+// stateFilePath returns the path of the state file keeping the issues left
+// unresolved at the end of the sprint.
+func stateFilePath() (string, error) {
+	if path := viper.GetString("state-file"); path != "" {
+		return expandPath(path), nil
+	}
 
-	jiraClient, err := newJiraClient(jiraServerURL, jiraUsername, jiraPassword)
-	cobra.CheckErr(err)
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
 
-	sprintName := viper.GetString("sprint")
-	rawIssues, err := fetchIssues(jiraClient, fmt.Sprintf(jiraSearchQuery, sprintName))
-	cobra.CheckErr(err)
+	return filepath.Join(configDir, program, profileFile("state.json")), nil
+}
 
-	issues := newJiraIssues(jiraServerURL, rawIssues)
+// historyDirPath returns the path of the directory the generated updates are
+// archived in.
+func historyDirPath() (string, error) {
+	if path := viper.GetString("history-dir"); path != "" {
+		return expandPath(path), nil
+	}
 
-	sprintUpdateType := "Mid-sprint"
-	if viper.GetBool("end-of-sprint") {
-		sprintUpdateType = "End of sprint"
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
 	}
 
-	descriptionTemplate := template.Must(template.New("description").Parse(sprintUpdateTemplate))
-	err = descriptionTemplate.Execute(os.Stdout, &sprintUpdate{
-		Title:  fmt.Sprintf("%s - %s", sprintName, sprintUpdateType),
-		Issues: issues,
-	})
+	return filepath.Join(configDir, program, profileFile("history")), nil
+}
 
-	cobra.CheckErr(err)
+// gitCacheDirPath returns the path of the directory the remote git
+// repositories are mirrored into.
+func gitCacheDirPath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, program, "git"), nil
+}
+
+// buildUpdate builds the sprint update. In interactive mode, the update is
+// reviewed before rendering.
+func buildUpdate(ctx context.Context, config sprint.Config) (*report.Update, error) {
+	build := sprint.BuildUpdate
+	if viper.GetBool("sample") {
+		build = func(_ context.Context, config sprint.Config) (*report.Update, error) {
+			return sprint.SampleUpdate(config)
+		}
+	}
+
+	update, err := build(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, warning := range update.Warnings {
+		printStatus("Warning:", warning)
+	}
+
+	if viper.GetBool("interactive") {
+		if err = review.Run(os.Stdin, os.Stderr, update); err != nil {
+			return nil, err
+		}
+	}
+
+	config.RedactUpdate(update)
+	return update, nil
 }
 
 func Execute(buildVersion string, buildCommit string, buildDate string) {
@@ -259,5 +619,14 @@ func Execute(buildVersion string, buildCommit string, buildDate string) {
 	commit = buildCommit
 	date = buildDate
 
-	cobra.CheckErr(rootCmd.Execute())
+	ctx, stop := signalContext()
+	defer stop()
+
+	// The errors are printed by checkErr, respecting --quiet and
+	// --json-errors. The errors returned by Cobra are invalid flags or
+	// arguments, as the commands handle their own errors.
+	rootCmd.SilenceErrors = true
+	rootCmd.SetFlagErrorFunc(silenceUsage)
+	registerCompletions()
+	checkErr(configError(rootCmd.ExecuteContext(ctx)))
 }
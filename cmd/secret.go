@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	// errEmptyStdinSecret is returned when --jira-password-stdin is set, but
+	// the standard input is empty.
+	errEmptyStdinSecret = errors.New("no password read from stdin")
+	// errStdinSecretInteractive is returned when the password is read from the
+	// standard input, which is needed for the interactive review too.
+	errStdinSecretInteractive = errors.New("--jira-password-stdin cannot be used together with --interactive")
+	// errStdinSecretOAuth is returned when the password is read from the
+	// standard input, but the authentication method uses no password.
+	errStdinSecretOAuth = errors.New("--jira-password-stdin cannot be used with the oauth auth type")
+)
+
+// readSecret prompts for a secret and reads it from the reader. If the
+// standard input is a terminal, the typed characters are not echoed.
+func readSecret(in *bufio.Reader, out io.Writer, prompt string) (string, error) {
+	fmt.Fprintf(out, "%s: ", prompt)
+
+	if isTerminal(os.Stdin.Fd()) {
+		if restore, err := disableEcho(os.Stdin.Fd()); err == nil {
+			defer func() {
+				restore()
+				fmt.Fprintln(out)
+			}()
+		}
+	}
+
+	value, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || value == "") {
+		return "", err
+	}
+
+	return strings.TrimRight(value, "\r\n"), nil
+}
+
+// jiraSecret returns the Jira credential required by the authentication
+// method besides the username, and its description. For jira.AuthOAuth,
+// nil is returned.
+func jiraSecret(config *sprint.Config) (*string, string) {
+	auth := jira.Auth{Type: config.AuthType}
+
+	switch auth.EffectiveType() {
+	case jira.AuthToken:
+		return &config.Token, "Jira API token"
+	case jira.AuthPAT:
+		return &config.Token, "Jira personal access token"
+	case jira.AuthOAuth:
+		return nil, ""
+	default:
+		return &config.Password, "Jira password"
+	}
+}
+
+// readJiraSecret fills in the missing Jira password or token. When
+// --jira-password-stdin is set, the first line of the standard input is read,
+// so secrets can be piped from password managers in scripts. Otherwise, if
+// the standard input is a terminal, the secret is prompted for.
+func readJiraSecret(config *sprint.Config) error {
+	secret, description := jiraSecret(config)
+
+	if viper.GetBool("jira-password-stdin") {
+		if secret == nil {
+			return errStdinSecretOAuth
+		}
+
+		if viper.GetBool("interactive") {
+			return errStdinSecretInteractive
+		}
+
+		value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && (err != io.EOF || value == "") {
+			return fmt.Errorf("%w: %v", errEmptyStdinSecret, err)
+		}
+
+		if *secret = strings.TrimRight(value, "\r\n"); *secret == "" {
+			return errEmptyStdinSecret
+		}
+
+		return nil
+	}
+
+	if secret == nil || *secret != "" || !isTerminal(os.Stdin.Fd()) {
+		return nil
+	}
+
+	// Basic and token authentication need the username too, without which
+	// the requests are anonymous, or the validation fails respectively.
+	auth := jira.Auth{Type: config.AuthType}
+	if auth.EffectiveType() != jira.AuthPAT && config.Username == "" {
+		return nil
+	}
+
+	value, err := readSecret(bufio.NewReader(os.Stdin), os.Stderr, fmt.Sprintf("%s for %s", description, config.ServerURL))
+	if err != nil {
+		return err
+	}
+
+	*secret = value
+	return nil
+}
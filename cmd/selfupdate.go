@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/selfupdate"
+
+	"github.com/spf13/cobra"
+)
+
+// errManagedInstall is returned when the binary is installed by a package
+// manager, which should be used for updating the binary instead.
+var errManagedInstall = errors.New("the binary is installed by a package manager")
+
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update the binary to the latest release.",
+	Long:  "Check the latest GitHub release, download the archive of the platform, verify its checksum and the signature of the checksums by the release key embedded in the binary, and replace the running binary with the one of the release.",
+	Args:  cobra.NoArgs,
+	Run:   runSelfUpdateCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+
+	flags := selfUpdateCmd.Flags()
+	flags.BoolP("check", "", false, "only check whether a newer release is available")
+	flags.BoolP("force", "", false, "update dirty builds, and reinstall the latest release even if it is installed")
+	flags.BoolP("verify-signature", "", true, "verify the GPG signature of the checksums by the release key embedded in the binary, which requires gpg")
+}
+
+// runSelfUpdateCmd replaces the running binary with the one of the latest
+// release, unless it is installed already.
+func runSelfUpdateCmd(cmd *cobra.Command, _ []string) {
+	check, err := cmd.Flags().GetBool("check")
+	checkErr(err)
+
+	force, err := cmd.Flags().GetBool("force")
+	checkErr(err)
+
+	verifySignature, err := cmd.Flags().GetBool("verify-signature")
+	checkErr(err)
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	checkErr(selfUpdate(ctx, check, force, verifySignature))
+}
+
+// selfUpdate checks the latest release, and unless check is set, installs it
+// if it is newer than the running binary, or force is set.
+func selfUpdate(ctx context.Context, check bool, force bool, verifySignature bool) error {
+	updater := selfupdate.NewUpdater(secret("github-token"))
+	updater.HTTPClient = newHTTPClient()
+
+	latest, err := updater.Latest(ctx)
+	if err != nil {
+		return fmt.Errorf("checking the latest release: %w", err)
+	}
+
+	current := version
+	if current == "" {
+		current = "dirty build"
+	}
+
+	newer := version != "" && selfupdate.IsNewer(version, latest.Version)
+
+	if check {
+		switch {
+		case newer:
+			fmt.Printf("%s %s is available (installed: %s): %s\n", program, latest.Version, current, latest.URL)
+		case version == "":
+			fmt.Printf("The latest release is %s (installed: %s)\n", latest.Version, current)
+		default:
+			fmt.Printf("%s %s is the latest release\n", program, current)
+		}
+
+		return nil
+	}
+
+	switch {
+	case version == "" && !force:
+		return fmt.Errorf("refusing to replace a dirty build, use --force to install %s anyway", latest.Version)
+	case !newer && !force:
+		fmt.Printf("%s %s is the latest release\n", program, current)
+		return nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		return err
+	}
+
+	if isManagedInstall(executable) {
+		return fmt.Errorf("%w at %s, update it with the package manager instead", errManagedInstall, executable)
+	}
+
+	printStatus("Downloading", latest.Version)
+
+	binary, err := updater.Download(ctx, latest, verifySignature)
+	if err != nil {
+		return err
+	}
+
+	if err := selfupdate.Replace(executable, binary); err != nil {
+		return fmt.Errorf("replacing %s: %w", executable, err)
+	}
+
+	fmt.Printf("Updated %s from %s to %s\n", program, current, latest.Version)
+
+	return nil
+}
+
+// isManagedInstall reports whether the binary at the path is installed by a
+// package manager, like Homebrew or Scoop.
+func isManagedInstall(executable string) bool {
+	path := filepath.ToSlash(executable)
+
+	for _, dir := range []string{"/Cellar/", "/homebrew/", "/scoop/apps/", "/nix/store/"} {
+		if strings.Contains(strings.ToLower(path), strings.ToLower(dir)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/metrics"
+	"gabor-boros/sprint-update/pkg/schedule"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// sprintWeekFirst restricts a schedule entry to the first week of the
+	// sprint.
+	sprintWeekFirst = "first"
+	// sprintWeekLast restricts a schedule entry to the last week of the
+	// sprint.
+	sprintWeekLast = "last"
+)
+
+var (
+	// errMissingSchedule is returned when the daemon is started without a
+	// schedule.
+	errMissingSchedule = errors.New("no schedule configured, add [[schedule]] entries to the configuration file")
+	// errInvalidSprintWeek is returned when the sprint week of a schedule
+	// entry is unknown.
+	errInvalidSprintWeek = errors.New("invalid sprint week")
+	// errSprintWeekWithoutBoard is returned when a schedule entry is
+	// restricted to a week of the sprint, but the board is not set.
+	errSprintWeekWithoutBoard = errors.New("the sprint week of the schedule requires --board")
+	// errNoScheduledRun is returned when the schedule matches no time in the
+	// next years.
+	errNoScheduledRun = errors.New("the schedule matches no time")
+	// errServeFlag is returned when a flag unsupported by the daemon is set.
+	errServeFlag = errors.New("cannot be used with serve")
+)
+
+var serveCmd = &cobra.Command{
+	Use:     "serve",
+	Short:   "Generate and deliver sprint updates on a schedule.",
+	Long:    "Run in the foreground, generating the sprint update and delivering it to the targets at the times of the schedule of the configuration file. When no targets are set, the update is posted to Discourse.",
+	Example: fmt.Sprintf("%s serve --board 42 --to slack", program),
+	Args:    cobra.NoArgs,
+	Run:     runServeCmd,
+}
+
+func init() {
+	addGenerationFlags(serveCmd.Flags())
+	addDeliveryFlags(serveCmd.Flags())
+	serveCmd.Flags().StringP("metrics-file", "", "", "file the metrics of the runs are written to in the prometheus text format, like for the textfile collector of the node exporter")
+	serveCmd.Flags().StringP("otlp-endpoint", "", "", "URL of the opentelemetry collector the metrics of the runs are exported to (ex: http://localhost:4318)")
+	serveCmd.Flags().StringToStringP("otlp-headers", "", map[string]string{}, "headers sent to the opentelemetry collector (ex: \"Authorization=Bearer token\")")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// scheduleEntry is an entry of the schedule in the configuration file.
+type scheduleEntry struct {
+	// Cron is the cron expression of the times the update is generated at,
+	// like "0 10 * * WED".
+	Cron string `mapstructure:"cron"`
+	// EndOfSprint indicates that an end of sprint update is generated.
+	EndOfSprint bool `mapstructure:"end-of-sprint"`
+	// SprintWeek restricts the entry to the first or the last week of the
+	// active sprint of the board. When empty, every week matches.
+	SprintWeek string `mapstructure:"sprint-week"`
+
+	expression *schedule.Expression
+}
+
+// matchesSprintWeek reports whether the time is within the week of the sprint
+// the entry is restricted to.
+func (e *scheduleEntry) matchesSprintWeek(s *jira.Sprint, now time.Time) bool {
+	switch e.SprintWeek {
+	case "":
+		return true
+	case sprintWeekFirst:
+		return s.StartDate != nil && now.Before(s.StartDate.AddDate(0, 0, 7))
+	default:
+		return s.EndDate != nil && now.After(s.EndDate.AddDate(0, 0, -7))
+	}
+}
+
+// scheduleEntries returns the parsed entries of the configured schedule.
+func scheduleEntries() ([]scheduleEntry, error) {
+	var entries []scheduleEntry
+	if err := viper.UnmarshalKey("schedule", &entries); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, errMissingSchedule
+	}
+
+	for i := range entries {
+		expression, err := schedule.Parse(entries[i].Cron)
+		if err != nil {
+			return nil, err
+		}
+
+		entries[i].expression = expression
+		entries[i].SprintWeek = strings.ToLower(entries[i].SprintWeek)
+
+		switch entries[i].SprintWeek {
+		case "", sprintWeekFirst, sprintWeekLast:
+		default:
+			return nil, fmt.Errorf("%w: %s (available: %s, %s)", errInvalidSprintWeek, entries[i].SprintWeek, sprintWeekFirst, sprintWeekLast)
+		}
+
+		if entries[i].SprintWeek != "" && viper.GetInt("board") == 0 {
+			return nil, errSprintWeekWithoutBoard
+		}
+	}
+
+	return entries, nil
+}
+
+// nextRun returns the next time the schedule matches after the given time,
+// and the entries matching it.
+func nextRun(entries []scheduleEntry, after time.Time) (time.Time, []scheduleEntry) {
+	var next time.Time
+	var due []scheduleEntry
+
+	for _, entry := range entries {
+		t := entry.expression.Next(after)
+		switch {
+		case t.IsZero():
+			continue
+		case next.IsZero() || t.Before(next):
+			next, due = t, []scheduleEntry{entry}
+		case t.Equal(next):
+			due = append(due, entry)
+		}
+	}
+
+	return next, due
+}
+
+// checkServeFlags checks that neither the flags requiring the user, as the
+// daemon runs unattended, nor recording the Jira responses are set.
+func checkServeFlags() error {
+	for _, flag := range []string{"interactive", "edit"} {
+		if viper.GetBool(flag) {
+			return fmt.Errorf("--%s %w", flag, errServeFlag)
+		}
+	}
+
+	if viper.GetString("record") != "" {
+		return fmt.Errorf("--record %w", errServeFlag)
+	}
+
+	return nil
+}
+
+// runServeCmd generates and delivers the update at the times of the schedule,
+// until the daemon is interrupted. A failed run is reported without stopping
+// the daemon, so the next runs are attempted. The metrics of the runs are
+// exported after every run, and once at start, so the monitoring knows the
+// daemon before its first run.
+func runServeCmd(cmd *cobra.Command, _ []string) {
+	checkErr(checkServeFlags())
+
+	entries, err := scheduleEntries()
+	checkErr(err)
+
+	targets, err := deliveryTargets()
+	checkErr(err)
+
+	if len(targets) == 0 {
+		targets = []string{targetDiscourse}
+	}
+
+	config := newConfig()
+	_, err = prepareConfig(&config)
+	checkErr(err)
+
+	ctx := cmd.Context()
+	collector := metrics.NewCollector()
+	exportMetrics(ctx, collector)
+
+	for {
+		next, due := nextRun(entries, time.Now())
+		if next.IsZero() {
+			checkErr(errNoScheduledRun)
+		}
+
+		printStatus("Next update at", next.Format("Mon Jan 2 15:04"))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		start := time.Now()
+		result, err := runScheduled(cmd, collector, config, due, targets)
+		if err != nil {
+			printError(err, exitCode(err))
+		}
+
+		collector.ObserveRun(start, result)
+		exportMetrics(ctx, collector)
+	}
+}
+
+// runScheduled generates and delivers the update of the entries due, unless
+// none of them matches the week of the sprint, and returns the result of the
+// run. If any of them generates an end of sprint update, an end of sprint
+// update is generated. The metrics of the run are recorded by the collector.
+func runScheduled(cmd *cobra.Command, collector *metrics.Collector, config sprint.Config, due []scheduleEntry, targets []string) (metrics.Result, error) {
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	ctx = metrics.NewContext(ctx, collector)
+
+	due, err := inSprintWeek(ctx, &config, due, time.Now())
+	if err != nil {
+		return metrics.ResultFailure, err
+	}
+
+	if len(due) == 0 {
+		printStatus("Skipping the update, as it is not the scheduled week of the sprint")
+		return metrics.ResultSkipped, nil
+	}
+
+	for _, entry := range due {
+		config.EndOfSprint = config.EndOfSprint || entry.EndOfSprint
+	}
+
+	if err = runUpdate(ctx, config, nil, targets); err != nil {
+		return metrics.ResultFailure, err
+	}
+
+	return metrics.ResultSuccess, nil
+}
+
+// inSprintWeek returns the entries matching the week of the active sprint of
+// the board. The sample issues have no sprint, hence every entry matches
+// them.
+func inSprintWeek(ctx context.Context, config *sprint.Config, entries []scheduleEntry, now time.Time) ([]scheduleEntry, error) {
+	var activeSprint *jira.Sprint
+	var matching []scheduleEntry
+
+	for i := range entries {
+		if entries[i].SprintWeek != "" && !viper.GetBool("sample") {
+			if activeSprint == nil {
+				var err error
+				if activeSprint, err = config.ActiveSprint(ctx); err != nil {
+					return nil, err
+				}
+			}
+
+			if !entries[i].matchesSprintWeek(activeSprint, now) {
+				continue
+			}
+		}
+
+		matching = append(matching, entries[i])
+	}
+
+	return matching, nil
+}
@@ -0,0 +1,420 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	// errUnknownSetting is returned when getting or setting a key that is not
+	// a setting.
+	errUnknownSetting = errors.New("unknown setting")
+	// errSecretSetting is returned when getting or setting a secret, which is
+	// managed by the credentials command instead.
+	errSecretSetting = errors.New("is a secret, use the credentials command to manage it")
+	// errTableSetting is returned when setting a table, which cannot be given
+	// as a single value.
+	errTableSetting = errors.New("is a table, edit the configuration file instead")
+	// errSettingNotSet is returned when getting a setting that is not set.
+	errSettingNotSet = errors.New("is not set")
+	// errMultilineString is returned when replacing a setting written as a
+	// multi-line string.
+	errMultilineString = errors.New("is a multi-line string, edit the configuration file instead")
+)
+
+var (
+	configGetCmd = &cobra.Command{
+		Use:               "get <key>",
+		Short:             "Print a setting of the configuration file.",
+		Long:              "Print the value of a setting read from the configuration file and the environment, taking the selected profile into account. The lists are printed one item per line, and the tables of strings one \"key=value\" pair per line.",
+		Example:           fmt.Sprintf("%s config get board\n%s config get --profile work jira-url", program, program),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSettings,
+		Run:               runConfigGetCmd,
+	}
+	configSetCmd = &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Write a setting to the configuration file.",
+		Long: fmt.Sprintf(`Write the value of a setting to the configuration file, keeping its other settings and comments. When no configuration file is found, it is created in the configuration directory of the user, like %%APPDATA%%\%[1]s\%[2]s on Windows or ~/.config/%[1]s/%[2]s on Linux. When a profile is selected, the setting of the profile is written.
+
+The lists are given as comma separated values, like "slack,email". The secrets are stored in the keyring using the credentials command instead.`, program, configFileName),
+		Example:           fmt.Sprintf("%s config set board 42\n%s config set to slack,email\n%s config set --profile work jira-url https://work.atlassian.net", program, program, program),
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeSettings,
+		Run:               runConfigSetCmd,
+	}
+)
+
+func init() {
+	configCmd.AddCommand(configGetCmd, configSetCmd)
+}
+
+// completeSettings completes the keys of the settings.
+func completeSettings(_ *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var keys []string
+	for key := range configSchema() {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return matchPrefix(keys, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// settingKindOf returns the kind of the setting, checking that it is a known
+// setting other than a secret.
+func settingKindOf(key string, schema map[string]settingKind) (settingKind, error) {
+	kind, ok := schema[key]
+	if !ok {
+		if closest := closestSetting(key, schema); closest != "" {
+			return "", fmt.Errorf("%w: %s (did you mean %s?)", errUnknownSetting, key, closest)
+		}
+
+		return "", fmt.Errorf("%w: %s", errUnknownSetting, key)
+	}
+
+	for _, secretKey := range secretKeys {
+		if key == secretKey {
+			return "", fmt.Errorf("%s %w", key, errSecretSetting)
+		}
+	}
+
+	return kind, nil
+}
+
+// isConfigGet returns whether the config get command is run, which prints
+// nothing but the value of the setting, so it can be captured by scripts.
+func isConfigGet() bool {
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	return err == nil && cmd == configGetCmd
+}
+
+// runConfigGetCmd prints the value of the setting.
+func runConfigGetCmd(_ *cobra.Command, args []string) {
+	key := strings.ToLower(args[0])
+	schema := configSchema()
+
+	kind, err := settingKindOf(key, schema)
+	checkErr(err)
+
+	settings := make(map[string]interface{})
+	if path := viper.ConfigFileUsed(); path != "" && !noConfig {
+		file := viper.New()
+		file.SetConfigFile(path)
+		file.SetConfigType("toml")
+		checkErr(configError(file.ReadInConfig()))
+
+		settings = file.AllSettings()
+	}
+
+	scope := newConfigScope(settings, activeProfile(), schema)
+	if !scope.settings.IsSet(key) {
+		checkErr(fmt.Errorf("%s %w", key, errSettingNotSet))
+	}
+
+	switch kind {
+	case kindList, kindStringOrList:
+		for _, item := range scope.settings.GetStringSlice(key) {
+			fmt.Println(item)
+		}
+	case kindMap, kindIntMap:
+		table := scope.settings.GetStringMapString(key)
+		names := make([]string, 0, len(table))
+		for name := range table {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%s=%s\n", name, table[name])
+		}
+	case kindTable, kindListOfTables:
+		checkErr(fmt.Errorf("%s %w", key, errTableSetting))
+	default:
+		fmt.Println(scope.settings.GetString(key))
+	}
+}
+
+// runConfigSetCmd writes the setting to the configuration file in use, or to
+// the configuration file of the platform if there is none.
+func runConfigSetCmd(_ *cobra.Command, args []string) {
+	key := strings.ToLower(args[0])
+
+	kind, err := settingKindOf(key, configSchema())
+	checkErr(err)
+
+	value, err := tomlValue(key, kind, args[1])
+	checkErr(configError(err))
+
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		path, err = configPath()
+		checkErr(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		checkErr(err)
+	}
+
+	table := ""
+	if profile := activeProfile(); profile != "" {
+		table = keyPath(profilesKey, profile)
+	}
+
+	updated, err := setTOMLValue(string(content), table, key, value)
+	checkErr(err)
+
+	// The file is only written if it is still a valid TOML file, so the
+	// configuration is never broken by an unforeseen syntax.
+	file := viper.New()
+	file.SetConfigType("toml")
+	checkErr(configError(file.ReadConfig(strings.NewReader(updated))))
+
+	checkErr(os.MkdirAll(filepath.Dir(path), 0700))
+	checkErr(os.WriteFile(path, []byte(updated), 0600))
+
+	printStatus(fmt.Sprintf("Set %s to %s in %s", keyPath(table, key), value, path))
+}
+
+// tomlValue returns the value of the setting given on the command line,
+// written in TOML.
+func tomlValue(key string, kind settingKind, value string) (string, error) {
+	switch kind {
+	case kindBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%s must be %s: %s", key, kind, value)
+		}
+
+		return strconv.FormatBool(b), nil
+	case kindInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("%s must be %s: %s", key, kind, value)
+		}
+
+		return strconv.Itoa(n), nil
+	case kindDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return "", fmt.Errorf("%s must be %s: %s", key, kind, value)
+		}
+	case kindList:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, tomlString(item))
+			}
+		}
+
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case kindMap, kindIntMap, kindTable, kindListOfTables:
+		return "", fmt.Errorf("%s %w", key, errTableSetting)
+	}
+
+	if validate, ok := settingValidators[key]; ok {
+		if err := validate(value); err != nil {
+			return "", err
+		}
+	}
+
+	return tomlString(value), nil
+}
+
+// tomlString returns the string as a TOML basic string, escaping the quotes,
+// the backslashes, and the control characters.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&b, `\u%04X`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	b.WriteByte('"')
+	return b.String()
+}
+
+// setTOMLValue returns the TOML content with the key of the table set to the
+// value, written in TOML. If the table is empty, the top-level key is set.
+// The line of the key is replaced if the key is set already; otherwise, the
+// key is added after the last setting of the table, and the table is added at
+// the end if it is missing. The other lines, including the comments, are kept
+// as is.
+func setTOMLValue(content string, table string, key string, value string) (string, error) {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	start, end := 0, len(lines)
+	if table != "" {
+		start = -1
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "[") {
+			continue
+		}
+
+		switch {
+		case start >= 0:
+			end = i
+		case isTableHeader(trimmed, table):
+			start = i + 1
+			continue
+		default:
+			continue
+		}
+
+		break
+	}
+
+	setting := key + " = " + value
+
+	if start < 0 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+
+		lines = append(lines, "["+table+"]", setting)
+		return strings.Join(lines, "\n") + "\n", nil
+	}
+
+	insertAt := start
+	for i := start; i < end; i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+
+		insertAt = i + 1
+
+		if !strings.EqualFold(tomlKey(trimmed), key) {
+			continue
+		}
+
+		count, err := valueLines(lines[i:end])
+		if err != nil {
+			return "", fmt.Errorf("%s %w", key, err)
+		}
+
+		updated := append(append(append([]string{}, lines[:i]...), setting), lines[i+count:]...)
+		return strings.Join(updated, "\n") + "\n", nil
+	}
+
+	updated := append(append(append([]string{}, lines[:insertAt]...), setting), lines[insertAt:]...)
+	return strings.Join(updated, "\n") + "\n", nil
+}
+
+// isTableHeader reports whether the trimmed line is the header of the table,
+// like "[profiles.work]" or `[profiles."work"]`.
+func isTableHeader(trimmed string, table string) bool {
+	if strings.HasPrefix(trimmed, "[[") {
+		return false
+	}
+
+	if i := strings.Index(trimmed, "]"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '"' || r == '\'' {
+			return -1
+		}
+
+		return r
+	}, strings.TrimPrefix(trimmed, "["))
+
+	return strings.EqualFold(name, table)
+}
+
+// tomlKey returns the key of the trimmed line setting a value, like "board"
+// for `board = 42`, or an empty string if the line sets no value.
+func tomlKey(trimmed string) string {
+	if strings.HasPrefix(trimmed, `"`) || strings.HasPrefix(trimmed, "'") {
+		if end := strings.IndexByte(trimmed[1:], trimmed[0]); end >= 0 {
+			return trimmed[1 : end+1]
+		}
+
+		return ""
+	}
+
+	i := strings.IndexByte(trimmed, '=')
+	if i < 0 || strings.HasPrefix(trimmed, "#") {
+		return ""
+	}
+
+	return strings.TrimSpace(trimmed[:i])
+}
+
+// valueLines returns the number of lines the value of the setting on the
+// first line spans, as the arrays can be written on multiple lines.
+func valueLines(lines []string) (int, error) {
+	depth := 0
+	var quote byte
+
+	for n, line := range lines {
+		value := line
+		if n == 0 {
+			value = line[strings.IndexByte(line, '=')+1:]
+		}
+
+		if strings.Contains(value, `"""`) || strings.Contains(value, "'''") {
+			return 0, errMultilineString
+		}
+
+		for i := 0; i < len(value); i++ {
+			c := value[i]
+
+			switch {
+			case quote != 0:
+				if c == '\\' && quote == '"' {
+					i++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == '[' || c == '{':
+				depth++
+			case c == ']' || c == '}':
+				depth--
+			case c == '#':
+				i = len(value)
+			}
+		}
+
+		if depth <= 0 {
+			return n + 1, nil
+		}
+	}
+
+	return len(lines), nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/viper"
+)
+
+// errRecordAndReplay is returned when both recording and replaying are
+// requested.
+var errRecordAndReplay = errors.New("--record and --replay cannot be used together")
+
+// setupSnapshot configures recording or replaying the Jira responses. When
+// recording, the returned recorder collects the responses, and the cache of
+// the Jira metadata is disabled, so every response is recorded. When
+// replaying, the Jira credentials, the cache, and the code host integrations
+// are disabled, so the update is generated offline.
+func setupSnapshot(config *sprint.Config) (*jira.Recorder, error) {
+	recordPath := viper.GetString("record")
+	replayPath := viper.GetString("replay")
+
+	switch {
+	case recordPath != "" && replayPath != "":
+		return nil, errRecordAndReplay
+	case recordPath != "":
+		recorder := &jira.Recorder{Transport: config.Transport}
+		config.Transport = recorder
+		config.Cache = nil
+		return recorder, nil
+	case replayPath != "":
+		snapshot, err := jira.LoadSnapshot(replayPath)
+		if err != nil {
+			return nil, err
+		}
+
+		config.Transport = jira.NewReplayer(snapshot)
+		config.ServerURL = snapshot.ServerURL
+		config.AuthType = jira.AuthBasic
+		config.Username = ""
+		config.Password = ""
+		config.Token = ""
+		config.CodeHosts = nil
+		config.GitRepositories = nil
+		config.Cache = nil
+	}
+
+	return nil, nil
+}
+
+// saveSnapshot saves the recorded Jira responses.
+func saveSnapshot(recorder *jira.Recorder, config *sprint.Config) error {
+	path := viper.GetString("record")
+	if err := recorder.Snapshot(config.ServerURL).Save(path); err != nil {
+		return err
+	}
+
+	printStatus("Jira responses recorded to", path)
+	return nil
+}
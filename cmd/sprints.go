@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// sprintDateLayout is the layout of the dates of the listed sprints.
+const sprintDateLayout = "2006-01-02"
+
+var (
+	// errMissingBoard is returned when no board is set for listing its
+	// sprints.
+	errMissingBoard = errors.New("board ID is required, set it using --board or the board configuration key")
+	// errAmbiguousSprint is returned when a partial sprint name matches
+	// multiple sprints of the board, and no sprint is chosen.
+	errAmbiguousSprint = errors.New("ambiguous sprint name")
+)
+
+var sprintsCmd = &cobra.Command{
+	Use:     "sprints",
+	Short:   "List the sprints of a board.",
+	Long:    "List the future, active, and recently closed sprints of a board with their IDs, names, and dates, so the exact sprint name can be passed to --sprint.",
+	Example: fmt.Sprintf("%s sprints --board 42", program),
+	Args:    cobra.NoArgs,
+	Run:     runSprintsCmd,
+}
+
+func init() {
+	sprintsCmd.Flags().IntP("board", "b", 0, "jira board ID (default is the board configuration key)")
+	sprintsCmd.Flags().IntP("closed", "", 5, "number of recently closed sprints listed")
+	rootCmd.AddCommand(sprintsCmd)
+}
+
+// runSprintsCmd prints the sprints of the board as a table.
+func runSprintsCmd(cmd *cobra.Command, _ []string) {
+	boardID, err := cmd.Flags().GetInt("board")
+	checkErr(err)
+
+	if boardID == 0 {
+		boardID = viper.GetInt("board")
+	}
+
+	if boardID == 0 {
+		checkErr(errMissingBoard)
+	}
+
+	closed, err := cmd.Flags().GetInt("closed")
+	checkErr(err)
+
+	config := newConfig()
+	checkErr(readJiraSecret(&config))
+
+	ctx, cancel := commandContext(cmd)
+	defer cancel()
+
+	sprints, err := config.ListSprints(ctx, boardID)
+	checkErr(err)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATE\tNAME\tSTART\tEND")
+
+	for _, s := range recentSprints(sprints, closed) {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", s.ID, s.State, s.Name, formatSprintDate(s.StartDate), formatSprintDate(s.EndDate))
+	}
+
+	checkErr(w.Flush())
+}
+
+// resolveSprintNames resolves the partial sprint names, like "253" for
+// "SE.253", against the sprints of the board. If a name matches multiple
+// sprints, the sprint is chosen on a terminal, otherwise errAmbiguousSprint
+// is returned. The names matching no sprint are left as is.
+func resolveSprintNames(ctx context.Context, config *sprint.Config) error {
+	names := append([]string{config.Sprint}, config.Sprints...)
+
+	for i, name := range names {
+		resolved, err := config.ResolveSprintNames(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case len(resolved) == 0:
+			continue
+		case len(resolved) == 1:
+			names[i] = resolved[0]
+		case isTerminal(os.Stdin.Fd()) && !boolSetting("quiet"):
+			if names[i], err = chooseSprint(name, resolved); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: %q matches %s", errAmbiguousSprint, name, strings.Join(resolved, ", "))
+		}
+
+		if names[i] != name {
+			printStatus("Using sprint:", names[i])
+		}
+	}
+
+	config.Sprint, config.Sprints = names[0], names[1:]
+	return nil
+}
+
+// chooseSprint prompts for choosing one of the sprints the partial name
+// matches.
+func chooseSprint(name string, names []string) (string, error) {
+	fmt.Fprintf(os.Stderr, "The sprint name %q matches multiple sprints:\n", name)
+	for i, n := range names {
+		fmt.Fprintf(os.Stderr, "  %d. %s\n", i+1, n)
+	}
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stderr}
+	answer, err := p.ask("Sprint", "1")
+	if err != nil {
+		return "", err
+	}
+
+	choice, err := strconv.Atoi(answer)
+	if err != nil || choice < 1 || choice > len(names) {
+		return "", fmt.Errorf("%w: %q is not one of the listed sprints", errAmbiguousSprint, answer)
+	}
+
+	return names[choice-1], nil
+}
+
+// recentSprints returns the sprints leaving out the closed ones, except for
+// the given number of the most recently closed sprints.
+func recentSprints(sprints []jira.Sprint, closed int) []jira.Sprint {
+	skipped := -closed
+	for i := range sprints {
+		if sprints[i].IsClosed() {
+			skipped++
+		}
+	}
+
+	recent := make([]jira.Sprint, 0, len(sprints))
+	for i := range sprints {
+		if sprints[i].IsClosed() && skipped > 0 {
+			skipped--
+			continue
+		}
+
+		recent = append(recent, sprints[i])
+	}
+
+	return recent
+}
+
+// formatSprintDate formats the date of a sprint, which is "-" if unknown.
+func formatSprintDate(date *time.Time) string {
+	if date == nil {
+		return "-"
+	}
+
+	return date.Local().Format(sprintDateLayout)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"gabor-boros/sprint-update/pkg/llm"
+	"gabor-boros/sprint-update/pkg/report"
+
+	"github.com/spf13/viper"
+)
+
+// newLanguageModel returns the language model client writing the narrative
+// summary. If the summary section is not rendered, or no model is set, nil is
+// returned, so the issues are never sent to a language model unless asked.
+func newLanguageModel(sections []report.Section) *llm.Client {
+	if !report.HasSection(sections, report.SectionSummary) || viper.GetString("llm-model") == "" {
+		return nil
+	}
+
+	return &llm.Client{
+		BaseURL:    viper.GetString("llm-url"),
+		APIKey:     secret("llm-api-key"),
+		Model:      viper.GetString("llm-model"),
+		HTTPClient: newHTTPClient(),
+	}
+}
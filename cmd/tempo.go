@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"gabor-boros/sprint-update/pkg/tempo"
+
+	"github.com/spf13/viper"
+)
+
+// newTempo returns the Tempo client the logged hours are read from. If Tempo
+// is not enabled, nil is returned.
+func newTempo() *tempo.Client {
+	if !viper.GetBool("tempo") {
+		return nil
+	}
+
+	return &tempo.Client{
+		URL:        viper.GetString("tempo-url"),
+		Token:      secret("tempo-token"),
+		HTTPClient: newHTTPClient(),
+	}
+}
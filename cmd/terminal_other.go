@@ -0,0 +1,36 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package cmd
+
+import (
+	"errors"
+)
+
+// errNoTerminal is returned when the echo of the terminal cannot be disabled
+// on the platform.
+var errNoTerminal = errors.New("terminal not supported on this platform")
+
+// isTerminal reports whether the file descriptor is a terminal, which is never
+// known on the platform.
+func isTerminal(_ uintptr) bool {
+	return false
+}
+
+// disableEcho fails, as the echo of the terminal cannot be disabled on the
+// platform.
+func disableEcho(_ uintptr) (func(), error) {
+	return nil, errNoTerminal
+}
+
+// terminalWidth returns 0, as the width of the terminal is never known on the
+// platform.
+func terminalWidth(_ uintptr) int {
+	return 0
+}
+
+// enableColors reports that no colors are written, as the terminal is never
+// known on the platform.
+func enableColors(_ uintptr) bool {
+	return false
+}
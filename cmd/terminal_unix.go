@@ -0,0 +1,53 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package cmd
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// isTerminal reports whether the file descriptor is a terminal.
+func isTerminal(fd uintptr) bool {
+	_, err := unix.IoctlGetTermios(int(fd), ioctlGetTermios)
+	return err == nil
+}
+
+// disableEcho stops the terminal from echoing the typed characters, and
+// returns the function restoring the previous state.
+func disableEcho(fd uintptr) (func(), error) {
+	state, err := unix.IoctlGetTermios(int(fd), ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	noEcho := *state
+	noEcho.Lflag &^= unix.ECHO
+	noEcho.Lflag |= unix.ICANON | unix.ISIG
+	noEcho.Iflag |= unix.ICRNL
+
+	if err = unix.IoctlSetTermios(int(fd), ioctlSetTermios, &noEcho); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = unix.IoctlSetTermios(int(fd), ioctlSetTermios, state)
+	}, nil
+}
+
+// terminalWidth returns the number of columns of the terminal, or 0 if the
+// file descriptor is not a terminal.
+func terminalWidth(fd uintptr) int {
+	size, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+
+	return int(size.Col)
+}
+
+// enableColors reports whether the ANSI escape sequences of the colors can be
+// written to the file descriptor, which is the case for terminals.
+func enableColors(fd uintptr) bool {
+	return isTerminal(fd)
+}
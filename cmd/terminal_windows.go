@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// isTerminal reports whether the file descriptor is a console.
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+	return windows.GetConsoleMode(windows.Handle(fd), &mode) == nil
+}
+
+// disableEcho stops the console from echoing the typed characters, and
+// returns the function restoring the previous mode.
+func disableEcho(fd uintptr) (func(), error) {
+	var mode uint32
+	if err := windows.GetConsoleMode(windows.Handle(fd), &mode); err != nil {
+		return nil, err
+	}
+
+	noEcho := mode&^windows.ENABLE_ECHO_INPUT | windows.ENABLE_PROCESSED_INPUT | windows.ENABLE_LINE_INPUT
+	if err := windows.SetConsoleMode(windows.Handle(fd), noEcho); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_ = windows.SetConsoleMode(windows.Handle(fd), mode)
+	}, nil
+}
+
+// terminalWidth returns the number of columns of the console window, or 0 if
+// the file descriptor is not a console.
+func terminalWidth(fd uintptr) int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0
+	}
+
+	return int(info.Window.Right - info.Window.Left + 1)
+}
+
+// enableColors enables the processing of the ANSI escape sequences of the
+// colors by the console, and reports whether it succeeded.
+func enableColors(fd uintptr) bool {
+	var mode uint32
+	if err := windows.GetConsoleMode(windows.Handle(fd), &mode); err != nil {
+		return false
+	}
+
+	return windows.SetConsoleMode(windows.Handle(fd), mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
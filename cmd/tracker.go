@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/azure"
+	"gabor-boros/sprint-update/pkg/github"
+	"gabor-boros/sprint-update/pkg/linear"
+	"gabor-boros/sprint-update/pkg/tracker"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// trackerJira fetches the issues from Jira.
+	trackerJira = "jira"
+	// trackerLinear fetches the issues of the cycles of a Linear team.
+	trackerLinear = "linear"
+	// trackerAzure fetches the work items of the iterations of an Azure
+	// Boards team.
+	trackerAzure = "azure"
+	// trackerGitHub fetches the issues of the milestones of a GitHub
+	// repository or of the iterations of a GitHub project.
+	trackerGitHub = "github"
+)
+
+// errUnknownTracker is returned when the requested issue tracker does not
+// exist.
+var errUnknownTracker = errors.New("unknown issue tracker")
+
+// newTracker returns the issue tracker the issues are fetched from. If the
+// issues are fetched from Jira, nil is returned.
+func newTracker() (tracker.Tracker, error) {
+	switch name := strings.ToLower(viper.GetString("tracker")); name {
+	case "", trackerJira:
+		return nil, nil
+	case trackerLinear:
+		return &linear.Client{
+			Token:      secret("linear-token"),
+			Team:       viper.GetString("linear-team"),
+			HTTPClient: newHTTPClient(),
+		}, nil
+	case trackerAzure:
+		return &azure.Client{
+			URL:        viper.GetString("azure-url"),
+			Project:    viper.GetString("azure-project"),
+			Team:       viper.GetString("azure-team"),
+			Token:      secret("azure-token"),
+			HTTPClient: newHTTPClient(),
+		}, nil
+	case trackerGitHub:
+		client := github.NewClient(viper.GetString("github-url"), secret("github-token"))
+		client.HTTPClient = newHTTPClient()
+
+		gitHub := &tracker.GitHub{
+			Client:         client,
+			Repository:     viper.GetString("github-milestone-repo"),
+			IterationField: viper.GetString("github-iteration-field"),
+			StatusField:    viper.GetString("github-status-field"),
+		}
+
+		if project := viper.GetString("github-project"); project != "" {
+			var err error
+			if gitHub.Project, err = tracker.ParseGitHubProject(project); err != nil {
+				return nil, err
+			}
+		}
+
+		return gitHub, nil
+	default:
+		return nil, fmt.Errorf("%w: %s (available: %s, %s, %s, %s)", errUnknownTracker, name, trackerJira, trackerLinear, trackerAzure, trackerGitHub)
+	}
+}
@@ -0,0 +1,733 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/credentials"
+	"gabor-boros/sprint-update/pkg/email"
+	"gabor-boros/sprint-update/pkg/i18n"
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/schedule"
+	"gabor-boros/sprint-update/pkg/signing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// maxTypoDistance is the maximum number of edits between an unknown key and a
+// known key for the known key to be suggested instead.
+const maxTypoDistance = 2
+
+var (
+	// errNoConfigFile is returned when no configuration file is found to
+	// validate.
+	errNoConfigFile = errors.New("no configuration file found, create it using the config init command")
+	// errInvalidConfig is returned when the configuration file does not match
+	// the schema of the settings.
+	errInvalidConfig = errors.New("invalid configuration")
+)
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration file.",
+	Long:  "Check the configuration file and its profiles against the schema of the settings: unknown keys, values of the wrong type or not among the available values, settings that cannot be used together, and settings missing for the enabled features, like the delivery targets. Every problem is printed with the path of the offending key.",
+	Args:  cobra.NoArgs,
+	Run:   runConfigValidateCmd,
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+}
+
+// settingKind is the type of the value of a setting.
+type settingKind string
+
+// The kinds of the settings, described as the problems refer to them.
+const (
+	kindString       settingKind = "a string"
+	kindStringOrList settingKind = "a string or a list of strings"
+	kindBool         settingKind = "true or false"
+	kindInt          settingKind = "an integer"
+	kindDuration     settingKind = "a duration, like \"2m\""
+	kindList         settingKind = "a list of strings"
+	kindMap          settingKind = "a table of strings"
+	kindIntMap       settingKind = "a table of integers"
+	kindTable        settingKind = "a table"
+	kindListOfTables settingKind = "a list of tables"
+	// kindAny is the kind of the flags of other types, which are not checked.
+	kindAny settingKind = "any value"
+)
+
+// The keys of the settings configured as tables rather than flags.
+const (
+	scheduleKey        = "schedule"
+	statusGroupsKey    = "status-groups"
+	redactAliasesKey   = "redact-aliases"
+	scheduleCronKey    = "cron"
+	scheduleWeekKey    = "sprint-week"
+	statusGroupNameKey = "name"
+)
+
+// scheduleSchema is the schema of the entries of the schedule.
+var scheduleSchema = map[string]settingKind{
+	scheduleCronKey: kindString,
+	"end-of-sprint": kindBool,
+	scheduleWeekKey: kindString,
+}
+
+// statusGroupSchema is the schema of the entries of the status groups.
+var statusGroupSchema = map[string]settingKind{
+	statusGroupNameKey: kindString,
+	"statuses":         kindList,
+}
+
+// exclusiveSettings are the pairs of settings that cannot be used together.
+var exclusiveSettings = [][2]string{
+	{"assignee", "assignees"},
+	{"sprint", "from"},
+	{"sprint", "sprint-id"},
+	{"sprint-id", "from"},
+	{"draft", "amend"},
+	{"record", "replay"},
+	{"jira-password-stdin", "interactive"},
+}
+
+// featureRequirement lists the settings a feature requires when it is
+// enabled.
+type featureRequirement struct {
+	// feature describes the settings enabling the feature.
+	feature string
+	// enabled reports whether the feature is enabled in the scope.
+	enabled func(s *configScope) bool
+	// required are the settings the feature requires.
+	required []string
+}
+
+// featureRequirements are the settings required by the features, besides the
+// settings of the delivery targets.
+var featureRequirements = []featureRequirement{
+	{"until", settingSet("until"), []string{"from"}},
+	{"tempo", settingSet("tempo"), []string{"tempo-token"}},
+	{"the summary", summaryEnabled, []string{"llm-model"}},
+	{"tracker = " + trackerLinear, settingIs("tracker", trackerLinear), []string{"linear-token", "linear-team"}},
+	{"tracker = " + trackerAzure, settingIs("tracker", trackerAzure), []string{"azure-url", "azure-project", "azure-token"}},
+	{"calendar-type = " + calendarCalDAV, settingIs("calendar-type", calendarCalDAV), []string{"calendar-url"}},
+	{"calendar-type = " + calendarTempo, settingIs("calendar-type", calendarTempo), []string{"tempo-token"}},
+	{"calendar-type = " + calendarBambooHR, settingIs("calendar-type", calendarBambooHR), []string{"bamboohr-company", "bamboohr-employee-id", "bamboohr-token"}},
+}
+
+// settingValidators check the values of the settings having a fixed set of
+// values. The values of the list settings are checked one by one.
+var settingValidators = map[string]func(value string) error{
+	"format":        validateFormat,
+	"plain-formats": validateFormat,
+	"lang":          i18n.Validate,
+	"group-by":      func(value string) error { return report.ValidateGroupBy(report.GroupBy(value)) },
+	"sort-by":       func(value string) error { return report.ValidateSortBy(report.SortBy(value)) },
+	"fold-by":       func(value string) error { return report.ValidateFoldBy(report.FoldBy(value)) },
+	"subtasks":      func(value string) error { return report.ValidateSubtaskMode(report.SubtaskMode(value)) },
+	"issue-fields":  func(value string) error { return jira.ValidateOptionalFields([]string{value}) },
+	"annotations": func(value string) error {
+		return report.ValidateAnnotations([]report.Annotation{report.Annotation(strings.ToLower(value))})
+	},
+	"sections": func(value string) error {
+		return report.ValidateSections([]report.Section{report.Section(strings.ToLower(value))}, nil)
+	},
+	"from":          validatePeriodDate,
+	"until":         validatePeriodDate,
+	"to":            oneOf(availableTargets...),
+	"tracker":       oneOf(trackerJira, trackerLinear, trackerAzure, trackerGitHub),
+	"calendar-type": oneOf(calendarTypes...),
+	"amend-mode":    oneOf(amendEdit, amendReply),
+	"auth-type":     oneOf(string(jira.AuthBasic), string(jira.AuthToken), string(jira.AuthPAT), string(jira.AuthOAuth)),
+	"email-tls":     oneOf(string(email.TLSNone), string(email.TLSStartTLS), string(email.TLSImplicit)),
+	"sign":          func(value string) error { return signing.ValidateTool(signing.Tool(value)) },
+}
+
+// validateFormat checks that the format is a built-in output format.
+func validateFormat(value string) error {
+	return render.ValidateFormats([]string{value})
+}
+
+// validatePeriodDate checks the date of the period covered by the update.
+func validatePeriodDate(value string) error {
+	if _, err := time.Parse(periodDateLayout, value); err != nil {
+		return fmt.Errorf("%w: %s (expected YYYY-MM-DD)", errInvalidDate, value)
+	}
+
+	return nil
+}
+
+// oneOf returns a validator accepting the given values, ignoring the case.
+func oneOf(available ...string) func(value string) error {
+	return func(value string) error {
+		for _, name := range available {
+			if strings.EqualFold(value, name) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("unknown value %q (available: %s)", value, strings.Join(available, ", "))
+	}
+}
+
+// settingSet returns a function reporting whether the setting is set.
+func settingSet(key string) func(s *configScope) bool {
+	return func(s *configScope) bool {
+		return s.isSet(key)
+	}
+}
+
+// settingIs returns a function reporting whether the setting has the value.
+func settingIs(key string, value string) func(s *configScope) bool {
+	return func(s *configScope) bool {
+		return strings.EqualFold(s.settings.GetString(key), value)
+	}
+}
+
+// summaryEnabled reports whether the narrative summary is written.
+func summaryEnabled(s *configScope) bool {
+	if s.isSet("summary") {
+		return true
+	}
+
+	for _, section := range s.settings.GetStringSlice("sections") {
+		if strings.EqualFold(section, string(report.SectionSummary)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// configSchema returns the kinds of the settings by their keys. Every flag of
+// the commands can be set in the configuration file, besides the settings
+// configured as tables.
+func configSchema() map[string]settingKind {
+	schema := map[string]settingKind{
+		scheduleKey:      kindListOfTables,
+		statusGroupsKey:  kindListOfTables,
+		redactAliasesKey: kindMap,
+		profilesKey:      kindTable,
+	}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags()} {
+			flags.VisitAll(func(flag *pflag.Flag) {
+				if flag.Name != "help" && flag.Name != "config" {
+					schema[flag.Name] = flagKind(flag)
+				}
+			})
+		}
+
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+
+	walk(rootCmd)
+
+	// A single sprint name is not split on whitespace, see sprintNames.
+	schema["sprint"] = kindStringOrList
+	// The maximum numbers of issues are read from a string map flag.
+	schema["max-items"] = kindIntMap
+
+	return schema
+}
+
+// flagKind returns the kind of the values of the flag.
+func flagKind(flag *pflag.Flag) settingKind {
+	switch flag.Value.Type() {
+	case "string":
+		return kindString
+	case "bool":
+		return kindBool
+	case "int", "int64", "uint", "uint64":
+		return kindInt
+	case "duration":
+		return kindDuration
+	case "stringSlice", "stringArray":
+		return kindList
+	case "stringToString":
+		return kindMap
+	default:
+		return kindAny
+	}
+}
+
+// configValidator collects the problems of the configuration file.
+type configValidator struct {
+	schema   map[string]settingKind
+	problems []string
+	// topLevel are the problems of the top-level settings, by their keys
+	// and descriptions.
+	topLevel map[string]bool
+}
+
+// report records a problem of the setting at the key path.
+func (v *configValidator) report(path string, format string, a ...interface{}) {
+	v.problems = append(v.problems, path+": "+fmt.Sprintf(format, a...))
+}
+
+// keyPath returns the path of the key under the prefix.
+func keyPath(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+// sortedKeys returns the keys of the table in alphabetical order, so the
+// problems are printed in a stable order.
+func sortedKeys(table map[string]interface{}) []string {
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// validateSettings checks the keys and the types of the settings of the top
+// level or of a profile.
+func (v *configValidator) validateSettings(prefix string, settings map[string]interface{}, inProfile bool) {
+	for _, key := range sortedKeys(settings) {
+		path, value := keyPath(prefix, key), settings[key]
+
+		kind, ok := v.schema[key]
+		if !ok {
+			v.reportUnknown(path, key, v.schema)
+			continue
+		}
+
+		if !v.checkKind(path, kind, value) {
+			continue
+		}
+
+		switch key {
+		case profilesKey:
+			if inProfile {
+				v.report(path, "profiles cannot be nested")
+				continue
+			}
+
+			profiles := value.(map[string]interface{})
+			for _, name := range sortedKeys(profiles) {
+				profilePath := keyPath(path, name)
+				if v.checkKind(profilePath, kindTable, profiles[name]) {
+					v.validateSettings(profilePath, profiles[name].(map[string]interface{}), true)
+				}
+			}
+		case scheduleKey:
+			v.validateEntries(path, value, scheduleSchema, []string{scheduleCronKey}, v.validateScheduleEntry)
+		case statusGroupsKey:
+			v.validateEntries(path, value, statusGroupSchema, []string{statusGroupNameKey}, nil)
+		default:
+			v.validateValue(path, key, value)
+		}
+	}
+}
+
+// validateEntries checks the entries of a list of tables against the schema
+// of the entries.
+func (v *configValidator) validateEntries(path string, value interface{}, schema map[string]settingKind, required []string, check func(path string, entry map[string]interface{})) {
+	for i, item := range value.([]interface{}) {
+		entryPath := fmt.Sprintf("%s[%d]", path, i)
+		if !v.checkKind(entryPath, kindTable, item) {
+			continue
+		}
+
+		entry := item.(map[string]interface{})
+		for _, key := range sortedKeys(entry) {
+			kind, ok := schema[key]
+			if !ok {
+				v.reportUnknown(keyPath(entryPath, key), key, schema)
+				continue
+			}
+
+			v.checkKind(keyPath(entryPath, key), kind, entry[key])
+		}
+
+		for _, key := range required {
+			if _, ok := entry[key]; !ok {
+				v.report(keyPath(entryPath, key), "required")
+			}
+		}
+
+		if check != nil {
+			check(entryPath, entry)
+		}
+	}
+}
+
+// validateScheduleEntry checks the cron expression and the sprint week of the
+// schedule entry.
+func (v *configValidator) validateScheduleEntry(path string, entry map[string]interface{}) {
+	if cron, ok := entry[scheduleCronKey].(string); ok {
+		if _, err := schedule.Parse(cron); err != nil {
+			v.report(keyPath(path, scheduleCronKey), "%v", err)
+		}
+	}
+
+	if week, ok := entry[scheduleWeekKey].(string); ok {
+		switch strings.ToLower(week) {
+		case sprintWeekFirst, sprintWeekLast:
+		default:
+			v.report(keyPath(path, scheduleWeekKey), "%v: %s (available: %s, %s)", errInvalidSprintWeek, week, sprintWeekFirst, sprintWeekLast)
+		}
+	}
+}
+
+// reportUnknown records an unknown key, suggesting the closest known key if
+// the unknown key is likely a typo of it.
+func (v *configValidator) reportUnknown(path string, key string, schema map[string]settingKind) {
+	if closest := closestSetting(key, schema); closest != "" {
+		v.report(path, "unknown setting (did you mean %s?)", closest)
+		return
+	}
+
+	v.report(path, "unknown setting")
+}
+
+// closestSetting returns the known key closest to the unknown key, or an
+// empty string if the unknown key is not likely a typo of any known key.
+func closestSetting(key string, schema map[string]settingKind) string {
+	closest, distance := "", maxTypoDistance+1
+	for known := range schema {
+		if d := editDistance(key, known); d < distance || (d == distance && known < closest) {
+			closest, distance = known, d
+		}
+	}
+
+	return closest
+}
+
+// checkKind checks that the value is of the kind, recording a problem if it
+// is not.
+func (v *configValidator) checkKind(path string, kind settingKind, value interface{}) bool {
+	ok := false
+
+	switch kind {
+	case kindString:
+		_, ok = value.(string)
+	case kindStringOrList:
+		_, ok = value.(string)
+		ok = ok || isStringList(value)
+	case kindBool:
+		_, ok = value.(bool)
+	case kindInt:
+		ok = isInt(value)
+	case kindDuration:
+		var s string
+		if s, ok = value.(string); ok {
+			if _, err := time.ParseDuration(s); err != nil {
+				v.report(path, "invalid duration %q, expected %s", s, kind)
+				return false
+			}
+		}
+	case kindList:
+		ok = isStringList(value)
+	case kindMap:
+		var table map[string]interface{}
+		if table, ok = value.(map[string]interface{}); ok {
+			for _, item := range table {
+				if _, isString := item.(string); !isString {
+					ok = false
+				}
+			}
+		}
+	case kindIntMap:
+		var table map[string]interface{}
+		if table, ok = value.(map[string]interface{}); ok {
+			for _, item := range table {
+				ok = ok && isInt(item)
+			}
+		}
+	case kindTable:
+		_, ok = value.(map[string]interface{})
+	case kindListOfTables:
+		_, ok = value.([]interface{})
+	default:
+		ok = true
+	}
+
+	if !ok {
+		v.report(path, "expected %s, got %s", kind, describeValue(value))
+	}
+
+	return ok
+}
+
+// isInt reports whether the value is an integer, which may be decoded as a
+// float.
+func isInt(value interface{}) bool {
+	switch n := value.(type) {
+	case int, int64:
+		return true
+	case float64:
+		return n == float64(int64(n))
+	default:
+		return false
+	}
+}
+
+// isStringList reports whether the value is a list of strings.
+func isStringList(value interface{}) bool {
+	items, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, item := range items {
+		if _, ok := item.(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describeValue describes the type of the value for the problems.
+func describeValue(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return fmt.Sprintf("the string %q", value)
+	case bool:
+		return fmt.Sprintf("the boolean %t", value)
+	case int, int64, float64:
+		return fmt.Sprintf("the number %v", value)
+	case time.Time:
+		return "a date, quote it to make it a string"
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "a table"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// validateValue checks the value of the setting having a fixed set of values.
+func (v *configValidator) validateValue(path string, key string, value interface{}) {
+	validate, ok := settingValidators[key]
+	if !ok {
+		return
+	}
+
+	items, isList := value.([]interface{})
+	if !isList {
+		s, _ := value.(string)
+		if err := validate(s); err != nil {
+			v.report(path, "%v", err)
+		}
+
+		return
+	}
+
+	for i, item := range items {
+		s, _ := item.(string)
+		if err := validate(s); err != nil {
+			v.report(fmt.Sprintf("%s[%d]", path, i), "%v", err)
+		}
+	}
+}
+
+// configScope is the configuration checked as a whole: the top-level
+// settings, or the settings of a profile merged over them.
+type configScope struct {
+	// prefix is the key path of the profile, or empty for the top level.
+	prefix string
+	// profile is the name of the profile, or empty for the top level.
+	profile string
+	// kinds are the kinds of the settings by their keys.
+	kinds map[string]settingKind
+	// settings are the settings of the scope, including the environment.
+	settings *viper.Viper
+}
+
+// newConfigScope returns the scope of the profile merged over the top-level
+// settings. If the name is empty, the scope of the top level is returned.
+func newConfigScope(settings map[string]interface{}, name string, kinds map[string]settingKind) *configScope {
+	s := &configScope{profile: name, kinds: kinds, settings: viper.New()}
+	s.settings.SetEnvPrefix(envPrefix)
+	s.settings.SetEnvKeyReplacer(envKeyReplacer)
+	s.settings.AutomaticEnv()
+	_ = s.settings.MergeConfigMap(settings)
+
+	if name != "" {
+		s.prefix = keyPath(profilesKey, name)
+		if profile, ok := s.settings.Get(s.prefix).(map[string]interface{}); ok {
+			_ = s.settings.MergeConfigMap(profile)
+		}
+	}
+
+	return s
+}
+
+// isSet reports whether the setting is set to a value other than the zero
+// value of its kind. The secrets are looked up in the keyring too.
+func (s *configScope) isSet(key string) bool {
+	switch s.kinds[key] {
+	case kindBool:
+		return s.settings.GetBool(key)
+	case kindInt:
+		return s.settings.GetInt(key) != 0
+	case kindList, kindStringOrList:
+		return len(s.settings.GetStringSlice(key)) > 0
+	case kindMap, kindIntMap:
+		return len(s.settings.GetStringMapString(key)) > 0
+	}
+
+	value := s.settings.GetString(key)
+	for _, secretKey := range secretKeys {
+		if key == secretKey {
+			value = credentials.Lookup(keyPath(s.profile, key), value)
+		}
+	}
+
+	return value != ""
+}
+
+// validateScope checks the settings used together in the scope: the settings
+// that cannot be used together, and the settings required by the enabled
+// features and delivery targets.
+func (v *configValidator) validateScope(s *configScope) {
+	for _, pair := range exclusiveSettings {
+		if s.isSet(pair[0]) && s.isSet(pair[1]) {
+			v.reportScope(s, pair[1], "cannot be used together with "+pair[0])
+		}
+	}
+
+	for _, requirement := range featureRequirements {
+		if !requirement.enabled(s) {
+			continue
+		}
+
+		for _, key := range requirement.required {
+			if !s.isSet(key) {
+				v.reportScope(s, key, "required by "+requirement.feature)
+			}
+		}
+	}
+
+	for _, target := range s.settings.GetStringSlice("to") {
+		target = strings.ToLower(strings.TrimSpace(target))
+		if !isTarget(target) {
+			continue
+		}
+
+		for _, key := range missingTargetSettings(target, s.isSet) {
+			v.reportScope(s, key, "required by the "+target+" delivery target")
+		}
+	}
+
+	var entries []scheduleEntry
+	if err := s.settings.UnmarshalKey(scheduleKey, &entries); err == nil && !s.isSet("board") {
+		for i, entry := range entries {
+			if entry.SprintWeek != "" {
+				v.reportScope(s, fmt.Sprintf("%s[%d].%s", scheduleKey, i, scheduleWeekKey), "requires board")
+			}
+		}
+	}
+}
+
+// reportScope records a problem of the setting in the scope. The problems of
+// the profiles that are inherited from the top level are recorded once, at
+// the top level.
+func (v *configValidator) reportScope(s *configScope, key string, problem string) {
+	if s.profile == "" {
+		v.topLevel[key+problem] = true
+	} else if v.topLevel[key+problem] {
+		return
+	}
+
+	v.report(keyPath(s.prefix, key), "%s", problem)
+}
+
+// editDistance returns the number of single character insertions, deletions,
+// or substitutions turning a into b.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+
+		previous = current
+	}
+
+	return previous[len(b)]
+}
+
+// minInt returns the smaller of the integers.
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// runConfigValidateCmd validates the configuration file given by --config, or
+// the configuration file found otherwise, printing the problems found. If any problem is found, the command exits with an error.
+func runConfigValidateCmd(_ *cobra.Command, _ []string) {
+	path := configFile
+	if path == "" {
+		path = viper.ConfigFileUsed()
+	}
+
+	if path == "" {
+		checkErr(errNoConfigFile)
+	}
+
+	file := viper.New()
+	file.SetConfigFile(path)
+	checkErr(configError(file.ReadInConfig()))
+
+	settings := file.AllSettings()
+	v := &configValidator{schema: configSchema(), topLevel: make(map[string]bool)}
+	v.validateSettings("", settings, false)
+
+	// The combinations are only checked if the settings are of the right
+	// type, as the values cannot be read otherwise.
+	if len(v.problems) == 0 {
+		v.validateScope(newConfigScope(settings, "", v.schema))
+
+		profiles, _ := settings[profilesKey].(map[string]interface{})
+		for _, name := range sortedKeys(profiles) {
+			v.validateScope(newConfigScope(settings, name, v.schema))
+		}
+	}
+
+	for _, problem := range v.problems {
+		fmt.Println(problem)
+	}
+
+	if len(v.problems) > 0 {
+		checkErr(fmt.Errorf("%w: %d problems found in %s", errInvalidConfig, len(v.problems), path))
+	}
+
+	fmt.Println(path, "is valid")
+}
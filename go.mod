@@ -5,5 +5,9 @@ go 1.16
 require (
 	github.com/andygrunwald/go-jira v1.14.0
 	github.com/spf13/cobra v1.2.1
+	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.8.1
+	github.com/zalando/go-keyring v0.1.1
+	golang.org/x/sys v0.0.0-20210510120138-977fb7262007
+	gopkg.in/yaml.v2 v2.4.0
 )
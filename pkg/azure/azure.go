@@ -0,0 +1,404 @@
+// Package azure implements the tracker of Azure Boards, treating the
+// iterations of a team as the sprints.
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/tracker"
+)
+
+// apiVersion is the version of the Azure DevOps REST API used.
+const apiVersion = "7.0"
+
+// batchSize is the maximum number of work items fetched by a single request.
+const batchSize = 200
+
+// completedCategory is the category of the states of the done work items.
+const completedCategory = "Completed"
+
+// pastTimeFrame is the time frame of the completed iterations.
+const pastTimeFrame = "past"
+
+var (
+	// ErrMissingToken is returned when no Azure DevOps personal access token
+	// is set.
+	ErrMissingToken = errors.New("azure devops personal access token is required")
+	// ErrMissingProject is returned when the organization URL or the project
+	// is not set.
+	ErrMissingProject = errors.New("azure devops organization URL and project are required")
+)
+
+// workItemFields are the fields of the work items read for the update.
+var workItemFields = []string{
+	"System.Title",
+	"System.State",
+	"System.WorkItemType",
+	"System.AssignedTo",
+	"System.Tags",
+	"System.Parent",
+	"System.TeamProject",
+	"Microsoft.VSTS.Scheduling.StoryPoints",
+	"Microsoft.VSTS.Common.Priority",
+	"Microsoft.VSTS.Common.ClosedDate",
+	"Microsoft.VSTS.Scheduling.DueDate",
+}
+
+// Client is a client of the Azure DevOps REST API, implementing the tracker
+// of the iterations of a team.
+type Client struct {
+	// URL is the URL of the organization, like "https://dev.azure.com/contoso".
+	URL string
+	// Project is the name of the project.
+	Project string
+	// Team is the name of the team. When empty, the default team of the
+	// project is used.
+	Team string
+	// Token is the personal access token of the user.
+	Token string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+var _ tracker.Tracker = (*Client)(nil)
+
+// iterationsResponse is the response listing the iterations of the team.
+type iterationsResponse struct {
+	Value []struct {
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+		Path       string `json:"path"`
+		Attributes struct {
+			StartDate  *time.Time `json:"startDate"`
+			FinishDate *time.Time `json:"finishDate"`
+			TimeFrame  string     `json:"timeFrame"`
+		} `json:"attributes"`
+	} `json:"value"`
+}
+
+// wiqlResponse is the response of a work item query.
+type wiqlResponse struct {
+	WorkItems []struct {
+		ID int `json:"id"`
+	} `json:"workItems"`
+}
+
+// workItem is a work item returned by the API.
+type workItem struct {
+	ID     int `json:"id"`
+	Fields struct {
+		Title      string `json:"System.Title"`
+		State      string `json:"System.State"`
+		Type       string `json:"System.WorkItemType"`
+		AssignedTo *struct {
+			DisplayName string `json:"displayName"`
+		} `json:"System.AssignedTo"`
+		Tags        string     `json:"System.Tags"`
+		Parent      int        `json:"System.Parent"`
+		Project     string     `json:"System.TeamProject"`
+		StoryPoints float64    `json:"Microsoft.VSTS.Scheduling.StoryPoints"`
+		Priority    int        `json:"Microsoft.VSTS.Common.Priority"`
+		ClosedDate  *time.Time `json:"Microsoft.VSTS.Common.ClosedDate"`
+		DueDate     *time.Time `json:"Microsoft.VSTS.Scheduling.DueDate"`
+	} `json:"fields"`
+}
+
+// workItemsResponse is the response listing work items.
+type workItemsResponse struct {
+	Value []workItem `json:"value"`
+}
+
+// statesResponse is the response listing the states of a work item type.
+type statesResponse struct {
+	Value []struct {
+		Name     string `json:"name"`
+		Category string `json:"category"`
+	} `json:"value"`
+}
+
+// errorResponse is the error returned by the API.
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// Name returns the name of the tracker.
+func (c *Client) Name() string {
+	return "Azure Boards"
+}
+
+// Sprint returns the iteration of the team of the given name or path, like
+// "Sprint 42" or "Contoso\Sprint 42", or the current iteration if the name is
+// empty.
+func (c *Client) Sprint(ctx context.Context, name string) (*tracker.Sprint, error) {
+	query := url.Values{}
+	if name == "" {
+		query.Set("$timeframe", "current")
+	}
+
+	var resp iterationsResponse
+	if err := c.do(ctx, http.MethodGet, c.teamURL("/_apis/work/teamsettings/iterations", query), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	for _, iteration := range resp.Value {
+		if name != "" && !strings.EqualFold(iteration.Name, name) && !strings.EqualFold(iteration.Path, name) {
+			continue
+		}
+
+		return &tracker.Sprint{
+			ID:        iteration.Path,
+			Name:      iteration.Name,
+			StartDate: iteration.Attributes.StartDate,
+			EndDate:   iteration.Attributes.FinishDate,
+			Closed:    iteration.Attributes.TimeFrame == pastTimeFrame,
+		}, nil
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("%w: the team has no current iteration", tracker.ErrSprintNotFound)
+	}
+
+	return nil, fmt.Errorf("%w: %s", tracker.ErrSprintNotFound, name)
+}
+
+// Issues returns the work items of the iteration assigned to the
+// authenticated user, or to the user of the given email address or display
+// name.
+func (c *Client) Issues(ctx context.Context, sprint *tracker.Sprint, assignee string) ([]report.Issue, error) {
+	assignedTo := "@Me"
+	if assignee != "" {
+		assignedTo = quote(assignee)
+	}
+
+	wiql := fmt.Sprintf(
+		"SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.IterationPath] = %s AND [System.AssignedTo] = %s ORDER BY [System.Id]",
+		quote(sprint.ID), assignedTo,
+	)
+
+	body, err := json.Marshal(map[string]string{"query": wiql})
+	if err != nil {
+		return nil, err
+	}
+
+	var result wiqlResponse
+	if err = c.do(ctx, http.MethodPost, c.teamURL("/_apis/wit/wiql", nil), body, &result); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(result.WorkItems))
+	for _, item := range result.WorkItems {
+		ids = append(ids, item.ID)
+	}
+
+	items, err := c.workItems(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]map[string]string)
+	issues := make([]report.Issue, 0, len(items))
+
+	for i := range items {
+		itemType := items[i].Fields.Type
+		if _, ok := categories[itemType]; !ok {
+			if categories[itemType], err = c.stateCategories(ctx, itemType); err != nil {
+				return nil, err
+			}
+		}
+
+		issues = append(issues, c.newIssue(&items[i], categories[itemType]))
+	}
+
+	return issues, nil
+}
+
+// workItems returns the work items of the given IDs.
+func (c *Client) workItems(ctx context.Context, ids []int) ([]workItem, error) {
+	var items []workItem
+
+	for start := 0; start < len(ids); start += batchSize {
+		end := start + batchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		body, err := json.Marshal(map[string]interface{}{
+			"ids":         ids[start:end],
+			"fields":      workItemFields,
+			"errorPolicy": "omit",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var batch workItemsResponse
+		if err = c.do(ctx, http.MethodPost, c.projectURL("/_apis/wit/workitemsbatch", nil), body, &batch); err != nil {
+			return nil, err
+		}
+
+		items = append(items, batch.Value...)
+	}
+
+	return items, nil
+}
+
+// stateCategories returns the categories of the states of the work item type
+// by the names of the states, like "Completed" for "Done".
+func (c *Client) stateCategories(ctx context.Context, itemType string) (map[string]string, error) {
+	var resp statesResponse
+	if err := c.do(ctx, http.MethodGet, c.projectURL("/_apis/wit/workitemtypes/"+url.PathEscape(itemType)+"/states", nil), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	categories := make(map[string]string, len(resp.Value))
+	for _, state := range resp.Value {
+		categories[state.Name] = state.Category
+	}
+
+	return categories, nil
+}
+
+// newIssue returns the issue of the update from the work item.
+func (c *Client) newIssue(item *workItem, categories map[string]string) report.Issue {
+	key := strconv.Itoa(item.ID)
+
+	issue := report.Issue{
+		Key:         key,
+		Summary:     item.Fields.Title,
+		URL:         c.projectURL("/_workitems/edit/"+key, nil),
+		Status:      item.Fields.State,
+		Type:        item.Fields.Type,
+		Done:        categories[item.Fields.State] == completedCategory,
+		StoryPoints: item.Fields.StoryPoints,
+		Project:     item.Fields.Project,
+	}
+
+	if item.Fields.AssignedTo != nil {
+		issue.Assignee = item.Fields.AssignedTo.DisplayName
+	}
+
+	for _, tag := range strings.Split(item.Fields.Tags, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			issue.Labels = append(issue.Labels, tag)
+		}
+	}
+
+	if item.Fields.Parent != 0 {
+		issue.Parent = strconv.Itoa(item.Fields.Parent)
+	}
+
+	if item.Fields.Priority != 0 {
+		issue.Priority = strconv.Itoa(item.Fields.Priority)
+	}
+
+	if item.Fields.ClosedDate != nil {
+		issue.Resolved = *item.Fields.ClosedDate
+	}
+
+	if item.Fields.DueDate != nil {
+		issue.Due = *item.Fields.DueDate
+	}
+
+	return issue
+}
+
+// quote returns the string literal of the value in a work item query.
+func quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// projectURL returns the URL of the path of the project API.
+func (c *Client) projectURL(path string, query url.Values) string {
+	return c.apiURL(url.PathEscape(c.Project)+path, query)
+}
+
+// teamURL returns the URL of the path of the team API.
+func (c *Client) teamURL(path string, query url.Values) string {
+	prefix := url.PathEscape(c.Project)
+	if c.Team != "" {
+		prefix += "/" + url.PathEscape(c.Team)
+	}
+
+	return c.apiURL(prefix+path, query)
+}
+
+// apiURL returns the URL of the path within the organization, setting the
+// version of the API for the API requests.
+func (c *Client) apiURL(path string, query url.Values) string {
+	if strings.Contains(path, "/_apis/") {
+		if query == nil {
+			query = url.Values{}
+		}
+
+		query.Set("api-version", apiVersion)
+	}
+
+	requestURL := strings.TrimSuffix(c.URL, "/") + "/" + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	return requestURL
+}
+
+// do sends an authenticated request to the API and decodes the response into
+// v.
+func (c *Client) do(ctx context.Context, method string, requestURL string, body []byte, v interface{}) error {
+	if c.Token == "" {
+		return ErrMissingToken
+	}
+
+	if c.URL == "" || c.Project == "" {
+		return ErrMissingProject
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth("", c.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Message != "" {
+			return fmt.Errorf("azure devops request failed with status %d: %s", resp.StatusCode, errResp.Message)
+		}
+
+		return fmt.Errorf("azure devops request failed with status %d", resp.StatusCode)
+	}
+
+	return json.Unmarshal(respBody, v)
+}
@@ -0,0 +1,154 @@
+// Package cache keeps the slow-changing Jira metadata, like the IDs of the
+// custom fields and the sprints of the boards, in a file for a while, so
+// repeated runs do not query the configuration endpoints of Jira again.
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the default time the cached values are kept for.
+const DefaultTTL = 24 * time.Hour
+
+// entry is a cached value and its expiry.
+type entry struct {
+	Value   json.RawMessage `json:"value"`
+	Expires time.Time       `json:"expires"`
+}
+
+// Cache is a cache of JSON encoded values saved to a file. A nil Cache caches
+// nothing, so it can be used without checking whether caching is enabled.
+type Cache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	entries map[string]entry
+}
+
+// New returns a new Cache saved to the file at the given path, keeping the
+// values for the given time.
+func New(path string, ttl time.Duration) *Cache {
+	return &Cache{path: path, ttl: ttl}
+}
+
+// load reads the cached values from the file, unless they are read already.
+// A missing or corrupt file is treated as an empty cache, as the values can
+// be fetched again.
+func (c *Cache) load() {
+	if c.entries != nil {
+		return
+	}
+
+	c.entries = make(map[string]entry)
+
+	data, err := os.ReadFile(filepath.Clean(c.path))
+	if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(data, &c.entries); err != nil {
+		c.entries = make(map[string]entry)
+	}
+}
+
+// save writes the cached values which have not expired to the file, creating
+// its directory if necessary.
+func (c *Cache) save() error {
+	now := time.Now()
+	for key, e := range c.entries {
+		if !now.Before(e.Expires) {
+			delete(c.entries, key)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0600)
+}
+
+// Get decodes the cached value of the key into v, and reports whether the
+// value was found and has not expired yet.
+func (c *Cache) Get(key string, v interface{}) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+
+	e, ok := c.entries[key]
+	if !ok || !time.Now().Before(e.Expires) {
+		return false
+	}
+
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Set caches the value of the key for the time the cache keeps the values
+// for.
+func (c *Cache) Set(key string, v interface{}) error {
+	return c.SetUntil(key, v, time.Time{})
+}
+
+// SetUntil caches the value of the key until the given time, or for the time
+// the cache keeps the values for if it is sooner or the time is zero. Values
+// expiring already are not cached.
+func (c *Cache) SetUntil(key string, v interface{}, expires time.Time) error {
+	if c == nil {
+		return nil
+	}
+
+	now := time.Now()
+	if limit := now.Add(c.ttl); expires.IsZero() || expires.After(limit) {
+		expires = limit
+	}
+
+	if !expires.After(now) {
+		return nil
+	}
+
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.load()
+	c.entries[key] = entry{Value: value, Expires: expires}
+
+	return c.save()
+}
+
+// Clear removes every cached value.
+func (c *Cache) Clear() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]entry)
+
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBambooHRURL is the base URL of the BambooHR API.
+const DefaultBambooHRURL = "https://api.bamboohr.com/api/gateway.php"
+
+// bambooHRDateLayout is the layout of the dates of the BambooHR API.
+const bambooHRDateLayout = "2006-01-02"
+
+// ErrMissingBambooHRSettings is returned when the company, the employee ID, or
+// the API key of BambooHR is not set.
+var ErrMissingBambooHRSettings = errors.New("bamboohr company, employee ID, and API key are required")
+
+// BambooHR lists the approved time off requests of an employee in BambooHR.
+type BambooHR struct {
+	// URL is the base URL of the API. When empty, DefaultBambooHRURL is
+	// used.
+	URL string
+	// Company is the subdomain of the company, like "acme" for
+	// acme.bamboohr.com.
+	Company string
+	// EmployeeID is the ID of the employee in BambooHR.
+	EmployeeID string
+	// Token is the API key of the user.
+	Token string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// bambooHRRequest is a time off request returned by the API.
+type bambooHRRequest struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Type  struct {
+		Name string `json:"name"`
+	} `json:"type"`
+}
+
+// Events returns the approved time off requests overlapping the date range
+// as out of office events, described by their time off type, like
+// "Vacation".
+func (c *BambooHR) Events(ctx context.Context, since time.Time, until time.Time) ([]Event, error) {
+	if c.Company == "" || c.EmployeeID == "" || c.Token == "" {
+		return nil, ErrMissingBambooHRSettings
+	}
+
+	baseURL := c.URL
+	if baseURL == "" {
+		baseURL = DefaultBambooHRURL
+	}
+
+	query := url.Values{}
+	query.Set("start", since.Format(bambooHRDateLayout))
+	query.Set("end", until.Format(bambooHRDateLayout))
+	query.Set("status", "approved")
+	query.Set("employeeId", c.EmployeeID)
+
+	requestURL := fmt.Sprintf("%s/%s/v1/time_off/requests/?%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(c.Company), query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// BambooHR takes the API key as the username, with any password.
+	req.SetBasicAuth(c.Token, "x")
+	req.Header.Set("Accept", "application/json")
+
+	body, err := do(c.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests []bambooHRRequest
+	if err = json.Unmarshal([]byte(body), &requests); err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(requests))
+	for _, request := range requests {
+		start, err := time.ParseInLocation(bambooHRDateLayout, request.Start, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("bamboohr time off request has an invalid start date: %w", err)
+		}
+
+		end, err := time.ParseInLocation(bambooHRDateLayout, request.End, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("bamboohr time off request has an invalid end date: %w", err)
+		}
+
+		// The end date of the requests is inclusive, unlike the end of the
+		// all day events.
+		events = append(events, Event{
+			Summary:     request.Type.Name,
+			Start:       start,
+			End:         end.AddDate(0, 0, 1),
+			AllDay:      true,
+			OutOfOffice: true,
+		})
+	}
+
+	return overlapping(events, since, until), nil
+}
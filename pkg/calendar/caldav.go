@@ -0,0 +1,91 @@
+package calendar
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// caldavTimeLayout is the layout of the time ranges in CalDAV queries.
+const caldavTimeLayout = "20060102T150405Z"
+
+// calendarQuery is the CalDAV calendar-query REPORT listing the events within
+// a time range, expanding the recurring events.
+const calendarQuery = `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <c:calendar-data>
+      <c:expand start="%[1]s" end="%[2]s"/>
+    </c:calendar-data>
+  </d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VEVENT">
+        <c:time-range start="%[1]s" end="%[2]s"/>
+      </c:comp-filter>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`
+
+// CalDAV is a calendar collection on a CalDAV server.
+type CalDAV struct {
+	// URL is the URL of the calendar collection.
+	URL      string
+	Username string
+	Password string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// multistatus is the response of the calendar-query REPORT.
+type multistatus struct {
+	Responses []struct {
+		CalendarData []string `xml:"propstat>prop>calendar-data"`
+	} `xml:"response"`
+}
+
+// Events queries the events overlapping the date range.
+func (c *CalDAV) Events(ctx context.Context, since time.Time, until time.Time) ([]Event, error) {
+	query := fmt.Sprintf(calendarQuery, since.UTC().Format(caldavTimeLayout), until.UTC().Format(caldavTimeLayout))
+
+	req, err := http.NewRequestWithContext(ctx, "REPORT", c.URL, strings.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	if c.Username != "" {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+
+	body, err := do(c.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var result multistatus
+	if err = xml.Unmarshal([]byte(body), &result); err != nil {
+		return nil, err
+	}
+
+	var events []Event
+
+	for _, response := range result.Responses {
+		for _, data := range response.CalendarData {
+			parsed, err := Parse(strings.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+
+			events = append(events, parsed...)
+		}
+	}
+
+	return overlapping(events, since, until), nil
+}
@@ -0,0 +1,223 @@
+// Package calendar reads events from iCalendar feeds, CalDAV servers, and the
+// time off requests of BambooHR to detect the planned time off within a
+// sprint.
+package calendar
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Event is a calendar event.
+type Event struct {
+	Summary string
+	Start   time.Time
+	// End is the exclusive end of the event.
+	End time.Time
+	// AllDay indicates that the event lasts for whole days.
+	AllDay bool
+	// OutOfOffice indicates that the event is marked as out of office by the
+	// calendar, which is the case for Outlook.
+	OutOfOffice bool
+}
+
+// Source lists the calendar events.
+type Source interface {
+	// Events returns the events overlapping the given date range.
+	Events(ctx context.Context, since time.Time, until time.Time) ([]Event, error)
+}
+
+// AccountSource is a Source of the events of a Jira user, like the leave
+// planned in Tempo, which identifies the users by their Jira account IDs.
+type AccountSource interface {
+	Source
+	// ForAccount returns the source of the events of the user having the
+	// Jira account ID.
+	ForAccount(accountID string) Source
+}
+
+// ICS is an iCalendar feed, like the secret address of a Google Calendar.
+// Recurring events are not expanded, only their first occurrence is listed.
+type ICS struct {
+	URL string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// Events downloads the feed and returns the events overlapping the date range.
+func (c *ICS) Events(ctx context.Context, since time.Time, until time.Time) ([]Event, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := do(c.HTTPClient, req)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := Parse(strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	return overlapping(events, since, until), nil
+}
+
+// do sends the request and returns the response body.
+func do(client *http.Client, req *http.Request) (string, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("calendar request failed with status %d", resp.StatusCode)
+	}
+
+	return string(body), nil
+}
+
+// overlapping returns the events overlapping the given date range.
+func overlapping(events []Event, since time.Time, until time.Time) []Event {
+	var result []Event
+
+	for _, event := range events {
+		if event.End.After(since) && event.Start.Before(until) {
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
+
+// Parse parses the VEVENT components of the iCalendar data.
+func Parse(r io.Reader) ([]Event, error) {
+	var events []Event
+	var event *Event
+
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range lines {
+		name, params, value := splitProperty(line)
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &Event{}
+		case name == "END" && value == "VEVENT" && event != nil:
+			if event.End.IsZero() {
+				event.End = event.Start
+				if event.AllDay {
+					event.End = event.Start.AddDate(0, 0, 1)
+				}
+			}
+
+			events = append(events, *event)
+			event = nil
+		case event == nil:
+			continue
+		case name == "SUMMARY":
+			event.Summary = unescape(value)
+		case name == "DTSTART":
+			if event.Start, err = parseTime(params, value); err != nil {
+				return nil, err
+			}
+
+			event.AllDay = params["VALUE"] == "DATE"
+		case name == "DTEND":
+			if event.End, err = parseTime(params, value); err != nil {
+				return nil, err
+			}
+		case name == "X-MICROSOFT-CDO-BUSYSTATUS":
+			event.OutOfOffice = value == "OOF"
+		}
+	}
+
+	return events, nil
+}
+
+// unfold reads the content lines, joining the lines folded by starting them
+// with a space or tab.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines, scanner.Err()
+}
+
+// splitProperty splits the content line into its name, parameters and value.
+func splitProperty(line string) (string, map[string]string, string) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", nil, ""
+	}
+
+	parts := strings.Split(line[:i], ";")
+	params := make(map[string]string, len(parts)-1)
+
+	for _, param := range parts[1:] {
+		if j := strings.Index(param, "="); j >= 0 {
+			params[strings.ToUpper(param[:j])] = strings.Trim(param[j+1:], `"`)
+		}
+	}
+
+	return strings.ToUpper(parts[0]), params, line[i+1:]
+}
+
+// parseTime parses the date or date-time value, using the time zone given in
+// the TZID parameter for local times.
+func parseTime(params map[string]string, value string) (time.Time, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		return time.ParseInLocation("20060102", value, time.Local)
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse("20060102T150405Z", value)
+	}
+
+	location := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			location = loc
+		}
+	}
+
+	return time.ParseInLocation("20060102T150405", value, location)
+}
+
+// unescape unescapes the text value.
+func unescape(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
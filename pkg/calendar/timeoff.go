@@ -0,0 +1,101 @@
+package calendar
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultTimeOffKeywords are the words and phrases of the event summaries
+// marking time off.
+var DefaultTimeOffKeywords = []string{
+	"out of office",
+	"ooo",
+	"pto",
+	"vacation",
+	"holiday",
+	"time off",
+	"day off",
+	"leave",
+}
+
+// dayLayout is the layout of the days in the time off description.
+const dayLayout = "Monday, Jan 2"
+
+// IsTimeOff reports whether the event is marked as out of office, or its
+// summary contains one of the keywords as a whole word or phrase.
+func IsTimeOff(event Event, keywords []string) bool {
+	if event.OutOfOffice {
+		return true
+	}
+
+	// Padding the words with spaces matches the keywords on word boundaries.
+	summary := " " + strings.Join(strings.FieldsFunc(strings.ToLower(event.Summary), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ") + " "
+
+	for _, keyword := range keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" && strings.Contains(summary, " "+keyword+" ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TimeOff describes the time off events overlapping the date range, like
+// "Off Thursday, Oct 15–Friday, Oct 16 for PTO". When the keywords are empty,
+// DefaultTimeOffKeywords are used. If there is no time off, an empty string
+// is returned.
+func TimeOff(events []Event, since time.Time, until time.Time, keywords []string) string {
+	if len(keywords) == 0 {
+		keywords = DefaultTimeOffKeywords
+	}
+
+	var descriptions []string
+
+	events = overlapping(events, since, until)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	for _, event := range events {
+		if !IsTimeOff(event, keywords) {
+			continue
+		}
+
+		start, end := event.Start, event.End
+		if start.Before(since) {
+			start = since
+		}
+
+		if end.After(until) {
+			end = until
+		}
+
+		// The end of all day events is exclusive, so the last day is the
+		// one before.
+		if event.AllDay && end.After(start) {
+			end = end.AddDate(0, 0, -1)
+		}
+
+		days := start.Format(dayLayout)
+		if end.Format("2006-01-02") != start.Format("2006-01-02") {
+			days += "–" + end.Format(dayLayout)
+		}
+
+		description := "Off " + days
+		if summary := strings.TrimSpace(event.Summary); summary != "" {
+			description += " for " + summary
+		}
+
+		descriptions = append(descriptions, description)
+	}
+
+	if len(descriptions) == 0 {
+		return ""
+	}
+
+	return strings.Join(descriptions, "; ") + "."
+}
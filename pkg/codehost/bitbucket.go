@@ -0,0 +1,129 @@
+package codehost
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBitbucketURL is the base URL of the Bitbucket Cloud API.
+const DefaultBitbucketURL = "https://api.bitbucket.org/2.0"
+
+// bitbucketPageSize is the number of pull requests requested per page.
+const bitbucketPageSize = 50
+
+// bitbucketMerged is the state of the merged Bitbucket pull requests.
+const bitbucketMerged = "MERGED"
+
+// ErrMissingBitbucketCredentials is returned when the Bitbucket username or
+// app password is not set.
+var ErrMissingBitbucketCredentials = errors.New("bitbucket username and app password are required")
+
+// Bitbucket lists the pull requests of the user on Bitbucket Cloud.
+type Bitbucket struct {
+	// BaseURL is the base URL of the Bitbucket API. When empty,
+	// DefaultBitbucketURL is used.
+	BaseURL string
+	// Username is the username of the user.
+	Username string
+	// AppPassword is the app password used for authentication.
+	AppPassword string
+	// Scopes lists the repositories, like "workspace/repo", and workspaces
+	// the pull requests are listed from. When empty, every repository is
+	// included.
+	Scopes []string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// bitbucketPage is a page of the pull requests returned by the Bitbucket API.
+type bitbucketPage struct {
+	Values []struct {
+		ID        int       `json:"id"`
+		Title     string    `json:"title"`
+		State     string    `json:"state"`
+		CreatedOn time.Time `json:"created_on"`
+		UpdatedOn time.Time `json:"updated_on"`
+		Links     struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+		} `json:"links"`
+		Source struct {
+			Branch struct {
+				Name string `json:"name"`
+			} `json:"branch"`
+		} `json:"source"`
+		Destination struct {
+			Repository struct {
+				FullName string `json:"full_name"`
+			} `json:"repository"`
+		} `json:"destination"`
+	} `json:"values"`
+	Next string `json:"next"`
+}
+
+// PullRequests returns the pull requests authored by the user which were
+// opened or merged within the given window, ordered by creation time. Since
+// Bitbucket does not tell when a pull request was merged, the time of its
+// last update is used instead.
+func (b *Bitbucket) PullRequests(ctx context.Context, since time.Time, until time.Time) ([]PullRequest, error) {
+	if b.Username == "" || b.AppPassword == "" {
+		return nil, ErrMissingBitbucketCredentials
+	}
+
+	baseURL := b.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBitbucketURL
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(b.Username+":"+b.AppPassword)))
+
+	params := url.Values{}
+	params["state"] = []string{"OPEN", bitbucketMerged, "DECLINED", "SUPERSEDED"}
+	params.Set("q", fmt.Sprintf("updated_on >= %s", since.UTC().Format(time.RFC3339)))
+	params.Set("pagelen", fmt.Sprint(bitbucketPageSize))
+
+	var pullRequests []PullRequest
+
+	for next := fmt.Sprintf("%s/pullrequests/%s?%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(b.Username), params.Encode()); next != ""; {
+		var page bitbucketPage
+		if _, err := getJSON(ctx, b.HTTPClient, "bitbucket", next, header, &page); err != nil {
+			return nil, err
+		}
+
+		for _, value := range page.Values {
+			pullRequest := PullRequest{
+				Repository: value.Destination.Repository.FullName,
+				Number:     value.ID,
+				Title:      value.Title,
+				URL:        value.Links.HTML.Href,
+				Branch:     value.Source.Branch.Name,
+				State:      strings.ToLower(value.State),
+				CreatedAt:  value.CreatedOn,
+			}
+
+			if value.State == bitbucketMerged {
+				mergedAt := value.UpdatedOn
+				pullRequest.MergedAt = &mergedAt
+			}
+
+			if pullRequest.inWindow(since, until) && inScopes(pullRequest.Repository, b.Scopes) {
+				pullRequests = append(pullRequests, pullRequest)
+			}
+		}
+
+		next = page.Next
+	}
+
+	sortByCreation(pullRequests)
+
+	return pullRequests, nil
+}
@@ -0,0 +1,145 @@
+// Package codehost lists the pull requests, or merge requests, of the user on
+// code hosting services like GitHub, GitLab, and Bitbucket.
+package codehost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/github"
+)
+
+// PullRequest is a pull request, or merge request, authored by the user.
+type PullRequest struct {
+	// Repository is the full name of the repository, like "owner/repo".
+	Repository string
+	Number     int
+	Title      string
+	URL        string
+	// Branch is the name of the source branch.
+	Branch    string
+	State     string
+	CreatedAt time.Time
+	// MergedAt is the time the pull request was merged, or nil if it is not
+	// merged.
+	MergedAt *time.Time
+}
+
+// IsMerged reports whether the pull request is merged.
+func (p *PullRequest) IsMerged() bool {
+	return p.MergedAt != nil
+}
+
+// inWindow reports whether the pull request was opened or merged within the
+// given window.
+func (p *PullRequest) inWindow(since time.Time, until time.Time) bool {
+	within := func(t time.Time) bool {
+		return !t.Before(since) && !t.After(until)
+	}
+
+	return within(p.CreatedAt) || (p.MergedAt != nil && within(*p.MergedAt))
+}
+
+// CodeHost lists the pull requests of the user on a code hosting service.
+type CodeHost interface {
+	// PullRequests returns the pull requests authored by the user which were
+	// opened or merged within the given window, ordered by creation time.
+	PullRequests(ctx context.Context, since time.Time, until time.Time) ([]PullRequest, error)
+}
+
+// GitHub lists the pull requests of the user on GitHub.
+type GitHub struct {
+	// Client is the GitHub API client.
+	Client *github.Client
+	// Scopes lists the repositories, like "owner/repo", and organizations the
+	// pull requests are searched in. When empty, every repository is searched.
+	Scopes []string
+}
+
+// PullRequests returns the pull requests authored by the user which were
+// opened or merged within the given window, ordered by creation time.
+func (g *GitHub) PullRequests(ctx context.Context, since time.Time, until time.Time) ([]PullRequest, error) {
+	found, err := g.Client.SearchPullRequests(ctx, github.Query{
+		Scopes: g.Scopes,
+		Since:  since,
+		Until:  until,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pullRequests := make([]PullRequest, 0, len(found))
+	for _, pullRequest := range found {
+		pullRequests = append(pullRequests, PullRequest(pullRequest))
+	}
+
+	return pullRequests, nil
+}
+
+// inScopes reports whether the repository is one of the scopes, or belongs to
+// a group or workspace listed in the scopes. Every repository is in scope if
+// no scopes are given.
+func inScopes(repository string, scopes []string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+
+	for _, scope := range scopes {
+		scope = strings.Trim(scope, "/")
+		if strings.EqualFold(repository, scope) || strings.HasPrefix(strings.ToLower(repository), strings.ToLower(scope)+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortByCreation sorts the pull requests by creation time.
+func sortByCreation(pullRequests []PullRequest) {
+	sort.SliceStable(pullRequests, func(i, j int) bool {
+		return pullRequests[i].CreatedAt.Before(pullRequests[j].CreatedAt)
+	})
+}
+
+// getJSON sends the GET request and decodes the response into v, returning
+// the response headers. The service name is used in the error messages. When
+// the client is nil, http.DefaultClient is used.
+func getJSON(ctx context.Context, client *http.Client, service string, requestURL string, header http.Header, v interface{}) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s request failed with status %d", service, resp.StatusCode)
+	}
+
+	return resp.Header, json.Unmarshal(respBody, v)
+}
@@ -0,0 +1,117 @@
+package codehost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultGitLabURL is the URL of GitLab.com.
+const DefaultGitLabURL = "https://gitlab.com"
+
+// gitLabPageSize is the number of merge requests requested per page.
+const gitLabPageSize = 100
+
+// ErrMissingGitLabToken is returned when no GitLab token is set.
+var ErrMissingGitLabToken = errors.New("gitlab token is required")
+
+// GitLab lists the merge requests of the user on GitLab.
+type GitLab struct {
+	// BaseURL is the URL of the GitLab instance. When empty,
+	// DefaultGitLabURL is used.
+	BaseURL string
+	// Token is the personal access token used for authentication.
+	Token string
+	// Scopes lists the projects, like "group/project", and groups the merge
+	// requests are listed from. When empty, every project is included.
+	Scopes []string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// gitLabMergeRequest is a merge request returned by the GitLab API.
+type gitLabMergeRequest struct {
+	IID          int        `json:"iid"`
+	Title        string     `json:"title"`
+	WebURL       string     `json:"web_url"`
+	SourceBranch string     `json:"source_branch"`
+	State        string     `json:"state"`
+	CreatedAt    time.Time  `json:"created_at"`
+	MergedAt     *time.Time `json:"merged_at"`
+	References   struct {
+		Full string `json:"full"`
+	} `json:"references"`
+}
+
+// PullRequests returns the merge requests authored by the user which were
+// opened or merged within the given window, ordered by creation time.
+func (g *GitLab) PullRequests(ctx context.Context, since time.Time, until time.Time) ([]PullRequest, error) {
+	if g.Token == "" {
+		return nil, ErrMissingGitLabToken
+	}
+
+	baseURL := g.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultGitLabURL
+	}
+
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", g.Token)
+
+	var pullRequests []PullRequest
+
+	// Every merge request opened or merged within the window was updated
+	// since its start, the rest is filtered out afterwards.
+	for page := "1"; page != ""; {
+		params := url.Values{}
+		params.Set("scope", "created_by_me")
+		params.Set("state", "all")
+		params.Set("updated_after", since.UTC().Format(time.RFC3339))
+		params.Set("per_page", fmt.Sprint(gitLabPageSize))
+		params.Set("page", page)
+
+		var mergeRequests []gitLabMergeRequest
+		respHeader, err := getJSON(ctx, g.HTTPClient, "gitlab", strings.TrimSuffix(baseURL, "/")+"/api/v4/merge_requests?"+params.Encode(), header, &mergeRequests)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mergeRequest := range mergeRequests {
+			pullRequest := PullRequest{
+				Repository: gitLabProject(mergeRequest.References.Full),
+				Number:     mergeRequest.IID,
+				Title:      mergeRequest.Title,
+				URL:        mergeRequest.WebURL,
+				Branch:     mergeRequest.SourceBranch,
+				State:      mergeRequest.State,
+				CreatedAt:  mergeRequest.CreatedAt,
+				MergedAt:   mergeRequest.MergedAt,
+			}
+
+			if pullRequest.inWindow(since, until) && inScopes(pullRequest.Repository, g.Scopes) {
+				pullRequests = append(pullRequests, pullRequest)
+			}
+		}
+
+		page = respHeader.Get("X-Next-Page")
+	}
+
+	sortByCreation(pullRequests)
+
+	return pullRequests, nil
+}
+
+// gitLabProject returns the full path of the project from the full reference
+// of a merge request, like "group/project!12".
+func gitLabProject(reference string) string {
+	if i := strings.LastIndex(reference, "!"); i != -1 {
+		return reference[:i]
+	}
+
+	return reference
+}
@@ -0,0 +1,243 @@
+// Package confluence implements a minimal Confluence Cloud and Server API
+// client for publishing sprint updates as pages.
+package confluence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrMissingSpace is returned when no space key is set for the page.
+var ErrMissingSpace = errors.New("confluence space key is required")
+
+// Client is a Confluence API client authenticating with a username and an API
+// token or password.
+type Client struct {
+	// BaseURL is the base URL of Confluence, like
+	// "https://example.atlassian.net/wiki".
+	BaseURL string
+	// Username is the username, or the email address on Confluence Cloud.
+	Username string
+	// Token is the API token or the password of the user.
+	Token string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a new Client for the given Confluence and credentials.
+func NewClient(baseURL string, username string, token string) *Client {
+	return &Client{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		Username: username,
+		Token:    token,
+	}
+}
+
+// Page is a Confluence page.
+type Page struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Space   *space `json:"space,omitempty"`
+	Version *struct {
+		Number int `json:"number"`
+	} `json:"version,omitempty"`
+	Ancestors []ancestor `json:"ancestors,omitempty"`
+	Body      *struct {
+		Storage storage `json:"storage"`
+	} `json:"body,omitempty"`
+	Links struct {
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// Content returns the storage format content of the page, if it was fetched.
+func (p *Page) Content() string {
+	if p.Body == nil {
+		return ""
+	}
+
+	return p.Body.Storage.Value
+}
+
+type space struct {
+	Key string `json:"key"`
+}
+
+type ancestor struct {
+	ID string `json:"id"`
+}
+
+// storage is a content body in the given representation, like "storage" or
+// "wiki".
+type storage struct {
+	Value          string `json:"value"`
+	Representation string `json:"representation"`
+}
+
+// errorResponse is the error returned by the Confluence API.
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// ConvertWiki converts the given wiki markup to the storage format of
+// Confluence.
+func (c *Client) ConvertWiki(ctx context.Context, wiki string) (string, error) {
+	body, err := json.Marshal(&storage{Value: wiki, Representation: "wiki"})
+	if err != nil {
+		return "", err
+	}
+
+	var converted storage
+	if err = c.do(ctx, http.MethodPost, "/rest/api/contentbody/convert/storage", body, &converted); err != nil {
+		return "", err
+	}
+
+	return converted.Value, nil
+}
+
+// FindPage returns the page having the given title in the space, together with
+// its content. If the page does not exist, nil is returned.
+func (c *Client) FindPage(ctx context.Context, spaceKey string, title string) (*Page, error) {
+	if spaceKey == "" {
+		return nil, ErrMissingSpace
+	}
+
+	params := url.Values{}
+	params.Set("spaceKey", spaceKey)
+	params.Set("title", title)
+	params.Set("expand", "version,body.storage")
+
+	var result struct {
+		Results []Page `json:"results"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/rest/api/content?"+params.Encode(), nil, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result.Results) == 0 {
+		return nil, nil
+	}
+
+	return &result.Results[0], nil
+}
+
+// CreatePage creates a page with the given storage format content in the
+// space. If the parent ID is set, the page is created under the parent page.
+func (c *Client) CreatePage(ctx context.Context, spaceKey string, parentID string, title string, content string) (*Page, error) {
+	if spaceKey == "" {
+		return nil, ErrMissingSpace
+	}
+
+	page := map[string]interface{}{
+		"type":  "page",
+		"title": title,
+		"space": &space{Key: spaceKey},
+		"body": map[string]interface{}{
+			"storage": &storage{Value: content, Representation: "storage"},
+		},
+	}
+
+	if parentID != "" {
+		page["ancestors"] = []ancestor{{ID: parentID}}
+	}
+
+	body, err := json.Marshal(page)
+	if err != nil {
+		return nil, err
+	}
+
+	var created Page
+	if err = c.do(ctx, http.MethodPost, "/rest/api/content", body, &created); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdatePage replaces the content of the page with the given storage format
+// content.
+func (c *Client) UpdatePage(ctx context.Context, page *Page, content string) (*Page, error) {
+	version := 1
+	if page.Version != nil {
+		version = page.Version.Number + 1
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"type":    "page",
+		"title":   page.Title,
+		"version": map[string]int{"number": version},
+		"body": map[string]interface{}{
+			"storage": &storage{Value: content, Representation: "storage"},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var updated Page
+	if err = c.do(ctx, http.MethodPut, "/rest/api/content/"+url.PathEscape(page.ID), body, &updated); err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// PageURL returns the URL of the given page.
+func (c *Client) PageURL(page *Page) string {
+	return c.BaseURL + page.Links.WebUI
+}
+
+// do sends an authenticated request to the Confluence API and decodes the
+// response into v.
+func (c *Client) do(ctx context.Context, method string, path string, body []byte, v interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(c.Username, c.Token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Message != "" {
+			return fmt.Errorf("confluence request failed with status %d: %s", resp.StatusCode, errResp.Message)
+		}
+
+		return fmt.Errorf("confluence request failed with status %d", resp.StatusCode)
+	}
+
+	return json.Unmarshal(respBody, v)
+}
@@ -0,0 +1,60 @@
+// Package credentials stores secrets in the keyring of the operating system:
+// the macOS Keychain, the Windows Credential Manager, or the Secret Service
+// (libsecret) on Linux. It also resolves references to the secrets of
+// external secret managers, like 1Password, Vault, or AWS Secrets Manager.
+package credentials
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// Service is the name of the keyring service the secrets are stored under.
+const Service = "sprint-update"
+
+// ErrNotFound is returned when the secret is not stored in the keyring.
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// Set stores the secret under the given key in the keyring.
+func Set(key string, secret string) error {
+	return keyring.Set(Service, key, secret)
+}
+
+// Get returns the secret stored under the given key in the keyring.
+func Get(key string) (string, error) {
+	secret, err := keyring.Get(Service, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+
+	return secret, err
+}
+
+// Delete removes the secret stored under the given key from the keyring.
+func Delete(key string) error {
+	err := keyring.Delete(Service, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrNotFound
+	}
+
+	return err
+}
+
+// Lookup returns the secret stored under the given key in the keyring. The
+// fallback value is returned if the secret is not stored or the keyring is
+// unavailable.
+func Lookup(key string, fallback string) string {
+	secret, err := Get(key)
+	if err != nil || secret == "" {
+		return fallback
+	}
+
+	return secret
+}
+
+// Available reports whether the keyring of the operating system can be used.
+func Available() bool {
+	_, err := Get(Service)
+	return err == nil || errors.Is(err, ErrNotFound)
+}
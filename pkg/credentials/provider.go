@@ -0,0 +1,206 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrUnresolvedReference is returned when a secret reference cannot be
+	// resolved by its provider.
+	ErrUnresolvedReference = errors.New("cannot resolve secret reference")
+	// ErrInvalidReference is returned when a secret reference is malformed.
+	ErrInvalidReference = errors.New("invalid secret reference")
+)
+
+// Provider resolves the references of a secret manager, like
+// "op://Work/Jira/token", to the secrets they refer to.
+type Provider interface {
+	// Resolve returns the secret the reference refers to. The reference
+	// includes the scheme of the provider.
+	Resolve(ctx context.Context, reference string) (string, error)
+}
+
+// ProviderFunc is an adapter allowing the use of functions as providers.
+type ProviderFunc func(ctx context.Context, reference string) (string, error)
+
+// Resolve calls the function.
+func (f ProviderFunc) Resolve(ctx context.Context, reference string) (string, error) {
+	return f(ctx, reference)
+}
+
+var (
+	providersMu sync.RWMutex
+	// providers are the registered providers by the scheme of their
+	// references.
+	providers = map[string]Provider{
+		"op":     ProviderFunc(resolveOnePassword),
+		"vault":  ProviderFunc(resolveVault),
+		"aws-sm": ProviderFunc(resolveAWSSecretsManager),
+	}
+
+	resolvedMu sync.Mutex
+	// resolved caches the resolved secrets, so the secret managers are asked
+	// once per reference, as they may require unlocking every time.
+	resolved = map[string]string{}
+)
+
+// Register registers the provider resolving the references of the scheme,
+// like "op" for "op://..." references, replacing the provider registered for
+// the scheme before.
+func Register(scheme string, provider Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+
+	providers[scheme] = provider
+}
+
+// Schemes returns the schemes of the registered providers in alphabetical
+// order.
+func Schemes() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	schemes := make([]string, 0, len(providers))
+	for scheme := range providers {
+		schemes = append(schemes, scheme)
+	}
+
+	sort.Strings(schemes)
+	return schemes
+}
+
+// provider returns the provider of the reference, or nil if the value is not
+// a reference of a registered provider.
+func provider(value string) Provider {
+	i := strings.Index(value, "://")
+	if i <= 0 {
+		return nil
+	}
+
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+
+	return providers[value[:i]]
+}
+
+// IsReference reports whether the value is a reference resolved by one of the
+// registered providers.
+func IsReference(value string) bool {
+	return provider(value) != nil
+}
+
+// Resolve returns the secret the value refers to if it is a secret reference,
+// or the value itself otherwise. The resolved secrets are cached.
+func Resolve(ctx context.Context, value string) (string, error) {
+	p := provider(value)
+	if p == nil {
+		return value, nil
+	}
+
+	resolvedMu.Lock()
+	defer resolvedMu.Unlock()
+
+	if secret, ok := resolved[value]; ok {
+		return secret, nil
+	}
+
+	secret, err := p.Resolve(ctx, value)
+	if err != nil {
+		return "", err
+	}
+
+	resolved[value] = secret
+	return secret, nil
+}
+
+// splitReference splits the reference into its path and the optional field
+// following the "#" sign, like "secret/jira" and "token" for
+// "vault://secret/jira#token".
+func splitReference(reference string) (string, string) {
+	path := reference[strings.Index(reference, "://")+len("://"):]
+
+	parts := strings.SplitN(path, "#", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+
+	return parts[0], parts[1]
+}
+
+// runCLI runs the command line tool of a secret manager and returns its
+// trimmed output. Its standard error is passed through, so the tool can ask
+// for unlocking.
+func runCLI(ctx context.Context, reference string, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	// #nosec G204 -- the arguments are read from the configuration of the user.
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+
+		return "", fmt.Errorf("%w: %s: %s", ErrUnresolvedReference, reference, message)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// resolveOnePassword resolves "op://vault/item/field" references using the
+// 1Password CLI.
+func resolveOnePassword(ctx context.Context, reference string) (string, error) {
+	return runCLI(ctx, reference, "op", "read", "--no-newline", reference)
+}
+
+// resolveVault resolves "vault://path#field" references using the Vault CLI,
+// reading the field of the secret at the path of a KV engine. The address and
+// the token of the server are read from the environment by the CLI.
+func resolveVault(ctx context.Context, reference string) (string, error) {
+	path, field := splitReference(reference)
+	if path == "" || field == "" {
+		return "", fmt.Errorf("%w: %s (expected vault://path#field)", ErrInvalidReference, reference)
+	}
+
+	return runCLI(ctx, reference, "vault", "kv", "get", "-field="+field, path)
+}
+
+// resolveAWSSecretsManager resolves "aws-sm://secret-id" references using the
+// AWS CLI. If a field is given, like "aws-sm://jira#token", the secret is
+// decoded as a JSON object, and the value of the field is returned.
+func resolveAWSSecretsManager(ctx context.Context, reference string) (string, error) {
+	secretID, field := splitReference(reference)
+	if secretID == "" {
+		return "", fmt.Errorf("%w: %s (expected aws-sm://secret-id or aws-sm://secret-id#field)", ErrInvalidReference, reference)
+	}
+
+	secret, err := runCLI(ctx, reference, "aws", "secretsmanager", "get-secret-value", "--secret-id", secretID, "--query", "SecretString", "--output", "text")
+	if err != nil || field == "" {
+		return secret, err
+	}
+
+	var fields map[string]interface{}
+	if err = json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("%w: %s: the secret is not a JSON object", ErrUnresolvedReference, reference)
+	}
+
+	value, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s: field %q not found", ErrUnresolvedReference, reference, field)
+	}
+
+	return fmt.Sprint(value), nil
+}
@@ -0,0 +1,166 @@
+// Package discourse implements a minimal Discourse API client for publishing
+// sprint updates.
+package discourse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrMissingTarget is returned when neither a topic nor a category is set for
+// the post.
+var ErrMissingTarget = errors.New("discourse topic or category is required")
+
+// Client is a Discourse API client authenticating with an API key.
+type Client struct {
+	// BaseURL is the base URL of the Discourse forum.
+	BaseURL string
+	// APIKey is the API key used for authentication.
+	APIKey string
+	// Username is the user on behalf of whom the posts are created.
+	Username string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a new Client for the given forum and credentials.
+func NewClient(baseURL string, apiKey string, username string) *Client {
+	return &Client{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		APIKey:   apiKey,
+		Username: username,
+	}
+}
+
+// Post is a post to create. If TopicID is set, the post is a reply to the
+// topic, or to the post of the topic numbered ReplyToPostNumber if it is set;
+// otherwise a new topic is created in the given category.
+type Post struct {
+	Title             string `json:"title,omitempty"`
+	Raw               string `json:"raw"`
+	TopicID           int    `json:"topic_id,omitempty"`
+	Category          int    `json:"category,omitempty"`
+	ReplyToPostNumber int    `json:"reply_to_post_number,omitempty"`
+}
+
+// CreatedPost is the post returned by Discourse after creating it.
+type CreatedPost struct {
+	ID         int    `json:"id"`
+	TopicID    int    `json:"topic_id"`
+	TopicSlug  string `json:"topic_slug"`
+	PostNumber int    `json:"post_number"`
+}
+
+// errorResponse is the error returned by the Discourse API.
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// CreatePost publishes the post and returns the created post.
+func (c *Client) CreatePost(ctx context.Context, post *Post) (*CreatedPost, error) {
+	if post.TopicID == 0 && post.Category == 0 {
+		return nil, ErrMissingTarget
+	}
+
+	// Replies must not contain a title, otherwise Discourse creates a new
+	// topic instead.
+	if post.TopicID != 0 {
+		post.Title = ""
+	}
+
+	body, err := json.Marshal(post)
+	if err != nil {
+		return nil, err
+	}
+
+	var createdPost CreatedPost
+	if err = c.do(ctx, http.MethodPost, "/posts.json", body, &createdPost); err != nil {
+		return nil, err
+	}
+
+	return &createdPost, nil
+}
+
+// editRequest is the request editing a post.
+type editRequest struct {
+	Post struct {
+		Raw string `json:"raw"`
+	} `json:"post"`
+}
+
+// editResponse is the response to editing a post.
+type editResponse struct {
+	Post CreatedPost `json:"post"`
+}
+
+// EditPost replaces the content of the post of the given ID, and returns the
+// edited post.
+func (c *Client) EditPost(ctx context.Context, postID int, raw string) (*CreatedPost, error) {
+	var edit editRequest
+	edit.Post.Raw = raw
+
+	body, err := json.Marshal(edit)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp editResponse
+	if err = c.do(ctx, http.MethodPut, fmt.Sprintf("/posts/%d.json", postID), body, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp.Post, nil
+}
+
+// PostURL returns the URL of the given post.
+func (c *Client) PostURL(post *CreatedPost) string {
+	return fmt.Sprintf("%s/t/%s/%d/%d", c.BaseURL, post.TopicSlug, post.TopicID, post.PostNumber)
+}
+
+// do sends an authenticated request to the Discourse API and decodes the
+// response into v.
+func (c *Client) do(ctx context.Context, method string, path string, body []byte, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Api-Key", c.APIKey)
+	req.Header.Set("Api-Username", c.Username)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && len(errResp.Errors) > 0 {
+			return fmt.Errorf("discourse request failed with status %d: %s", resp.StatusCode, strings.Join(errResp.Errors, "; "))
+		}
+
+		return fmt.Errorf("discourse request failed with status %d", resp.StatusCode)
+	}
+
+	return json.Unmarshal(respBody, v)
+}
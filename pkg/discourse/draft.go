@@ -0,0 +1,91 @@
+package discourse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// newTopicDraftKey is the key of the draft of a new topic. Discourse keeps a
+// single draft of a new topic per user.
+const newTopicDraftKey = "new_topic"
+
+// draftData is the state of the composer saved in a draft.
+type draftData struct {
+	Reply      string `json:"reply"`
+	Title      string `json:"title,omitempty"`
+	CategoryID int    `json:"categoryId,omitempty"`
+	Action     string `json:"action"`
+	Archetype  string `json:"archetypeId"`
+}
+
+// draftRequest is the request saving a draft.
+type draftRequest struct {
+	DraftKey string `json:"draft_key"`
+	Sequence int    `json:"sequence"`
+	Data     string `json:"data"`
+}
+
+// draftResponse is the draft returned by Discourse.
+type draftResponse struct {
+	DraftSequence int `json:"draft_sequence"`
+}
+
+// draftKey returns the key of the draft of the post, which is the key of the
+// draft of the reply to the topic, or the key of the draft of a new topic.
+func draftKey(post *Post) string {
+	if post.TopicID != 0 {
+		return "topic_" + strconv.Itoa(post.TopicID)
+	}
+
+	return newTopicDraftKey
+}
+
+// SaveDraft saves the post as a draft of the user, replacing the draft of the
+// same reply or new topic, so it can be reviewed in the composer before
+// publishing it.
+func (c *Client) SaveDraft(ctx context.Context, post *Post) error {
+	if post.TopicID == 0 && post.Category == 0 {
+		return ErrMissingTarget
+	}
+
+	data := draftData{Reply: post.Raw, Action: "reply", Archetype: "regular"}
+	if post.TopicID == 0 {
+		data.Title, data.CategoryID, data.Action = post.Title, post.Category, "createTopic"
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	// The sequence of the draft is increased every time it is saved, and
+	// saving a draft of an outdated sequence is rejected.
+	key := draftKey(post)
+
+	var current draftResponse
+	if err = c.do(ctx, http.MethodGet, "/drafts/"+url.PathEscape(key)+".json", nil, &current); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(draftRequest{DraftKey: key, Sequence: current.DraftSequence, Data: string(encoded)})
+	if err != nil {
+		return err
+	}
+
+	var saved draftResponse
+	return c.do(ctx, http.MethodPost, "/drafts.json", body, &saved)
+}
+
+// DraftURL returns the URL the draft of the post can be reviewed and published
+// at: the topic the reply is drafted to, or the composer of a new topic.
+func (c *Client) DraftURL(post *Post) string {
+	if post.TopicID != 0 {
+		return fmt.Sprintf("%s/t/%d", c.BaseURL, post.TopicID)
+	}
+
+	return c.BaseURL + "/new-topic"
+}
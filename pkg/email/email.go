@@ -0,0 +1,229 @@
+// Package email implements sending sprint updates as multipart emails over
+// SMTP.
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLSMode is the way the connection to the SMTP server is secured.
+type TLSMode string
+
+const (
+	// TLSNone sends the email without encryption.
+	TLSNone TLSMode = "none"
+	// TLSStartTLS upgrades the plain connection using the STARTTLS command.
+	TLSStartTLS TLSMode = "starttls"
+	// TLSImplicit connects to the server over TLS.
+	TLSImplicit TLSMode = "tls"
+)
+
+var (
+	// ErrMissingSender is returned when no sender address is set.
+	ErrMissingSender = errors.New("email sender is required")
+	// ErrMissingRecipients is returned when no recipient address is set.
+	ErrMissingRecipients = errors.New("email recipients are required")
+	// ErrUnknownTLSMode is returned when the TLS mode is not supported.
+	ErrUnknownTLSMode = errors.New("unknown TLS mode")
+)
+
+// Client sends emails through an SMTP server.
+type Client struct {
+	// Host is the host name of the SMTP server.
+	Host string
+	// Port is the port of the SMTP server.
+	Port int
+	// TLS is the way the connection is secured. When empty, TLSStartTLS is
+	// used.
+	TLS TLSMode
+	// Username is the username used for authentication. When empty, no
+	// authentication is done.
+	Username string
+	// Password is the password used for authentication.
+	Password string
+	// From is the address of the sender.
+	From string
+	// To are the addresses of the recipients.
+	To []string
+	// TLSConfig is the base TLS configuration of the connection, like the
+	// trusted CA certificates. The server name is always set to Host.
+	TLSConfig *tls.Config
+}
+
+// Message is an email sent in both plain text and HTML.
+type Message struct {
+	Subject string
+	Text    string
+	// HTML is the HTML alternative of the text. When empty, only the plain
+	// text is sent.
+	HTML string
+}
+
+// Send sends the message to the recipients.
+func (c *Client) Send(ctx context.Context, msg *Message) error {
+	if c.From == "" {
+		return ErrMissingSender
+	}
+
+	if len(c.To) == 0 {
+		return ErrMissingRecipients
+	}
+
+	body, err := c.compose(msg)
+	if err != nil {
+		return err
+	}
+
+	client, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if c.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", c.Username, c.Password, c.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(c.From); err != nil {
+		return err
+	}
+
+	for _, to := range c.To {
+		if err = client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.Write(body); err != nil {
+		return err
+	}
+
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// dial connects to the SMTP server, securing the connection as configured.
+func (c *Client) dial(ctx context.Context) (*smtp.Client, error) {
+	mode := c.TLS
+	if mode == "" {
+		mode = TLSStartTLS
+	}
+
+	if mode != TLSNone && mode != TLSStartTLS && mode != TLSImplicit {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTLSMode, mode)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.Host, strconv.Itoa(c.Port)))
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.TLSConfig != nil {
+		tlsConfig = c.TLSConfig.Clone()
+	}
+
+	tlsConfig.ServerName = c.Host
+	if mode == TLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, c.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if mode == TLSStartTLS {
+		if err = client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// compose builds the multipart/alternative email from the message.
+func (c *Client) compose(msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + c.From,
+		"To: " + strings.Join(c.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + parts.Boundary(),
+	}
+
+	var message bytes.Buffer
+	message.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	if err := writePart(parts, "text/plain", msg.Text); err != nil {
+		return nil, err
+	}
+
+	if msg.HTML != "" {
+		if err := writePart(parts, "text/html", msg.HTML); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	message.Write(buf.Bytes())
+	return message.Bytes(), nil
+}
+
+// writePart writes the content as a quoted-printable part of the given type.
+func writePart(parts *multipart.Writer, contentType string, content string) error {
+	part, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	w := quotedprintable.NewWriter(part)
+	if _, err = w.Write([]byte(content)); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
@@ -0,0 +1,206 @@
+// Package git lists the commits authored in git repositories by running the
+// git command, so the work without pull requests or issues, like the changes
+// of the infrastructure, shows up in the update.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrGitFailed is returned when the git command exits with an error.
+	ErrGitFailed = errors.New("git command failed")
+	// ErrUnknownAuthor is returned when no author is set, and no email
+	// address is configured for git either.
+	ErrUnknownAuthor = errors.New("cannot determine the author of the commits")
+)
+
+// fieldSeparator separates the fields of the commits in the log output.
+const fieldSeparator = "\x1f"
+
+// unsafeNameChars matches the characters replaced in the directory names of
+// the mirrored remotes.
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Commit is a commit of a repository.
+type Commit struct {
+	// Repository is the name of the repository, like "example/reports".
+	Repository string
+	// Hash is the full hash of the commit.
+	Hash string
+	// Subject is the first line of the commit message.
+	Subject string
+	// Ref is the branch the commit was reached from, like
+	// "se-101-csv-export" or "origin/se-101-csv-export".
+	Ref string
+	// AuthoredAt is the time the commit was authored.
+	AuthoredAt time.Time
+}
+
+// Repository is a local clone of a repository.
+type Repository struct {
+	// Name is the name of the repository listed in the update.
+	Name string
+	// Dir is the directory of the clone.
+	Dir string
+}
+
+// IsRemote reports whether the location is the URL of a remote, like
+// "https://github.com/example/reports.git" or
+// "git@github.com:example/reports.git", rather than the path of a clone.
+func IsRemote(location string) bool {
+	if strings.Contains(location, "://") {
+		return true
+	}
+
+	at, colon := strings.Index(location, "@"), strings.Index(location, ":")
+	return at > 0 && colon > at
+}
+
+// Open returns the repository at the location. Local clones are used as they
+// are. Remotes are mirrored into the cache directory without their file
+// contents, and fetched on every call, so their branches are up to date.
+func Open(ctx context.Context, location string, cacheDir string) (*Repository, error) {
+	if !IsRemote(location) {
+		dir, err := filepath.Abs(location)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Repository{Name: filepath.Base(dir), Dir: dir}, nil
+	}
+
+	name := remoteName(location)
+	repo := &Repository{Name: name, Dir: filepath.Join(cacheDir, unsafeNameChars.ReplaceAllString(name, "_")+".git")}
+
+	if _, err := os.Stat(repo.Dir); err == nil {
+		_, err = repo.git(ctx, "fetch", "--prune", "--quiet", "origin")
+		return repo, err
+	}
+
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return nil, err
+	}
+
+	if _, err := run(ctx, "", "clone", "--mirror", "--quiet", "--filter=blob:none", location, repo.Dir); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// remoteName returns the path of the remote without its extension, like
+// "example/reports" for "git@github.com:example/reports.git".
+func remoteName(location string) string {
+	name := location
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+		if j := strings.Index(name, "/"); j >= 0 {
+			name = name[j+1:]
+		}
+	} else if i = strings.Index(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	name = strings.TrimSuffix(strings.Trim(name, "/"), ".git")
+	if name == "" {
+		return path.Base(location)
+	}
+
+	return name
+}
+
+// Commits returns the commits of the authors authored within the window on
+// every branch, leaving out the merge commits, from the newest to the oldest.
+// The authors are matched against the names and the email addresses of the
+// commit authors. When no authors are set, the email address configured for
+// git in the repository is used.
+func (r *Repository) Commits(ctx context.Context, authors []string, since time.Time, until time.Time) ([]Commit, error) {
+	if len(authors) == 0 {
+		email, err := r.git(ctx, "config", "user.email")
+		if err != nil || strings.TrimSpace(email) == "" {
+			return nil, fmt.Errorf("%w of %s: set the git authors", ErrUnknownAuthor, r.Name)
+		}
+
+		authors = []string{strings.TrimSpace(email)}
+	}
+
+	args := []string{
+		"log", "--branches", "--remotes", "--no-merges", "--source",
+		"--since=" + since.Format(time.RFC3339), "--until=" + until.Format(time.RFC3339),
+		"--format=" + strings.Join([]string{"%H", "%S", "%aI", "%s"}, fieldSeparator),
+	}
+
+	for _, author := range authors {
+		args = append(args, "--author="+regexp.QuoteMeta(author))
+	}
+
+	out, err := r.git(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, fieldSeparator, 4)
+		if len(fields) != 4 {
+			continue
+		}
+
+		authoredAt, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, Commit{
+			Repository: r.Name,
+			Hash:       fields[0],
+			Ref:        fields[1],
+			AuthoredAt: authoredAt,
+			Subject:    fields[3],
+		})
+	}
+
+	return commits, nil
+}
+
+// git runs the git command in the repository.
+func (r *Repository) git(ctx context.Context, args ...string) (string, error) {
+	return run(ctx, r.Dir, args...)
+}
+
+// run runs the git command in the directory, returning its standard output.
+// If the directory is empty, the command is run in the working directory.
+func run(ctx context.Context, dir string, args ...string) (string, error) {
+	subcommand := args[0]
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: git %s: %s", ErrGitFailed, subcommand, message)
+		}
+
+		return "", fmt.Errorf("%w: git %s: %v", ErrGitFailed, subcommand, err)
+	}
+
+	return stdout.String(), nil
+}
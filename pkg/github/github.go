@@ -0,0 +1,273 @@
+// Package github implements a minimal GitHub API client for listing the pull
+// requests of a sprint, and the issues of a milestone or a project iteration.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the GitHub API.
+const DefaultBaseURL = "https://api.github.com"
+
+// searchPageSize is the number of pull requests requested per search page.
+const searchPageSize = 100
+
+// searchTimeLayout is the layout of the date qualifiers in search queries.
+const searchTimeLayout = "2006-01-02T15:04:05Z"
+
+// ErrMissingToken is returned when no GitHub token is set.
+var ErrMissingToken = errors.New("github token is required")
+
+// Client is a GitHub API client authenticating with a personal access token.
+type Client struct {
+	// BaseURL is the base URL of the GitHub API. For GitHub Enterprise Server,
+	// it is like "https://github.example.com/api/v3".
+	BaseURL string
+	// Token is the personal access token used for authentication.
+	Token string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a new Client for the given API and token. If the base URL
+// is empty, DefaultBaseURL is used.
+func NewClient(baseURL string, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Token:   token,
+	}
+}
+
+// PullRequest is a pull request authored by the authenticated user.
+type PullRequest struct {
+	// Repository is the full name of the repository, like "owner/repo".
+	Repository string
+	Number     int
+	Title      string
+	URL        string
+	// Branch is the name of the head branch.
+	Branch    string
+	State     string
+	CreatedAt time.Time
+	// MergedAt is the time the pull request was merged, or nil if it is not
+	// merged.
+	MergedAt *time.Time
+}
+
+// IsMerged reports whether the pull request is merged.
+func (p *PullRequest) IsMerged() bool {
+	return p.MergedAt != nil
+}
+
+// Query filters the pull requests listed by SearchPullRequests.
+type Query struct {
+	// Scopes lists the repositories, like "owner/repo", and organizations the
+	// pull requests are searched in. When empty, every repository is searched.
+	Scopes []string
+	// Since is the start of the sprint window.
+	Since time.Time
+	// Until is the end of the sprint window.
+	Until time.Time
+}
+
+// qualifiers returns the search qualifiers of the query, except for the date
+// qualifiers.
+func (q *Query) qualifiers() []string {
+	qualifiers := []string{"is:pr", "author:@me"}
+
+	for _, scope := range q.Scopes {
+		if strings.Contains(scope, "/") {
+			qualifiers = append(qualifiers, "repo:"+scope)
+		} else {
+			qualifiers = append(qualifiers, "org:"+scope)
+		}
+	}
+
+	return qualifiers
+}
+
+// window returns the sprint window of the query as a search range.
+func (q *Query) window() string {
+	return q.Since.UTC().Format(searchTimeLayout) + ".." + q.Until.UTC().Format(searchTimeLayout)
+}
+
+// searchResult is a page of the issue search results.
+type searchResult struct {
+	Items []struct {
+		Number        int       `json:"number"`
+		Title         string    `json:"title"`
+		HTMLURL       string    `json:"html_url"`
+		State         string    `json:"state"`
+		RepositoryURL string    `json:"repository_url"`
+		CreatedAt     time.Time `json:"created_at"`
+		PullRequest   struct {
+			URL string `json:"url"`
+		} `json:"pull_request"`
+	} `json:"items"`
+}
+
+// pullRequestDetails holds the details of a pull request missing from the
+// search results.
+type pullRequestDetails struct {
+	Head struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	MergedAt *time.Time `json:"merged_at"`
+}
+
+// errorResponse is the error returned by the GitHub API.
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// SearchPullRequests returns the pull requests authored by the authenticated
+// user which were opened or merged during the sprint window, ordered by
+// creation time.
+func (c *Client) SearchPullRequests(ctx context.Context, query Query) ([]PullRequest, error) {
+	if c.Token == "" {
+		return nil, ErrMissingToken
+	}
+
+	var pullRequests []PullRequest
+	seen := make(map[string]bool)
+
+	for _, qualifier := range []string{"created:", "merged:"} {
+		found, err := c.search(ctx, strings.Join(append(query.qualifiers(), qualifier+query.window()), " "))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pullRequest := range found {
+			if !seen[pullRequest.URL] {
+				seen[pullRequest.URL] = true
+				pullRequests = append(pullRequests, pullRequest)
+			}
+		}
+	}
+
+	sort.SliceStable(pullRequests, func(i, j int) bool {
+		return pullRequests[i].CreatedAt.Before(pullRequests[j].CreatedAt)
+	})
+
+	return pullRequests, nil
+}
+
+// search returns every pull request matching the search query.
+func (c *Client) search(ctx context.Context, q string) ([]PullRequest, error) {
+	var pullRequests []PullRequest
+
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("q", q)
+		params.Set("sort", "created")
+		params.Set("order", "asc")
+		params.Set("per_page", fmt.Sprint(searchPageSize))
+		params.Set("page", fmt.Sprint(page))
+
+		var result searchResult
+		if err := c.get(ctx, c.BaseURL+"/search/issues?"+params.Encode(), &result); err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.Items {
+			var details pullRequestDetails
+			if err := c.get(ctx, item.PullRequest.URL, &details); err != nil {
+				return nil, err
+			}
+
+			pullRequests = append(pullRequests, PullRequest{
+				Repository: repositoryName(item.RepositoryURL),
+				Number:     item.Number,
+				Title:      item.Title,
+				URL:        item.HTMLURL,
+				Branch:     details.Head.Ref,
+				State:      item.State,
+				CreatedAt:  item.CreatedAt,
+				MergedAt:   details.MergedAt,
+			})
+		}
+
+		if len(result.Items) < searchPageSize {
+			return pullRequests, nil
+		}
+	}
+}
+
+// repositoryName returns the full name of the repository from its API URL,
+// like "https://api.github.com/repos/owner/repo".
+func repositoryName(repositoryURL string) string {
+	parts := strings.Split(repositoryURL, "/")
+	if len(parts) < 2 {
+		return repositoryURL
+	}
+
+	return strings.Join(parts[len(parts)-2:], "/")
+}
+
+// get sends an authenticated GET request to the GitHub API and decodes the
+// response into v.
+func (c *Client) get(ctx context.Context, requestURL string, v interface{}) error {
+	return c.do(ctx, http.MethodGet, requestURL, nil, v)
+}
+
+// do sends an authenticated request to the GitHub API and decodes the response
+// into v.
+func (c *Client) do(ctx context.Context, method string, requestURL string, body []byte, v interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Message != "" {
+			return fmt.Errorf("github request failed with status %d: %s", resp.StatusCode, errResp.Message)
+		}
+
+		return fmt.Errorf("github request failed with status %d", resp.StatusCode)
+	}
+
+	return json.Unmarshal(respBody, v)
+}
@@ -0,0 +1,514 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// issuesPageSize is the number of issues, milestones, or project items
+// requested per page.
+const issuesPageSize = 100
+
+// iterationDateLayout is the layout of the start dates of the iterations.
+const iterationDateLayout = "2006-01-02"
+
+var (
+	// ErrProjectNotFound is returned when the project does not exist or is
+	// not accessible with the token.
+	ErrProjectNotFound = errors.New("github project not found")
+	// ErrIterationFieldNotFound is returned when the project has no iteration
+	// field of the given name.
+	ErrIterationFieldNotFound = errors.New("github project iteration field not found")
+)
+
+// Milestone is a milestone of a repository.
+type Milestone struct {
+	Number int
+	Title  string
+	// State is either "open" or "closed".
+	State string
+	// DueOn is the due date of the milestone, or nil if it has none.
+	DueOn *time.Time
+}
+
+// Issue is an issue of a repository.
+type Issue struct {
+	// Repository is the full name of the repository, like "owner/repo".
+	Repository string
+	Number     int
+	Title      string
+	URL        string
+	// State is either "open" or "closed".
+	State string
+	// StateReason is the reason the issue was closed, like "completed" or
+	// "not_planned".
+	StateReason string
+	Assignees   []string
+	Labels      []string
+	// ClosedAt is the time the issue was closed, or nil if it is open.
+	ClosedAt *time.Time
+}
+
+// IsClosed reports whether the issue is closed.
+func (i *Issue) IsClosed() bool {
+	return strings.EqualFold(i.State, "closed")
+}
+
+// Iteration is an iteration of the iteration field of a project.
+type Iteration struct {
+	ID    string
+	Title string
+	// StartDate is the first day of the iteration.
+	StartDate time.Time
+	// Duration is the length of the iteration in days.
+	Duration int
+	// Completed indicates that the iteration is over.
+	Completed bool
+}
+
+// EndDate returns the last day of the iteration.
+func (i *Iteration) EndDate() time.Time {
+	return i.StartDate.AddDate(0, 0, i.Duration-1)
+}
+
+// ProjectItem is an issue added to a project.
+type ProjectItem struct {
+	Issue Issue
+	// IterationID is the ID of the iteration of the issue, or empty if the
+	// issue is not in an iteration.
+	IterationID string
+	// Status is the value of the status field of the issue, which is the
+	// column of the issue on the board, or empty if it is not set.
+	Status string
+}
+
+// Project identifies a project owned by a user or an organization.
+type Project struct {
+	// Owner is the login of the user or organization owning the project.
+	Owner string
+	// Number is the number of the project, as found in its URL.
+	Number int
+}
+
+// user is the authenticated user.
+type user struct {
+	Login string `json:"login"`
+}
+
+// milestone is a milestone returned by the REST API.
+type milestone struct {
+	Number int        `json:"number"`
+	Title  string     `json:"title"`
+	State  string     `json:"state"`
+	DueOn  *time.Time `json:"due_on"`
+}
+
+// issue is an issue returned by the REST API.
+type issue struct {
+	Number        int        `json:"number"`
+	Title         string     `json:"title"`
+	HTMLURL       string     `json:"html_url"`
+	State         string     `json:"state"`
+	StateReason   string     `json:"state_reason"`
+	ClosedAt      *time.Time `json:"closed_at"`
+	RepositoryURL string     `json:"repository_url"`
+	Assignees     []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// iteration is an iteration returned by the GraphQL API.
+type iteration struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	StartDate string `json:"startDate"`
+	Duration  int    `json:"duration"`
+}
+
+// projectIssue is an issue returned by the GraphQL API.
+type projectIssue struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	State       string     `json:"state"`
+	StateReason string     `json:"stateReason"`
+	ClosedAt    *time.Time `json:"closedAt"`
+	Repository  struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+	Assignees struct {
+		Nodes []struct {
+			Login string `json:"login"`
+		} `json:"nodes"`
+	} `json:"assignees"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+// graphQLResponse is the response of the GraphQL API.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+const iterationsQuery = `query($owner: String!, $number: Int!, $field: String!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        field(name: $field) {
+          ... on ProjectV2IterationField {
+            configuration {
+              iterations { id title startDate duration }
+              completedIterations { id title startDate duration }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const projectItemsQuery = `query($owner: String!, $number: Int!, $field: String!, $status: String!, $first: Int!, $after: String) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        items(first: $first, after: $after) {
+          nodes {
+            iteration: fieldValueByName(name: $field) {
+              ... on ProjectV2ItemFieldIterationValue { iterationId }
+            }
+            status: fieldValueByName(name: $status) {
+              ... on ProjectV2ItemFieldSingleSelectValue { name }
+            }
+            content {
+              ... on Issue {
+                number
+                title
+                url
+                state
+                stateReason
+                closedAt
+                repository { nameWithOwner }
+                assignees(first: 20) { nodes { login } }
+                labels(first: 50) { nodes { name } }
+              }
+            }
+          }
+          pageInfo { hasNextPage endCursor }
+        }
+      }
+    }
+  }
+}`
+
+// CurrentUser returns the login of the authenticated user.
+func (c *Client) CurrentUser(ctx context.Context) (string, error) {
+	if c.Token == "" {
+		return "", ErrMissingToken
+	}
+
+	var u user
+	if err := c.get(ctx, c.BaseURL+"/user", &u); err != nil {
+		return "", err
+	}
+
+	return u.Login, nil
+}
+
+// Milestones returns the open and closed milestones of the repository, like
+// "owner/repo", ordered by due date.
+func (c *Client) Milestones(ctx context.Context, repository string) ([]Milestone, error) {
+	if c.Token == "" {
+		return nil, ErrMissingToken
+	}
+
+	var milestones []Milestone
+
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("state", "all")
+		params.Set("sort", "due_on")
+		params.Set("direction", "asc")
+		params.Set("per_page", fmt.Sprint(issuesPageSize))
+		params.Set("page", fmt.Sprint(page))
+
+		var result []milestone
+		if err := c.get(ctx, c.BaseURL+"/repos/"+repository+"/milestones?"+params.Encode(), &result); err != nil {
+			return nil, err
+		}
+
+		for _, m := range result {
+			milestones = append(milestones, Milestone(m))
+		}
+
+		if len(result) < issuesPageSize {
+			return milestones, nil
+		}
+	}
+}
+
+// MilestoneIssues returns the open and closed issues of the milestone of the
+// repository assigned to the user of the given login. The pull requests of the
+// milestone are skipped.
+func (c *Client) MilestoneIssues(ctx context.Context, repository string, number int, assignee string) ([]Issue, error) {
+	if c.Token == "" {
+		return nil, ErrMissingToken
+	}
+
+	var issues []Issue
+
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("milestone", fmt.Sprint(number))
+		params.Set("state", "all")
+		params.Set("assignee", assignee)
+		params.Set("per_page", fmt.Sprint(issuesPageSize))
+		params.Set("page", fmt.Sprint(page))
+
+		var result []issue
+		if err := c.get(ctx, c.BaseURL+"/repos/"+repository+"/issues?"+params.Encode(), &result); err != nil {
+			return nil, err
+		}
+
+		for _, i := range result {
+			if i.PullRequest == nil {
+				issues = append(issues, newIssue(&i))
+			}
+		}
+
+		if len(result) < issuesPageSize {
+			return issues, nil
+		}
+	}
+}
+
+// ProjectIterations returns the active, upcoming, and completed iterations of
+// the iteration field of the given name of the project.
+func (c *Client) ProjectIterations(ctx context.Context, project Project, field string) ([]Iteration, error) {
+	var resp struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				Field *struct {
+					Configuration *struct {
+						Iterations          []iteration `json:"iterations"`
+						CompletedIterations []iteration `json:"completedIterations"`
+					} `json:"configuration"`
+				} `json:"field"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+
+	variables := map[string]interface{}{"owner": project.Owner, "number": project.Number, "field": field}
+	if err := c.graphQL(ctx, iterationsQuery, variables, &resp); err != nil {
+		return nil, err
+	}
+
+	if resp.RepositoryOwner == nil || resp.RepositoryOwner.ProjectV2 == nil {
+		return nil, fmt.Errorf("%w: %s/%d", ErrProjectNotFound, project.Owner, project.Number)
+	}
+
+	projectField := resp.RepositoryOwner.ProjectV2.Field
+	if projectField == nil || projectField.Configuration == nil {
+		return nil, fmt.Errorf("%w: %s", ErrIterationFieldNotFound, field)
+	}
+
+	var iterations []Iteration
+	for _, group := range []struct {
+		iterations []iteration
+		completed  bool
+	}{
+		{projectField.Configuration.Iterations, false},
+		{projectField.Configuration.CompletedIterations, true},
+	} {
+		for _, i := range group.iterations {
+			startDate, err := time.ParseInLocation(iterationDateLayout, i.StartDate, time.Local)
+			if err != nil {
+				return nil, err
+			}
+
+			iterations = append(iterations, Iteration{
+				ID:        i.ID,
+				Title:     i.Title,
+				StartDate: startDate,
+				Duration:  i.Duration,
+				Completed: group.completed,
+			})
+		}
+	}
+
+	return iterations, nil
+}
+
+// ProjectItems returns the issues added to the project with the values of
+// their iteration and status fields of the given names. The draft issues and
+// the pull requests of the project are skipped.
+func (c *Client) ProjectItems(ctx context.Context, project Project, iterationField string, statusField string) ([]ProjectItem, error) {
+	var items []ProjectItem
+	var after interface{}
+
+	for {
+		var resp struct {
+			RepositoryOwner *struct {
+				ProjectV2 *struct {
+					Items struct {
+						Nodes []struct {
+							Iteration *struct {
+								IterationID string `json:"iterationId"`
+							} `json:"iteration"`
+							Status *struct {
+								Name string `json:"name"`
+							} `json:"status"`
+							Content *projectIssue `json:"content"`
+						} `json:"nodes"`
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+					} `json:"items"`
+				} `json:"projectV2"`
+			} `json:"repositoryOwner"`
+		}
+
+		variables := map[string]interface{}{
+			"owner":  project.Owner,
+			"number": project.Number,
+			"field":  iterationField,
+			"status": statusField,
+			"first":  issuesPageSize,
+			"after":  after,
+		}
+
+		if err := c.graphQL(ctx, projectItemsQuery, variables, &resp); err != nil {
+			return nil, err
+		}
+
+		if resp.RepositoryOwner == nil || resp.RepositoryOwner.ProjectV2 == nil {
+			return nil, fmt.Errorf("%w: %s/%d", ErrProjectNotFound, project.Owner, project.Number)
+		}
+
+		page := resp.RepositoryOwner.ProjectV2.Items
+		for _, node := range page.Nodes {
+			// Draft issues and pull requests have no issue number.
+			if node.Content == nil || node.Content.Number == 0 {
+				continue
+			}
+
+			item := ProjectItem{Issue: newProjectIssue(node.Content)}
+			if node.Iteration != nil {
+				item.IterationID = node.Iteration.IterationID
+			}
+
+			if node.Status != nil {
+				item.Status = node.Status.Name
+			}
+
+			items = append(items, item)
+		}
+
+		if !page.PageInfo.HasNextPage {
+			return items, nil
+		}
+
+		after = page.PageInfo.EndCursor
+	}
+}
+
+// newIssue returns the issue from the issue returned by the REST API.
+func newIssue(i *issue) Issue {
+	transformedIssue := Issue{
+		Repository:  repositoryName(i.RepositoryURL),
+		Number:      i.Number,
+		Title:       i.Title,
+		URL:         i.HTMLURL,
+		State:       i.State,
+		StateReason: i.StateReason,
+		ClosedAt:    i.ClosedAt,
+	}
+
+	for _, assignee := range i.Assignees {
+		transformedIssue.Assignees = append(transformedIssue.Assignees, assignee.Login)
+	}
+
+	for _, label := range i.Labels {
+		transformedIssue.Labels = append(transformedIssue.Labels, label.Name)
+	}
+
+	return transformedIssue
+}
+
+// newProjectIssue returns the issue from the issue returned by the GraphQL API,
+// whose states are upper case, like "CLOSED" or "NOT_PLANNED".
+func newProjectIssue(i *projectIssue) Issue {
+	transformedIssue := Issue{
+		Repository:  i.Repository.NameWithOwner,
+		Number:      i.Number,
+		Title:       i.Title,
+		URL:         i.URL,
+		State:       strings.ToLower(i.State),
+		StateReason: strings.ToLower(i.StateReason),
+		ClosedAt:    i.ClosedAt,
+	}
+
+	for _, assignee := range i.Assignees.Nodes {
+		transformedIssue.Assignees = append(transformedIssue.Assignees, assignee.Login)
+	}
+
+	for _, label := range i.Labels.Nodes {
+		transformedIssue.Labels = append(transformedIssue.Labels, label.Name)
+	}
+
+	return transformedIssue
+}
+
+// graphQLURL returns the URL of the GraphQL API. For GitHub Enterprise Server,
+// the GraphQL API is at "/api/graphql" instead of "/api/v3/graphql".
+func (c *Client) graphQLURL() string {
+	if strings.HasSuffix(c.BaseURL, "/api/v3") {
+		return strings.TrimSuffix(c.BaseURL, "/v3") + "/graphql"
+	}
+
+	return c.BaseURL + "/graphql"
+}
+
+// graphQL sends the GraphQL query and decodes its data into v.
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]interface{}, v interface{}) error {
+	if c.Token == "" {
+		return ErrMissingToken
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	var resp graphQLResponse
+	if err = c.do(ctx, http.MethodPost, c.graphQLURL(), body, &resp); err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+
+		return fmt.Errorf("github request failed: %s", strings.Join(messages, "; "))
+	}
+
+	return json.Unmarshal(resp.Data, v)
+}
@@ -0,0 +1,208 @@
+package google
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// credentialsServiceAccount is the type of the service account keys.
+	credentialsServiceAccount = "service_account"
+	// credentialsAuthorizedUser is the type of the OAuth 2.0 user
+	// credentials, like the ones written by gcloud.
+	credentialsAuthorizedUser = "authorized_user"
+	// defaultTokenURL is the token endpoint used when the credentials do not
+	// set one.
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+	// tokenLifetime is the lifetime of the assertions of service accounts.
+	tokenLifetime = time.Hour
+	// expiryDelta is subtracted from the token expiry, so tokens are
+	// refreshed before they actually expire.
+	expiryDelta = time.Minute
+)
+
+// scopes are the scopes requested for the access tokens.
+var scopes = []string{
+	"https://www.googleapis.com/auth/drive",
+	"https://www.googleapis.com/auth/documents",
+}
+
+var (
+	// ErrUnknownCredentials is returned when the type of the credentials is
+	// not supported.
+	ErrUnknownCredentials = errors.New("unknown google credentials type")
+	// ErrInvalidPrivateKey is returned when the private key of a service
+	// account cannot be parsed.
+	ErrInvalidPrivateKey = errors.New("invalid google service account private key")
+)
+
+// Credentials are the credentials of a service account, or the OAuth 2.0
+// credentials of a user, in the JSON format used by Google Cloud.
+type Credentials struct {
+	// Type is the type of the credentials, "service_account" or
+	// "authorized_user".
+	Type string `json:"type"`
+	// ClientEmail is the email address of the service account.
+	ClientEmail string `json:"client_email"`
+	// PrivateKey is the PEM encoded private key of the service account.
+	PrivateKey string `json:"private_key"`
+	// TokenURI is the token endpoint. When empty, the default Google token
+	// endpoint is used.
+	TokenURI string `json:"token_uri"`
+	// ClientID is the client ID of the OAuth 2.0 app of the user.
+	ClientID string `json:"client_id"`
+	// ClientSecret is the client secret of the OAuth 2.0 app of the user.
+	ClientSecret string `json:"client_secret"`
+	// RefreshToken is the refresh token of the user.
+	RefreshToken string `json:"refresh_token"`
+}
+
+// tokenResponse is the response of the token endpoint.
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// ParseCredentials parses the JSON credentials of a service account or a
+// user.
+func ParseCredentials(data []byte) (*Credentials, error) {
+	var credentials Credentials
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, err
+	}
+
+	switch credentials.Type {
+	case credentialsServiceAccount, credentialsAuthorizedUser:
+		return &credentials, nil
+	default:
+		return nil, fmt.Errorf("%w: %q (available: %s, %s)", ErrUnknownCredentials, credentials.Type, credentialsServiceAccount, credentialsAuthorizedUser)
+	}
+}
+
+// token requests an access token, using a signed assertion for service
+// accounts, or the refresh token for users.
+func (c *Credentials) token(ctx context.Context, httpClient *http.Client) (string, time.Time, error) {
+	tokenURL := c.TokenURI
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+
+	params := url.Values{}
+	if c.Type == credentialsServiceAccount {
+		assertion, err := c.assertion(time.Now())
+		if err != nil {
+			return "", time.Time{}, err
+		}
+
+		params.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+		params.Set("assertion", assertion)
+	} else {
+		params.Set("grant_type", "refresh_token")
+		params.Set("client_id", c.ClientID)
+		params.Set("client_secret", c.ClientSecret)
+		params.Set("refresh_token", c.RefreshToken)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	var token tokenResponse
+	decodeErr := json.Unmarshal(body, &token)
+
+	if token.Error != "" {
+		return "", time.Time{}, fmt.Errorf("google token request failed: %s: %s", token.Error, token.Description)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", time.Time{}, fmt.Errorf("google token request failed with status %d", resp.StatusCode)
+	}
+
+	if decodeErr != nil {
+		return "", time.Time{}, decodeErr
+	}
+
+	return token.AccessToken, time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - expiryDelta), nil
+}
+
+// assertion returns the JWT assertion of the service account, signed by its
+// private key.
+func (c *Credentials) assertion(now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(c.PrivateKey))
+	if block == nil {
+		return "", ErrInvalidPrivateKey
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return "", fmt.Errorf("%w: %s", ErrInvalidPrivateKey, err)
+		}
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", ErrInvalidPrivateKey
+	}
+
+	tokenURL := c.TokenURI
+	if tokenURL == "" {
+		tokenURL = defaultTokenURL
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   c.ClientEmail,
+		"scope": strings.Join(scopes, " "),
+		"aud":   tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(tokenLifetime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,367 @@
+// Package google implements minimal Google Drive and Docs API clients for
+// archiving sprint updates as documents of a Drive folder.
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	// DefaultDriveURL is the base URL of the Drive API.
+	DefaultDriveURL = "https://www.googleapis.com/drive/v3"
+	// DefaultDocsURL is the base URL of the Docs API.
+	DefaultDocsURL = "https://docs.googleapis.com/v1"
+	// documentMimeType is the MIME type of Google Docs documents.
+	documentMimeType = "application/vnd.google-apps.document"
+	// bulletPreset is the glyphs of the bulleted lists.
+	bulletPreset = "BULLET_DISC_CIRCLE_SQUARE"
+)
+
+// ErrMissingFolder is returned when no Drive folder is set.
+var ErrMissingFolder = errors.New("google drive folder ID is required")
+
+// queryEscaper escapes the string literals of Drive search queries.
+var queryEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// Client is a Google Drive and Docs API client.
+type Client struct {
+	// Credentials are the credentials the access tokens are requested with.
+	Credentials *Credentials
+	// DriveURL is the base URL of the Drive API. When empty, DefaultDriveURL
+	// is used.
+	DriveURL string
+	// DocsURL is the base URL of the Docs API. When empty, DefaultDocsURL is
+	// used.
+	DocsURL string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	accessToken string
+	expiry      time.Time
+}
+
+// File is a file of Google Drive.
+type File struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	WebViewLink string `json:"webViewLink"`
+}
+
+// document is a Google Docs document, with the end indexes of its body
+// elements.
+type document struct {
+	Body struct {
+		Content []struct {
+			EndIndex int `json:"endIndex"`
+		} `json:"content"`
+	} `json:"body"`
+}
+
+// errorResponse is the error returned by the APIs.
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// FindDocument returns the document of the folder with the given name. If
+// there is no such document, nil is returned.
+func (c *Client) FindDocument(ctx context.Context, folderID string, name string) (*File, error) {
+	if folderID == "" {
+		return nil, ErrMissingFolder
+	}
+
+	query := url.Values{}
+	query.Set("q", fmt.Sprintf("name = '%s' and '%s' in parents and mimeType = '%s' and trashed = false", queryEscaper.Replace(name), queryEscaper.Replace(folderID), documentMimeType))
+	query.Set("fields", "files(id,name,webViewLink)")
+	query.Set("supportsAllDrives", "true")
+	query.Set("includeItemsFromAllDrives", "true")
+
+	var resp struct {
+		Files []File `json:"files"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, c.driveURL()+"/files?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Files) == 0 {
+		return nil, nil
+	}
+
+	return &resp.Files[0], nil
+}
+
+// CreateDocument creates an empty document in the folder with the given name.
+func (c *Client) CreateDocument(ctx context.Context, folderID string, name string) (*File, error) {
+	if folderID == "" {
+		return nil, ErrMissingFolder
+	}
+
+	query := url.Values{}
+	query.Set("fields", "id,name,webViewLink")
+	query.Set("supportsAllDrives", "true")
+
+	var file File
+	if err := c.do(ctx, http.MethodPost, c.driveURL()+"/files?"+query.Encode(), map[string]interface{}{
+		"name":     name,
+		"mimeType": documentMimeType,
+		"parents":  []string{folderID},
+	}, &file); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// AppendParagraphs appends the paragraphs to the end of the document.
+func (c *Client) AppendParagraphs(ctx context.Context, documentID string, paragraphs []Paragraph) error {
+	return c.write(ctx, documentID, paragraphs, false)
+}
+
+// ReplaceParagraphs replaces the content of the document with the
+// paragraphs.
+func (c *Client) ReplaceParagraphs(ctx context.Context, documentID string, paragraphs []Paragraph) error {
+	return c.write(ctx, documentID, paragraphs, true)
+}
+
+// write inserts the paragraphs at the end of the document, deleting its
+// content first if replace is set.
+func (c *Client) write(ctx context.Context, documentID string, paragraphs []Paragraph, replace bool) error {
+	var doc document
+	if err := c.do(ctx, http.MethodGet, c.docsURL()+"/documents/"+url.PathEscape(documentID)+"?fields=body.content(endIndex)", nil, &doc); err != nil {
+		return err
+	}
+
+	// The body always ends with a newline, which cannot be deleted.
+	end := 1
+	if content := doc.Body.Content; len(content) > 0 {
+		end = content[len(content)-1].EndIndex - 1
+	}
+
+	var requests []map[string]interface{}
+	if replace && end > 1 {
+		requests = append(requests, map[string]interface{}{
+			"deleteContentRange": map[string]interface{}{"range": textRange(1, end)},
+		})
+		end = 1
+	}
+
+	requests = append(requests, insertRequests(end, end > 1, paragraphs)...)
+
+	return c.do(ctx, http.MethodPost, c.docsURL()+"/documents/"+url.PathEscape(documentID)+":batchUpdate", map[string]interface{}{
+		"requests": requests,
+	}, nil)
+}
+
+// insertRequests returns the batch update requests inserting the paragraphs
+// at the index, and styling them. If separate is set, the paragraphs are
+// started on a new line, as the text at the index is not empty.
+func insertRequests(index int, separate bool, paragraphs []Paragraph) []map[string]interface{} {
+	insertAt := index
+
+	var text strings.Builder
+	if separate {
+		text.WriteString("\n")
+		index++
+	}
+
+	var styles []map[string]interface{}
+	var bullets []map[string]int
+	start := index
+	previousBullet := false
+
+	for _, paragraph := range paragraphs {
+		paragraphStart := index
+		if paragraph.Bullet {
+			line := strings.Repeat("\t", paragraph.Level)
+			text.WriteString(line)
+			index += length(line)
+		}
+
+		for _, run := range paragraph.Runs {
+			text.WriteString(run.Text)
+
+			runStart := index
+			index += length(run.Text)
+
+			if run.URL == "" && !run.Bold && !run.Italic {
+				continue
+			}
+
+			textStyle := map[string]interface{}{"bold": run.Bold, "italic": run.Italic}
+			if run.URL != "" {
+				textStyle["link"] = map[string]string{"url": run.URL}
+			}
+
+			styles = append(styles, map[string]interface{}{
+				"updateTextStyle": map[string]interface{}{
+					"range":     textRange(runStart, index),
+					"textStyle": textStyle,
+					"fields":    "bold,italic,link",
+				},
+			})
+		}
+
+		text.WriteString("\n")
+		index++
+
+		style := paragraph.Style
+		if style == "" {
+			style = StyleNormal
+		}
+
+		styles = append(styles, map[string]interface{}{
+			"updateParagraphStyle": map[string]interface{}{
+				"range":          textRange(paragraphStart, index),
+				"paragraphStyle": map[string]string{"namedStyleType": style},
+				"fields":         "namedStyleType",
+			},
+		})
+
+		// The consecutive list items are created as one list, so their
+		// nesting levels are kept.
+		switch {
+		case paragraph.Bullet && previousBullet:
+			bullets[len(bullets)-1]["endIndex"] = index
+		case paragraph.Bullet:
+			bullets = append(bullets, textRange(paragraphStart, index))
+		}
+
+		previousBullet = paragraph.Bullet
+	}
+
+	requests := []map[string]interface{}{
+		{"insertText": map[string]interface{}{
+			"location": map[string]int{"index": insertAt},
+			"text":     text.String(),
+		}},
+		{"deleteParagraphBullets": map[string]interface{}{"range": textRange(start, index)}},
+		{"updateTextStyle": map[string]interface{}{
+			"range":     textRange(start, index),
+			"textStyle": map[string]interface{}{},
+			"fields":    "bold,italic,link",
+		}},
+	}
+
+	requests = append(requests, styles...)
+
+	// Creating the bullets removes the tabs setting the nesting levels, which
+	// shifts the indexes of the following text, hence the bullets are
+	// created from the end of the text.
+	for i := len(bullets) - 1; i >= 0; i-- {
+		requests = append(requests, map[string]interface{}{
+			"createParagraphBullets": map[string]interface{}{
+				"range":        bullets[i],
+				"bulletPreset": bulletPreset,
+			},
+		})
+	}
+
+	return requests
+}
+
+// textRange returns the range of the body between the indexes.
+func textRange(start int, end int) map[string]int {
+	return map[string]int{"startIndex": start, "endIndex": end}
+}
+
+// length returns the length of the text in UTF-16 code units, the unit of
+// the indexes of documents.
+func length(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// driveURL returns the base URL of the Drive API.
+func (c *Client) driveURL() string {
+	if c.DriveURL == "" {
+		return DefaultDriveURL
+	}
+
+	return strings.TrimSuffix(c.DriveURL, "/")
+}
+
+// docsURL returns the base URL of the Docs API.
+func (c *Client) docsURL() string {
+	if c.DocsURL == "" {
+		return DefaultDocsURL
+	}
+
+	return strings.TrimSuffix(c.DocsURL, "/")
+}
+
+// do sends an authenticated request and decodes the response into v, unless
+// v is nil.
+func (c *Client) do(ctx context.Context, method string, requestURL string, payload interface{}, v interface{}) error {
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if c.accessToken == "" || time.Now().After(c.expiry) {
+		accessToken, expiry, err := c.Credentials.token(ctx, httpClient)
+		if err != nil {
+			return err
+		}
+
+		c.accessToken, c.expiry = accessToken, expiry
+	}
+
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+			return fmt.Errorf("google request failed with status %d: %s", resp.StatusCode, errResp.Error.Message)
+		}
+
+		return fmt.Errorf("google request failed with status %d", resp.StatusCode)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.Unmarshal(respBody, v)
+}
@@ -0,0 +1,465 @@
+package google
+
+import (
+	"fmt"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+const (
+	// StyleNormal is the named style of the normal paragraphs.
+	StyleNormal = "NORMAL_TEXT"
+	// StyleHeading1 is the named style of the first level headings.
+	StyleHeading1 = "HEADING_1"
+	// StyleHeading3 is the named style of the third level headings.
+	StyleHeading3 = "HEADING_3"
+)
+
+// Paragraph is a paragraph of a document, like a heading or a list item.
+type Paragraph struct {
+	// Style is the named style of the paragraph, like StyleHeading3. When
+	// empty, StyleNormal is used.
+	Style string
+	// Bullet indicates that the paragraph is a bulleted list item.
+	Bullet bool
+	// Level is the nesting level of the list item, starting from zero.
+	Level int
+	// Runs are the text of the paragraph.
+	Runs []Run
+}
+
+// Run is a part of the text of a paragraph sharing the same style.
+type Run struct {
+	Text   string
+	URL    string
+	Bold   bool
+	Italic bool
+}
+
+// HeadingParagraph returns a heading paragraph of the given style.
+func HeadingParagraph(style string, text string) Paragraph {
+	return Paragraph{Style: style, Runs: []Run{{Text: text}}}
+}
+
+// textParagraph returns a normal paragraph of the runs.
+func textParagraph(runs ...Run) Paragraph {
+	return Paragraph{Runs: runs}
+}
+
+// bulletParagraph returns a list item of the runs at the nesting level.
+func bulletParagraph(level int, runs ...Run) Paragraph {
+	return Paragraph{Bullet: true, Level: level, Runs: runs}
+}
+
+// issueRuns returns the link of the issue followed by a dash and its summary.
+// If the issue has no key, only the summary is returned.
+func issueRuns(key string, url string, summary string) []Run {
+	if key == "" {
+		return []Run{{Text: summary}}
+	}
+
+	return []Run{{Text: key, URL: url}, {Text: " - " + summary}}
+}
+
+// issueParagraph returns the list item of the issue.
+func issueParagraph(update *report.Update, issue *report.Issue, withAssignee bool) Paragraph {
+	summary := issue.Summary
+	if issue.Change != "" {
+		summary += fmt.Sprintf(" (%s)", issue.ChangeNote())
+	}
+
+	if withAssignee {
+		summary += fmt.Sprintf(" (%s)", issue.Assignee)
+	}
+
+	if len(issue.BlockedBy) > 0 {
+		summary += fmt.Sprintf(" (%s)", update.T("blocked by %s", strings.Join(issue.BlockedBy, ", ")))
+	}
+
+	if issue.Flagged {
+		summary += fmt.Sprintf(" (%s)", update.T("flagged"))
+	}
+
+	if issue.BlockedReason != "" {
+		summary += ": " + issue.BlockedReason
+	}
+
+	return bulletParagraph(0, issueRuns(issue.Key, issue.URL, summary)...)
+}
+
+// groupParagraphs returns the paragraphs of every issue group, headed by the
+// name of the group like the collapsible details of the Discourse format.
+func groupParagraphs(update *report.Update, issues report.Issues) []Paragraph {
+	var paragraphs []Paragraph
+
+	for _, group := range update.Groups(issues) {
+		name := group.Name
+		if update.Decorated && group.Emoji != "" {
+			name = group.Emoji + " " + name
+		}
+
+		header := []Run{{Text: name, Italic: true}}
+		if group.StoryPoints > 0 {
+			header = append(header, Run{Text: fmt.Sprintf(" (%s pts)", report.FormatPoints(group.StoryPoints))})
+		}
+
+		paragraphs = append(paragraphs, textParagraph(header...))
+
+		for i := range group.Issues {
+			issue := &group.Issues[i]
+
+			paragraph := issueParagraph(update, issue, false)
+			if group.Status == "" {
+				paragraph.Runs = append(paragraph.Runs, Run{Text: fmt.Sprintf(" (%s)", issue.Status)})
+			}
+
+			if issue.TimeSpent > 0 {
+				paragraph.Runs = append(paragraph.Runs, Run{Text: fmt.Sprintf(" (%s)", report.FormatHours(issue.TimeSpent))})
+			}
+
+			if progress := issue.SubtaskProgress(); progress != "" {
+				paragraph.Runs = append(paragraph.Runs, Run{Text: fmt.Sprintf(" (%s)", progress)})
+			}
+
+			if len(issue.Annotations) > 0 {
+				paragraph.Runs = append(paragraph.Runs, Run{Text: fmt.Sprintf(" (%s)", strings.Join(issue.Annotations, ", "))})
+			}
+
+			paragraphs = append(paragraphs, paragraph)
+
+			if issue.Note != "" {
+				paragraphs = append(paragraphs, bulletParagraph(1, Run{Text: issue.Note}))
+			}
+
+			for _, subtask := range issue.Subtasks {
+				paragraphs = append(paragraphs, bulletParagraph(1, issueRuns(subtask.Key, subtask.URL, fmt.Sprintf("%s (%s)", subtask.Summary, subtask.Status))...))
+			}
+		}
+
+		if group.Folded > 0 {
+			paragraphs = append(paragraphs, bulletParagraph(0, Run{Text: group.FoldedText, URL: group.FoldedURL}))
+		}
+	}
+
+	return paragraphs
+}
+
+// listParagraphs returns a titled list of the issues of every status. If
+// there are no issues, the empty message is listed instead, unless it is
+// empty too.
+func listParagraphs(update *report.Update, title string, issues report.Issues, empty string) []Paragraph {
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, title)}
+
+	for _, group := range update.Groups(issues) {
+		for i := range group.Issues {
+			paragraphs = append(paragraphs, issueParagraph(update, &group.Issues[i], len(update.Members) > 0))
+		}
+	}
+
+	if len(paragraphs) == 1 {
+		if empty == "" {
+			return nil
+		}
+
+		paragraphs = append(paragraphs, textParagraph(Run{Text: empty}))
+	}
+
+	return paragraphs
+}
+
+// summaryParagraphs returns the summary section, if the summary is written.
+func summaryParagraphs(update *report.Update) []Paragraph {
+	if update.Summary == "" {
+		return nil
+	}
+
+	return append([]Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionSummary), "Summary"))}, TextParagraphs(update.Summary)...)
+}
+
+// themeParagraphs returns the themes section, if there are themes.
+func themeParagraphs(update *report.Update) []Paragraph {
+	if len(update.Themes) == 0 {
+		return nil
+	}
+
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionThemes), "Themes"))}
+
+	for i := range update.Themes {
+		theme := &update.Themes[i]
+
+		var progress string
+		if theme.Total > 0 {
+			progress = update.T("%s%% done", report.FormatPoints(theme.DonePercentage())) + ", "
+		}
+
+		if theme.Issues == 1 {
+			progress += update.T("%d issue", theme.Issues)
+		} else {
+			progress += update.T("%d issues", theme.Issues)
+		}
+
+		runs := append(issueRuns(theme.Key, theme.URL, theme.Name), Run{Text: ": " + progress})
+		paragraphs = append(paragraphs, bulletParagraph(0, runs...))
+	}
+
+	return paragraphs
+}
+
+// workedOnParagraphs returns the worked on section, split by member or
+// project if set.
+func workedOnParagraphs(update *report.Update) []Paragraph {
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionWorkedOn), "Worked on"))}
+
+	if update.StoryPoints {
+		paragraphs = append(paragraphs, textParagraph(Run{Text: update.T(
+			"Done: %s pts of %s committed",
+			report.FormatPoints(update.DonePoints()),
+			report.FormatPoints(update.CommittedPoints()),
+		)}))
+	}
+
+	switch {
+	case len(update.Members) > 0:
+		for _, member := range update.Members {
+			paragraphs = append(paragraphs, textParagraph(Run{Text: member.Name, Bold: true}))
+			paragraphs = append(paragraphs, groupParagraphs(update, member.Issues)...)
+		}
+	case len(update.Projects) > 0:
+		for i := range update.Projects {
+			project := &update.Projects[i]
+
+			count := update.T("%d issues", project.Count())
+			if project.Count() == 1 {
+				count = update.T("%d issue", project.Count())
+			}
+
+			paragraphs = append(paragraphs, textParagraph(Run{Text: project.Name, Bold: true}, Run{Text: fmt.Sprintf(" (%s)", count)}))
+
+			if update.StoryPoints {
+				paragraphs = append(paragraphs, textParagraph(Run{Text: update.T(
+					"Done: %s pts of %s committed",
+					report.FormatPoints(project.DonePoints()),
+					report.FormatPoints(project.CommittedPoints()),
+				)}))
+			}
+
+			paragraphs = append(paragraphs, groupParagraphs(update, project.Issues)...)
+		}
+	default:
+		paragraphs = append(paragraphs, groupParagraphs(update, update.Issues)...)
+	}
+
+	return paragraphs
+}
+
+// dependencyParagraphs returns the dependencies on other teams section, if
+// there are dependencies.
+func dependencyParagraphs(update *report.Update) []Paragraph {
+	if len(update.Dependencies) == 0 {
+		return nil
+	}
+
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionDependencies), "Dependencies on other teams"))}
+
+	for _, dependency := range update.Dependencies {
+		runs := issueRuns(dependency.Key, dependency.URL, dependency.Summary)
+		runs = append(runs, Run{Text: ": " + dependency.Link.Relation + " "})
+		runs = append(runs, issueRuns(dependency.Link.Key, dependency.Link.URL, dependency.Link.Summary)...)
+
+		if dependency.Link.Status != "" {
+			runs = append(runs, Run{Text: fmt.Sprintf(" (%s)", dependency.Link.Status)})
+		}
+
+		paragraphs = append(paragraphs, bulletParagraph(0, runs...))
+	}
+
+	return paragraphs
+}
+
+// pullRequestParagraphs returns the pull requests section, if there are pull
+// requests.
+func pullRequestParagraphs(update *report.Update) []Paragraph {
+	if len(update.PullRequests) == 0 {
+		return nil
+	}
+
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionPullRequests), "Pull requests"))}
+
+	for _, pr := range update.PullRequests {
+		runs := []Run{{Text: fmt.Sprintf("%s#%d", pr.Repository, pr.Number), URL: pr.URL}, {Text: " - " + pr.Title}}
+		if pr.Merged {
+			runs = append(runs, Run{Text: fmt.Sprintf(" (%s)", update.T("merged"))})
+		}
+
+		for i, issue := range pr.Issues {
+			separator := ", "
+			if i == 0 {
+				separator = " - "
+			}
+
+			runs = append(runs, Run{Text: separator}, Run{Text: issue.Key, URL: issue.URL})
+		}
+
+		paragraphs = append(paragraphs, bulletParagraph(0, runs...))
+	}
+
+	return paragraphs
+}
+
+// commitParagraphs returns the commits section, if there are commits,
+// listing the commits under the issues they reference.
+func commitParagraphs(update *report.Update) []Paragraph {
+	if len(update.Commits) == 0 {
+		return nil
+	}
+
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionCommits), "Commits"))}
+
+	for _, group := range update.Commits {
+		switch {
+		case group.Key == "":
+			paragraphs = append(paragraphs, textParagraph(Run{Text: update.T("No issue"), Italic: true}))
+		case group.Summary != "":
+			paragraphs = append(paragraphs, textParagraph(Run{Text: group.Key, URL: group.URL}, Run{Text: " - " + group.Summary}))
+		default:
+			paragraphs = append(paragraphs, textParagraph(Run{Text: group.Key, URL: group.URL}))
+		}
+
+		for i := range group.Commits {
+			commit := &group.Commits[i]
+			paragraphs = append(paragraphs, bulletParagraph(0, Run{Text: fmt.Sprintf("%s@%s - %s", commit.Repository, commit.ShortHash(), commit.Subject)}))
+		}
+	}
+
+	return paragraphs
+}
+
+// timesheetParagraphs returns the section of the hours logged in Tempo, if
+// Tempo is used, listing the done issues without logged time.
+func timesheetParagraphs(update *report.Update) []Paragraph {
+	if update.Timesheet == nil {
+		return nil
+	}
+
+	total := update.T("%s logged", report.FormatHours(update.Timesheet.Total))
+	if update.Timesheet.Approved {
+		total += fmt.Sprintf(" (%s)", update.T("approved"))
+	}
+
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionHours), "Hours")), textParagraph(Run{Text: total})}
+	if len(update.Timesheet.Unlogged) > 0 {
+		paragraphs = append(paragraphs, textParagraph(Run{Text: update.T("Done without logged time:")}))
+	}
+
+	for _, issue := range update.Timesheet.Unlogged {
+		paragraphs = append(paragraphs, bulletParagraph(0, issueRuns(issue.Key, issue.URL, issue.Summary)...))
+	}
+
+	return paragraphs
+}
+
+// kudosParagraphs returns the kudos section, listing a placeholder if there
+// are no kudos.
+func kudosParagraphs(update *report.Update) []Paragraph {
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionKudos), "Kudos"))}
+
+	for _, k := range update.KudosOrPlaceholder() {
+		paragraphs = append(paragraphs, bulletParagraph(0, Run{Text: k}))
+	}
+
+	for _, k := range update.SuggestedKudos {
+		paragraphs = append(paragraphs, bulletParagraph(0, Run{Text: fmt.Sprintf("%s (%s)", k.Name, update.T("suggested: %s", update.KudosReason(k)))}))
+	}
+
+	return paragraphs
+}
+
+// statsParagraphs returns the velocity of the sprint, if the sprint report
+// is read, and the cycle time of the issues, if their timeline is read.
+func statsParagraphs(update *report.Update) []Paragraph {
+	stats, cycleTime := update.Stats, update.CycleTime
+	if stats == nil && cycleTime == nil {
+		return nil
+	}
+
+	paragraphs := []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(report.SectionStats), "Velocity"))}
+
+	if stats != nil {
+		paragraphs = append(paragraphs,
+			bulletParagraph(0, Run{Text: update.T("%s of %s committed pts completed (%s%%)", report.FormatPoints(stats.CompletedPoints), report.FormatPoints(stats.CommittedPoints), report.FormatPoints(stats.CompletionPercentage()))}),
+			bulletParagraph(0, Run{Text: update.T("%d issues completed, %d not completed, %d added after the start", stats.CompletedIssues, stats.NotCompletedIssues, stats.AddedIssues)}),
+		)
+	}
+
+	if cycleTime != nil {
+		paragraphs = append(paragraphs, bulletParagraph(0, Run{Text: update.T("%d issues done in %s days on average, %s days at the median", cycleTime.Issues, report.FormatPoints(cycleTime.AverageDays()), report.FormatPoints(cycleTime.MedianDays()))}))
+	}
+
+	return paragraphs
+}
+
+// sectionParagraphs returns the paragraphs of the section of the update, or
+// nil if the section is left out, like the pull requests section without
+// pull requests.
+func sectionParagraphs(update *report.Update, section report.Section) []Paragraph {
+	switch section {
+	case report.SectionSummary:
+		return summaryParagraphs(update)
+	case report.SectionThemes:
+		return themeParagraphs(update)
+	case report.SectionWorkedOn:
+		return workedOnParagraphs(update)
+	case report.SectionBlocked:
+		return listParagraphs(update, update.Heading(string(section), "Blocked / Needs help"), update.Blocked, "")
+	case report.SectionDependencies:
+		return dependencyParagraphs(update)
+	case report.SectionPullRequests:
+		return pullRequestParagraphs(update)
+	case report.SectionCommits:
+		return commitParagraphs(update)
+	case report.SectionHours:
+		return timesheetParagraphs(update)
+	case report.SectionSpillovers:
+		return listParagraphs(update, update.Heading(string(section), "Spillovers"), update.Spillovers, update.T("No spillovers in this sprint."))
+	case report.SectionCarriedOver:
+		return listParagraphs(update, update.Heading(string(section), "Carried over from %s", update.CarriedOverFrom), update.CarriedOver, "")
+	case report.SectionKudos:
+		return kudosParagraphs(update)
+	case report.SectionTimeOff:
+		return []Paragraph{HeadingParagraph(StyleHeading3, update.Heading(string(section), "Time off")), textParagraph(Run{Text: update.TimeOffText()})}
+	case report.SectionStats:
+		return statsParagraphs(update)
+	}
+
+	return nil
+}
+
+// NewParagraphs returns the sprint update as the paragraphs of a document,
+// having the sections of the update in their order. The title of the update
+// is the name of the document, hence it is not repeated.
+func NewParagraphs(update *report.Update) []Paragraph {
+	var paragraphs []Paragraph
+
+	for _, section := range update.OrderedSections() {
+		paragraphs = append(paragraphs, sectionParagraphs(update, section)...)
+	}
+
+	if note := update.ProvenanceNote(); note != "" {
+		paragraphs = append(paragraphs, textParagraph(Run{Text: note, Italic: true}))
+	}
+
+	return paragraphs
+}
+
+// TextParagraphs returns the text as normal paragraphs, one per line, like
+// the edited text of the update.
+func TextParagraphs(text string) []Paragraph {
+	var paragraphs []Paragraph
+
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		paragraphs = append(paragraphs, textParagraph(Run{Text: line}))
+	}
+
+	return paragraphs
+}
@@ -0,0 +1,235 @@
+// Package history archives the generated sprint updates, so the updates can
+// be browsed later and compared to the previous ones of the sprint.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// entryTimeLayout is the layout of the creation time in the entry file names,
+// which keeps the files of a sprint sorted by their creation.
+const entryTimeLayout = "20060102T150405.000000000"
+
+// Entry is a generated sprint update.
+type Entry struct {
+	// Sprint is the name of the sprint the update was generated for.
+	Sprint string `json:"sprint"`
+	// Title is the title of the update.
+	Title string `json:"title"`
+	// EndOfSprint indicates that the update is an end of sprint update.
+	EndOfSprint bool `json:"end_of_sprint"`
+	// Created is the time the update was generated at.
+	Created time.Time `json:"created"`
+	// Format is the format the update was rendered in.
+	Format string `json:"format"`
+	// Output is the rendered update.
+	Output string `json:"output"`
+	// Issues lists the issues of the update, grouped by status.
+	Issues report.Issues `json:"issues"`
+	// DiscoursePost is the Discourse post the update was posted as. It is nil
+	// if the update was not posted to Discourse.
+	DiscoursePost *DiscoursePost `json:"discourse_post,omitempty"`
+	// Signature is the detached GPG or minisign signature of the output. It
+	// is empty if the update was not signed.
+	Signature string `json:"signature,omitempty"`
+}
+
+// DiscoursePost is a Discourse post an update was posted as.
+type DiscoursePost struct {
+	// ID is the ID of the post.
+	ID int `json:"id"`
+	// TopicID is the ID of the topic of the post.
+	TopicID int `json:"topic_id"`
+	// PostNumber is the number of the post within its topic.
+	PostNumber int `json:"post_number"`
+	// URL is the URL of the post.
+	URL string `json:"url"`
+}
+
+// sprintDir returns the directory the entries of the sprint are saved in.
+func sprintDir(dir string, sprint string) string {
+	return filepath.Join(dir, SanitizeName(sprint))
+}
+
+// SanitizeName replaces the characters of the name that are not allowed in
+// file names on any platform, including the separators of the paths, so the
+// name can be used as a file name.
+func SanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+
+		return r
+	}, name)
+}
+
+// Save writes the entry to the history directory, creating the directory if
+// necessary. Saving the entry again overwrites the entry saved before.
+func Save(dir string, entry *Entry) error {
+	path := filepath.Join(sprintDir(dir, entry.Sprint), entry.Created.UTC().Format(entryTimeLayout)+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// Sprints returns the names of the sprints having entries, in alphabetical
+// order.
+func Sprints(dir string) ([]string, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var sprints []string
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+
+		paths, err := entryPaths(dir, file.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if len(paths) == 0 {
+			continue
+		}
+
+		// The directory names are sanitized, so the name is read from the
+		// entries.
+		entry, err := load(paths[0])
+		if err != nil {
+			return nil, err
+		}
+
+		sprints = append(sprints, entry.Sprint)
+	}
+
+	sort.Strings(sprints)
+	return sprints, nil
+}
+
+// Entries returns the entries of the sprint, ordered by their creation.
+func Entries(dir string, sprint string) ([]Entry, error) {
+	paths, err := entryPaths(dir, sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(paths))
+	for _, path := range paths {
+		entry, err := load(path)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, *entry)
+	}
+
+	return entries, nil
+}
+
+// Latest returns the entry of the sprint generated last. If the sprint has no
+// entries, nil is returned.
+func Latest(dir string, sprint string) (*Entry, error) {
+	paths, err := entryPaths(dir, sprint)
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+
+	return load(paths[len(paths)-1])
+}
+
+// Final returns the last end of sprint entry of the sprint, which summarizes
+// the sprint best, or the entry generated last if the sprint has no end of
+// sprint entries. If the sprint has no entries, nil is returned.
+func Final(dir string, sprint string) (*Entry, error) {
+	entries, err := Entries(dir, sprint)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].EndOfSprint {
+			return &entries[i], nil
+		}
+	}
+
+	return &entries[len(entries)-1], nil
+}
+
+// LatestDiscoursePost returns the Discourse post of the entry of the sprint
+// posted to Discourse last. If no entries of the sprint were posted to
+// Discourse, nil is returned.
+func LatestDiscoursePost(dir string, sprint string) (*DiscoursePost, error) {
+	entries, err := Entries(dir, sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].DiscoursePost != nil {
+			return entries[i].DiscoursePost, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// entryPaths returns the paths of the entry files of the sprint, ordered by
+// their creation.
+func entryPaths(dir string, sprint string) ([]string, error) {
+	files, err := os.ReadDir(sprintDir(dir, sprint))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var paths []string
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".json" {
+			paths = append(paths, filepath.Join(sprintDir(dir, sprint), file.Name()))
+		}
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// load reads the entry from the file at the given path.
+func load(path string) (*Entry, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var entry Entry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
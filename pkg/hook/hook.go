@@ -0,0 +1,66 @@
+// Package hook runs the external commands hooked into generating the sprint
+// update, so the issues and the rendered update can be filtered and extended
+// without modifying the program.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Stage is the stage of generating the sprint update a hook is run at.
+type Stage string
+
+const (
+	// PreFetch hooks are run before the issues are fetched from Jira.
+	PreFetch Stage = "pre-fetch"
+	// PostFetch hooks are run after the issues are fetched. They receive the
+	// issues as JSON on the standard input and may print the modified issues.
+	PostFetch Stage = "post-fetch"
+	// PostRender hooks are run after the update is rendered. They receive the
+	// rendered update on the standard input and may print the modified update.
+	PostRender Stage = "post-render"
+)
+
+// ErrHookFailed is returned when a hook exits with an error.
+var ErrHookFailed = errors.New("hook failed")
+
+// Hooks lists the commands run at every stage, in the order they are run. The
+// arguments of the commands are separated by whitespace.
+type Hooks struct {
+	PreFetch   []string
+	PostFetch  []string
+	PostRender []string
+}
+
+// Run runs the command of the hook at the given stage, writing the input to
+// its standard input, and returns its standard output. Its standard error is
+// passed through. Besides the environment of the program, the command
+// receives the stage in the SPRINT_UPDATE_HOOK environment variable, and the
+// given variables, like "KEY=value".
+func Run(ctx context.Context, stage Stage, command string, input []byte, env ...string) ([]byte, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: empty %s command", ErrHookFailed, stage)
+	}
+
+	var stdout bytes.Buffer
+
+	// #nosec G204 -- the hooks are configured by the user running the command.
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(append(os.Environ(), "SPRINT_UPDATE_HOOK="+string(stage)), env...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w: %s hook %q: %v", ErrHookFailed, stage, command, err)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,166 @@
+package i18n
+
+// catalogs maps the codes of the languages to the translations of the English
+// messages of the built-in templates.
+var catalogs = map[string]map[string]string{
+	"de": {
+		"Mid-sprint":                    "Sprint-Mitte",
+		"End of sprint":                 "Sprint-Ende",
+		"Worked on":                     "Woran ich gearbeitet habe",
+		"Done: %s pts of %s committed":  "Erledigt: %s von %s zugesagten Punkten",
+		"Blocked / Needs help":          "Blockiert / Hilfe benötigt",
+		"Pull requests":                 "Pull-Requests",
+		"Spillovers":                    "Überhänge",
+		"No spillovers in this sprint.": "Keine Überhänge in diesem Sprint.",
+		"Carried over from %s":          "Übernommen aus %s",
+		"Kudos":                         "Anerkennung",
+		"Time off":                      "Abwesenheit",
+		"I did not plan any time off.":  "Ich habe keine Abwesenheit geplant.",
+		"Hours":                         "Stunden",
+		"%s logged":                     "%s erfasst",
+		"approved":                      "freigegeben",
+		"Done without logged time:":     "Erledigt ohne erfasste Zeit:",
+		"Velocity":                      "Velocity",
+		"Dependencies on other teams":   "Abhängigkeiten von anderen Teams",
+		"Generated by %s at %s":         "Erstellt von %s am %s",
+		"sprint ID %s":                  "Sprint-ID %s",
+		"query SHA-256 %s":              "Abfrage-SHA-256 %s",
+		"…and %d more":                  "…und %d weitere",
+		"%s of %s committed pts completed (%s%%)":                         "%s von %s zugesagten Punkten abgeschlossen (%s%%)",
+		"%d issues completed, %d not completed, %d added after the start": "%d Aufgaben abgeschlossen, %d nicht abgeschlossen, %d nach dem Start hinzugefügt",
+		"%d issues done in %s days on average, %s days at the median":     "%d Aufgaben in durchschnittlich %s Tagen erledigt, im Median %s Tage",
+		"Themes":          "Themen",
+		"%s%% done":       "%s%% erledigt",
+		"%d issue":        "%d Aufgabe",
+		"%d issues":       "%d Aufgaben",
+		"Summary":         "Zusammenfassung",
+		"Commits":         "Commits",
+		"No issue":        "Ohne Aufgabe",
+		"blocked by %s":   "blockiert durch %s",
+		"flagged":         "markiert",
+		"merged":          "zusammengeführt",
+		"suggested: %s":   "vorgeschlagen: %s",
+		"commented on %s": "kommentierte %s",
+		"unblocked %s":    "löste die Blockade von %s",
+	},
+	"es": {
+		"Mid-sprint":                    "Mitad de sprint",
+		"End of sprint":                 "Fin de sprint",
+		"Worked on":                     "En qué trabajé",
+		"Done: %s pts of %s committed":  "Completado: %s pts de %s comprometidos",
+		"Blocked / Needs help":          "Bloqueado / Necesita ayuda",
+		"Pull requests":                 "Pull requests",
+		"Spillovers":                    "Pendientes",
+		"No spillovers in this sprint.": "No hay pendientes en este sprint.",
+		"Carried over from %s":          "Arrastrado de %s",
+		"Kudos":                         "Reconocimientos",
+		"Time off":                      "Ausencias",
+		"I did not plan any time off.":  "No he planificado ninguna ausencia.",
+		"Hours":                         "Horas",
+		"%s logged":                     "%s registradas",
+		"approved":                      "aprobadas",
+		"Done without logged time:":     "Completado sin tiempo registrado:",
+		"Velocity":                      "Velocidad",
+		"Dependencies on other teams":   "Dependencias de otros equipos",
+		"Generated by %s at %s":         "Generado por %s el %s",
+		"sprint ID %s":                  "ID del sprint %s",
+		"query SHA-256 %s":              "SHA-256 de la consulta %s",
+		"…and %d more":                  "…y %d más",
+		"%s of %s committed pts completed (%s%%)":                         "%s de %s pts comprometidos completados (%s%%)",
+		"%d issues completed, %d not completed, %d added after the start": "%d tareas completadas, %d sin completar, %d añadidas tras el inicio",
+		"%d issues done in %s days on average, %s days at the median":     "%d tareas completadas en %s días de media, %s días de mediana",
+		"Themes":          "Temas",
+		"%s%% done":       "%s%% completado",
+		"%d issue":        "%d tarea",
+		"%d issues":       "%d tareas",
+		"Summary":         "Resumen",
+		"Commits":         "Commits",
+		"No issue":        "Sin tarea",
+		"blocked by %s":   "bloqueada por %s",
+		"flagged":         "marcada",
+		"merged":          "fusionada",
+		"suggested: %s":   "sugerido: %s",
+		"commented on %s": "comentó en %s",
+		"unblocked %s":    "desbloqueó %s",
+	},
+	"fr": {
+		"Mid-sprint":                    "Mi-sprint",
+		"End of sprint":                 "Fin de sprint",
+		"Worked on":                     "Travail effectué",
+		"Done: %s pts of %s committed":  "Terminé : %s pts sur %s engagés",
+		"Blocked / Needs help":          "Bloqué / Besoin d'aide",
+		"Pull requests":                 "Pull requests",
+		"Spillovers":                    "Reports",
+		"No spillovers in this sprint.": "Aucun report dans ce sprint.",
+		"Carried over from %s":          "Reporté de %s",
+		"Kudos":                         "Remerciements",
+		"Time off":                      "Absences",
+		"I did not plan any time off.":  "Je n'ai prévu aucune absence.",
+		"Hours":                         "Heures",
+		"%s logged":                     "%s saisies",
+		"approved":                      "approuvées",
+		"Done without logged time:":     "Terminé sans temps saisi :",
+		"Velocity":                      "Vélocité",
+		"Dependencies on other teams":   "Dépendances envers d'autres équipes",
+		"Generated by %s at %s":         "Généré par %s le %s",
+		"sprint ID %s":                  "ID du sprint %s",
+		"query SHA-256 %s":              "SHA-256 de la requête %s",
+		"…and %d more":                  "…et %d de plus",
+		"%s of %s committed pts completed (%s%%)":                         "%s pts sur %s engagés terminés (%s %%)",
+		"%d issues completed, %d not completed, %d added after the start": "%d tickets terminés, %d non terminés, %d ajoutés après le début",
+		"%d issues done in %s days on average, %s days at the median":     "%d tickets terminés en %s jours en moyenne, %s jours en médiane",
+		"Themes":          "Thèmes",
+		"%s%% done":       "%s %% terminé",
+		"%d issue":        "%d ticket",
+		"%d issues":       "%d tickets",
+		"Summary":         "Résumé",
+		"Commits":         "Commits",
+		"No issue":        "Sans ticket",
+		"blocked by %s":   "bloqué par %s",
+		"flagged":         "signalé",
+		"merged":          "fusionnée",
+		"suggested: %s":   "suggéré : %s",
+		"commented on %s": "a commenté %s",
+		"unblocked %s":    "a débloqué %s",
+	},
+	"hu": {
+		"Mid-sprint":                    "Sprint közepe",
+		"End of sprint":                 "Sprint vége",
+		"Worked on":                     "Amin dolgoztam",
+		"Done: %s pts of %s committed":  "Kész: %s pont a vállalt %s pontból",
+		"Blocked / Needs help":          "Blokkolva / Segítség kell",
+		"Pull requests":                 "Pull requestek",
+		"Spillovers":                    "Átcsúszó feladatok",
+		"No spillovers in this sprint.": "Ebben a sprintben nincs átcsúszó feladat.",
+		"Carried over from %s":          "Áthozva innen: %s",
+		"Kudos":                         "Elismerések",
+		"Time off":                      "Szabadság",
+		"I did not plan any time off.":  "Nem tervezek szabadságot.",
+		"Hours":                         "Órák",
+		"%s logged":                     "%s rögzítve",
+		"approved":                      "jóváhagyva",
+		"Done without logged time:":     "Kész, rögzített idő nélkül:",
+		"Velocity":                      "Sebesség",
+		"Dependencies on other teams":   "Függőségek más csapatoktól",
+		"Generated by %s at %s":         "Készítette: %s, %s",
+		"sprint ID %s":                  "sprint azonosító %s",
+		"query SHA-256 %s":              "lekérdezés SHA-256 %s",
+		"…and %d more":                  "…és még %d",
+		"%s of %s committed pts completed (%s%%)":                         "%s pont kész a vállalt %s pontból (%s%%)",
+		"%d issues completed, %d not completed, %d added after the start": "%d feladat kész, %d nincs kész, %d a kezdés után került be",
+		"%d issues done in %s days on average, %s days at the median":     "%d feladat készült el átlagosan %s, mediánban %s nap alatt",
+		"Themes":          "Témák",
+		"%s%% done":       "%s%% kész",
+		"%d issue":        "%d feladat",
+		"%d issues":       "%d feladat",
+		"Summary":         "Összefoglaló",
+		"Commits":         "Commitok",
+		"No issue":        "Feladat nélkül",
+		"blocked by %s":   "blokkolja: %s",
+		"flagged":         "megjelölve",
+		"merged":          "beolvasztva",
+		"suggested: %s":   "javasolt: %s",
+		"commented on %s": "megjegyzést írt a(z) %s feladathoz",
+		"unblocked %s":    "feloldotta a(z) %s blokkolását",
+	},
+}
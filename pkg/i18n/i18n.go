@@ -0,0 +1,68 @@
+// Package i18n translates the headings and the messages of the built-in
+// templates, so the updates of non-English teams can use the built-in
+// templates too.
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultLanguage is the language of the messages, used when no language is
+// set.
+const DefaultLanguage = "en"
+
+// ErrUnknownLanguage is returned when the messages are not translated to the
+// requested language.
+var ErrUnknownLanguage = errors.New("unknown language")
+
+// Languages returns the codes of the languages the messages are translated
+// to, in alphabetical order.
+func Languages() []string {
+	languages := []string{DefaultLanguage}
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+
+	sort.Strings(languages)
+	return languages
+}
+
+// normalize returns the code of the language, ignoring the case and the
+// region, like "de" for "de-AT" or "de_CH".
+func normalize(language string) string {
+	language = strings.ToLower(language)
+	if i := strings.IndexAny(language, "-_"); i > 0 {
+		language = language[:i]
+	}
+
+	return language
+}
+
+// Validate checks that the messages are translated to the language. An empty
+// language stands for DefaultLanguage.
+func Validate(language string) error {
+	language = normalize(language)
+	if _, ok := catalogs[language]; ok || language == "" || language == DefaultLanguage {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s (supported languages: %s)", ErrUnknownLanguage, language, strings.Join(Languages(), ", "))
+}
+
+// Translate returns the translation of the English message to the language,
+// formatted with the arguments, like fmt.Sprintf. The messages not translated
+// to the language are returned in English.
+func Translate(language string, message string, args ...interface{}) string {
+	if translation, ok := catalogs[normalize(language)][message]; ok {
+		message = translation
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf(message, args...)
+}
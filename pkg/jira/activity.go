@@ -0,0 +1,69 @@
+package jira
+
+import (
+	"context"
+	"strings"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// activityFields lists the issue fields read for collecting the activity of
+// others on an issue.
+const activityFields = "comment,assignee,status"
+
+// FetchActivity returns the issue having the given key with its comments,
+// assignee, and status.
+func FetchActivity(ctx context.Context, client *gojira.Client, issueKey string) (*gojira.Issue, error) {
+	issue, resp, err := client.Issue.GetWithContext(ctx, issueKey, &gojira.GetQueryOptions{Fields: activityFields})
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	return issue, nil
+}
+
+// LastComment returns the last comment of the issue with its whitespace
+// collapsed, skipping the comments added when flagging the issue. If the
+// issue has no comments, an empty string is returned.
+func LastComment(issue *gojira.Issue) string {
+	if issue == nil || issue.Fields == nil || issue.Fields.Comments == nil {
+		return ""
+	}
+
+	var last string
+	for _, comment := range issue.Fields.Comments.Comments {
+		body := strings.Join(strings.Fields(comment.Body), " ")
+		if body != "" && !strings.HasPrefix(body, flagCommentPrefix) {
+			last = body
+		}
+	}
+
+	return last
+}
+
+// FieldText returns the text of the value of a custom field, like the value
+// of a text field, or the values of a select or multi-select field joined by
+// commas. If the value has no text, an empty string is returned.
+func FieldText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.Join(strings.Fields(v), " ")
+	case map[string]interface{}:
+		for _, key := range []string{"value", "name"} {
+			if text, ok := v[key].(string); ok {
+				return FieldText(text)
+			}
+		}
+	case []interface{}:
+		var texts []string
+		for _, item := range v {
+			if text := FieldText(item); text != "" {
+				texts = append(texts, text)
+			}
+		}
+
+		return strings.Join(texts, ", ")
+	}
+
+	return ""
+}
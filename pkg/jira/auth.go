@@ -0,0 +1,264 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/oauth"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// AuthType is the authentication method used against the Jira server.
+type AuthType string
+
+const (
+	// AuthBasic authenticates with a username and password. Not supported
+	// by Jira Cloud.
+	AuthBasic AuthType = "basic"
+	// AuthToken authenticates with an email and an API token on Jira Cloud.
+	AuthToken AuthType = "token"
+	// AuthPAT authenticates with a Personal Access Token on Jira Server and
+	// Data Center.
+	AuthPAT AuthType = "pat"
+	// AuthOAuth authenticates with OAuth 2.0 (3LO) access tokens on Jira
+	// Cloud.
+	AuthOAuth AuthType = "oauth"
+)
+
+// ErrUnknownAuthType is returned when the authentication method does not
+// exist.
+var ErrUnknownAuthType = errors.New("unknown auth type")
+
+// ErrMissingCredentials is returned when the credentials required by the
+// authentication method are not set.
+var ErrMissingCredentials = errors.New("missing credentials")
+
+// cloudHostSuffixes are the host suffixes of Jira Cloud sites.
+var cloudHostSuffixes = []string{".atlassian.net", ".gojira.com"}
+
+// Auth holds the credentials used for authenticating against Jira.
+type Auth struct {
+	// Type is the authentication method. When empty, AuthBasic is used.
+	Type AuthType
+	// Username is the username, or the email address for AuthToken.
+	Username string
+	// Password is the password used by AuthBasic.
+	Password string
+	// Token is the API token used by AuthToken or the Personal Access Token
+	// used by AuthPAT.
+	Token string
+	// OAuth is the source of the access tokens used by AuthOAuth.
+	OAuth *oauth.TokenSource
+	// Transport is the underlying HTTP transport of the authenticated
+	// requests, like a Recorder. When nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+}
+
+// EffectiveType returns the authentication method, defaulting to AuthBasic.
+func (a *Auth) EffectiveType() AuthType {
+	if a.Type == "" {
+		return AuthBasic
+	}
+
+	return AuthType(strings.ToLower(string(a.Type)))
+}
+
+// Validate checks that the authentication method exists and its credentials
+// are set.
+func (a *Auth) Validate() error {
+	switch a.EffectiveType() {
+	case AuthBasic:
+		return nil
+	case AuthToken:
+		if a.Username == "" || a.Token == "" {
+			return fmt.Errorf("%w: auth type %q requires the email address and an API token", ErrMissingCredentials, AuthToken)
+		}
+	case AuthPAT:
+		if a.Token == "" {
+			return fmt.Errorf("%w: auth type %q requires a personal access token", ErrMissingCredentials, AuthPAT)
+		}
+	case AuthOAuth:
+		if a.OAuth == nil {
+			return fmt.Errorf("%w: auth type %q requires an oauth token source", ErrMissingCredentials, AuthOAuth)
+		}
+	default:
+		return fmt.Errorf("%w: %s (available: %s, %s, %s, %s)", ErrUnknownAuthType, a.Type, AuthBasic, AuthToken, AuthPAT, AuthOAuth)
+	}
+
+	return nil
+}
+
+// apiURL returns the base URL of the Jira API. For AuthOAuth, the requests
+// are sent through the Atlassian API gateway instead of the site URL.
+func (a *Auth) apiURL(serverURL string) (string, error) {
+	if a.EffectiveType() != AuthOAuth {
+		return serverURL, nil
+	}
+
+	token, err := a.OAuth.Store.Load()
+	if err != nil {
+		return "", err
+	}
+
+	return token.APIURL(), nil
+}
+
+// Secrets returns the credentials that must never be part of error messages,
+// including the "username:secret" pairs encoded by the basic auth headers.
+func (a *Auth) Secrets() []string {
+	secrets := []string{a.Username, a.Password, a.Token}
+
+	for _, secret := range []string{a.Password, a.Token} {
+		if a.Username != "" && secret != "" {
+			secrets = append(secrets, a.Username+":"+secret)
+		}
+	}
+
+	return secrets
+}
+
+// httpClient returns an HTTP client authenticating with the credentials.
+func (a *Auth) httpClient() *http.Client {
+	switch a.EffectiveType() {
+	case AuthToken:
+		transport := gojira.BasicAuthTransport{Username: a.Username, Password: a.Token, Transport: a.Transport}
+		return transport.Client()
+	case AuthPAT:
+		return &http.Client{Transport: &bearerAuthTransport{Token: a.Token, Transport: a.Transport}}
+	case AuthOAuth:
+		return &http.Client{Transport: &oauth.Transport{Source: a.OAuth, Base: a.Transport}}
+	default:
+		transport := gojira.BasicAuthTransport{Username: a.Username, Password: a.Password, Transport: a.Transport}
+		return transport.Client()
+	}
+}
+
+// WrapError redacts the credentials from the error returned by Jira, and
+// explains how to fix authentication failures against the given server.
+func (a *Auth) WrapError(serverURL string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	err = RedactError(err, a.Secrets()...)
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.IsAuthFailure() {
+		return &AuthError{Hint: a.hint(serverURL), err: err}
+	}
+
+	return err
+}
+
+// hint returns a hint on how to fix an authentication failure against the
+// given server, based on the authentication method used.
+func (a *Auth) hint(serverURL string) string {
+	isCloud := isCloudServer(serverURL)
+
+	switch authType := a.EffectiveType(); {
+	case isCloud && authType == AuthBasic:
+		return fmt.Sprintf("Jira Cloud does not accept passwords; set auth-type to %q and use an API token with your email address", AuthToken)
+	case isCloud && authType == AuthPAT:
+		return fmt.Sprintf("Jira Cloud does not support personal access tokens; set auth-type to %q and use an API token with your email address", AuthToken)
+	case !isCloud && authType == AuthToken:
+		return fmt.Sprintf("API tokens are only supported by Jira Cloud; set auth-type to %q to use a personal access token", AuthPAT)
+	case authType == AuthOAuth:
+		return "the oauth authorization may be revoked; run the login command again"
+	default:
+		return "check the configured credentials"
+	}
+}
+
+// isCloudServer reports whether the server URL points to a Jira Cloud site.
+func isCloudServer(serverURL string) bool {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, suffix := range cloudHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bearerAuthTransport is an http.RoundTripper that authenticates all requests
+// using a bearer token.
+type bearerAuthTransport struct {
+	Token string
+
+	// Transport is the underlying HTTP transport to use when making requests.
+	// It will default to http.DefaultTransport if nil.
+	Transport http.RoundTripper
+}
+
+// RoundTrip implements the RoundTripper interface by adding the bearer token
+// to a copy of the request.
+func (t *bearerAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", "Bearer "+t.Token)
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return transport.RoundTrip(req2)
+}
+
+// StatusError is returned when Jira responds with an unsuccessful status.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	err        error
+}
+
+// Error returns the message of the wrapped error.
+func (e *StatusError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *StatusError) Unwrap() error {
+	return e.err
+}
+
+// IsAuthFailure reports whether the request failed due to the credentials.
+func (e *StatusError) IsAuthFailure() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+}
+
+// jiraError wraps the error returned by the Jira client with the status code
+// of the response, if any.
+func jiraError(err error, resp *gojira.Response) error {
+	if err == nil || resp == nil || resp.Response == nil {
+		return err
+	}
+
+	return &StatusError{StatusCode: resp.StatusCode, err: err}
+}
+
+// AuthError is returned when Jira rejects the credentials.
+type AuthError struct {
+	// Hint tells how to fix the authentication failure.
+	Hint string
+	err  error
+}
+
+// Error returns the message of the wrapped error extended with the hint.
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("authentication failed: %v (hint: %s)", e.err, e.Hint)
+}
+
+// Unwrap returns the wrapped error.
+func (e *AuthError) Unwrap() error {
+	return e.err
+}
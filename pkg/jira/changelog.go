@@ -0,0 +1,105 @@
+package jira
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// changelogTimeLayout is the layout of the changelog entry creation times
+// returned by Jira.
+const changelogTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// StatusChange is a transition of an issue from one status to another.
+type StatusChange struct {
+	// At is the time the issue was transitioned at.
+	At time.Time
+	// From and To are the IDs of the statuses.
+	From string
+	To   string
+}
+
+// StatusCategories returns the keys of the status categories, like
+// "indeterminate", by the IDs of the statuses.
+func StatusCategories(ctx context.Context, client *gojira.Client) (map[string]string, error) {
+	statuses, resp, err := client.Status.GetAllStatusesWithContext(ctx)
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	categories := make(map[string]string, len(statuses))
+	for _, status := range statuses {
+		categories[status.ID] = status.StatusCategory.Key
+	}
+
+	return categories, nil
+}
+
+// FetchStatusChanges returns the status transitions of the issue having the
+// given key, read from its changelog, from the oldest to the newest.
+func FetchStatusChanges(ctx context.Context, client *gojira.Client, issueKey string) ([]StatusChange, error) {
+	issue, resp, err := client.Issue.GetWithContext(ctx, issueKey, &gojira.GetQueryOptions{Fields: "status", Expand: "changelog"})
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	if issue.Changelog == nil {
+		return nil, nil
+	}
+
+	var changes []StatusChange
+	for _, history := range issue.Changelog.Histories {
+		at, err := time.Parse(changelogTimeLayout, history.Created)
+		if err != nil {
+			continue
+		}
+
+		for _, item := range history.Items {
+			if item.Field != "status" {
+				continue
+			}
+
+			from, _ := item.From.(string)
+			to, _ := item.To.(string)
+			changes = append(changes, StatusChange{At: at, From: from, To: to})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].At.Before(changes[j].At)
+	})
+
+	return changes, nil
+}
+
+// Timeline returns the time the issue first entered a status of the "in
+// progress" category, and the time it last entered a status of the "done"
+// category, based on its status transitions until the given time. The times
+// are zero if the issue did not enter such a status, and the time it was done
+// is zero if the issue was reopened afterwards.
+func Timeline(changes []StatusChange, categories map[string]string, until time.Time) (time.Time, time.Time) {
+	var started, done time.Time
+
+	for _, change := range changes {
+		if change.At.After(until) {
+			break
+		}
+
+		switch categories[change.To] {
+		case gojira.StatusCategoryInProgress:
+			if started.IsZero() {
+				started = change.At
+			}
+
+			done = time.Time{}
+		case gojira.StatusCategoryComplete:
+			done = change.At
+		case gojira.StatusCategoryToDo:
+			done = time.Time{}
+		}
+	}
+
+	return started, done
+}
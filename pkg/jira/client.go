@@ -0,0 +1,278 @@
+// Package jira fetches the issues and sprints of sprint updates from Jira,
+// authenticating with any of the supported methods.
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gabor-boros/sprint-update/pkg/logging"
+	"gabor-boros/sprint-update/pkg/metrics"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// searchFields lists the issue fields always requested from Jira when
+// searching.
+var searchFields = []string{
+	"summary",
+	"status",
+	"issuelinks",
+	"parent",
+	"subtasks",
+	"project",
+	"resolutiondate",
+	"duedate",
+	"updated",
+}
+
+// OptionalFields lists the issue fields requested from Jira by FetchIssues
+// besides the searchFields. They are read by some features and templates
+// only, hence they can be left out of the search results to keep them small.
+var OptionalFields = []string{
+	"issuetype",
+	"labels",
+	"components",
+	"priority",
+	"assignee",
+}
+
+// ErrUnknownField is returned when an issue field is not one of the
+// OptionalFields.
+var ErrUnknownField = errors.New("unknown issue field")
+
+// ValidateOptionalFields checks that the fields are OptionalFields.
+func ValidateOptionalFields(fields []string) error {
+	for _, field := range fields {
+		known := false
+		for _, optional := range OptionalFields {
+			if strings.EqualFold(field, optional) {
+				known = true
+				break
+			}
+		}
+
+		if !known {
+			return fmt.Errorf("%w: %s (available: %s)", ErrUnknownField, field, strings.Join(OptionalFields, ", "))
+		}
+	}
+
+	return nil
+}
+
+// NewClient returns creates a transport for the authentication method and
+// returns a new Jira client.
+func NewClient(serverURL string, auth Auth) (*gojira.Client, error) {
+	if err := auth.Validate(); err != nil {
+		return nil, err
+	}
+
+	apiURL, err := auth.apiURL(serverURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := gojira.NewClient(auth.httpClient(), apiURL)
+	if err != nil {
+		return nil, RedactError(err, auth.Secrets()...)
+	}
+
+	return client, nil
+}
+
+// DefaultWorkers is the number of pages fetched concurrently by FetchIssues.
+const DefaultWorkers = 4
+
+// maxPageSize is the maximum number of issues requested per page. Jira may
+// return fewer issues per page, like Jira Cloud does.
+const maxPageSize = 1000
+
+// FetchIssues fetches issues from Jira returned as a result of the given JQL,
+// using DefaultWorkers to fetch the pages concurrently.
+//
+// Besides the default search fields and the OptionalFields, the given custom
+// fields are requested. Returned errors never contain the userinfo of the
+// server URL.
+func FetchIssues(ctx context.Context, client *gojira.Client, jql string, customFields ...string) ([]gojira.Issue, error) {
+	return FetchIssuesWithWorkers(ctx, client, jql, DefaultWorkers, append(append([]string{}, OptionalFields...), customFields...)...)
+}
+
+// pageFunc fetches the page of issues starting at the given offset, and
+// returns the total number of issues too.
+type pageFunc func(ctx context.Context, startAt int) ([]gojira.Issue, int, error)
+
+// FetchIssuesWithWorkers fetches issues from Jira returned as a result of the
+// given JQL. The number of issues returned by a search is limited, hence the
+// issues are paginated. The first page reveals the total number of issues and
+// the page size used by the server; the remaining pages are fetched
+// concurrently by the given number of workers, and the issues are returned in
+// the order of the search results.
+//
+// Besides the default search fields, the given fields are requested, like the
+// OptionalFields or custom fields. Returned errors never contain the userinfo
+// of the server URL.
+func FetchIssuesWithWorkers(ctx context.Context, client *gojira.Client, jql string, workers int, fields ...string) ([]gojira.Issue, error) {
+	fields = append(append([]string{}, searchFields...), fields...)
+
+	logging.FromContext(ctx).Verbose("searching issues", "jql", jql)
+
+	return fetchPages(ctx, workers, func(ctx context.Context, startAt int) ([]gojira.Issue, int, error) {
+		return searchPage(ctx, client, jql, startAt, fields)
+	})
+}
+
+// FetchSprintIssues fetches the issues of the sprint having the given ID
+// using the Jira Agile API, paginated like FetchIssuesWithWorkers. Unlike
+// searching by the name of the sprint, the issues of other sprints of the
+// same name are not returned. The issues are filtered by the given JQL, if
+// not empty.
+//
+// Besides the default search fields, the given fields are requested. Returned
+// errors never contain the userinfo of the server URL.
+func FetchSprintIssues(ctx context.Context, client *gojira.Client, sprintID int, jql string, workers int, fields ...string) ([]gojira.Issue, error) {
+	fields = append(append([]string{}, searchFields...), fields...)
+
+	logging.FromContext(ctx).Verbose("fetching sprint issues", "sprint", sprintID, "jql", jql)
+
+	return fetchPages(ctx, workers, func(ctx context.Context, startAt int) ([]gojira.Issue, int, error) {
+		return sprintIssuesPage(ctx, client, sprintID, jql, startAt, fields)
+	})
+}
+
+// fetchPages fetches the pages of issues using the given function. The first
+// page reveals the total number of issues and the page size used by the
+// server; the remaining pages are fetched concurrently by the given number of
+// workers, and the issues are returned in the order of the pages.
+func fetchPages(ctx context.Context, workers int, fetchPage pageFunc) ([]gojira.Issue, error) {
+	logger := logging.FromContext(ctx)
+
+	firstPage, total, err := fetchPage(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := len(firstPage)
+
+	if total <= pageSize || pageSize == 0 {
+		logger.Verbose("fetched issues", "total", len(firstPage), "pages", 1)
+		metrics.FromContext(ctx).AddIssues(len(firstPage))
+		return firstPage, nil
+	}
+
+	pageCount := (total + pageSize - 1) / pageSize
+	logger.Verbose("fetching remaining pages", "total", total, "page_size", pageSize, "pages", pageCount, "workers", workers)
+	pages := make([][]gojira.Issue, pageCount)
+	pages[0] = firstPage
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	pageIndexes := make(chan int)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range pageIndexes {
+				page, _, err := fetchPage(ctx, i*pageSize)
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+
+					continue
+				}
+
+				pages[i] = page
+			}
+		}()
+	}
+
+	for i := 1; i < pageCount; i++ {
+		pageIndexes <- i
+	}
+
+	close(pageIndexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	issues := make([]gojira.Issue, 0, total)
+	for _, page := range pages {
+		issues = append(issues, page...)
+	}
+
+	logger.Verbose("fetched issues", "total", len(issues), "pages", pageCount)
+	metrics.FromContext(ctx).AddIssues(len(issues))
+
+	return issues, nil
+}
+
+// searchPage fetches the page of the search results starting at the given
+// offset, and returns the total number of results too.
+func searchPage(ctx context.Context, client *gojira.Client, jql string, startAt int, fields []string) ([]gojira.Issue, int, error) {
+	issues, resp, err := client.Issue.SearchWithContext(ctx, jql, &gojira.SearchOptions{
+		StartAt:    startAt,
+		MaxResults: maxPageSize,
+		Fields:     fields,
+	})
+	if err != nil {
+		return nil, 0, RedactError(jiraError(err, resp))
+	}
+
+	logging.FromContext(ctx).Debug("fetched search page", "jql", jql, "start_at", startAt, "issues", len(issues), "total", resp.Total)
+
+	return issues, resp.Total, nil
+}
+
+// sprintIssuesPage fetches the page of the issues of the sprint starting at
+// the given offset, and returns the total number of issues too.
+func sprintIssuesPage(ctx context.Context, client *gojira.Client, sprintID int, jql string, startAt int, fields []string) ([]gojira.Issue, int, error) {
+	query := url.Values{}
+	query.Set("startAt", strconv.Itoa(startAt))
+	query.Set("maxResults", strconv.Itoa(maxPageSize))
+	query.Set("fields", strings.Join(fields, ","))
+
+	if jql != "" {
+		query.Set("jql", jql)
+	}
+
+	req, err := client.NewRequestWithContext(ctx, "GET", fmt.Sprintf("rest/agile/1.0/sprint/%d/issue?%s", sprintID, query.Encode()), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var page struct {
+		Total  int            `json:"total"`
+		Issues []gojira.Issue `json:"issues"`
+	}
+
+	resp, err := client.Do(req, &page)
+	if err != nil {
+		return nil, 0, RedactError(jiraError(err, resp))
+	}
+
+	logging.FromContext(ctx).Debug("fetched sprint issues page", "sprint", sprintID, "start_at", startAt, "issues", len(page.Issues), "total", page.Total)
+
+	return page.Issues, page.Total, nil
+}
@@ -0,0 +1,145 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// epicLinkFieldSchema is the custom schema type of the Jira Agile epic link
+// field.
+const epicLinkFieldSchema = "com.pyxis.greenhopper.jira:gh-epic-link"
+
+// epicBatchSize is the number of epics looked up by a single search, keeping
+// the query short and the results on a single page.
+const epicBatchSize = 50
+
+// FindEpicLinkFieldID returns the ID of the Jira Agile epic link custom field.
+// If the field does not exist, like in team-managed projects, an empty string
+// is returned.
+func FindEpicLinkFieldID(ctx context.Context, client *gojira.Client) (string, error) {
+	fields, resp, err := client.Field.GetListWithContext(ctx)
+	if err != nil {
+		return "", RedactError(jiraError(err, resp))
+	}
+
+	for _, field := range fields {
+		if field.Schema.Custom == epicLinkFieldSchema {
+			return field.ID, nil
+		}
+	}
+
+	return "", nil
+}
+
+// FetchEpicNames returns the summaries of the epics with the given keys,
+// keyed by the epic keys. The epics not visible to the user are left out.
+func FetchEpicNames(ctx context.Context, client *gojira.Client, keys []string) (map[string]string, error) {
+	names := make(map[string]string, len(keys))
+
+	for start := 0; start < len(keys); start += epicBatchSize {
+		end := start + epicBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		jql := fmt.Sprintf("key in (%s)", strings.Join(keys[start:end], ", "))
+
+		epics, _, err := searchPage(ctx, client, jql, 0, []string{"summary"})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, epic := range epics {
+			names[epic.Key] = epic.Fields.Summary
+		}
+	}
+
+	return names, nil
+}
+
+// EpicProgress is the progress of an epic, counting every child issue of the
+// epic, regardless of its assignee.
+type EpicProgress struct {
+	// Done is the number of the child issues in a status of the "done"
+	// category.
+	Done int
+	// Total is the number of the child issues.
+	Total int
+}
+
+// FetchEpicProgress returns the progress of the epics with the given keys,
+// keyed by the epic keys. The children of the epics are the issues whose
+// parent is the epic, like in team-managed projects and on Jira Cloud, and
+// the issues linked to the epic using the epic link field, if its ID is not
+// empty. The epics without children are left out.
+func FetchEpicProgress(ctx context.Context, client *gojira.Client, keys []string, epicLinkFieldID string) (map[string]EpicProgress, error) {
+	progress := make(map[string]EpicProgress, len(keys))
+
+	for start := 0; start < len(keys); start += epicBatchSize {
+		end := start + epicBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		batch := strings.Join(keys[start:end], ", ")
+		jql := fmt.Sprintf("parent in (%s)", batch)
+
+		var fields []string
+		if epicLinkFieldID != "" {
+			jql += fmt.Sprintf(" OR %s in (%s)", customFieldClause(epicLinkFieldID), batch)
+			fields = append(fields, epicLinkFieldID)
+		}
+
+		children, err := FetchIssuesWithWorkers(ctx, client, jql, DefaultWorkers, fields...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range children {
+			epicKey := childEpicKey(&children[i], epicLinkFieldID)
+			if epicKey == "" {
+				continue
+			}
+
+			p := progress[epicKey]
+			p.Total++
+
+			if status := children[i].Fields.Status; status != nil && status.StatusCategory.Key == gojira.StatusCategoryComplete {
+				p.Done++
+			}
+
+			progress[epicKey] = p
+		}
+	}
+
+	return progress, nil
+}
+
+// childEpicKey returns the key of the epic of the child issue, which is the
+// value of its epic link field, or else its parent.
+func childEpicKey(issue *gojira.Issue, epicLinkFieldID string) string {
+	if epicLinkFieldID != "" {
+		if epicKey, ok := issue.Fields.Unknowns[epicLinkFieldID].(string); ok && epicKey != "" {
+			return epicKey
+		}
+	}
+
+	if issue.Fields.Parent != nil {
+		return issue.Fields.Parent.Key
+	}
+
+	return ""
+}
+
+// customFieldClause returns the JQL reference of the custom field, like
+// "cf[10014]" for "customfield_10014".
+func customFieldClause(fieldID string) string {
+	if id := strings.TrimPrefix(fieldID, "customfield_"); id != fieldID {
+		return fmt.Sprintf("cf[%s]", id)
+	}
+
+	return fieldID
+}
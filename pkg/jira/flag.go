@@ -0,0 +1,66 @@
+package jira
+
+import (
+	"context"
+	"strings"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// flaggedFieldName is the name of the Jira Software field marking the issues
+// flagged as impeded.
+const flaggedFieldName = "Flagged"
+
+// flagCommentPrefix is the prefix of the comments added by Jira when an issue
+// is flagged with a comment, like "(flag) Flag added".
+const flagCommentPrefix = "(flag)"
+
+// FindFlaggedFieldID returns the ID of the Jira Software flagged custom field.
+// If the field does not exist, an empty string is returned.
+func FindFlaggedFieldID(ctx context.Context, client *gojira.Client) (string, error) {
+	fields, resp, err := client.Field.GetListWithContext(ctx)
+	if err != nil {
+		return "", RedactError(jiraError(err, resp))
+	}
+
+	for _, field := range fields {
+		if field.Custom && strings.EqualFold(field.Name, flaggedFieldName) {
+			return field.ID, nil
+		}
+	}
+
+	return "", nil
+}
+
+// IsFlagged reports whether the value of the flagged custom field marks the
+// issue as flagged, which is a non-empty list of options, like "Impediment".
+func IsFlagged(value interface{}) bool {
+	values, ok := value.([]interface{})
+	return ok && len(values) > 0
+}
+
+// FlagReason returns the reason of flagging the issue, taken from the last
+// comment added when flagging it, without its "(flag) Flag added" line. If
+// the issue was flagged without a comment, an empty string is returned.
+func FlagReason(issue *gojira.Issue) string {
+	if issue == nil || issue.Fields == nil || issue.Fields.Comments == nil {
+		return ""
+	}
+
+	var reason string
+	for _, comment := range issue.Fields.Comments.Comments {
+		body := strings.TrimSpace(comment.Body)
+		if !strings.HasPrefix(body, flagCommentPrefix) {
+			continue
+		}
+
+		lines := strings.SplitN(body, "\n", 2)
+		if len(lines) == 2 {
+			reason = strings.Join(strings.Fields(lines[1]), " ")
+		} else {
+			reason = ""
+		}
+	}
+
+	return reason
+}
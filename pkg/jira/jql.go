@@ -0,0 +1,146 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// ErrInvalidJQL is returned when a JQL query is malformed or rejected by Jira.
+var ErrInvalidJQL = errors.New("invalid JQL")
+
+// ErrInvalidJQLValue is returned when a value inserted into JQL queries, like
+// a sprint name or an assignee, cannot be used in a query.
+var ErrInvalidJQLValue = errors.New("invalid value for JQL queries")
+
+// jqlEscaper escapes the characters of JQL string literals.
+var jqlEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// orderByPattern matches the ORDER BY clause of JQL queries.
+var orderByPattern = regexp.MustCompile(`(?i)\s+order\s+by\s+`)
+
+// JoinJQL restricts the base query with the given clauses, keeping the ORDER
+// BY clause of the base query at the end. Empty clauses are skipped.
+func JoinJQL(base string, clauses ...string) string {
+	query, orderBy := base, ""
+	if loc := orderByPattern.FindStringIndex(base); loc != nil {
+		query, orderBy = base[:loc[0]], base[loc[0]:]
+	}
+
+	var parts []string
+	if query = strings.TrimSpace(query); query != "" {
+		parts = append(parts, query)
+	}
+
+	for _, clause := range clauses {
+		if clause = strings.TrimSpace(clause); clause != "" {
+			parts = append(parts, clause)
+		}
+	}
+
+	// Wrap every part in parentheses only when combining them, so operator
+	// precedence of OR clauses is kept.
+	if len(parts) > 1 {
+		for i, part := range parts {
+			parts[i] = "(" + part + ")"
+		}
+	}
+
+	return strings.Join(parts, " AND ") + orderBy
+}
+
+// QuoteJQL returns the value as a JQL string literal, escaping its quotes
+// and backslashes, so the value is matched as is and cannot alter the query.
+func QuoteJQL(value string) string {
+	return `"` + jqlEscaper.Replace(value) + `"`
+}
+
+// CheckJQLValue checks the value of the setting inserted into JQL queries: it
+// must not be blank or contain control characters, like line breaks, which
+// JQL string literals cannot hold.
+func CheckJQLValue(setting string, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%w: %s is blank", ErrInvalidJQLValue, setting)
+	}
+
+	for _, r := range value {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: %s %q contains control characters", ErrInvalidJQLValue, setting, value)
+		}
+	}
+
+	return nil
+}
+
+// CheckJQL performs a basic syntax check of the query, catching unbalanced
+// quotes and parentheses before the query is sent to Jira.
+func CheckJQL(jql string) error {
+	if strings.TrimSpace(jql) == "" {
+		return fmt.Errorf("%w: empty query", ErrInvalidJQL)
+	}
+
+	depth := 0
+	escaped := false
+	var quote rune
+
+	for i, r := range jql {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("%w: unexpected closing parenthesis at position %d", ErrInvalidJQL, i)
+			}
+		}
+	}
+
+	if quote != 0 {
+		return fmt.Errorf("%w: unclosed quote", ErrInvalidJQL)
+	}
+
+	if depth != 0 {
+		return fmt.Errorf("%w: unclosed parenthesis", ErrInvalidJQL)
+	}
+
+	return nil
+}
+
+// ValidateJQL asks Jira to strictly validate the query without fetching the
+// matching issues, returning the reasons if the query is rejected.
+func ValidateJQL(ctx context.Context, client *gojira.Client, jql string) error {
+	if err := CheckJQL(jql); err != nil {
+		return err
+	}
+
+	_, resp, err := client.Issue.SearchWithContext(ctx, jql, &gojira.SearchOptions{
+		MaxResults:    1,
+		Fields:        []string{"key"},
+		ValidateQuery: "strict",
+	})
+	if err == nil {
+		return nil
+	}
+
+	var jiraErr *gojira.Error
+	if resp != nil && resp.StatusCode == 400 && errors.As(err, &jiraErr) && len(jiraErr.ErrorMessages) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidJQL, strings.Join(jiraErr.ErrorMessages, "; "))
+	}
+
+	return RedactError(jiraError(err, resp))
+}
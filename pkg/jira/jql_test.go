@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuoteJQL(t *testing.T) {
+	tests := map[string]struct {
+		value string
+		want  string
+	}{
+		"plain":              {value: "SE.253", want: `"SE.253"`},
+		"empty":              {value: "", want: `""`},
+		"double quote":       {value: `Sprint "A"`, want: `"Sprint \"A\""`},
+		"backslash":          {value: `C:\sprints`, want: `"C:\\sprints"`},
+		"trailing backslash": {value: `SE\`, want: `"SE\\"`},
+		"escaped quote":      {value: `\"`, want: `"\\\""`},
+		"single quote":       {value: "Jane's sprint", want: `"Jane's sprint"`},
+		"injection":          {value: `SE" OR project = "OPS`, want: `"SE\" OR project = \"OPS"`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := QuoteJQL(tt.value)
+			if got != tt.want {
+				t.Errorf("QuoteJQL(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+
+			if err := CheckJQL("sprint = " + got); err != nil {
+				t.Errorf("the quoted value is not a valid literal: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckJQLValue(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		wantErr bool
+	}{
+		"plain":           {value: "SE.253"},
+		"quotes":          {value: `Sprint "A"`},
+		"backslash":       {value: `SE\`},
+		"unicode":         {value: "Sprint ünnep"},
+		"empty":           {value: "", wantErr: true},
+		"blank":           {value: " \t ", wantErr: true},
+		"line break":      {value: "SE.253\nOR", wantErr: true},
+		"carriage return": {value: "SE.253\r", wantErr: true},
+		"tab":             {value: "SE\t253", wantErr: true},
+		"null":            {value: "SE\x00", wantErr: true},
+		"delete":          {value: "SE\x7f", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckJQLValue("sprint", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckJQLValue(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, ErrInvalidJQLValue) {
+				t.Errorf("CheckJQLValue(%q) error = %v, want %v", tt.value, err, ErrInvalidJQLValue)
+			}
+		})
+	}
+}
+
+func TestCheckJQL(t *testing.T) {
+	tests := map[string]struct {
+		jql     string
+		wantErr bool
+	}{
+		"simple":                        {jql: `project = SE`},
+		"nested parentheses":            {jql: `(project = SE AND (status = Done OR status = "In Progress")) ORDER BY key`},
+		"parentheses in quotes":         {jql: `summary ~ "(draft"`},
+		"escaped quote":                 {jql: `summary ~ "say \"hi\""`},
+		"escaped backslash":             {jql: `summary ~ "C:\\"`},
+		"single quotes":                 {jql: `summary ~ 'it"s'`},
+		"double quote in single quotes": {jql: `summary ~ 'a " b'`},
+		"function":                      {jql: `assignee = currentUser()`},
+		"empty":                         {jql: "", wantErr: true},
+		"blank":                         {jql: "  \n", wantErr: true},
+		"unclosed quote":                {jql: `summary ~ "draft`, wantErr: true},
+		"unclosed single quote":         {jql: `summary ~ 'draft`, wantErr: true},
+		"escaped closing quote":         {jql: `summary ~ "draft\"`, wantErr: true},
+		"unclosed parenthesis":          {jql: `(project = SE`, wantErr: true},
+		"unexpected parenthesis":        {jql: `project = SE)`, wantErr: true},
+		"reversed parentheses":          {jql: `)project = SE(`, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckJQL(tt.jql)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckJQL(%q) error = %v, wantErr %v", tt.jql, err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, ErrInvalidJQL) {
+				t.Errorf("CheckJQL(%q) error = %v, want %v", tt.jql, err, ErrInvalidJQL)
+			}
+		})
+	}
+}
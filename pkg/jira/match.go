@@ -0,0 +1,178 @@
+package jira
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MatchSprints returns the sprints whose names are similar to the given name,
+// like "SE.253" for "se 253" or "253", the most similar first. The sprints as
+// similar are listed from the most recent, assuming the sprints are listed
+// from the oldest, like Jira does.
+func MatchSprints(sprints []Sprint, name string) []Sprint {
+	query := normalizeSprintName(name)
+	if query == "" {
+		return nil
+	}
+
+	type match struct {
+		sprint Sprint
+		index  int
+		score  int
+	}
+
+	var matches []match
+	for i := range sprints {
+		if score, ok := sprintNameScore(query, normalizeSprintName(sprints[i].Name)); ok {
+			matches = append(matches, match{sprint: sprints[i], index: i, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].score != matches[b].score {
+			return matches[a].score < matches[b].score
+		}
+
+		return matches[a].index > matches[b].index
+	})
+
+	matched := make([]Sprint, 0, len(matches))
+	for _, m := range matches {
+		matched = append(matched, m.sprint)
+	}
+
+	return matched
+}
+
+// maxPartialScore is the highest score of the names matching partially: the
+// names equal to, ending with, or containing the query when normalized.
+const maxPartialScore = 2
+
+// ResolveSprintName returns the sprints best matching the partial sprint
+// name, like "SE.253" for "253": the sprint of the exact name, or else the
+// sprints whose names are the same as the name when normalized, end with
+// it, or contain it, in this order of preference. If more than one sprint is
+// returned, the name is ambiguous. The sprints are listed from the most
+// recent, assuming the sprints are listed from the oldest.
+func ResolveSprintName(sprints []Sprint, name string) []Sprint {
+	for i := range sprints {
+		if sprints[i].Name == name {
+			return []Sprint{sprints[i]}
+		}
+	}
+
+	query := normalizeSprintName(name)
+	if query == "" {
+		return nil
+	}
+
+	best := maxPartialScore + 1
+	var resolved []Sprint
+
+	for i := len(sprints) - 1; i >= 0; i-- {
+		score, ok := sprintNameScore(query, normalizeSprintName(sprints[i].Name))
+		switch {
+		case !ok || score > maxPartialScore || score > best:
+		case score < best:
+			best = score
+			resolved = []Sprint{sprints[i]}
+		default:
+			resolved = append(resolved, sprints[i])
+		}
+	}
+
+	return resolved
+}
+
+// sprintNameScore scores how similar the normalized sprint name is to the
+// normalized query, the lower the more similar. The names not similar at all
+// are reported as not matching.
+func sprintNameScore(query string, name string) (int, bool) {
+	switch {
+	case name == query:
+		return 0, true
+	case strings.HasSuffix(name, query):
+		return 1, true
+	case strings.Contains(name, query):
+		return 2, true
+	case isSubsequence(query, name):
+		return 3, true
+	}
+
+	maxDistance := len(query) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	if distance := editDistance(query, name); distance <= maxDistance {
+		return 3 + distance, true
+	}
+
+	return 0, false
+}
+
+// normalizeSprintName lowercases the sprint name, leaving out everything but
+// the letters and digits, so "SE.253" and "se 253" are the same.
+func normalizeSprintName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// isSubsequence reports whether the characters of the query appear in the
+// name in the same order.
+func isSubsequence(query string, name string) bool {
+	i := 0
+	for j := 0; i < len(query) && j < len(name); j++ {
+		if query[i] == name[j] {
+			i++
+		}
+	}
+
+	return i == len(query)
+}
+
+// editDistance returns the Levenshtein distance of the strings.
+func editDistance(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// minInt returns the smallest of the numbers.
+func minInt(first int, others ...int) int {
+	min := first
+	for _, n := range others {
+		if n < min {
+			min = n
+		}
+	}
+
+	return min
+}
@@ -0,0 +1,45 @@
+package jira
+
+import (
+	"context"
+	"strings"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// rankFieldName is the name of the Jira Software field ordering the issues on
+// the boards and in the backlogs.
+const rankFieldName = "Rank"
+
+// FindRankFieldID returns the ID of the Jira Software rank custom field. If
+// the field does not exist, an empty string is returned.
+func FindRankFieldID(ctx context.Context, client *gojira.Client) (string, error) {
+	fields, resp, err := client.Field.GetListWithContext(ctx)
+	if err != nil {
+		return "", RedactError(jiraError(err, resp))
+	}
+
+	for _, field := range fields {
+		if field.Custom && strings.EqualFold(field.Name, rankFieldName) {
+			return field.ID, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Priorities returns the names of the priorities of the Jira server, from the
+// highest to the lowest.
+func Priorities(ctx context.Context, client *gojira.Client) ([]string, error) {
+	priorities, resp, err := client.Priority.GetListWithContext(ctx)
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	names := make([]string, 0, len(priorities))
+	for _, priority := range priorities {
+		names = append(names, priority.Name)
+	}
+
+	return names, nil
+}
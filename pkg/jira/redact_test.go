@@ -0,0 +1,114 @@
+package jira
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const (
+	// testUsername is the username of the credentials of the tests.
+	testUsername = "jane@example.com"
+	// testSecret is the password or token of the credentials of the tests,
+	// having characters escaped differently in the queries and the paths.
+	testSecret = "s3cr3t/p@ss w+rd&="
+)
+
+// secretVariants returns the forms of the credentials that must not be part
+// of the error messages.
+func secretVariants() map[string]string {
+	basicAuth := base64.StdEncoding.EncodeToString([]byte(testUsername + ":" + testSecret))
+
+	return map[string]string{
+		"secret":        testSecret,
+		"query escaped": url.QueryEscape(testSecret),
+		"path escaped":  url.PathEscape(testSecret),
+		"base64":        base64.StdEncoding.EncodeToString([]byte(testSecret)),
+		"basic auth":    basicAuth,
+		"userinfo":      url.UserPassword(testUsername, testSecret).String() + "@",
+	}
+}
+
+// assertRedacted fails the test if the message contains any variant of the
+// credentials.
+func assertRedacted(t *testing.T, message string) {
+	t.Helper()
+
+	for name, variant := range secretVariants() {
+		if strings.Contains(message, variant) {
+			t.Errorf("the %s credentials are part of the error: %s", name, message)
+		}
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	auth := Auth{Username: testUsername, Password: testSecret}
+	wrapped := errors.New("wrapped")
+
+	tests := map[string]string{
+		"userinfo":      fmt.Sprintf(`Get "https://%s@jira.example.com/rest/api/2/myself": dial tcp: i/o timeout`, url.UserPassword(testUsername, testSecret)),
+		"query escaped": fmt.Sprintf(`Get "https://jira.example.com/rest/api/2/search?token=%s": EOF`, url.QueryEscape(testSecret)),
+		"path escaped":  fmt.Sprintf(`Get "https://jira.example.com/rest/api/2/user/%s": EOF`, url.PathEscape(testSecret)),
+		"base64":        fmt.Sprintf("unexpected token %s", base64.StdEncoding.EncodeToString([]byte(testSecret))),
+		"basic auth":    fmt.Sprintf("the proxy rejected the request: Authorization: Basic %s", base64.StdEncoding.EncodeToString([]byte(testUsername+":"+testSecret))),
+		"secret":        fmt.Sprintf("invalid password %q", testSecret),
+	}
+
+	for name, message := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := RedactError(fmt.Errorf("%s: %w", message, wrapped), auth.Secrets()...)
+
+			assertRedacted(t, err.Error())
+
+			if !strings.Contains(err.Error(), redactedText) && name != "userinfo" {
+				t.Errorf("the credentials are not replaced: %s", err)
+			}
+
+			if !errors.Is(err, wrapped) {
+				t.Errorf("the redacted error does not wrap the error: %v", err)
+			}
+		})
+	}
+}
+
+// echoTransport fails every request with an error containing the URL and the
+// authorization header of the request, like a misbehaving proxy.
+type echoTransport struct{}
+
+// RoundTrip fails the request.
+func (echoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("the proxy rejected %s with %q", req.URL, req.Header.Get("Authorization"))
+}
+
+func TestClientErrorRedacted(t *testing.T) {
+	tests := map[string]Auth{
+		"basic": {Type: AuthBasic, Username: testUsername, Password: testSecret},
+		"token": {Type: AuthToken, Username: testUsername, Token: testSecret},
+		"pat":   {Type: AuthPAT, Token: testSecret},
+	}
+
+	serverURL := fmt.Sprintf("https://%s@jira.example.com/%s/", url.UserPassword(testUsername, testSecret), url.PathEscape(testSecret))
+
+	for name, auth := range tests {
+		t.Run(name, func(t *testing.T) {
+			auth.Transport = echoTransport{}
+
+			client, err := NewClient(serverURL, auth)
+			if err != nil {
+				t.Fatalf("creating the client: %v", err)
+			}
+
+			_, err = FetchIssues(context.Background(), client, fmt.Sprintf("text ~ %q", testSecret))
+			if err == nil {
+				t.Fatal("the search did not fail")
+			}
+
+			assertRedacted(t, auth.WrapError(serverURL, err).Error())
+		})
+	}
+}
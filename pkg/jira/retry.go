@@ -0,0 +1,153 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/logging"
+	"gabor-boros/sprint-update/pkg/metrics"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts made by RetryTransport.
+	DefaultMaxAttempts = 4
+	// DefaultRetryTimeout is the total time RetryTransport spends on a request,
+	// including the delays between the attempts.
+	DefaultRetryTimeout = 2 * time.Minute
+	// baseRetryDelay is the delay before the first retry, doubled after every
+	// attempt.
+	baseRetryDelay = 500 * time.Millisecond
+	// maxRetryDelay is the maximum delay between two attempts.
+	maxRetryDelay = 30 * time.Second
+)
+
+// ErrRetriesExhausted is returned when the request still fails after every
+// attempt.
+var ErrRetriesExhausted = errors.New("jira request failed after retrying")
+
+// RetryTransport is an http.RoundTripper retrying the requests rejected by
+// rate limiting or temporary unavailability. The Retry-After header of the
+// response is honored; otherwise, the delay grows exponentially with jitter.
+type RetryTransport struct {
+	// MaxAttempts is the number of attempts made. When zero,
+	// DefaultMaxAttempts is used.
+	MaxAttempts int
+	// Timeout is the total time spent on a request, after which no more
+	// attempts are made. When zero, DefaultRetryTimeout is used.
+	Timeout time.Duration
+
+	// Transport is the underlying HTTP transport to use when making requests.
+	// It will default to http.DefaultTransport if nil.
+	Transport http.RoundTripper
+}
+
+// isRetryable reports whether the request can be retried after receiving the
+// given status code.
+func isRetryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// RoundTrip implements the RoundTripper interface by retrying the request
+// until it succeeds, the attempts are exhausted, or the timeout is reached.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	maxAttempts := t.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRetryTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("%w: request body cannot be replayed", ErrRetriesExhausted)
+			}
+
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := transport.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isRetryable(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := retryDelay(resp, attempt)
+
+		// Drain the body so the connection can be reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if attempt >= maxAttempts || time.Now().Add(delay).After(deadline) {
+			return nil, fmt.Errorf("%w: status %d after %d attempts", ErrRetriesExhausted, resp.StatusCode, attempt)
+		}
+
+		logging.FromContext(req.Context()).Verbose("retrying request", "method", req.Method, "url", req.URL.Redacted(), "status", resp.StatusCode, "attempt", attempt, "delay", delay)
+		metrics.FromContext(req.Context()).AddRetry()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns the delay before the next attempt. The delay requested
+// by the Retry-After header takes precedence; otherwise, the delay is doubled
+// after every attempt, and randomized to avoid synchronized retries.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			if delay := time.Until(date); delay > 0 {
+				return delay
+			}
+
+			return 0
+		}
+	}
+
+	delay := baseRetryDelay << uint(attempt-1)
+	if delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+
+	// Use a random delay between the half and the full delay.
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
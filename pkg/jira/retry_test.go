@@ -0,0 +1,190 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// responseWithRetryAfter returns a rate limited response having the given
+// Retry-After header, if any.
+func responseWithRetryAfter(retryAfter string) *http.Response {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: make(http.Header)}
+	if retryAfter != "" {
+		resp.Header.Set("Retry-After", retryAfter)
+	}
+
+	return resp
+}
+
+func TestRetryDelayRetryAfter(t *testing.T) {
+	tests := map[string]struct {
+		retryAfter string
+		min        time.Duration
+		max        time.Duration
+	}{
+		"seconds":                     {retryAfter: "7", min: 7 * time.Second, max: 7 * time.Second},
+		"zero seconds":                {retryAfter: "0", min: 0, max: 0},
+		"longer than the backoff cap": {retryAfter: "120", min: 120 * time.Second, max: 120 * time.Second},
+		"http date":                   {retryAfter: time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat), min: 8 * time.Second, max: 10 * time.Second},
+		"past http date":              {retryAfter: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), min: 0, max: 0},
+		"malformed":                   {retryAfter: "soon", min: baseRetryDelay / 2, max: baseRetryDelay},
+		"negative":                    {retryAfter: "-5", min: baseRetryDelay / 2, max: baseRetryDelay},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := retryDelay(responseWithRetryAfter(tt.retryAfter), 1); got < tt.min || got > tt.max {
+				t.Errorf("retryDelay() = %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryDelayBackoff(t *testing.T) {
+	tests := map[string]struct {
+		attempt int
+		max     time.Duration
+	}{
+		"first attempt":  {attempt: 1, max: baseRetryDelay},
+		"third attempt":  {attempt: 3, max: 4 * baseRetryDelay},
+		"capped":         {attempt: 10, max: maxRetryDelay},
+		"shift overflow": {attempt: 100, max: maxRetryDelay},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := retryDelay(responseWithRetryAfter(""), tt.attempt); got < tt.max/2 || got > tt.max {
+					t.Fatalf("retryDelay() = %s, want between %s and %s", got, tt.max/2, tt.max)
+				}
+			}
+		})
+	}
+}
+
+// retryServer responds by the given status codes in order, recording the
+// bodies of the requests.
+type retryServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+}
+
+// newRetryServer returns a server responding by the status codes, and by 200
+// OK once the status codes are exhausted.
+func newRetryServer(t *testing.T, statuses ...int) *retryServer {
+	t.Helper()
+
+	s := &retryServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		status := http.StatusOK
+		if len(s.bodies) < len(s.statuses) {
+			status = s.statuses[len(s.bodies)]
+		}
+
+		s.bodies = append(s.bodies, string(body))
+
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestRetryTransportReplaysBody(t *testing.T) {
+	server := newRetryServer(t, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, bytes.NewReader([]byte(`{"jql":"project = SE"}`)))
+	if err != nil {
+		t.Fatalf("creating the request: %v", err)
+	}
+
+	resp, err := (&RetryTransport{}).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	want := []string{`{"jql":"project = SE"}`, `{"jql":"project = SE"}`, `{"jql":"project = SE"}`}
+	if len(server.bodies) != len(want) {
+		t.Fatalf("bodies = %q, want %q", server.bodies, want)
+	}
+
+	for i := range want {
+		if server.bodies[i] != want[i] {
+			t.Errorf("body of attempt %d = %q, want %q", i+1, server.bodies[i], want[i])
+		}
+	}
+}
+
+func TestRetryTransportExhausted(t *testing.T) {
+	server := newRetryServer(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("creating the request: %v", err)
+	}
+
+	if _, err := (&RetryTransport{MaxAttempts: 2}).RoundTrip(req); !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("RoundTrip() error = %v, want %v", err, ErrRetriesExhausted)
+	}
+
+	if len(server.bodies) != 2 {
+		t.Errorf("attempts = %d, want 2", len(server.bodies))
+	}
+}
+
+func TestRetryTransportNotRetryable(t *testing.T) {
+	server := newRetryServer(t, http.StatusBadRequest)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("creating the request: %v", err)
+	}
+
+	resp, err := (&RetryTransport{}).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest || len(server.bodies) != 1 {
+		t.Errorf("status = %d after %d attempts, want %d after 1 attempt", resp.StatusCode, len(server.bodies), http.StatusBadRequest)
+	}
+}
+
+func TestRetryTransportBodyNotReplayable(t *testing.T) {
+	server := newRetryServer(t, http.StatusTooManyRequests)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, io.NopCloser(bytes.NewReader([]byte("body"))))
+	if err != nil {
+		t.Fatalf("creating the request: %v", err)
+	}
+
+	if _, err := (&RetryTransport{}).RoundTrip(req); !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("RoundTrip() error = %v, want %v", err, ErrRetriesExhausted)
+	}
+
+	if len(server.bodies) != 1 {
+		t.Errorf("attempts = %d, want 1", len(server.bodies))
+	}
+}
@@ -0,0 +1,213 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// apiPathPrefix is the path prefix of the Jira REST APIs. The path is recorded
+// starting from the prefix, so the snapshots of the requests sent through the
+// Atlassian API gateway can be replayed against any server URL.
+const apiPathPrefix = "/rest/"
+
+// ErrNotRecorded is returned when replaying a request missing from the
+// snapshot.
+var ErrNotRecorded = errors.New("request not found in the snapshot")
+
+// Snapshot holds the raw responses of the Jira API.
+type Snapshot struct {
+	// ServerURL is the URL of the Jira server the responses were recorded
+	// from, used for linking the issues when replaying.
+	ServerURL string `json:"serverURL"`
+	// Responses lists the responses in the order they were received.
+	Responses []RecordedResponse `json:"responses"`
+}
+
+// RecordedResponse is a raw response of the Jira API.
+type RecordedResponse struct {
+	Method     string `json:"method"`
+	URL        string `json:"url"`
+	StatusCode int    `json:"statusCode"`
+	Body       string `json:"body"`
+}
+
+// LoadSnapshot reads the snapshot from the file at the given path.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// Save writes the snapshot to the file at the given path, creating its
+// directory if necessary.
+func (s *Snapshot) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// requestURL returns the recorded URL of the request, which is the path
+// starting from the REST API prefix and the query. The credentials are never
+// part of the recorded URL.
+func requestURL(req *http.Request) string {
+	uri := req.URL.RequestURI()
+	if i := strings.Index(uri, apiPathPrefix); i >= 0 {
+		uri = uri[i:]
+	}
+
+	return uri
+}
+
+// Recorder is an http.RoundTripper recording the responses into a Snapshot.
+type Recorder struct {
+	// Transport is the underlying HTTP transport to use when making requests.
+	// It will default to http.DefaultTransport if nil.
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	snapshot Snapshot
+}
+
+// RoundTrip implements the RoundTripper interface by recording the response
+// of the request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mu.Lock()
+	r.snapshot.Responses = append(r.snapshot.Responses, RecordedResponse{
+		Method:     req.Method,
+		URL:        requestURL(req),
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Snapshot returns the responses recorded so far from the given server.
+func (r *Recorder) Snapshot(serverURL string) *Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Snapshot{
+		ServerURL: serverURL,
+		Responses: append([]RecordedResponse{}, r.snapshot.Responses...),
+	}
+}
+
+// Replayer is an http.RoundTripper responding to the requests from a
+// Snapshot without contacting Jira.
+type Replayer struct {
+	mu       sync.Mutex
+	snapshot *Snapshot
+	replayed []bool
+}
+
+// NewReplayer returns a new Replayer responding from the given snapshot.
+func NewReplayer(snapshot *Snapshot) *Replayer {
+	return &Replayer{
+		snapshot: snapshot,
+		replayed: make([]bool, len(snapshot.Responses)),
+	}
+}
+
+// RoundTrip implements the RoundTripper interface by returning the recorded
+// response of the request. Every response is replayed once: requests are
+// matched by their method and URL first; as the queries may contain the
+// current date, requests not found are matched by their method and path, in
+// the order they were recorded.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uri := requestURL(req)
+	path := strings.SplitN(uri, "?", 2)[0]
+
+	match := -1
+	for i, recorded := range r.snapshot.Responses {
+		if !r.replayed[i] && recorded.Method == req.Method && recorded.URL == uri {
+			match = i
+			break
+		}
+	}
+
+	if match == -1 {
+		for i, recorded := range r.snapshot.Responses {
+			if !r.replayed[i] && recorded.Method == req.Method && strings.SplitN(recorded.URL, "?", 2)[0] == path {
+				match = i
+				break
+			}
+		}
+	}
+
+	// Requests sent more times than recorded get the last matching response.
+	if match == -1 {
+		for i, recorded := range r.snapshot.Responses {
+			if recorded.Method == req.Method && recorded.URL == uri {
+				match = i
+			}
+		}
+	}
+
+	if match == -1 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, uri)
+	}
+
+	r.replayed[match] = true
+	recorded := r.snapshot.Responses[match]
+
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode: recorded.StatusCode,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(recorded.Body)),
+		Request:    req,
+	}, nil
+}
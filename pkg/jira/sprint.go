@@ -0,0 +1,298 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/logging"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// sprintFieldSchema is the custom schema type of the Jira Agile sprint field.
+const sprintFieldSchema = "com.pyxis.greenhopper.jira:gh-sprint"
+
+// sprintFieldName is the default name of the sprint field, which is used in
+// the JQL queries if the ID of the field is unknown.
+const sprintFieldName = "Sprint"
+
+// customFieldPrefix is the prefix of the IDs of the custom fields, like
+// customfield_10020.
+const customFieldPrefix = "customfield_"
+
+// sprintClosedState is the state of sprints that were completed.
+const sprintClosedState = "closed"
+
+// sprintActiveState is the state of the sprints in progress.
+const sprintActiveState = "active"
+
+// sprintFutureState is the state of the sprints not started yet.
+const sprintFutureState = "future"
+
+// ErrNoActiveSprint is returned when the board has no active sprint.
+var ErrNoActiveSprint = errors.New("no active sprint found on the board")
+
+// ErrSprintNotFound is returned when no issue of the sprint is found, hence
+// the details of the sprint are unknown.
+var ErrSprintNotFound = errors.New("sprint not found")
+
+// legacySprintPattern matches the attributes of the string representation of
+// sprints returned by older Jira Server versions, like
+// "com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,state=CLOSED,name=SE.252,...]".
+var legacySprintPattern = regexp.MustCompile(`(\w+)=([^,\]]*)`)
+
+// Sprint represents a sprint an issue was part of.
+type Sprint struct {
+	ID        int
+	Name      string
+	State     string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// IsClosed reports whether the sprint is completed.
+func (s *Sprint) IsClosed() bool {
+	return strings.EqualFold(s.State, sprintClosedState)
+}
+
+// newSprint returns a new Sprint from the given gojira.Sprint.
+func newSprint(s *gojira.Sprint) Sprint {
+	return Sprint{
+		ID:        s.ID,
+		Name:      s.Name,
+		State:     s.State,
+		StartDate: s.StartDate,
+		EndDate:   s.EndDate,
+	}
+}
+
+// FindActiveSprint returns the active sprint of the given board using the
+// Jira Agile API. If the board has multiple active sprints, the first one is
+// returned.
+func FindActiveSprint(ctx context.Context, client *gojira.Client, boardID int) (*Sprint, error) {
+	sprints, resp, err := client.Board.GetAllSprintsWithOptionsWithContext(ctx, boardID, &gojira.GetAllSprintsOptions{
+		State: sprintActiveState,
+	})
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	if len(sprints.Values) == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrNoActiveSprint, boardID)
+	}
+
+	activeSprint := newSprint(&sprints.Values[0])
+	return &activeSprint, nil
+}
+
+// ListSprints returns the future, active, and closed sprints of the given
+// board using the Jira Agile API. The sprints are returned in the order Jira
+// lists them, which is the order they were created in.
+func ListSprints(ctx context.Context, client *gojira.Client, boardID int) ([]Sprint, error) {
+	var sprints []Sprint
+
+	for startAt := 0; ; {
+		page, resp, err := client.Board.GetAllSprintsWithOptionsWithContext(ctx, boardID, &gojira.GetAllSprintsOptions{
+			State:         strings.Join([]string{sprintFutureState, sprintActiveState, sprintClosedState}, ","),
+			SearchOptions: gojira.SearchOptions{StartAt: startAt},
+		})
+		if err != nil {
+			return nil, RedactError(jiraError(err, resp))
+		}
+
+		for i := range page.Values {
+			sprints = append(sprints, newSprint(&page.Values[i]))
+		}
+
+		logging.FromContext(ctx).Debug("fetched sprints page", "board", boardID, "start_at", startAt, "sprints", len(page.Values))
+
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			return sprints, nil
+		}
+	}
+}
+
+// FindBoardSprint returns the sprint of the given board having the given
+// name using the Jira Agile API. If multiple sprints of the board have the
+// name, the last created one is returned.
+func FindBoardSprint(ctx context.Context, client *gojira.Client, boardID int, name string) (*Sprint, error) {
+	sprints, err := ListSprints(ctx, client, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(sprints) - 1; i >= 0; i-- {
+		if sprints[i].Name == name {
+			return &sprints[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s on board %d", ErrSprintNotFound, name, boardID)
+}
+
+// FetchSprint returns the sprint having the given ID using the Jira Agile API.
+func FetchSprint(ctx context.Context, client *gojira.Client, sprintID int) (*Sprint, error) {
+	req, err := client.NewRequestWithContext(ctx, "GET", fmt.Sprintf("rest/agile/1.0/sprint/%d", sprintID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var s gojira.Sprint
+	resp, err := client.Do(req, &s)
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	fetchedSprint := newSprint(&s)
+	return &fetchedSprint, nil
+}
+
+// FindSprint returns the sprint having the given name, as read from the sprint
+// field of one of its issues.
+func FindSprint(ctx context.Context, client *gojira.Client, name string, sprintFieldID string) (*Sprint, error) {
+	issues, resp, err := client.Issue.SearchWithContext(ctx, SprintJQLField(sprintFieldID)+" = "+QuoteJQL(name), &gojira.SearchOptions{
+		MaxResults: 1,
+		Fields:     []string{sprintFieldID},
+	})
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	for _, issue := range issues {
+		for _, s := range ParseSprints(issue.Fields.Unknowns[sprintFieldID]) {
+			if s.Name == name {
+				return &s, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrSprintNotFound, name)
+}
+
+// FindSprintFieldID returns the ID of the Jira Agile sprint custom field,
+// found by its schema type, as the field may be renamed. If there are multiple
+// sprint fields, like the field of the team-managed projects besides the
+// field of the company-managed ones, the field named "Sprint" is preferred,
+// then the first field. If the field does not exist, an empty string is
+// returned.
+func FindSprintFieldID(ctx context.Context, client *gojira.Client) (string, error) {
+	fields, resp, err := client.Field.GetListWithContext(ctx)
+	if err != nil {
+		return "", RedactError(jiraError(err, resp))
+	}
+
+	var id string
+	for _, field := range fields {
+		if field.Schema.Custom != sprintFieldSchema {
+			continue
+		}
+
+		if strings.EqualFold(field.Name, sprintFieldName) {
+			return field.ID, nil
+		}
+
+		if id == "" {
+			id = field.ID
+		}
+	}
+
+	return id, nil
+}
+
+// SprintJQLField returns the JQL name of the sprint field of the given ID,
+// like cf[10020] for customfield_10020, which works even if the field is
+// renamed. If the ID is empty, the default name of the field is returned.
+func SprintJQLField(sprintFieldID string) string {
+	switch {
+	case sprintFieldID == "":
+		return sprintFieldName
+	case strings.HasPrefix(sprintFieldID, customFieldPrefix):
+		return "cf[" + strings.TrimPrefix(sprintFieldID, customFieldPrefix) + "]"
+	default:
+		return sprintFieldID
+	}
+}
+
+// ParseSprints parses the value of the sprint custom field.
+func ParseSprints(value interface{}) []Sprint {
+	values, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	sprints := make([]Sprint, 0, len(values))
+	for _, v := range values {
+		switch rawSprint := v.(type) {
+		case map[string]interface{}:
+			sprints = append(sprints, parseSprintObject(rawSprint))
+		case string:
+			sprints = append(sprints, parseLegacySprint(rawSprint))
+		}
+	}
+
+	return sprints
+}
+
+// parseSprintObject parses a sprint returned as a JSON object.
+func parseSprintObject(rawSprint map[string]interface{}) Sprint {
+	var s Sprint
+
+	if id, ok := rawSprint["id"].(float64); ok {
+		s.ID = int(id)
+	}
+
+	s.Name, _ = rawSprint["name"].(string)
+	s.State, _ = rawSprint["state"].(string)
+	s.StartDate = parseSprintDate(rawSprint["startDate"])
+	s.EndDate = parseSprintDate(rawSprint["endDate"])
+
+	return s
+}
+
+// parseLegacySprint parses a sprint returned in its string representation.
+func parseLegacySprint(rawSprint string) Sprint {
+	var s Sprint
+
+	start := strings.Index(rawSprint, "[")
+	if start == -1 {
+		return s
+	}
+
+	for _, match := range legacySprintPattern.FindAllStringSubmatch(rawSprint[start:], -1) {
+		switch match[1] {
+		case "id":
+			s.ID, _ = strconv.Atoi(match[2])
+		case "name":
+			s.Name = match[2]
+		case "state":
+			s.State = match[2]
+		case "startDate":
+			s.StartDate = parseSprintDate(match[2])
+		case "endDate":
+			s.EndDate = parseSprintDate(match[2])
+		}
+	}
+
+	return s
+}
+
+// parseSprintDate parses the sprint date if it is set.
+func parseSprintDate(value interface{}) *time.Time {
+	rawDate, ok := value.(string)
+	if !ok || rawDate == "" || rawDate == "<null>" {
+		return nil
+	}
+
+	date, err := time.Parse(time.RFC3339, rawDate)
+	if err != nil {
+		return nil
+	}
+
+	return &date
+}
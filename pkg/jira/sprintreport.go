@@ -0,0 +1,97 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// SprintReport is the summary of a sprint as shown by the sprint report of
+// the board, covering every issue of the sprint, regardless of its assignee.
+type SprintReport struct {
+	// CommittedPoints is the total of the story points estimated for the
+	// issues of the sprint when it started.
+	CommittedPoints float64
+	// CompletedPoints is the total of the story points of the issues
+	// completed within the sprint.
+	CompletedPoints float64
+	// CompletedIssues is the number of the issues completed within the sprint.
+	CompletedIssues int
+	// NotCompletedIssues is the number of the issues left unresolved by the
+	// end of the sprint.
+	NotCompletedIssues int
+	// RemovedIssues is the number of the issues removed from the sprint
+	// before it was completed.
+	RemovedIssues int
+	// AddedIssues is the number of the issues added to the sprint after it
+	// started.
+	AddedIssues int
+}
+
+// sprintReportIssue is an issue of the sprint report.
+type sprintReportIssue struct {
+	Key               string `json:"key"`
+	EstimateStatistic struct {
+		StatFieldValue struct {
+			Value float64 `json:"value"`
+		} `json:"statFieldValue"`
+	} `json:"estimateStatistic"`
+	CurrentEstimateStatistic struct {
+		StatFieldValue struct {
+			Value float64 `json:"value"`
+		} `json:"statFieldValue"`
+	} `json:"currentEstimateStatistic"`
+}
+
+// sprintReportResponse is the response of the sprint report endpoint.
+type sprintReportResponse struct {
+	Contents struct {
+		CompletedIssues                   []sprintReportIssue `json:"completedIssues"`
+		IssuesNotCompletedInCurrentSprint []sprintReportIssue `json:"issuesNotCompletedInCurrentSprint"`
+		PuntedIssues                      []sprintReportIssue `json:"puntedIssues"`
+		IssueKeysAddedDuringSprint        map[string]bool     `json:"issueKeysAddedDuringSprint"`
+	} `json:"contents"`
+}
+
+// FetchSprintReport returns the sprint report of the sprint on the given
+// board, as computed by Jira Agile. The story points are the estimates of the
+// board's estimation statistic.
+func FetchSprintReport(ctx context.Context, client *gojira.Client, boardID int, sprintID int) (*SprintReport, error) {
+	path := fmt.Sprintf("rest/greenhopper/1.0/rapid/charts/sprintreport?rapidViewId=%d&sprintId=%d", boardID, sprintID)
+
+	req, err := client.NewRequestWithContext(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw sprintReportResponse
+	resp, err := client.Do(req, &raw)
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	contents := &raw.Contents
+	report := &SprintReport{
+		CompletedIssues:    len(contents.CompletedIssues),
+		NotCompletedIssues: len(contents.IssuesNotCompletedInCurrentSprint),
+		RemovedIssues:      len(contents.PuntedIssues),
+		AddedIssues:        len(contents.IssueKeysAddedDuringSprint),
+	}
+
+	// The issues added after the start of the sprint were not committed to,
+	// even if they were completed.
+	for _, issues := range [][]sprintReportIssue{contents.CompletedIssues, contents.IssuesNotCompletedInCurrentSprint, contents.PuntedIssues} {
+		for _, issue := range issues {
+			if !contents.IssueKeysAddedDuringSprint[issue.Key] {
+				report.CommittedPoints += issue.EstimateStatistic.StatFieldValue.Value
+			}
+		}
+	}
+
+	for _, issue := range contents.CompletedIssues {
+		report.CompletedPoints += issue.CurrentEstimateStatistic.StatFieldValue.Value
+	}
+
+	return report, nil
+}
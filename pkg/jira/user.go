@@ -0,0 +1,44 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// ErrUserNotFound is returned when no user has the given account ID or
+// username.
+var ErrUserNotFound = errors.New("user not found")
+
+// userLookupParams lists the query parameters identifying the users, the
+// account ID on Jira Cloud and the username on Jira Server.
+var userLookupParams = []string{"accountId", "username"}
+
+// FetchUser returns the user having the given account ID on Jira Cloud, or
+// the given username on Jira Server.
+func FetchUser(ctx context.Context, client *gojira.Client, id string) (*gojira.User, error) {
+	for _, param := range userLookupParams {
+		req, err := client.NewRequestWithContext(ctx, "GET", fmt.Sprintf("rest/api/2/user?%s=%s", param, url.QueryEscape(id)), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var user gojira.User
+		resp, err := client.Do(req, &user)
+		if err == nil {
+			return &user, nil
+		}
+
+		// Jira rejects the parameters it does not support, like the
+		// usernames on Jira Cloud.
+		if resp == nil || (resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusBadRequest) {
+			return nil, RedactError(jiraError(err, resp))
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+}
@@ -0,0 +1,71 @@
+package jira
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// FetchCurrentUser returns the identifier of the authenticated user, which is
+// the account ID on Jira Cloud and the username on Jira Server.
+func FetchCurrentUser(ctx context.Context, client *gojira.Client) (string, error) {
+	user, resp, err := client.User.GetSelfWithContext(ctx)
+	if err != nil {
+		return "", RedactError(jiraError(err, resp))
+	}
+
+	if user.AccountID != "" {
+		return user.AccountID, nil
+	}
+
+	return user.Name, nil
+}
+
+// FetchWorklogs returns the worklogs of the issue having the given key.
+func FetchWorklogs(ctx context.Context, client *gojira.Client, issueKey string) ([]gojira.WorklogRecord, error) {
+	worklog, resp, err := client.Issue.GetWorklogsWithContext(ctx, issueKey)
+	if err != nil {
+		return nil, RedactError(jiraError(err, resp))
+	}
+
+	return worklog.Worklogs, nil
+}
+
+// IsUser reports whether the user is identified by the given account ID,
+// username, key, or email address.
+func IsUser(user *gojira.User, id string) bool {
+	if user == nil || id == "" {
+		return false
+	}
+
+	for _, userID := range []string{user.AccountID, user.Name, user.Key, user.EmailAddress} {
+		if userID != "" && strings.EqualFold(userID, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TimeSpent returns the time logged by the given user between since and
+// until.
+func TimeSpent(worklogs []gojira.WorklogRecord, userID string, since time.Time, until time.Time) time.Duration {
+	var spent time.Duration
+
+	for _, worklog := range worklogs {
+		if !IsUser(worklog.Author, userID) || worklog.Started == nil {
+			continue
+		}
+
+		started := time.Time(*worklog.Started)
+		if started.Before(since) || started.After(until) {
+			continue
+		}
+
+		spent += time.Duration(worklog.TimeSpentSeconds) * time.Second
+	}
+
+	return spent
+}
@@ -0,0 +1,378 @@
+// Package linear implements the tracker of Linear, treating the cycles of a
+// team as the sprints.
+package linear
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/tracker"
+)
+
+// DefaultURL is the URL of the Linear GraphQL API.
+const DefaultURL = "https://api.linear.app/graphql"
+
+// personalKeyPrefix is the prefix of the personal API keys, which are sent
+// without the Bearer scheme, unlike the OAuth access tokens.
+const personalKeyPrefix = "lin_api_"
+
+// completedStateType is the type of the workflow states of the done issues.
+const completedStateType = "completed"
+
+// stateCategories maps the types of the workflow states to the status
+// categories of the issues.
+var stateCategories = map[string]string{
+	"triage":           report.StatusCategoryToDo,
+	"backlog":          report.StatusCategoryToDo,
+	"unstarted":        report.StatusCategoryToDo,
+	"started":          report.StatusCategoryInProgress,
+	completedStateType: report.StatusCategoryDone,
+	"canceled":         report.StatusCategoryDone,
+}
+
+// pageSize is the number of issues fetched by a single request.
+const pageSize = 100
+
+var (
+	// ErrMissingToken is returned when no Linear API key is set.
+	ErrMissingToken = errors.New("linear API key is required")
+	// ErrMissingTeam is returned when no Linear team is set.
+	ErrMissingTeam = errors.New("linear team key is required")
+	// ErrTeamNotFound is returned when the Linear team does not exist.
+	ErrTeamNotFound = errors.New("linear team not found")
+)
+
+const sprintQuery = `query($team: String!, $filter: CycleFilter) {
+  teams(filter: {key: {eq: $team}}) {
+    nodes {
+      activeCycle { ...cycle }
+      cycles(filter: $filter, first: 1) { nodes { ...cycle } }
+    }
+  }
+}
+
+fragment cycle on Cycle { id number name startsAt endsAt completedAt }`
+
+const issuesQuery = `query($filter: IssueFilter, $first: Int, $after: String) {
+  issues(filter: $filter, first: $first, after: $after) {
+    nodes {
+      identifier
+      title
+      url
+      estimate
+      dueDate
+      completedAt
+      priorityLabel
+      state { name type }
+      assignee { displayName }
+      labels { nodes { name } }
+      parent { identifier }
+      project { name }
+      team { name }
+    }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// Client is a client of the Linear GraphQL API, implementing the tracker of
+// the cycles of a team.
+type Client struct {
+	// URL is the URL of the GraphQL API. When empty, DefaultURL is used.
+	URL string
+	// Token is the personal API key or the OAuth access token of the user.
+	Token string
+	// Team is the key of the team, like "ENG".
+	Team string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+var _ tracker.Tracker = (*Client)(nil)
+
+// cycle is a cycle returned by the API.
+type cycle struct {
+	ID          string     `json:"id"`
+	Number      int        `json:"number"`
+	Name        string     `json:"name"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+}
+
+// sprint returns the sprint of the cycle. The cycles without a name are named
+// after their number, like "Cycle 42".
+func (c *cycle) sprint() *tracker.Sprint {
+	name := c.Name
+	if name == "" {
+		name = "Cycle " + strconv.Itoa(c.Number)
+	}
+
+	return &tracker.Sprint{
+		ID:        c.ID,
+		Name:      name,
+		StartDate: c.StartsAt,
+		EndDate:   c.EndsAt,
+		Closed:    c.CompletedAt != nil,
+	}
+}
+
+// sprintResponse is the response of the sprint query.
+type sprintResponse struct {
+	Teams struct {
+		Nodes []struct {
+			ActiveCycle *cycle `json:"activeCycle"`
+			Cycles      struct {
+				Nodes []cycle `json:"nodes"`
+			} `json:"cycles"`
+		} `json:"nodes"`
+	} `json:"teams"`
+}
+
+// issue is an issue returned by the API.
+type issue struct {
+	Identifier    string     `json:"identifier"`
+	Title         string     `json:"title"`
+	URL           string     `json:"url"`
+	Estimate      float64    `json:"estimate"`
+	DueDate       string     `json:"dueDate"`
+	CompletedAt   *time.Time `json:"completedAt"`
+	PriorityLabel string     `json:"priorityLabel"`
+	State         struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"state"`
+	Assignee *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"assignee"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Parent *struct {
+		Identifier string `json:"identifier"`
+	} `json:"parent"`
+	Project *struct {
+		Name string `json:"name"`
+	} `json:"project"`
+	Team struct {
+		Name string `json:"name"`
+	} `json:"team"`
+}
+
+// issuesResponse is a page of the response of the issues query.
+type issuesResponse struct {
+	Issues struct {
+		Nodes    []issue `json:"nodes"`
+		PageInfo struct {
+			HasNextPage bool   `json:"hasNextPage"`
+			EndCursor   string `json:"endCursor"`
+		} `json:"pageInfo"`
+	} `json:"issues"`
+}
+
+// graphQLResponse is the response of the GraphQL API.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Name returns the name of the tracker.
+func (c *Client) Name() string {
+	return "Linear"
+}
+
+// Sprint returns the cycle of the team of the given name or number, like
+// "Cycle 42" or "42", or the active cycle if the name is empty.
+func (c *Client) Sprint(ctx context.Context, name string) (*tracker.Sprint, error) {
+	if c.Team == "" {
+		return nil, ErrMissingTeam
+	}
+
+	variables := map[string]interface{}{"team": c.Team}
+	if name != "" {
+		number := strings.TrimSpace(strings.TrimPrefix(strings.ToLower(name), "cycle"))
+		if n, err := strconv.Atoi(number); err == nil {
+			variables["filter"] = map[string]interface{}{"number": map[string]interface{}{"eq": n}}
+		} else {
+			variables["filter"] = map[string]interface{}{"name": map[string]interface{}{"eq": name}}
+		}
+	}
+
+	var resp sprintResponse
+	if err := c.query(ctx, sprintQuery, variables, &resp); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Teams.Nodes) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTeamNotFound, c.Team)
+	}
+
+	team := resp.Teams.Nodes[0]
+	switch {
+	case name == "" && team.ActiveCycle != nil:
+		return team.ActiveCycle.sprint(), nil
+	case name != "" && len(team.Cycles.Nodes) > 0:
+		return team.Cycles.Nodes[0].sprint(), nil
+	case name == "":
+		return nil, fmt.Errorf("%w: team %s has no active cycle", tracker.ErrSprintNotFound, c.Team)
+	default:
+		return nil, fmt.Errorf("%w: %s", tracker.ErrSprintNotFound, name)
+	}
+}
+
+// Issues returns the issues of the cycle assigned to the authenticated user,
+// or to the user of the given email address or ID.
+func (c *Client) Issues(ctx context.Context, sprint *tracker.Sprint, assignee string) ([]report.Issue, error) {
+	assigneeFilter := map[string]interface{}{"isMe": map[string]interface{}{"eq": true}}
+	switch {
+	case strings.Contains(assignee, "@"):
+		assigneeFilter = map[string]interface{}{"email": map[string]interface{}{"eq": assignee}}
+	case assignee != "":
+		assigneeFilter = map[string]interface{}{"id": map[string]interface{}{"eq": assignee}}
+	}
+
+	filter := map[string]interface{}{
+		"cycle":    map[string]interface{}{"id": map[string]interface{}{"eq": sprint.ID}},
+		"assignee": assigneeFilter,
+	}
+
+	var issues []report.Issue
+	var after interface{}
+
+	for {
+		var page issuesResponse
+		if err := c.query(ctx, issuesQuery, map[string]interface{}{"filter": filter, "first": pageSize, "after": after}, &page); err != nil {
+			return nil, err
+		}
+
+		for i := range page.Issues.Nodes {
+			issues = append(issues, newIssue(&page.Issues.Nodes[i]))
+		}
+
+		if !page.Issues.PageInfo.HasNextPage {
+			return issues, nil
+		}
+
+		after = page.Issues.PageInfo.EndCursor
+	}
+}
+
+// newIssue returns the issue of the update from the Linear issue.
+func newIssue(i *issue) report.Issue {
+	transformedIssue := report.Issue{
+		Key:         i.Identifier,
+		Summary:     i.Title,
+		URL:         i.URL,
+		Status:      i.State.Name,
+		Done:        i.State.Type == completedStateType,
+		StoryPoints: i.Estimate,
+		Priority:    i.PriorityLabel,
+		Project:     i.Team.Name,
+	}
+
+	transformedIssue.StatusCategory = stateCategories[i.State.Type]
+
+	if i.Assignee != nil {
+		transformedIssue.Assignee = i.Assignee.DisplayName
+	}
+
+	for _, label := range i.Labels.Nodes {
+		transformedIssue.Labels = append(transformedIssue.Labels, label.Name)
+	}
+
+	if i.Parent != nil {
+		transformedIssue.Parent = i.Parent.Identifier
+	}
+
+	if i.Project != nil {
+		transformedIssue.Project = i.Project.Name
+	}
+
+	if i.CompletedAt != nil {
+		transformedIssue.Resolved = *i.CompletedAt
+	}
+
+	if due, err := time.ParseInLocation("2006-01-02", i.DueDate, time.Local); err == nil {
+		transformedIssue.Due = due
+	}
+
+	return transformedIssue
+}
+
+// query sends the GraphQL query and decodes its data into v.
+func (c *Client) query(ctx context.Context, query string, variables map[string]interface{}, v interface{}) error {
+	if c.Token == "" {
+		return ErrMissingToken
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	apiURL := c.URL
+	if apiURL == "" {
+		apiURL = DefaultURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if strings.HasPrefix(c.Token, personalKeyPrefix) {
+		req.Header.Set("Authorization", c.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var result graphQLResponse
+	if err = json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("linear request failed with status %d", resp.StatusCode)
+	}
+
+	if len(result.Errors) > 0 {
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+
+		return fmt.Errorf("linear request failed with status %d: %s", resp.StatusCode, strings.Join(messages, "; "))
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("linear request failed with status %d", resp.StatusCode)
+	}
+
+	return json.Unmarshal(result.Data, v)
+}
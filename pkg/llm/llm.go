@@ -0,0 +1,137 @@
+// Package llm implements a minimal client of the OpenAI-compatible chat
+// completions API, served by OpenAI and by the self-hosted model servers, like
+// Ollama, vLLM, or LocalAI.
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultBaseURL is the base URL of the OpenAI API.
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+var (
+	// ErrMissingModel is returned when no model is set.
+	ErrMissingModel = errors.New("language model is required")
+	// ErrEmptyCompletion is returned when the model returns no text.
+	ErrEmptyCompletion = errors.New("language model returned an empty completion")
+)
+
+// Message is a message of the chat completion request.
+type Message struct {
+	// Role is the author of the message, like "system" or "user".
+	Role string `json:"role"`
+	// Content is the text of the message.
+	Content string `json:"content"`
+}
+
+// Client is a chat completions API client.
+type Client struct {
+	// BaseURL is the base URL of the API, like "http://localhost:11434/v1"
+	// for Ollama. When empty, DefaultBaseURL is used.
+	BaseURL string
+	// APIKey is the API key sent as a bearer token. When empty, no
+	// Authorization header is sent, like for the local model servers.
+	APIKey string
+	// Model is the name of the model, like "gpt-4o-mini" or "llama3".
+	Model string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// completionRequest is the body of the chat completion request.
+type completionRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+}
+
+// completionResponse is the chat completion returned by the API.
+type completionResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+}
+
+// errorResponse is the error returned by the API.
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Complete returns the text the model completes the conversation of the
+// messages with.
+func (c *Client) Complete(ctx context.Context, messages []Message) (string, error) {
+	if c.Model == "" {
+		return "", ErrMissingModel
+	}
+
+	body, err := json.Marshal(completionRequest{Model: c.Model, Messages: messages})
+	if err != nil {
+		return "", err
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(baseURL, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+			return "", fmt.Errorf("completion request failed with status %d: %s", resp.StatusCode, errResp.Error.Message)
+		}
+
+		return "", fmt.Errorf("completion request failed with status %d", resp.StatusCode)
+	}
+
+	var completion completionResponse
+	if err = json.Unmarshal(respBody, &completion); err != nil {
+		return "", err
+	}
+
+	if len(completion.Choices) == 0 {
+		return "", ErrEmptyCompletion
+	}
+
+	text := strings.TrimSpace(completion.Choices[0].Message.Content)
+	if text == "" {
+		return "", ErrEmptyCompletion
+	}
+
+	return text, nil
+}
@@ -0,0 +1,154 @@
+// Package logging provides the structured logger used for troubleshooting,
+// writing the messages and their key-value pairs in logfmt format, and its
+// HTTP transport tracing the requests.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Level is the verbosity of the logger.
+type Level int
+
+const (
+	// LevelQuiet disables logging.
+	LevelQuiet Level = iota
+	// LevelVerbose logs the queries sent, the retried requests, and the
+	// decisions made while generating the update.
+	LevelVerbose
+	// LevelDebug logs the pagination progress and every HTTP request too.
+	LevelDebug
+)
+
+// String returns the name of the level used in the log lines.
+func (l Level) String() string {
+	switch l {
+	case LevelVerbose:
+		return "info"
+	case LevelDebug:
+		return "debug"
+	default:
+		return "quiet"
+	}
+}
+
+// Logger writes structured log lines, and optionally dumps the raw HTTP
+// responses. A nil Logger discards everything, so it can be used without
+// checking whether logging is enabled.
+type Logger struct {
+	mu    sync.Mutex
+	out   io.Writer
+	level Level
+	dump  io.Writer
+}
+
+// New returns a new Logger writing the lines up to the given level to out.
+// When dump is not nil, the raw HTTP responses are written to it, regardless
+// of the level.
+func New(out io.Writer, level Level, dump io.Writer) *Logger {
+	return &Logger{out: out, level: level, dump: dump}
+}
+
+// Enabled reports whether the lines of the given level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return l != nil && level != LevelQuiet && level <= l.level
+}
+
+// Verbose logs the message with the given key-value pairs at LevelVerbose.
+func (l *Logger) Verbose(msg string, keyvals ...interface{}) {
+	l.log(LevelVerbose, msg, keyvals...)
+}
+
+// Debug logs the message with the given key-value pairs at LevelDebug.
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LevelDebug, msg, keyvals...)
+}
+
+// log writes the line of the message if the level is enabled. A key missing
+// its value is logged with an empty value.
+func (l *Logger) log(level Level, msg string, keyvals ...interface{}) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	var line strings.Builder
+	line.WriteString("time=" + time.Now().Format("15:04:05.000"))
+	line.WriteString(" level=" + level.String())
+	line.WriteString(" msg=" + quote(msg))
+
+	for i := 0; i < len(keyvals); i += 2 {
+		value := ""
+		if i+1 < len(keyvals) {
+			value = formatValue(keyvals[i+1])
+		}
+
+		line.WriteString(fmt.Sprintf(" %v=%s", keyvals[i], quote(value)))
+	}
+
+	line.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, _ = io.WriteString(l.out, line.String())
+}
+
+// dumpResponse writes the raw response of the request to the dump, if any.
+func (l *Logger) dumpResponse(method string, url string, status string, body []byte) {
+	if l == nil || l.dump == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, _ = fmt.Fprintf(l.dump, "%s %s\n%s\n%s\n\n", method, url, status, body)
+}
+
+// formatValue formats the value of a key-value pair.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case time.Duration:
+		return v.Round(time.Millisecond).String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quote quotes the value if it contains whitespace, quotes, or equal signs,
+// or if it is empty.
+func quote(value string) string {
+	needsQuoting := value == "" || strings.IndexFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r)
+	}) >= 0
+
+	if needsQuoting {
+		return strconv.Quote(value)
+	}
+
+	return value
+}
+
+// contextKey is the key of the logger stored in contexts.
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the logger.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by the context, or nil if there is
+// none.
+func FromContext(ctx context.Context) *Logger {
+	logger, _ := ctx.Value(contextKey{}).(*Logger)
+	return logger
+}
@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Transport is an http.RoundTripper tracing the requests using the logger
+// carried by their context. Every request is logged with its status code and
+// duration at LevelDebug, and the raw responses are dumped if the logger has
+// a dump.
+type Transport struct {
+	// Transport is the underlying HTTP transport to use when making requests.
+	// It will default to http.DefaultTransport if nil.
+	Transport http.RoundTripper
+}
+
+// RoundTrip implements the RoundTripper interface by tracing the request.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	logger := FromContext(req.Context())
+	if logger == nil {
+		return transport.RoundTrip(req)
+	}
+
+	url := req.URL.Redacted()
+	start := time.Now()
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		logger.Debug("http request failed", "method", req.Method, "url", url, "duration", time.Since(start), "error", err)
+		return nil, err
+	}
+
+	logger.Debug("http request", "method", req.Method, "url", url, "status", resp.StatusCode, "duration", time.Since(start))
+
+	if logger.dump == nil {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	logger.dumpResponse(req.Method, url, resp.Status, body)
+
+	return resp, nil
+}
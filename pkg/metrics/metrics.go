@@ -0,0 +1,271 @@
+// Package metrics collects the metrics of the scheduled runs of the daemon,
+// like the issues fetched, the latency of the Jira requests, the retries, and
+// the results of the deliveries, and exports them as a Prometheus textfile or
+// to an OpenTelemetry collector, so the automated updates can be monitored.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// namePrefix is the prefix of the names of the metrics.
+const namePrefix = "sprint_update_"
+
+// Result is the result of a scheduled run.
+type Result string
+
+const (
+	// ResultSuccess is the result of a run delivering the update.
+	ResultSuccess Result = "success"
+	// ResultFailure is the result of a run failing to generate or deliver
+	// the update.
+	ResultFailure Result = "failure"
+	// ResultSkipped is the result of a run skipped, as it is not the
+	// scheduled week of the sprint.
+	ResultSkipped Result = "skipped"
+)
+
+// kind is the type of a metric.
+type kind string
+
+const (
+	kindCounter kind = "counter"
+	kindGauge   kind = "gauge"
+	kindSummary kind = "summary"
+)
+
+// label is a label of a data point, like the target of a delivery.
+type label struct {
+	name  string
+	value string
+}
+
+// point is a data point of a metric. The summaries have a count and a sum
+// instead of a value.
+type point struct {
+	labels []label
+	value  float64
+	count  int64
+	sum    float64
+}
+
+// metric is a collected metric with its data points.
+type metric struct {
+	// name is the name of the metric without the prefix, and without the
+	// "_total" suffix of the Prometheus counters.
+	name   string
+	help   string
+	unit   string
+	kind   kind
+	points []point
+}
+
+// requestKey identifies the Jira requests by their status class, like "2xx",
+// or "error" if no response was received.
+type requestKey string
+
+// deliveryKey identifies the deliveries by their target and result.
+type deliveryKey struct {
+	target string
+	result Result
+}
+
+// Collector collects the metrics of the runs. It is safe for concurrent use,
+// as the pages of the issues are fetched concurrently. The methods of a nil
+// collector do nothing, so the metrics are only collected when running on a
+// schedule.
+type Collector struct {
+	mu sync.Mutex
+
+	start       time.Time
+	runs        map[Result]int64
+	lastRun     time.Time
+	lastSuccess time.Time
+	lastResult  Result
+	lastElapsed time.Duration
+
+	issues         int64
+	requests       map[requestKey]int64
+	requestSeconds float64
+	requestCount   int64
+	retries        int64
+	deliveries     map[deliveryKey]int64
+}
+
+// NewCollector returns a collector without any runs.
+func NewCollector() *Collector {
+	return &Collector{
+		start:      time.Now(),
+		runs:       make(map[Result]int64),
+		requests:   make(map[requestKey]int64),
+		deliveries: make(map[deliveryKey]int64),
+	}
+}
+
+// contextKey is the key of the collector stored in contexts.
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the collector.
+func NewContext(ctx context.Context, c *Collector) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the collector carried by the context, or nil if there
+// is none.
+func FromContext(ctx context.Context) *Collector {
+	c, _ := ctx.Value(contextKey{}).(*Collector)
+	return c
+}
+
+// ObserveRun records the result of a run started at the given time.
+func (c *Collector) ObserveRun(start time.Time, result Result) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.runs[result]++
+	c.lastRun = start
+	c.lastResult = result
+	c.lastElapsed = time.Since(start)
+
+	if result == ResultSuccess {
+		c.lastSuccess = start
+	}
+}
+
+// AddIssues records the number of issues fetched from Jira.
+func (c *Collector) AddIssues(n int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.issues += int64(n)
+}
+
+// ObserveRequest records a Jira request, its response, or nil if it failed,
+// and its duration.
+func (c *Collector) ObserveRequest(resp *http.Response, duration time.Duration) {
+	if c == nil {
+		return
+	}
+
+	key := requestKey("error")
+	if resp != nil {
+		key = requestKey(strconv.Itoa(resp.StatusCode/100) + "xx")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests[key]++
+	c.requestSeconds += duration.Seconds()
+	c.requestCount++
+}
+
+// AddRetry records a retried Jira request.
+func (c *Collector) AddRetry() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retries++
+}
+
+// ObserveDelivery records the delivery of the update to the target, which
+// failed if err is not nil.
+func (c *Collector) ObserveDelivery(target string, err error) {
+	if c == nil {
+		return
+	}
+
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deliveries[deliveryKey{target: target, result: result}]++
+}
+
+// metrics returns the collected metrics. The data points are sorted by their
+// labels, so the exports are stable.
+func (c *Collector) metrics() []metric {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var runs []point
+	for _, result := range []Result{ResultSuccess, ResultFailure, ResultSkipped} {
+		runs = append(runs, point{labels: []label{{"result", string(result)}}, value: float64(c.runs[result])})
+	}
+
+	var requests []point
+	for key, n := range c.requests {
+		requests = append(requests, point{labels: []label{{"status", string(key)}}, value: float64(n)})
+	}
+
+	var deliveries []point
+	for key, n := range c.deliveries {
+		deliveries = append(deliveries, point{labels: []label{{"target", key.target}, {"result", string(key.result)}}, value: float64(n)})
+	}
+
+	sortPoints(requests)
+	sortPoints(deliveries)
+
+	lastSuccess := 0.0
+	if c.lastResult != "" && c.lastResult != ResultFailure {
+		lastSuccess = 1
+	}
+
+	return []metric{
+		{name: "runs", help: "Number of scheduled runs by their result.", kind: kindCounter, points: runs},
+		{name: "last_run_timestamp_seconds", help: "Time of the start of the last run.", unit: "s", kind: kindGauge, points: []point{{value: unixSeconds(c.lastRun)}}},
+		{name: "last_success_timestamp_seconds", help: "Time of the start of the last run delivering the update.", unit: "s", kind: kindGauge, points: []point{{value: unixSeconds(c.lastSuccess)}}},
+		{name: "last_run_duration_seconds", help: "Duration of the last run.", unit: "s", kind: kindGauge, points: []point{{value: c.lastElapsed.Seconds()}}},
+		{name: "last_run_success", help: "Whether the last run succeeded or was skipped.", kind: kindGauge, points: []point{{value: lastSuccess}}},
+		{name: "issues_fetched", help: "Number of issues fetched from Jira.", kind: kindCounter, points: []point{{value: float64(c.issues)}}},
+		{name: "jira_requests", help: "Number of Jira requests by the class of their status code.", kind: kindCounter, points: requests},
+		{name: "jira_request_duration_seconds", help: "Duration of the Jira requests.", unit: "s", kind: kindSummary, points: []point{{count: c.requestCount, sum: c.requestSeconds}}},
+		{name: "jira_retries", help: "Number of retried Jira requests.", kind: kindCounter, points: []point{{value: float64(c.retries)}}},
+		{name: "deliveries", help: "Number of deliveries by their target and result.", kind: kindCounter, points: deliveries},
+	}
+}
+
+// sortPoints sorts the data points by the values of their labels.
+func sortPoints(points []point) {
+	sort.Slice(points, func(i, j int) bool {
+		a, b := points[i].labels, points[j].labels
+		for k := range a {
+			if a[k].value != b[k].value {
+				return a[k].value < b[k].value
+			}
+		}
+
+		return false
+	})
+}
+
+// unixSeconds returns the time as seconds since the Unix epoch, or zero if
+// the time is not set.
+func unixSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return float64(t.UnixNano()) / float64(time.Second)
+}
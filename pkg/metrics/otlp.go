@@ -0,0 +1,237 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// otlpMetricsPath is the path of the metrics endpoint of the
+	// OpenTelemetry collectors, appended to the endpoint URL.
+	otlpMetricsPath = "/v1/metrics"
+	// cumulativeTemporality is the aggregation temporality of the counters,
+	// which are never reset while the daemon runs.
+	cumulativeTemporality = 2
+)
+
+// OTLPExporter exports the metrics to an OpenTelemetry collector using the
+// JSON encoding of the OTLP/HTTP protocol.
+type OTLPExporter struct {
+	// Endpoint is the URL of the collector, like "http://localhost:4318".
+	// The metrics are posted to its "/v1/metrics" path.
+	Endpoint string
+	// Header is sent with the requests, like the credentials of the
+	// collector.
+	Header http.Header
+	// ServiceName and ServiceVersion are the attributes of the resource
+	// exporting the metrics.
+	ServiceName    string
+	ServiceVersion string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// The OTLP messages, as mapped to JSON. The 64-bit integers are encoded as
+// strings, and the empty fields are left out.
+type (
+	otlpRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+
+	otlpScope struct {
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+	}
+
+	otlpMetric struct {
+		Name        string       `json:"name"`
+		Description string       `json:"description,omitempty"`
+		Unit        string       `json:"unit,omitempty"`
+		Sum         *otlpSum     `json:"sum,omitempty"`
+		Gauge       *otlpGauge   `json:"gauge,omitempty"`
+		Summary     *otlpSummary `json:"summary,omitempty"`
+	}
+
+	otlpSum struct {
+		DataPoints             []otlpDataPoint `json:"dataPoints"`
+		AggregationTemporality int             `json:"aggregationTemporality"`
+		IsMonotonic            bool            `json:"isMonotonic"`
+	}
+
+	otlpGauge struct {
+		DataPoints []otlpDataPoint `json:"dataPoints"`
+	}
+
+	otlpSummary struct {
+		DataPoints []otlpSummaryDataPoint `json:"dataPoints"`
+	}
+
+	otlpDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		AsDouble          *float64        `json:"asDouble,omitempty"`
+		AsInt             string          `json:"asInt,omitempty"`
+	}
+
+	otlpSummaryDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes,omitempty"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		Count             string          `json:"count"`
+		Sum               float64         `json:"sum"`
+	}
+
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+
+	otlpValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// Export posts the metrics of the collector to the collector endpoint.
+func (e *OTLPExporter) Export(ctx context.Context, c *Collector) error {
+	body, err := json.Marshal(c.otlpRequest(e.ServiceName, e.ServiceVersion, time.Now()))
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(e.Endpoint, "/")
+	if !strings.HasSuffix(url, otlpMetricsPath) {
+		url += otlpMetricsPath
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for key, values := range e.Header {
+		req.Header[key] = values
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := e.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("otlp export failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	return nil
+}
+
+// otlpRequest returns the export request of the metrics collected until the
+// given time. The metrics without data points are left out.
+func (c *Collector) otlpRequest(service string, version string, now time.Time) *otlpRequest {
+	start := strconv.FormatInt(c.start.UnixNano(), 10)
+	timestamp := strconv.FormatInt(now.UnixNano(), 10)
+
+	var metrics []otlpMetric
+	for _, m := range c.metrics() {
+		if len(m.points) == 0 {
+			continue
+		}
+
+		metric := otlpMetric{Name: namePrefix + m.name, Description: m.help, Unit: m.unit}
+
+		switch m.kind {
+		case kindCounter:
+			metric.Sum = &otlpSum{AggregationTemporality: cumulativeTemporality, IsMonotonic: true}
+			for _, p := range m.points {
+				metric.Sum.DataPoints = append(metric.Sum.DataPoints, otlpDataPoint{
+					Attributes:        otlpAttributes(p.labels),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      timestamp,
+					AsInt:             strconv.FormatInt(int64(p.value), 10),
+				})
+			}
+		case kindGauge:
+			metric.Gauge = &otlpGauge{}
+			for i := range m.points {
+				metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpDataPoint{
+					Attributes:   otlpAttributes(m.points[i].labels),
+					TimeUnixNano: timestamp,
+					AsDouble:     &m.points[i].value,
+				})
+			}
+		case kindSummary:
+			metric.Summary = &otlpSummary{}
+			for _, p := range m.points {
+				metric.Summary.DataPoints = append(metric.Summary.DataPoints, otlpSummaryDataPoint{
+					Attributes:        otlpAttributes(p.labels),
+					StartTimeUnixNano: start,
+					TimeUnixNano:      timestamp,
+					Count:             strconv.FormatInt(p.count, 10),
+					Sum:               p.sum,
+				})
+			}
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	resource := []otlpAttribute{{Key: "service.name", Value: otlpValue{StringValue: service}}}
+	if version != "" {
+		resource = append(resource, otlpAttribute{Key: "service.version", Value: otlpValue{StringValue: version}})
+	}
+
+	return &otlpRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource: otlpResource{Attributes: resource},
+			ScopeMetrics: []otlpScopeMetrics{{
+				Scope:   otlpScope{Name: service, Version: version},
+				Metrics: metrics,
+			}},
+		}},
+	}
+}
+
+// otlpAttributes returns the labels of a data point as OTLP attributes.
+func otlpAttributes(labels []label) []otlpAttribute {
+	var attributes []otlpAttribute
+	for _, l := range labels {
+		attributes = append(attributes, otlpAttribute{Key: l.name, Value: otlpValue{StringValue: l.value}})
+	}
+
+	return attributes
+}
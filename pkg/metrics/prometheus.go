@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// labelEscaper escapes the values of the labels in the Prometheus text
+// format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes the metrics of the collector in the Prometheus text
+// exposition format.
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	var b strings.Builder
+
+	for _, m := range c.metrics() {
+		name := namePrefix + m.name
+		if m.kind == kindCounter {
+			name += "_total"
+		}
+
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, m.help, name, m.kind)
+
+		for _, p := range m.points {
+			labels := formatLabels(p.labels)
+			if m.kind == kindSummary {
+				fmt.Fprintf(&b, "%s_sum%s %s\n%s_count%s %d\n", name, labels, formatValue(p.sum), name, labels, p.count)
+				continue
+			}
+
+			fmt.Fprintf(&b, "%s%s %s\n", name, labels, formatValue(p.value))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteTextfile writes the metrics of the collector to the file in the
+// Prometheus text format, as read by the textfile collector of the node
+// exporter. The file is replaced atomically, so the exporter never reads a
+// partially written file.
+func (c *Collector) WriteTextfile(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	if err = c.WritePrometheus(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err = file.Chmod(0644); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// formatLabels formats the labels of a data point, like
+// `{target="slack",result="success"}`, or returns an empty string if there
+// are none.
+func formatLabels(labels []label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for _, l := range labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, l.name, labelEscaper.Replace(l.value)))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue formats the value of a data point, leaving out the decimals of
+// whole numbers.
+func formatValue(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatInt(int64(value), 10)
+	}
+
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+)
+
+// Transport is an http.RoundTripper recording the requests and their
+// duration in the collector carried by their context.
+type Transport struct {
+	// Transport is the underlying HTTP transport to use when making requests.
+	// It will default to http.DefaultTransport if nil.
+	Transport http.RoundTripper
+}
+
+// RoundTrip implements the RoundTripper interface by recording the request.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	collector := FromContext(req.Context())
+	if collector == nil {
+		return transport.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(req)
+	collector.ObserveRequest(resp, time.Since(start))
+
+	return resp, err
+}
@@ -0,0 +1,127 @@
+// Package network configures the connections of every client, so Jira and the
+// delivery targets can be reached through corporate proxies and servers using
+// certificates signed by internal certificate authorities.
+package network
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+var (
+	// ErrUnsupportedProxy is returned when the scheme of the proxy URL is not
+	// supported.
+	ErrUnsupportedProxy = errors.New("unsupported proxy")
+	// ErrInvalidCACert is returned when the CA certificate file contains no
+	// PEM encoded certificates.
+	ErrInvalidCACert = errors.New("invalid CA certificate")
+)
+
+// proxySchemes lists the supported schemes of the proxy URLs.
+var proxySchemes = []string{"http", "https", "socks5"}
+
+// Settings configures the connections.
+type Settings struct {
+	// ProxyURL is the URL of the HTTP, HTTPS, or SOCKS5 proxy used for every
+	// request, like "http://proxy.example.com:8080" or
+	// "socks5://localhost:1080". When empty, the proxy is read from the
+	// HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+	ProxyURL string
+	// CACertFile is the path of a PEM file of CA certificates trusted besides
+	// the certificates of the system.
+	CACertFile string
+	// InsecureSkipVerify disables verifying the certificates of the servers.
+	// It makes the connections vulnerable to man-in-the-middle attacks, hence
+	// it should be used only as a last resort.
+	InsecureSkipVerify bool
+}
+
+// IsDefault reports whether the settings leave the connections unchanged.
+func (s *Settings) IsDefault() bool {
+	return s.ProxyURL == "" && s.CACertFile == "" && !s.InsecureSkipVerify
+}
+
+// TLSConfig returns the TLS configuration trusting the CA certificates of the
+// settings. When the settings have no TLS options, nil is returned, so the
+// defaults are used.
+func (s *Settings) TLSConfig() (*tls.Config, error) {
+	if s.CACertFile == "" && !s.InsecureSkipVerify {
+		return nil, nil
+	}
+
+	// #nosec G402 -- skipping the verification is an explicit escape hatch.
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: s.InsecureSkipVerify,
+	}
+
+	if s.CACertFile == "" {
+		return config, nil
+	}
+
+	pem, err := os.ReadFile(filepath.Clean(s.CACertFile))
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: no certificates found in %s", ErrInvalidCACert, s.CACertFile)
+	}
+
+	config.RootCAs = pool
+	return config, nil
+}
+
+// Proxy returns the function selecting the proxy of the requests, as used by
+// http.Transport.
+func (s *Settings) Proxy() (func(*http.Request) (*url.URL, error), error) {
+	if s.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+
+	proxyURL, err := url.Parse(s.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedProxy, err)
+	}
+
+	for _, scheme := range proxySchemes {
+		if proxyURL.Scheme == scheme && proxyURL.Host != "" {
+			return http.ProxyURL(proxyURL), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s (supported schemes: http, https, socks5)", ErrUnsupportedProxy, proxyURL.Redacted())
+}
+
+// Transport returns a copy of http.DefaultTransport using the proxy and the
+// TLS configuration of the settings.
+func (s *Settings) Transport() (*http.Transport, error) {
+	proxy, err := s.Proxy()
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := s.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return transport, nil
+}
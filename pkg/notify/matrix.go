@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// Matrix sends the sprint update to a Matrix room as a formatted message.
+type Matrix struct {
+	// HomeserverURL is the base URL of the homeserver, like
+	// "https://matrix.example.com".
+	HomeserverURL string
+	// AccessToken is the access token of the user sending the message.
+	AccessToken string
+	// RoomID is the ID of the room, like "!abc123:example.com".
+	RoomID string
+	// Text is sent as the plain body instead of rendering the update, if set.
+	Text string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// matrixMessage is an m.room.message event with HTML formatted body.
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// Notify sends the sprint update to the room, rendered in the markdown format
+// for clients displaying the plain body and in the html format otherwise. If
+// Text is set, only the plain body is sent.
+func (m *Matrix) Notify(ctx context.Context, update *report.Update) error {
+	message := &matrixMessage{MsgType: "m.text", Body: m.Text}
+
+	if m.Text == "" {
+		text, err := render.RenderFormat("markdown", update)
+		if err != nil {
+			return err
+		}
+
+		html, err := render.RenderFormat("html", update)
+		if err != nil {
+			return err
+		}
+
+		message.Body = text
+		message.Format = "org.matrix.custom.html"
+		message.FormattedBody = html
+	}
+
+	// The transaction ID makes retried requests idempotent.
+	txnID := strconv.FormatInt(time.Now().UnixNano(), 10)
+	endpoint := strings.TrimSuffix(m.HomeserverURL, "/") +
+		"/_matrix/client/v3/rooms/" + url.PathEscape(m.RoomID) +
+		"/send/m.room.message/" + txnID
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+m.AccessToken)
+
+	return sendJSON(ctx, m.HTTPClient, http.MethodPut, endpoint, header, message)
+}
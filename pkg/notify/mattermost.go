@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// Mattermost posts the sprint update using a Mattermost incoming webhook.
+type Mattermost struct {
+	// WebhookURL is the URL of the incoming webhook.
+	WebhookURL string
+	// Channel overrides the default channel of the webhook, if set.
+	Channel string
+	// Username overrides the username of the webhook, if set.
+	Username string
+	// Text is posted instead of rendering the update, if set.
+	Text string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// mattermostMessage is the payload of an incoming webhook.
+type mattermostMessage struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// Notify posts the sprint update rendered in the markdown format, unless Text
+// is set.
+func (m *Mattermost) Notify(ctx context.Context, update *report.Update) error {
+	text := m.Text
+	if text == "" {
+		var err error
+		if text, err = render.RenderFormat("markdown", update); err != nil {
+			return err
+		}
+	}
+
+	return sendJSON(ctx, m.HTTPClient, http.MethodPost, m.WebhookURL, nil, &mattermostMessage{
+		Text:     text,
+		Channel:  m.Channel,
+		Username: m.Username,
+	})
+}
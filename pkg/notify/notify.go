@@ -0,0 +1,74 @@
+// Package notify delivers sprint updates to chat tools and webhooks.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// Notifier delivers a sprint update to a target.
+type Notifier interface {
+	// Notify delivers the sprint update.
+	Notify(ctx context.Context, update *report.Update) error
+}
+
+// Func is an adapter to use ordinary functions as Notifiers.
+type Func func(ctx context.Context, update *report.Update) error
+
+// Notify calls f(ctx, update).
+func (f Func) Notify(ctx context.Context, update *report.Update) error {
+	return f(ctx, update)
+}
+
+// sendJSON sends the payload as JSON to the given URL using the HTTP client.
+// When the client is nil, http.DefaultClient is used.
+func sendJSON(ctx context.Context, client *http.Client, method string, url string, header http.Header, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return send(ctx, client, method, url, header, body)
+}
+
+// send sends the JSON body to the given URL using the HTTP client. When the
+// client is nil, http.DefaultClient is used.
+func send(ctx context.Context, client *http.Client, method string, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	return nil
+}
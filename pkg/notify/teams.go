@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// Teams posts the sprint update to a Microsoft Teams channel using an
+// incoming webhook.
+type Teams struct {
+	// WebhookURL is the URL of the incoming webhook of the channel.
+	WebhookURL string
+	// Text is posted instead of rendering the update, if set.
+	Text string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// teamsMessage is a legacy actionable message card, which is accepted by the
+// incoming webhooks of the channels.
+type teamsMessage struct {
+	Type    string `json:"@type"`
+	Context string `json:"@context"`
+	Summary string `json:"summary"`
+	Text    string `json:"text"`
+}
+
+// Notify posts the sprint update rendered in the markdown format, unless Text
+// is set.
+func (t *Teams) Notify(ctx context.Context, update *report.Update) error {
+	text := t.Text
+	if text == "" {
+		var err error
+		if text, err = render.RenderFormat("markdown", update); err != nil {
+			return err
+		}
+	}
+
+	return sendJSON(ctx, t.HTTPClient, http.MethodPost, t.WebhookURL, nil, &teamsMessage{
+		Type:    "MessageCard",
+		Context: "https://schema.org/extensions",
+		Summary: update.Title,
+		Text:    text,
+	})
+}
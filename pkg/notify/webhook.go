@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+const (
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the
+	// webhook requests, like "sha256=3f2a...".
+	SignatureHeader = "X-Sprint-Update-Signature"
+	// signaturePrefix prefixes the hex encoded signature in the header.
+	signaturePrefix = "sha256="
+)
+
+// Webhook posts the sprint update as JSON to a URL, for other tools, like
+// dashboards or bots, to consume.
+type Webhook struct {
+	// URL is the URL the update is posted to.
+	URL string
+	// Secret is the key of the HMAC-SHA256 signature of the request body,
+	// sent in the SignatureHeader header. When empty, the requests are not
+	// signed.
+	Secret string
+	// Text is sent as the text of the update instead of rendering the update
+	// in the markdown format, if set.
+	Text string
+	// Format is the format of Text, like "markdown" or "html". When empty,
+	// the format is markdown.
+	Format string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// webhookPayload is the body of the webhook requests.
+type webhookPayload struct {
+	// Update is the structured update, like the one of the json format.
+	Update *report.ExportedUpdate `json:"update"`
+	// Text is the update rendered in the format of Format.
+	Text string `json:"text"`
+	// Format is the format of Text, like "markdown".
+	Format string `json:"format"`
+}
+
+// Notify posts the structured update along with the update rendered in the
+// markdown format, unless Text is set.
+func (w *Webhook) Notify(ctx context.Context, update *report.Update) error {
+	text, format := w.Text, w.Format
+	if text == "" || format == "" {
+		format = "markdown"
+	}
+
+	if text == "" {
+		var err error
+		if text, err = render.RenderFormat(format, update); err != nil {
+			return err
+		}
+	}
+
+	body, err := json.Marshal(&webhookPayload{Update: update.Export(), Text: text, Format: format})
+	if err != nil {
+		return err
+	}
+
+	header := make(http.Header)
+	if w.Secret != "" {
+		header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+
+	return send(ctx, w.HTTPClient, http.MethodPost, w.URL, header, body)
+}
+
+// Sign returns the value of the SignatureHeader header of the body: the hex
+// encoded HMAC-SHA256 of the body keyed by the secret, prefixed by "sha256=".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
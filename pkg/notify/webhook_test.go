@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+func TestSign(t *testing.T) {
+	// The test case 2 of RFC 4231.
+	got := Sign("Jefe", []byte("what do ya want for nothing?"))
+	want := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+
+	if got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookNotify(t *testing.T) {
+	tests := map[string]struct {
+		webhook    Webhook
+		wantText   string
+		wantFormat string
+	}{
+		"rendered":              {wantText: "# Sprint update", wantFormat: "markdown"},
+		"edited":                {webhook: Webhook{Text: "<h1>Edited</h1>", Format: "html"}, wantText: "<h1>Edited</h1>", wantFormat: "html"},
+		"edited without format": {webhook: Webhook{Text: "Edited"}, wantText: "Edited", wantFormat: "markdown"},
+		"format without text":   {webhook: Webhook{Format: "html"}, wantText: "# Sprint update", wantFormat: "markdown"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var body []byte
+			var signature string
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+				signature = r.Header.Get(SignatureHeader)
+			}))
+			defer server.Close()
+
+			webhook := tt.webhook
+			webhook.URL = server.URL
+			webhook.Secret = "secret"
+
+			update := report.NewUpdate("Sprint update", report.Issues{}, nil, report.Options{})
+			if err := webhook.Notify(context.Background(), update); err != nil {
+				t.Fatalf("Notify() error = %v", err)
+			}
+
+			if want := Sign("secret", body); signature != want {
+				t.Errorf("signature = %s, want %s", signature, want)
+			}
+
+			var payload webhookPayload
+			if err := json.Unmarshal(body, &payload); err != nil {
+				t.Fatalf("decoding the payload: %v", err)
+			}
+
+			if !strings.Contains(payload.Text, tt.wantText) {
+				t.Errorf("text = %q, want it to contain %q", payload.Text, tt.wantText)
+			}
+
+			if payload.Format != tt.wantFormat {
+				t.Errorf("format = %q, want %q", payload.Format, tt.wantFormat)
+			}
+
+			if payload.Update == nil || payload.Update.Title != "Sprint update" {
+				t.Errorf("update = %+v, want the structured update", payload.Update)
+			}
+		})
+	}
+}
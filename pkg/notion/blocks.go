@@ -0,0 +1,540 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// maxTextLength is the maximum length of the content of a rich text object.
+const maxTextLength = 2000
+
+// Block is a block of the content of a Notion page, like a heading, a
+// paragraph, or a list item.
+type Block struct {
+	// Type is the type of the block, like "paragraph" or "heading_3".
+	Type string
+	// RichText is the text of the block.
+	RichText []RichText
+	// Children are the blocks nested under the block, like the notes of the
+	// issue list items.
+	Children []Block
+}
+
+// MarshalJSON encodes the block as a Notion block object, whose content is
+// set under the key of its type.
+func (b Block) MarshalJSON() ([]byte, error) {
+	content := make(map[string]interface{})
+	if b.RichText != nil {
+		content["rich_text"] = b.RichText
+	}
+
+	if len(b.Children) > 0 {
+		content["children"] = b.Children
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"object": "block",
+		"type":   b.Type,
+		b.Type:   content,
+	})
+}
+
+// RichText is a text rich text object.
+type RichText struct {
+	Type        string       `json:"type"`
+	Text        Text         `json:"text"`
+	Annotations *Annotations `json:"annotations,omitempty"`
+}
+
+// Text is the content of a rich text object, optionally linked.
+type Text struct {
+	Content string `json:"content"`
+	Link    *Link  `json:"link,omitempty"`
+}
+
+// Link is the link of a rich text object.
+type Link struct {
+	URL string `json:"url"`
+}
+
+// Annotations are the styles of a rich text object.
+type Annotations struct {
+	Bold   bool `json:"bold,omitempty"`
+	Italic bool `json:"italic,omitempty"`
+}
+
+// richText returns the rich text of the plain text, truncated to fit into a
+// rich text object.
+func richText(text string) []RichText {
+	return []RichText{plainText(text)}
+}
+
+// plainText returns a rich text object of the text, truncated to fit into it.
+func plainText(text string) RichText {
+	if runes := []rune(text); len(runes) > maxTextLength {
+		text = string(runes[:maxTextLength-3]) + "..."
+	}
+
+	return RichText{Type: "text", Text: Text{Content: text}}
+}
+
+// linkText returns a rich text object of the text linked to the URL. If the
+// URL is empty, the text is not linked, like for redacted updates.
+func linkText(text string, url string) RichText {
+	t := plainText(text)
+	if url != "" {
+		t.Text.Link = &Link{URL: url}
+	}
+
+	return t
+}
+
+// styledText returns a rich text object of the text with the annotations.
+func styledText(text string, annotations Annotations) RichText {
+	t := plainText(text)
+	t.Annotations = &annotations
+	return t
+}
+
+// headingBlock returns a third level heading block.
+func headingBlock(text string) Block {
+	return Block{Type: "heading_3", RichText: richText(text)}
+}
+
+// paragraphBlock returns a paragraph block of the rich text.
+func paragraphBlock(text ...RichText) Block {
+	return Block{Type: "paragraph", RichText: text}
+}
+
+// bulletBlock returns a bulleted list item of the rich text.
+func bulletBlock(text ...RichText) Block {
+	return Block{Type: "bulleted_list_item", RichText: text}
+}
+
+// dividerBlock returns a divider block.
+func dividerBlock() Block {
+	return Block{Type: "divider"}
+}
+
+// issueText returns the link of the issue followed by a dash and its
+// summary. If the issue has no key, only the summary is returned.
+func issueText(key string, url string, summary string) []RichText {
+	if key == "" {
+		return []RichText{plainText(summary)}
+	}
+
+	return []RichText{linkText(key, url), plainText(" - " + summary)}
+}
+
+// issueBlock returns the list item of the issue.
+func issueBlock(update *report.Update, issue *report.Issue, withAssignee bool) Block {
+	var suffix string
+	if issue.Change != "" {
+		suffix += fmt.Sprintf(" (%s)", issue.ChangeNote())
+	}
+
+	if withAssignee {
+		suffix += fmt.Sprintf(" (%s)", issue.Assignee)
+	}
+
+	if len(issue.BlockedBy) > 0 {
+		suffix += fmt.Sprintf(" (%s)", update.T("blocked by %s", strings.Join(issue.BlockedBy, ", ")))
+	}
+
+	if issue.Flagged {
+		suffix += fmt.Sprintf(" (%s)", update.T("flagged"))
+	}
+
+	if issue.BlockedReason != "" {
+		suffix += ": " + issue.BlockedReason
+	}
+
+	return bulletBlock(issueText(issue.Key, issue.URL, issue.Summary+suffix)...)
+}
+
+// groupBlocks returns the blocks of every issue group, headed by the name of
+// the group like the collapsible details of the Discourse format.
+func groupBlocks(update *report.Update, issues report.Issues) []Block {
+	var blocks []Block
+
+	for _, group := range update.Groups(issues) {
+		name := group.Name
+		if update.Decorated && group.Emoji != "" {
+			name = group.Emoji + " " + name
+		}
+
+		header := []RichText{styledText(name, Annotations{Italic: true})}
+		if group.StoryPoints > 0 {
+			header = append(header, plainText(fmt.Sprintf(" (%s pts)", report.FormatPoints(group.StoryPoints))))
+		}
+
+		blocks = append(blocks, paragraphBlock(header...))
+
+		for i := range group.Issues {
+			issue := &group.Issues[i]
+
+			block := issueBlock(update, issue, false)
+			if group.Status == "" {
+				block.RichText = append(block.RichText, plainText(fmt.Sprintf(" (%s)", issue.Status)))
+			}
+
+			if issue.TimeSpent > 0 {
+				block.RichText = append(block.RichText, plainText(fmt.Sprintf(" (%s)", report.FormatHours(issue.TimeSpent))))
+			}
+
+			if progress := issue.SubtaskProgress(); progress != "" {
+				block.RichText = append(block.RichText, plainText(fmt.Sprintf(" (%s)", progress)))
+			}
+
+			if len(issue.Annotations) > 0 {
+				block.RichText = append(block.RichText, plainText(fmt.Sprintf(" (%s)", strings.Join(issue.Annotations, ", "))))
+			}
+
+			if issue.Note != "" {
+				block.Children = append(block.Children, bulletBlock(plainText(issue.Note)))
+			}
+
+			for _, subtask := range issue.Subtasks {
+				block.Children = append(block.Children, bulletBlock(issueText(subtask.Key, subtask.URL, fmt.Sprintf("%s (%s)", subtask.Summary, subtask.Status))...))
+			}
+
+			blocks = append(blocks, block)
+		}
+	}
+
+	return blocks
+}
+
+// listBlocks returns a titled list of the issues of every status. If there
+// are no issues, the empty message is listed instead, unless it is empty too.
+func listBlocks(update *report.Update, title string, issues report.Issues, empty string) []Block {
+	blocks := []Block{headingBlock(title)}
+
+	for _, group := range update.Groups(issues) {
+		for i := range group.Issues {
+			blocks = append(blocks, issueBlock(update, &group.Issues[i], len(update.Members) > 0))
+		}
+	}
+
+	if len(blocks) == 1 {
+		if empty == "" {
+			return nil
+		}
+
+		blocks = append(blocks, paragraphBlock(plainText(empty)))
+	}
+
+	return blocks
+}
+
+// summaryBlocks returns the summary section, if the summary is written.
+func summaryBlocks(update *report.Update) []Block {
+	if update.Summary == "" {
+		return nil
+	}
+
+	return append([]Block{headingBlock(update.Heading(string(report.SectionSummary), "Summary"))}, TextBlocks(update.Summary)...)
+}
+
+// themeBlocks returns the themes section, if there are themes.
+func themeBlocks(update *report.Update) []Block {
+	if len(update.Themes) == 0 {
+		return nil
+	}
+
+	blocks := []Block{headingBlock(update.Heading(string(report.SectionThemes), "Themes"))}
+
+	for i := range update.Themes {
+		theme := &update.Themes[i]
+
+		var progress string
+		if theme.Total > 0 {
+			progress = update.T("%s%% done", report.FormatPoints(theme.DonePercentage())) + ", "
+		}
+
+		if theme.Issues == 1 {
+			progress += update.T("%d issue", theme.Issues)
+		} else {
+			progress += update.T("%d issues", theme.Issues)
+		}
+
+		text := append(issueText(theme.Key, theme.URL, theme.Name), plainText(": "+progress))
+		blocks = append(blocks, bulletBlock(text...))
+	}
+
+	return blocks
+}
+
+// workedOnBlocks returns the worked on section, split by member or project
+// if set.
+func workedOnBlocks(update *report.Update) []Block {
+	blocks := []Block{headingBlock(update.Heading(string(report.SectionWorkedOn), "Worked on"))}
+
+	if update.StoryPoints {
+		blocks = append(blocks, paragraphBlock(plainText(update.T(
+			"Done: %s pts of %s committed",
+			report.FormatPoints(update.DonePoints()),
+			report.FormatPoints(update.CommittedPoints()),
+		))))
+	}
+
+	switch {
+	case len(update.Members) > 0:
+		for _, member := range update.Members {
+			blocks = append(blocks, paragraphBlock(styledText(member.Name, Annotations{Bold: true})))
+			blocks = append(blocks, groupBlocks(update, member.Issues)...)
+		}
+	case len(update.Projects) > 0:
+		for i := range update.Projects {
+			project := &update.Projects[i]
+
+			count := update.T("%d issues", project.Count())
+			if project.Count() == 1 {
+				count = update.T("%d issue", project.Count())
+			}
+
+			blocks = append(blocks, paragraphBlock(styledText(project.Name, Annotations{Bold: true}), plainText(fmt.Sprintf(" (%s)", count))))
+
+			if update.StoryPoints {
+				blocks = append(blocks, paragraphBlock(plainText(update.T(
+					"Done: %s pts of %s committed",
+					report.FormatPoints(project.DonePoints()),
+					report.FormatPoints(project.CommittedPoints()),
+				))))
+			}
+
+			blocks = append(blocks, groupBlocks(update, project.Issues)...)
+		}
+	default:
+		blocks = append(blocks, groupBlocks(update, update.Issues)...)
+	}
+
+	return blocks
+}
+
+// dependencyBlocks returns the dependencies on other teams section, if there
+// are dependencies.
+func dependencyBlocks(update *report.Update) []Block {
+	if len(update.Dependencies) == 0 {
+		return nil
+	}
+
+	blocks := []Block{headingBlock(update.Heading(string(report.SectionDependencies), "Dependencies on other teams"))}
+
+	for _, dependency := range update.Dependencies {
+		text := issueText(dependency.Key, dependency.URL, dependency.Summary)
+		text = append(text, plainText(": "+dependency.Link.Relation+" "))
+		text = append(text, issueText(dependency.Link.Key, dependency.Link.URL, dependency.Link.Summary)...)
+
+		if dependency.Link.Status != "" {
+			text = append(text, plainText(fmt.Sprintf(" (%s)", dependency.Link.Status)))
+		}
+
+		blocks = append(blocks, bulletBlock(text...))
+	}
+
+	return blocks
+}
+
+// pullRequestBlocks returns the pull requests section, if there are pull
+// requests.
+func pullRequestBlocks(update *report.Update) []Block {
+	if len(update.PullRequests) == 0 {
+		return nil
+	}
+
+	blocks := []Block{headingBlock(update.Heading(string(report.SectionPullRequests), "Pull requests"))}
+
+	for _, pr := range update.PullRequests {
+		text := []RichText{linkText(fmt.Sprintf("%s#%d", pr.Repository, pr.Number), pr.URL), plainText(" - " + pr.Title)}
+		if pr.Merged {
+			text = append(text, plainText(fmt.Sprintf(" (%s)", update.T("merged"))))
+		}
+
+		for i, issue := range pr.Issues {
+			separator := ", "
+			if i == 0 {
+				separator = " - "
+			}
+
+			text = append(text, plainText(separator), linkText(issue.Key, issue.URL))
+		}
+
+		blocks = append(blocks, bulletBlock(text...))
+	}
+
+	return blocks
+}
+
+// commitBlocks returns the commits section, if there are commits, listing
+// the commits under the issues they reference.
+func commitBlocks(update *report.Update) []Block {
+	if len(update.Commits) == 0 {
+		return nil
+	}
+
+	blocks := []Block{headingBlock(update.Heading(string(report.SectionCommits), "Commits"))}
+
+	for _, group := range update.Commits {
+		switch {
+		case group.Key == "":
+			blocks = append(blocks, paragraphBlock(styledText(update.T("No issue"), Annotations{Italic: true})))
+		case group.Summary != "":
+			blocks = append(blocks, paragraphBlock(linkText(group.Key, group.URL), plainText(" - "+group.Summary)))
+		default:
+			blocks = append(blocks, paragraphBlock(linkText(group.Key, group.URL)))
+		}
+
+		for i := range group.Commits {
+			commit := &group.Commits[i]
+			blocks = append(blocks, bulletBlock(plainText(fmt.Sprintf("%s@%s - %s", commit.Repository, commit.ShortHash(), commit.Subject))))
+		}
+	}
+
+	return blocks
+}
+
+// timesheetBlocks returns the section of the hours logged in Tempo, if Tempo
+// is used, listing the done issues without logged time.
+func timesheetBlocks(update *report.Update) []Block {
+	if update.Timesheet == nil {
+		return nil
+	}
+
+	total := update.T("%s logged", report.FormatHours(update.Timesheet.Total))
+	if update.Timesheet.Approved {
+		total += fmt.Sprintf(" (%s)", update.T("approved"))
+	}
+
+	blocks := []Block{headingBlock(update.Heading(string(report.SectionHours), "Hours")), paragraphBlock(plainText(total))}
+	if len(update.Timesheet.Unlogged) > 0 {
+		blocks = append(blocks, paragraphBlock(plainText(update.T("Done without logged time:"))))
+	}
+
+	for _, issue := range update.Timesheet.Unlogged {
+		blocks = append(blocks, bulletBlock(issueText(issue.Key, issue.URL, issue.Summary)...))
+	}
+
+	return blocks
+}
+
+// kudosBlocks returns the kudos section, listing a placeholder if there are
+// no kudos.
+func kudosBlocks(update *report.Update) []Block {
+	blocks := []Block{headingBlock(update.Heading(string(report.SectionKudos), "Kudos"))}
+
+	for _, k := range update.KudosOrPlaceholder() {
+		blocks = append(blocks, bulletBlock(plainText(k)))
+	}
+
+	for _, k := range update.SuggestedKudos {
+		blocks = append(blocks, bulletBlock(plainText(fmt.Sprintf("%s (%s)", k.Name, update.T("suggested: %s", update.KudosReason(k))))))
+	}
+
+	return blocks
+}
+
+// statsBlocks returns the velocity of the sprint, if the sprint report is
+// read, and the cycle time of the issues, if their timeline is read.
+func statsBlocks(update *report.Update) []Block {
+	stats, cycleTime := update.Stats, update.CycleTime
+	if stats == nil && cycleTime == nil {
+		return nil
+	}
+
+	blocks := []Block{headingBlock(update.Heading(string(report.SectionStats), "Velocity"))}
+
+	if stats != nil {
+		blocks = append(blocks,
+			bulletBlock(plainText(update.T("%s of %s committed pts completed (%s%%)", report.FormatPoints(stats.CompletedPoints), report.FormatPoints(stats.CommittedPoints), report.FormatPoints(stats.CompletionPercentage())))),
+			bulletBlock(plainText(update.T("%d issues completed, %d not completed, %d added after the start", stats.CompletedIssues, stats.NotCompletedIssues, stats.AddedIssues))),
+		)
+	}
+
+	if cycleTime != nil {
+		blocks = append(blocks, bulletBlock(plainText(update.T("%d issues done in %s days on average, %s days at the median", cycleTime.Issues, report.FormatPoints(cycleTime.AverageDays()), report.FormatPoints(cycleTime.MedianDays())))))
+	}
+
+	return blocks
+}
+
+// sectionBlocks returns the blocks of the section of the update, or nil if
+// the section is left out, like the pull requests section without pull
+// requests.
+func sectionBlocks(update *report.Update, section report.Section) []Block {
+	switch section {
+	case report.SectionSummary:
+		return summaryBlocks(update)
+	case report.SectionThemes:
+		return themeBlocks(update)
+	case report.SectionWorkedOn:
+		return workedOnBlocks(update)
+	case report.SectionBlocked:
+		return listBlocks(update, update.Heading(string(section), "Blocked / Needs help"), update.Blocked, "")
+	case report.SectionDependencies:
+		return dependencyBlocks(update)
+	case report.SectionPullRequests:
+		return pullRequestBlocks(update)
+	case report.SectionCommits:
+		return commitBlocks(update)
+	case report.SectionHours:
+		return timesheetBlocks(update)
+	case report.SectionSpillovers:
+		return listBlocks(update, update.Heading(string(section), "Spillovers"), update.Spillovers, update.T("No spillovers in this sprint."))
+	case report.SectionCarriedOver:
+		return listBlocks(update, update.Heading(string(section), "Carried over from %s", update.CarriedOverFrom), update.CarriedOver, "")
+	case report.SectionKudos:
+		return kudosBlocks(update)
+	case report.SectionTimeOff:
+		return []Block{headingBlock(update.Heading(string(section), "Time off")), paragraphBlock(plainText(update.TimeOffText()))}
+	case report.SectionStats:
+		return statsBlocks(update)
+	}
+
+	return nil
+}
+
+// NewBlocks returns the sprint update as the blocks of a Notion page, having
+// the sections of the update in their order, separated by dividers. The
+// title of the update is the title of the page, hence it is not repeated.
+func NewBlocks(update *report.Update) []Block {
+	var blocks []Block
+
+	for _, section := range update.OrderedSections() {
+		content := sectionBlocks(update, section)
+		if len(content) == 0 {
+			continue
+		}
+
+		if len(blocks) > 0 {
+			blocks = append(blocks, dividerBlock())
+		}
+
+		blocks = append(blocks, content...)
+	}
+
+	if note := update.ProvenanceNote(); note != "" {
+		blocks = append(blocks, dividerBlock(), paragraphBlock(styledText(note, Annotations{Italic: true})))
+	}
+
+	return blocks
+}
+
+// TextBlocks returns the text as paragraph blocks, one per non-empty line,
+// like the edited text of the update.
+func TextBlocks(text string) []Block {
+	var blocks []Block
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		blocks = append(blocks, paragraphBlock(plainText(line)))
+	}
+
+	return blocks
+}
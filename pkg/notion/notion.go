@@ -0,0 +1,210 @@
+// Package notion implements a minimal Notion API client for archiving sprint
+// updates as pages of a database.
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the base URL of the Notion API.
+const DefaultBaseURL = "https://api.notion.com/v1"
+
+// apiVersion is the version of the Notion API the requests are made against.
+const apiVersion = "2022-06-28"
+
+// maxChildren is the maximum number of blocks created by a single request.
+const maxChildren = 100
+
+var (
+	// ErrMissingToken is returned when no integration token is set.
+	ErrMissingToken = errors.New("notion integration token is required")
+	// ErrMissingDatabase is returned when no database ID is set.
+	ErrMissingDatabase = errors.New("notion database ID is required")
+	// ErrUnsupportedProperty is returned when a property cannot be set from
+	// text, like a people or a date property.
+	ErrUnsupportedProperty = errors.New("unsupported notion property type")
+)
+
+// Client is a Notion API client authenticating with the token of an internal
+// integration, which the database is shared with.
+type Client struct {
+	// BaseURL is the base URL of the API. When empty, DefaultBaseURL is used.
+	BaseURL string
+	// Token is the internal integration token.
+	Token string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// Page is a created Notion page.
+type Page struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// database is the schema of a database, mapping the names of its properties
+// to their types.
+type database struct {
+	Properties map[string]struct {
+		Type string `json:"type"`
+	} `json:"properties"`
+}
+
+// errorResponse is the error returned by the API.
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// CreatePage creates a page in the database with the title and the blocks.
+// The properties map the names of the database properties to their values,
+// set according to the type of the property: title, text, select, or
+// multi-select. The properties the database does not have are left out.
+func (c *Client) CreatePage(ctx context.Context, databaseID string, title string, properties map[string]string, blocks []Block) (*Page, error) {
+	if c.Token == "" {
+		return nil, ErrMissingToken
+	}
+
+	if databaseID == "" {
+		return nil, ErrMissingDatabase
+	}
+
+	var schema database
+	if err := c.do(ctx, http.MethodGet, "/databases/"+url.PathEscape(databaseID), nil, &schema); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{}, len(properties)+1)
+	for name, property := range schema.Properties {
+		if property.Type == "title" {
+			values[name] = map[string]interface{}{"title": richText(title)}
+		}
+	}
+
+	for name, value := range properties {
+		property, ok := schema.Properties[name]
+		if !ok || value == "" {
+			continue
+		}
+
+		propertyValue, err := newPropertyValue(property.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s (%s)", err, name, property.Type)
+		}
+
+		values[name] = propertyValue
+	}
+
+	first := blocks
+	if len(first) > maxChildren {
+		first = first[:maxChildren]
+	}
+
+	var page Page
+	if err := c.do(ctx, http.MethodPost, "/pages", map[string]interface{}{
+		"parent":     map[string]string{"database_id": databaseID},
+		"properties": values,
+		"children":   first,
+	}, &page); err != nil {
+		return nil, err
+	}
+
+	for start := len(first); start < len(blocks); start += maxChildren {
+		end := start + maxChildren
+		if end > len(blocks) {
+			end = len(blocks)
+		}
+
+		if err := c.do(ctx, http.MethodPatch, "/blocks/"+url.PathEscape(page.ID)+"/children", map[string]interface{}{
+			"children": blocks[start:end],
+		}, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &page, nil
+}
+
+// newPropertyValue returns the value of a property of the given type set to
+// the text.
+func newPropertyValue(propertyType string, text string) (interface{}, error) {
+	switch propertyType {
+	case "title":
+		return map[string]interface{}{"title": richText(text)}, nil
+	case "rich_text":
+		return map[string]interface{}{"rich_text": richText(text)}, nil
+	case "select":
+		return map[string]interface{}{"select": map[string]string{"name": text}}, nil
+	case "multi_select":
+		return map[string]interface{}{"multi_select": []map[string]string{{"name": text}}}, nil
+	default:
+		return nil, ErrUnsupportedProperty
+	}
+}
+
+// do sends an authenticated request to the Notion API and decodes the
+// response into v, unless v is nil.
+func (c *Client) do(ctx context.Context, method string, path string, payload interface{}, v interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+
+		body = bytes.NewReader(encoded)
+	}
+
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(baseURL, "/")+path, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Notion-Version", apiVersion)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var errResp errorResponse
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Message != "" {
+			return fmt.Errorf("notion request failed with status %d: %s", resp.StatusCode, errResp.Message)
+		}
+
+		return fmt.Errorf("notion request failed with status %d", resp.StatusCode)
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.Unmarshal(respBody, v)
+}
@@ -0,0 +1,112 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+)
+
+// ErrStateMismatch is returned when the state of the callback differs from
+// the state sent to the authorization page.
+var ErrStateMismatch = errors.New("oauth state mismatch")
+
+// callbackResult is the outcome of the authorization callback.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// Login performs the authorization code flow: it opens the consent page,
+// waits for the callback on the redirect URL, exchanges the code for a token,
+// and selects the Jira site of the token. The authorization URL is passed to
+// notify, so it can be shown if the browser cannot be opened.
+func (c *Config) Login(ctx context.Context, siteURL string, notify func(authURL string)) (*Token, error) {
+	redirectURL, err := url.Parse(c.RedirectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := NewState()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", redirectURL.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan callbackResult, 1)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != redirectURL.Path {
+				http.NotFound(w, r)
+				return
+			}
+
+			query := r.URL.Query()
+			switch {
+			case query.Get("state") != state:
+				results <- callbackResult{err: ErrStateMismatch}
+			case query.Get("error") != "":
+				results <- callbackResult{err: fmt.Errorf("authorization failed: %s", query.Get("error_description"))}
+			default:
+				results <- callbackResult{code: query.Get("code")}
+			}
+
+			fmt.Fprintln(w, "Authorization finished, you can close this window.")
+		}),
+	}
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	authURL := c.AuthCodeURL(state)
+	notify(authURL)
+	_ = OpenBrowser(authURL)
+
+	var result callbackResult
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-results:
+	}
+
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := c.Exchange(ctx, result.code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.SelectResource(ctx, token, siteURL); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// OpenBrowser opens the URL in the default browser of the user.
+func OpenBrowser(rawURL string) error {
+	var cmd *exec.Cmd
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", rawURL)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", rawURL)
+	default:
+		cmd = exec.Command("xdg-open", rawURL)
+	}
+
+	return cmd.Start()
+}
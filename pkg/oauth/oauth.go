@@ -0,0 +1,257 @@
+// Package oauth implements the Atlassian OAuth 2.0 (3LO) authorization code
+// flow used for authenticating against Jira Cloud without storing passwords
+// or API tokens.
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// authorizeURL is the URL of the Atlassian authorization page.
+	authorizeURL = "https://auth.atlassian.com/authorize"
+	// tokenURL is the URL of the Atlassian token endpoint.
+	tokenURL = "https://auth.atlassian.com/oauth/token"
+	// resourcesURL lists the sites the access token grants access to.
+	resourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	// apiURLFormat is the format of the Jira API base URL of cloud sites.
+	apiURLFormat = "https://api.atlassian.com/ex/jira/%s"
+	// expiryDelta is subtracted from the token expiry, so tokens are
+	// refreshed before they actually expire.
+	expiryDelta = time.Minute
+)
+
+// DefaultScopes are the scopes requested when no scopes are configured. The
+// "offline_access" scope is required for receiving refresh tokens.
+var DefaultScopes = []string{"read:jira-work", "read:jira-user", "offline_access"}
+
+// ErrNoResource is returned when the access token grants access to no Jira
+// site.
+var ErrNoResource = errors.New("no accessible jira site found")
+
+// ErrNotLoggedIn is returned when no token is stored.
+var ErrNotLoggedIn = errors.New("not logged in; run the login command first")
+
+// Config holds the OAuth 2.0 application settings.
+type Config struct {
+	// ClientID is the client ID of the OAuth 2.0 app.
+	ClientID string
+	// ClientSecret is the client secret of the OAuth 2.0 app.
+	ClientSecret string
+	// RedirectURL is the callback URL registered for the app.
+	RedirectURL string
+	// Scopes are the requested scopes. When empty, DefaultScopes are used.
+	Scopes []string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// Token is an OAuth 2.0 token together with the Jira site it grants access
+// to.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+	// CloudID is the ID of the Jira site.
+	CloudID string `json:"cloud_id"`
+	// SiteURL is the URL of the Jira site.
+	SiteURL string `json:"site_url"`
+}
+
+// Valid reports whether the access token is set and not expired.
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && time.Now().Add(expiryDelta).Before(t.Expiry)
+}
+
+// APIURL returns the base URL of the Jira API of the site.
+func (t *Token) APIURL() string {
+	return fmt.Sprintf(apiURLFormat, t.CloudID)
+}
+
+// tokenResponse is the response of the token endpoint.
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+// Resource is a site the access token grants access to.
+type Resource struct {
+	ID   string `json:"id"`
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// NewState returns a random state value used for preventing CSRF attacks.
+func NewState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// AuthCodeURL returns the URL of the consent page the user must visit.
+func (c *Config) AuthCodeURL(state string) string {
+	scopes := c.Scopes
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+
+	v := url.Values{}
+	v.Set("audience", "api.atlassian.com")
+	v.Set("client_id", c.ClientID)
+	v.Set("scope", strings.Join(scopes, " "))
+	v.Set("redirect_uri", c.RedirectURL)
+	v.Set("state", state)
+	v.Set("response_type", "code")
+	v.Set("prompt", "consent")
+
+	return authorizeURL + "?" + v.Encode()
+}
+
+// Exchange exchanges the authorization code for a token.
+func (c *Config) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.requestToken(ctx, map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     c.ClientID,
+		"client_secret": c.ClientSecret,
+		"code":          code,
+		"redirect_uri":  c.RedirectURL,
+	})
+}
+
+// Refresh returns a new token using the refresh token of the given one. As
+// Atlassian rotates refresh tokens, the returned token contains a new refresh
+// token that must be stored.
+func (c *Config) Refresh(ctx context.Context, token *Token) (*Token, error) {
+	refreshed, err := c.requestToken(ctx, map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     c.ClientID,
+		"client_secret": c.ClientSecret,
+		"refresh_token": token.RefreshToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+
+	refreshed.CloudID = token.CloudID
+	refreshed.SiteURL = token.SiteURL
+
+	return refreshed, nil
+}
+
+// requestToken requests a token from the token endpoint.
+func (c *Config) requestToken(ctx context.Context, params map[string]string) (*Token, error) {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(string(body)))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp tokenResponse
+	if err = c.do(req, &resp); err != nil && resp.Error == "" {
+		return nil, err
+	}
+
+	if resp.Error != "" {
+		return nil, fmt.Errorf("token request failed: %s: %s", resp.Error, resp.Description)
+	}
+
+	return &Token{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// AccessibleResources returns the sites the access token grants access to.
+func (c *Config) AccessibleResources(ctx context.Context, token *Token) ([]Resource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resourcesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	var resources []Resource
+	if err = c.do(req, &resources); err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// SelectResource sets the site of the token. If a site URL is given, the
+// matching site is selected; otherwise the first accessible site is used.
+func (c *Config) SelectResource(ctx context.Context, token *Token, siteURL string) error {
+	resources, err := c.AccessibleResources(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	siteURL = strings.TrimSuffix(siteURL, "/")
+	for _, resource := range resources {
+		if siteURL == "" || strings.EqualFold(strings.TrimSuffix(resource.URL, "/"), siteURL) {
+			token.CloudID = resource.ID
+			token.SiteURL = resource.URL
+			return nil
+		}
+	}
+
+	return ErrNoResource
+}
+
+// do sends the request and decodes the JSON response into v. The response is
+// decoded for unsuccessful statuses too, so error details can be read.
+func (c *Config) do(req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	decodeErr := json.Unmarshal(body, v)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("oauth request failed with status %d", resp.StatusCode)
+	}
+
+	return decodeErr
+}
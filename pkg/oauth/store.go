@@ -0,0 +1,94 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gabor-boros/sprint-update/pkg/credentials"
+)
+
+// TokenStore persists the OAuth 2.0 token between runs.
+type TokenStore interface {
+	// Load returns the stored token, or ErrNotLoggedIn if no token is stored.
+	Load() (*Token, error)
+	// Save stores the token, replacing any existing one.
+	Save(token *Token) error
+}
+
+// FileStore is a TokenStore keeping the token in a file readable only by the
+// current user.
+type FileStore struct {
+	// Path is the path of the token file.
+	Path string
+}
+
+// Load reads the token from the file.
+func (s *FileStore) Load() (*Token, error) {
+	data, err := os.ReadFile(filepath.Clean(s.Path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotLoggedIn
+		}
+
+		return nil, err
+	}
+
+	var token Token
+	if err = json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Save writes the token to the file, creating its directory if necessary.
+func (s *FileStore) Save(token *Token) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.Path, data, 0600)
+}
+
+// KeyringStore is a TokenStore keeping the token in the keyring of the
+// operating system.
+type KeyringStore struct {
+	// Key is the key the token is stored under.
+	Key string
+}
+
+// Load reads the token from the keyring.
+func (s *KeyringStore) Load() (*Token, error) {
+	data, err := credentials.Get(s.Key)
+	if err != nil {
+		if errors.Is(err, credentials.ErrNotFound) {
+			return nil, ErrNotLoggedIn
+		}
+
+		return nil, err
+	}
+
+	var token Token
+	if err = json.Unmarshal([]byte(data), &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Save writes the token to the keyring.
+func (s *KeyringStore) Save(token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	return credentials.Set(s.Key, string(data))
+}
@@ -0,0 +1,83 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// TokenSource returns valid tokens, transparently refreshing and storing them
+// when they expire.
+type TokenSource struct {
+	Config *Config
+	Store  TokenStore
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewTokenSource returns a new TokenSource using the given app settings and
+// token store.
+func NewTokenSource(config *Config, store TokenStore) *TokenSource {
+	return &TokenSource{Config: config, Store: store}
+}
+
+// Token returns a valid token, refreshing it if it is expired.
+func (s *TokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		token, err := s.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		s.token = token
+	}
+
+	if s.token.Valid() {
+		return s.token, nil
+	}
+
+	token, err := s.Config.Refresh(ctx, s.token)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.Store.Save(token); err != nil {
+		return nil, err
+	}
+
+	s.token = token
+	return s.token, nil
+}
+
+// Transport is an http.RoundTripper authenticating all requests using the
+// access tokens of the token source.
+type Transport struct {
+	Source *TokenSource
+
+	// Base is the underlying HTTP transport to use when making requests.
+	// It will default to http.DefaultTransport if nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements the RoundTripper interface by adding the access token
+// to a copy of the request.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(req2)
+}
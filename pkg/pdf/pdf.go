@@ -0,0 +1,384 @@
+// Package pdf implements a minimal PDF writer laying out headings,
+// paragraphs, lists, and tables on A4 pages, using the standard Helvetica
+// fonts, so no fonts have to be embedded.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// pageWidth and pageHeight are the dimensions of A4 pages in points.
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	// margin is the margin of the pages in points.
+	margin = 50
+	// lineSpacing is the height of the lines relative to the font size.
+	lineSpacing = 1.35
+	// cellPadding is the padding of the table cells in points.
+	cellPadding = 4
+	// textSize is the font size of the paragraphs and the tables.
+	textSize = 9.5
+)
+
+// Font is one of the standard fonts of the documents.
+type Font int
+
+const (
+	// Regular is the Helvetica font.
+	Regular Font = iota
+	// Bold is the Helvetica-Bold font.
+	Bold
+	// Italic is the Helvetica-Oblique font.
+	Italic
+)
+
+// fontNames are the base fonts of the resources of the pages.
+var fontNames = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique"}
+
+// Color is an RGB color, each component being between 0 and 1.
+type Color struct {
+	R, G, B float64
+}
+
+var (
+	// Black is the color of the text.
+	Black = Color{}
+	// White is the color of the text on colored backgrounds.
+	White = Color{R: 1, G: 1, B: 1}
+	// linkColor is the color of the linked text.
+	linkColor = Color{R: 0.035, G: 0.412, B: 0.855}
+	// borderColor is the color of the table borders.
+	borderColor = Color{R: 0.82, G: 0.84, B: 0.86}
+	// headerColor is the background color of the column headers.
+	headerColor = Color{R: 0.965, G: 0.973, B: 0.98}
+)
+
+// ParseColor parses a hexadecimal RGB color, like "#1a7f37". If the color
+// cannot be parsed, black is returned.
+func ParseColor(hex string) Color {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return Black
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return Black
+	}
+
+	return Color{
+		R: float64(value>>16&0xff) / 255,
+		G: float64(value>>8&0xff) / 255,
+		B: float64(value&0xff) / 255,
+	}
+}
+
+// Column is a column of a table.
+type Column struct {
+	// Title is the title of the column.
+	Title string
+	// Width is the width of the column relative to the other columns.
+	Width float64
+}
+
+// Cell is a cell of a table, linked if the URL is set.
+type Cell struct {
+	Text string
+	URL  string
+}
+
+// Table is a table with a colored title bar, like a status group.
+type Table struct {
+	// Title is the text of the title bar.
+	Title string
+	// Color is the background color of the title bar.
+	Color Color
+	// Columns are the columns of the table.
+	Columns []Column
+	// Rows are the rows of the table, having a cell for every column.
+	Rows [][]Cell
+}
+
+// link is a link annotation of a page.
+type link struct {
+	x, y, width, height float64
+	url                 string
+}
+
+// page is a page of the document.
+type page struct {
+	content bytes.Buffer
+	links   []link
+}
+
+// Document is a PDF document laid out from the top of the first page
+// downwards, adding pages as necessary.
+type Document struct {
+	title string
+	pages []*page
+	y     float64
+}
+
+// New returns an empty document with the given title.
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.addPage()
+
+	return d
+}
+
+// Heading adds a bold heading of the given font size.
+func (d *Document) Heading(text string, size float64) {
+	d.space(size * 0.6)
+	d.lines(margin, Bold, size, Black, wrap(text, Bold, size, pageWidth-2*margin), "")
+	d.space(size * 0.3)
+}
+
+// Paragraph adds a paragraph of the given font.
+func (d *Document) Paragraph(text string, font Font) {
+	d.lines(margin, font, textSize, Black, wrap(text, font, textSize, pageWidth-2*margin), "")
+	d.space(textSize * 0.5)
+}
+
+// Bullet adds a bulleted list item at the nesting level, linked if the URL is
+// set.
+func (d *Document) Bullet(text string, url string, level int) {
+	indent := margin + 10 + float64(level)*14
+	lines := wrap(text, Regular, textSize, pageWidth-margin-indent-10)
+
+	d.ensure(textSize * lineSpacing)
+	d.text(indent, d.y-textSize, Regular, textSize, Black, encode("•"))
+
+	color := Black
+	if url != "" {
+		color = linkColor
+	}
+
+	d.lines(indent+10, Regular, textSize, color, lines, url)
+	d.space(textSize * 0.2)
+}
+
+// AddTable adds the table, starting new pages between the rows as necessary.
+func (d *Document) AddTable(table Table) {
+	width := pageWidth - 2*margin
+	lineHeight := textSize * lineSpacing
+
+	var total float64
+	for _, column := range table.Columns {
+		total += column.Width
+	}
+
+	widths := make([]float64, len(table.Columns))
+	for i, column := range table.Columns {
+		widths[i] = width * column.Width / total
+	}
+
+	titleHeight := lineHeight + 2*cellPadding
+	d.space(textSize * 0.5)
+	d.ensure(titleHeight + 2*(lineHeight+2*cellPadding))
+	d.rect(margin, d.y-titleHeight, width, titleHeight, table.Color, true)
+	d.text(margin+cellPadding, d.y-cellPadding-textSize, Bold, textSize, White, encode(table.Title))
+	d.y -= titleHeight
+
+	header := make([]Cell, len(table.Columns))
+	for i, column := range table.Columns {
+		header[i] = Cell{Text: column.Title}
+	}
+
+	d.row(header, widths, Bold, headerColor)
+	for _, row := range table.Rows {
+		if d.y-rowHeight(row, widths, Regular) < margin {
+			d.addPage()
+			d.row(header, widths, Bold, headerColor)
+		}
+
+		d.row(row, widths, Regular, White)
+	}
+
+	d.space(textSize)
+}
+
+// row adds a row of the table with the background color.
+func (d *Document) row(cells []Cell, widths []float64, font Font, background Color) {
+	height := rowHeight(cells, widths, font)
+
+	var x float64 = margin
+	for i, cell := range cells {
+		d.rect(x, d.y-height, widths[i], height, background, true)
+		d.rect(x, d.y-height, widths[i], height, borderColor, false)
+
+		color := Black
+		if cell.URL != "" {
+			color = linkColor
+		}
+
+		lines := wrap(cell.Text, font, textSize, widths[i]-2*cellPadding)
+		for j, line := range lines {
+			d.text(x+cellPadding, d.y-cellPadding-textSize-float64(j)*textSize*lineSpacing, font, textSize, color, line)
+		}
+
+		if cell.URL != "" {
+			d.page().links = append(d.page().links, link{x: x, y: d.y - height, width: widths[i], height: height, url: cell.URL})
+		}
+
+		x += widths[i]
+	}
+
+	d.y -= height
+}
+
+// rowHeight returns the height of the row, fitting the wrapped text of its
+// highest cell.
+func rowHeight(cells []Cell, widths []float64, font Font) float64 {
+	lines := 1
+	for i, cell := range cells {
+		if n := len(wrap(cell.Text, font, textSize, widths[i]-2*cellPadding)); n > lines {
+			lines = n
+		}
+	}
+
+	return float64(lines)*textSize*lineSpacing + 2*cellPadding - (lineSpacing-1)*textSize
+}
+
+// lines adds the lines of text at the left offset, starting new pages as
+// necessary. If the URL is set, the lines are linked to it.
+func (d *Document) lines(x float64, font Font, size float64, color Color, lines []string, url string) {
+	lineHeight := size * lineSpacing
+
+	for _, line := range lines {
+		d.ensure(lineHeight)
+		d.text(x, d.y-size, font, size, color, line)
+
+		if url != "" {
+			d.page().links = append(d.page().links, link{x: x, y: d.y - lineHeight, width: textWidth(line, font, size), height: lineHeight, url: url})
+		}
+
+		d.y -= lineHeight
+	}
+}
+
+// space adds vertical space, unless at the top of a page.
+func (d *Document) space(height float64) {
+	if d.y < pageHeight-margin {
+		d.y -= height
+	}
+}
+
+// ensure starts a new page if the content of the given height does not fit
+// on the current page.
+func (d *Document) ensure(height float64) {
+	if d.y-height < margin {
+		d.addPage()
+	}
+}
+
+// addPage starts a new page.
+func (d *Document) addPage() {
+	d.pages = append(d.pages, &page{})
+	d.y = pageHeight - margin
+}
+
+// page returns the current page.
+func (d *Document) page() *page {
+	return d.pages[len(d.pages)-1]
+}
+
+// text draws the text encoded in WinAnsiEncoding with its baseline at the
+// given position.
+func (d *Document) text(x float64, y float64, font Font, size float64, color Color, text string) {
+	fmt.Fprintf(&d.page().content, "BT /F%d %s Tf %s rg %s %s Td (%s) Tj ET\n", font+1, number(size), rgb(color), number(x), number(y), escape(text))
+}
+
+// rect draws a filled or stroked rectangle.
+func (d *Document) rect(x float64, y float64, width float64, height float64, color Color, fill bool) {
+	if fill {
+		fmt.Fprintf(&d.page().content, "%s rg %s %s %s %s re f\n", rgb(color), number(x), number(y), number(width), number(height))
+		return
+	}
+
+	fmt.Fprintf(&d.page().content, "0.5 w %s RG %s %s %s %s re S\n", rgb(color), number(x), number(y), number(width), number(height))
+}
+
+// Bytes returns the encoded document.
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// The catalog, the page tree, the info dictionary, and the three fonts
+	// are the first objects, followed by the objects of every page.
+	const firstPage = 7
+
+	var pageRefs []string
+	next := firstPage
+	for _, p := range d.pages {
+		pageRefs = append(pageRefs, fmt.Sprintf("%d 0 R", next))
+		next += 2 + len(p.links)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageRefs, " "), len(d.pages)))
+	object(fmt.Sprintf("<< /Title (%s) /Producer (sprint-update) >>", escape(encode(d.title))))
+
+	for _, name := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+
+	for _, p := range d.pages {
+		pageObject := len(offsets) + 1
+
+		var annots []string
+		for i := range p.links {
+			annots = append(annots, fmt.Sprintf("%d 0 R", pageObject+2+i))
+		}
+
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 4 0 R /F2 5 0 R /F3 6 0 R >> >> /Contents %d 0 R /Annots [%s] >>",
+			number(pageWidth), number(pageHeight), pageObject+1, strings.Join(annots, " "),
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+
+		for _, l := range p.links {
+			object(fmt.Sprintf(
+				"<< /Type /Annot /Subtype /Link /Rect [%s %s %s %s] /Border [0 0 0] /A << /S /URI /URI (%s) >> >>",
+				number(l.x), number(l.y), number(l.x+l.width), number(l.y+l.height), escape(encode(l.url)),
+			))
+		}
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// number formats the number with at most two decimal places.
+func number(n float64) string {
+	return strconv.FormatFloat(math.Round(n*100)/100, 'f', -1, 64)
+}
+
+// rgb formats the color as the operands of the color operators.
+func rgb(c Color) string {
+	return number(c.R) + " " + number(c.G) + " " + number(c.B)
+}
+
+// escape escapes the special characters of the literal strings.
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", `\r`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,127 @@
+package pdf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// defaultWidth is the width of the characters missing from the width tables,
+// like the accented letters, in thousandths of the font size.
+const defaultWidth = 556
+
+// regularWidths and boldWidths are the widths of the printable ASCII
+// characters, from the space to the tilde, of Helvetica and Helvetica-Bold,
+// in thousandths of the font size. Helvetica-Oblique has the widths of
+// Helvetica.
+var (
+	regularWidths = []int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	boldWidths = []int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// winAnsi maps the characters of the Windows-1252 code page outside of
+// Latin-1 to their codes, as the standard fonts use its WinAnsiEncoding.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b,
+	'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encode encodes the text in WinAnsiEncoding. The characters it cannot
+// encode, like emojis, are replaced by question marks, except for the
+// variation selectors and the other invisible characters, which are left out.
+func encode(text string) string {
+	var b strings.Builder
+
+	for _, r := range text {
+		switch code, ok := winAnsi[r]; {
+		case ok:
+			b.WriteByte(code)
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r <= 0x7e, r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Cf, r), unicode.IsControl(r), r >= 0xfe00 && r <= 0xfe0f:
+		default:
+			b.WriteByte('?')
+		}
+	}
+
+	return b.String()
+}
+
+// textWidth returns the width of the text in points.
+func textWidth(text string, font Font, size float64) float64 {
+	widths := regularWidths
+	if font == Bold {
+		widths = boldWidths
+	}
+
+	var total int
+	for i := 0; i < len(text); i++ {
+		if c := text[i]; c >= ' ' && c <= '~' {
+			total += widths[c-' ']
+		} else {
+			total += defaultWidth
+		}
+	}
+
+	return float64(total) * size / 1000
+}
+
+// wrap encodes the text and breaks it into lines fitting into the width,
+// between the words if possible.
+func wrap(text string, font Font, size float64, width float64) []string {
+	var lines []string
+
+	for _, paragraph := range strings.Split(encode(strings.ReplaceAll(text, "\r", "")), "\n") {
+		var line string
+
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+
+			if textWidth(candidate, font, size) <= width {
+				line = candidate
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, line)
+			}
+
+			// Break the words longer than the line, like the URLs.
+			for textWidth(word, font, size) > width && len(word) > 1 {
+				n := len(word) - 1
+				for n > 1 && textWidth(word[:n], font, size) > width {
+					n--
+				}
+
+				lines = append(lines, word[:n])
+				word = word[n:]
+			}
+
+			line = word
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
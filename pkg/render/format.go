@@ -0,0 +1,1274 @@
+// Package render renders sprint updates using the templates of the built-in
+// output formats or custom templates.
+package render
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultFormat is the name of the format used when no format is set.
+const DefaultFormat = "discourse"
+
+// DecoratedFormat is the name of the Discourse Markdown format decorating the
+// statuses with emojis.
+const DecoratedFormat = "decorated"
+
+// ErrUnknownFormat is returned when the requested output format does not
+// exist.
+var ErrUnknownFormat = errors.New("unknown format")
+
+// ErrNoTemplate is returned when a template is set for a structured format,
+// which has no template.
+var ErrNoTemplate = errors.New("format does not use templates")
+
+// sectionsTemplate renders the sections of the update in their order, using
+// the templates of the same name defined by the built-in templates. It
+// defines the issueLine partial too, rendering the key and the summary of the
+// issues, subtasks, and linked issues in every section, which only differs
+// by the escaping and the links of the formats.
+const sectionsTemplate string = `{{- define "issueLine" }}{{ with link .Key .URL }}{{ . }} - {{ end }}{{ escape .Summary }}{{ end }}
+{{- define "sections" }}
+{{- range $section := .OrderedSections }}
+{{- if eq $section "summary" }}{{ template "summary" $ }}
+{{- else if eq $section "themes" }}{{ template "themes" $ }}
+{{- else if eq $section "worked-on" }}{{ template "worked-on" $ }}
+{{- else if eq $section "blocked" }}{{ template "blocked" $ }}
+{{- else if eq $section "dependencies" }}{{ template "dependencies" $ }}
+{{- else if eq $section "pull-requests" }}{{ template "pull-requests" $ }}
+{{- else if eq $section "commits" }}{{ template "commits" $ }}
+{{- else if eq $section "hours" }}{{ template "hours" $ }}
+{{- else if eq $section "spillovers" }}{{ template "spillovers" $ }}
+{{- else if eq $section "carried-over" }}{{ template "carried-over" $ }}
+{{- else if eq $section "kudos" }}{{ template "kudos" $ }}
+{{- else if eq $section "time-off" }}{{ template "time-off" $ }}
+{{- else if eq $section "stats" }}{{ template "stats" $ }}
+{{- end }}
+{{- end }}
+{{- end }}
+`
+
+// DefaultTemplate is a Discourse Markdown template used for generating
+// the mid- and end of sprint updates.
+const DefaultTemplate string = sectionsTemplate + `{{- define "sectionHeader" }}**{{ escape . }}**{{ end }}
+{{- define "statusGroups" }}
+{{- range $group := . }}
+{{- if $group.Collapsible "discourse" }}
+
+[details="{{ escape $group.Name }}{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}"{{ if $group.Open }} open{{ end }}]
+{{- else }}
+
+_{{ escape $group.Name }}_{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}
+{{- end }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if not $group.Status }} ({{ escape $item.Status }}){{ end }}{{ if $item.Change }} ({{ escape $item.ChangeNote }}){{ end }}{{ if $item.TimeSpent }} ({{ hours $item.TimeSpent }}){{ end }}{{ with $item.SubtaskProgress }} ({{ . }}){{ end }}{{ with $item.Annotations }} ({{ escape (join . ", ") }}){{ end }}:
+{{- if $item.Note }}
+  * {{ escape $item.Note }}
+{{- end }}
+{{- range $sub := $item.Subtasks }}
+  * {{ template "issueLine" $sub }} ({{ escape $sub.Status }})
+{{- end }}
+{{- end }}
+{{- if $group.Folded }}
+* {{ link $group.FoldedText $group.FoldedURL }}
+{{- end }}
+{{- if $group.Collapsible "discourse" }}
+[/details]
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "summary" }}{{- if .Summary }}
+
+{{ template "sectionHeader" ($.Heading "summary" "Summary") }}
+
+{{ escape .Summary }}
+{{- end }}{{ end }}
+{{- define "themes" }}{{- if .Themes }}
+
+{{ template "sectionHeader" ($.Heading "themes" "Themes") }}
+{{ range .Themes }}
+* {{ with link .Key .URL }}{{ . }} - {{ end }}{{ escape .Name }}: {{ if .Total }}{{ escape ($.T "%s%% done" (points .DonePercentage)) }}, {{ end }}{{ if eq .Issues 1 }}{{ escape ($.T "%d issue" .Issues) }}{{ else }}{{ escape ($.T "%d issues" .Issues) }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "worked-on" }}
+
+{{ template "sectionHeader" ($.Heading "worked-on" "Worked on") }}
+{{- if .StoryPoints }}
+
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+
+{{- if .Members }}
+{{- range .Members }}
+
+### {{ escape .Name }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else if .Projects }}
+{{- range .Projects }}
+
+### {{ escape .Name }} ({{ if eq .Count 1 }}{{ escape ($.T "%d issue" .Count) }}{{ else }}{{ escape ($.T "%d issues" .Count) }}{{ end }})
+{{- if $.StoryPoints }}
+
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}{{ end }}
+{{- define "blocked" }}{{- if .Blocked }}
+
+{{ template "sectionHeader" ($.Heading "blocked" "Blocked / Needs help") }}
+{{ range $group := $.Groups .Blocked }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- if $item.BlockedBy }} ({{ escape ($.T "blocked by %s" (join $item.BlockedBy ", ")) }}){{ end }}{{ if $item.Flagged }} ({{ escape ($.T "flagged") }}){{ end }}{{ if $item.BlockedReason }}: {{ escape $item.BlockedReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "dependencies" }}{{- if .Dependencies }}
+
+{{ template "sectionHeader" ($.Heading "dependencies" "Dependencies on other teams") }}
+{{ range $dependency := .Dependencies }}
+* {{ template "issueLine" $dependency }}: {{ escape $dependency.Link.Relation }} {{ with $dependency.Link }}{{ template "issueLine" . }}{{ with .Status }} ({{ escape . }}){{ end }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "pull-requests" }}{{- if .PullRequests }}
+
+{{ template "sectionHeader" ($.Heading "pull-requests" "Pull requests") }}
+{{ range $i, $pr := .PullRequests }}
+* [{{ escape $pr.Repository }}#{{ $pr.Number }}]({{ $pr.URL }}) - {{ escape $pr.Title }}{{ if $pr.Merged }} ({{ escape ($.T "merged") }}){{ end }}
+{{- range $j, $issue := $pr.Issues }}{{ if $j }},{{ else }} -{{ end }} [{{ $issue.Key }}]({{ $issue.URL }}){{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "commits" }}{{- if .Commits }}
+
+{{ template "sectionHeader" ($.Heading "commits" "Commits") }}
+{{- range $group := .Commits }}
+
+{{ if $group.Key }}{{ link $group.Key $group.URL }}{{ with $group.Summary }} - {{ escape . }}{{ end }}{{ else }}_{{ escape ($.T "No issue") }}_{{ end }}
+{{- range $commit := $group.Commits }}
+* {{ escape $commit.Repository }}@{{ $commit.ShortHash }} - {{ escape $commit.Subject }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "hours" }}{{- with .Timesheet }}
+
+{{ template "sectionHeader" ($.Heading "hours" "Hours") }}
+
+{{ escape ($.T "%s logged" (hours .Total)) }}{{ if .Approved }} ({{ escape ($.T "approved") }}){{ end }}
+{{- if .Unlogged }}
+
+{{ escape ($.T "Done without logged time:") }}
+{{- range $item := .Unlogged }}
+* {{ template "issueLine" $item }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "spillovers" }}
+
+{{ template "sectionHeader" ($.Heading "spillovers" "Spillovers") }}
+{{ if .Spillovers }}
+{{- range $group := $.Groups .Spillovers }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}{{ if $item.SpilloverReason }}: {{ escape $item.SpilloverReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- else }}
+{{ escape ($.T "No spillovers in this sprint.") }}
+{{- end }}{{ end }}
+{{- define "carried-over" }}{{- if .CarriedOver }}
+
+{{ template "sectionHeader" ($.Heading "carried-over" "Carried over from %s" .CarriedOverFrom) }}
+{{ range $group := $.Groups .CarriedOver }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "kudos" }}
+
+{{ template "sectionHeader" ($.Heading "kudos" "Kudos") }}
+{{ range .KudosOrPlaceholder }}
+* {{ escape . }}
+{{- end }}
+{{- range .SuggestedKudos }}
+* {{ escape .Name }} ({{ escape ($.T "suggested: %s" ($.KudosReason .)) }})
+{{- end }}{{ end }}
+{{- define "time-off" }}
+
+{{ template "sectionHeader" ($.Heading "time-off" "Time off") }}
+
+{{ escape .TimeOffText }}{{ end }}
+{{- define "stats" }}{{- if or .Stats .CycleTime }}
+
+{{ template "sectionHeader" ($.Heading "stats" "Velocity") }}
+{{ with .Stats }}
+* {{ escape ($.T "%s of %s committed pts completed (%s%%)" (points .CompletedPoints) (points .CommittedPoints) (points .CompletionPercentage)) }}
+* {{ escape ($.T "%d issues completed, %d not completed, %d added after the start" .CompletedIssues .NotCompletedIssues .AddedIssues) }}
+{{- end }}{{ with .CycleTime }}
+* {{ escape ($.T "%d issues done in %s days on average, %s days at the median" .Issues (points .AverageDays) (points .MedianDays)) }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "provenance" }}{{ with .ProvenanceNote }}
+
+---
+_{{ escape . }}_{{ end }}{{ end }}
+{{- define "layout" }}**{{ escape .Title }}**{{ template "sections" . }}{{ template "provenance" . }}{{ end }}
+{{ template "layout" . }}
+`
+
+// DecoratedTemplate is a Discourse Markdown template decorating the statuses
+// and the sections with emojis, which makes the updates easier to scan.
+const DecoratedTemplate string = sectionsTemplate + `{{- define "sectionHeader" }}**{{ escape . }}**{{ end }}
+{{- define "statusGroups" }}
+{{- range $group := . }}
+{{- if $group.Collapsible "decorated" }}
+
+[details="{{ with $group.Emoji }}{{ . }} {{ end }}{{ escape $group.Name }}{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}"{{ if $group.Open }} open{{ end }}]
+{{- else }}
+
+{{ with $group.Emoji }}{{ . }} {{ end }}_{{ escape $group.Name }}_{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}
+{{- end }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if not $group.Status }} ({{ escape $item.Status }}){{ end }}{{ if $item.Change }} ({{ escape $item.ChangeNote }}){{ end }}{{ if $item.TimeSpent }} ({{ hours $item.TimeSpent }}){{ end }}{{ with $item.SubtaskProgress }} ({{ . }}){{ end }}{{ with $item.Annotations }} ({{ escape (join . ", ") }}){{ end }}:
+{{- if $item.Note }}
+  * {{ escape $item.Note }}
+{{- end }}
+{{- range $sub := $item.Subtasks }}
+  * {{ template "issueLine" $sub }} ({{ escape $sub.Status }})
+{{- end }}
+{{- end }}
+{{- if $group.Folded }}
+* {{ link $group.FoldedText $group.FoldedURL }}
+{{- end }}
+{{- if $group.Collapsible "decorated" }}
+[/details]
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "summary" }}{{- if .Summary }}
+
+{{ template "sectionHeader" (printf "📝 %s" ($.Heading "summary" "Summary")) }}
+
+{{ escape .Summary }}
+{{- end }}{{ end }}
+{{- define "themes" }}{{- if .Themes }}
+
+{{ template "sectionHeader" (printf "🧭 %s" ($.Heading "themes" "Themes")) }}
+{{ range .Themes }}
+* {{ with link .Key .URL }}{{ . }} - {{ end }}{{ escape .Name }}: {{ if .Total }}{{ escape ($.T "%s%% done" (points .DonePercentage)) }}, {{ end }}{{ if eq .Issues 1 }}{{ escape ($.T "%d issue" .Issues) }}{{ else }}{{ escape ($.T "%d issues" .Issues) }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "worked-on" }}
+
+{{ template "sectionHeader" (printf "🛠️ %s" ($.Heading "worked-on" "Worked on")) }}
+{{- if .StoryPoints }}
+
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+
+{{- if .Members }}
+{{- range .Members }}
+
+### {{ escape .Name }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else if .Projects }}
+{{- range .Projects }}
+
+### {{ escape .Name }} ({{ if eq .Count 1 }}{{ escape ($.T "%d issue" .Count) }}{{ else }}{{ escape ($.T "%d issues" .Count) }}{{ end }})
+{{- if $.StoryPoints }}
+
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}{{ end }}
+{{- define "blocked" }}{{- if .Blocked }}
+
+{{ template "sectionHeader" (printf "⛔ %s" ($.Heading "blocked" "Blocked / Needs help")) }}
+{{ range $group := $.Groups .Blocked }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- if $item.BlockedBy }} ({{ escape ($.T "blocked by %s" (join $item.BlockedBy ", ")) }}){{ end }}{{ if $item.Flagged }} ({{ escape ($.T "flagged") }}){{ end }}{{ if $item.BlockedReason }}: {{ escape $item.BlockedReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "dependencies" }}{{- if .Dependencies }}
+
+{{ template "sectionHeader" (printf "🔗 %s" ($.Heading "dependencies" "Dependencies on other teams")) }}
+{{ range $dependency := .Dependencies }}
+* {{ template "issueLine" $dependency }}: {{ escape $dependency.Link.Relation }} {{ with $dependency.Link }}{{ template "issueLine" . }}{{ with .Status }} ({{ escape . }}){{ end }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "pull-requests" }}{{- if .PullRequests }}
+
+{{ template "sectionHeader" (printf "🔀 %s" ($.Heading "pull-requests" "Pull requests")) }}
+{{ range $i, $pr := .PullRequests }}
+* [{{ escape $pr.Repository }}#{{ $pr.Number }}]({{ $pr.URL }}) - {{ escape $pr.Title }}{{ if $pr.Merged }} ({{ escape ($.T "merged") }}){{ end }}
+{{- range $j, $issue := $pr.Issues }}{{ if $j }},{{ else }} -{{ end }} [{{ $issue.Key }}]({{ $issue.URL }}){{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "commits" }}{{- if .Commits }}
+
+{{ template "sectionHeader" (printf "💾 %s" ($.Heading "commits" "Commits")) }}
+{{- range $group := .Commits }}
+
+{{ if $group.Key }}{{ link $group.Key $group.URL }}{{ with $group.Summary }} - {{ escape . }}{{ end }}{{ else }}_{{ escape ($.T "No issue") }}_{{ end }}
+{{- range $commit := $group.Commits }}
+* {{ escape $commit.Repository }}@{{ $commit.ShortHash }} - {{ escape $commit.Subject }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "hours" }}{{- with .Timesheet }}
+
+{{ template "sectionHeader" (printf "⏱️ %s" ($.Heading "hours" "Hours")) }}
+
+{{ escape ($.T "%s logged" (hours .Total)) }}{{ if .Approved }} ({{ escape ($.T "approved") }}){{ end }}
+{{- if .Unlogged }}
+
+{{ escape ($.T "Done without logged time:") }}
+{{- range $item := .Unlogged }}
+* {{ template "issueLine" $item }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "spillovers" }}
+
+{{ template "sectionHeader" (printf "🔁 %s" ($.Heading "spillovers" "Spillovers")) }}
+{{ if .Spillovers }}
+{{- range $group := $.Groups .Spillovers }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}{{ if $item.SpilloverReason }}: {{ escape $item.SpilloverReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- else }}
+{{ escape ($.T "No spillovers in this sprint.") }}
+{{- end }}{{ end }}
+{{- define "carried-over" }}{{- if .CarriedOver }}
+
+{{ template "sectionHeader" (printf "↩️ %s" ($.Heading "carried-over" "Carried over from %s" .CarriedOverFrom)) }}
+{{ range $group := $.Groups .CarriedOver }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "kudos" }}
+
+{{ template "sectionHeader" (printf "🙌 %s" ($.Heading "kudos" "Kudos")) }}
+{{ range .KudosOrPlaceholder }}
+* {{ escape . }}
+{{- end }}
+{{- range .SuggestedKudos }}
+* {{ escape .Name }} ({{ escape ($.T "suggested: %s" ($.KudosReason .)) }})
+{{- end }}{{ end }}
+{{- define "time-off" }}
+
+{{ template "sectionHeader" (printf "🌴 %s" ($.Heading "time-off" "Time off")) }}
+
+{{ escape .TimeOffText }}{{ end }}
+{{- define "stats" }}{{- if or .Stats .CycleTime }}
+
+{{ template "sectionHeader" (printf "📈 %s" ($.Heading "stats" "Velocity")) }}
+{{ with .Stats }}
+* {{ escape ($.T "%s of %s committed pts completed (%s%%)" (points .CompletedPoints) (points .CommittedPoints) (points .CompletionPercentage)) }}
+* {{ escape ($.T "%d issues completed, %d not completed, %d added after the start" .CompletedIssues .NotCompletedIssues .AddedIssues) }}
+{{- end }}{{ with .CycleTime }}
+* {{ escape ($.T "%d issues done in %s days on average, %s days at the median" .Issues (points .AverageDays) (points .MedianDays)) }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "provenance" }}{{ with .ProvenanceNote }}
+
+---
+_{{ escape . }}_{{ end }}{{ end }}
+{{- define "layout" }}**{{ escape .Title }}**{{ template "sections" . }}{{ template "provenance" . }}{{ end }}
+{{ template "layout" . }}
+`
+
+// MarkdownTemplate is a GitHub-flavored Markdown template.
+const MarkdownTemplate string = sectionsTemplate + `{{- define "sectionHeader" }}### {{ escape . }}{{ end }}
+{{- define "statusGroups" }}
+{{- range $group := . }}
+{{- if $group.Collapsible "markdown" }}
+
+<details{{ if $group.Open }} open{{ end }}>
+<summary>{{ escape $group.Name }}{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}</summary>
+{{- else }}
+
+_{{ escape $group.Name }}_{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}
+{{- end }}
+{{ range $i, $item := $group.Issues }}
+- {{ template "issueLine" $item }}{{ if not $group.Status }} ({{ escape $item.Status }}){{ end }}{{ if $item.Change }} ({{ escape $item.ChangeNote }}){{ end }}{{ if $item.TimeSpent }} ({{ hours $item.TimeSpent }}){{ end }}{{ with $item.SubtaskProgress }} ({{ . }}){{ end }}{{ with $item.Annotations }} ({{ escape (join . ", ") }}){{ end }}
+{{- if $item.Note }}
+  - {{ escape $item.Note }}
+{{- end }}
+{{- range $sub := $item.Subtasks }}
+  - {{ template "issueLine" $sub }} ({{ escape $sub.Status }})
+{{- end }}
+{{- end }}
+{{- if $group.Folded }}
+- {{ link $group.FoldedText $group.FoldedURL }}
+{{- end }}
+{{- if $group.Collapsible "markdown" }}
+
+</details>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "summary" }}{{- if .Summary }}
+
+{{ template "sectionHeader" ($.Heading "summary" "Summary") }}
+
+{{ escape .Summary }}
+{{- end }}{{ end }}
+{{- define "themes" }}{{- if .Themes }}
+
+{{ template "sectionHeader" ($.Heading "themes" "Themes") }}
+{{ range .Themes }}
+- {{ with link .Key .URL }}{{ . }} - {{ end }}{{ escape .Name }}: {{ if .Total }}{{ escape ($.T "%s%% done" (points .DonePercentage)) }}, {{ end }}{{ if eq .Issues 1 }}{{ escape ($.T "%d issue" .Issues) }}{{ else }}{{ escape ($.T "%d issues" .Issues) }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "worked-on" }}
+
+{{ template "sectionHeader" ($.Heading "worked-on" "Worked on") }}
+{{- if .StoryPoints }}
+
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+
+{{- if .Members }}
+{{- range .Members }}
+
+#### {{ escape .Name }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else if .Projects }}
+{{- range .Projects }}
+
+#### {{ escape .Name }} ({{ if eq .Count 1 }}{{ escape ($.T "%d issue" .Count) }}{{ else }}{{ escape ($.T "%d issues" .Count) }}{{ end }})
+{{- if $.StoryPoints }}
+
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}{{ end }}
+{{- define "blocked" }}{{- if .Blocked }}
+
+{{ template "sectionHeader" ($.Heading "blocked" "Blocked / Needs help") }}
+{{ range $group := $.Groups .Blocked }}
+{{- range $i, $item := $group.Issues }}
+- {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- if $item.BlockedBy }} ({{ escape ($.T "blocked by %s" (join $item.BlockedBy ", ")) }}){{ end }}{{ if $item.Flagged }} ({{ escape ($.T "flagged") }}){{ end }}{{ if $item.BlockedReason }}: {{ escape $item.BlockedReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "dependencies" }}{{- if .Dependencies }}
+
+{{ template "sectionHeader" ($.Heading "dependencies" "Dependencies on other teams") }}
+{{ range $dependency := .Dependencies }}
+- {{ template "issueLine" $dependency }}: {{ escape $dependency.Link.Relation }} {{ with $dependency.Link }}{{ template "issueLine" . }}{{ with .Status }} ({{ escape . }}){{ end }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "pull-requests" }}{{- if .PullRequests }}
+
+{{ template "sectionHeader" ($.Heading "pull-requests" "Pull requests") }}
+{{ range $i, $pr := .PullRequests }}
+- [{{ escape $pr.Repository }}#{{ $pr.Number }}]({{ $pr.URL }}) - {{ escape $pr.Title }}{{ if $pr.Merged }} ({{ escape ($.T "merged") }}){{ end }}
+{{- range $j, $issue := $pr.Issues }}{{ if $j }},{{ else }} -{{ end }} [{{ $issue.Key }}]({{ $issue.URL }}){{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "commits" }}{{- if .Commits }}
+
+{{ template "sectionHeader" ($.Heading "commits" "Commits") }}
+{{- range $group := .Commits }}
+
+{{ if $group.Key }}{{ link $group.Key $group.URL }}{{ with $group.Summary }} - {{ escape . }}{{ end }}{{ else }}_{{ escape ($.T "No issue") }}_{{ end }}
+{{- range $commit := $group.Commits }}
+- {{ escape $commit.Repository }}@{{ $commit.ShortHash }} - {{ escape $commit.Subject }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "hours" }}{{- with .Timesheet }}
+
+{{ template "sectionHeader" ($.Heading "hours" "Hours") }}
+
+{{ escape ($.T "%s logged" (hours .Total)) }}{{ if .Approved }} ({{ escape ($.T "approved") }}){{ end }}
+{{- if .Unlogged }}
+
+{{ escape ($.T "Done without logged time:") }}
+{{- range $item := .Unlogged }}
+- {{ template "issueLine" $item }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "spillovers" }}
+
+{{ template "sectionHeader" ($.Heading "spillovers" "Spillovers") }}
+{{ if .Spillovers }}
+{{- range $group := $.Groups .Spillovers }}
+{{- range $i, $item := $group.Issues }}
+- {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}{{ if $item.SpilloverReason }}: {{ escape $item.SpilloverReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- else }}
+{{ escape ($.T "No spillovers in this sprint.") }}
+{{- end }}{{ end }}
+{{- define "carried-over" }}{{- if .CarriedOver }}
+
+{{ template "sectionHeader" ($.Heading "carried-over" "Carried over from %s" .CarriedOverFrom) }}
+{{ range $group := $.Groups .CarriedOver }}
+{{- range $i, $item := $group.Issues }}
+- {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "kudos" }}
+
+{{ template "sectionHeader" ($.Heading "kudos" "Kudos") }}
+{{ range .KudosOrPlaceholder }}
+- {{ escape . }}
+{{- end }}
+{{- range .SuggestedKudos }}
+- {{ escape .Name }} ({{ escape ($.T "suggested: %s" ($.KudosReason .)) }})
+{{- end }}{{ end }}
+{{- define "time-off" }}
+
+{{ template "sectionHeader" ($.Heading "time-off" "Time off") }}
+
+{{ escape .TimeOffText }}{{ end }}
+{{- define "stats" }}{{- if or .Stats .CycleTime }}
+
+{{ template "sectionHeader" ($.Heading "stats" "Velocity") }}
+{{ with .Stats }}
+- {{ escape ($.T "%s of %s committed pts completed (%s%%)" (points .CompletedPoints) (points .CommittedPoints) (points .CompletionPercentage)) }}
+- {{ escape ($.T "%d issues completed, %d not completed, %d added after the start" .CompletedIssues .NotCompletedIssues .AddedIssues) }}
+{{- end }}{{ with .CycleTime }}
+- {{ escape ($.T "%d issues done in %s days on average, %s days at the median" .Issues (points .AverageDays) (points .MedianDays)) }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "provenance" }}{{ with .ProvenanceNote }}
+
+---
+_{{ escape . }}_{{ end }}{{ end }}
+{{- define "layout" }}## {{ escape .Title }}{{ template "sections" . }}{{ template "provenance" . }}{{ end }}
+{{ template "layout" . }}
+`
+
+// SlackTemplate is a Slack mrkdwn template.
+const SlackTemplate string = sectionsTemplate + `{{- define "sectionHeader" }}*{{ escape . }}*{{ end }}
+{{- define "statusGroups" }}
+{{- range $group := . }}
+
+_{{ escape $group.Name }}_{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}
+{{- range $i, $item := $group.Issues }}
+• {{ template "issueLine" $item }}{{ if not $group.Status }} ({{ escape $item.Status }}){{ end }}{{ if $item.Change }} ({{ escape $item.ChangeNote }}){{ end }}{{ if $item.TimeSpent }} ({{ hours $item.TimeSpent }}){{ end }}{{ with $item.SubtaskProgress }} ({{ . }}){{ end }}{{ with $item.Annotations }} ({{ escape (join . ", ") }}){{ end }}
+{{- if $item.Note }}
+    ◦ {{ escape $item.Note }}
+{{- end }}
+{{- range $sub := $item.Subtasks }}
+    ◦ {{ template "issueLine" $sub }} ({{ escape $sub.Status }})
+{{- end }}
+{{- end }}
+{{- if $group.Folded }}
+• {{ link $group.FoldedText $group.FoldedURL }}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "summary" }}{{- if .Summary }}
+
+{{ template "sectionHeader" ($.Heading "summary" "Summary") }}
+{{ escape .Summary }}
+{{- end }}{{ end }}
+{{- define "themes" }}{{- if .Themes }}
+
+{{ template "sectionHeader" ($.Heading "themes" "Themes") }}
+{{ range .Themes }}
+• {{ with link .Key .URL }}{{ . }} - {{ end }}{{ escape .Name }}: {{ if .Total }}{{ escape ($.T "%s%% done" (points .DonePercentage)) }}, {{ end }}{{ if eq .Issues 1 }}{{ escape ($.T "%d issue" .Issues) }}{{ else }}{{ escape ($.T "%d issues" .Issues) }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "worked-on" }}
+
+{{ template "sectionHeader" ($.Heading "worked-on" "Worked on") }}
+{{- if .StoryPoints }}
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+{{- if .Members }}
+{{- range .Members }}
+
+*{{ escape .Name }}*
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else if .Projects }}
+{{- range .Projects }}
+
+*{{ escape .Name }} ({{ if eq .Count 1 }}{{ escape ($.T "%d issue" .Count) }}{{ else }}{{ escape ($.T "%d issues" .Count) }}{{ end }})*
+{{- if $.StoryPoints }}
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}{{ end }}
+{{- define "blocked" }}{{- if .Blocked }}
+
+{{ template "sectionHeader" ($.Heading "blocked" "Blocked / Needs help") }}
+{{ range $group := $.Groups .Blocked }}
+{{- range $i, $item := $group.Issues }}
+• {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- if $item.BlockedBy }} ({{ escape ($.T "blocked by %s" (join $item.BlockedBy ", ")) }}){{ end }}{{ if $item.Flagged }} ({{ escape ($.T "flagged") }}){{ end }}{{ if $item.BlockedReason }}: {{ escape $item.BlockedReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "dependencies" }}{{- if .Dependencies }}
+
+{{ template "sectionHeader" ($.Heading "dependencies" "Dependencies on other teams") }}
+{{ range $dependency := .Dependencies }}
+• {{ template "issueLine" $dependency }}: {{ escape $dependency.Link.Relation }} {{ with $dependency.Link }}{{ template "issueLine" . }}{{ with .Status }} ({{ escape . }}){{ end }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "pull-requests" }}{{- if .PullRequests }}
+
+{{ template "sectionHeader" ($.Heading "pull-requests" "Pull requests") }}
+{{ range $i, $pr := .PullRequests }}
+• <{{ $pr.URL }}|{{ escape $pr.Repository }}#{{ $pr.Number }}> - {{ escape $pr.Title }}{{ if $pr.Merged }} ({{ escape ($.T "merged") }}){{ end }}
+{{- range $j, $issue := $pr.Issues }}{{ if $j }},{{ else }} -{{ end }} <{{ $issue.URL }}|{{ $issue.Key }}>{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "commits" }}{{- if .Commits }}
+
+{{ template "sectionHeader" ($.Heading "commits" "Commits") }}
+{{- range $group := .Commits }}
+
+{{ if $group.Key }}{{ link $group.Key $group.URL }}{{ with $group.Summary }} - {{ escape . }}{{ end }}{{ else }}_{{ escape ($.T "No issue") }}_{{ end }}
+{{- range $commit := $group.Commits }}
+• {{ escape $commit.Repository }}@{{ $commit.ShortHash }} - {{ escape $commit.Subject }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "hours" }}{{- with .Timesheet }}
+
+{{ template "sectionHeader" ($.Heading "hours" "Hours") }}
+
+{{ escape ($.T "%s logged" (hours .Total)) }}{{ if .Approved }} ({{ escape ($.T "approved") }}){{ end }}
+{{- if .Unlogged }}
+
+{{ escape ($.T "Done without logged time:") }}
+{{- range $item := .Unlogged }}
+• {{ template "issueLine" $item }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "spillovers" }}
+
+{{ template "sectionHeader" ($.Heading "spillovers" "Spillovers") }}
+{{ if .Spillovers }}
+{{- range $group := $.Groups .Spillovers }}
+{{- range $i, $item := $group.Issues }}
+• {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}{{ if $item.SpilloverReason }}: {{ escape $item.SpilloverReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- else }}
+{{ escape ($.T "No spillovers in this sprint.") }}
+{{- end }}{{ end }}
+{{- define "carried-over" }}{{- if .CarriedOver }}
+
+{{ template "sectionHeader" ($.Heading "carried-over" "Carried over from %s" .CarriedOverFrom) }}
+{{ range $group := $.Groups .CarriedOver }}
+{{- range $i, $item := $group.Issues }}
+• {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "kudos" }}
+
+{{ template "sectionHeader" ($.Heading "kudos" "Kudos") }}
+{{- range .KudosOrPlaceholder }}
+• {{ escape . }}
+{{- end }}
+{{- range .SuggestedKudos }}
+• {{ escape .Name }} ({{ escape ($.T "suggested: %s" ($.KudosReason .)) }})
+{{- end }}{{ end }}
+{{- define "time-off" }}
+
+{{ template "sectionHeader" ($.Heading "time-off" "Time off") }}
+{{ escape .TimeOffText }}{{ end }}
+{{- define "stats" }}{{- if or .Stats .CycleTime }}
+
+{{ template "sectionHeader" ($.Heading "stats" "Velocity") }}{{ with .Stats }}
+• {{ escape ($.T "%s of %s committed pts completed (%s%%)" (points .CompletedPoints) (points .CommittedPoints) (points .CompletionPercentage)) }}
+• {{ escape ($.T "%d issues completed, %d not completed, %d added after the start" .CompletedIssues .NotCompletedIssues .AddedIssues) }}
+{{- end }}{{ with .CycleTime }}
+• {{ escape ($.T "%d issues done in %s days on average, %s days at the median" .Issues (points .AverageDays) (points .MedianDays)) }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "provenance" }}{{ with .ProvenanceNote }}
+
+_{{ escape . }}_{{ end }}{{ end }}
+{{- define "layout" }}*{{ escape .Title }}*{{ template "sections" . }}{{ template "provenance" . }}{{ end }}
+{{ template "layout" . }}
+`
+
+// ConfluenceTemplate is a Confluence wiki markup template.
+const ConfluenceTemplate string = sectionsTemplate + `{{- define "sectionHeader" }}h3. {{ escape . }}{{ end }}
+{{- define "statusGroups" }}
+{{- range $group := . }}
+{{- if and ($group.Collapsible "confluence") (not $group.Open) }}
+
+{expand:{{ escape $group.Name }}{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}}
+{{- else }}
+
+_{{ escape $group.Name }}_{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}
+{{- end }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if not $group.Status }} ({{ escape $item.Status }}){{ end }}{{ if $item.Change }} ({{ escape $item.ChangeNote }}){{ end }}{{ if $item.TimeSpent }} ({{ hours $item.TimeSpent }}){{ end }}{{ with $item.SubtaskProgress }} ({{ . }}){{ end }}{{ with $item.Annotations }} ({{ escape (join . ", ") }}){{ end }}
+{{- if $item.Note }}
+** {{ escape $item.Note }}
+{{- end }}
+{{- range $sub := $item.Subtasks }}
+** {{ template "issueLine" $sub }} ({{ escape $sub.Status }})
+{{- end }}
+{{- end }}
+{{- if $group.Folded }}
+* {{ link $group.FoldedText $group.FoldedURL }}
+{{- end }}
+{{- if and ($group.Collapsible "confluence") (not $group.Open) }}
+{expand}
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "summary" }}{{- if .Summary }}
+
+{{ template "sectionHeader" ($.Heading "summary" "Summary") }}
+
+{{ escape .Summary }}
+{{- end }}{{ end }}
+{{- define "themes" }}{{- if .Themes }}
+
+{{ template "sectionHeader" ($.Heading "themes" "Themes") }}
+{{ range .Themes }}
+* {{ with link .Key .URL }}{{ . }} - {{ end }}{{ escape .Name }}: {{ if .Total }}{{ escape ($.T "%s%% done" (points .DonePercentage)) }}, {{ end }}{{ if eq .Issues 1 }}{{ escape ($.T "%d issue" .Issues) }}{{ else }}{{ escape ($.T "%d issues" .Issues) }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "worked-on" }}
+
+{{ template "sectionHeader" ($.Heading "worked-on" "Worked on") }}
+{{- if .StoryPoints }}
+
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+{{- if .Members }}
+{{- range .Members }}
+
+h4. {{ escape .Name }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else if .Projects }}
+{{- range .Projects }}
+
+h4. {{ escape .Name }} ({{ if eq .Count 1 }}{{ escape ($.T "%d issue" .Count) }}{{ else }}{{ escape ($.T "%d issues" .Count) }}{{ end }})
+{{- if $.StoryPoints }}
+
+{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}
+{{- end }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}{{ end }}
+{{- define "blocked" }}{{- if .Blocked }}
+
+{{ template "sectionHeader" ($.Heading "blocked" "Blocked / Needs help") }}
+{{ range $group := $.Groups .Blocked }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- if $item.BlockedBy }} ({{ escape ($.T "blocked by %s" (join $item.BlockedBy ", ")) }}){{ end }}{{ if $item.Flagged }} ({{ escape ($.T "flagged") }}){{ end }}{{ if $item.BlockedReason }}: {{ escape $item.BlockedReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "dependencies" }}{{- if .Dependencies }}
+
+{{ template "sectionHeader" ($.Heading "dependencies" "Dependencies on other teams") }}
+{{ range $dependency := .Dependencies }}
+* {{ template "issueLine" $dependency }}: {{ escape $dependency.Link.Relation }} {{ with $dependency.Link }}{{ template "issueLine" . }}{{ with .Status }} ({{ escape . }}){{ end }}{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "pull-requests" }}{{- if .PullRequests }}
+
+{{ template "sectionHeader" ($.Heading "pull-requests" "Pull requests") }}
+{{ range $i, $pr := .PullRequests }}
+* [{{ escape $pr.Repository }}#{{ $pr.Number }}|{{ $pr.URL }}] - {{ escape $pr.Title }}{{ if $pr.Merged }} ({{ escape ($.T "merged") }}){{ end }}
+{{- range $j, $issue := $pr.Issues }}{{ if $j }},{{ else }} -{{ end }} [{{ $issue.Key }}|{{ $issue.URL }}]{{ end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "commits" }}{{- if .Commits }}
+
+{{ template "sectionHeader" ($.Heading "commits" "Commits") }}
+{{- range $group := .Commits }}
+
+{{ if $group.Key }}{{ link $group.Key $group.URL }}{{ with $group.Summary }} - {{ escape . }}{{ end }}{{ else }}_{{ escape ($.T "No issue") }}_{{ end }}
+{{- range $commit := $group.Commits }}
+* {{ escape $commit.Repository }}@{{ $commit.ShortHash }} - {{ escape $commit.Subject }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "hours" }}{{- with .Timesheet }}
+
+{{ template "sectionHeader" ($.Heading "hours" "Hours") }}
+
+{{ escape ($.T "%s logged" (hours .Total)) }}{{ if .Approved }} ({{ escape ($.T "approved") }}){{ end }}
+{{- if .Unlogged }}
+
+{{ escape ($.T "Done without logged time:") }}
+{{- range $item := .Unlogged }}
+* {{ template "issueLine" $item }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "spillovers" }}
+
+{{ template "sectionHeader" ($.Heading "spillovers" "Spillovers") }}
+{{ if .Spillovers }}
+{{- range $group := $.Groups .Spillovers }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}{{ if $item.SpilloverReason }}: {{ escape $item.SpilloverReason }}{{ end }}
+{{- end }}
+{{- end }}
+{{- else }}
+{{ escape ($.T "No spillovers in this sprint.") }}
+{{- end }}{{ end }}
+{{- define "carried-over" }}{{- if .CarriedOver }}
+
+{{ template "sectionHeader" ($.Heading "carried-over" "Carried over from %s" .CarriedOverFrom) }}
+{{ range $group := $.Groups .CarriedOver }}
+{{- range $i, $item := $group.Issues }}
+* {{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- end }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "kudos" }}
+
+{{ template "sectionHeader" ($.Heading "kudos" "Kudos") }}
+{{ range .KudosOrPlaceholder }}
+* {{ escape . }}
+{{- end }}
+{{- range .SuggestedKudos }}
+* {{ escape .Name }} ({{ escape ($.T "suggested: %s" ($.KudosReason .)) }})
+{{- end }}{{ end }}
+{{- define "time-off" }}
+
+{{ template "sectionHeader" ($.Heading "time-off" "Time off") }}
+
+{{ escape .TimeOffText }}{{ end }}
+{{- define "stats" }}{{- if or .Stats .CycleTime }}
+
+{{ template "sectionHeader" ($.Heading "stats" "Velocity") }}
+{{ with .Stats }}
+* {{ escape ($.T "%s of %s committed pts completed (%s%%)" (points .CompletedPoints) (points .CommittedPoints) (points .CompletionPercentage)) }}
+* {{ escape ($.T "%d issues completed, %d not completed, %d added after the start" .CompletedIssues .NotCompletedIssues .AddedIssues) }}
+{{- end }}{{ with .CycleTime }}
+* {{ escape ($.T "%d issues done in %s days on average, %s days at the median" .Issues (points .AverageDays) (points .MedianDays)) }}
+{{- end }}
+{{- end }}{{ end }}
+{{- define "provenance" }}{{ with .ProvenanceNote }}
+
+----
+_{{ escape . }}_{{ end }}{{ end }}
+{{- define "layout" }}h2. {{ escape .Title }}{{ template "sections" . }}{{ template "provenance" . }}{{ end }}
+{{ template "layout" . }}
+`
+
+// HTMLTemplate is an HTML fragment template.
+const HTMLTemplate string = sectionsTemplate + `{{- define "sectionHeader" }}<h3>{{ escape . }}</h3>{{ end }}
+{{- define "statusGroups" }}
+{{- range $group := . }}
+{{- if $group.Collapsible "html" }}
+<details{{ if $group.Open }} open{{ end }}>
+<summary>{{ escape $group.Name }}{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}</summary>
+{{- else }}
+<p><em>{{ escape $group.Name }}</em>{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}</p>
+{{- end }}
+<ul>
+{{- range $i, $item := $group.Issues }}
+<li>{{ template "issueLine" $item }}{{ if not $group.Status }} ({{ escape $item.Status }}){{ end }}{{ if $item.Change }} ({{ escape $item.ChangeNote }}){{ end }}{{ if $item.TimeSpent }} ({{ hours $item.TimeSpent }}){{ end }}{{ with $item.SubtaskProgress }} ({{ . }}){{ end }}{{ with $item.Annotations }} ({{ escape (join . ", ") }}){{ end }}{{ if $item.Note }}<ul><li>{{ escape $item.Note }}</li></ul>{{ end }}
+{{- if $item.Subtasks }}
+<ul>
+{{- range $sub := $item.Subtasks }}
+<li>{{ template "issueLine" $sub }} ({{ escape $sub.Status }})</li>
+{{- end }}
+</ul>
+{{- end }}</li>
+{{- end }}
+{{- if $group.Folded }}
+<li>{{ link $group.FoldedText $group.FoldedURL }}</li>
+{{- end }}
+</ul>
+{{- if $group.Collapsible "html" }}
+</details>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- define "summary" }}{{- if .Summary }}
+
+{{ template "sectionHeader" ($.Heading "summary" "Summary") }}
+<p>{{ escape .Summary }}</p>
+{{- end }}{{ end }}
+{{- define "themes" }}{{- if .Themes }}
+
+{{ template "sectionHeader" ($.Heading "themes" "Themes") }}
+<ul>
+{{- range .Themes }}
+<li>{{ with link .Key .URL }}{{ . }} - {{ end }}{{ escape .Name }}: {{ if .Total }}{{ escape ($.T "%s%% done" (points .DonePercentage)) }}, {{ end }}{{ if eq .Issues 1 }}{{ escape ($.T "%d issue" .Issues) }}{{ else }}{{ escape ($.T "%d issues" .Issues) }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "worked-on" }}
+
+{{ template "sectionHeader" ($.Heading "worked-on" "Worked on") }}
+{{- if .StoryPoints }}
+<p>{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}</p>
+{{- end }}
+{{- if .Members }}
+{{- range .Members }}
+
+<h4>{{ escape .Name }}</h4>
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else if .Projects }}
+{{- range .Projects }}
+
+<h4>{{ escape .Name }} ({{ if eq .Count 1 }}{{ escape ($.T "%d issue" .Count) }}{{ else }}{{ escape ($.T "%d issues" .Count) }}{{ end }})</h4>
+{{- if $.StoryPoints }}
+<p>{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}</p>
+{{- end }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}{{ end }}
+{{- define "blocked" }}{{- if .Blocked }}
+
+{{ template "sectionHeader" ($.Heading "blocked" "Blocked / Needs help") }}
+<ul>
+{{- range $group := $.Groups .Blocked }}
+{{- range $i, $item := $group.Issues }}
+<li>{{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- if $item.BlockedBy }} ({{ escape ($.T "blocked by %s" (join $item.BlockedBy ", ")) }}){{ end }}{{ if $item.Flagged }} ({{ escape ($.T "flagged") }}){{ end }}{{ if $item.BlockedReason }}: {{ escape $item.BlockedReason }}{{ end }}</li>
+{{- end }}
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "dependencies" }}{{- if .Dependencies }}
+
+{{ template "sectionHeader" ($.Heading "dependencies" "Dependencies on other teams") }}
+<ul>
+{{- range $dependency := .Dependencies }}
+<li>{{ template "issueLine" $dependency }}: {{ escape $dependency.Link.Relation }} {{ with $dependency.Link }}{{ template "issueLine" . }}{{ with .Status }} ({{ escape . }}){{ end }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "pull-requests" }}{{- if .PullRequests }}
+
+{{ template "sectionHeader" ($.Heading "pull-requests" "Pull requests") }}
+<ul>
+{{- range $i, $pr := .PullRequests }}
+<li><a href="{{ escape $pr.URL }}">{{ escape $pr.Repository }}#{{ $pr.Number }}</a> - {{ escape $pr.Title }}{{ if $pr.Merged }} ({{ escape ($.T "merged") }}){{ end }}
+{{- range $j, $issue := $pr.Issues }}{{ if $j }},{{ else }} -{{ end }} <a href="{{ escape $issue.URL }}">{{ escape $issue.Key }}</a>{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "commits" }}{{- if .Commits }}
+
+{{ template "sectionHeader" ($.Heading "commits" "Commits") }}
+{{- range $group := .Commits }}
+<p>{{ if $group.Key }}{{ link $group.Key $group.URL }}{{ with $group.Summary }} - {{ escape . }}{{ end }}{{ else }}<em>{{ escape ($.T "No issue") }}</em>{{ end }}</p>
+<ul>
+{{- range $commit := $group.Commits }}
+<li>{{ escape $commit.Repository }}@<code>{{ $commit.ShortHash }}</code> - {{ escape $commit.Subject }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}{{ end }}
+{{- define "hours" }}{{- with .Timesheet }}
+
+{{ template "sectionHeader" ($.Heading "hours" "Hours") }}
+<p>{{ escape ($.T "%s logged" (hours .Total)) }}{{ if .Approved }} ({{ escape ($.T "approved") }}){{ end }}</p>
+{{- if .Unlogged }}
+<p>{{ escape ($.T "Done without logged time:") }}</p>
+<ul>
+{{- range $item := .Unlogged }}
+<li>{{ template "issueLine" $item }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}{{ end }}
+{{- define "spillovers" }}
+
+{{ template "sectionHeader" ($.Heading "spillovers" "Spillovers") }}
+{{- if .Spillovers }}
+<ul>
+{{- range $group := $.Groups .Spillovers }}
+{{- range $i, $item := $group.Issues }}
+<li>{{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}{{ if $item.SpilloverReason }}: {{ escape $item.SpilloverReason }}{{ end }}</li>
+{{- end }}
+{{- end }}
+</ul>
+{{- else }}
+<p>{{ escape ($.T "No spillovers in this sprint.") }}</p>
+{{- end }}{{ end }}
+{{- define "carried-over" }}{{- if .CarriedOver }}
+
+{{ template "sectionHeader" ($.Heading "carried-over" "Carried over from %s" .CarriedOverFrom) }}
+<ul>
+{{- range $group := $.Groups .CarriedOver }}
+{{- range $i, $item := $group.Issues }}
+<li>{{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}</li>
+{{- end }}
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "kudos" }}
+
+{{ template "sectionHeader" ($.Heading "kudos" "Kudos") }}
+<ul>
+{{- range .KudosOrPlaceholder }}
+<li>{{ escape . }}</li>
+{{- end }}
+{{- range .SuggestedKudos }}
+<li>{{ escape .Name }} ({{ escape ($.T "suggested: %s" ($.KudosReason .)) }})</li>
+{{- end }}
+</ul>{{ end }}
+{{- define "time-off" }}
+
+{{ template "sectionHeader" ($.Heading "time-off" "Time off") }}
+<p>{{ escape .TimeOffText }}</p>{{ end }}
+{{- define "stats" }}{{- if or .Stats .CycleTime }}
+
+{{ template "sectionHeader" ($.Heading "stats" "Velocity") }}
+<ul>{{ with .Stats }}
+<li>{{ escape ($.T "%s of %s committed pts completed (%s%%)" (points .CompletedPoints) (points .CommittedPoints) (points .CompletionPercentage)) }}</li>
+<li>{{ escape ($.T "%d issues completed, %d not completed, %d added after the start" .CompletedIssues .NotCompletedIssues .AddedIssues) }}</li>
+{{- end }}{{ with .CycleTime }}
+<li>{{ escape ($.T "%d issues done in %s days on average, %s days at the median" .Issues (points .AverageDays) (points .MedianDays)) }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "provenance" }}{{ with .ProvenanceNote }}
+<hr>
+<p><small>{{ escape . }}</small></p>{{ end }}{{ end }}
+{{- define "layout" }}<h2>{{ escape .Title }}</h2>{{ template "sections" . }}{{ template "provenance" . }}{{ end }}
+{{ template "layout" . }}
+`
+
+// Format is an output format of the sprint update.
+type Format struct {
+	// Name is the name of the format.
+	Name string
+	// Template is the built-in template of the format.
+	Template string
+	// Escape escapes the values rendered by the templates according to the
+	// escaping rules of the format.
+	Escape func(string) string
+	// Link renders the link of an issue, or its escaped key if the URL is
+	// empty, like for redacted updates. If both are empty, an empty string
+	// is returned.
+	Link func(key string, url string) string
+	// Encode encodes the sprint update as structured data. It is set for the
+	// structured formats only, which have no template.
+	Encode func(v interface{}) ([]byte, error)
+	// Document indicates that Encode renders the sprint update itself as a
+	// binary document, like a PDF, instead of encoding its structured
+	// representation. The documents cannot be edited or delivered.
+	Document bool
+}
+
+// IsStructured reports whether the format encodes the sprint update as
+// structured data instead of rendering a template.
+func (f *Format) IsStructured() bool {
+	return f.Encode != nil
+}
+
+// encodeJSON encodes the value as indented JSON.
+func encodeJSON(v interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
+
+// newLink returns the function rendering the issue links using the layout,
+// which receives the URL and the escaped key, in this order.
+func newLink(layout string, escape func(string) string) func(string, string) string {
+	return func(key string, url string) string {
+		switch {
+		case url == "":
+			return escape(key)
+		case key == "":
+			return fmt.Sprintf(layout, url, escape(url))
+		default:
+			return fmt.Sprintf(layout, url, escape(key))
+		}
+	}
+}
+
+// htmlLink renders the issue links of the HTML format, escaping the URL of the
+// attribute as well.
+func htmlLink(key string, url string) string {
+	return newLink(`<a href="%[1]s">%[2]s</a>`, html.EscapeString)(key, html.EscapeString(url))
+}
+
+// noEscape returns the value as it is, for the structured formats.
+func noEscape(value string) string {
+	return value
+}
+
+// slackEscaper escapes the control characters of Slack mrkdwn.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markdownEscaper escapes the characters having a special meaning in inline
+// Markdown, so summaries and titles are rendered as they are, without HTML
+// entities, by Discourse and GitHub alike.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"&", `\&`,
+	"~", `\~`,
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+	"|", `\|`,
+)
+
+// confluenceEscaper escapes the characters having a special meaning in
+// Confluence wiki markup.
+var confluenceEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"{", `\{`,
+	"}", `\}`,
+	"|", `\|`,
+)
+
+// formats are the built-in output formats.
+var formats = map[string]*Format{
+	"discourse": {
+		Name:     "discourse",
+		Template: DefaultTemplate,
+		Escape:   markdownEscaper.Replace,
+		Link:     newLink("[%[2]s](%[1]s)", markdownEscaper.Replace),
+	},
+	DecoratedFormat: {
+		Name:     DecoratedFormat,
+		Template: DecoratedTemplate,
+		Escape:   markdownEscaper.Replace,
+		Link:     newLink("[%[2]s](%[1]s)", markdownEscaper.Replace),
+	},
+	"markdown": {
+		Name:     "markdown",
+		Template: MarkdownTemplate,
+		Escape:   markdownEscaper.Replace,
+		Link:     newLink("[%[2]s](%[1]s)", markdownEscaper.Replace),
+	},
+	"slack": {
+		Name:     "slack",
+		Template: SlackTemplate,
+		Escape:   slackEscaper.Replace,
+		Link:     newLink("<%[1]s|%[2]s>", slackEscaper.Replace),
+	},
+	"confluence": {
+		Name:     "confluence",
+		Template: ConfluenceTemplate,
+		Escape:   confluenceEscaper.Replace,
+		Link:     newLink("[%[2]s|%[1]s]", confluenceEscaper.Replace),
+	},
+	"html": {
+		Name:     "html",
+		Template: HTMLTemplate,
+		Escape:   html.EscapeString,
+		Link:     htmlLink,
+	},
+	StyledHTMLFormat: {
+		Name:     StyledHTMLFormat,
+		Template: StyledHTMLTemplate,
+		Escape:   html.EscapeString,
+		Link:     htmlLink,
+	},
+	"pdf": {
+		Name:     "pdf",
+		Escape:   noEscape,
+		Link:     newLink("%[2]s (%[1]s)", noEscape),
+		Encode:   encodePDF,
+		Document: true,
+	},
+	"json": {
+		Name:   "json",
+		Escape: noEscape,
+		Link:   newLink("%[2]s (%[1]s)", noEscape),
+		Encode: encodeJSON,
+	},
+	"yaml": {
+		Name:   "yaml",
+		Escape: noEscape,
+		Link:   newLink("%[2]s (%[1]s)", noEscape),
+		Encode: yaml.Marshal,
+	},
+}
+
+// Formats returns the names of the built-in output formats.
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// ValidateFormats checks that the formats are built-in output formats.
+func ValidateFormats(names []string) error {
+	for _, name := range names {
+		if _, err := LookupFormat(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LookupFormat returns the built-in output format with the given name. If the
+// name is empty, DefaultFormat is returned.
+func LookupFormat(name string) (*Format, error) {
+	if name == "" {
+		name = DefaultFormat
+	}
+
+	format, ok := formats[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (available: %s)", ErrUnknownFormat, name, strings.Join(Formats(), ", "))
+	}
+
+	return format, nil
+}
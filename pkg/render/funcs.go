@@ -0,0 +1,149 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// ErrUnsupportedArgument is returned when a template function is called with
+// a value it cannot handle.
+var ErrUnsupportedArgument = errors.New("unsupported argument")
+
+// helperFuncs returns the template functions independent of the output
+// format, available in the sprint update and the title templates. They take
+// the piped value as their last argument, like {{ .Summary | truncate 40 }}.
+func helperFuncs() template.FuncMap {
+	return template.FuncMap{
+		"date":        formatDate,
+		"truncate":    truncate,
+		"plural":      plural,
+		"sortAlpha":   sortAlpha,
+		"upper":       strings.ToUpper,
+		"lower":       strings.ToLower,
+		"trim":        strings.TrimSpace,
+		"replace":     replace,
+		"contains":    contains,
+		"default":     defaultValue,
+		"statusEmoji": statusEmoji,
+		"statusColor": report.StatusColor,
+		"issueCount":  issueCount,
+		"pointsTotal": pointsTotal,
+	}
+}
+
+// formatDate formats the time using the Go layout, like "Jan 2". The zero
+// time, used for the unknown dates, is formatted as an empty string.
+func formatDate(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(layout)
+}
+
+// truncate truncates the text to the given number of characters, like the
+// issue summaries are truncated.
+func truncate(length int, text string) string {
+	return report.Truncate(text, length)
+}
+
+// plural returns the singular form if the count is 1, or the plural form
+// otherwise, like {{ len .Issues }} {{ plural (len .Issues) "issue" "issues" }}.
+func plural(count int, singular string, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+
+	return pluralForm
+}
+
+// sortAlpha returns a copy of the list sorted in alphabetical order.
+func sortAlpha(list []string) []string {
+	sorted := append([]string(nil), list...)
+	sort.Strings(sorted)
+
+	return sorted
+}
+
+// replace replaces every occurrence of old with new in the text.
+func replace(old string, new string, text string) string {
+	return strings.ReplaceAll(text, old, new)
+}
+
+// contains reports whether the text contains the substring.
+func contains(substr string, text string) bool {
+	return strings.Contains(text, substr)
+}
+
+// defaultValue returns the fallback if the value is empty, or the value
+// otherwise.
+func defaultValue(fallback string, value string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
+
+// statusEmoji returns the default emoji of the status, like "✅" for "Done"
+// or "🚧" for "In Progress". The emojis configured for the update are
+// returned by its Emoji method instead.
+func statusEmoji(status string) string {
+	return report.StatusEmoji(status, nil)
+}
+
+// issuesOf returns the issues of the value, which can be issues grouped by
+// status, a list of issues, a status group, or a list of status groups.
+func issuesOf(value interface{}) ([]report.Issue, error) {
+	switch v := value.(type) {
+	case report.Issues:
+		var issues []report.Issue
+		for _, statusIssues := range v {
+			issues = append(issues, statusIssues...)
+		}
+
+		return issues, nil
+	case []report.Issue:
+		return v, nil
+	case report.StatusGroup:
+		return v.Issues, nil
+	case []report.StatusGroup:
+		var issues []report.Issue
+		for _, group := range v {
+			issues = append(issues, group.Issues...)
+		}
+
+		return issues, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedArgument, value)
+	}
+}
+
+// issueCount returns the number of issues of the value, like
+// {{ issueCount .Spillovers }}.
+func issueCount(value interface{}) (int, error) {
+	issues, err := issuesOf(value)
+	return len(issues), err
+}
+
+// pointsTotal returns the total story points of the issues of the value, like
+// {{ points (pointsTotal .Spillovers) }}.
+func pointsTotal(value interface{}) (float64, error) {
+	issues, err := issuesOf(value)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, issue := range issues {
+		total += issue.StoryPoints
+	}
+
+	return total, nil
+}
@@ -0,0 +1,399 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/pdf"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+const (
+	// titleSize, headingSize, and subheadingSize are the font sizes of the
+	// title, the section headings, and the member and project headings of
+	// the PDF documents.
+	titleSize      = 18
+	headingSize    = 13
+	subheadingSize = 11
+)
+
+// ErrUnsupportedDocument is returned when a document format is used for
+// something other than a sprint update, like a rollup.
+var ErrUnsupportedDocument = errors.New("documents can be rendered for sprint updates only")
+
+// encodePDF renders the sprint update as a PDF document, listing the issue
+// groups of the worked on section as tables colored by their status.
+func encodePDF(v interface{}) ([]byte, error) {
+	update, ok := v.(*report.Update)
+	if !ok {
+		return nil, ErrUnsupportedDocument
+	}
+
+	doc := pdf.New(update.Title)
+	doc.Heading(update.Title, titleSize)
+
+	if dates := sprintDates(update); dates != "" {
+		doc.Paragraph(dates, pdf.Italic)
+	}
+
+	for _, section := range update.OrderedSections() {
+		switch section {
+		case report.SectionSummary:
+			if update.Summary != "" {
+				doc.Heading(update.Heading(string(section), "Summary"), headingSize)
+				doc.Paragraph(update.Summary, pdf.Regular)
+			}
+		case report.SectionThemes:
+			pdfThemes(doc, update)
+		case report.SectionWorkedOn:
+			pdfWorkedOn(doc, update)
+		case report.SectionBlocked:
+			pdfIssues(doc, update, update.Heading(string(section), "Blocked / Needs help"), update.Blocked, "")
+		case report.SectionDependencies:
+			pdfDependencies(doc, update)
+		case report.SectionPullRequests:
+			pdfPullRequests(doc, update)
+		case report.SectionCommits:
+			pdfCommits(doc, update)
+		case report.SectionHours:
+			pdfHours(doc, update)
+		case report.SectionSpillovers:
+			pdfIssues(doc, update, update.Heading(string(section), "Spillovers"), update.Spillovers, update.T("No spillovers in this sprint."))
+		case report.SectionCarriedOver:
+			pdfIssues(doc, update, update.Heading(string(section), "Carried over from %s", update.CarriedOverFrom), update.CarriedOver, "")
+		case report.SectionKudos:
+			pdfKudos(doc, update)
+		case report.SectionTimeOff:
+			doc.Heading(update.Heading(string(section), "Time off"), headingSize)
+			doc.Paragraph(update.TimeOffText(), pdf.Regular)
+		case report.SectionStats:
+			pdfStats(doc, update)
+		}
+	}
+
+	if note := update.ProvenanceNote(); note != "" {
+		doc.Paragraph(note, pdf.Italic)
+	}
+
+	return doc.Bytes(), nil
+}
+
+// sprintDates returns the dates of the sprint, like "Oct 4 - Oct 15, 2021".
+// If the dates are unknown, an empty string is returned.
+func sprintDates(update *report.Update) string {
+	if update.StartDate.IsZero() || update.EndDate.IsZero() {
+		return ""
+	}
+
+	return formatDate("Jan 2", update.StartDate) + " - " + formatDate("Jan 2, 2006", update.EndDate)
+}
+
+// pdfWorkedOn adds the worked on section, split by member or project if set.
+func pdfWorkedOn(doc *pdf.Document, update *report.Update) {
+	doc.Heading(update.Heading(string(report.SectionWorkedOn), "Worked on"), headingSize)
+
+	if update.StoryPoints {
+		doc.Paragraph(update.T("Done: %s pts of %s committed", report.FormatPoints(update.DonePoints()), report.FormatPoints(update.CommittedPoints())), pdf.Regular)
+	}
+
+	switch {
+	case len(update.Members) > 0:
+		for _, member := range update.Members {
+			doc.Heading(member.Name, subheadingSize)
+			pdfGroups(doc, update, member.Issues)
+		}
+	case len(update.Projects) > 0:
+		for i := range update.Projects {
+			project := &update.Projects[i]
+
+			count := update.T("%d issues", project.Count())
+			if project.Count() == 1 {
+				count = update.T("%d issue", project.Count())
+			}
+
+			doc.Heading(fmt.Sprintf("%s (%s)", project.Name, count), subheadingSize)
+
+			if update.StoryPoints {
+				doc.Paragraph(update.T("Done: %s pts of %s committed", report.FormatPoints(project.DonePoints()), report.FormatPoints(project.CommittedPoints())), pdf.Regular)
+			}
+
+			pdfGroups(doc, update, project.Issues)
+		}
+	default:
+		pdfGroups(doc, update, update.Issues)
+	}
+}
+
+// pdfGroups adds a table for every issue group, colored by the status of the
+// group.
+func pdfGroups(doc *pdf.Document, update *report.Update, issues report.Issues) {
+	for _, group := range update.Groups(issues) {
+		title := group.Name
+		if group.StoryPoints > 0 {
+			title += fmt.Sprintf(" (%s pts)", report.FormatPoints(group.StoryPoints))
+		}
+
+		table := pdf.Table{
+			Title:   title,
+			Color:   pdf.ParseColor(report.StatusColor(group.Name)),
+			Columns: []pdf.Column{{Title: "Issue", Width: 1.2}, {Title: "Summary", Width: 4.5}},
+		}
+
+		if group.Status == "" {
+			table.Columns = append(table.Columns, pdf.Column{Title: "Status", Width: 1.4})
+		}
+
+		table.Columns = append(table.Columns, pdf.Column{Title: "Notes", Width: 2.6})
+
+		for i := range group.Issues {
+			issue := &group.Issues[i]
+
+			row := []pdf.Cell{{Text: issue.Key, URL: issue.URL}, {Text: issue.Summary}}
+			if group.Status == "" {
+				row = append(row, pdf.Cell{Text: issue.Status})
+			}
+
+			table.Rows = append(table.Rows, append(row, pdf.Cell{Text: issueNotes(issue)}))
+		}
+
+		doc.AddTable(table)
+	}
+}
+
+// issueNotes returns the notes of the issue in the tables of the groups, like
+// its change, logged time, and subtasks, one per line.
+func issueNotes(issue *report.Issue) string {
+	var notes []string
+	if issue.Change != "" {
+		notes = append(notes, issue.ChangeNote())
+	}
+
+	if issue.TimeSpent > 0 {
+		notes = append(notes, report.FormatHours(issue.TimeSpent))
+	}
+
+	if progress := issue.SubtaskProgress(); progress != "" {
+		notes = append(notes, progress)
+	}
+
+	if len(issue.Annotations) > 0 {
+		notes = append(notes, strings.Join(issue.Annotations, ", "))
+	}
+
+	if issue.Note != "" {
+		notes = append(notes, issue.Note)
+	}
+
+	for _, subtask := range issue.Subtasks {
+		notes = append(notes, fmt.Sprintf("%s - %s (%s)", subtask.Key, subtask.Summary, subtask.Status))
+	}
+
+	return strings.Join(notes, "\n")
+}
+
+// pdfIssues adds a titled list of the issues of every status. If there are no
+// issues, the empty message is added instead, unless it is empty too.
+func pdfIssues(doc *pdf.Document, update *report.Update, title string, issues report.Issues, empty string) {
+	groups := update.Groups(issues)
+	if len(groups) == 0 && empty == "" {
+		return
+	}
+
+	doc.Heading(title, headingSize)
+
+	if len(groups) == 0 {
+		doc.Paragraph(empty, pdf.Regular)
+		return
+	}
+
+	for _, group := range groups {
+		for i := range group.Issues {
+			issue := &group.Issues[i]
+
+			text := issue.Summary
+			if issue.Key != "" {
+				text = issue.Key + " - " + text
+			}
+
+			if len(update.Members) > 0 {
+				text += fmt.Sprintf(" (%s)", issue.Assignee)
+			}
+
+			if len(issue.BlockedBy) > 0 {
+				text += fmt.Sprintf(" (%s)", update.T("blocked by %s", strings.Join(issue.BlockedBy, ", ")))
+			}
+
+			if issue.Flagged {
+				text += fmt.Sprintf(" (%s)", update.T("flagged"))
+			}
+
+			if issue.BlockedReason != "" {
+				text += ": " + issue.BlockedReason
+			}
+
+			if issue.SpilloverReason != "" {
+				text += ": " + issue.SpilloverReason
+			}
+
+			doc.Bullet(text, issue.URL, 0)
+		}
+	}
+}
+
+// pdfThemes adds the themes section, if there are themes.
+func pdfThemes(doc *pdf.Document, update *report.Update) {
+	if len(update.Themes) == 0 {
+		return
+	}
+
+	doc.Heading(update.Heading(string(report.SectionThemes), "Themes"), headingSize)
+
+	for i := range update.Themes {
+		theme := &update.Themes[i]
+
+		text := theme.Name + ": "
+		if theme.Total > 0 {
+			text += update.T("%s%% done", report.FormatPoints(theme.DonePercentage())) + ", "
+		}
+
+		if theme.Issues == 1 {
+			text += update.T("%d issue", theme.Issues)
+		} else {
+			text += update.T("%d issues", theme.Issues)
+		}
+
+		doc.Bullet(text, theme.URL, 0)
+	}
+}
+
+// pdfDependencies adds the dependencies on other teams section, if there are
+// dependencies.
+func pdfDependencies(doc *pdf.Document, update *report.Update) {
+	if len(update.Dependencies) == 0 {
+		return
+	}
+
+	doc.Heading(update.Heading(string(report.SectionDependencies), "Dependencies on other teams"), headingSize)
+
+	for _, dependency := range update.Dependencies {
+		text := fmt.Sprintf("%s - %s: %s %s - %s", dependency.Key, dependency.Summary, dependency.Link.Relation, dependency.Link.Key, dependency.Link.Summary)
+		if dependency.Link.Status != "" {
+			text += " (" + dependency.Link.Status + ")"
+		}
+
+		doc.Bullet(text, dependency.Link.URL, 0)
+	}
+}
+
+// pdfPullRequests adds the pull requests section, if there are pull requests.
+func pdfPullRequests(doc *pdf.Document, update *report.Update) {
+	if len(update.PullRequests) == 0 {
+		return
+	}
+
+	doc.Heading(update.Heading(string(report.SectionPullRequests), "Pull requests"), headingSize)
+
+	for _, pr := range update.PullRequests {
+		text := fmt.Sprintf("%s#%d - %s", pr.Repository, pr.Number, pr.Title)
+		if pr.Merged {
+			text += fmt.Sprintf(" (%s)", update.T("merged"))
+		}
+
+		for i, issue := range pr.Issues {
+			if i == 0 {
+				text += " -"
+			} else {
+				text += ","
+			}
+
+			text += " " + issue.Key
+		}
+
+		doc.Bullet(text, pr.URL, 0)
+	}
+}
+
+// pdfCommits adds the commits section, if there are commits, listing the
+// commits under the issues they reference.
+func pdfCommits(doc *pdf.Document, update *report.Update) {
+	if len(update.Commits) == 0 {
+		return
+	}
+
+	doc.Heading(update.Heading(string(report.SectionCommits), "Commits"), headingSize)
+
+	for _, group := range update.Commits {
+		switch {
+		case group.Key == "":
+			doc.Paragraph(update.T("No issue"), pdf.Italic)
+		case group.Summary != "":
+			doc.Paragraph(group.Key+" - "+group.Summary, pdf.Bold)
+		default:
+			doc.Paragraph(group.Key, pdf.Bold)
+		}
+
+		for i := range group.Commits {
+			commit := &group.Commits[i]
+			doc.Bullet(fmt.Sprintf("%s@%s - %s", commit.Repository, commit.ShortHash(), commit.Subject), "", 0)
+		}
+	}
+}
+
+// pdfHours adds the section of the hours logged in Tempo, if Tempo is used.
+func pdfHours(doc *pdf.Document, update *report.Update) {
+	if update.Timesheet == nil {
+		return
+	}
+
+	doc.Heading(update.Heading(string(report.SectionHours), "Hours"), headingSize)
+
+	total := update.T("%s logged", report.FormatHours(update.Timesheet.Total))
+	if update.Timesheet.Approved {
+		total += fmt.Sprintf(" (%s)", update.T("approved"))
+	}
+
+	doc.Paragraph(total, pdf.Regular)
+
+	if len(update.Timesheet.Unlogged) > 0 {
+		doc.Paragraph(update.T("Done without logged time:"), pdf.Regular)
+	}
+
+	for _, issue := range update.Timesheet.Unlogged {
+		doc.Bullet(issue.Key+" - "+issue.Summary, issue.URL, 0)
+	}
+}
+
+// pdfKudos adds the kudos section, listing a placeholder if there are no
+// kudos.
+func pdfKudos(doc *pdf.Document, update *report.Update) {
+	doc.Heading(update.Heading(string(report.SectionKudos), "Kudos"), headingSize)
+
+	for _, k := range update.KudosOrPlaceholder() {
+		doc.Bullet(k, "", 0)
+	}
+
+	for _, k := range update.SuggestedKudos {
+		doc.Bullet(fmt.Sprintf("%s (%s)", k.Name, update.T("suggested: %s", update.KudosReason(k))), "", 0)
+	}
+}
+
+// pdfStats adds the velocity of the sprint, if the sprint report is read,
+// and the cycle time of the issues, if their timeline is read.
+func pdfStats(doc *pdf.Document, update *report.Update) {
+	stats, cycleTime := update.Stats, update.CycleTime
+	if stats == nil && cycleTime == nil {
+		return
+	}
+
+	doc.Heading(update.Heading(string(report.SectionStats), "Velocity"), headingSize)
+
+	if stats != nil {
+		doc.Bullet(update.T("%s of %s committed pts completed (%s%%)", report.FormatPoints(stats.CompletedPoints), report.FormatPoints(stats.CommittedPoints), report.FormatPoints(stats.CompletionPercentage())), "", 0)
+		doc.Bullet(update.T("%d issues completed, %d not completed, %d added after the start", stats.CompletedIssues, stats.NotCompletedIssues, stats.AddedIssues), "", 0)
+	}
+
+	if cycleTime != nil {
+		doc.Bullet(update.T("%d issues done in %s days on average, %s days at the median", cycleTime.Issues, report.FormatPoints(cycleTime.AverageDays()), report.FormatPoints(cycleTime.MedianDays())), "", 0)
+	}
+}
@@ -0,0 +1,241 @@
+package render
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// The ANSI escape sequences of the styles of the terminal preview.
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiItalic    = "\x1b[3m"
+	ansiUnderline = "\x1b[4m"
+)
+
+// span is a run of text rendered in the same style.
+type span struct {
+	text  string
+	style string
+}
+
+// Preview renders the update rendered in the markdown format for reading in
+// a terminal: the headings are bold, the status groups are colored by their
+// status, the links are underlined, and the lines are wrapped at the width,
+// indenting the wrapped lines of the list items. If color is false, plain
+// text is rendered without escape sequences.
+func Preview(markdown string, width int, color bool) string {
+	var b strings.Builder
+	blank := true
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := strings.Repeat(" ", len(line)-len(strings.TrimLeft(line, " ")))
+
+		switch {
+		case trimmed == "":
+			if !blank {
+				b.WriteString("\n")
+			}
+
+			blank = true
+			continue
+		case strings.HasPrefix(trimmed, "<details") || trimmed == "</details>":
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			style := ansiBold
+			if level <= 2 {
+				style += ansiUnderline
+			}
+
+			writeWrapped(&b, "", "", parseInline(strings.TrimSpace(trimmed[level:]), style), width, color)
+		case strings.HasPrefix(trimmed, "<summary>"):
+			name := strings.TrimSuffix(strings.TrimPrefix(trimmed, "<summary>"), "</summary>")
+			writeWrapped(&b, "", "", groupSpans(name), width, color)
+		case strings.HasPrefix(trimmed, "_") && strings.HasSuffix(strings.TrimSuffix(trimmed, pointsSuffix(trimmed)), "_"):
+			name := strings.TrimSuffix(trimmed, pointsSuffix(trimmed))
+			name = strings.TrimSuffix(strings.TrimPrefix(name, "_"), "_") + pointsSuffix(trimmed)
+			writeWrapped(&b, "", "", groupSpans(name), width, color)
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			marker := indent + "- "
+			writeWrapped(&b, marker, strings.Repeat(" ", len(marker)), parseInline(trimmed[2:], ""), width, color)
+		default:
+			writeWrapped(&b, indent, indent, parseInline(trimmed, ""), width, color)
+		}
+
+		blank = false
+	}
+
+	return b.String()
+}
+
+// pointsSuffix returns the story points of the status group following its
+// name, like " (5 pts)", or an empty string if the group has no points.
+func pointsSuffix(text string) string {
+	if !strings.HasSuffix(text, " pts)") {
+		return ""
+	}
+
+	return text[strings.LastIndex(text, " ("):]
+}
+
+// groupSpans returns the spans of the name of a status group, colored by the
+// status, followed by its story points if any.
+func groupSpans(text string) []span {
+	suffix := pointsSuffix(text)
+	name := unescapeMarkdown(strings.TrimSuffix(text, suffix))
+
+	spans := []span{{text: name, style: ansiBold + ansiColor(report.StatusColor(name))}}
+	if suffix != "" {
+		spans = append(spans, span{text: unescapeMarkdown(suffix)})
+	}
+
+	return spans
+}
+
+// ansiColor returns the escape sequence of the foreground color given as a
+// hexadecimal RGB value, like "#1a7f37".
+func ansiColor(hex string) string {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(hex, "#"), 16, 32)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", rgb>>16&0xff, rgb>>8&0xff, rgb&0xff)
+}
+
+// unescapeMarkdown removes the backslashes escaping the characters of the
+// text.
+func unescapeMarkdown(text string) string {
+	var b strings.Builder
+	escaped := false
+
+	for _, r := range text {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+
+		escaped = false
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// parseInline splits the Markdown text into spans by the bold and italic
+// markers and the links, whose text is underlined and whose URL is left
+// out. The spans are rendered in the base style besides their own style. The
+// markdown format escapes the markers in the values, hence every unescaped
+// marker is a marker.
+func parseInline(text string, base string) []span {
+	var spans []span
+	var current strings.Builder
+	bold, italic := false, false
+
+	style := func() string {
+		s := base
+		if bold {
+			s += ansiBold
+		}
+
+		if italic {
+			s += ansiItalic
+		}
+
+		return s
+	}
+
+	flush := func() {
+		if current.Len() > 0 {
+			spans = append(spans, span{text: current.String(), style: style()})
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '\\' && i+1 < len(text):
+			i++
+			current.WriteByte(text[i])
+		case strings.HasPrefix(text[i:], "**"):
+			flush()
+			bold = !bold
+			i++
+		case c == '_':
+			flush()
+			italic = !italic
+		case c == '[':
+			end := strings.Index(text[i:], "](")
+			closing := -1
+			if end >= 0 {
+				closing = strings.IndexByte(text[i+end:], ')')
+			}
+
+			if closing < 0 {
+				current.WriteByte(c)
+				continue
+			}
+
+			flush()
+			spans = append(spans, span{text: unescapeMarkdown(text[i+1 : i+end]), style: style() + ansiUnderline})
+			i += end + closing
+		default:
+			current.WriteByte(c)
+		}
+	}
+
+	flush()
+	return spans
+}
+
+// writeWrapped writes the spans as words wrapped at the width, starting the
+// first line with the prefix and the wrapped lines with the indent.
+func writeWrapped(b *strings.Builder, prefix string, indent string, spans []span, width int, color bool) {
+	b.WriteString(prefix)
+	column := utf8.RuneCountInString(prefix)
+	lineStart, space := true, false
+
+	for _, s := range spans {
+		for i, word := range strings.Split(s.text, " ") {
+			// The words of adjacent spans are only separated if there is a
+			// space between them, like before the text following a link.
+			space = space || i > 0
+			if word == "" {
+				continue
+			}
+
+			length := utf8.RuneCountInString(word)
+			switch {
+			case lineStart || !space:
+			case width > 0 && column+1+length > width:
+				b.WriteString("\n" + indent)
+				column = utf8.RuneCountInString(indent)
+			case color && s.style != "" && i > 0:
+				// The spaces within a span are styled too, so the
+				// underlined headings and links are not interrupted.
+				b.WriteString(s.style + " " + ansiReset)
+				column++
+			default:
+				b.WriteString(" ")
+				column++
+			}
+
+			if color && s.style != "" {
+				b.WriteString(s.style + word + ansiReset)
+			} else {
+				b.WriteString(word)
+			}
+
+			column += length
+			lineStart, space = false, false
+		}
+	}
+
+	b.WriteString("\n")
+}
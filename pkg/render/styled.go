@@ -0,0 +1,245 @@
+package render
+
+// StyledHTMLFormat is the name of the format rendering the update as a
+// standalone HTML document, styled by an embedded stylesheet.
+const StyledHTMLFormat = "styled-html"
+
+// DefaultStylesheet is the CSS theme embedded in the styled HTML documents,
+// unless a stylesheet is configured. The status groups are colored using the
+// --status-color custom property set on their tables.
+const DefaultStylesheet string = `body {
+  font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif;
+  font-size: 14px;
+  line-height: 1.5;
+  color: #1f2328;
+  max-width: 960px;
+  margin: 2em auto;
+  padding: 0 1em;
+}
+h1 { font-size: 1.8em; margin-bottom: 0.2em; }
+h2 { font-size: 1.3em; margin-top: 1.6em; border-bottom: 1px solid #d0d7de; }
+h3 { font-size: 1.1em; margin-top: 1.2em; }
+a { color: #0969da; text-decoration: none; }
+.dates { color: #57606a; font-style: italic; margin-top: 0; }
+.provenance {
+  color: #57606a;
+  font-size: 0.85em;
+  margin-top: 2em;
+  padding-top: 0.6em;
+  border-top: 1px solid #d0d7de;
+}
+table.group { width: 100%; border-collapse: collapse; margin: 1em 0; }
+table.group caption {
+  background: var(--status-color);
+  color: #ffffff;
+  font-weight: bold;
+  text-align: left;
+  padding: 0.4em 0.6em;
+}
+table.group th, table.group td {
+  border: 1px solid #d0d7de;
+  padding: 0.3em 0.6em;
+  text-align: left;
+  vertical-align: top;
+}
+table.group th { background: #f6f8fa; }
+table.group td.key { white-space: nowrap; border-left: 4px solid var(--status-color); }
+table.group ul { margin: 0; padding-left: 1.2em; }
+@media print {
+  body { margin: 0; max-width: none; }
+  table.group { page-break-inside: auto; }
+  table.group tr { page-break-inside: avoid; }
+}
+`
+
+// StyledHTMLTemplate is an HTML template rendering the update as a standalone
+// HTML document, listing the issue groups as tables colored by their status.
+// The stylesheet is embedded using the stylesheet function.
+const StyledHTMLTemplate string = sectionsTemplate + `{{- define "sectionHeader" }}<h2>{{ escape . }}</h2>{{ end }}
+{{- define "statusGroups" }}
+{{- range $group := . }}
+<table class="group" style="--status-color: {{ statusColor $group.Name }}">
+<caption>{{ escape $group.Name }}{{ if $group.StoryPoints }} ({{ points $group.StoryPoints }} pts){{ end }}</caption>
+<thead>
+<tr><th>Issue</th><th>Summary</th>{{ if not $group.Status }}<th>Status</th>{{ end }}<th>Notes</th></tr>
+</thead>
+<tbody>
+{{- range $i, $item := $group.Issues }}
+<tr><td class="key">{{ link $item.Key $item.URL }}</td><td>{{ escape $item.Summary }}</td>{{ if not $group.Status }}<td>{{ escape $item.Status }}</td>{{ end }}<td>
+{{- if $item.Change }}{{ escape $item.ChangeNote }} {{ end }}{{ if $item.TimeSpent }}{{ hours $item.TimeSpent }} {{ end }}{{ with $item.SubtaskProgress }}{{ . }} {{ end }}{{ with $item.Annotations }}{{ escape (join . ", ") }} {{ end }}{{ if $item.Note }}{{ escape $item.Note }}{{ end }}
+{{- if $item.Subtasks }}
+<ul>
+{{- range $sub := $item.Subtasks }}
+<li>{{ template "issueLine" $sub }} ({{ escape $sub.Status }})</li>
+{{- end }}
+</ul>
+{{- end }}</td></tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+{{- end }}
+{{- define "summary" }}{{- if .Summary }}
+
+{{ template "sectionHeader" ($.Heading "summary" "Summary") }}
+<p>{{ escape .Summary }}</p>
+{{- end }}{{ end }}
+{{- define "themes" }}{{- if .Themes }}
+
+{{ template "sectionHeader" ($.Heading "themes" "Themes") }}
+<ul>
+{{- range .Themes }}
+<li>{{ with link .Key .URL }}{{ . }} - {{ end }}{{ escape .Name }}: {{ if .Total }}{{ escape ($.T "%s%% done" (points .DonePercentage)) }}, {{ end }}{{ if eq .Issues 1 }}{{ escape ($.T "%d issue" .Issues) }}{{ else }}{{ escape ($.T "%d issues" .Issues) }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "worked-on" }}
+
+{{ template "sectionHeader" ($.Heading "worked-on" "Worked on") }}
+{{- if .StoryPoints }}
+<p>{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}</p>
+{{- end }}
+{{- if .Members }}
+{{- range .Members }}
+
+<h3>{{ escape .Name }}</h3>
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else if .Projects }}
+{{- range .Projects }}
+
+<h3>{{ escape .Name }} ({{ if eq .Count 1 }}{{ escape ($.T "%d issue" .Count) }}{{ else }}{{ escape ($.T "%d issues" .Count) }}{{ end }})</h3>
+{{- if $.StoryPoints }}
+<p>{{ escape ($.T "Done: %s pts of %s committed" (points .DonePoints) (points .CommittedPoints)) }}</p>
+{{- end }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}
+{{- else }}
+{{- template "statusGroups" ($.Groups .Issues) }}
+{{- end }}{{ end }}
+{{- define "blocked" }}{{- if .Blocked }}
+
+{{ template "sectionHeader" ($.Heading "blocked" "Blocked / Needs help") }}
+<ul>
+{{- range $group := $.Groups .Blocked }}
+{{- range $i, $item := $group.Issues }}
+<li>{{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}
+{{- if $item.BlockedBy }} ({{ escape ($.T "blocked by %s" (join $item.BlockedBy ", ")) }}){{ end }}{{ if $item.Flagged }} ({{ escape ($.T "flagged") }}){{ end }}{{ if $item.BlockedReason }}: {{ escape $item.BlockedReason }}{{ end }}</li>
+{{- end }}
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "dependencies" }}{{- if .Dependencies }}
+
+{{ template "sectionHeader" ($.Heading "dependencies" "Dependencies on other teams") }}
+<ul>
+{{- range $dependency := .Dependencies }}
+<li>{{ template "issueLine" $dependency }}: {{ escape $dependency.Link.Relation }} {{ with $dependency.Link }}{{ template "issueLine" . }}{{ with .Status }} ({{ escape . }}){{ end }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "pull-requests" }}{{- if .PullRequests }}
+
+{{ template "sectionHeader" ($.Heading "pull-requests" "Pull requests") }}
+<ul>
+{{- range $i, $pr := .PullRequests }}
+<li><a href="{{ escape $pr.URL }}">{{ escape $pr.Repository }}#{{ $pr.Number }}</a> - {{ escape $pr.Title }}{{ if $pr.Merged }} ({{ escape ($.T "merged") }}){{ end }}
+{{- range $j, $issue := $pr.Issues }}{{ if $j }},{{ else }} -{{ end }} <a href="{{ escape $issue.URL }}">{{ escape $issue.Key }}</a>{{ end }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "commits" }}{{- if .Commits }}
+
+{{ template "sectionHeader" ($.Heading "commits" "Commits") }}
+{{- range $group := .Commits }}
+<p>{{ if $group.Key }}{{ link $group.Key $group.URL }}{{ with $group.Summary }} - {{ escape . }}{{ end }}{{ else }}<em>{{ escape ($.T "No issue") }}</em>{{ end }}</p>
+<ul>
+{{- range $commit := $group.Commits }}
+<li>{{ escape $commit.Repository }}@<code>{{ $commit.ShortHash }}</code> - {{ escape $commit.Subject }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}{{ end }}
+{{- define "hours" }}{{- with .Timesheet }}
+
+{{ template "sectionHeader" ($.Heading "hours" "Hours") }}
+<p>{{ escape ($.T "%s logged" (hours .Total)) }}{{ if .Approved }} ({{ escape ($.T "approved") }}){{ end }}</p>
+{{- if .Unlogged }}
+<p>{{ escape ($.T "Done without logged time:") }}</p>
+<ul>
+{{- range $item := .Unlogged }}
+<li>{{ template "issueLine" $item }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- end }}{{ end }}
+{{- define "spillovers" }}
+
+{{ template "sectionHeader" ($.Heading "spillovers" "Spillovers") }}
+{{- if .Spillovers }}
+<ul>
+{{- range $group := $.Groups .Spillovers }}
+{{- range $i, $item := $group.Issues }}
+<li>{{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}{{ if $item.SpilloverReason }}: {{ escape $item.SpilloverReason }}{{ end }}</li>
+{{- end }}
+{{- end }}
+</ul>
+{{- else }}
+<p>{{ escape ($.T "No spillovers in this sprint.") }}</p>
+{{- end }}{{ end }}
+{{- define "carried-over" }}{{- if .CarriedOver }}
+
+{{ template "sectionHeader" ($.Heading "carried-over" "Carried over from %s" .CarriedOverFrom) }}
+<ul>
+{{- range $group := $.Groups .CarriedOver }}
+{{- range $i, $item := $group.Issues }}
+<li>{{ template "issueLine" $item }}{{ if $.Members }} ({{ escape $item.Assignee }}){{ end }}</li>
+{{- end }}
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "kudos" }}
+
+{{ template "sectionHeader" ($.Heading "kudos" "Kudos") }}
+<ul>
+{{- range .KudosOrPlaceholder }}
+<li>{{ escape . }}</li>
+{{- end }}
+{{- range .SuggestedKudos }}
+<li>{{ escape .Name }} ({{ escape ($.T "suggested: %s" ($.KudosReason .)) }})</li>
+{{- end }}
+</ul>{{ end }}
+{{- define "time-off" }}
+
+{{ template "sectionHeader" ($.Heading "time-off" "Time off") }}
+<p>{{ escape .TimeOffText }}</p>{{ end }}
+{{- define "stats" }}{{- if or .Stats .CycleTime }}
+
+{{ template "sectionHeader" ($.Heading "stats" "Velocity") }}
+<ul>{{ with .Stats }}
+<li>{{ escape ($.T "%s of %s committed pts completed (%s%%)" (points .CompletedPoints) (points .CommittedPoints) (points .CompletionPercentage)) }}</li>
+<li>{{ escape ($.T "%d issues completed, %d not completed, %d added after the start" .CompletedIssues .NotCompletedIssues .AddedIssues) }}</li>
+{{- end }}{{ with .CycleTime }}
+<li>{{ escape ($.T "%d issues done in %s days on average, %s days at the median" .Issues (points .AverageDays) (points .MedianDays)) }}</li>
+{{- end }}
+</ul>
+{{- end }}{{ end }}
+{{- define "provenance" }}{{ with .ProvenanceNote }}
+<footer class="provenance">{{ escape . }}</footer>{{ end }}{{ end }}
+{{- define "layout" }}<!DOCTYPE html>
+<html lang="{{ with .Language }}{{ escape . }}{{ else }}en{{ end }}">
+<head>
+<meta charset="utf-8">
+<title>{{ escape .Title }}</title>
+<style>
+{{ stylesheet }}</style>
+</head>
+<body>
+<h1>{{ escape .Title }}</h1>
+{{- if and (not .StartDate.IsZero) (not .EndDate.IsZero) }}
+<p class="dates">{{ .StartDate | date "Jan 2" }} - {{ .EndDate | date "Jan 2, 2006" }}</p>
+{{- end }}{{ template "sections" . }}{{ template "provenance" . }}
+</body>
+</html>{{ end -}}
+{{ template "layout" . }}
+`
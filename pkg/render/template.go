@@ -0,0 +1,153 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// templateFuncs returns the functions available in the sprint update
+// templates of the given format.
+func templateFuncs(format *Format) template.FuncMap {
+	funcs := helperFuncs()
+	funcs["join"] = strings.Join
+	funcs["escape"] = format.Escape
+	funcs["link"] = format.Link
+	funcs["points"] = report.FormatPoints
+	funcs["hours"] = report.FormatHours
+	funcs["stylesheet"] = func() string { return DefaultStylesheet }
+
+	return funcs
+}
+
+// SetStylesheet sets the CSS returned by the stylesheet function of the
+// template, replacing DefaultStylesheet embedded in the styled HTML
+// documents.
+func SetStylesheet(tmpl *template.Template, css string) {
+	tmpl.Funcs(template.FuncMap{"stylesheet": func() string { return css }})
+}
+
+// ParseTemplate parses the given sprint update template; the values are
+// escaped using the escaping rules of the format. The name is used in the
+// error messages, which contain the line number of the parse errors too.
+func ParseTemplate(name string, text string, format *Format) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs(format)).Parse(text)
+}
+
+// ParseTemplateFile reads and parses the sprint update template file found at
+// the given path.
+func ParseTemplateFile(path string, format *Format) (*template.Template, error) {
+	text, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseTemplate(filepath.Base(path), string(text), format)
+}
+
+// PartialExtension is the extension of the partial template files.
+const PartialExtension = ".tmpl"
+
+// ParseCustomTemplate parses the custom sprint update template on top of the
+// built-in template of the format, its base layout, so the custom template can
+// render the templates of the layout, like {{ template "blocked" . }}, and
+// redefine them, like {{ define "issueLine" }}. The returned template renders
+// the custom template.
+func ParseCustomTemplate(name string, text string, format *Format) (*template.Template, error) {
+	layout, err := ParseTemplate(format.Name, format.Template, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return layout.New(name).Parse(text)
+}
+
+// ParseCustomTemplateFile reads and parses the custom sprint update template
+// file found at the given path on top of the built-in template of the format.
+func ParseCustomTemplateFile(path string, format *Format) (*template.Template, error) {
+	text, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseCustomTemplate(filepath.Base(path), string(text), format)
+}
+
+// ParsePartials parses the partial template files of the directory into the
+// templates of tmpl, in the alphabetical order of the files. Every file
+// defines the template named after the file without its extension, like
+// "issueLine" for issueLine.tmpl, replacing the template of the same name,
+// and can define further templates too. A trailing newline of the files is
+// dropped, so the partials rendered within a line do not break it.
+func ParsePartials(tmpl *template.Template, dir string) error {
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == PartialExtension {
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		text, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+
+		partial := strings.TrimSuffix(strings.TrimSuffix(string(text), "\n"), "\r")
+		if _, err = tmpl.New(strings.TrimSuffix(name, PartialExtension)).Parse(partial); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Render executes the template using the given data and returns the rendered
+// sprint update.
+func Render(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// Encode encodes the data using the given structured format.
+func Encode(format *Format, data interface{}) (string, error) {
+	encoded, err := format.Encode(data)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// RenderFormat renders the data using the built-in template of the format
+// having the given name.
+func RenderFormat(name string, data interface{}) (string, error) {
+	format, err := LookupFormat(name)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := ParseTemplate(format.Name, format.Template, format)
+	if err != nil {
+		return "", err
+	}
+
+	return Render(tmpl, data)
+}
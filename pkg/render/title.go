@@ -0,0 +1,91 @@
+package render
+
+import (
+	"bytes"
+	"math"
+	"text/template"
+	"time"
+)
+
+// DefaultTitleTemplate is the template used for generating the title of the
+// sprint update.
+const DefaultTitleTemplate string = `{{ .Sprint }} - {{ .Type }}{{ with .Assignee }} ({{ . }}){{ end }}`
+
+// TitleData is the input of the title template.
+type TitleData struct {
+	// Sprint is the name of the sprint.
+	Sprint string
+	// Type is the type of the sprint update, "Mid-sprint" or "End of sprint".
+	Type string
+	// EndOfSprint indicates that an end of sprint update is generated.
+	EndOfSprint bool
+	// Assignee is the display name of the person the update is generated
+	// for. It is empty if the update is generated for the authenticated
+	// user.
+	Assignee string
+	// StartDate is the start date of the sprint. It is zero if unknown.
+	StartDate time.Time
+	// EndDate is the end date of the sprint. It is zero if unknown.
+	EndDate time.Time
+	// DaysRemaining is the number of days left until the end of the sprint.
+	DaysRemaining int
+}
+
+// SetDates sets the start and end dates of the sprint, and the days remaining
+// from now until its end. Nil dates are left unset.
+func (d *TitleData) SetDates(start *time.Time, end *time.Time, now time.Time) {
+	if start != nil {
+		d.StartDate = *start
+	}
+
+	if end != nil {
+		d.EndDate = *end
+		d.DaysRemaining = DaysRemaining(*end, now)
+	}
+}
+
+// DaysRemaining returns the number of days left from now until the end date,
+// counting partial days as whole ones. If the end date has passed, 0 is
+// returned.
+func DaysRemaining(end time.Time, now time.Time) int {
+	if !end.After(now) {
+		return 0
+	}
+
+	return int(math.Ceil(end.Sub(now).Hours() / 24))
+}
+
+// NewTitleData returns the TitleData for the given sprint and update type.
+func NewTitleData(sprintName string, endOfSprint bool) TitleData {
+	sprintUpdateType := "Mid-sprint"
+	if endOfSprint {
+		sprintUpdateType = "End of sprint"
+	}
+
+	return TitleData{
+		Sprint:      sprintName,
+		Type:        sprintUpdateType,
+		EndOfSprint: endOfSprint,
+	}
+}
+
+// ParseTitleTemplate parses the given title template. If the template is
+// empty, DefaultTitleTemplate is parsed.
+func ParseTitleTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultTitleTemplate
+	}
+
+	return template.New("title").Option("missingkey=error").Funcs(helperFuncs()).Parse(text)
+}
+
+// NewTitle renders the title of the sprint update using the given template.
+func NewTitle(tmpl *template.Template, data TitleData) (string, error) {
+	var buf bytes.Buffer
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
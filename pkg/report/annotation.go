@@ -0,0 +1,149 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Annotation is a detail of the issues rendered on their lines in the
+// built-in templates.
+type Annotation string
+
+const (
+	// AnnotationResolved annotates the resolved issues with their resolution
+	// date, like "resolved Mar 11".
+	AnnotationResolved Annotation = "resolved"
+	// AnnotationDue annotates the unresolved issues with their due date, like
+	// "⚠ due Friday" if the due date is within a week or has passed.
+	AnnotationDue Annotation = "due"
+	// AnnotationPriority annotates the issues with their priority, like
+	// "High priority".
+	AnnotationPriority Annotation = "priority"
+	// AnnotationTimeline annotates the issues with the days they were started
+	// and done at, like "started Mon, done Thu", read from their changelog.
+	AnnotationTimeline Annotation = "timeline"
+	// AnnotationLinks annotates the issues with the issues they block and
+	// are blocked by, like "blocks SE-7, blocked by OPS-42".
+	AnnotationLinks Annotation = "links"
+)
+
+const (
+	// annotationDateLayout is the layout of the annotated dates.
+	annotationDateLayout = "Jan 2"
+	// dueSoonDays is the number of days a due date is considered close
+	// within, hence rendered by the name of the weekday with a warning.
+	dueSoonDays = 7
+	// recentDays is the number of days the timeline dates are considered
+	// recent within, hence rendered by the short name of the weekday.
+	recentDays = 7
+	// dueWarning prefixes the due dates that are close or have passed.
+	dueWarning = "⚠ "
+)
+
+// ErrUnknownAnnotation is returned when the requested annotation does not
+// exist.
+var ErrUnknownAnnotation = errors.New("unknown annotation")
+
+// Annotations returns the supported annotations.
+func Annotations() []string {
+	return []string{string(AnnotationResolved), string(AnnotationDue), string(AnnotationPriority), string(AnnotationTimeline), string(AnnotationLinks)}
+}
+
+// ValidateAnnotations checks that the annotations are supported.
+func ValidateAnnotations(annotations []Annotation) error {
+	for _, annotation := range annotations {
+		switch annotation {
+		case AnnotationResolved, AnnotationDue, AnnotationPriority, AnnotationTimeline, AnnotationLinks:
+		default:
+			return fmt.Errorf("%w: %s", ErrUnknownAnnotation, annotation)
+		}
+	}
+
+	return nil
+}
+
+// annotate sets the annotations of the issue in the given order, relative to
+// the given time.
+func (i *Issue) annotate(annotations []Annotation, now time.Time) {
+	i.Annotations = nil
+
+	for _, annotation := range annotations {
+		switch {
+		case annotation == AnnotationResolved && i.Done && !i.Resolved.IsZero():
+			i.Annotations = append(i.Annotations, "resolved "+i.Resolved.Format(annotationDateLayout))
+		case annotation == AnnotationDue && !i.Done && !i.Due.IsZero():
+			i.Annotations = append(i.Annotations, dueAnnotation(i.Due, now))
+		case annotation == AnnotationPriority && i.Priority != "":
+			i.Annotations = append(i.Annotations, i.Priority+" priority")
+		case annotation == AnnotationTimeline && (!i.Started.IsZero() || !i.Finished.IsZero()):
+			i.Annotations = append(i.Annotations, timelineAnnotation(i.Started, i.Finished, now))
+		case annotation == AnnotationLinks:
+			if note := linksAnnotation(i.blockingKeys()); note != "" {
+				i.Annotations = append(i.Annotations, note)
+			}
+		}
+	}
+
+	for j := range i.Subtasks {
+		i.Subtasks[j].annotate(annotations, now)
+	}
+}
+
+// dueAnnotation returns the annotation of the due date. The due dates within
+// a week are rendered by the name of their weekday, and the ones passed as
+// overdue, both with a warning.
+func dueAnnotation(due time.Time, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, due.Location())
+	days := int(due.Sub(today).Hours() / 24)
+
+	switch {
+	case days < 0:
+		return dueWarning + "overdue since " + due.Format(annotationDateLayout)
+	case days == 0:
+		return dueWarning + "due today"
+	case days < dueSoonDays:
+		return dueWarning + "due " + due.Format("Monday")
+	default:
+		return "due " + due.Format(annotationDateLayout)
+	}
+}
+
+// timelineAnnotation returns the annotation of the days the issue was started
+// and done at. The days within the last week, including today, are rendered
+// by the short name of their weekday, like "started Mon, done Thu".
+func timelineAnnotation(started time.Time, finished time.Time, now time.Time) string {
+	day := func(t time.Time) string {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, t.Location())
+		if !t.Before(today.AddDate(0, 0, 1-recentDays)) {
+			return t.Format("Mon")
+		}
+
+		return t.Format(annotationDateLayout)
+	}
+
+	var parts []string
+	if !started.IsZero() {
+		parts = append(parts, "started "+day(started))
+	}
+
+	if !finished.IsZero() {
+		parts = append(parts, "done "+day(finished))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Annotate sets the annotations of the issues, relative to the given time.
+func (i Issues) Annotate(annotations []Annotation, now time.Time) {
+	if len(annotations) == 0 {
+		return
+	}
+
+	for _, issues := range i {
+		for j := range issues {
+			issues[j].annotate(annotations, now)
+		}
+	}
+}
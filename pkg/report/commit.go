@@ -0,0 +1,100 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/git"
+)
+
+// Commit represents a commit in the sprint update.
+type Commit struct {
+	// Repository is the name of the repository, like "example/reports".
+	Repository string
+	Hash       string
+	Subject    string
+	AuthoredAt time.Time
+}
+
+// ShortHash returns the abbreviated hash of the commit.
+func (c *Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+
+	return c.Hash
+}
+
+// CommitGroup lists the commits referencing the same Jira issue.
+type CommitGroup struct {
+	// Key is the key of the issue, like "SE-101". It is empty for the group
+	// of the commits referencing no issue.
+	Key string
+	URL string
+	// Summary is the summary of the issue, if the issue is listed in the
+	// update.
+	Summary string
+	Commits []Commit
+}
+
+// SetCommits groups the commits by the first Jira issue key found in their
+// subject, or else in the name of their branch, regardless of its case. The
+// groups follow the order of their newest commits, and the commits
+// referencing no issue are listed last.
+func (u *Update) SetCommits(serverURL string, commits []git.Commit) {
+	var groups []CommitGroup
+	var unlinked []Commit
+	indexes := make(map[string]int)
+
+	for i := range commits {
+		commit := Commit{
+			Repository: commits[i].Repository,
+			Hash:       commits[i].Hash,
+			Subject:    commits[i].Subject,
+			AuthoredAt: commits[i].AuthoredAt,
+		}
+
+		key := issueKeyPattern.FindString(commit.Subject)
+		if key == "" {
+			key = issueKeyPattern.FindString(strings.ToUpper(commits[i].Ref))
+		}
+
+		if key == "" {
+			unlinked = append(unlinked, commit)
+			continue
+		}
+
+		if index, ok := indexes[key]; ok {
+			groups[index].Commits = append(groups[index].Commits, commit)
+			continue
+		}
+
+		indexes[key] = len(groups)
+		groups = append(groups, CommitGroup{
+			Key:     key,
+			URL:     fmt.Sprintf("%s/browse/%s", serverURL, key),
+			Summary: u.issueSummary(key),
+			Commits: []Commit{commit},
+		})
+	}
+
+	if len(unlinked) > 0 {
+		groups = append(groups, CommitGroup{Commits: unlinked})
+	}
+
+	u.Commits = groups
+}
+
+// issueSummary returns the summary of the issue having the given key, or an
+// empty string if the issue is not listed in the update.
+func (u *Update) issueSummary(key string) string {
+	var summary string
+	for _, section := range u.sections() {
+		section.Update(key, func(issue *Issue) {
+			summary = issue.Summary
+		})
+	}
+
+	return summary
+}
@@ -0,0 +1,38 @@
+package report
+
+import "strings"
+
+// Collapsible reports whether the group is wrapped in a collapsible block,
+// like [details] on Discourse, when rendered in the format. The groups having
+// at most the threshold number of issues, and the groups rendered in the
+// formats the collapsible blocks are disabled for, are listed as they are.
+func (g *StatusGroup) Collapsible(format string) bool {
+	if g.Plain {
+		return false
+	}
+
+	for _, name := range g.plainFormats {
+		if strings.EqualFold(name, format) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setDetails sets whether the groups are expanded by default, and whether
+// they are listed without collapsible blocks, based on the details options
+// of the update.
+func (u *Update) setDetails(groups []StatusGroup) {
+	for i := range groups {
+		for _, name := range u.ExpandedGroups {
+			if strings.EqualFold(name, groups[i].Name) {
+				groups[i].Open = true
+				break
+			}
+		}
+
+		groups[i].Plain = len(groups[i].Issues) <= u.DetailsThreshold
+		groups[i].plainFormats = u.PlainFormats
+	}
+}
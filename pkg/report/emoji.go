@@ -0,0 +1,66 @@
+package report
+
+import "strings"
+
+// DefaultStatusEmoji is the emoji of the statuses not matching any of the
+// configured or the default emojis.
+const DefaultStatusEmoji = "🔹"
+
+// DefaultStatusColor is the color of the statuses not matching any of the
+// default colors.
+const DefaultStatusColor = "#57606a"
+
+// defaultStatusEmojis maps the words of the statuses to their default emojis
+// and colors. The words are matched in order, so the blocked statuses are
+// matched before the ones in progress, like "Blocked in progress".
+var defaultStatusEmojis = []struct {
+	words []string
+	emoji string
+	color string
+}{
+	{words: []string{"block", "hold", "wait"}, emoji: "⛔", color: "#cf222e"},
+	{words: []string{"done", "closed", "resolved", "complete", "released"}, emoji: "✅", color: "#1a7f37"},
+	{words: []string{"review"}, emoji: "👀", color: "#8250df"},
+	{words: []string{"test", "qa", "verif"}, emoji: "🧪", color: "#bc4c00"},
+	{words: []string{"progress", "doing", "develop"}, emoji: "🚧", color: "#0969da"},
+	{words: []string{"to do", "todo", "backlog", "open", "new", "selected"}, emoji: "📋", color: "#6e7781"},
+}
+
+// StatusEmoji returns the emoji of the status or display group. The emojis
+// are looked up by the name of the status case-insensitively, falling back
+// to the default emojis based on the words of the status, like "✅" for
+// "Done" or "🚧" for "In Progress".
+func StatusEmoji(status string, emojis map[string]string) string {
+	for name, emoji := range emojis {
+		if strings.EqualFold(name, status) {
+			return emoji
+		}
+	}
+
+	status = strings.ToLower(status)
+	for _, candidate := range defaultStatusEmojis {
+		for _, word := range candidate.words {
+			if strings.Contains(status, word) {
+				return candidate.emoji
+			}
+		}
+	}
+
+	return DefaultStatusEmoji
+}
+
+// StatusColor returns the color of the status or display group as a
+// hexadecimal RGB value, based on the words of the status like the default
+// emojis, like "#1a7f37" (green) for "Done".
+func StatusColor(status string) string {
+	status = strings.ToLower(status)
+	for _, candidate := range defaultStatusEmojis {
+		for _, word := range candidate.words {
+			if strings.Contains(status, word) {
+				return candidate.color
+			}
+		}
+	}
+
+	return DefaultStatusColor
+}
@@ -0,0 +1,488 @@
+package report
+
+import (
+	"math"
+	"time"
+)
+
+// exportDateLayout is the layout of the sprint dates of the exported updates.
+const exportDateLayout = "2006-01-02"
+
+// ExportedUpdate is the structured representation of the sprint update,
+// encoded by the json and yaml formats for other tools to consume. Unlike
+// Update, the issues are listed in the order they are rendered.
+type ExportedUpdate struct {
+	Title       string `json:"title" yaml:"title"`
+	Sprint      string `json:"sprint,omitempty" yaml:"sprint,omitempty"`
+	EndOfSprint bool   `json:"end_of_sprint" yaml:"end_of_sprint"`
+	// Summary is the narrative summary of the update, if the summary section
+	// is enabled.
+	Summary string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	// StartDate and EndDate are the dates of the sprint, like "2021-10-04".
+	// They are empty if unknown.
+	StartDate     string `json:"start_date,omitempty" yaml:"start_date,omitempty"`
+	EndDate       string `json:"end_date,omitempty" yaml:"end_date,omitempty"`
+	DaysRemaining int    `json:"days_remaining,omitempty" yaml:"days_remaining,omitempty"`
+	// Points is the story point totals. It is nil if the story points are
+	// not read.
+	Points *ExportedPoints `json:"points,omitempty" yaml:"points,omitempty"`
+	// Stats is the velocity of the sprint. It is nil if the sprint report
+	// is not read.
+	Stats *ExportedStats `json:"stats,omitempty" yaml:"stats,omitempty"`
+	// CycleTime is the cycle time of the done issues. It is nil if the
+	// timeline of the issues is not read.
+	CycleTime *ExportedCycleTime `json:"cycle_time,omitempty" yaml:"cycle_time,omitempty"`
+	// Timesheet is the summary of the hours logged in Tempo. It is nil if
+	// Tempo is not used.
+	Timesheet *ExportedTimesheet `json:"timesheet,omitempty" yaml:"timesheet,omitempty"`
+	// Themes lists the epics touched by the issues, if the themes section is
+	// enabled.
+	Themes []ExportedTheme `json:"themes,omitempty" yaml:"themes,omitempty"`
+	// Groups lists the issues grouped like in the rendered update.
+	Groups []ExportedGroup `json:"groups,omitempty" yaml:"groups,omitempty"`
+	// Members lists the issues per team member in team mode.
+	Members []ExportedMember `json:"members,omitempty" yaml:"members,omitempty"`
+	// Projects lists the issues per project, if the worked on section is
+	// split by project.
+	Projects        []ExportedProject     `json:"projects,omitempty" yaml:"projects,omitempty"`
+	Blocked         []ExportedIssue       `json:"blocked,omitempty" yaml:"blocked,omitempty"`
+	Spillovers      []ExportedIssue       `json:"spillovers,omitempty" yaml:"spillovers,omitempty"`
+	CarriedOver     []ExportedIssue       `json:"carried_over,omitempty" yaml:"carried_over,omitempty"`
+	CarriedOverFrom string                `json:"carried_over_from,omitempty" yaml:"carried_over_from,omitempty"`
+	PullRequests    []ExportedPullRequest `json:"pull_requests,omitempty" yaml:"pull_requests,omitempty"`
+	Commits         []ExportedCommit      `json:"commits,omitempty" yaml:"commits,omitempty"`
+	Kudos           []string              `json:"kudos,omitempty" yaml:"kudos,omitempty"`
+	SuggestedKudos  []ExportedKudos       `json:"suggested_kudos,omitempty" yaml:"suggested_kudos,omitempty"`
+	TimeOff         string                `json:"time_off,omitempty" yaml:"time_off,omitempty"`
+	// Provenance describes how the update was generated. It is nil if the
+	// provenance footer is not rendered.
+	Provenance *ExportedProvenance `json:"provenance,omitempty" yaml:"provenance,omitempty"`
+	// Dependencies lists the links of the issues to the issues of other
+	// projects, if the dependencies section is enabled.
+	Dependencies []ExportedDependency `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+}
+
+// ExportedPoints is the story point totals of the exported update.
+type ExportedPoints struct {
+	Committed float64 `json:"committed" yaml:"committed"`
+	Done      float64 `json:"done" yaml:"done"`
+}
+
+// ExportedStats is the velocity of the sprint of the exported update.
+type ExportedStats struct {
+	Committed            float64 `json:"committed" yaml:"committed"`
+	Completed            float64 `json:"completed" yaml:"completed"`
+	CompletionPercentage float64 `json:"completion_percentage" yaml:"completion_percentage"`
+	CompletedIssues      int     `json:"completed_issues" yaml:"completed_issues"`
+	NotCompletedIssues   int     `json:"not_completed_issues" yaml:"not_completed_issues"`
+	RemovedIssues        int     `json:"removed_issues" yaml:"removed_issues"`
+	AddedIssues          int     `json:"added_issues" yaml:"added_issues"`
+}
+
+// ExportedCycleTime is the cycle time of the done issues of the exported
+// update.
+type ExportedCycleTime struct {
+	Issues      int     `json:"issues" yaml:"issues"`
+	AverageDays float64 `json:"average_days" yaml:"average_days"`
+	MedianDays  float64 `json:"median_days" yaml:"median_days"`
+}
+
+// ExportedProvenance describes how the exported update was generated.
+type ExportedProvenance struct {
+	Tool      string `json:"tool" yaml:"tool"`
+	Generated string `json:"generated" yaml:"generated"`
+	SprintID  string `json:"sprint_id,omitempty" yaml:"sprint_id,omitempty"`
+	QueryHash string `json:"query_hash" yaml:"query_hash"`
+}
+
+// ExportedTimesheet is the summary of the hours logged in Tempo of the
+// exported update.
+type ExportedTimesheet struct {
+	TotalHours float64         `json:"total_hours" yaml:"total_hours"`
+	Approved   bool            `json:"approved" yaml:"approved"`
+	Unlogged   []ExportedIssue `json:"unlogged,omitempty" yaml:"unlogged,omitempty"`
+}
+
+// ExportedTheme is an epic touched by the issues of the exported update.
+type ExportedTheme struct {
+	Key            string  `json:"key,omitempty" yaml:"key,omitempty"`
+	Name           string  `json:"name" yaml:"name"`
+	URL            string  `json:"url,omitempty" yaml:"url,omitempty"`
+	Issues         int     `json:"issues" yaml:"issues"`
+	Done           int     `json:"done" yaml:"done"`
+	Total          int     `json:"total" yaml:"total"`
+	DonePercentage float64 `json:"done_percentage" yaml:"done_percentage"`
+}
+
+// ExportedGroup is a group of issues of the exported update.
+type ExportedGroup struct {
+	Name string `json:"name" yaml:"name"`
+	// Status is empty if the issues are grouped by something other than
+	// their status.
+	Status      string          `json:"status,omitempty" yaml:"status,omitempty"`
+	StoryPoints float64         `json:"story_points,omitempty" yaml:"story_points,omitempty"`
+	Issues      []ExportedIssue `json:"issues" yaml:"issues"`
+}
+
+// ExportedMember is a team member of the exported update.
+type ExportedMember struct {
+	Name   string          `json:"name" yaml:"name"`
+	Groups []ExportedGroup `json:"groups" yaml:"groups"`
+}
+
+// ExportedProject is a project of the exported update.
+type ExportedProject struct {
+	Key    string `json:"key,omitempty" yaml:"key,omitempty"`
+	Name   string `json:"name" yaml:"name"`
+	Issues int    `json:"issues" yaml:"issues"`
+	// Points is the story point totals of the project. It is nil if the
+	// story points are not read.
+	Points *ExportedPoints `json:"points,omitempty" yaml:"points,omitempty"`
+	Groups []ExportedGroup `json:"groups" yaml:"groups"`
+}
+
+// ExportedIssue is an issue of the exported update.
+type ExportedIssue struct {
+	Key            string   `json:"key" yaml:"key"`
+	Summary        string   `json:"summary" yaml:"summary"`
+	URL            string   `json:"url" yaml:"url"`
+	Status         string   `json:"status" yaml:"status"`
+	StatusCategory string   `json:"status_category,omitempty" yaml:"status_category,omitempty"`
+	Type           string   `json:"type,omitempty" yaml:"type,omitempty"`
+	Done           bool     `json:"done" yaml:"done"`
+	Assignee       string   `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	BlockedBy      []string `json:"blocked_by,omitempty" yaml:"blocked_by,omitempty"`
+	Flagged        bool     `json:"flagged,omitempty" yaml:"flagged,omitempty"`
+	BlockedReason  string   `json:"blocked_reason,omitempty" yaml:"blocked_reason,omitempty"`
+	StoryPoints    float64  `json:"story_points,omitempty" yaml:"story_points,omitempty"`
+	HoursSpent     float64  `json:"hours_spent,omitempty" yaml:"hours_spent,omitempty"`
+	Change         Change   `json:"change,omitempty" yaml:"change,omitempty"`
+	PreviousStatus string   `json:"previous_status,omitempty" yaml:"previous_status,omitempty"`
+	Note           string   `json:"note,omitempty" yaml:"note,omitempty"`
+	Priority       string   `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Resolved and Due are the resolution and the due dates of the issue,
+	// like "2021-10-04". They are empty if unknown.
+	Resolved string `json:"resolved,omitempty" yaml:"resolved,omitempty"`
+	Due      string `json:"due,omitempty" yaml:"due,omitempty"`
+	// Started and Finished are the dates the issue was started and done at,
+	// read from its changelog. They are empty if unknown.
+	Started  string   `json:"started,omitempty" yaml:"started,omitempty"`
+	Finished string   `json:"finished,omitempty" yaml:"finished,omitempty"`
+	Epic     string   `json:"epic,omitempty" yaml:"epic,omitempty"`
+	Project  string   `json:"project,omitempty" yaml:"project,omitempty"`
+	Labels   []string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	// Components lists the names of the components of the issue.
+	Components []string `json:"components,omitempty" yaml:"components,omitempty"`
+	Parent     string   `json:"parent,omitempty" yaml:"parent,omitempty"`
+	// Links lists the issues linked to the issue.
+	Links []ExportedLink `json:"links,omitempty" yaml:"links,omitempty"`
+	// Subtasks lists the subtasks nested under the issue.
+	Subtasks []ExportedIssue `json:"subtasks,omitempty" yaml:"subtasks,omitempty"`
+	// SubtasksDone and SubtasksTotal are the subtask counts rolled up into
+	// the issue.
+	SubtasksDone  int `json:"subtasks_done,omitempty" yaml:"subtasks_done,omitempty"`
+	SubtasksTotal int `json:"subtasks_total,omitempty" yaml:"subtasks_total,omitempty"`
+	// SpilloverReason is the reason the issue spilled over.
+	SpilloverReason string `json:"spillover_reason,omitempty" yaml:"spillover_reason,omitempty"`
+}
+
+// ExportedLink is an issue linked to an issue of the exported update.
+type ExportedLink struct {
+	Relation string `json:"relation" yaml:"relation"`
+	Key      string `json:"key" yaml:"key"`
+	Summary  string `json:"summary,omitempty" yaml:"summary,omitempty"`
+	URL      string `json:"url" yaml:"url"`
+	Status   string `json:"status,omitempty" yaml:"status,omitempty"`
+	Done     bool   `json:"done" yaml:"done"`
+}
+
+// ExportedDependency is a link of an issue of the exported update to an
+// issue of another project.
+type ExportedDependency struct {
+	Key     string       `json:"key" yaml:"key"`
+	Summary string       `json:"summary" yaml:"summary"`
+	URL     string       `json:"url" yaml:"url"`
+	Link    ExportedLink `json:"link" yaml:"link"`
+}
+
+// ExportedPullRequest is a pull request of the exported update.
+type ExportedPullRequest struct {
+	Repository string   `json:"repository" yaml:"repository"`
+	Number     int      `json:"number" yaml:"number"`
+	Title      string   `json:"title" yaml:"title"`
+	URL        string   `json:"url" yaml:"url"`
+	Merged     bool     `json:"merged" yaml:"merged"`
+	Issues     []string `json:"issues,omitempty" yaml:"issues,omitempty"`
+}
+
+// ExportedCommit is a commit of the exported update.
+type ExportedCommit struct {
+	Repository string `json:"repository" yaml:"repository"`
+	Hash       string `json:"hash" yaml:"hash"`
+	Subject    string `json:"subject" yaml:"subject"`
+	// AuthoredAt is the time the commit was authored, in RFC 3339 format.
+	AuthoredAt string `json:"authored_at" yaml:"authored_at"`
+	// Issue is the key of the issue the commit references, if any.
+	Issue string `json:"issue,omitempty" yaml:"issue,omitempty"`
+}
+
+// ExportedKudos is a kudos suggestion of the exported update.
+type ExportedKudos struct {
+	Name      string   `json:"name" yaml:"name"`
+	Commented []string `json:"commented,omitempty" yaml:"commented,omitempty"`
+	Unblocked []string `json:"unblocked,omitempty" yaml:"unblocked,omitempty"`
+}
+
+// Export returns the structured representation of the update.
+func (u *Update) Export() *ExportedUpdate {
+	exported := &ExportedUpdate{
+		Title:           u.Title,
+		Sprint:          u.Sprint,
+		EndOfSprint:     u.EndOfSprint,
+		Summary:         u.Summary,
+		StartDate:       exportDate(u.StartDate),
+		EndDate:         exportDate(u.EndDate),
+		DaysRemaining:   u.DaysRemaining,
+		Blocked:         exportList(u.Blocked.Groups(u.StatusOrder)),
+		Spillovers:      exportList(u.Spillovers.Groups(u.StatusOrder)),
+		CarriedOver:     exportList(u.CarriedOver.Groups(u.StatusOrder)),
+		CarriedOverFrom: u.CarriedOverFrom,
+		Kudos:           u.Kudos,
+		TimeOff:         u.TimeOff,
+	}
+
+	if u.StoryPoints {
+		exported.Points = &ExportedPoints{Committed: u.CommittedPoints(), Done: u.DonePoints()}
+	}
+
+	if u.Stats != nil {
+		exported.Stats = &ExportedStats{
+			Committed:            u.Stats.CommittedPoints,
+			Completed:            u.Stats.CompletedPoints,
+			CompletionPercentage: u.Stats.CompletionPercentage(),
+			CompletedIssues:      u.Stats.CompletedIssues,
+			NotCompletedIssues:   u.Stats.NotCompletedIssues,
+			RemovedIssues:        u.Stats.RemovedIssues,
+			AddedIssues:          u.Stats.AddedIssues,
+		}
+	}
+
+	if u.CycleTime != nil {
+		exported.CycleTime = &ExportedCycleTime{
+			Issues:      u.CycleTime.Issues,
+			AverageDays: u.CycleTime.AverageDays(),
+			MedianDays:  u.CycleTime.MedianDays(),
+		}
+	}
+
+	if u.Provenance != nil {
+		exported.Provenance = &ExportedProvenance{
+			Tool:      u.Provenance.Tool,
+			Generated: u.Provenance.Generated.Format(ProvenanceTimeLayout),
+			SprintID:  u.Provenance.SprintID,
+			QueryHash: u.Provenance.QueryHash,
+		}
+	}
+
+	if u.Timesheet != nil {
+		exported.Timesheet = &ExportedTimesheet{
+			TotalHours: math.Round(u.Timesheet.Total.Hours()*10) / 10,
+			Approved:   u.Timesheet.Approved,
+			Unlogged:   exportIssues(u.Timesheet.Unlogged),
+		}
+	}
+
+	for i := range u.Themes {
+		exported.Themes = append(exported.Themes, ExportedTheme{
+			Key:            u.Themes[i].Key,
+			Name:           u.Themes[i].Name,
+			URL:            u.Themes[i].URL,
+			Issues:         u.Themes[i].Issues,
+			Done:           u.Themes[i].Done,
+			Total:          u.Themes[i].Total,
+			DonePercentage: u.Themes[i].DonePercentage(),
+		})
+	}
+
+	if len(u.Members) == 0 {
+		exported.Groups = exportGroups(u.AllGroups(u.Issues))
+	}
+
+	for _, member := range u.Members {
+		exported.Members = append(exported.Members, ExportedMember{
+			Name:   member.Name,
+			Groups: exportGroups(u.AllGroups(member.Issues)),
+		})
+	}
+
+	for i := range u.Projects {
+		exportedProject := ExportedProject{
+			Key:    u.Projects[i].Key,
+			Name:   u.Projects[i].Name,
+			Issues: u.Projects[i].Count(),
+			Groups: exportGroups(u.AllGroups(u.Projects[i].Issues)),
+		}
+
+		if u.StoryPoints {
+			exportedProject.Points = &ExportedPoints{Committed: u.Projects[i].CommittedPoints(), Done: u.Projects[i].DonePoints()}
+		}
+
+		exported.Projects = append(exported.Projects, exportedProject)
+	}
+
+	for _, pullRequest := range u.PullRequests {
+		exportedPullRequest := ExportedPullRequest{
+			Repository: pullRequest.Repository,
+			Number:     pullRequest.Number,
+			Title:      pullRequest.Title,
+			URL:        pullRequest.URL,
+			Merged:     pullRequest.Merged,
+		}
+
+		for _, link := range pullRequest.Issues {
+			exportedPullRequest.Issues = append(exportedPullRequest.Issues, link.Key)
+		}
+
+		exported.PullRequests = append(exported.PullRequests, exportedPullRequest)
+	}
+
+	for _, dependency := range u.Dependencies {
+		exported.Dependencies = append(exported.Dependencies, ExportedDependency{
+			Key:     dependency.Key,
+			Summary: dependency.Summary,
+			URL:     dependency.URL,
+			Link:    exportLink(dependency.Link),
+		})
+	}
+
+	for _, group := range u.Commits {
+		for _, commit := range group.Commits {
+			exported.Commits = append(exported.Commits, ExportedCommit{
+				Repository: commit.Repository,
+				Hash:       commit.Hash,
+				Subject:    commit.Subject,
+				AuthoredAt: commit.AuthoredAt.Format(time.RFC3339),
+				Issue:      group.Key,
+			})
+		}
+	}
+
+	for _, suggestion := range u.SuggestedKudos {
+		exported.SuggestedKudos = append(exported.SuggestedKudos, ExportedKudos(suggestion))
+	}
+
+	return exported
+}
+
+// exportDate formats the date of the exported update. Zero dates are
+// exported as empty strings.
+func exportDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format(exportDateLayout)
+}
+
+// exportGroups returns the exported issue groups.
+func exportGroups(groups []StatusGroup) []ExportedGroup {
+	exported := make([]ExportedGroup, 0, len(groups))
+
+	for _, group := range groups {
+		exported = append(exported, ExportedGroup{
+			Name:        group.Name,
+			Status:      group.Status,
+			StoryPoints: group.StoryPoints,
+			Issues:      exportIssues(group.Issues),
+		})
+	}
+
+	return exported
+}
+
+// exportList returns the issues of the groups as a single list, for the
+// sections rendered without group headers.
+func exportList(groups []StatusGroup) []ExportedIssue {
+	var exported []ExportedIssue
+
+	for _, group := range groups {
+		exported = append(exported, exportIssues(group.Issues)...)
+	}
+
+	return exported
+}
+
+// exportIssues returns the exported issues.
+func exportIssues(issues []Issue) []ExportedIssue {
+	exported := make([]ExportedIssue, 0, len(issues))
+
+	for _, issue := range issues {
+		exported = append(exported, ExportedIssue{
+			Key:             issue.Key,
+			Summary:         issue.Summary,
+			URL:             issue.URL,
+			Status:          issue.Status,
+			StatusCategory:  issue.StatusCategory,
+			Type:            issue.Type,
+			Done:            issue.Done,
+			Assignee:        issue.Assignee,
+			BlockedBy:       issue.BlockedBy,
+			Links:           exportLinks(issue.Links),
+			Flagged:         issue.Flagged,
+			BlockedReason:   issue.BlockedReason,
+			StoryPoints:     issue.StoryPoints,
+			HoursSpent:      math.Round(issue.TimeSpent.Hours()*10) / 10,
+			Change:          issue.Change,
+			PreviousStatus:  issue.PreviousStatus,
+			Note:            issue.Note,
+			Priority:        issue.Priority,
+			Resolved:        exportDate(issue.Resolved),
+			Due:             exportDate(issue.Due),
+			Started:         exportDate(issue.Started),
+			Finished:        exportDate(issue.Finished),
+			Epic:            issue.Epic,
+			Project:         issue.Project,
+			Labels:          issue.Labels,
+			Components:      issue.Components,
+			Parent:          issue.Parent,
+			Subtasks:        exportSubtasks(issue.Subtasks),
+			SubtasksDone:    issue.SubtasksDone,
+			SubtasksTotal:   issue.SubtasksTotal,
+			SpilloverReason: issue.SpilloverReason,
+		})
+	}
+
+	return exported
+}
+
+// exportLinks returns the exported linked issues.
+func exportLinks(links []LinkedIssue) []ExportedLink {
+	var exported []ExportedLink
+	for _, link := range links {
+		exported = append(exported, exportLink(link))
+	}
+
+	return exported
+}
+
+// exportLink returns the exported linked issue.
+func exportLink(link LinkedIssue) ExportedLink {
+	return ExportedLink{
+		Relation: link.Relation,
+		Key:      link.Key,
+		Summary:  link.Summary,
+		URL:      link.URL,
+		Status:   link.Status,
+		Done:     link.Done,
+	}
+}
+
+// exportSubtasks returns the exported subtasks, leaving out the empty list
+// of the issues without nested subtasks.
+func exportSubtasks(subtasks []Issue) []ExportedIssue {
+	if len(subtasks) == 0 {
+		return nil
+	}
+
+	return exportIssues(subtasks)
+}
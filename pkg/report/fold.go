@@ -0,0 +1,111 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FoldBy is the attribute choosing the issues listed in the groups having
+// more issues than their maximum, the rest of the issues being folded.
+type FoldBy string
+
+const (
+	// FoldByOrder lists the issues listed first in the group, which are the
+	// issues of the highest rank unless sorted otherwise.
+	FoldByOrder FoldBy = "order"
+	// FoldByPoints lists the issues of the most story points, in the order of
+	// the group.
+	FoldByPoints FoldBy = "points"
+)
+
+// everyGroup is the key of the maximum number of issues of the groups having
+// no maximum of their own.
+const everyGroup = "*"
+
+// ErrUnknownFoldBy is returned when the listed issues cannot be chosen by the
+// requested attribute.
+var ErrUnknownFoldBy = errors.New("unknown folding")
+
+// FoldBys returns the supported ways of choosing the listed issues.
+func FoldBys() []string {
+	return []string{string(FoldByOrder), string(FoldByPoints)}
+}
+
+// ValidateFoldBy checks that the way of choosing the listed issues is
+// supported. An empty value is the same as FoldByOrder.
+func ValidateFoldBy(by FoldBy) error {
+	switch by {
+	case "", FoldByOrder, FoldByPoints:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownFoldBy, by)
+	}
+}
+
+// maxItems returns the maximum number of issues listed in the group of the
+// given name, matched case-insensitively, or the maximum of every group if
+// the group has none. Zero means the issues are not limited.
+func (u *Update) maxItems(name string) int {
+	limit := 0
+	for group, max := range u.MaxItems {
+		switch {
+		case strings.EqualFold(group, name):
+			return max
+		case group == everyGroup:
+			limit = max
+		}
+	}
+
+	return limit
+}
+
+// fold limits the issues of the groups to their maximum number of issues,
+// folding the rest of the issues into a link to the Jira search listing
+// them.
+func (u *Update) fold(groups []StatusGroup) {
+	for i := range groups {
+		limit := u.maxItems(groups[i].Name)
+		if limit <= 0 || len(groups[i].Issues) <= limit {
+			continue
+		}
+
+		listed, folded := foldIssues(groups[i].Issues, limit, u.FoldBy)
+		groups[i].Issues = listed
+		groups[i].Folded = len(folded)
+		groups[i].FoldedText = u.T("…and %d more", len(folded))
+		groups[i].FoldedURL = searchURL(folded)
+	}
+}
+
+// foldIssues splits the issues into the given number of listed issues, chosen
+// by the attribute, and the folded issues. Both keep the order of the issues.
+func foldIssues(issues []Issue, limit int, by FoldBy) ([]Issue, []Issue) {
+	listedIndexes := make([]int, len(issues))
+	for i := range issues {
+		listedIndexes[i] = i
+	}
+
+	if by == FoldByPoints {
+		sort.SliceStable(listedIndexes, func(a, b int) bool {
+			return issues[listedIndexes[a]].StoryPoints > issues[listedIndexes[b]].StoryPoints
+		})
+	}
+
+	isListed := make(map[int]bool, limit)
+	for _, i := range listedIndexes[:limit] {
+		isListed[i] = true
+	}
+
+	var listed, folded []Issue
+	for i := range issues {
+		if isListed[i] {
+			listed = append(listed, issues[i])
+		} else {
+			folded = append(folded, issues[i])
+		}
+	}
+
+	return listed, folded
+}
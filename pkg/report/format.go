@@ -0,0 +1,19 @@
+package report
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// FormatPoints formats the story points without trailing zeros, like "2.5"
+// or "3".
+func FormatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+// FormatHours formats the duration in hours, rounded to one decimal place,
+// like "1.5h".
+func FormatHours(d time.Duration) string {
+	return strconv.FormatFloat(math.Round(d.Hours()*10)/10, 'f', -1, 64) + "h"
+}
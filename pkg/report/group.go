@@ -0,0 +1,217 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupBy is the attribute the issues are grouped by in the update.
+type GroupBy string
+
+const (
+	// GroupByStatus groups the issues by their status.
+	GroupByStatus GroupBy = "status"
+	// GroupByEpic groups the issues by their epic.
+	GroupByEpic GroupBy = "epic"
+	// GroupByProject groups the issues by their project.
+	GroupByProject GroupBy = "project"
+	// GroupByLabel groups the issues by their labels. Issues having multiple
+	// labels are listed under every label.
+	GroupByLabel GroupBy = "label"
+	// GroupByStatusCategory groups the issues by the category of their
+	// status, which is the same regardless of the workflow of their project.
+	GroupByStatusCategory GroupBy = "status-category"
+)
+
+const (
+	// StatusCategoryToDo is the name of the category of the statuses of the
+	// issues not started yet.
+	StatusCategoryToDo = "To Do"
+	// StatusCategoryInProgress is the name of the category of the statuses
+	// of the issues in progress.
+	StatusCategoryInProgress = "In Progress"
+	// StatusCategoryDone is the name of the category of the statuses of the
+	// done issues.
+	StatusCategoryDone = "Done"
+)
+
+const (
+	// noEpicGroup is the name of the group of the issues without an epic.
+	noEpicGroup = "No epic"
+	// noProjectGroup is the name of the group of the issues without a
+	// project, like the issues of snapshots taken by earlier versions.
+	noProjectGroup = "No project"
+	// noLabelsGroup is the name of the group of the issues without labels.
+	noLabelsGroup = "No labels"
+)
+
+// ErrUnknownGroupBy is returned when the issues cannot be grouped by the
+// requested attribute.
+var ErrUnknownGroupBy = errors.New("unknown grouping")
+
+// GroupBys returns the supported groupings.
+func GroupBys() []string {
+	return []string{string(GroupByStatus), string(GroupByEpic), string(GroupByProject), string(GroupByLabel), string(GroupByStatusCategory)}
+}
+
+// ValidateGroupBy checks that the grouping is supported. An empty grouping is
+// the same as GroupByStatus.
+func ValidateGroupBy(by GroupBy) error {
+	switch by {
+	case "", GroupByStatus, GroupByEpic, GroupByProject, GroupByLabel, GroupByStatusCategory:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownGroupBy, by)
+	}
+}
+
+// fallbackGroups lists the groups of the issues missing the attribute they are
+// grouped by, which are listed after the other groups.
+var fallbackGroups = []string{noEpicGroup, noProjectGroup, noLabelsGroup}
+
+// isFallbackGroup reports whether the group is one of the fallbackGroups.
+func isFallbackGroup(name string) bool {
+	for _, fallback := range fallbackGroups {
+		if name == fallback {
+			return true
+		}
+	}
+
+	return false
+}
+
+// groupNames returns the names of the groups the issue is listed under.
+func (i *Issue) groupNames(by GroupBy) []string {
+	switch by {
+	case GroupByEpic:
+		if i.Epic == "" {
+			return []string{noEpicGroup}
+		}
+		return []string{i.Epic}
+	case GroupByProject:
+		if i.Project == "" {
+			return []string{noProjectGroup}
+		}
+		return []string{i.Project}
+	case GroupByLabel:
+		if len(i.Labels) == 0 {
+			return []string{noLabelsGroup}
+		}
+		return i.Labels
+	case GroupByStatusCategory:
+		return []string{i.statusCategory()}
+	default:
+		return []string{i.Status}
+	}
+}
+
+// statusCategory returns the name of the category of the status of the issue.
+// If the category is unknown, like for the issues of snapshots taken by
+// earlier versions, the done issues are in the "Done" category, and the rest
+// in the "To Do" category.
+func (i *Issue) statusCategory() string {
+	switch {
+	case i.StatusCategory != "":
+		return i.StatusCategory
+	case i.Done:
+		return StatusCategoryDone
+	default:
+		return StatusCategoryToDo
+	}
+}
+
+// GroupsBy returns the issues grouped by the given attribute. When grouping
+// by status, it is the same as Groups. Otherwise, the groups are listed in
+// alphabetical order, followed by the group of the issues missing the
+// attribute, and the issues of a group are listed in the given status order.
+func (i Issues) GroupsBy(by GroupBy, order []string) []StatusGroup {
+	if by == "" || by == GroupByStatus {
+		return i.Groups(order)
+	}
+
+	grouped := make(map[string][]Issue)
+	for _, statusGroup := range i.Groups(order) {
+		for _, issue := range statusGroup.Issues {
+			for _, name := range issue.groupNames(by) {
+				grouped[name] = append(grouped[name], issue)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(grouped))
+	for name := range grouped {
+		if !isFallbackGroup(name) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(a, b int) bool {
+		return strings.ToLower(names[a]) < strings.ToLower(names[b])
+	})
+
+	for _, name := range fallbackGroups {
+		if _, ok := grouped[name]; ok {
+			names = append(names, name)
+		}
+	}
+
+	groups := make([]StatusGroup, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, StatusGroup{Name: name, Issues: grouped[name], StoryPoints: sumStoryPoints(grouped[name])})
+	}
+
+	return groups
+}
+
+// EpicKeys returns the keys of the epics of the issues, in alphabetical
+// order.
+func (i Issues) EpicKeys() []string {
+	listed := make(map[string]bool)
+
+	var keys []string
+	for _, issues := range i {
+		for _, issue := range issues {
+			if issue.EpicKey != "" && !listed[issue.EpicKey] {
+				listed[issue.EpicKey] = true
+				keys = append(keys, issue.EpicKey)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// ResolveEpics sets the epic of the issues using the given epic names keyed
+// by the epic keys. The subtasks without an epic inherit the epic of their
+// parent, if the parent is listed. The epics without a name are referred to
+// by their key.
+func (i Issues) ResolveEpics(names map[string]string) {
+	epicOf := make(map[string]string)
+	for _, issues := range i {
+		for _, issue := range issues {
+			epicOf[issue.Key] = issue.EpicKey
+		}
+	}
+
+	for status := range i {
+		for j := range i[status] {
+			issue := &i[status][j]
+
+			if issue.EpicKey == "" && issue.Parent != "" {
+				issue.EpicKey = epicOf[issue.Parent]
+			}
+
+			if issue.EpicKey == "" {
+				continue
+			}
+
+			if issue.Epic = names[issue.EpicKey]; issue.Epic == "" {
+				issue.Epic = issue.EpicKey
+			}
+		}
+	}
+}
@@ -0,0 +1,661 @@
+// Package report builds the data model of sprint updates from Jira issues.
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"gabor-boros/sprint-update/pkg/jira"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// Issue represents an item in the sprint update.
+type Issue struct {
+	Key     string
+	Summary string
+	URL     string
+	Status  string
+	// Type is the name of the issue type, like "Bug" or "Sub-task".
+	Type string
+	// Assignee is the display name of the issue's assignee.
+	Assignee string
+	// BlockedBy lists the keys of the issues blocking this issue.
+	BlockedBy []string
+	// Links lists the issues linked to this issue, like the issues it
+	// blocks or depends on.
+	Links []LinkedIssue
+	// Flagged indicates that the issue is flagged as impeded in Jira.
+	Flagged bool
+	// BlockedReason is the reason the issue is blocked, taken from the
+	// comment added when flagging the issue.
+	BlockedReason string
+	// Done indicates that the issue is in a status of the "done" category.
+	Done bool
+	// StatusCategory is the name of the category of the status of the issue,
+	// like "In Progress". It is empty if unknown.
+	StatusCategory string
+	// Sprints lists the sprints the issue was part of.
+	Sprints []jira.Sprint
+	// StoryPoints is the estimation of the issue.
+	StoryPoints float64
+	// TimeSpent is the time logged on the issue within the sprint in worklog
+	// mode.
+	TimeSpent time.Duration
+	// Change is the change of the issue since the previous update in diff
+	// mode. It is empty if the issue did not change.
+	Change Change
+	// PreviousStatus is the status of the issue in the previous update, if
+	// the issue moved to another status since then.
+	PreviousStatus string
+	// Note is the progress note of the issue, taken from its comments.
+	Note string
+	// SpilloverReason is the reason the issue spilled over, taken from the
+	// spillover reason field or the last comment of the issue.
+	SpilloverReason string
+	// Parent is the key of the parent issue of subtasks.
+	Parent string
+	// Subtasks lists the subtasks of the issue, if they are nested under
+	// their parents.
+	Subtasks []Issue
+	// SubtasksTotal is the number of the subtasks of the issue, if their
+	// status is rolled up into their parents.
+	SubtasksTotal int
+	// SubtasksDone is the number of the done subtasks of the issue, if their
+	// status is rolled up into their parents.
+	SubtasksDone int
+	// EpicKey is the key of the epic of the issue.
+	EpicKey string
+	// Epic is the name of the epic of the issue, if the issues are grouped by
+	// epic.
+	Epic string
+	// Project is the name of the project of the issue.
+	Project string
+	// ProjectKey is the key of the project of the issue.
+	ProjectKey string
+	// Labels lists the labels of the issue.
+	Labels []string
+	// Components lists the names of the components of the issue.
+	Components []string
+	// Priority is the name of the priority of the issue.
+	Priority string
+	// Resolved is the time the issue was resolved at. It is zero if the
+	// issue is not resolved.
+	Resolved time.Time
+	// Due is the due date of the issue. It is zero if the issue has no due
+	// date.
+	Due time.Time
+	// Started is the time the issue first entered a status of the "in
+	// progress" category, read from its changelog for the timeline
+	// annotation. It is zero if unknown.
+	Started time.Time
+	// Finished is the time the issue last entered a status of the "done"
+	// category, read from its changelog for the timeline annotation. It is
+	// zero if unknown.
+	Finished time.Time
+	// Updated is the time the issue was last updated at.
+	Updated time.Time
+	// Rank is the rank of the issue, which orders the issues on the board
+	// when compared lexicographically. It is empty if the rank is unknown.
+	Rank string
+	// Annotations lists the configured annotations of the issue, like
+	// "resolved Mar 11" or "⚠ due Friday", rendered on the issue lines.
+	Annotations []string
+
+	// subtasksTotal and subtasksDone are the subtask counts read from Jira,
+	// which are exposed only in rollup mode.
+	subtasksTotal int
+	subtasksDone  int
+}
+
+// Change is the change of an issue since the previous update.
+type Change string
+
+const (
+	// ChangeNew marks the issues not listed in the previous update.
+	ChangeNew Change = "NEW"
+	// ChangeMoved marks the issues moved to another status.
+	ChangeMoved Change = "MOVED"
+	// ChangeDone marks the issues done since the previous update.
+	ChangeDone Change = "DONE"
+)
+
+// ChangeNote describes the change of the issue since the previous update,
+// like "MOVED from In Progress". It is empty if the issue did not change.
+func (i *Issue) ChangeNote() string {
+	switch i.Change {
+	case ChangeMoved:
+		return "MOVED from " + i.PreviousStatus
+	case ChangeDone:
+		return "DONE since last update"
+	default:
+		return string(i.Change)
+	}
+}
+
+// Diff annotates the issues with their changes compared to the issues of the
+// previous update.
+func (i Issues) Diff(previous Issues) {
+	previousIssues := make(map[string]Issue)
+	for _, statusIssues := range previous {
+		for _, issue := range statusIssues {
+			previousIssues[issue.Key] = issue
+		}
+	}
+
+	for _, statusIssues := range i {
+		for j := range statusIssues {
+			issue := &statusIssues[j]
+			previousIssue, ok := previousIssues[issue.Key]
+
+			switch {
+			case !ok:
+				issue.Change = ChangeNew
+			case issue.Done && !previousIssue.Done:
+				issue.Change = ChangeDone
+			case !strings.EqualFold(issue.Status, previousIssue.Status):
+				issue.Change = ChangeMoved
+				issue.PreviousStatus = previousIssue.Status
+			}
+		}
+	}
+}
+
+// CustomFields holds the IDs of the Jira custom fields read from the issues.
+// Empty IDs are not read.
+type CustomFields struct {
+	// Sprint is the ID of the Jira Agile sprint field.
+	Sprint string
+	// StoryPoints is the ID of the story points field.
+	StoryPoints string
+	// EpicLink is the ID of the Jira Agile epic link field.
+	EpicLink string
+	// Flagged is the ID of the Jira Software flagged field.
+	Flagged string
+	// Rank is the ID of the Jira Software rank field.
+	Rank string
+	// SpilloverReason is the ID of the field holding the reason the issue
+	// spilled over.
+	SpilloverReason string
+}
+
+// IDs returns the non-empty custom field IDs.
+func (f *CustomFields) IDs() []string {
+	var ids []string
+
+	if f.Sprint != "" {
+		ids = append(ids, f.Sprint)
+	}
+
+	if f.StoryPoints != "" {
+		ids = append(ids, f.StoryPoints)
+	}
+
+	if f.EpicLink != "" {
+		ids = append(ids, f.EpicLink)
+	}
+
+	if f.Flagged != "" {
+		ids = append(ids, f.Flagged)
+	}
+
+	if f.Rank != "" {
+		ids = append(ids, f.Rank)
+	}
+
+	if f.SpilloverReason != "" {
+		ids = append(ids, f.SpilloverReason)
+	}
+
+	return ids
+}
+
+// spilloverReasonLength is the maximum length of the spillover reasons, so
+// long reasons do not take over the update.
+const spilloverReasonLength = 280
+
+// blockedByLink is the inward description of the issue link used for
+// marking an issue as blocked by another one.
+const blockedByLink = "is blocked by"
+
+// statusCategoryName returns the name of the Jira status category of the
+// given key, or an empty string if the category is unknown.
+func statusCategoryName(key string) string {
+	switch key {
+	case gojira.StatusCategoryToDo:
+		return StatusCategoryToDo
+	case gojira.StatusCategoryInProgress:
+		return StatusCategoryInProgress
+	case gojira.StatusCategoryComplete:
+		return StatusCategoryDone
+	default:
+		return ""
+	}
+}
+
+// NewIssue returns a new Issue from the given Jira issue. The custom fields
+// are read using the given field IDs.
+func NewIssue(serverURL string, issue *gojira.Issue, fields CustomFields) Issue {
+	var assignee string
+	if issue.Fields.Assignee != nil {
+		assignee = issue.Fields.Assignee.DisplayName
+	}
+
+	transformedIssue := Issue{
+		Key:       issue.Key,
+		Summary:   issue.Fields.Summary,
+		URL:       fmt.Sprintf("%s/browse/%s", serverURL, issue.Key),
+		Status:    issue.Fields.Status.Name,
+		Type:      issue.Fields.Type.Name,
+		Assignee:  assignee,
+		BlockedBy: blockerKeys(issue),
+		Links:     linkedIssues(serverURL, issue),
+		Done:      issue.Fields.Status.StatusCategory.Key == gojira.StatusCategoryComplete,
+		Labels:    issue.Fields.Labels,
+	}
+
+	transformedIssue.StatusCategory = statusCategoryName(issue.Fields.Status.StatusCategory.Key)
+
+	if issue.Fields.Priority != nil {
+		transformedIssue.Priority = issue.Fields.Priority.Name
+	}
+
+	for _, component := range issue.Fields.Components {
+		if component != nil {
+			transformedIssue.Components = append(transformedIssue.Components, component.Name)
+		}
+	}
+
+	transformedIssue.Resolved = time.Time(issue.Fields.Resolutiondate)
+	transformedIssue.Updated = time.Time(issue.Fields.Updated)
+	transformedIssue.Due = time.Time(issue.Fields.Duedate)
+
+	transformedIssue.ProjectKey = issue.Fields.Project.Key
+
+	if issue.Fields.Project.Name != "" {
+		transformedIssue.Project = issue.Fields.Project.Name
+	} else {
+		transformedIssue.Project = issue.Fields.Project.Key
+	}
+
+	if issue.Fields.Parent != nil {
+		transformedIssue.Parent = issue.Fields.Parent.Key
+
+		// The parent of the issues other than subtasks is their epic in
+		// team-managed projects and on Jira Cloud.
+		if !issue.Fields.Type.Subtask {
+			transformedIssue.EpicKey = issue.Fields.Parent.Key
+		}
+	}
+
+	if fields.EpicLink != "" {
+		if epicKey, ok := issue.Fields.Unknowns[fields.EpicLink].(string); ok && epicKey != "" {
+			transformedIssue.EpicKey = epicKey
+		}
+	}
+
+	for _, subtask := range issue.Fields.Subtasks {
+		transformedIssue.subtasksTotal++
+
+		if subtask.Fields.Status != nil && subtask.Fields.Status.StatusCategory.Key == gojira.StatusCategoryComplete {
+			transformedIssue.subtasksDone++
+		}
+	}
+
+	if fields.Sprint != "" {
+		transformedIssue.Sprints = jira.ParseSprints(issue.Fields.Unknowns[fields.Sprint])
+	}
+
+	if fields.StoryPoints != "" {
+		transformedIssue.StoryPoints, _ = issue.Fields.Unknowns[fields.StoryPoints].(float64)
+	}
+
+	if fields.Flagged != "" {
+		transformedIssue.Flagged = jira.IsFlagged(issue.Fields.Unknowns[fields.Flagged])
+	}
+
+	if fields.Rank != "" {
+		transformedIssue.Rank, _ = issue.Fields.Unknowns[fields.Rank].(string)
+	}
+
+	if fields.SpilloverReason != "" {
+		transformedIssue.SpilloverReason = Truncate(jira.FieldText(issue.Fields.Unknowns[fields.SpilloverReason]), spilloverReasonLength)
+	}
+
+	return transformedIssue
+}
+
+// blockerKeys returns the keys of the inward "is blocked by" issue links.
+func blockerKeys(issue *gojira.Issue) []string {
+	var keys []string
+
+	for _, link := range issue.Fields.IssueLinks {
+		if link.InwardIssue != nil && strings.EqualFold(link.Type.Inward, blockedByLink) {
+			keys = append(keys, link.InwardIssue.Key)
+		}
+	}
+
+	return keys
+}
+
+// DoneBlockerKeys returns the keys of the issues blocking the given issue that
+// are already in a status of the "done" category.
+func DoneBlockerKeys(issue *gojira.Issue) []string {
+	var keys []string
+
+	for _, link := range issue.Fields.IssueLinks {
+		if link.InwardIssue == nil || !strings.EqualFold(link.Type.Inward, blockedByLink) {
+			continue
+		}
+
+		blocker := link.InwardIssue.Fields
+		if blocker != nil && blocker.Status != nil && blocker.Status.StatusCategory.Key == gojira.StatusCategoryComplete {
+			keys = append(keys, link.InwardIssue.Key)
+		}
+	}
+
+	return keys
+}
+
+// IsBlocked reports whether the issue is blocked, either by having a blocker
+// issue link, being flagged, having one of the given blocked labels, or being
+// in one of the given blocked statuses.
+func (i *Issue) IsBlocked(blockedStatuses []string, blockedLabels []string) bool {
+	if len(i.BlockedBy) > 0 || i.Flagged {
+		return true
+	}
+
+	for _, blockedLabel := range blockedLabels {
+		for _, label := range i.Labels {
+			if strings.EqualFold(label, blockedLabel) {
+				return true
+			}
+		}
+	}
+
+	for _, status := range blockedStatuses {
+		if strings.EqualFold(i.Status, status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsCarriedOver reports whether the issue was part of a closed sprint other
+// than the given ones, hence it was carried over from a previous sprint.
+// Consolidated updates covering multiple sprints pass every covered sprint.
+func (i *Issue) IsCarriedOver(sprintNames ...string) bool {
+	covered := make(map[string]bool, len(sprintNames))
+	for _, name := range sprintNames {
+		covered[name] = true
+	}
+
+	for _, s := range i.Sprints {
+		if s.IsClosed() && !covered[s.Name] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSpillover reports whether the issue is a spillover of the given sprint,
+// either by being carried over from a previous sprint or by remaining
+// unresolved at the end of the sprint.
+func (i *Issue) IsSpillover(sprintName string, endOfSprint bool) bool {
+	return i.IsCarriedOver(sprintName) || (endOfSprint && !i.Done)
+}
+
+// Issues is the grouping of multiple Issue by their status.
+type Issues map[string][]Issue
+
+// NewIssues returns Issues grouped by issue status.
+func NewIssues(serverURL string, issues []gojira.Issue, fields CustomFields) Issues {
+	groupedIssues := make(Issues)
+
+	for _, issue := range issues {
+		transformedIssue := NewIssue(serverURL, &issue, fields)
+		groupedIssues[issue.Fields.Status.Name] = append(groupedIssues[issue.Fields.Status.Name], transformedIssue)
+	}
+
+	return groupedIssues
+}
+
+// Filter returns the issues matching the given predicate grouped by issue
+// status.
+func (i Issues) Filter(predicate func(issue *Issue) bool) Issues {
+	filteredIssues := make(Issues)
+
+	for status, issues := range i {
+		for j := range issues {
+			if predicate(&issues[j]) {
+				filteredIssues[status] = append(filteredIssues[status], issues[j])
+			}
+		}
+	}
+
+	return filteredIssues
+}
+
+// Blocked returns the blocked issues grouped by issue status.
+func (i Issues) Blocked(blockedStatuses []string, blockedLabels []string) Issues {
+	return i.Filter(func(issue *Issue) bool {
+		return issue.IsBlocked(blockedStatuses, blockedLabels)
+	})
+}
+
+// Spillovers returns the spillover issues of the given sprint grouped by
+// issue status.
+func (i Issues) Spillovers(sprintName string, endOfSprint bool) Issues {
+	return i.Filter(func(issue *Issue) bool {
+		return issue.IsSpillover(sprintName, endOfSprint)
+	})
+}
+
+// Truncate shortens the summaries of the issues to the given number of
+// characters. If the length is zero, the summaries are left intact.
+func (i Issues) Truncate(length int) {
+	for _, issues := range i {
+		for j := range issues {
+			issues[j].Summary = Truncate(issues[j].Summary, length)
+
+			for k := range issues[j].Subtasks {
+				issues[j].Subtasks[k].Summary = Truncate(issues[j].Subtasks[k].Summary, length)
+			}
+		}
+	}
+}
+
+// Remove returns the issues without the issue having the given key.
+func (i Issues) Remove(key string) Issues {
+	return i.Filter(func(issue *Issue) bool {
+		return issue.Key != key
+	})
+}
+
+// Update calls fn for every issue having the given key, so the issue can be
+// modified in place.
+func (i Issues) Update(key string, fn func(issue *Issue)) {
+	for _, issues := range i {
+		for j := range issues {
+			if issues[j].Key == key {
+				fn(&issues[j])
+			}
+
+			for k := range issues[j].Subtasks {
+				if issues[j].Subtasks[k].Key == key {
+					fn(&issues[j].Subtasks[k])
+				}
+			}
+		}
+	}
+}
+
+// StoryPoints returns the total story points of the issues.
+func (i Issues) StoryPoints() float64 {
+	var total float64
+
+	for _, issues := range i {
+		total += sumStoryPoints(issues)
+	}
+
+	return total
+}
+
+// sumStoryPoints returns the total story points of the issues.
+func sumStoryPoints(issues []Issue) float64 {
+	var total float64
+
+	for _, issue := range issues {
+		total += issue.StoryPoints
+	}
+
+	return total
+}
+
+// StatusGroup is a group of issues having the same status, or the same epic,
+// project, or label.
+type StatusGroup struct {
+	// Name is the name of the group, which is the status of the issues, or
+	// their epic, project, or label, depending on the grouping.
+	Name string
+	// Status is the status of the issues. It is empty if the issues are
+	// grouped by something other than their status.
+	Status string
+	Issues []Issue
+	// StoryPoints is the total story points of the issues.
+	StoryPoints float64
+	// Emoji is the emoji of the status of the issues. It is empty if the
+	// issues are grouped by something other than their status.
+	Emoji string
+	// Open indicates that the collapsible block of the group is expanded by
+	// default.
+	Open bool
+	// Plain indicates that the group is small enough to be listed without a
+	// collapsible block.
+	Plain bool
+	// SearchURL is the URL of the Jira search listing every issue of the
+	// group, including the folded issues and the subtasks. It is empty if
+	// the issues do not link Jira.
+	SearchURL string
+	// Folded is the number of issues left out of the group, as the group has
+	// more issues than its maximum number of issues.
+	Folded int
+	// FoldedText is the text of the link to the folded issues, like "…and 7
+	// more". It is empty if no issue is folded.
+	FoldedText string
+	// FoldedURL is the URL of the Jira search listing the folded issues. It
+	// is empty if no issue is folded, or the issues do not link Jira.
+	FoldedURL string
+	// plainFormats lists the formats rendering the group without a
+	// collapsible block.
+	plainFormats []string
+}
+
+// Groups returns the issues grouped by status. The statuses are listed in the
+// given order, matched case-insensitively, followed by the rest of the
+// statuses in alphabetical order.
+func (i Issues) Groups(order []string) []StatusGroup {
+	groups := make([]StatusGroup, 0, len(i))
+	listed := make(map[string]bool)
+
+	statusOf := make(map[string]string, len(i))
+	for status := range i {
+		statusOf[strings.ToLower(status)] = status
+	}
+
+	for _, name := range order {
+		if status, ok := statusOf[strings.ToLower(name)]; ok && !listed[status] {
+			listed[status] = true
+			groups = append(groups, StatusGroup{Name: status, Status: status, Issues: i[status], StoryPoints: sumStoryPoints(i[status])})
+		}
+	}
+
+	statuses := make([]string, 0, len(i))
+	for status := range i {
+		if !listed[status] {
+			statuses = append(statuses, status)
+		}
+	}
+
+	sort.Strings(statuses)
+
+	for _, status := range statuses {
+		groups = append(groups, StatusGroup{Name: status, Status: status, Issues: i[status], StoryPoints: sumStoryPoints(i[status])})
+	}
+
+	return groups
+}
+
+// Regroup returns the issues with their statuses mapped to display groups and
+// the hidden statuses removed. The groups map the name of every display group
+// to the statuses it consists of. The statuses are matched case-insensitively,
+// and can be hidden by either their own or their group's name.
+func (i Issues) Regroup(groups map[string][]string, hidden []string) Issues {
+	groupOf := make(map[string]string)
+	for group, statuses := range groups {
+		for _, status := range statuses {
+			groupOf[strings.ToLower(status)] = group
+		}
+	}
+
+	isHidden := make(map[string]bool)
+	for _, status := range hidden {
+		isHidden[strings.ToLower(status)] = true
+	}
+
+	regrouped := make(Issues)
+
+	for status, issues := range i {
+		group, ok := groupOf[strings.ToLower(status)]
+		if !ok {
+			group = status
+		}
+
+		if isHidden[strings.ToLower(status)] || isHidden[strings.ToLower(group)] {
+			continue
+		}
+
+		regrouped[group] = append(regrouped[group], issues...)
+	}
+
+	return regrouped
+}
+
+// ellipsis is appended to the truncated texts.
+const ellipsis = "..."
+
+// Truncate shortens the text to the given number of characters, including the
+// appended ellipsis. The text is cut at a word boundary if possible, without
+// splitting multi-byte characters. If the length is zero or negative, the text
+// is returned intact.
+func Truncate(text string, length int) string {
+	runes := []rune(text)
+	if length <= 0 || len(runes) <= length {
+		return text
+	}
+
+	cut := length - len(ellipsis)
+	if cut <= 0 {
+		return string(runes[:length])
+	}
+
+	// Cut at the last space if it does not drop more than half of the text.
+	truncated := runes[:cut]
+	if !unicode.IsSpace(runes[cut]) {
+		for i := len(truncated) - 1; i > cut/2; i-- {
+			if unicode.IsSpace(truncated[i]) {
+				truncated = truncated[:i]
+				break
+			}
+		}
+	}
+
+	return strings.TrimRightFunc(string(truncated), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + ellipsis
+}
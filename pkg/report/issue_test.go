@@ -0,0 +1,44 @@
+package report
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := map[string]struct {
+		text   string
+		length int
+		want   string
+	}{
+		"shorter":                 {text: "Fix the login", length: 20, want: "Fix the login"},
+		"exact length":            {text: "Fix the login", length: 13, want: "Fix the login"},
+		"zero length":             {text: "Fix the login", length: 0, want: "Fix the login"},
+		"negative length":         {text: "Fix the login", length: -1, want: "Fix the login"},
+		"word boundary":           {text: "Fix the login page of the app", length: 15, want: "Fix the..."},
+		"cut before a space":      {text: "Fix it, then ship it", length: 10, want: "Fix it..."},
+		"long word":               {text: "Supercalifragilistic", length: 10, want: "Superca..."},
+		"multi-byte runes":        {text: "Árvíztűrő tükörfúrógép", length: 10, want: "Árvíztű..."},
+		"multi-byte word":         {text: "日本語 テキストの要約", length: 8, want: "日本語..."},
+		"length of the ellipsis":  {text: "Fix the login", length: 3, want: "Fix"},
+		"below the ellipsis":      {text: "Fix the login", length: 2, want: "Fi"},
+		"multi-byte below limits": {text: "日本語", length: 1, want: "日"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Truncate(tt.text, tt.length)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.length, got, tt.want)
+			}
+
+			if !utf8.ValidString(got) {
+				t.Errorf("Truncate(%q, %d) = %q is not valid UTF-8", tt.text, tt.length, got)
+			}
+
+			if tt.length > 0 && utf8.RuneCountInString(got) > tt.length {
+				t.Errorf("Truncate(%q, %d) = %q is longer than the length", tt.text, tt.length, got)
+			}
+		})
+	}
+}
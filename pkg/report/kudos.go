@@ -0,0 +1,66 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kudosPlaceholder is listed in the kudos section when no kudos are given
+// or suggested, reminding to fill it in.
+const kudosPlaceholder = "TODO"
+
+// KudosSuggestion is a colleague suggested for kudos based on their activity
+// on the issues of the sprint.
+type KudosSuggestion struct {
+	// Name is the display name of the colleague.
+	Name string
+	// Commented lists the keys of the issues the colleague commented on.
+	Commented []string
+	// Unblocked lists the keys of the issues the colleague unblocked by
+	// resolving their blockers.
+	Unblocked []string
+}
+
+// Reason describes the activity the kudos is suggested for, like "commented
+// on ABC-1, ABC-2; unblocked ABC-3".
+func (k KudosSuggestion) Reason() string {
+	return k.reason(fmt.Sprintf)
+}
+
+// reason describes the activity the kudos is suggested for, formatting the
+// messages using the translate function.
+func (k KudosSuggestion) reason(translate func(message string, args ...interface{}) string) string {
+	var reasons []string
+
+	if len(k.Commented) > 0 {
+		reasons = append(reasons, translate("commented on %s", strings.Join(k.Commented, ", ")))
+	}
+
+	if len(k.Unblocked) > 0 {
+		reasons = append(reasons, translate("unblocked %s", strings.Join(k.Unblocked, ", ")))
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+// KudosReason returns the reason of the kudos suggestion in the language of
+// the update.
+func (u *Update) KudosReason(k KudosSuggestion) string {
+	return k.reason(u.T)
+}
+
+// KudosOrPlaceholder returns the kudos given to others, or the placeholder
+// to fill in if no kudos are given or suggested. The suggestions are listed
+// after the kudos.
+func (u *Update) KudosOrPlaceholder() []string {
+	if len(u.Kudos) == 0 && len(u.SuggestedKudos) == 0 {
+		return []string{kudosPlaceholder}
+	}
+
+	return u.Kudos
+}
+
+// String returns the suggestion as kudos text.
+func (k KudosSuggestion) String() string {
+	return k.Name + " for their help (" + k.Reason() + ")"
+}
@@ -0,0 +1,171 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// blocksLink is the outward description of the issue link used for blocking
+// issues.
+const blocksLink = "blocks"
+
+// LinkedIssue is an issue linked to an issue of the update, like an issue of
+// another team blocking it.
+type LinkedIssue struct {
+	// Relation describes the link from the point of view of the issue of the
+	// update, like "blocks", "is blocked by", or "relates to".
+	Relation string
+	Key      string
+	Summary  string
+	URL      string
+	Status   string
+	// Done indicates that the linked issue is in a status of the "done"
+	// category.
+	Done bool
+}
+
+// ProjectKey returns the key of the project of the linked issue, like "OPS"
+// for "OPS-42".
+func (l *LinkedIssue) ProjectKey() string {
+	return projectKey(l.Key)
+}
+
+// Dependency is a link of an issue of the update to an issue of another
+// project, listed in the dependencies section.
+type Dependency struct {
+	// Key, Summary, and URL identify the issue of the update.
+	Key     string
+	Summary string
+	URL     string
+	// Link is the linked issue of the other project.
+	Link LinkedIssue
+}
+
+// projectKey returns the project of the issue key, like "SE" for "SE-101",
+// or the key itself if it has no project.
+func projectKey(key string) string {
+	if i := strings.LastIndex(key, "-"); i > 0 {
+		return key[:i]
+	}
+
+	return key
+}
+
+// linkedIssues returns the issues linked to the issue, in the order of the
+// links.
+func linkedIssues(serverURL string, issue *gojira.Issue) []LinkedIssue {
+	var links []LinkedIssue
+
+	for _, link := range issue.Fields.IssueLinks {
+		linked, relation := link.OutwardIssue, link.Type.Outward
+		if linked == nil {
+			linked, relation = link.InwardIssue, link.Type.Inward
+		}
+
+		if linked == nil {
+			continue
+		}
+
+		l := LinkedIssue{
+			Relation: relation,
+			Key:      linked.Key,
+			URL:      fmt.Sprintf("%s/browse/%s", serverURL, linked.Key),
+		}
+
+		if fields := linked.Fields; fields != nil {
+			l.Summary = fields.Summary
+			if fields.Status != nil {
+				l.Status = fields.Status.Name
+				l.Done = fields.Status.StatusCategory.Key == gojira.StatusCategoryComplete
+			}
+		}
+
+		links = append(links, l)
+	}
+
+	return links
+}
+
+// blockingKeys returns the keys of the issues blocked by the issue, and of
+// the issues blocking it.
+func (i *Issue) blockingKeys() ([]string, []string) {
+	var blocks, blockedBy []string
+
+	for _, link := range i.Links {
+		switch {
+		case strings.EqualFold(link.Relation, blocksLink):
+			blocks = append(blocks, link.Key)
+		case strings.EqualFold(link.Relation, blockedByLink):
+			blockedBy = append(blockedBy, link.Key)
+		}
+	}
+
+	return blocks, blockedBy
+}
+
+// linksAnnotation returns the annotation of the blocking links of the issue,
+// like "blocks SE-7, blocked by OPS-42", or an empty string if the issue has
+// no blocking links.
+func linksAnnotation(blocks []string, blockedBy []string) string {
+	var parts []string
+	if len(blocks) > 0 {
+		parts = append(parts, "blocks "+strings.Join(blocks, ", "))
+	}
+
+	if len(blockedBy) > 0 {
+		parts = append(parts, "blocked by "+strings.Join(blockedBy, ", "))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Dependencies returns the links of the issues to the issues of the projects
+// other than the projects of the issues, in both directions, so both the
+// issues the team waits for and the issues waiting for the team are listed.
+// The dependencies are sorted by the linked project, then by the keys of the
+// issues.
+func (i Issues) Dependencies() []Dependency {
+	projects := make(map[string]bool)
+	for _, issues := range i {
+		for j := range issues {
+			projects[strings.ToUpper(projectKey(issues[j].Key))] = true
+		}
+	}
+
+	var dependencies []Dependency
+	for _, issues := range i {
+		for j := range issues {
+			issue := &issues[j]
+			for _, link := range issue.Links {
+				if projects[strings.ToUpper(link.ProjectKey())] {
+					continue
+				}
+
+				dependencies = append(dependencies, Dependency{
+					Key:     issue.Key,
+					Summary: issue.Summary,
+					URL:     issue.URL,
+					Link:    link,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(dependencies, func(a, b int) bool {
+		x, y := &dependencies[a], &dependencies[b]
+		if px, py := x.Link.ProjectKey(), y.Link.ProjectKey(); px != py {
+			return px < py
+		}
+
+		if x.Link.Key != y.Link.Key {
+			return compareKeys(x.Link.Key, y.Link.Key) < 0
+		}
+
+		return compareKeys(x.Key, y.Key) < 0
+	})
+
+	return dependencies
+}
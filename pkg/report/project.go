@@ -0,0 +1,99 @@
+package report
+
+import (
+	"sort"
+)
+
+// Project is a project of the issues of the update, listed separately in the
+// worked on section when the issues are split by project.
+type Project struct {
+	// Key is the key of the project.
+	Key string
+	// Name is the name of the project, or its key if the name is unknown.
+	Name string
+	// Issues are the issues of the project grouped by status.
+	Issues Issues
+}
+
+// Count returns the number of the issues of the project. The subtasks nested
+// under their parents are not counted, and the issues listed in multiple
+// statuses are counted once.
+func (p *Project) Count() int {
+	counted := make(map[string]bool)
+
+	for _, issues := range p.Issues {
+		for _, issue := range issues {
+			counted[issue.Key] = true
+		}
+	}
+
+	return len(counted)
+}
+
+// CommittedPoints returns the total story points of the issues of the
+// project.
+func (p *Project) CommittedPoints() float64 {
+	return p.Issues.StoryPoints()
+}
+
+// DonePoints returns the total story points of the done issues of the
+// project.
+func (p *Project) DonePoints() float64 {
+	return p.Issues.Filter(func(issue *Issue) bool {
+		return issue.Done
+	}).StoryPoints()
+}
+
+// Projects returns the issues split by their project, keeping their statuses,
+// in the alphabetical order of the project names. The issues without a
+// project are listed last.
+func (i Issues) Projects() []Project {
+	projects := make(map[string]*Project)
+
+	var keys []string
+	for status, issues := range i {
+		for _, issue := range issues {
+			key := issue.ProjectKey
+			if key == "" {
+				key = issue.Project
+			}
+
+			project, ok := projects[key]
+			if !ok {
+				name := issue.Project
+				if name == "" {
+					name = noProjectGroup
+				}
+
+				project = &Project{
+					Key:    issue.ProjectKey,
+					Name:   name,
+					Issues: make(Issues),
+				}
+				projects[key] = project
+				keys = append(keys, key)
+			}
+
+			project.Issues[status] = append(project.Issues[status], issue)
+		}
+	}
+
+	sorted := make([]Project, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, *projects[key])
+	}
+
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if (sorted[a].Name == noProjectGroup) != (sorted[b].Name == noProjectGroup) {
+			return sorted[b].Name == noProjectGroup
+		}
+
+		if sorted[a].Name != sorted[b].Name {
+			return sorted[a].Name < sorted[b].Name
+		}
+
+		return sorted[a].Key < sorted[b].Key
+	})
+
+	return sorted
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// ProvenanceTimeLayout is the layout of the time the update was generated at,
+// rendered in the provenance footer.
+const ProvenanceTimeLayout = time.RFC3339
+
+// Provenance describes how the update was generated, rendered in its footer,
+// so the archived updates can be traced back to the version of the tool and
+// the data producing them.
+type Provenance struct {
+	// Tool is the name and the version of the tool, like
+	// "sprint-update 1.2.0".
+	Tool string
+	// Generated is the time the update was generated at, in UTC.
+	Generated time.Time
+	// SprintID is the ID of the sprint in the issue tracker. It is empty if
+	// unknown, like for the updates covering a period.
+	SprintID string
+	// QueryHash is the hex-encoded SHA-256 hash of the queries the issues
+	// were fetched with, so the updates generated using the same queries can
+	// be told apart from the others without revealing the queries.
+	QueryHash string
+}
+
+// NewProvenance returns the provenance of an update generated now by the tool
+// for the sprint, fetching the issues with the queries, like one JQL query
+// per team member.
+func NewProvenance(tool string, sprintID string, queries []string) *Provenance {
+	hash := sha256.Sum256([]byte(strings.Join(queries, "\n")))
+
+	return &Provenance{
+		Tool:      tool,
+		Generated: time.Now().UTC().Truncate(time.Second),
+		SprintID:  sprintID,
+		QueryHash: hex.EncodeToString(hash[:]),
+	}
+}
+
+// ProvenanceNote returns the sentence of the provenance footer translated to
+// the language of the update, like "Generated by sprint-update 1.2.0 at
+// 2021-10-04T09:00:00Z, sprint ID 42, query SHA-256 3a7bd3e2…". It is empty
+// if the provenance of the update is not rendered.
+func (u *Update) ProvenanceNote() string {
+	p := u.Provenance
+	if p == nil {
+		return ""
+	}
+
+	parts := []string{u.T("Generated by %s at %s", p.Tool, p.Generated.Format(ProvenanceTimeLayout))}
+	if p.SprintID != "" {
+		parts = append(parts, u.T("sprint ID %s", p.SprintID))
+	}
+
+	parts = append(parts, u.T("query SHA-256 %s", p.QueryHash))
+
+	return strings.Join(parts, ", ")
+}
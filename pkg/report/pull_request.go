@@ -0,0 +1,71 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/codehost"
+)
+
+// issueKeyPattern matches Jira issue keys, like "SE-123".
+var issueKeyPattern = regexp.MustCompile(`[A-Z][A-Z0-9]+-[0-9]+`)
+
+// PullRequest represents a pull request in the sprint update.
+type PullRequest struct {
+	// Repository is the full name of the repository, like "owner/repo".
+	Repository string
+	Number     int
+	Title      string
+	URL        string
+	Merged     bool
+	// Issues lists the Jira issues referenced by the branch name or title.
+	Issues []IssueLink
+}
+
+// IssueLink is a reference to a Jira issue.
+type IssueLink struct {
+	Key string
+	URL string
+}
+
+// NewPullRequest returns a new PullRequest from the given pull request of a
+// code host. The Jira issue keys are looked up in the branch name, regardless
+// of its case, and in the title.
+func NewPullRequest(serverURL string, pullRequest *codehost.PullRequest) PullRequest {
+	var links []IssueLink
+	seen := make(map[string]bool)
+
+	keys := issueKeyPattern.FindAllString(strings.ToUpper(pullRequest.Branch), -1)
+	keys = append(keys, issueKeyPattern.FindAllString(pullRequest.Title, -1)...)
+
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			links = append(links, IssueLink{
+				Key: key,
+				URL: fmt.Sprintf("%s/browse/%s", serverURL, key),
+			})
+		}
+	}
+
+	return PullRequest{
+		Repository: pullRequest.Repository,
+		Number:     pullRequest.Number,
+		Title:      pullRequest.Title,
+		URL:        pullRequest.URL,
+		Merged:     pullRequest.IsMerged(),
+		Issues:     links,
+	}
+}
+
+// NewPullRequests returns the given pull requests as PullRequests.
+func NewPullRequests(serverURL string, pullRequests []codehost.PullRequest) []PullRequest {
+	transformed := make([]PullRequest, 0, len(pullRequests))
+
+	for i := range pullRequests {
+		transformed = append(transformed, NewPullRequest(serverURL, &pullRequests[i]))
+	}
+
+	return transformed
+}
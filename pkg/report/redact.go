@@ -0,0 +1,172 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// redactor replaces the internal issue keys by their aliases.
+type redactor struct {
+	// aliases maps the lowercase issue and project keys to their aliases.
+	aliases map[string]string
+}
+
+// newRedactor returns a redactor using the aliases of the issue and project
+// keys, matched case-insensitively.
+func newRedactor(aliases map[string]string) *redactor {
+	r := &redactor{aliases: make(map[string]string, len(aliases))}
+	for key, alias := range aliases {
+		r.aliases[strings.ToLower(key)] = alias
+	}
+
+	return r
+}
+
+// key returns the alias of the issue key, or an empty string if the key has
+// no alias. The aliases of the projects replace the project key of the issue
+// keys, like "SE-101" to "SEARCH-101" for the "SEARCH" alias of "SE".
+func (r *redactor) key(key string) string {
+	if key == "" {
+		return ""
+	}
+
+	if alias, ok := r.aliases[strings.ToLower(key)]; ok {
+		return alias
+	}
+
+	i := strings.LastIndex(key, "-")
+	if i <= 0 {
+		return ""
+	}
+
+	if alias, ok := r.aliases[strings.ToLower(key[:i])]; ok {
+		return alias + key[i:]
+	}
+
+	return ""
+}
+
+// keys returns the aliases of the issue keys, leaving out the keys without
+// aliases.
+func (r *redactor) keys(keys []string) []string {
+	var aliased []string
+	for _, key := range keys {
+		if alias := r.key(key); alias != "" {
+			aliased = append(aliased, alias)
+		}
+	}
+
+	return aliased
+}
+
+// mentions returns the aliases of the issue keys mentioned in the text, like
+// the kudos suggestions, counting the keys without aliases instead, like
+// "2 issues", so the text still makes sense.
+func (r *redactor) mentions(keys []string) []string {
+	aliased := r.keys(keys)
+
+	switch redacted := len(keys) - len(aliased); {
+	case redacted == 0:
+		return aliased
+	case len(aliased) == 0 && redacted == 1:
+		return []string{"an issue"}
+	case len(aliased) == 0:
+		return []string{fmt.Sprintf("%d issues", redacted)}
+	case redacted == 1:
+		return append(aliased, "another issue")
+	default:
+		return append(aliased, fmt.Sprintf("%d other issues", redacted))
+	}
+}
+
+// issue strips the key and the URL of the issue and its subtasks.
+func (r *redactor) issue(issue *Issue) {
+	r.linksAnnotation(issue)
+
+	issue.Key = r.key(issue.Key)
+	issue.URL = ""
+	issue.BlockedBy = r.keys(issue.BlockedBy)
+
+	for i := range issue.Links {
+		r.link(&issue.Links[i])
+	}
+	issue.Parent = r.key(issue.Parent)
+	issue.EpicKey = r.key(issue.EpicKey)
+
+	for i := range issue.Subtasks {
+		r.issue(&issue.Subtasks[i])
+	}
+}
+
+// link strips the key and the URL of the linked issue.
+func (r *redactor) link(link *LinkedIssue) {
+	link.Key = r.key(link.Key)
+	link.URL = ""
+}
+
+// linksAnnotation replaces the keys of the links annotation of the issue by
+// their aliases, or by the number of the issues without aliases.
+func (r *redactor) linksAnnotation(issue *Issue) {
+	blocks, blockedBy := issue.blockingKeys()
+
+	annotation := linksAnnotation(blocks, blockedBy)
+	if annotation == "" {
+		return
+	}
+
+	for i := range issue.Annotations {
+		if issue.Annotations[i] == annotation {
+			issue.Annotations[i] = linksAnnotation(r.mentions(blocks), r.mentions(blockedBy))
+		}
+	}
+}
+
+// Redact strips the internal issue keys and URLs from every section of the
+// update, so it can be shared with external stakeholders. The issue keys
+// having an alias, either by their own key or by the key of their project,
+// are replaced by their alias instead. The pull requests link internal
+// repositories, hence they are left out.
+func (u *Update) Redact(aliases map[string]string) {
+	r := newRedactor(aliases)
+
+	for _, section := range u.sections() {
+		for _, issues := range *section {
+			for i := range issues {
+				r.issue(&issues[i])
+			}
+		}
+	}
+
+	if u.Timesheet != nil {
+		for i := range u.Timesheet.Unlogged {
+			r.issue(&u.Timesheet.Unlogged[i])
+		}
+	}
+
+	for i := range u.SuggestedKudos {
+		u.SuggestedKudos[i].Commented = r.mentions(u.SuggestedKudos[i].Commented)
+		u.SuggestedKudos[i].Unblocked = r.mentions(u.SuggestedKudos[i].Unblocked)
+	}
+
+	for i := range u.Themes {
+		if u.Themes[i].Name == u.Themes[i].Key {
+			u.Themes[i].Name = r.key(u.Themes[i].Key)
+		}
+
+		u.Themes[i].Key = r.key(u.Themes[i].Key)
+		u.Themes[i].URL = ""
+	}
+
+	for i := range u.Projects {
+		u.Projects[i].Key = ""
+	}
+
+	for i := range u.Dependencies {
+		u.Dependencies[i].Key = r.key(u.Dependencies[i].Key)
+		u.Dependencies[i].URL = ""
+		r.link(&u.Dependencies[i].Link)
+	}
+
+	u.PullRequests = nil
+	u.Commits = nil
+}
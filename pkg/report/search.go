@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SearchURL returns the URL of the Jira search listing the given issues and
+// their subtasks, like {{ $.SearchURL .Issues }} for the worked on section or
+// {{ $.SearchURL .Blocked }} for the blocked issues. It is empty if the issues
+// do not link Jira, like the redacted issues or the issues of other trackers.
+func (u *Update) SearchURL(issues Issues) string {
+	var listed []Issue
+	for _, group := range issues.Groups(nil) {
+		listed = append(listed, withSubtasks(group.Issues)...)
+	}
+
+	return searchURL(listed)
+}
+
+// withSubtasks returns the issues followed by their subtasks.
+func withSubtasks(issues []Issue) []Issue {
+	listed := make([]Issue, 0, len(issues))
+	for i := range issues {
+		listed = append(listed, issues[i])
+		listed = append(listed, issues[i].Subtasks...)
+	}
+
+	return listed
+}
+
+// searchURL returns the URL of the Jira issue search listing the issues, or
+// an empty string if the issues do not link Jira, like the redacted issues or
+// the issues of other trackers.
+func searchURL(issues []Issue) string {
+	var serverURL string
+	keys := make([]string, 0, len(issues))
+
+	for i := range issues {
+		browse := strings.LastIndex(issues[i].URL, "/browse/")
+		if browse < 0 || issues[i].Key == "" {
+			continue
+		}
+
+		serverURL = issues[i].URL[:browse]
+		keys = append(keys, issues[i].Key)
+	}
+
+	if serverURL == "" {
+		return ""
+	}
+
+	jql := fmt.Sprintf("key in (%s) ORDER BY Rank", strings.Join(keys, ", "))
+	return serverURL + "/issues/?jql=" + url.QueryEscape(jql)
+}
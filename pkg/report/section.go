@@ -0,0 +1,159 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Section is a section of the body of the update, rendered by the template of
+// the same name of the built-in formats.
+type Section string
+
+const (
+	// SectionSummary is the narrative summary of the update written by a
+	// language model.
+	SectionSummary Section = "summary"
+	// SectionThemes summarizes the epics touched by the issues.
+	SectionThemes Section = "themes"
+	// SectionWorkedOn lists the issues worked on, grouped by status.
+	SectionWorkedOn Section = "worked-on"
+	// SectionBlocked lists the blocked issues.
+	SectionBlocked Section = "blocked"
+	// SectionDependencies lists the links of the issues to the issues of
+	// other projects, like the issues of other teams blocking them.
+	SectionDependencies Section = "dependencies"
+	// SectionPullRequests lists the pull requests of the sprint.
+	SectionPullRequests Section = "pull-requests"
+	// SectionCommits lists the commits of the sprint, grouped by issue.
+	SectionCommits Section = "commits"
+	// SectionHours summarizes the hours logged in Tempo.
+	SectionHours Section = "hours"
+	// SectionSpillovers lists the spillovers of the sprint.
+	SectionSpillovers Section = "spillovers"
+	// SectionCarriedOver lists the issues carried over from the previous
+	// sprint.
+	SectionCarriedOver Section = "carried-over"
+	// SectionKudos lists the kudos given to others.
+	SectionKudos Section = "kudos"
+	// SectionTimeOff describes the planned time off.
+	SectionTimeOff Section = "time-off"
+	// SectionStats summarizes the velocity of the sprint in end of sprint
+	// updates.
+	SectionStats Section = "stats"
+)
+
+// DefaultSections lists the sections rendered when no sections are
+// configured, in their order.
+var DefaultSections = []Section{
+	SectionWorkedOn,
+	SectionBlocked,
+	SectionPullRequests,
+	SectionCommits,
+	SectionHours,
+	SectionSpillovers,
+	SectionCarriedOver,
+	SectionKudos,
+	SectionTimeOff,
+}
+
+var (
+	// ErrUnknownSection is returned when the requested section does not
+	// exist.
+	ErrUnknownSection = errors.New("unknown section")
+	// ErrDuplicateSection is returned when a section is listed more than
+	// once.
+	ErrDuplicateSection = errors.New("duplicate section")
+)
+
+// Sections returns the supported sections.
+func Sections() []string {
+	return []string{
+		string(SectionSummary),
+		string(SectionThemes),
+		string(SectionWorkedOn),
+		string(SectionBlocked),
+		string(SectionDependencies),
+		string(SectionPullRequests),
+		string(SectionCommits),
+		string(SectionHours),
+		string(SectionSpillovers),
+		string(SectionCarriedOver),
+		string(SectionKudos),
+		string(SectionTimeOff),
+		string(SectionStats),
+	}
+}
+
+// ValidateSections checks that the sections are supported and listed once,
+// and that the titles belong to supported sections.
+func ValidateSections(sections []Section, titles map[string]string) error {
+	seen := make(map[Section]bool, len(sections))
+
+	for _, section := range sections {
+		if !isSection(section) {
+			return fmt.Errorf("%w: %s", ErrUnknownSection, section)
+		}
+
+		if seen[section] {
+			return fmt.Errorf("%w: %s", ErrDuplicateSection, section)
+		}
+
+		seen[section] = true
+	}
+
+	for section := range titles {
+		if !isSection(Section(section)) {
+			return fmt.Errorf("%w: %s", ErrUnknownSection, section)
+		}
+	}
+
+	return nil
+}
+
+// isSection reports whether the section is supported.
+func isSection(section Section) bool {
+	for _, name := range Sections() {
+		if Section(name) == section {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasSection reports whether the section is rendered, being one of the
+// sections, or of DefaultSections if the sections are not set.
+func HasSection(sections []Section, section Section) bool {
+	if len(sections) == 0 {
+		sections = DefaultSections
+	}
+
+	for _, s := range sections {
+		if s == section {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OrderedSections returns the sections of the update in the order they are
+// rendered, which are DefaultSections unless the sections are set.
+func (u *Update) OrderedSections() []Section {
+	if len(u.Sections) == 0 {
+		return DefaultSections
+	}
+
+	return u.Sections
+}
+
+// Heading returns the heading of the section, which is its configured title,
+// or else the translation of the default heading formatted with the
+// arguments, like {{ $.Heading "kudos" "Kudos" }}.
+func (u *Update) Heading(section string, heading string, args ...interface{}) string {
+	if title := u.SectionTitles[section]; title != "" {
+		return title
+	}
+
+	return u.T(heading, args...)
+}
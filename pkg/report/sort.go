@@ -0,0 +1,148 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortBy is the attribute the issues are sorted by within their groups.
+type SortBy string
+
+const (
+	// SortByRank sorts the issues by their rank, which is their order on the
+	// board or in the backlog.
+	SortByRank SortBy = "rank"
+	// SortByKey sorts the issues by their key, like "SE-9" before "SE-10".
+	SortByKey SortBy = "key"
+	// SortByUpdated sorts the issues by the time they were last updated, the
+	// most recently updated first.
+	SortByUpdated SortBy = "updated"
+	// SortByPriority sorts the issues by their priority, the highest first.
+	SortByPriority SortBy = "priority"
+)
+
+// DefaultPriorities lists the priorities from the highest to the lowest used
+// when the priorities of the tracker are unknown, covering the default
+// priorities of Jira and Linear.
+var DefaultPriorities = []string{"Highest", "Urgent", "High", "Medium", "Low", "Lowest"}
+
+// ErrUnknownSortBy is returned when the issues cannot be sorted by the
+// requested attribute.
+var ErrUnknownSortBy = errors.New("unknown sorting")
+
+// SortBys returns the supported sortings.
+func SortBys() []string {
+	return []string{string(SortByRank), string(SortByKey), string(SortByUpdated), string(SortByPriority)}
+}
+
+// ValidateSortBy checks that the sorting is supported. An empty sorting is the
+// same as SortByRank.
+func ValidateSortBy(by SortBy) error {
+	switch by {
+	case "", SortByRank, SortByKey, SortByUpdated, SortByPriority:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownSortBy, by)
+	}
+}
+
+// Sort sorts the issues of every group by the given attribute. The issues
+// sorted the same, like the issues of the same priority, are sorted by their
+// key, so the order is stable from run to run. The priorities list the
+// priorities from the highest to the lowest; when empty, DefaultPriorities is
+// used.
+func (i Issues) Sort(by SortBy, priorities []string) {
+	if len(priorities) == 0 {
+		priorities = DefaultPriorities
+	}
+
+	for _, issues := range i {
+		sort.SliceStable(issues, func(a, b int) bool {
+			if c := compareIssues(&issues[a], &issues[b], by, priorities); c != 0 {
+				return c < 0
+			}
+
+			return compareKeys(issues[a].Key, issues[b].Key) < 0
+		})
+	}
+}
+
+// compareIssues compares the issues by the given attribute, returning a
+// negative number if a is listed before b, a positive number if a is listed
+// after b, and zero if they are sorted the same.
+func compareIssues(a *Issue, b *Issue, by SortBy, priorities []string) int {
+	switch by {
+	case "", SortByRank:
+		// The issues without a rank, like the issues of other trackers, are
+		// listed last.
+		switch {
+		case a.Rank == b.Rank:
+			return 0
+		case a.Rank == "":
+			return 1
+		case b.Rank == "":
+			return -1
+		}
+
+		return strings.Compare(a.Rank, b.Rank)
+	case SortByUpdated:
+		switch {
+		case a.Updated.After(b.Updated):
+			return -1
+		case a.Updated.Before(b.Updated):
+			return 1
+		}
+	case SortByPriority:
+		if c := priorityIndex(a.Priority, priorities) - priorityIndex(b.Priority, priorities); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Priority, b.Priority)
+	}
+
+	return 0
+}
+
+// priorityIndex returns the index of the priority in the priorities. The
+// unknown priorities are sorted after the known ones.
+func priorityIndex(priority string, priorities []string) int {
+	for i, p := range priorities {
+		if strings.EqualFold(p, priority) {
+			return i
+		}
+	}
+
+	return len(priorities)
+}
+
+// compareKeys compares the issue keys by their prefix, like "SE-", then by
+// their number, so "SE-9" is sorted before "SE-10".
+func compareKeys(a string, b string) int {
+	prefixA, numberA := splitKey(a)
+	prefixB, numberB := splitKey(b)
+
+	if c := strings.Compare(prefixA, prefixB); c != 0 {
+		return c
+	}
+
+	return numberA - numberB
+}
+
+// splitKey splits the issue key into its prefix and its trailing number. Keys
+// without a trailing number have a number of -1.
+func splitKey(key string) (string, int) {
+	end := len(key)
+	for end > 0 && key[end-1] >= '0' && key[end-1] <= '9' {
+		end--
+	}
+
+	number, err := strconv.Atoi(key[end:])
+	if err != nil {
+		return key, -1
+	}
+
+	return key[:end], number
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Stats is the velocity of the sprint, read from the sprint report of the
+// board at the end of the sprint. Unlike the story point totals of the
+// update, the statistics cover every issue of the sprint, regardless of its
+// assignee.
+type Stats struct {
+	// CommittedPoints is the total of the story points estimated for the
+	// issues of the sprint when it started.
+	CommittedPoints float64
+	// CompletedPoints is the total of the story points of the issues
+	// completed within the sprint, including the issues added after its
+	// start.
+	CompletedPoints float64
+	// CompletedIssues is the number of the issues completed within the sprint.
+	CompletedIssues int
+	// NotCompletedIssues is the number of the issues left unresolved.
+	NotCompletedIssues int
+	// RemovedIssues is the number of the issues removed from the sprint.
+	RemovedIssues int
+	// AddedIssues is the number of the issues added to the sprint after it
+	// started, also known as scope creep.
+	AddedIssues int
+}
+
+// CompletionPercentage returns the completed story points as the percentage
+// of the committed story points, rounded to a whole number, like 85. Without
+// committed story points, the percentage of the completed issues is returned.
+func (s *Stats) CompletionPercentage() float64 {
+	if s.CommittedPoints > 0 {
+		return math.Round(s.CompletedPoints / s.CommittedPoints * 100)
+	}
+
+	if total := s.CompletedIssues + s.NotCompletedIssues; total > 0 {
+		return math.Round(float64(s.CompletedIssues) / float64(total) * 100)
+	}
+
+	return 0
+}
+
+// CycleTime is the time the done issues of the sprint took from their start
+// to their completion, read from their changelog.
+type CycleTime struct {
+	// Issues is the number of the issues the cycle time is measured on.
+	Issues int
+	// Average and Median are the average and the median cycle time of the
+	// issues.
+	Average time.Duration
+	Median  time.Duration
+}
+
+// AverageDays returns the average cycle time in days, rounded to one decimal.
+func (c *CycleTime) AverageDays() float64 {
+	return durationDays(c.Average)
+}
+
+// MedianDays returns the median cycle time in days, rounded to one decimal.
+func (c *CycleTime) MedianDays() float64 {
+	return durationDays(c.Median)
+}
+
+// durationDays returns the duration in days, rounded to one decimal.
+func durationDays(d time.Duration) float64 {
+	return math.Round(d.Hours()/24*10) / 10
+}
+
+// CycleTime returns the cycle time of the done issues and their subtasks
+// having both their start and completion times known, or nil if there are no
+// such issues.
+func (i Issues) CycleTime() *CycleTime {
+	var durations []time.Duration
+	seen := make(map[string]bool)
+
+	var add func(issue *Issue)
+	add = func(issue *Issue) {
+		if !seen[issue.Key] && issue.Done && !issue.Started.IsZero() && issue.Finished.After(issue.Started) {
+			seen[issue.Key] = true
+			durations = append(durations, issue.Finished.Sub(issue.Started))
+		}
+
+		for j := range issue.Subtasks {
+			add(&issue.Subtasks[j])
+		}
+	}
+
+	for _, issues := range i {
+		for j := range issues {
+			add(&issues[j])
+		}
+	}
+
+	if len(durations) == 0 {
+		return nil
+	}
+
+	sort.Slice(durations, func(a, b int) bool {
+		return durations[a] < durations[b]
+	})
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+
+	median := durations[len(durations)/2]
+	if len(durations)%2 == 0 {
+		median = (durations[len(durations)/2-1] + median) / 2
+	}
+
+	return &CycleTime{
+		Issues:  len(durations),
+		Average: total / time.Duration(len(durations)),
+		Median:  median,
+	}
+}
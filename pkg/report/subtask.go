@@ -0,0 +1,111 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SubtaskMode is the way the subtasks are listed in the update.
+type SubtaskMode string
+
+const (
+	// SubtasksFlat lists the subtasks as peers of their parents.
+	SubtasksFlat SubtaskMode = "flat"
+	// SubtasksNest lists the subtasks under their parents.
+	SubtasksNest SubtaskMode = "nest"
+	// SubtasksRollup lists the parents only, together with the number of
+	// their done subtasks.
+	SubtasksRollup SubtaskMode = "rollup"
+)
+
+// ErrUnknownSubtaskMode is returned when the subtask mode is not supported.
+var ErrUnknownSubtaskMode = errors.New("unknown subtask mode")
+
+// SubtaskModes returns the supported subtask modes.
+func SubtaskModes() []string {
+	return []string{string(SubtasksFlat), string(SubtasksNest), string(SubtasksRollup)}
+}
+
+// ValidateSubtaskMode checks that the subtask mode is supported. An empty mode
+// is the same as SubtasksFlat.
+func ValidateSubtaskMode(mode SubtaskMode) error {
+	switch mode {
+	case "", SubtasksFlat, SubtasksNest, SubtasksRollup:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownSubtaskMode, mode)
+	}
+}
+
+// SubtaskProgress describes the number of done subtasks of the issue in
+// rollup mode, like "3/5 subtasks done". It is empty otherwise.
+func (i *Issue) SubtaskProgress() string {
+	if i.SubtasksTotal == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d/%d subtasks done", i.SubtasksDone, i.SubtasksTotal)
+}
+
+// KeepSubtaskCounts copies the subtask counts read from Jira from the original
+// issues having the same key, since the counts are not exchanged as JSON,
+// like with the post-fetch hooks.
+func (i Issues) KeepSubtaskCounts(originals ...Issues) {
+	type counts struct{ total, done int }
+
+	countsOf := make(map[string]counts)
+	for _, original := range originals {
+		for _, issues := range original {
+			for _, issue := range issues {
+				countsOf[issue.Key] = counts{issue.subtasksTotal, issue.subtasksDone}
+			}
+		}
+	}
+
+	for status := range i {
+		for j := range i[status] {
+			c := countsOf[i[status][j].Key]
+			i[status][j].subtasksTotal, i[status][j].subtasksDone = c.total, c.done
+		}
+	}
+}
+
+// ArrangeSubtasks returns the issues with the subtasks arranged according to
+// the mode. The subtasks whose parent is not listed remain peers of the other
+// issues in every mode.
+func (i Issues) ArrangeSubtasks(mode SubtaskMode) Issues {
+	if mode == "" || mode == SubtasksFlat {
+		return i
+	}
+
+	listed := make(map[string]bool)
+	for _, issues := range i {
+		for _, issue := range issues {
+			listed[issue.Key] = true
+		}
+	}
+
+	subtasks := make(map[string][]Issue)
+	arranged := i.Filter(func(issue *Issue) bool {
+		if issue.Parent == "" || !listed[issue.Parent] {
+			return true
+		}
+
+		subtasks[issue.Parent] = append(subtasks[issue.Parent], *issue)
+		return false
+	})
+
+	for _, issues := range arranged {
+		for j := range issues {
+			issue := &issues[j]
+
+			if mode == SubtasksNest {
+				issue.Subtasks = subtasks[issue.Key]
+			} else {
+				issue.SubtasksTotal, issue.SubtasksDone = issue.subtasksTotal, issue.subtasksDone
+			}
+		}
+	}
+
+	return arranged
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Theme is an epic touched by the issues of the update, summarized in the
+// themes section.
+type Theme struct {
+	// Key is the key of the epic.
+	Key string
+	// Name is the name of the epic, or its key if the name is unknown.
+	Name string
+	// URL is the link to the epic.
+	URL string
+	// Issues is the number of the issues of the update under the epic.
+	Issues int
+	// Done is the number of the child issues of the epic in a status of the
+	// "done" category, regardless of their assignee.
+	Done int
+	// Total is the number of the child issues of the epic, regardless of
+	// their assignee. It is zero if the progress of the epic is unknown.
+	Total int
+}
+
+// DonePercentage returns the done child issues of the epic as the percentage
+// of its child issues, rounded to a whole number, like 60.
+func (t *Theme) DonePercentage() float64 {
+	if t.Total == 0 {
+		return 0
+	}
+
+	return math.Round(float64(t.Done) / float64(t.Total) * 100)
+}
+
+// Themes returns the distinct epics of the issues with the number of the
+// issues under them, the epics of the most issues first. The issues without
+// an epic are left out, and the issues listed in multiple statuses are
+// counted once.
+func (i Issues) Themes(serverURL string) []Theme {
+	counted := make(map[string]bool)
+	themes := make(map[string]*Theme)
+
+	var keys []string
+	for _, issues := range i {
+		for _, issue := range issues {
+			if issue.EpicKey == "" || counted[issue.Key] {
+				continue
+			}
+
+			counted[issue.Key] = true
+
+			theme, ok := themes[issue.EpicKey]
+			if !ok {
+				name := issue.Epic
+				if name == "" {
+					name = issue.EpicKey
+				}
+
+				theme = &Theme{
+					Key:  issue.EpicKey,
+					Name: name,
+					URL:  fmt.Sprintf("%s/browse/%s", serverURL, issue.EpicKey),
+				}
+				themes[issue.EpicKey] = theme
+				keys = append(keys, issue.EpicKey)
+			}
+
+			theme.Issues++
+		}
+	}
+
+	sorted := make([]Theme, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, *themes[key])
+	}
+
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if sorted[a].Issues != sorted[b].Issues {
+			return sorted[a].Issues > sorted[b].Issues
+		}
+
+		if sorted[a].Name != sorted[b].Name {
+			return sorted[a].Name < sorted[b].Name
+		}
+
+		return sorted[a].Key < sorted[b].Key
+	})
+
+	return sorted
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"sort"
+	"time"
+)
+
+// Timesheet is the summary of the hours logged within the sprint, read from
+// Tempo Timesheets.
+type Timesheet struct {
+	// Total is the total time logged on the issues of the update.
+	Total time.Duration
+	// Approved indicates that the timesheets of the sprint are approved.
+	Approved bool
+	// Unlogged lists the done issues without logged time, ordered by their
+	// keys.
+	Unlogged []Issue
+}
+
+// NewTimesheet returns the summary of the time logged on the issues.
+func NewTimesheet(issues Issues, approved bool) *Timesheet {
+	timesheet := &Timesheet{Approved: approved}
+
+	for _, statusIssues := range issues {
+		for _, issue := range statusIssues {
+			timesheet.Total += issue.TimeSpent
+
+			if issue.Done && issue.TimeSpent == 0 {
+				timesheet.Unlogged = append(timesheet.Unlogged, issue)
+			}
+		}
+	}
+
+	sort.Slice(timesheet.Unlogged, func(i, j int) bool {
+		return timesheet.Unlogged[i].Key < timesheet.Unlogged[j].Key
+	})
+
+	return timesheet
+}
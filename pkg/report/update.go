@@ -0,0 +1,454 @@
+package report
+
+import (
+	"time"
+
+	"gabor-boros/sprint-update/pkg/i18n"
+)
+
+// Update is the actual sprint update used as the input for the sprint
+// update template.
+type Update struct {
+	Title      string
+	Issues     Issues
+	Blocked    Issues
+	Spillovers Issues
+	// Dependencies lists the links of the issues to the issues of other
+	// projects, if the dependencies section is rendered.
+	Dependencies []Dependency
+	// Members lists the issues per team member in team mode.
+	Members []Member
+	// Projects lists the issues per project, if the worked on section is
+	// split by project. Team updates are split by member instead.
+	Projects []Project
+	// PullRequests lists the pull requests of the sprint, if a code host
+	// integration is enabled.
+	PullRequests []PullRequest
+	// Commits lists the commits of the user authored during the sprint,
+	// grouped by the issues they reference, if git repositories are
+	// scanned.
+	Commits []CommitGroup
+	// CarriedOver lists the issues left unresolved at the end of the previous
+	// sprint, grouped by their status at that time.
+	CarriedOver Issues
+	// CarriedOverFrom is the name of the previous sprint.
+	CarriedOverFrom string
+	// StatusOrder lists the statuses in the order they are rendered. The
+	// statuses not listed follow in alphabetical order.
+	StatusOrder []string
+	// GroupBy is the attribute the issues of the update are grouped by. When
+	// empty, the issues are grouped by status.
+	GroupBy GroupBy
+	// Kudos lists the kudos given to others. When empty, a placeholder is
+	// rendered.
+	Kudos []string
+	// SuggestedKudos lists the colleagues suggested for kudos based on their
+	// activity on the issues. Unlike Kudos, they are rendered as suggestions.
+	SuggestedKudos []KudosSuggestion
+	// TimeOff describes the planned time off. When empty, no time off is
+	// planned.
+	TimeOff string
+	// StoryPoints indicates that the story points of the issues are read,
+	// hence the totals are rendered.
+	StoryPoints bool
+	// Sprint is the name of the sprint the update is generated for.
+	Sprint string
+	// Assignee is the display name of the person the update is generated
+	// for. It is empty if the update is generated for the authenticated
+	// user.
+	Assignee string
+	// EndOfSprint indicates that the update is an end of sprint update.
+	EndOfSprint bool
+	// StartDate is the start date of the sprint. It is zero if unknown.
+	StartDate time.Time
+	// EndDate is the end date of the sprint. It is zero if unknown.
+	EndDate time.Time
+	// DaysRemaining is the number of days left until the end of the sprint.
+	DaysRemaining int
+	// Stats is the velocity of the sprint in end of sprint updates. It is
+	// nil if the sprint report of the board is not read.
+	Stats *Stats
+	// CycleTime is the cycle time of the done issues in end of sprint
+	// updates. It is nil if the timeline of the issues is not read.
+	CycleTime *CycleTime
+	// Themes lists the epics touched by the issues, if the themes section is
+	// rendered.
+	Themes []Theme
+	// Summary is the narrative summary of the update written by a language
+	// model, if the summary section is rendered.
+	Summary string
+	// Timesheet is the summary of the hours logged in Tempo. It is nil if
+	// Tempo is not used.
+	Timesheet *Timesheet
+	// StatusEmojis maps the statuses and display groups to the emojis
+	// returned by Emoji, overriding the default emojis.
+	StatusEmojis map[string]string
+	// Decorated indicates that the statuses are decorated with their emojis,
+	// like in the decorated format.
+	Decorated bool
+	// Language is the language the headings and the messages of the built-in
+	// templates are translated to by T. When empty, i18n.DefaultLanguage is
+	// used.
+	Language string
+	// Sections lists the sections of the body in the order they are rendered
+	// by the built-in templates. When empty, DefaultSections are rendered.
+	Sections []Section
+	// SectionTitles maps the sections to the titles replacing their default
+	// headings, returned by Heading.
+	SectionTitles map[string]string
+	// ExpandedGroups lists the groups whose collapsible blocks are expanded
+	// by default.
+	ExpandedGroups []string
+	// DetailsThreshold is the number of issues up to which the groups are
+	// listed without collapsible blocks. When zero, every group is wrapped.
+	DetailsThreshold int
+	// PlainFormats lists the formats rendering the groups without
+	// collapsible blocks, like the formats not supporting them.
+	PlainFormats []string
+	// MaxItems maps the groups, matched case-insensitively, to the maximum
+	// number of issues listed in them; the rest of the issues are folded
+	// into a link. The "*" key sets the maximum of the other groups.
+	MaxItems map[string]int
+	// FoldBy is the attribute choosing the issues listed in the groups
+	// having more issues than their maximum. When empty, the issues listed
+	// first are kept.
+	FoldBy FoldBy
+	// Provenance describes how the update was generated, rendered in its
+	// footer. It is nil if the footer is not rendered.
+	Provenance *Provenance
+	// Warnings lists the problems met while building the update that did not
+	// stop it, like an optional section left out as it could not be read.
+	Warnings []string
+}
+
+// T returns the translation of the English heading or message of the built-in
+// templates to the language of the update, formatted with the arguments, like
+// {{ $.T "Carried over from %s" .CarriedOverFrom }}.
+func (u *Update) T(message string, args ...interface{}) string {
+	return i18n.Translate(u.Language, message, args...)
+}
+
+// TimeOffText returns the planned time off, or the translation of the
+// message telling that no time off is planned.
+func (u *Update) TimeOffText() string {
+	if u.TimeOff == "" {
+		return u.T("I did not plan any time off.")
+	}
+
+	return u.TimeOff
+}
+
+// Emoji returns the emoji of the status or display group, which is the
+// configured emoji of the status, or its default emoji, like "✅" for
+// "Done".
+func (u *Update) Emoji(status string) string {
+	return StatusEmoji(status, u.StatusEmojis)
+}
+
+// Diff annotates the issues of the update, including the issues of the team
+// members, with their changes compared to the issues of the previous update.
+func (u *Update) Diff(previous Issues) {
+	u.Issues.Diff(previous)
+	u.Blocked.Diff(previous)
+	u.Spillovers.Diff(previous)
+
+	for i := range u.Members {
+		u.Members[i].Issues.Diff(previous)
+	}
+
+	for i := range u.Projects {
+		u.Projects[i].Issues.Diff(previous)
+	}
+}
+
+// CommittedPoints returns the total story points of the issues.
+func (u *Update) CommittedPoints() float64 {
+	return u.Issues.StoryPoints()
+}
+
+// DonePoints returns the total story points of the done issues.
+func (u *Update) DonePoints() float64 {
+	return u.Issues.Filter(func(issue *Issue) bool {
+		return issue.Done
+	}).StoryPoints()
+}
+
+// Groups returns the given issues grouped by the grouping of the update,
+// using the status order of the update. The groups of statuses are decorated
+// with their emojis, the groups having more issues than their maximum are
+// folded, and every group is marked as collapsible or expanded according to
+// the details options of the update.
+func (u *Update) Groups(issues Issues) []StatusGroup {
+	groups := u.AllGroups(issues)
+	u.fold(groups)
+	u.setDetails(groups)
+
+	return groups
+}
+
+// AllGroups returns the given issues grouped like Groups, without folding
+// the issues of any group, for listing every issue, like when reviewing or
+// exporting the update.
+func (u *Update) AllGroups(issues Issues) []StatusGroup {
+	groups := issues.GroupsBy(u.GroupBy, u.StatusOrder)
+	for i := range groups {
+		if groups[i].Status != "" {
+			groups[i].Emoji = u.Emoji(groups[i].Name)
+		}
+
+		groups[i].SearchURL = searchURL(withSubtasks(groups[i].Issues))
+	}
+
+	u.setDetails(groups)
+
+	return groups
+}
+
+// sections returns every issue grouping of the update.
+func (u *Update) sections() []*Issues {
+	sections := []*Issues{&u.Issues, &u.Blocked, &u.Spillovers, &u.CarriedOver}
+
+	for i := range u.Members {
+		sections = append(sections, &u.Members[i].Issues)
+	}
+
+	for i := range u.Projects {
+		sections = append(sections, &u.Projects[i].Issues)
+	}
+
+	return sections
+}
+
+// SetCarriedOver sets the issues left unresolved at the end of the previous
+// sprint, leaving out the issues listed as spillovers already, so no issue is
+// listed in both sections.
+func (u *Update) SetCarriedOver(sprintName string, issues Issues) {
+	spillovers := make(map[string]bool)
+	for _, statusIssues := range u.Spillovers {
+		for j := range statusIssues {
+			spillovers[statusIssues[j].Key] = true
+		}
+	}
+
+	carriedOver := issues.Filter(func(issue *Issue) bool {
+		return !spillovers[issue.Key]
+	})
+
+	if len(carriedOver) > 0 {
+		u.CarriedOver = carriedOver
+		u.CarriedOverFrom = sprintName
+	}
+}
+
+// Remove excludes the issue having the given key from every section of the
+// update.
+func (u *Update) Remove(key string) {
+	for _, section := range u.sections() {
+		*section = section.Remove(key)
+	}
+}
+
+// SetSummary changes the summary of the issue having the given key in every
+// section of the update.
+func (u *Update) SetSummary(key string, summary string) {
+	for _, section := range u.sections() {
+		section.Update(key, func(issue *Issue) {
+			issue.Summary = summary
+		})
+	}
+}
+
+// SetBlockedReason changes the blocked reason of the issue having the given
+// key in every section of the update.
+func (u *Update) SetBlockedReason(key string, reason string) {
+	for _, section := range u.sections() {
+		section.Update(key, func(issue *Issue) {
+			issue.BlockedReason = reason
+		})
+	}
+}
+
+// SetSpilloverReason changes the spillover reason of the issue having the
+// given key in the spillovers of the update.
+func (u *Update) SetSpilloverReason(key string, reason string) {
+	u.Spillovers.Update(key, func(issue *Issue) {
+		issue.SpilloverReason = Truncate(reason, spilloverReasonLength)
+	})
+}
+
+// Member is a team member of a team update.
+type Member struct {
+	// Name is the display name of the member.
+	Name string
+	// Issues are the issues of the member grouped by status.
+	Issues Issues
+}
+
+// NewMember returns a new Member with the given issues. The name of the
+// member is the display name of the issues' assignee, falling back to the
+// given name if the member has no issues.
+func NewMember(name string, issues Issues) Member {
+	for _, statusIssues := range issues {
+		if len(statusIssues) > 0 && statusIssues[0].Assignee != "" {
+			name = statusIssues[0].Assignee
+			break
+		}
+	}
+
+	return Member{
+		Name:   name,
+		Issues: issues,
+	}
+}
+
+// Options configures how the sections of the Update are assembled.
+type Options struct {
+	// Sprint is the name of the sprint the update is generated for.
+	Sprint string
+	// Sprints lists the other sprints covered by a consolidated update. Their
+	// issues are not considered as carried over.
+	Sprints []string
+	// Period indicates that the update covers a date range instead of
+	// sprints, hence no issue is considered as carried over.
+	Period bool
+	// EndOfSprint indicates that an end of sprint update is generated.
+	EndOfSprint bool
+	// BlockedStatuses lists the statuses considered as blocked.
+	BlockedStatuses []string
+	// BlockedLabels lists the labels marking the issues as blocked.
+	BlockedLabels []string
+	// StatusGroups maps the display groups to the statuses they consist of.
+	// The statuses not mapped are displayed as they are.
+	StatusGroups map[string][]string
+	// StatusOrder lists the statuses or display groups in the order they are
+	// rendered.
+	StatusOrder []string
+	// HiddenStatuses lists the statuses or display groups left out of the
+	// update.
+	HiddenStatuses []string
+	// StoryPoints indicates that the story points of the issues are read.
+	StoryPoints bool
+	// SummaryLength is the number of characters the summaries are truncated
+	// to. When zero, the summaries are not truncated.
+	SummaryLength int
+	// Subtasks is the way the subtasks are listed. When empty, the subtasks
+	// are listed as peers of their parents.
+	Subtasks SubtaskMode
+	// GroupBy is the attribute the issues are grouped by. When empty, the
+	// issues are grouped by status.
+	GroupBy GroupBy
+	// SortBy is the attribute the issues are sorted by within their groups.
+	// When empty, the issues are sorted by their rank.
+	SortBy SortBy
+	// Priorities lists the priorities from the highest to the lowest, used
+	// when sorting by priority. When empty, DefaultPriorities is used.
+	Priorities []string
+	// StatusEmojis maps the statuses and display groups to their emojis.
+	StatusEmojis map[string]string
+	// Decorated indicates that the statuses are decorated with their emojis.
+	Decorated bool
+	// Annotations lists the annotations of the issues in the order they are
+	// rendered.
+	Annotations []Annotation
+	// Language is the language of the headings and the messages.
+	Language string
+	// Sections lists the sections of the body in the order they are
+	// rendered. When empty, DefaultSections are rendered.
+	Sections []Section
+	// SectionTitles maps the sections to the titles replacing their default
+	// headings.
+	SectionTitles map[string]string
+	// SplitByProject indicates that the worked on section lists the issues
+	// per project, unless the update is a team update.
+	SplitByProject bool
+	// ExpandedGroups lists the groups expanded by default.
+	ExpandedGroups []string
+	// DetailsThreshold is the number of issues up to which the groups are
+	// listed without collapsible blocks.
+	DetailsThreshold int
+	// PlainFormats lists the formats rendering the groups without
+	// collapsible blocks.
+	PlainFormats []string
+	// MaxItems maps the groups to the maximum number of issues listed in
+	// them.
+	MaxItems map[string]int
+	// FoldBy is the attribute choosing the issues listed in the groups
+	// having more issues than their maximum.
+	FoldBy FoldBy
+}
+
+// spillovers returns the spillover issues of the sprints covered by the
+// update. Updates covering a date range have no carried over issues.
+func spillovers(issues Issues, opts Options) Issues {
+	if len(opts.Sprints) == 0 && !opts.Period {
+		return issues.Spillovers(opts.Sprint, opts.EndOfSprint)
+	}
+
+	sprintNames := append([]string{opts.Sprint}, opts.Sprints...)
+
+	return issues.Filter(func(issue *Issue) bool {
+		return (!opts.Period && issue.IsCarriedOver(sprintNames...)) || (opts.EndOfSprint && !issue.Done)
+	})
+}
+
+// NewUpdate returns a new Update assembling the sections from the issues. In
+// team mode, the members are listed in the given order.
+func NewUpdate(title string, issues Issues, members []Member, opts Options) *Update {
+	regroup := func(issues Issues) Issues {
+		regrouped := issues.Regroup(opts.StatusGroups, opts.HiddenStatuses)
+		regrouped.Truncate(opts.SummaryLength)
+		regrouped.Sort(opts.SortBy, opts.Priorities)
+		return regrouped
+	}
+
+	for i := range members {
+		members[i].Issues = regroup(members[i].Issues).ArrangeSubtasks(opts.Subtasks)
+	}
+
+	issues = regroup(issues)
+
+	// The blocked issues and spillovers are listed regardless of being
+	// subtasks, hence they are derived before arranging the subtasks.
+	update := &Update{
+		Title:            title,
+		Sprint:           opts.Sprint,
+		EndOfSprint:      opts.EndOfSprint,
+		Issues:           issues.ArrangeSubtasks(opts.Subtasks),
+		Blocked:          issues.Blocked(opts.BlockedStatuses, opts.BlockedLabels),
+		Spillovers:       spillovers(issues, opts),
+		Members:          members,
+		StatusOrder:      opts.StatusOrder,
+		GroupBy:          opts.GroupBy,
+		StoryPoints:      opts.StoryPoints,
+		StatusEmojis:     opts.StatusEmojis,
+		Decorated:        opts.Decorated,
+		Language:         opts.Language,
+		Sections:         opts.Sections,
+		SectionTitles:    opts.SectionTitles,
+		ExpandedGroups:   opts.ExpandedGroups,
+		DetailsThreshold: opts.DetailsThreshold,
+		PlainFormats:     opts.PlainFormats,
+		MaxItems:         opts.MaxItems,
+		FoldBy:           opts.FoldBy,
+	}
+
+	if opts.SplitByProject && len(members) == 0 {
+		update.Projects = update.Issues.Projects()
+	}
+
+	// The dependencies of the subtasks are listed too, hence they are derived
+	// before arranging the subtasks.
+	if HasSection(opts.Sections, SectionDependencies) {
+		update.Dependencies = issues.Dependencies()
+		for i := range update.Dependencies {
+			update.Dependencies[i].Link.Summary = Truncate(update.Dependencies[i].Link.Summary, opts.SummaryLength)
+		}
+	}
+
+	now := time.Now()
+	for _, section := range update.sections() {
+		section.Annotate(opts.Annotations, now)
+	}
+
+	return update
+}
@@ -0,0 +1,250 @@
+// Package review implements the interactive review of sprint updates, so
+// issues can be excluded, summaries edited, statuses reordered, the kudos
+// suggestions accepted, and the kudos and time off filled in before rendering.
+package review
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// ErrAborted is returned when the review is aborted.
+var ErrAborted = errors.New("review aborted")
+
+// help lists the commands of the review.
+const help = `Commands:
+  l                    list the issues
+  x <n>                exclude issue n from the update, or include it again
+  e <n> <summary>      edit the summary of issue n
+  o <status>, ...      order the statuses (ex: o In Progress, Done)
+  k <kudos>            add kudos
+  ka <n> [kudos]       accept suggested kudos n, optionally rewording it
+  kx <n>               dismiss suggested kudos n
+  t <time off>         set the time off
+  h                    show this help
+  d                    finish the review and render the update
+  q                    abort`
+
+// reviewer holds the state of the review.
+type reviewer struct {
+	out    io.Writer
+	update *report.Update
+	// keys lists the issue keys in the order they were last listed.
+	keys      []string
+	excluded  map[string]bool
+	summaries map[string]string
+}
+
+// Run reviews the update interactively, reading the commands from in and
+// writing the prompts to out. The update is modified in place once the review
+// is finished, either by the "d" command or by reaching the end of the input.
+func Run(in io.Reader, out io.Writer, update *report.Update) error {
+	r := &reviewer{
+		out:       out,
+		update:    update,
+		excluded:  make(map[string]bool),
+		summaries: make(map[string]string),
+	}
+
+	fmt.Fprintln(out, help)
+	r.list()
+
+	scanner := bufio.NewScanner(in)
+
+	for {
+		fmt.Fprint(out, "> ")
+
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(out)
+			break
+		}
+
+		command, arg := splitCommand(scanner.Text())
+
+		if command == "d" {
+			break
+		}
+
+		if command == "q" {
+			return ErrAborted
+		}
+
+		if err := r.run(command, arg); err != nil {
+			fmt.Fprintln(out, "Error:", err)
+		}
+	}
+
+	r.apply()
+	return nil
+}
+
+// splitCommand splits the input line into the command and its argument.
+func splitCommand(line string) (string, string) {
+	line = strings.TrimSpace(line)
+
+	i := strings.IndexAny(line, " \t")
+	if i < 0 {
+		return line, ""
+	}
+
+	return line[:i], strings.TrimSpace(line[i+1:])
+}
+
+// run executes the given command.
+func (r *reviewer) run(command string, arg string) error {
+	switch command {
+	case "":
+		return nil
+	case "h":
+		fmt.Fprintln(r.out, help)
+		return nil
+	case "l":
+		r.list()
+		return nil
+	case "x":
+		key, err := r.lookup(arg)
+		if err != nil {
+			return err
+		}
+
+		r.excluded[key] = !r.excluded[key]
+	case "e":
+		n, summary := splitCommand(arg)
+		if summary == "" {
+			return errors.New("summary is required")
+		}
+
+		key, err := r.lookup(n)
+		if err != nil {
+			return err
+		}
+
+		r.summaries[key] = summary
+	case "o":
+		var order []string
+		for _, status := range strings.Split(arg, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				order = append(order, status)
+			}
+		}
+
+		r.update.StatusOrder = order
+	case "k":
+		if arg == "" {
+			return errors.New("kudos is required")
+		}
+
+		r.update.Kudos = append(r.update.Kudos, arg)
+		r.listKudos()
+		return nil
+	case "ka", "kx":
+		n, kudos := splitCommand(arg)
+
+		i, err := strconv.Atoi(n)
+		if err != nil || i < 1 || i > len(r.update.SuggestedKudos) {
+			return fmt.Errorf("invalid suggestion number: %s", n)
+		}
+
+		if command == "ka" {
+			if kudos == "" {
+				kudos = r.update.SuggestedKudos[i-1].String()
+			}
+
+			r.update.Kudos = append(r.update.Kudos, kudos)
+		}
+
+		r.update.SuggestedKudos = append(r.update.SuggestedKudos[:i-1], r.update.SuggestedKudos[i:]...)
+		r.listKudos()
+		return nil
+	case "t":
+		r.update.TimeOff = arg
+		return nil
+	default:
+		return fmt.Errorf("unknown command: %s", command)
+	}
+
+	r.list()
+	return nil
+}
+
+// lookup returns the key of the issue having the given number in the last
+// listing.
+func (r *reviewer) lookup(n string) (string, error) {
+	i, err := strconv.Atoi(n)
+	if err != nil || i < 1 || i > len(r.keys) {
+		return "", fmt.Errorf("invalid issue number: %s", n)
+	}
+
+	return r.keys[i-1], nil
+}
+
+// list prints the issues grouped the same way as in the update, marking the
+// excluded issues.
+func (r *reviewer) list() {
+	r.keys = r.keys[:0]
+
+	for _, group := range r.update.AllGroups(r.update.Issues) {
+		fmt.Fprintf(r.out, "\n%s\n", group.Name)
+
+		for _, issue := range group.Issues {
+			r.keys = append(r.keys, issue.Key)
+
+			mark := "x"
+			if r.excluded[issue.Key] {
+				mark = " "
+			}
+
+			summary, ok := r.summaries[issue.Key]
+			if !ok {
+				summary = issue.Summary
+			}
+
+			fmt.Fprintf(r.out, "  %2d. [%s] %s - %s\n", len(r.keys), mark, issue.Key, summary)
+		}
+	}
+
+	fmt.Fprintln(r.out)
+	r.listKudos()
+}
+
+// listKudos prints the kudos and the numbered kudos suggestions, if any.
+func (r *reviewer) listKudos() {
+	if len(r.update.Kudos) == 0 && len(r.update.SuggestedKudos) == 0 {
+		return
+	}
+
+	fmt.Fprintln(r.out, "Kudos")
+
+	for _, kudos := range r.update.Kudos {
+		fmt.Fprintf(r.out, "        %s\n", kudos)
+	}
+
+	for i, suggestion := range r.update.SuggestedKudos {
+		fmt.Fprintf(r.out, "  %2d. [?] %s\n", i+1, suggestion)
+	}
+
+	fmt.Fprintln(r.out)
+}
+
+// apply applies the exclusions and the edited summaries to the update.
+func (r *reviewer) apply() {
+	for key, excluded := range r.excluded {
+		if excluded {
+			r.update.Remove(key)
+		}
+	}
+
+	for key, summary := range r.summaries {
+		r.update.SetSummary(key, summary)
+	}
+}
@@ -0,0 +1,206 @@
+package review
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// newUpdate returns an update of issues in two statuses, having two kudos
+// suggestions.
+func newUpdate() *report.Update {
+	update := report.NewUpdate("Sprint update", report.Issues{
+		"In Progress": {
+			{Key: "SE-1", Summary: "Add the review", Status: "In Progress"},
+			{Key: "SE-2", Summary: "Fix the login", Status: "In Progress"},
+		},
+		"Done": {
+			{Key: "SE-3", Summary: "Write the docs", Status: "Done"},
+		},
+	}, nil, report.Options{})
+
+	update.SuggestedKudos = []report.KudosSuggestion{
+		{Name: "Jane Doe", Commented: []string{"SE-1"}},
+		{Name: "John Doe", Unblocked: []string{"SE-2"}},
+	}
+
+	return update
+}
+
+// number returns the number of the issue having the given key in the listing
+// of the review.
+func number(t *testing.T, update *report.Update, key string) int {
+	t.Helper()
+
+	n := 0
+	for _, group := range update.AllGroups(update.Issues) {
+		for _, issue := range group.Issues {
+			n++
+			if issue.Key == key {
+				return n
+			}
+		}
+	}
+
+	t.Fatalf("%s is not listed", key)
+	return 0
+}
+
+// summaries returns the summaries of the issues of the update by their keys.
+func summaries(update *report.Update) map[string]string {
+	summaries := make(map[string]string)
+	for _, issues := range update.Issues {
+		for _, issue := range issues {
+			summaries[issue.Key] = issue.Summary
+		}
+	}
+
+	return summaries
+}
+
+func TestRun(t *testing.T) {
+	update := newUpdate()
+	input := strings.Join([]string{
+		fmt.Sprintf("x %d", number(t, update, "SE-2")),
+		fmt.Sprintf("e %d Document the review", number(t, update, "SE-3")),
+		"k Thanks to Jim for the pairing",
+		"ka 2",
+		"kx 1",
+		"t Jane is off on Friday",
+		"o Done, In Progress",
+		"d",
+		"x 1",
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := Run(strings.NewReader(input), &out, update); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	wantSummaries := map[string]string{"SE-1": "Add the review", "SE-3": "Document the review"}
+	if got := summaries(update); !reflect.DeepEqual(got, wantSummaries) {
+		t.Errorf("summaries = %v, want %v", got, wantSummaries)
+	}
+
+	if want := []string{"Done", "In Progress"}; !reflect.DeepEqual(update.StatusOrder, want) {
+		t.Errorf("StatusOrder = %v, want %v", update.StatusOrder, want)
+	}
+
+	wantKudos := []string{"Thanks to Jim for the pairing", report.KudosSuggestion{Name: "John Doe", Unblocked: []string{"SE-2"}}.String()}
+	if !reflect.DeepEqual(update.Kudos, wantKudos) {
+		t.Errorf("Kudos = %q, want %q", update.Kudos, wantKudos)
+	}
+
+	if len(update.SuggestedKudos) != 0 {
+		t.Errorf("SuggestedKudos = %v, want none", update.SuggestedKudos)
+	}
+
+	if update.TimeOff != "Jane is off on Friday" {
+		t.Errorf("TimeOff = %q, want %q", update.TimeOff, "Jane is off on Friday")
+	}
+}
+
+func TestRunIncludeAgain(t *testing.T) {
+	update := newUpdate()
+	n := number(t, update, "SE-1")
+
+	var out bytes.Buffer
+	if err := Run(strings.NewReader(fmt.Sprintf("x %d\nx %d\nd\n", n, n)), &out, update); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if _, ok := summaries(update)["SE-1"]; !ok {
+		t.Error("SE-1 is excluded after including it again")
+	}
+}
+
+func TestRunAccept(t *testing.T) {
+	update := newUpdate()
+
+	var out bytes.Buffer
+	if err := Run(strings.NewReader("ka 1 Thanks Jane for the reviews\nd\n"), &out, update); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if want := []string{"Thanks Jane for the reviews"}; !reflect.DeepEqual(update.Kudos, want) {
+		t.Errorf("Kudos = %q, want %q", update.Kudos, want)
+	}
+
+	if len(update.SuggestedKudos) != 1 || update.SuggestedKudos[0].Name != "John Doe" {
+		t.Errorf("SuggestedKudos = %v, want the suggestion of John Doe", update.SuggestedKudos)
+	}
+}
+
+func TestRunEndOfInput(t *testing.T) {
+	update := newUpdate()
+	n := number(t, update, "SE-1")
+
+	var out bytes.Buffer
+	if err := Run(strings.NewReader(fmt.Sprintf("x %d\ne %d Renamed", n, number(t, update, "SE-3"))), &out, update); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	want := map[string]string{"SE-2": "Fix the login", "SE-3": "Renamed"}
+	if got := summaries(update); !reflect.DeepEqual(got, want) {
+		t.Errorf("summaries = %v, want %v", got, want)
+	}
+}
+
+func TestRunAbort(t *testing.T) {
+	update := newUpdate()
+	n := number(t, update, "SE-1")
+	input := fmt.Sprintf("x %d\ne %d Renamed\nq\nd\n", n, n)
+
+	var out bytes.Buffer
+	if err := Run(strings.NewReader(input), &out, update); !errors.Is(err, ErrAborted) {
+		t.Fatalf("Run() error = %v, want %v", err, ErrAborted)
+	}
+
+	want := summaries(newUpdate())
+	if got := summaries(update); !reflect.DeepEqual(got, want) {
+		t.Errorf("summaries = %v, want %v", got, want)
+	}
+}
+
+func TestRunInvalidCommands(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"unknown command":       {input: "z", want: "Error: unknown command: z"},
+		"issue number too high": {input: "x 4", want: "Error: invalid issue number: 4"},
+		"not an issue number":   {input: "x one", want: "Error: invalid issue number: one"},
+		"missing summary":       {input: "e 1", want: "Error: summary is required"},
+		"missing kudos":         {input: "k", want: "Error: kudos is required"},
+		"unknown suggestion":    {input: "ka 3", want: "Error: invalid suggestion number: 3"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			update := newUpdate()
+			n := number(t, update, "SE-1")
+
+			var out bytes.Buffer
+			if err := Run(strings.NewReader(fmt.Sprintf("%s\nx %d\nd\n", tt.input, n)), &out, update); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("the output does not contain %q:\n%s", tt.want, out.String())
+			}
+
+			if _, ok := summaries(update)["SE-1"]; ok {
+				t.Error("the review did not continue after the invalid command")
+			}
+
+			if len(update.Kudos) != 0 || len(update.SuggestedKudos) != 2 {
+				t.Errorf("the invalid command changed the kudos: %q, %v", update.Kudos, update.SuggestedKudos)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// Package rollup aggregates the archived sprint updates of a month or a
+// quarter into a summary of the completed work grouped by epic, like the
+// summaries written for performance reviews.
+package rollup
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/history"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// periodDateLayout is the layout of the dates of the exported periods.
+const periodDateLayout = "2006-01-02"
+
+// NoEpic is the name of the group of the completed issues without an epic.
+const NoEpic = "No epic"
+
+// ErrInvalidPeriod is returned when the month or the quarter cannot be
+// parsed.
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// Period is the month or the quarter covered by the rollup.
+type Period struct {
+	// Name is the name of the period, like "Q3 2026" or "September 2026".
+	Name string
+	// Start is the start of the period.
+	Start time.Time
+	// End is the end of the period, which is the start of the next period.
+	End time.Time
+}
+
+// Contains reports whether the time is within the period.
+func (p *Period) Contains(t time.Time) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// Month returns the period of the month, like "2026-09".
+func Month(value string) (Period, error) {
+	start, err := time.ParseInLocation("2006-01", value, time.Local)
+	if err != nil {
+		return Period{}, fmt.Errorf("%w: %s (expected YYYY-MM)", ErrInvalidPeriod, value)
+	}
+
+	return Period{Name: start.Format("January 2006"), Start: start, End: start.AddDate(0, 1, 0)}, nil
+}
+
+// Quarter returns the period of the quarter, like "2026-Q3".
+func Quarter(value string) (Period, error) {
+	parts := strings.SplitN(strings.ToUpper(value), "-Q", 2)
+	if len(parts) != 2 {
+		return Period{}, fmt.Errorf("%w: %s (expected YYYY-QN)", ErrInvalidPeriod, value)
+	}
+
+	year, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return Period{}, fmt.Errorf("%w: %s (expected YYYY-QN)", ErrInvalidPeriod, value)
+	}
+
+	quarter, err := strconv.Atoi(parts[1])
+	if err != nil || quarter < 1 || quarter > 4 {
+		return Period{}, fmt.Errorf("%w: %s (the quarter must be between 1 and 4)", ErrInvalidPeriod, value)
+	}
+
+	start := time.Date(year, time.Month(3*(quarter-1)+1), 1, 0, 0, 0, 0, time.Local)
+	return Period{Name: fmt.Sprintf("Q%d %d", quarter, year), Start: start, End: start.AddDate(0, 3, 0)}, nil
+}
+
+// CurrentQuarter returns the period of the quarter the time is in.
+func CurrentQuarter(now time.Time) Period {
+	period, _ := Quarter(fmt.Sprintf("%d-Q%d", now.Year(), (int(now.Month())-1)/3+1))
+	return period
+}
+
+// Rollup is the summary of the completed work of a period.
+type Rollup struct {
+	Title string `json:"title" yaml:"title"`
+	// Period is the name of the period, like "Q3 2026".
+	Period string `json:"period" yaml:"period"`
+	// StartDate and EndDate are the first and the last day of the period,
+	// like "2026-07-01" and "2026-09-30".
+	StartDate string `json:"start_date" yaml:"start_date"`
+	EndDate   string `json:"end_date" yaml:"end_date"`
+	// Sprints lists the sprints covered by the rollup, in the order of their
+	// updates.
+	Sprints []string `json:"sprints" yaml:"sprints"`
+	// Completed is the number of the completed issues.
+	Completed int `json:"completed" yaml:"completed"`
+	// StoryPoints is the total of the story points of the completed issues.
+	StoryPoints float64 `json:"story_points,omitempty" yaml:"story_points,omitempty"`
+	// Epics lists the completed issues grouped by epic, in the alphabetical
+	// order of the epics. The issues without an epic are listed last.
+	Epics []Epic `json:"epics" yaml:"epics"`
+}
+
+// Epic is an epic of the rollup and its completed issues.
+type Epic struct {
+	// Key is the key of the epic. It is empty for the issues without an
+	// epic.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+	// Name is the name of the epic, or its key if the name is unknown.
+	Name string `json:"name" yaml:"name"`
+	// Completed is the number of the completed issues of the epic.
+	Completed int `json:"completed" yaml:"completed"`
+	// StoryPoints is the total of the story points of the completed issues
+	// of the epic.
+	StoryPoints float64 `json:"story_points,omitempty" yaml:"story_points,omitempty"`
+	// Issues lists the completed issues of the epic, in the order of the
+	// sprints they were completed in.
+	Issues []Issue `json:"issues" yaml:"issues"`
+}
+
+// Issue is a completed issue of the rollup.
+type Issue struct {
+	Key         string  `json:"key" yaml:"key"`
+	Summary     string  `json:"summary" yaml:"summary"`
+	URL         string  `json:"url" yaml:"url"`
+	StoryPoints float64 `json:"story_points,omitempty" yaml:"story_points,omitempty"`
+	// Sprint is the sprint the issue was completed in.
+	Sprint string `json:"sprint" yaml:"sprint"`
+}
+
+// New returns the rollup of the completed issues of the archived updates.
+// The entries are expected to be the final updates of the sprints, ordered
+// by their creation. An issue listed by multiple updates is counted once.
+func New(period Period, entries []history.Entry) *Rollup {
+	rollup := &Rollup{
+		Title:     period.Name + " rollup",
+		Period:    period.Name,
+		StartDate: period.Start.Format(periodDateLayout),
+		EndDate:   period.End.AddDate(0, 0, -1).Format(periodDateLayout),
+		Sprints:   []string{},
+		Epics:     []Epic{},
+	}
+
+	completed := make(map[string]bool)
+	epics := make(map[string]*Epic)
+
+	for _, entry := range entries {
+		rollup.Sprints = append(rollup.Sprints, entry.Sprint)
+
+		for _, issue := range doneIssues(entry.Issues) {
+			if completed[issue.Key] {
+				continue
+			}
+
+			completed[issue.Key] = true
+
+			epic, ok := epics[issue.EpicKey]
+			if !ok {
+				epic = newEpic(&issue)
+				epics[issue.EpicKey] = epic
+			}
+
+			epic.Completed++
+			epic.StoryPoints += issue.StoryPoints
+			epic.Issues = append(epic.Issues, Issue{
+				Key:         issue.Key,
+				Summary:     issue.Summary,
+				URL:         issue.URL,
+				StoryPoints: issue.StoryPoints,
+				Sprint:      entry.Sprint,
+			})
+
+			rollup.Completed++
+			rollup.StoryPoints += issue.StoryPoints
+		}
+	}
+
+	for _, epic := range epics {
+		rollup.Epics = append(rollup.Epics, *epic)
+	}
+
+	sort.Slice(rollup.Epics, func(i, j int) bool {
+		if (rollup.Epics[i].Key == "") != (rollup.Epics[j].Key == "") {
+			return rollup.Epics[j].Key == ""
+		}
+
+		return rollup.Epics[i].Name < rollup.Epics[j].Name
+	})
+
+	return rollup
+}
+
+// Load returns the rollup of the sprints archived in the history directory,
+// whose final update was generated within the period.
+func Load(dir string, period Period) (*Rollup, error) {
+	sprints, err := history.Sprints(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []history.Entry
+	for _, sprint := range sprints {
+		entry, err := history.Final(dir, sprint)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil && period.Contains(entry.Created) {
+			entries = append(entries, *entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Created.Before(entries[j].Created)
+	})
+
+	return New(period, entries), nil
+}
+
+// newEpic returns the epic of the issue.
+func newEpic(issue *report.Issue) *Epic {
+	switch {
+	case issue.EpicKey == "":
+		return &Epic{Name: NoEpic}
+	case issue.Epic == "":
+		return &Epic{Key: issue.EpicKey, Name: issue.EpicKey}
+	default:
+		return &Epic{Key: issue.EpicKey, Name: issue.Epic}
+	}
+}
+
+// doneIssues returns the done issues, ordered by their keys, so the rollup
+// does not depend on the order of the statuses.
+func doneIssues(issues report.Issues) []report.Issue {
+	var done []report.Issue
+	for _, statusIssues := range issues {
+		for _, issue := range statusIssues {
+			if issue.Done {
+				done = append(done, issue)
+			}
+		}
+	}
+
+	sort.Slice(done, func(i, j int) bool {
+		return done[i].Key < done[j].Key
+	})
+
+	return done
+}
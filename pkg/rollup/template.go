@@ -0,0 +1,22 @@
+package rollup
+
+// DefaultTemplate is a Markdown template used for rendering the rollups.
+const DefaultTemplate string = `## {{ escape .Title }}
+
+{{ .StartDate }} – {{ .EndDate }}
+{{- if .Sprints }}, sprints: {{ escape (join .Sprints ", ") }}{{ end }}
+
+**Completed issues:** {{ .Completed }}{{ if .StoryPoints }}, **story points:** {{ points .StoryPoints }}{{ end }}
+{{- range $epic := .Epics }}
+
+### {{ escape $epic.Name }}{{ if $epic.Key }} ({{ $epic.Key }}){{ end }}
+
+Completed issues: {{ $epic.Completed }}{{ if $epic.StoryPoints }}, story points: {{ points $epic.StoryPoints }}{{ end }}
+{{ range $issue := $epic.Issues }}
+- [{{ $issue.Key }}]({{ $issue.URL }}) - {{ escape $issue.Summary }} ({{ escape $issue.Sprint }}{{ if $issue.StoryPoints }}, {{ points $issue.StoryPoints }} pts{{ end }})
+{{- end }}
+{{- else }}
+
+No completed issues found in the archived updates.
+{{- end }}
+`
@@ -0,0 +1,253 @@
+// Package schedule parses cron expressions, like "0 10 * * WED", and finds
+// the times they match, so the updates can be generated and delivered on a
+// schedule.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidExpression is returned when a cron expression cannot be parsed.
+var ErrInvalidExpression = errors.New("invalid cron expression")
+
+// maxLookahead is how far the next matching time is looked for, which covers
+// the expressions matching only in leap years, like "0 0 29 2 *".
+const maxLookahead = 5
+
+// macros are the shorthands of the common expressions.
+var macros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// monthNames and dayNames are the names usable instead of the numbers of the
+// months and the days of the week.
+var (
+	monthNames = []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	dayNames   = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+)
+
+// field describes a field of the expressions.
+type field struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	dayField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: monthNames}
+	// weekdayField allows 7 as Sunday too, like most cron implementations.
+	weekdayField = field{name: "day of week", min: 0, max: 7, names: dayNames}
+)
+
+// Expression is a parsed cron expression of five fields: the minute, the
+// hour, the day of the month, the month, and the day of the week. The fields
+// accept lists, ranges, and steps, like "1,15", "MON-FRI", and "*/15".
+type Expression struct {
+	spec     string
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+	// anyDay and anyWeekday report whether the day of the month and the day
+	// of the week are unrestricted. When both are restricted, the times
+	// matching either of them match, like in cron.
+	anyDay     bool
+	anyWeekday bool
+}
+
+// Parse parses the cron expression, or one of the @hourly, @daily, @weekly,
+// @monthly, and @yearly shorthands.
+func Parse(spec string) (*Expression, error) {
+	expanded := strings.TrimSpace(spec)
+	if macro, ok := macros[strings.ToLower(expanded)]; ok {
+		expanded = macro
+	}
+
+	fields := strings.Fields(expanded)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q (expected 5 fields, like \"0 10 * * WED\")", ErrInvalidExpression, spec)
+	}
+
+	e := &Expression{spec: spec}
+
+	var err error
+	if e.minutes, err = minuteField.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidExpression, spec, err)
+	}
+
+	if e.hours, err = hourField.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidExpression, spec, err)
+	}
+
+	if e.days, err = dayField.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidExpression, spec, err)
+	}
+
+	if e.months, err = monthField.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidExpression, spec, err)
+	}
+
+	if e.weekdays, err = weekdayField.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidExpression, spec, err)
+	}
+
+	// Sunday is both 0 and 7.
+	if e.weekdays&(1<<7) != 0 {
+		e.weekdays |= 1
+	}
+
+	e.anyDay = fields[2] == "*" || fields[2] == "?"
+	e.anyWeekday = fields[4] == "*" || fields[4] == "?"
+
+	return e, nil
+}
+
+// String returns the expression as it was parsed.
+func (e *Expression) String() string {
+	return e.spec
+}
+
+// Matches reports whether the expression matches the minute of the time.
+func (e *Expression) Matches(t time.Time) bool {
+	return has(e.months, int(t.Month())) && e.matchesDay(t) && has(e.hours, t.Hour()) && has(e.minutes, t.Minute())
+}
+
+// Next returns the first minute after the time matched by the expression, or
+// the zero time if the expression matches no time in the next years, like
+// "0 0 31 2 *".
+func (e *Expression) Next(after time.Time) time.Time {
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute()+1, 0, 0, after.Location())
+	limit := t.AddDate(maxLookahead, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !has(e.months, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !e.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(e.hours, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(e.minutes, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// matchesDay reports whether the expression matches the day of the time.
+func (e *Expression) matchesDay(t time.Time) bool {
+	day := has(e.days, t.Day())
+	weekday := has(e.weekdays, int(t.Weekday()))
+
+	switch {
+	case e.anyDay && e.anyWeekday:
+		return true
+	case e.anyDay:
+		return weekday
+	case e.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+// has reports whether the value is set in the bits of a field.
+func has(bits uint64, value int) bool {
+	return bits&(1<<uint(value)) != 0
+}
+
+// parse returns the bits of the values of the field set by the expression,
+// which is a comma separated list of values, ranges, and steps.
+func (f *field) parse(expr string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step of the %s: %s", f.name, part)
+			}
+
+			rangeExpr = part[:i]
+		}
+
+		start, end, err := f.parseRange(rangeExpr)
+		if err != nil {
+			return 0, err
+		}
+
+		// A step without a range, like "5/15", lasts until the maximum.
+		if step > 1 && !strings.ContainsAny(rangeExpr, "*?-") {
+			end = f.max
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+// parseRange returns the first and the last value of the range, which is
+// a wildcard, a value, or two values separated by a dash.
+func (f *field) parseRange(expr string) (int, int, error) {
+	if expr == "*" || expr == "?" {
+		return f.min, f.max, nil
+	}
+
+	bounds := strings.SplitN(expr, "-", 2)
+
+	start, err := f.parseValue(bounds[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(bounds) == 1 {
+		return start, start, nil
+	}
+
+	end, err := f.parseValue(bounds[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end < start {
+		return 0, 0, fmt.Errorf("reversed range of the %s: %s", f.name, expr)
+	}
+
+	return start, end, nil
+}
+
+// parseValue returns the number of the value, which is a number or a name.
+func (f *field) parseValue(value string) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(name, value) {
+			return i, nil
+		}
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("invalid %s: %s (expected %d-%d)", f.name, value, f.min, f.max)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,157 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// date returns the time of the minute in UTC.
+func date(year int, month time.Month, day int, hour int, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":                 "",
+		"too few fields":        "0 10 * *",
+		"too many fields":       "0 0 10 * * WED",
+		"unknown macro":         "@fortnightly",
+		"minute out of range":   "60 * * * *",
+		"hour out of range":     "0 24 * * *",
+		"day out of range":      "0 0 0 * *",
+		"month out of range":    "0 0 1 13 *",
+		"weekday out of range":  "0 0 * * 8",
+		"unknown month":         "0 0 1 SMARCH *",
+		"unknown weekday":       "0 0 * * FUN",
+		"reversed range":        "0 0 * * FRI-MON",
+		"zero step":             "*/0 * * * *",
+		"negative step":         "*/-5 * * * *",
+		"step without a number": "*/x * * * *",
+		"empty list item":       "0,,30 * * * *",
+		"open range":            "0 9- * * *",
+	}
+
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(spec); !errors.Is(err, ErrInvalidExpression) {
+				t.Errorf("Parse(%q) error = %v, want %v", spec, err, ErrInvalidExpression)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	// 2026-10-14 is a Wednesday.
+	after := date(2026, time.October, 14, 10, 30)
+
+	tests := map[string]struct {
+		spec string
+		want time.Time
+	}{
+		"every minute":              {spec: "* * * * *", want: date(2026, time.October, 14, 10, 31)},
+		"next hour":                 {spec: "0 * * * *", want: date(2026, time.October, 14, 11, 0)},
+		"list":                      {spec: "15,45 * * * *", want: date(2026, time.October, 14, 10, 45)},
+		"minute range":              {spec: "0-10 11 * * *", want: date(2026, time.October, 14, 11, 0)},
+		"step":                      {spec: "*/20 * * * *", want: date(2026, time.October, 14, 10, 40)},
+		"step of a range":           {spec: "0 9-17/4 * * *", want: date(2026, time.October, 14, 13, 0)},
+		"step from a value":         {spec: "50/5 * * * *", want: date(2026, time.October, 14, 10, 50)},
+		"same minute is skipped":    {spec: "30 10 * * *", want: date(2026, time.October, 15, 10, 30)},
+		"weekday name":              {spec: "0 10 * * FRI", want: date(2026, time.October, 16, 10, 0)},
+		"weekday name range":        {spec: "0 9 * * mon-fri", want: date(2026, time.October, 15, 9, 0)},
+		"weekday number":            {spec: "0 10 * * 1", want: date(2026, time.October, 19, 10, 0)},
+		"sunday as 0":               {spec: "0 10 * * 0", want: date(2026, time.October, 18, 10, 0)},
+		"sunday as 7":               {spec: "0 10 * * 7", want: date(2026, time.October, 18, 10, 0)},
+		"range ending on sunday":    {spec: "0 10 * * 6-7", want: date(2026, time.October, 17, 10, 0)},
+		"month name":                {spec: "0 0 1 JAN *", want: date(2027, time.January, 1, 0, 0)},
+		"month name range":          {spec: "0 0 1 nov-dec *", want: date(2026, time.November, 1, 0, 0)},
+		"day of the month":          {spec: "0 0 14 * *", want: date(2026, time.November, 14, 0, 0)},
+		"day of the month skipped":  {spec: "0 0 31 11,12 *", want: date(2026, time.December, 31, 0, 0)},
+		"day or weekday":            {spec: "0 0 20 * MON", want: date(2026, time.October, 19, 0, 0)},
+		"day or weekday by the day": {spec: "0 0 15 * SAT", want: date(2026, time.October, 15, 0, 0)},
+		"question mark":             {spec: "0 0 ? * SUN", want: date(2026, time.October, 18, 0, 0)},
+		"leap day":                  {spec: "0 0 29 2 *", want: date(2028, time.February, 29, 0, 0)},
+		"hourly":                    {spec: "@hourly", want: date(2026, time.October, 14, 11, 0)},
+		"daily":                     {spec: "@daily", want: date(2026, time.October, 15, 0, 0)},
+		"weekly":                    {spec: "@weekly", want: date(2026, time.October, 18, 0, 0)},
+		"monthly":                   {spec: "@monthly", want: date(2026, time.November, 1, 0, 0)},
+		"yearly":                    {spec: "@YEARLY", want: date(2027, time.January, 1, 0, 0)},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+			}
+
+			if got := e.Next(after); !got.Equal(tt.want) {
+				t.Errorf("Next() of %q = %s, want %s", tt.spec, got, tt.want)
+			}
+
+			if next := e.Next(after); !next.IsZero() && !e.Matches(next) {
+				t.Errorf("Matches(%s) of %q = false for the next time", next, tt.spec)
+			}
+		})
+	}
+}
+
+func TestNextNeverMatching(t *testing.T) {
+	for _, spec := range []string{"0 0 31 2 *", "0 0 30 2 *", "0 0 31 4,6,9,11 *"} {
+		t.Run(spec, func(t *testing.T) {
+			e, err := Parse(spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", spec, err)
+			}
+
+			done := make(chan time.Time, 1)
+			go func() { done <- e.Next(date(2026, time.October, 14, 10, 30)) }()
+
+			select {
+			case next := <-done:
+				if !next.IsZero() {
+					t.Errorf("Next() of %q = %s, want the zero time", spec, next)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Next() of %q does not terminate", spec)
+			}
+		})
+	}
+}
+
+func TestMatchesDayOrWeekday(t *testing.T) {
+	e, err := Parse("0 9 13 * FRI")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := map[string]struct {
+		t    time.Time
+		want bool
+	}{
+		"the day":               {t: date(2026, time.October, 13, 9, 0), want: true},
+		"the weekday":           {t: date(2026, time.October, 16, 9, 0), want: true},
+		"friday the 13th":       {t: date(2026, time.November, 13, 9, 0), want: true},
+		"neither":               {t: date(2026, time.October, 14, 9, 0), want: false},
+		"the day at other hour": {t: date(2026, time.October, 13, 10, 0), want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := e.Matches(tt.t); got != tt.want {
+				t.Errorf("Matches(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	e, err := Parse("@weekly")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if e.String() != "@weekly" {
+		t.Errorf("String() = %q, want %q", e.String(), "@weekly")
+	}
+}
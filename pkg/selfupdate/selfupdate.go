@@ -0,0 +1,506 @@
+// Package selfupdate looks up the latest GitHub release of the command,
+// downloads the archive of the platform, verifies its checksum and the
+// signature of the checksums, and replaces the running binary with the one of
+// the release.
+package selfupdate
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultBaseURL is the base URL of the GitHub API.
+	DefaultBaseURL = "https://api.github.com"
+	// DefaultRepository is the repository the releases are published in.
+	DefaultRepository = "gabor-boros/sprint-update"
+	// Program is the name of the binary in the release archives.
+	Program = "sprint-update"
+	// ChecksumsAsset is the name of the asset listing the SHA-256 checksums
+	// of the archives.
+	ChecksumsAsset = "checksums.txt"
+	// SignatureSuffix is the suffix of the detached GPG signatures of the
+	// assets.
+	SignatureSuffix = ".sig"
+)
+
+// maxAssetSize is the largest asset downloaded, in bytes.
+const maxAssetSize = 200 << 20
+
+var (
+	// ErrNoAsset is returned when the release has no archive for the platform.
+	ErrNoAsset = errors.New("no release archive for the platform")
+	// ErrChecksumMismatch is returned when the checksum of the downloaded
+	// archive differs from the published one.
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrMissingChecksum is returned when the checksums of the release do not
+	// list the archive.
+	ErrMissingChecksum = errors.New("missing checksum")
+	// ErrMissingGPG is returned when the signature is verified, but gpg is not
+	// installed.
+	ErrMissingGPG = errors.New("gpg is required to verify the signature")
+	// ErrNoReleaseKey is returned when the signature is verified, but no
+	// public key of the releases is set, like for the builds from source
+	// without an exported release key.
+	ErrNoReleaseKey = errors.New("no release key to verify the signature with")
+	// ErrUntrustedSignature is returned when the signature is not made by the
+	// release key.
+	ErrUntrustedSignature = errors.New("the signature is not made by the release key")
+)
+
+// releaseKey is the ASCII armored public key of the maintainer signing the
+// releases, exported to release-key.asc by "make release-key".
+//
+//go:embed release-key.asc
+var releaseKey []byte
+
+// archAliases are the architectures as named in the release archives.
+var archAliases = map[string]string{
+	"386":   "i386",
+	"amd64": "x86_64",
+}
+
+// Release is a published release of the command.
+type Release struct {
+	// Version is the version of the release, without the leading "v".
+	Version string
+	// URL is the web page of the release.
+	URL string
+	// Assets maps the names of the release assets to their download URLs.
+	Assets map[string]string
+}
+
+// Updater looks up and downloads the releases of the command.
+type Updater struct {
+	// BaseURL is the base URL of the GitHub API.
+	BaseURL string
+	// Repository is the full name of the repository, like "owner/repo".
+	Repository string
+	// Token is the optional GitHub token, raising the rate limit of the API.
+	Token string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+	// PublicKey is the ASCII armored public key the checksums of the
+	// releases must be signed by.
+	PublicKey []byte
+}
+
+// NewUpdater returns a new Updater of the releases of DefaultRepository.
+func NewUpdater(token string) *Updater {
+	return &Updater{
+		BaseURL:    DefaultBaseURL,
+		Repository: DefaultRepository,
+		Token:      token,
+		PublicKey:  releaseKey,
+	}
+}
+
+// release is the release as returned by the GitHub API.
+type release struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+	Assets  []struct {
+		Name               string `json:"name"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Latest returns the latest release, leaving out drafts and pre-releases.
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	requestURL := fmt.Sprintf("%s/repos/%s/releases/latest", strings.TrimSuffix(u.BaseURL, "/"), u.Repository)
+
+	body, err := u.get(ctx, requestURL, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var r release
+	if err := json.Unmarshal(body, &r); err != nil {
+		return nil, err
+	}
+
+	latest := &Release{
+		Version: strings.TrimPrefix(r.TagName, "v"),
+		URL:     r.HTMLURL,
+		Assets:  make(map[string]string, len(r.Assets)),
+	}
+
+	for _, asset := range r.Assets {
+		latest.Assets[asset.Name] = asset.BrowserDownloadURL
+	}
+
+	return latest, nil
+}
+
+// ArchiveName returns the name of the release archive of the platform, like
+// "sprint-update_1.2.0_Linux_x86_64.tar.gz".
+func (r *Release) ArchiveName(goos string, goarch string) string {
+	arch := goarch
+	if alias, ok := archAliases[goarch]; ok {
+		arch = alias
+	}
+
+	return fmt.Sprintf("%s_%s_%s_%s.tar.gz", Program, r.Version, strings.Title(goos), arch)
+}
+
+// Download downloads the release archive of the running platform, verifies
+// its checksum, and returns the binary in it. When verifySignature is set,
+// the GPG signature of the checksums is verified to be made by the public key
+// of the updater too, which requires gpg, so the checksums cannot be replaced
+// together with the archive.
+func (u *Updater) Download(ctx context.Context, r *Release, verifySignature bool) ([]byte, error) {
+	name := r.ArchiveName(runtime.GOOS, runtime.GOARCH)
+
+	archiveURL, ok := r.Assets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNoAsset, runtime.GOOS, runtime.GOARCH)
+	}
+
+	checksumsURL, ok := r.Assets[ChecksumsAsset]
+	if !ok {
+		return nil, fmt.Errorf("%w: the release has no %s", ErrMissingChecksum, ChecksumsAsset)
+	}
+
+	checksums, err := u.get(ctx, checksumsURL, false)
+	if err != nil {
+		return nil, fmt.Errorf("downloading the checksums: %w", err)
+	}
+
+	if verifySignature {
+		signatureURL, ok := r.Assets[ChecksumsAsset+SignatureSuffix]
+		if !ok {
+			return nil, fmt.Errorf("the release has no signature of %s", ChecksumsAsset)
+		}
+
+		signature, err := u.get(ctx, signatureURL, false)
+		if err != nil {
+			return nil, fmt.Errorf("downloading the signature: %w", err)
+		}
+
+		if err := VerifySignature(ctx, u.PublicKey, checksums, signature); err != nil {
+			return nil, err
+		}
+	}
+
+	archive, err := u.get(ctx, archiveURL, false)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", name, err)
+	}
+
+	if err := VerifyChecksum(checksums, name, archive); err != nil {
+		return nil, err
+	}
+
+	return ExtractBinary(archive, binaryName(runtime.GOOS))
+}
+
+// VerifyChecksum verifies the SHA-256 checksum of the named file against the
+// checksums, listed in the format of sha256sum.
+func VerifyChecksum(checksums []byte, name string, content []byte) error {
+	sum := sha256.Sum256(content)
+	got := hex.EncodeToString(sum[:])
+
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+
+		if !strings.EqualFold(fields[0], got) {
+			return fmt.Errorf("%w of %s: expected %s, got %s", ErrChecksumMismatch, name, fields[0], got)
+		}
+
+		return nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w of %s", ErrMissingChecksum, name)
+}
+
+// VerifySignature verifies that the detached GPG signature of the content is
+// made by the ASCII armored public key. The key is imported into a keyring of
+// its own, so the keys of the keyring of the user are not trusted.
+func VerifySignature(ctx context.Context, publicKey []byte, content []byte, signature []byte) error {
+	if len(bytes.TrimSpace(publicKey)) == 0 {
+		return ErrNoReleaseKey
+	}
+
+	gpg, err := exec.LookPath("gpg")
+	if err != nil {
+		return ErrMissingGPG
+	}
+
+	dir, err := os.MkdirTemp("", Program+"-signature")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	homeDir := filepath.Join(dir, "gnupg")
+	if err := os.Mkdir(homeDir, 0o700); err != nil {
+		return err
+	}
+
+	keyPath := filepath.Join(dir, "release-key.asc")
+	contentPath := filepath.Join(dir, ChecksumsAsset)
+	signaturePath := contentPath + SignatureSuffix
+
+	for path, data := range map[string][]byte{keyPath: publicKey, contentPath: content, signaturePath: signature} {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return err
+		}
+	}
+
+	args := []string{"--batch", "--homedir", homeDir, "--no-default-keyring", "--keyring", filepath.Join(dir, "release.kbx"), "--status-fd", "1"}
+
+	status, err := runGPG(ctx, gpg, append(args, "--import", keyPath)...)
+	if err != nil {
+		return fmt.Errorf("importing the release key: %w", err)
+	}
+
+	fingerprints := statusFields(status, "IMPORT_OK", 3)
+	if len(fingerprints) == 0 {
+		return fmt.Errorf("%w: the release key is not a public key", ErrNoReleaseKey)
+	}
+
+	status, err = runGPG(ctx, gpg, append(args, "--verify", signaturePath, contentPath)...)
+	if len(statusFields(status, "NO_PUBKEY", 2)) > 0 {
+		return ErrUntrustedSignature
+	} else if err != nil {
+		return fmt.Errorf("verifying the signature: %w", err)
+	}
+
+	// The primary key fingerprint is the last field of VALIDSIG, so the
+	// signatures made by the subkeys of the release key are accepted too.
+	for _, signer := range statusFields(status, "VALIDSIG", 11) {
+		for _, fingerprint := range fingerprints {
+			if strings.EqualFold(signer, fingerprint) {
+				return nil
+			}
+		}
+	}
+
+	return ErrUntrustedSignature
+}
+
+// runGPG runs gpg with the arguments, returning the status lines written to
+// the standard output, and the messages of gpg on failure.
+func runGPG(ctx context.Context, gpg string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, gpg, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return stdout.Bytes(), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// statusFields returns the field at the index of the gpg status lines of the
+// keyword, like the fingerprint of "[GNUPG:] IMPORT_OK 1 <fingerprint>" at
+// index 3.
+func statusFields(output []byte, keyword string, index int) []string {
+	var values []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > index && fields[0] == "[GNUPG:]" && fields[1] == keyword {
+			values = append(values, fields[index])
+		}
+	}
+
+	return values
+}
+
+// ExtractBinary returns the content of the named file of the gzipped tar
+// archive, looking up the file at any depth.
+func ExtractBinary(archive []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("the archive has no %s", name)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag != tar.TypeReg || path.Base(header.Name) != name {
+			continue
+		}
+
+		return io.ReadAll(io.LimitReader(tr, maxAssetSize))
+	}
+}
+
+// Replace replaces the binary at the path with the new one, keeping its file
+// mode. The new binary is written next to the old one and renamed over it,
+// so the binary is never left half written. On Windows, the running binary
+// cannot be overwritten, hence it is moved aside first.
+func Replace(executable string, binary []byte) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(executable)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(executable)+".new")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(binary); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		_ = os.Remove(old)
+
+		if err := os.Rename(executable, old); err != nil {
+			return err
+		}
+
+		if err := os.Rename(tmp.Name(), executable); err != nil {
+			_ = os.Rename(old, executable)
+			return err
+		}
+
+		return nil
+	}
+
+	return os.Rename(tmp.Name(), executable)
+}
+
+// IsNewer reports whether the latest version is newer than the current one,
+// comparing the dot separated numbers of the versions, like "1.10.0" and
+// "1.9.2". The pre-release and build suffixes are ignored.
+func IsNewer(current string, latest string) bool {
+	c := versionNumbers(current)
+	l := versionNumbers(latest)
+
+	for i := 0; i < len(c) || i < len(l); i++ {
+		var cn, ln int
+		if i < len(c) {
+			cn = c[i]
+		}
+
+		if i < len(l) {
+			ln = l[i]
+		}
+
+		if cn != ln {
+			return ln > cn
+		}
+	}
+
+	return false
+}
+
+// versionNumbers returns the numbers of the version, like [1 2 0] for
+// "v1.2.0-rc1".
+func versionNumbers(version string) []int {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	var numbers []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+
+		numbers = append(numbers, n)
+	}
+
+	return numbers
+}
+
+// binaryName returns the name of the binary on the operating system.
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return Program + ".exe"
+	}
+
+	return Program
+}
+
+// get returns the body of the response to the GET request. The GitHub token
+// is only sent to the API, the assets are downloaded from other hosts.
+func (u *Updater) get(ctx context.Context, requestURL string, api bool) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if api {
+		req.Header.Set("Accept", "application/vnd.github+json")
+
+		if u.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+u.Token)
+		}
+	}
+
+	httpClient := u.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("github request failed with status %d", resp.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxAssetSize))
+}
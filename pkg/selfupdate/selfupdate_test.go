@@ -0,0 +1,244 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	content := []byte("archive")
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	tests := map[string]struct {
+		checksums string
+		want      error
+	}{
+		"match":            {checksums: fmt.Sprintf("%s  other.tar.gz\n%s  sprint-update.tar.gz\n", checksum, checksum), want: nil},
+		"binary mode":      {checksums: fmt.Sprintf("%s *sprint-update.tar.gz\n", checksum), want: nil},
+		"upper case":       {checksums: fmt.Sprintf("%X  sprint-update.tar.gz\n", sum), want: nil},
+		"mismatch":         {checksums: fmt.Sprintf("%s  sprint-update.tar.gz\n", hex.EncodeToString(make([]byte, sha256.Size))), want: ErrChecksumMismatch},
+		"missing entry":    {checksums: fmt.Sprintf("%s  other.tar.gz\n", checksum), want: ErrMissingChecksum},
+		"malformed entry":  {checksums: fmt.Sprintf("%s sprint-update.tar.gz extra\n", checksum), want: ErrMissingChecksum},
+		"empty checksums":  {checksums: "", want: ErrMissingChecksum},
+		"name as a prefix": {checksums: fmt.Sprintf("%s  sprint-update.tar.gz.sig\n", checksum), want: ErrMissingChecksum},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyChecksum([]byte(tt.checksums), "sprint-update.tar.gz", content)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("VerifyChecksum() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current string
+		latest  string
+		want    bool
+	}{
+		{current: "1.0.0", latest: "1.0.1", want: true},
+		{current: "1.9.2", latest: "1.10.0", want: true},
+		{current: "v1.2.0", latest: "1.3.0", want: true},
+		{current: "1.2", latest: "1.2.1", want: true},
+		{current: "1.2.0", latest: "1.2.0", want: false},
+		{current: "1.2.0", latest: "v1.2", want: false},
+		{current: "1.10.0", latest: "1.9.9", want: false},
+		{current: "2.0.0", latest: "1.99.99", want: false},
+		{current: "1.2.0-rc1", latest: "1.2.0", want: false},
+		{current: "1.2.0", latest: "1.2.1+build.5", want: true},
+		{current: "dev", latest: "0.1.0", want: true},
+		{current: "0.1.0", latest: "dev", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+" to "+tt.latest, func(t *testing.T) {
+			if got := IsNewer(tt.current, tt.latest); got != tt.want {
+				t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.current, tt.latest, got, tt.want)
+			}
+		})
+	}
+}
+
+// tarEntry is a file or directory of the archives built by the tests.
+type tarEntry struct {
+	name     string
+	typeflag byte
+	content  string
+}
+
+// newArchive returns a gzipped tar archive of the entries.
+func newArchive(t *testing.T, entries ...tarEntry) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Typeflag: entry.typeflag, Mode: 0o755, Size: int64(len(entry.content))}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatalf("writing the header of %s: %v", entry.name, err)
+		}
+
+		if _, err := tw.Write([]byte(entry.content)); err != nil {
+			t.Fatalf("writing %s: %v", entry.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatalf("closing the archive: %v", err)
+	}
+
+	if err := gz.Close(); err != nil {
+		t.Fatalf("compressing the archive: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestExtractBinary(t *testing.T) {
+	archive := newArchive(t,
+		tarEntry{name: "README.md", typeflag: tar.TypeReg, content: "readme"},
+		tarEntry{name: "sprint-update/", typeflag: tar.TypeDir},
+		tarEntry{name: "sprint-update/sprint-update", typeflag: tar.TypeReg, content: "binary"},
+	)
+
+	binary, err := ExtractBinary(archive, Program)
+	if err != nil {
+		t.Fatalf("ExtractBinary() error = %v", err)
+	}
+
+	if string(binary) != "binary" {
+		t.Errorf("ExtractBinary() = %q, want %q", binary, "binary")
+	}
+}
+
+func TestExtractBinaryMissing(t *testing.T) {
+	archive := newArchive(t,
+		tarEntry{name: "sprint-update", typeflag: tar.TypeDir},
+		tarEntry{name: "sprint-update.exe", typeflag: tar.TypeReg, content: "binary"},
+	)
+
+	if _, err := ExtractBinary(archive, Program); err == nil {
+		t.Error("ExtractBinary() returned no error for an archive without the binary")
+	}
+}
+
+func TestExtractBinaryNotGzipped(t *testing.T) {
+	if _, err := ExtractBinary([]byte("not an archive"), Program); err == nil {
+		t.Error("ExtractBinary() returned no error for content not gzipped")
+	}
+}
+
+// testKeyring generates keys into a keyring of its own, skipping the test if
+// gpg is not installed.
+type testKeyring struct {
+	t       *testing.T
+	gpg     string
+	homeDir string
+}
+
+// newTestKeyring returns a keyring in a temporary directory.
+func newTestKeyring(t *testing.T) *testKeyring {
+	t.Helper()
+
+	gpg, err := exec.LookPath("gpg")
+	if err != nil {
+		t.Skip("gpg is not installed")
+	}
+
+	homeDir := filepath.Join(t.TempDir(), "gnupg")
+	if err := os.Mkdir(homeDir, 0o700); err != nil {
+		t.Fatalf("creating the keyring: %v", err)
+	}
+
+	return &testKeyring{t: t, gpg: gpg, homeDir: homeDir}
+}
+
+// run runs gpg on the keyring, returning its standard output.
+func (k *testKeyring) run(stdin []byte, args ...string) []byte {
+	k.t.Helper()
+
+	args = append([]string{"--batch", "--homedir", k.homeDir, "--pinentry-mode", "loopback", "--passphrase", ""}, args...)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(k.gpg, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		k.t.Fatalf("running gpg %v: %v: %s", args, err, stderr.String())
+	}
+
+	return out
+}
+
+// generate generates a signing key of the user ID, returning its ASCII
+// armored public key.
+func (k *testKeyring) generate(userID string) []byte {
+	k.t.Helper()
+
+	k.run(nil, "--quick-generate-key", userID, "default", "sign", "never")
+
+	return k.run(nil, "--armor", "--export", userID)
+}
+
+// sign returns the detached signature of the content made by the key of the
+// user ID.
+func (k *testKeyring) sign(userID string, content []byte) []byte {
+	k.t.Helper()
+
+	return k.run(content, "--local-user", userID, "--detach-sign", "--output", "-")
+}
+
+func TestVerifySignature(t *testing.T) {
+	keyring := newTestKeyring(t)
+	releaseKey := keyring.generate("release@example.com")
+	keyring.generate("other@example.com")
+
+	content := []byte("checksums")
+	signature := keyring.sign("release@example.com", content)
+
+	tests := map[string]struct {
+		publicKey []byte
+		content   []byte
+		signature []byte
+		wantErr   bool
+		want      error
+	}{
+		"signed by the release key": {publicKey: releaseKey, content: content, signature: signature},
+		"signed by another key":     {publicKey: releaseKey, content: content, signature: keyring.sign("other@example.com", content), wantErr: true, want: ErrUntrustedSignature},
+		"tampered content":          {publicKey: releaseKey, content: []byte("tampered"), signature: signature, wantErr: true},
+		"malformed signature":       {publicKey: releaseKey, content: content, signature: []byte("not a signature"), wantErr: true},
+		"no release key":            {publicKey: []byte(" \n"), content: content, signature: signature, wantErr: true, want: ErrNoReleaseKey},
+		"not a public key":          {publicKey: []byte("not a key"), content: content, signature: signature, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifySignature(context.Background(), tt.publicKey, tt.content, tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("VerifySignature() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Package signing signs the generated updates with GPG or minisign, producing
+// detached signatures stored next to the updates, so the archived updates can
+// be verified to be unchanged since they were generated.
+package signing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Tool is the tool the updates are signed with.
+type Tool string
+
+const (
+	// ToolGPG signs the updates using gpg, producing ASCII armored detached
+	// signatures.
+	ToolGPG Tool = "gpg"
+	// ToolMinisign signs the updates using minisign.
+	ToolMinisign Tool = "minisign"
+)
+
+// ErrUnknownTool is returned when signing with an unknown tool.
+var ErrUnknownTool = errors.New("unknown signing tool")
+
+// ErrMissingTool is returned when the signing tool is not installed.
+var ErrMissingTool = errors.New("signing tool not found")
+
+// ValidateTool checks that the signing tool is known. An empty tool means
+// the updates are not signed.
+func ValidateTool(tool Tool) error {
+	switch tool {
+	case "", ToolGPG, ToolMinisign:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownTool, tool)
+	}
+}
+
+// Extension returns the extension of the signature files of the tool, added
+// to the name of the signed file, like "update.md.asc".
+func (t Tool) Extension() string {
+	if t == ToolMinisign {
+		return ".minisig"
+	}
+
+	return ".asc"
+}
+
+// Sign returns the detached signature of the content, named like the file it
+// is written to, signed with the tool. The key is the GPG key ID or the path
+// of the minisign secret key; when empty, the default key of the tool is
+// used. As the tools run unattended, the GPG passphrase must be cached by
+// gpg-agent, and the minisign key must not be encrypted.
+func Sign(ctx context.Context, tool Tool, key string, name string, content []byte) ([]byte, error) {
+	if err := ValidateTool(tool); err != nil {
+		return nil, err
+	}
+
+	path, err := exec.LookPath(string(tool))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMissingTool, tool)
+	}
+
+	if tool == ToolGPG {
+		return signGPG(ctx, path, key, content)
+	}
+
+	return signMinisign(ctx, path, key, name, content)
+}
+
+// signGPG signs the content with gpg, reading it from the standard input.
+func signGPG(ctx context.Context, gpg string, key string, content []byte) ([]byte, error) {
+	args := []string{"--batch", "--armor", "--detach-sign"}
+	if key != "" {
+		args = append(args, "--local-user", key)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, gpg, args...)
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("signing the update: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// signMinisign signs the content with minisign, which only signs files, so
+// the content is written to a temporary file of the name, which is recorded
+// in the trusted comment of the signature.
+func signMinisign(ctx context.Context, minisign string, key string, name string, content []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "sprint-update-signature")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	if name == "" {
+		name = "update"
+	}
+
+	contentPath := filepath.Join(dir, filepath.Base(name))
+	signaturePath := contentPath + ToolMinisign.Extension()
+
+	if err := os.WriteFile(contentPath, content, 0600); err != nil {
+		return nil, err
+	}
+
+	args := []string{"-S", "-m", contentPath, "-x", signaturePath}
+	if key != "" {
+		args = append(args, "-s", key)
+	}
+
+	output, err := exec.CommandContext(ctx, minisign, args...).CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("signing the update: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return os.ReadFile(signaturePath)
+}
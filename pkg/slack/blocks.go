@@ -0,0 +1,496 @@
+package slack
+
+import (
+	"fmt"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// maxSectionLength is the maximum length of the text of a section block.
+const maxSectionLength = 3000
+
+// maxHeaderLength is the maximum length of the text of a header block.
+const maxHeaderLength = 150
+
+// escaper escapes the control characters of Slack mrkdwn.
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Block is a Block Kit layout block.
+type Block struct {
+	Type string `json:"type"`
+	Text *Text  `json:"text,omitempty"`
+}
+
+// Text is a Block Kit text object.
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// headerBlock returns a header block with the given plain text.
+func headerBlock(text string) Block {
+	if runes := []rune(text); len(runes) > maxHeaderLength {
+		text = string(runes[:maxHeaderLength-3]) + "..."
+	}
+
+	return Block{Type: "header", Text: &Text{Type: "plain_text", Text: text}}
+}
+
+// sectionBlocks returns section blocks with the given mrkdwn lines. The lines
+// are split into multiple sections if they do not fit into one.
+func sectionBlocks(lines ...string) []Block {
+	var blocks []Block
+	var text strings.Builder
+
+	for _, line := range lines {
+		if text.Len() > 0 && text.Len()+len(line)+1 > maxSectionLength {
+			blocks = append(blocks, Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: text.String()}})
+			text.Reset()
+		}
+
+		if text.Len() > 0 {
+			text.WriteString("\n")
+		}
+
+		text.WriteString(line)
+	}
+
+	if text.Len() > 0 {
+		blocks = append(blocks, Block{Type: "section", Text: &Text{Type: "mrkdwn", Text: text.String()}})
+	}
+
+	return blocks
+}
+
+// dividerBlock returns a divider block.
+func dividerBlock() Block {
+	return Block{Type: "divider"}
+}
+
+// issueLink returns the link of the issue followed by a dash, or its key if
+// the URL is empty, like for redacted updates. If both are empty, an empty
+// string is returned.
+func issueLink(key string, url string) string {
+	switch {
+	case url != "":
+		return fmt.Sprintf("<%s|%s> - ", url, key)
+	case key != "":
+		return escaper.Replace(key) + " - "
+	default:
+		return ""
+	}
+}
+
+// issueLine returns the list item of the issue.
+func issueLine(update *report.Update, issue *report.Issue, withAssignee bool) string {
+	line := "• " + issueLink(issue.Key, issue.URL) + escaper.Replace(issue.Summary)
+	if issue.Change != "" {
+		line += fmt.Sprintf(" (%s)", escaper.Replace(issue.ChangeNote()))
+	}
+
+	if withAssignee {
+		line += fmt.Sprintf(" (%s)", escaper.Replace(issue.Assignee))
+	}
+
+	if len(issue.BlockedBy) > 0 {
+		line += fmt.Sprintf(" (%s)", escaper.Replace(update.T("blocked by %s", strings.Join(issue.BlockedBy, ", "))))
+	}
+
+	if issue.Flagged {
+		line += fmt.Sprintf(" (%s)", escaper.Replace(update.T("flagged")))
+	}
+
+	if issue.BlockedReason != "" {
+		line += ": " + escaper.Replace(issue.BlockedReason)
+	}
+
+	return line
+}
+
+// groupBlocks returns a section for every issue group, corresponding to the
+// collapsible details of the Discourse format.
+func groupBlocks(update *report.Update, issues report.Issues) []Block {
+	var blocks []Block
+
+	for _, group := range update.Groups(issues) {
+		header := fmt.Sprintf("_%s_", escaper.Replace(group.Name))
+		if update.Decorated && group.Emoji != "" {
+			header = group.Emoji + " " + header
+		}
+
+		if group.StoryPoints > 0 {
+			header += fmt.Sprintf(" (%s pts)", report.FormatPoints(group.StoryPoints))
+		}
+
+		lines := []string{header}
+		for i := range group.Issues {
+			line := issueLine(update, &group.Issues[i], false)
+			if group.Status == "" {
+				line += fmt.Sprintf(" (%s)", escaper.Replace(group.Issues[i].Status))
+			}
+
+			if group.Issues[i].TimeSpent > 0 {
+				line += fmt.Sprintf(" (%s)", report.FormatHours(group.Issues[i].TimeSpent))
+			}
+
+			if progress := group.Issues[i].SubtaskProgress(); progress != "" {
+				line += fmt.Sprintf(" (%s)", progress)
+			}
+
+			if len(group.Issues[i].Annotations) > 0 {
+				line += fmt.Sprintf(" (%s)", escaper.Replace(strings.Join(group.Issues[i].Annotations, ", ")))
+			}
+
+			if group.Issues[i].Note != "" {
+				line += "\n    ◦ " + escaper.Replace(group.Issues[i].Note)
+			}
+
+			for _, subtask := range group.Issues[i].Subtasks {
+				line += fmt.Sprintf("\n    ◦ %s%s (%s)", issueLink(subtask.Key, subtask.URL), escaper.Replace(subtask.Summary), escaper.Replace(subtask.Status))
+			}
+
+			lines = append(lines, line)
+		}
+
+		blocks = append(blocks, sectionBlocks(lines...)...)
+	}
+
+	return blocks
+}
+
+// heading returns the bold heading of a section, which is escaped.
+func heading(title string) string {
+	return fmt.Sprintf("*%s*", escaper.Replace(title))
+}
+
+// listBlocks returns a titled section listing the issues of every status. The
+// title is escaped.
+func listBlocks(update *report.Update, title string, issues report.Issues, empty string) []Block {
+	lines := []string{heading(title)}
+
+	for _, group := range update.Groups(issues) {
+		for i := range group.Issues {
+			lines = append(lines, issueLine(update, &group.Issues[i], len(update.Members) > 0))
+		}
+	}
+
+	if len(lines) == 1 {
+		if empty == "" {
+			return nil
+		}
+
+		lines = append(lines, empty)
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// summaryBlocks returns the summary section, if the summary is written.
+func summaryBlocks(update *report.Update) []Block {
+	if update.Summary == "" {
+		return nil
+	}
+
+	lines := []string{heading(update.Heading(string(report.SectionSummary), "Summary"))}
+	for _, line := range strings.Split(strings.TrimSpace(update.Summary), "\n") {
+		lines = append(lines, escaper.Replace(line))
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// themeBlocks returns the themes section, if there are themes.
+func themeBlocks(update *report.Update) []Block {
+	if len(update.Themes) == 0 {
+		return nil
+	}
+
+	lines := []string{heading(update.Heading(string(report.SectionThemes), "Themes"))}
+
+	for i := range update.Themes {
+		theme := &update.Themes[i]
+
+		line := "• " + issueLink(theme.Key, theme.URL) + escaper.Replace(theme.Name) + ": "
+		if theme.Total > 0 {
+			line += escaper.Replace(update.T("%s%% done", report.FormatPoints(theme.DonePercentage()))) + ", "
+		}
+
+		if theme.Issues == 1 {
+			line += escaper.Replace(update.T("%d issue", theme.Issues))
+		} else {
+			line += escaper.Replace(update.T("%d issues", theme.Issues))
+		}
+
+		lines = append(lines, line)
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// workedOnBlocks returns the worked on section, split by member or project
+// if set.
+func workedOnBlocks(update *report.Update) []Block {
+	blocks := sectionBlocks(heading(update.Heading(string(report.SectionWorkedOn), "Worked on")))
+
+	if update.StoryPoints {
+		blocks = append(blocks, sectionBlocks(escaper.Replace(update.T(
+			"Done: %s pts of %s committed",
+			report.FormatPoints(update.DonePoints()),
+			report.FormatPoints(update.CommittedPoints()),
+		)))...)
+	}
+
+	switch {
+	case len(update.Members) > 0:
+		for _, member := range update.Members {
+			blocks = append(blocks, sectionBlocks(heading(member.Name))...)
+			blocks = append(blocks, groupBlocks(update, member.Issues)...)
+		}
+	case len(update.Projects) > 0:
+		for i := range update.Projects {
+			project := &update.Projects[i]
+
+			count := update.T("%d issues", project.Count())
+			if project.Count() == 1 {
+				count = update.T("%d issue", project.Count())
+			}
+
+			lines := []string{fmt.Sprintf("%s (%s)", heading(project.Name), escaper.Replace(count))}
+			if update.StoryPoints {
+				lines = append(lines, escaper.Replace(update.T(
+					"Done: %s pts of %s committed",
+					report.FormatPoints(project.DonePoints()),
+					report.FormatPoints(project.CommittedPoints()),
+				)))
+			}
+
+			blocks = append(blocks, sectionBlocks(lines...)...)
+			blocks = append(blocks, groupBlocks(update, project.Issues)...)
+		}
+	default:
+		blocks = append(blocks, groupBlocks(update, update.Issues)...)
+	}
+
+	return blocks
+}
+
+// dependencyBlocks returns the dependencies on other teams section, if there
+// are dependencies.
+func dependencyBlocks(update *report.Update) []Block {
+	if len(update.Dependencies) == 0 {
+		return nil
+	}
+
+	lines := []string{heading(update.Heading(string(report.SectionDependencies), "Dependencies on other teams"))}
+
+	for _, dependency := range update.Dependencies {
+		link := dependency.Link
+
+		line := fmt.Sprintf("• %s%s: %s %s%s", issueLink(dependency.Key, dependency.URL), escaper.Replace(dependency.Summary), escaper.Replace(link.Relation), issueLink(link.Key, link.URL), escaper.Replace(link.Summary))
+		if link.Status != "" {
+			line += fmt.Sprintf(" (%s)", escaper.Replace(link.Status))
+		}
+
+		lines = append(lines, line)
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// pullRequestBlocks returns the pull requests section, if there are pull
+// requests.
+func pullRequestBlocks(update *report.Update) []Block {
+	if len(update.PullRequests) == 0 {
+		return nil
+	}
+
+	lines := []string{heading(update.Heading(string(report.SectionPullRequests), "Pull requests"))}
+
+	for _, pr := range update.PullRequests {
+		line := fmt.Sprintf("• <%s|%s#%d> - %s", pr.URL, escaper.Replace(pr.Repository), pr.Number, escaper.Replace(pr.Title))
+		if pr.Merged {
+			line += fmt.Sprintf(" (%s)", escaper.Replace(update.T("merged")))
+		}
+
+		for i, issue := range pr.Issues {
+			if i == 0 {
+				line += " -"
+			} else {
+				line += ","
+			}
+
+			line += fmt.Sprintf(" <%s|%s>", issue.URL, issue.Key)
+		}
+
+		lines = append(lines, line)
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// commitBlocks returns the commits section, if there are commits, listing
+// the commits under the issues they reference.
+func commitBlocks(update *report.Update) []Block {
+	if len(update.Commits) == 0 {
+		return nil
+	}
+
+	lines := []string{heading(update.Heading(string(report.SectionCommits), "Commits"))}
+
+	for _, group := range update.Commits {
+		var title string
+
+		switch {
+		case group.Key == "":
+			title = fmt.Sprintf("_%s_", escaper.Replace(update.T("No issue")))
+		case group.URL != "":
+			title = fmt.Sprintf("<%s|%s>", group.URL, group.Key)
+		default:
+			title = escaper.Replace(group.Key)
+		}
+
+		if group.Key != "" && group.Summary != "" {
+			title += " - " + escaper.Replace(group.Summary)
+		}
+
+		lines = append(lines, title)
+
+		for i := range group.Commits {
+			commit := &group.Commits[i]
+			lines = append(lines, "• "+escaper.Replace(fmt.Sprintf("%s@%s - %s", commit.Repository, commit.ShortHash(), commit.Subject)))
+		}
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// timesheetBlocks returns the section of the hours logged in Tempo, if Tempo
+// is used, listing the done issues without logged time.
+func timesheetBlocks(update *report.Update) []Block {
+	if update.Timesheet == nil {
+		return nil
+	}
+
+	total := escaper.Replace(update.T("%s logged", report.FormatHours(update.Timesheet.Total)))
+	if update.Timesheet.Approved {
+		total += fmt.Sprintf(" (%s)", escaper.Replace(update.T("approved")))
+	}
+
+	lines := []string{heading(update.Heading(string(report.SectionHours), "Hours")), total}
+	if len(update.Timesheet.Unlogged) > 0 {
+		lines = append(lines, escaper.Replace(update.T("Done without logged time:")))
+	}
+
+	for _, issue := range update.Timesheet.Unlogged {
+		lines = append(lines, "• "+issueLink(issue.Key, issue.URL)+escaper.Replace(issue.Summary))
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// kudosBlocks returns the kudos section, listing a placeholder if there are
+// no kudos.
+func kudosBlocks(update *report.Update) []Block {
+	lines := []string{heading(update.Heading(string(report.SectionKudos), "Kudos"))}
+
+	for _, k := range update.KudosOrPlaceholder() {
+		lines = append(lines, "• "+escaper.Replace(k))
+	}
+
+	for _, k := range update.SuggestedKudos {
+		lines = append(lines, fmt.Sprintf("• %s (%s)", escaper.Replace(k.Name), escaper.Replace(update.T("suggested: %s", update.KudosReason(k)))))
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// statsBlocks returns the velocity of the sprint, if the sprint report is
+// read, and the cycle time of the issues, if their timeline is read.
+func statsBlocks(update *report.Update) []Block {
+	stats, cycleTime := update.Stats, update.CycleTime
+	if stats == nil && cycleTime == nil {
+		return nil
+	}
+
+	lines := []string{heading(update.Heading(string(report.SectionStats), "Velocity"))}
+
+	if stats != nil {
+		lines = append(lines,
+			"• "+escaper.Replace(update.T("%s of %s committed pts completed (%s%%)", report.FormatPoints(stats.CompletedPoints), report.FormatPoints(stats.CommittedPoints), report.FormatPoints(stats.CompletionPercentage()))),
+			"• "+escaper.Replace(update.T("%d issues completed, %d not completed, %d added after the start", stats.CompletedIssues, stats.NotCompletedIssues, stats.AddedIssues)),
+		)
+	}
+
+	if cycleTime != nil {
+		lines = append(lines, "• "+escaper.Replace(update.T("%d issues done in %s days on average, %s days at the median", cycleTime.Issues, report.FormatPoints(cycleTime.AverageDays()), report.FormatPoints(cycleTime.MedianDays()))))
+	}
+
+	return sectionBlocks(lines...)
+}
+
+// updateSectionBlocks returns the blocks of the section of the update, or
+// nil if the section is left out, like the pull requests section without
+// pull requests.
+func updateSectionBlocks(update *report.Update, section report.Section) []Block {
+	switch section {
+	case report.SectionSummary:
+		return summaryBlocks(update)
+	case report.SectionThemes:
+		return themeBlocks(update)
+	case report.SectionWorkedOn:
+		return workedOnBlocks(update)
+	case report.SectionBlocked:
+		return listBlocks(update, update.Heading(string(section), "Blocked / Needs help"), update.Blocked, "")
+	case report.SectionDependencies:
+		return dependencyBlocks(update)
+	case report.SectionPullRequests:
+		return pullRequestBlocks(update)
+	case report.SectionCommits:
+		return commitBlocks(update)
+	case report.SectionHours:
+		return timesheetBlocks(update)
+	case report.SectionSpillovers:
+		return listBlocks(update, update.Heading(string(section), "Spillovers"), update.Spillovers, escaper.Replace(update.T("No spillovers in this sprint.")))
+	case report.SectionCarriedOver:
+		return listBlocks(update, update.Heading(string(section), "Carried over from %s", update.CarriedOverFrom), update.CarriedOver, "")
+	case report.SectionKudos:
+		return kudosBlocks(update)
+	case report.SectionTimeOff:
+		return sectionBlocks(heading(update.Heading(string(section), "Time off")), escaper.Replace(update.TimeOffText()))
+	case report.SectionStats:
+		return statsBlocks(update)
+	}
+
+	return nil
+}
+
+// NewMessage returns the sprint update as a message formatted using Block
+// Kit, having the sections of the update in their order, separated by
+// dividers.
+func NewMessage(update *report.Update) *Message {
+	blocks := []Block{headerBlock(update.Title)}
+
+	for _, section := range update.OrderedSections() {
+		content := updateSectionBlocks(update, section)
+		if len(content) == 0 {
+			continue
+		}
+
+		if len(blocks) > 1 {
+			blocks = append(blocks, dividerBlock())
+		}
+
+		blocks = append(blocks, content...)
+	}
+
+	if note := update.ProvenanceNote(); note != "" {
+		blocks = append(blocks, dividerBlock())
+		blocks = append(blocks, sectionBlocks("_"+escaper.Replace(note)+"_")...)
+	}
+
+	return &Message{
+		Text:   update.Title,
+		Blocks: blocks,
+	}
+}
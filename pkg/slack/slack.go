@@ -0,0 +1,177 @@
+// Package slack implements a minimal Slack client for delivering sprint
+// updates using incoming webhooks or bot tokens.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// postMessageURL is the URL of the chat.postMessage Web API method.
+const postMessageURL = "https://slack.com/api/chat.postMessage"
+
+// defaultMaxAttempts is the number of attempts made when Slack rate limits
+// the requests.
+const defaultMaxAttempts = 3
+
+// defaultRetryAfter is the delay before retrying a rate limited request if
+// Slack does not tell how long to wait.
+const defaultRetryAfter = time.Second
+
+var (
+	// ErrMissingTarget is returned when neither a webhook URL nor a bot token
+	// is set.
+	ErrMissingTarget = errors.New("slack webhook URL or bot token is required")
+	// ErrMissingChannel is returned when posting with a bot token without
+	// setting the channel.
+	ErrMissingChannel = errors.New("slack channel is required when using a bot token")
+	// ErrRateLimited is returned when the request is still rate limited after
+	// every attempt.
+	ErrRateLimited = errors.New("slack rate limit exceeded")
+)
+
+// Client is a Slack client posting messages either to an incoming webhook or
+// to a channel on behalf of a bot.
+type Client struct {
+	// WebhookURL is the URL of the incoming webhook. When set, the message is
+	// posted to the webhook's channel.
+	WebhookURL string
+	// Token is the bot token used to post messages to Channel.
+	Token string
+	// Channel is the ID or name of the channel the bot posts to.
+	Channel string
+	// MaxAttempts is the number of attempts made when the requests are rate
+	// limited. When zero, 3 attempts are made.
+	MaxAttempts int
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// Message is a message to post.
+type Message struct {
+	// Channel is set by the client when posting with a bot token.
+	Channel string `json:"channel,omitempty"`
+	// Text is the fallback text of the message, shown in notifications.
+	Text   string  `json:"text"`
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// apiResponse is the response of the Slack Web API.
+type apiResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+// PostMessage posts the message. Rate limited requests are retried after the
+// delay requested by Slack.
+func (c *Client) PostMessage(ctx context.Context, message *Message) error {
+	if c.WebhookURL == "" && c.Token == "" {
+		return ErrMissingTarget
+	}
+
+	requestURL := c.WebhookURL
+	if requestURL == "" {
+		if c.Channel == "" {
+			return ErrMissingChannel
+		}
+
+		requestURL = postMessageURL
+		message.Channel = c.Channel
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	maxAttempts := c.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := c.post(ctx, requestURL, body)
+		if err == nil || !errors.Is(err, ErrRateLimited) || attempt >= maxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryAfter):
+		}
+	}
+}
+
+// post sends the message and returns the delay requested by Slack if the
+// request is rate limited.
+func (c *Client) post(ctx context.Context, requestURL string, body []byte) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if c.WebhookURL == "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return retryAfter(resp), ErrRateLimited
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("slack request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	// Incoming webhooks respond with plain text, while the Web API responds
+	// with a JSON object even for failed requests.
+	if c.WebhookURL == "" {
+		var apiResp apiResponse
+		if err = json.Unmarshal(respBody, &apiResp); err != nil {
+			return 0, err
+		}
+
+		if !apiResp.OK {
+			return 0, fmt.Errorf("slack request failed: %s", apiResp.Error)
+		}
+	}
+
+	return 0, nil
+}
+
+// retryAfter returns the delay requested by the Retry-After header of the
+// response.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return defaultRetryAfter
+	}
+
+	return time.Duration(seconds) * time.Second
+}
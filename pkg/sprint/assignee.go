@@ -0,0 +1,42 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// ErrAssigneeAndTeam is returned when an update is generated for someone else
+// in team mode.
+var ErrAssigneeAndTeam = errors.New("an assignee and team members cannot be used together")
+
+// resolveAssignee looks up the display name of the person the update is
+// generated for, if it is not the authenticated user.
+func (c *Config) resolveAssignee(ctx context.Context, client *gojira.Client) error {
+	if c.Assignee == "" {
+		return nil
+	}
+
+	name, err := c.userDisplayName(ctx, client, c.Assignee)
+	if err != nil {
+		return err
+	}
+
+	c.assigneeName = name
+	if c.assigneeName == "" {
+		c.assigneeName = c.Assignee
+	}
+
+	return nil
+}
+
+// userID returns the identifier of the person the update is generated for,
+// which is the authenticated user unless an assignee is set.
+func (c *Config) userID(ctx context.Context, client *gojira.Client) (string, error) {
+	if c.Assignee != "" {
+		return c.Assignee, nil
+	}
+
+	return c.currentUser(ctx, client)
+}
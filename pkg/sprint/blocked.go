@@ -0,0 +1,40 @@
+package sprint
+
+import (
+	"context"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// addBlockedReasons sets the blocked reason of the flagged issues to the
+// comment added when flagging them. The comments are read from the already
+// fetched activities, and fetched only for the flagged issues otherwise.
+func addBlockedReasons(ctx context.Context, client *gojira.Client, update *report.Update, activities map[string]*gojira.Issue) error {
+	var flagged []string
+	for _, statusIssues := range update.Blocked {
+		for _, issue := range statusIssues {
+			if issue.Flagged {
+				flagged = append(flagged, issue.Key)
+			}
+		}
+	}
+
+	for _, key := range flagged {
+		activity, ok := activities[key]
+		if !ok {
+			var err error
+			if activity, err = jira.FetchActivity(ctx, client, key); err != nil {
+				return err
+			}
+		}
+
+		if reason := jira.FlagReason(activity); reason != "" {
+			update.SetBlockedReason(key, reason)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package sprint
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/logging"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// cacheKey returns the key of the cached metadata, prefixed by the Jira
+// server and the user, as the metadata depends on both.
+func (c *Config) cacheKey(name string) string {
+	return c.ServerURL + " " + c.Username + " " + name
+}
+
+// cached decodes the cached metadata of the key into v, or fetches and caches
+// it if it is not cached. Failing to save the cache is logged only, as the
+// metadata is fetched already.
+func (c *Config) cached(ctx context.Context, name string, v interface{}, fetch func() error) error {
+	return c.cachedUntil(ctx, name, v, func() (time.Time, error) {
+		return time.Time{}, fetch()
+	})
+}
+
+// cachedUntil is like cached, but the fetch function returns the time the
+// metadata is valid until too. A zero time means the metadata is valid until
+// the cache expires.
+func (c *Config) cachedUntil(ctx context.Context, name string, v interface{}, fetch func() (time.Time, error)) error {
+	logger := logging.FromContext(ctx)
+
+	key := c.cacheKey(name)
+	if c.Cache.Get(key, v) {
+		logger.Verbose("using cached metadata", "key", name)
+		return nil
+	}
+
+	expires, err := fetch()
+	if err != nil {
+		return err
+	}
+
+	if err = c.Cache.SetUntil(key, v, expires); err != nil {
+		logger.Verbose("cannot cache metadata", "key", name, "error", err)
+	}
+
+	return nil
+}
+
+// fieldID returns the ID of the custom field found by the given function.
+func (c *Config) fieldID(ctx context.Context, client *gojira.Client, name string, find func(context.Context, *gojira.Client) (string, error)) (string, error) {
+	var id string
+	err := c.cached(ctx, "field:"+name, &id, func() (err error) {
+		id, err = find(ctx, client)
+		return err
+	})
+
+	return id, err
+}
+
+// sprintFieldID returns the ID of the Sprint custom field: the configured
+// field, or the field discovered using the fields API.
+func (c *Config) sprintFieldID(ctx context.Context, client *gojira.Client) (string, error) {
+	if c.SprintField != "" {
+		return c.SprintField, nil
+	}
+
+	return c.fieldID(ctx, client, "sprint", jira.FindSprintFieldID)
+}
+
+// findSprint returns the sprint of the given name, including its dates. The
+// sprints are cached until they end, as the active sprints may be started
+// or completed early.
+func (c *Config) findSprint(ctx context.Context, client *gojira.Client, name string, sprintFieldID string) (*jira.Sprint, error) {
+	var s *jira.Sprint
+	err := c.cachedUntil(ctx, "sprint:"+name, &s, func() (time.Time, error) {
+		var err error
+		if s, err = jira.FindSprint(ctx, client, name, sprintFieldID); err != nil {
+			return time.Time{}, err
+		}
+
+		if s.StartDate == nil && s.ID != 0 {
+			if s, err = jira.FetchSprint(ctx, client, s.ID); err != nil {
+				return time.Time{}, err
+			}
+		}
+
+		return sprintExpiry(s), nil
+	})
+
+	return s, err
+}
+
+// boardActiveSprint returns the active sprint of the board, cached until the
+// sprint ends.
+func (c *Config) boardActiveSprint(ctx context.Context, client *gojira.Client, boardID int) (*jira.Sprint, error) {
+	var s *jira.Sprint
+	err := c.cachedUntil(ctx, "board:"+strconv.Itoa(boardID)+":active-sprint", &s, func() (time.Time, error) {
+		var err error
+		if s, err = jira.FindActiveSprint(ctx, client, boardID); err != nil {
+			return time.Time{}, err
+		}
+
+		return sprintExpiry(s), nil
+	})
+
+	return s, err
+}
+
+// fetchSprint returns the sprint having the given ID, cached until the sprint
+// ends.
+func (c *Config) fetchSprint(ctx context.Context, client *gojira.Client, sprintID int) (*jira.Sprint, error) {
+	var s *jira.Sprint
+	err := c.cachedUntil(ctx, "sprint-id:"+strconv.Itoa(sprintID), &s, func() (time.Time, error) {
+		var err error
+		if s, err = jira.FetchSprint(ctx, client, sprintID); err != nil {
+			return time.Time{}, err
+		}
+
+		return sprintExpiry(s), nil
+	})
+
+	return s, err
+}
+
+// boardSprint returns the sprint of the board having the given name, cached
+// until the sprint ends. If the board has no sprint of the name,
+// jira.ErrSprintNotFound is returned.
+func (c *Config) boardSprint(ctx context.Context, client *gojira.Client, boardID int, name string) (*jira.Sprint, error) {
+	var s *jira.Sprint
+	err := c.cachedUntil(ctx, "board:"+strconv.Itoa(boardID)+":sprint:"+name, &s, func() (time.Time, error) {
+		var err error
+		if s, err = jira.FindBoardSprint(ctx, client, boardID, name); err != nil {
+			return time.Time{}, err
+		}
+
+		return sprintExpiry(s), nil
+	})
+
+	return s, err
+}
+
+// sprintExpiry returns the time the sprint can be cached until: until the
+// cache expires if it is closed, or until its end otherwise. Sprints without
+// an end are not cached, as they expire right away.
+func sprintExpiry(s *jira.Sprint) time.Time {
+	switch {
+	case s.IsClosed():
+		return time.Time{}
+	case s.EndDate != nil:
+		return *s.EndDate
+	default:
+		return time.Now()
+	}
+}
+
+// priorityNames returns the names of the priorities of the Jira server, from
+// the highest to the lowest.
+func (c *Config) priorityNames(ctx context.Context, client *gojira.Client) ([]string, error) {
+	var priorities []string
+	err := c.cached(ctx, "priorities", &priorities, func() (err error) {
+		priorities, err = jira.Priorities(ctx, client)
+		return err
+	})
+
+	return priorities, err
+}
+
+// currentUser returns the account ID, or the username on Jira Server, of the
+// authenticated user.
+func (c *Config) currentUser(ctx context.Context, client *gojira.Client) (string, error) {
+	var id string
+	err := c.cached(ctx, "user:self", &id, func() (err error) {
+		id, err = jira.FetchCurrentUser(ctx, client)
+		return err
+	})
+
+	return id, err
+}
+
+// userDisplayName returns the display name of the user of the given account
+// ID or username.
+func (c *Config) userDisplayName(ctx context.Context, client *gojira.Client, id string) (string, error) {
+	var name string
+	err := c.cached(ctx, "user:"+id, &name, func() error {
+		user, err := jira.FetchUser(ctx, client, id)
+		if err != nil {
+			return err
+		}
+
+		name = user.DisplayName
+		return nil
+	})
+
+	return name, err
+}
+
+// statusCategories returns the keys of the status categories by the IDs of
+// the statuses.
+func (c *Config) statusCategories(ctx context.Context, client *gojira.Client) (map[string]string, error) {
+	var categories map[string]string
+	err := c.cached(ctx, "status-categories", &categories, func() (err error) {
+		categories, err = jira.StatusCategories(ctx, client)
+		return err
+	})
+
+	return categories, err
+}
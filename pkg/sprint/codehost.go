@@ -0,0 +1,65 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/codehost"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// ErrUnknownSprintWindow is returned when the start date of the sprint cannot
+// be determined from its issues, hence the pull requests cannot be listed.
+var ErrUnknownSprintWindow = errors.New("cannot determine the start date of the sprint")
+
+// sprintWindow returns the start and end of the sprint, based on the sprints
+// the issues are part of. If the sprint has not ended yet, the current time is
+// used as its end. The window of consolidated updates is their resolved date
+// range.
+func (c *Config) sprintWindow(issues report.Issues) (time.Time, time.Time, error) {
+	if c.isConsolidated() && c.sprint != nil && c.sprint.StartDate != nil {
+		since, until := c.window()
+		return since, until, nil
+	}
+
+	for _, statusIssues := range issues {
+		for _, issue := range statusIssues {
+			for _, s := range issue.Sprints {
+				if s.Name != c.Sprint || s.StartDate == nil {
+					continue
+				}
+
+				until := time.Now()
+				if s.EndDate != nil && s.EndDate.Before(until) {
+					until = *s.EndDate
+				}
+
+				return *s.StartDate, until, nil
+			}
+		}
+	}
+
+	return time.Time{}, time.Time{}, ErrUnknownSprintWindow
+}
+
+// fetchPullRequests returns the pull requests of the user opened or merged
+// during the sprint on every code host, in the order of the code hosts.
+func (c *Config) fetchPullRequests(ctx context.Context, issues report.Issues) ([]report.PullRequest, error) {
+	since, until, err := c.sprintWindow(issues)
+	if err != nil {
+		return nil, err
+	}
+
+	var pullRequests []codehost.PullRequest
+	for _, host := range c.CodeHosts {
+		found, err := host.PullRequests(ctx, since, until)
+		if err != nil {
+			return nil, err
+		}
+
+		pullRequests = append(pullRequests, found...)
+	}
+
+	return report.NewPullRequests(c.ServerURL, pullRequests), nil
+}
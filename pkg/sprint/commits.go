@@ -0,0 +1,41 @@
+package sprint
+
+import (
+	"context"
+	"sort"
+
+	"gabor-boros/sprint-update/pkg/git"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// addCommits lists the commits of the user authored during the sprint in
+// every git repository, grouped by the issues they reference.
+func (c *Config) addCommits(ctx context.Context, update *report.Update, issues report.Issues) error {
+	since, until, err := c.sprintWindow(issues)
+	if err != nil {
+		return err
+	}
+
+	var commits []git.Commit
+	for _, location := range c.GitRepositories {
+		repo, err := git.Open(ctx, location, c.GitCacheDir)
+		if err != nil {
+			return err
+		}
+
+		found, err := repo.Commits(ctx, c.GitAuthors, since, until)
+		if err != nil {
+			return err
+		}
+
+		commits = append(commits, found...)
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].AuthoredAt.After(commits[j].AuthoredAt)
+	})
+
+	update.SetCommits(c.ServerURL, commits)
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package sprint
+
+import (
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// filterIssues leaves out the issues excluded by their status, labels, type,
+// or project from the issues and the issues of the team members.
+func (c *Config) filterIssues(issues report.Issues, members []report.Member) (report.Issues, []report.Member) {
+	if len(c.ExcludeStatuses) == 0 && len(c.ExcludeLabels) == 0 && len(c.IncludeTypes) == 0 && len(c.Projects) == 0 {
+		return issues, members
+	}
+
+	filteredMembers := make([]report.Member, 0, len(members))
+	for _, member := range members {
+		filteredMembers = append(filteredMembers, report.Member{Name: member.Name, Issues: member.Issues.Filter(c.isIncluded)})
+	}
+
+	return issues.Filter(c.isIncluded), filteredMembers
+}
+
+// isIncluded reports whether the issue is kept in the update by the exclusion
+// and inclusion filters.
+func (c *Config) isIncluded(issue *report.Issue) bool {
+	if containsFold(c.ExcludeStatuses, issue.Status) {
+		return false
+	}
+
+	for _, label := range issue.Labels {
+		if containsFold(c.ExcludeLabels, label) {
+			return false
+		}
+	}
+
+	if len(c.Projects) > 0 && !containsFold(c.Projects, issue.ProjectKey) {
+		return false
+	}
+
+	return len(c.IncludeTypes) == 0 || containsFold(c.IncludeTypes, issue.Type)
+}
+
+// containsFold reports whether the values contain the value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,64 @@
+package sprint
+
+import (
+	"context"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// needsEpics reports whether the epics of the issues are resolved, for
+// grouping the issues by epic or rendering the themes section.
+func (c *Config) needsEpics() bool {
+	return c.GroupBy == report.GroupByEpic || report.HasSection(c.Sections, report.SectionThemes)
+}
+
+// resolveEpics looks up the names of the epics of the issues, including the
+// issues of the team members, for grouping the issues by epic.
+func (c *Config) resolveEpics(ctx context.Context, client *gojira.Client, issues report.Issues, members []report.Member) error {
+	names, err := jira.FetchEpicNames(ctx, client, issues.EpicKeys())
+	if err != nil {
+		return err
+	}
+
+	issues.ResolveEpics(names)
+
+	for i := range members {
+		members[i].Issues.ResolveEpics(names)
+	}
+
+	return nil
+}
+
+// themes returns the epics touched by the issues with their progress, for
+// the themes section.
+func (c *Config) themes(ctx context.Context, client *gojira.Client, issues report.Issues, epicLinkFieldID string) ([]report.Theme, error) {
+	themes := issues.Themes(c.ServerURL)
+	if len(themes) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(themes))
+	for i := range themes {
+		keys = append(keys, themes[i].Key)
+	}
+
+	progress, err := jira.FetchEpicProgress(ctx, client, keys, epicLinkFieldID)
+	if err != nil {
+		return nil, err
+	}
+
+	setProgress(themes, progress)
+
+	return themes, nil
+}
+
+// setProgress sets the progress of the epics of the themes.
+func setProgress(themes []report.Theme, progress map[string]jira.EpicProgress) {
+	for i := range themes {
+		themes[i].Done = progress[themes[i].Key].Done
+		themes[i].Total = progress[themes[i].Key].Total
+	}
+}
@@ -0,0 +1,80 @@
+package sprint
+
+import (
+	"time"
+
+	"gabor-boros/sprint-update/pkg/history"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// diffPrevious annotates the issues of the update with their changes since
+// the update of the sprint generated last, based on the history directory.
+func (c *Config) diffPrevious(update *report.Update) error {
+	if c.HistoryDir == "" {
+		return nil
+	}
+
+	previous, err := history.Latest(c.HistoryDir, c.Name())
+	if err != nil || previous == nil {
+		return err
+	}
+
+	update.Diff(previous.Issues)
+	return nil
+}
+
+// SaveHistory archives the update and its rendered text in the configured
+// history directory, so the next update of the sprint can be compared to it.
+// It does nothing for redacted updates, or if no history directory is set.
+func (c *Config) SaveHistory(update *report.Update, text string) error {
+	if c.HistoryDir == "" || c.Redact {
+		return nil
+	}
+
+	c.historyEntry = &history.Entry{
+		Sprint:      c.Name(),
+		Title:       update.Title,
+		EndOfSprint: c.EndOfSprint,
+		Created:     time.Now(),
+		Format:      c.Format,
+		Output:      text,
+		Issues:      update.Issues,
+	}
+
+	return history.Save(c.HistoryDir, c.historyEntry)
+}
+
+// SaveDiscoursePost records the Discourse post the update was posted as in the
+// update archived by SaveHistory, so the post can be amended by the next
+// updates of the sprint. It does nothing if the update was not archived.
+func (c *Config) SaveDiscoursePost(post *history.DiscoursePost) error {
+	if c.historyEntry == nil {
+		return nil
+	}
+
+	c.historyEntry.DiscoursePost = post
+	return history.Save(c.HistoryDir, c.historyEntry)
+}
+
+// SaveSignature records the detached signature of the output of the update
+// archived by SaveHistory, so the archived update can be verified. It does
+// nothing if the update was not archived or signed.
+func (c *Config) SaveSignature(signature string) error {
+	if c.historyEntry == nil || signature == "" {
+		return nil
+	}
+
+	c.historyEntry.Signature = signature
+	return history.Save(c.HistoryDir, c.historyEntry)
+}
+
+// DiscoursePost returns the Discourse post of the sprint posted last, based on
+// the history directory. If no updates of the sprint were posted to
+// Discourse, nil is returned.
+func (c *Config) DiscoursePost() (*history.DiscoursePost, error) {
+	if c.HistoryDir == "" {
+		return nil, nil
+	}
+
+	return history.LatestDiscoursePost(c.HistoryDir, c.Name())
+}
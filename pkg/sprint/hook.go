@@ -0,0 +1,114 @@
+package sprint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"gabor-boros/sprint-update/pkg/hook"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// hookIssues is the JSON document exchanged with the post-fetch hooks.
+type hookIssues struct {
+	// Issues lists the issues grouped by status.
+	Issues report.Issues `json:"issues"`
+	// Members lists the issues per team member in team mode.
+	Members []hookMember `json:"members,omitempty"`
+}
+
+// hookMember is a team member of the hookIssues document.
+type hookMember struct {
+	Name   string        `json:"name"`
+	Issues report.Issues `json:"issues"`
+}
+
+// hookEnv returns the environment variables passed to the hooks.
+func (c *Config) hookEnv() []string {
+	return []string{
+		"SPRINT_UPDATE_SPRINT=" + c.Name(),
+		"SPRINT_UPDATE_END_OF_SPRINT=" + strconv.FormatBool(c.EndOfSprint),
+		"SPRINT_UPDATE_FORMAT=" + c.Format,
+	}
+}
+
+// runPreFetchHooks runs the pre-fetch hooks. Their output is ignored.
+func (c *Config) runPreFetchHooks(ctx context.Context) error {
+	for _, command := range c.Hooks.PreFetch {
+		if _, err := hook.Run(ctx, hook.PreFetch, command, nil, c.hookEnv()...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runPostFetchHooks passes the issues through the post-fetch hooks, one after
+// the other, and returns the issues printed by the last hook. If a hook
+// prints nothing, the issues are passed on unchanged.
+func (c *Config) runPostFetchHooks(ctx context.Context, issues report.Issues, members []report.Member) (report.Issues, []report.Member, error) {
+	if len(c.Hooks.PostFetch) == 0 {
+		return issues, members, nil
+	}
+
+	document := hookIssues{Issues: issues}
+	for _, member := range members {
+		document.Members = append(document.Members, hookMember{Name: member.Name, Issues: member.Issues})
+	}
+
+	input, err := json.Marshal(document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, command := range c.Hooks.PostFetch {
+		output, err := hook.Run(ctx, hook.PostFetch, command, input, c.hookEnv()...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(bytes.TrimSpace(output)) > 0 {
+			input = output
+		}
+	}
+
+	var modified hookIssues
+	if err = json.Unmarshal(input, &modified); err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid %s hook output: %v", hook.ErrHookFailed, hook.PostFetch, err)
+	}
+
+	originals := []report.Issues{issues}
+	for _, member := range members {
+		originals = append(originals, member.Issues)
+	}
+
+	modified.Issues.KeepSubtaskCounts(originals...)
+
+	var modifiedMembers []report.Member
+	for _, member := range modified.Members {
+		member.Issues.KeepSubtaskCounts(originals...)
+		modifiedMembers = append(modifiedMembers, report.Member{Name: member.Name, Issues: member.Issues})
+	}
+
+	return modified.Issues, modifiedMembers, nil
+}
+
+// RunPostRenderHooks passes the rendered update through the post-render
+// hooks, one after the other, and returns the update printed by the last
+// hook. If a hook prints nothing, the update is passed on unchanged.
+func (c *Config) RunPostRenderHooks(ctx context.Context, text string) (string, error) {
+	for _, command := range c.Hooks.PostRender {
+		output, err := hook.Run(ctx, hook.PostRender, command, []byte(text), c.hookEnv()...)
+		if err != nil {
+			return "", err
+		}
+
+		if len(bytes.TrimSpace(output)) > 0 {
+			text = string(output)
+		}
+	}
+
+	return text, nil
+}
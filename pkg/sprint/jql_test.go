@@ -0,0 +1,149 @@
+package sprint
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gabor-boros/sprint-update/pkg/jira"
+)
+
+const (
+	// hostileSprint, hostileAssignee, and hostileProject try to break out of
+	// the string literals of the queries.
+	hostileSprint   = `SE.253" OR project = "OPS`
+	hostileAssignee = `jane\" OR assignee = "john\`
+	hostileProject  = `SE") OR (key = "OPS-1`
+)
+
+// jqlLiterals returns the unescaped double quoted string literals of the
+// query.
+func jqlLiterals(t *testing.T, jql string) []string {
+	t.Helper()
+
+	var literals []string
+	var literal strings.Builder
+	inLiteral, escaped := false, false
+
+	for _, r := range jql {
+		switch {
+		case escaped:
+			literal.WriteRune(r)
+			escaped = false
+		case inLiteral && r == '\\':
+			escaped = true
+		case inLiteral && r == '"':
+			literals = append(literals, literal.String())
+			literal.Reset()
+			inLiteral = false
+		case inLiteral:
+			literal.WriteRune(r)
+		case r == '"':
+			inLiteral = true
+		}
+	}
+
+	if inLiteral {
+		t.Fatalf("the query has an unclosed literal: %s", jql)
+	}
+
+	return literals
+}
+
+// assertLiterals fails the test if the query is malformed, or the values are
+// not string literals of the query as a whole.
+func assertLiterals(t *testing.T, jql string, values ...string) {
+	t.Helper()
+
+	if err := jira.CheckJQL(jql); err != nil {
+		t.Fatalf("the query is malformed: %v", err)
+	}
+
+	literals := jqlLiterals(t, jql)
+
+	for _, value := range values {
+		found := false
+		for _, literal := range literals {
+			found = found || literal == value
+		}
+
+		if !found {
+			t.Errorf("%q is not a literal of the query: %s", value, jql)
+		}
+	}
+
+	if strings.Contains(jql, `project = "OPS"`) || strings.Contains(jql, `assignee = "john`) || strings.Contains(jql, `key = "OPS-1"`) {
+		t.Errorf("a value altered the query: %s", jql)
+	}
+}
+
+func TestConfigJQLQuotesValues(t *testing.T) {
+	tests := map[string]struct {
+		config Config
+		values []string
+	}{
+		"sprint": {
+			config: Config{Sprint: hostileSprint, Assignee: hostileAssignee, Projects: []string{hostileProject}},
+			values: []string{hostileSprint, hostileAssignee, hostileProject},
+		},
+		"sprints": {
+			config: Config{Sprint: hostileSprint, Sprints: []string{`SE.254\`}, Assignee: hostileAssignee},
+			values: []string{hostileSprint, `SE.254\`, hostileAssignee},
+		},
+		"custom query": {
+			config: Config{JQL: `project = SE ORDER BY key`, Assignee: hostileAssignee, Projects: []string{hostileProject}},
+			values: []string{hostileAssignee, hostileProject},
+		},
+		"custom query of the worklogs": {
+			config: Config{JQL: `project = SE`, Worklog: true, Assignee: hostileAssignee},
+			values: []string{hostileAssignee},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assertLiterals(t, tt.config.jql(""), tt.values...)
+		})
+	}
+}
+
+func TestConfigJQLQuotesMembers(t *testing.T) {
+	config := Config{Sprint: hostileSprint, Assignees: []string{hostileAssignee}}
+
+	assertLiterals(t, config.jql(hostileAssignee), hostileSprint, hostileAssignee)
+}
+
+func TestConfigJQLCurrentUser(t *testing.T) {
+	config := Config{Sprint: "SE.253"}
+
+	if jql := config.jql(""); !strings.HasPrefix(jql, "assignee = currentUser() AND ") {
+		t.Errorf("the query does not search the issues of the authenticated user: %s", jql)
+	}
+}
+
+func TestValidateQueryValues(t *testing.T) {
+	tests := map[string]struct {
+		config  Config
+		wantErr bool
+	}{
+		"hostile values":        {config: Config{Sprint: hostileSprint, Assignee: hostileAssignee, Projects: []string{hostileProject}}},
+		"line break in sprint":  {config: Config{Sprint: "SE.253\n OR project = OPS"}, wantErr: true},
+		"line break in sprints": {config: Config{Sprints: []string{"SE.253", "SE.254\n"}}, wantErr: true},
+		"control character":     {config: Config{Sprint: "SE.253", Assignee: "jane\x00"}, wantErr: true},
+		"blank assignee":        {config: Config{Sprint: "SE.253", Assignees: []string{" "}}, wantErr: true},
+		"blank project":         {config: Config{Sprint: "SE.253", Projects: []string{""}}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.config.validateQueryValues()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateQueryValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr && !errors.Is(err, jira.ErrInvalidJQLValue) {
+				t.Errorf("validateQueryValues() error = %v, want %v", err, jira.ErrInvalidJQLValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package sprint
+
+import (
+	"context"
+	"sort"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// fetchActivities returns the comments, assignee, and status of the issues by
+// their keys.
+func fetchActivities(ctx context.Context, client *gojira.Client, issues []gojira.Issue) (map[string]*gojira.Issue, error) {
+	activities := make(map[string]*gojira.Issue, len(issues))
+
+	for i := range issues {
+		activity, err := jira.FetchActivity(ctx, client, issues[i].Key)
+		if err != nil {
+			return nil, err
+		}
+
+		activities[issues[i].Key] = activity
+	}
+
+	return activities, nil
+}
+
+// suggestKudos suggests kudos for the colleagues who commented on the issues
+// or resolved the issues blocking them. The activity of the current user is
+// ignored.
+func (c *Config) suggestKudos(ctx context.Context, client *gojira.Client, issues []gojira.Issue, activities map[string]*gojira.Issue) ([]report.KudosSuggestion, error) {
+	currentUserID, err := c.userID(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make(map[string]*report.KudosSuggestion)
+	suggestion := func(user *gojira.User) *report.KudosSuggestion {
+		if user == nil || user.DisplayName == "" || jira.IsUser(user, currentUserID) {
+			return nil
+		}
+
+		if _, ok := suggestions[user.DisplayName]; !ok {
+			suggestions[user.DisplayName] = &report.KudosSuggestion{Name: user.DisplayName}
+		}
+
+		return suggestions[user.DisplayName]
+	}
+
+	blockers := make(map[string]*gojira.Issue)
+
+	for i := range issues {
+		issue := &issues[i]
+		activity := activities[issue.Key]
+
+		commented := make(map[string]bool)
+		if activity != nil && activity.Fields != nil && activity.Fields.Comments != nil {
+			for _, comment := range activity.Fields.Comments.Comments {
+				author := comment.Author
+				if s := suggestion(&author); s != nil && !commented[s.Name] {
+					commented[s.Name] = true
+					s.Commented = append(s.Commented, issue.Key)
+				}
+			}
+		}
+
+		for _, key := range report.DoneBlockerKeys(issue) {
+			blocker, ok := blockers[key]
+			if !ok {
+				if blocker, err = jira.FetchActivity(ctx, client, key); err != nil {
+					return nil, err
+				}
+
+				blockers[key] = blocker
+			}
+
+			if blocker.Fields == nil {
+				continue
+			}
+
+			if s := suggestion(blocker.Fields.Assignee); s != nil {
+				s.Unblocked = append(s.Unblocked, issue.Key)
+			}
+		}
+	}
+
+	result := make([]report.KudosSuggestion, 0, len(suggestions))
+	for _, s := range suggestions {
+		result = append(result, *s)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result, nil
+}
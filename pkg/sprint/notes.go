@@ -0,0 +1,83 @@
+package sprint
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// commentTimeLayout is the layout of the comment creation times returned by
+// Jira.
+const commentTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// noteLength is the maximum length of the progress notes, so long comments do
+// not take over the update.
+const noteLength = 280
+
+// addProgressNotes sets the progress note of the issues to the last comment
+// of the current user written within the sprint. If a marker is configured,
+// the last comment of anyone containing the marker is used instead, with the
+// marker removed.
+func (c *Config) addProgressNotes(ctx context.Context, client *gojira.Client, update *report.Update, activities map[string]*gojira.Issue) error {
+	currentUserID, err := c.userID(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	since, until := c.window()
+
+	for key, activity := range activities {
+		if activity == nil || activity.Fields == nil || activity.Fields.Comments == nil {
+			continue
+		}
+
+		var note string
+		var noteCreated time.Time
+
+		for _, comment := range activity.Fields.Comments.Comments {
+			created, err := time.Parse(commentTimeLayout, comment.Created)
+			if err != nil || created.Before(since) || created.After(until) || created.Before(noteCreated) {
+				continue
+			}
+
+			body := comment.Body
+			if c.ProgressMarker != "" {
+				if !strings.Contains(body, c.ProgressMarker) {
+					continue
+				}
+
+				body = strings.ReplaceAll(body, c.ProgressMarker, "")
+			} else if author := comment.Author; !jira.IsUser(&author, currentUserID) {
+				continue
+			}
+
+			if body = strings.Join(strings.Fields(body), " "); body != "" {
+				note, noteCreated = report.Truncate(body, noteLength), created
+			}
+		}
+
+		if note == "" {
+			continue
+		}
+
+		setNote := func(issue *report.Issue) {
+			issue.Note = note
+		}
+
+		update.Issues.Update(key, setNote)
+		for i := range update.Members {
+			update.Members[i].Issues.Update(key, setNote)
+		}
+
+		for i := range update.Projects {
+			update.Projects[i].Issues.Update(key, setNote)
+		}
+	}
+
+	return nil
+}
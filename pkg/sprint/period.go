@@ -0,0 +1,155 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/jira"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// MultiSprintJQL represents the JQL query used to search tickets of the
+// assignee within any of the given sprints, searching by the sprint field
+// like DefaultJQL.
+const MultiSprintJQL string = `assignee = %s AND %s in (%s)`
+
+// PeriodJQL represents the JQL query used to search tickets assigned to the
+// assignee and updated within the given date range.
+const PeriodJQL string = `assignee was %s DURING ("%s", "%s") AND updated >= "%s"`
+
+var (
+	// ErrInvalidPeriod is returned when the date range of the update ends
+	// before it starts.
+	ErrInvalidPeriod = errors.New("the end of the period is before its start")
+	// ErrSprintAndPeriod is returned when both sprints and a date range are
+	// set.
+	ErrSprintAndPeriod = errors.New("sprints and a date range cannot be used together")
+)
+
+// sprintNames returns the names of the sprints covered by the update.
+func (c *Config) sprintNames() []string {
+	var names []string
+	for _, name := range append([]string{c.Sprint}, c.Sprints...) {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// isPeriod reports whether the update covers a date range instead of
+// sprints.
+func (c *Config) isPeriod() bool {
+	return !c.From.IsZero()
+}
+
+// isConsolidated reports whether the update covers multiple sprints or a date
+// range, instead of a single sprint.
+func (c *Config) isConsolidated() bool {
+	return len(c.Sprints) > 0 || c.isPeriod()
+}
+
+// until returns the end of the date range of the update, defaulting to the
+// current time.
+func (c *Config) until() time.Time {
+	if c.Until.IsZero() {
+		return time.Now()
+	}
+
+	return c.Until
+}
+
+// Name returns the name of the update, which is the name of the sprint, the
+// names of the sprints of a consolidated update, like "SE.253, SE.254", or
+// the date range, like "2026-09-01 – 2026-09-30".
+func (c *Config) Name() string {
+	if c.isPeriod() {
+		return c.From.Format(jqlDateLayout) + " – " + c.until().Format(jqlDateLayout)
+	}
+
+	return strings.Join(c.sprintNames(), ", ")
+}
+
+// validatePeriod checks that the sprints and the date range are not mixed,
+// and that the date range is not reversed.
+func (c *Config) validatePeriod() error {
+	if !c.isPeriod() {
+		if !c.Until.IsZero() {
+			return fmt.Errorf("%w: the period has no start", ErrInvalidPeriod)
+		}
+
+		return nil
+	}
+
+	if len(c.sprintNames()) > 0 || c.SprintID != 0 {
+		return ErrSprintAndPeriod
+	}
+
+	if c.until().Before(c.From) {
+		return fmt.Errorf("%w: %s", ErrInvalidPeriod, c.Name())
+	}
+
+	return nil
+}
+
+// consolidatedJQL returns the default query of consolidated updates for the
+// given user.
+func (c *Config) consolidatedJQL(user string) string {
+	if c.isPeriod() {
+		from := c.From.Format(jqlDateLayout)
+		return fmt.Sprintf(PeriodJQL, user, from, c.until().Format(jqlDateLayout), from)
+	}
+
+	quoted := make([]string, 0, len(c.sprintNames()))
+	for _, name := range c.sprintNames() {
+		quoted = append(quoted, jira.QuoteJQL(name))
+	}
+
+	return fmt.Sprintf(MultiSprintJQL, user, jira.SprintJQLField(c.sprintField), strings.Join(quoted, ", "))
+}
+
+// lookupPeriod resolves the date range of consolidated updates: the date
+// range itself, or the start of the first and the end of the last sprint
+// found. The sprints not found are left out of the date range.
+func (c *Config) lookupPeriod(ctx context.Context, client *gojira.Client, sprintFieldID string) error {
+	if c.isPeriod() {
+		from, until := c.From, c.until()
+		c.sprint = &jira.Sprint{Name: c.Name(), StartDate: &from, EndDate: &until}
+		return nil
+	}
+
+	period := &jira.Sprint{Name: c.Name()}
+	if sprintFieldID == "" {
+		c.sprint = period
+		return nil
+	}
+
+	for _, name := range c.sprintNames() {
+		s, err := c.findSprint(ctx, client, name, sprintFieldID)
+		if errors.Is(err, jira.ErrSprintNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if s.StartDate != nil && (period.StartDate == nil || s.StartDate.Before(*period.StartDate)) {
+			period.StartDate = s.StartDate
+		}
+
+		if s.EndDate != nil && (period.EndDate == nil || s.EndDate.After(*period.EndDate)) {
+			period.EndDate = s.EndDate
+		}
+
+		period.State = s.State
+	}
+
+	c.sprint = period
+	return nil
+}
@@ -0,0 +1,529 @@
+package sprint
+
+import (
+	"fmt"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/codehost"
+	"gabor-boros/sprint-update/pkg/git"
+	"gabor-boros/sprint-update/pkg/i18n"
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+const (
+	// sampleSprint is the name of the sample sprint used when no sprint is
+	// configured.
+	sampleSprint = "SE.253"
+	// samplePreviousSprint is the name of the sprint the sample spillovers are
+	// carried over from.
+	samplePreviousSprint = "SE.252"
+	// sampleProjectKey is the key of the project of the sample issues.
+	sampleProjectKey = "SE"
+	// sampleServerURL is the Jira server the sample issues link to when no
+	// server is configured.
+	sampleServerURL = "https://jira.example.com"
+	// sampleSprintLength is the length of the sample sprint.
+	sampleSprintLength = 14 * 24 * time.Hour
+)
+
+// sampleIssue describes an issue of the sample update.
+type sampleIssue struct {
+	key         string
+	summary     string
+	status      string
+	issueType   string
+	done        bool
+	epicKey     string
+	epic        string
+	labels      []string
+	components  []string
+	storyPoints float64
+	timeSpent   time.Duration
+	// unlogged indicates that no time is logged on the issue in Tempo.
+	unlogged    bool
+	parent      string
+	blockedBy   []string
+	flagReason  string
+	carriedOver bool
+	note        string
+	// spillReason is the reason the issue spills over, rendered if the
+	// spillover reasons are enabled.
+	spillReason string
+	priority    string
+	// startedDay, resolvedDay, and dueDay are the days of the sprint the
+	// issue was started at, resolved at, and is due at. They are ignored when
+	// zero.
+	startedDay  int
+	resolvedDay int
+	dueDay      int
+	// links are the issues linked to the issue, some of them owned by other
+	// teams.
+	links []report.LinkedIssue
+}
+
+// sampleStatusCategories maps the statuses of the sample issues to their
+// categories.
+var sampleStatusCategories = map[string]string{
+	"To Do":       report.StatusCategoryToDo,
+	"In Progress": report.StatusCategoryInProgress,
+	"In Review":   report.StatusCategoryInProgress,
+	"Done":        report.StatusCategoryDone,
+}
+
+// sampleEpicProgress is the progress of the epics of the sample issues.
+var sampleEpicProgress = map[string]jira.EpicProgress{
+	"SE-10": {Done: 6, Total: 8},
+	"SE-20": {Done: 1, Total: 4},
+	"SE-30": {Done: 3, Total: 5},
+}
+
+// sampleSummary is the narrative summary of the sample update.
+const sampleSummary = "I finished the CSV export of the reports along with its documentation, and the dashboard migration is in review. " +
+	"The root cause of the search pagination bug is found, but the fix needs the new search index, so it is likely to spill over. " +
+	"The database upgrade is blocked until the ops team schedules a maintenance window."
+
+// sampleIssues lists the issues of the sample update, covering every section
+// of the update: done and unresolved issues, a subtask, a blocked and flagged
+// issue, and an issue carried over from the previous sprint.
+var sampleIssues = []sampleIssue{
+	{
+		key:         "SE-101",
+		summary:     "Add support for exporting the reports as CSV",
+		status:      "Done",
+		issueType:   "Story",
+		done:        true,
+		epicKey:     "SE-10",
+		epic:        "Reporting",
+		labels:      []string{"backend"},
+		components:  []string{"API"},
+		storyPoints: 5,
+		timeSpent:   6 * time.Hour,
+		note:        "Released in v1.4.0.",
+		priority:    "High",
+		startedDay:  1,
+		resolvedDay: 3,
+		links: []report.LinkedIssue{
+			{Relation: "blocks", Key: "OPS-51", Summary: "Publish the export API in the partner portal", Status: "To Do"},
+		},
+	},
+	{
+		key:         "SE-102",
+		summary:     "Write the documentation of the export endpoint",
+		status:      "Done",
+		issueType:   "Sub-task",
+		done:        true,
+		epicKey:     "SE-10",
+		epic:        "Reporting",
+		labels:      []string{"docs"},
+		components:  []string{"Docs"},
+		storyPoints: 1,
+		timeSpent:   time.Hour,
+		unlogged:    true,
+		parent:      "SE-101",
+		priority:    "Medium",
+		startedDay:  3,
+		resolvedDay: 4,
+	},
+	{
+		key:         "SE-103",
+		summary:     "Fix the pagination of the search results when filtering by date",
+		status:      "In Progress",
+		issueType:   "Bug",
+		epicKey:     "SE-20",
+		epic:        "Search",
+		labels:      []string{"backend", "bug"},
+		components:  []string{"Search"},
+		storyPoints: 3,
+		timeSpent:   4*time.Hour + 30*time.Minute,
+		carriedOver: true,
+		note:        "The root cause is found, the fix is under way.",
+		spillReason: "The fix needs the new search index, which is rolled out next sprint.",
+		priority:    "Highest",
+		startedDay:  2,
+		dueDay:      9,
+		links: []report.LinkedIssue{
+			{Relation: "depends on", Key: "IDX-12", Summary: "Roll out the new search index", Status: "In Progress"},
+		},
+	},
+	{
+		key:         "SE-104",
+		summary:     "Migrate the dashboard to the new design system",
+		status:      "In Review",
+		issueType:   "Story",
+		epicKey:     "SE-30",
+		epic:        "Dashboard",
+		labels:      []string{"frontend"},
+		components:  []string{"Web"},
+		storyPoints: 8,
+		timeSpent:   12 * time.Hour,
+		priority:    "Medium",
+		startedDay:  5,
+		dueDay:      13,
+	},
+	{
+		key:         "SE-105",
+		summary:     "Upgrade the database to the next major version",
+		status:      "To Do",
+		issueType:   "Task",
+		labels:      []string{"ops"},
+		components:  []string{"Database"},
+		storyPoints: 2,
+		blockedBy:   []string{"OPS-42"},
+		flagReason:  "Waiting for the maintenance window of the ops team.",
+		priority:    "Low",
+		links: []report.LinkedIssue{
+			{Relation: "is blocked by", Key: "OPS-42", Summary: "Schedule the maintenance window of the database", Status: "Selected for Development"},
+		},
+	},
+}
+
+// sampleCarriedOverIssue is the issue left unresolved at the end of the
+// previous sprint, which was moved back to the backlog instead of spilling
+// over to the sample sprint.
+var sampleCarriedOverIssue = sampleIssue{
+	key:         "SE-98",
+	summary:     "Remove the deprecated endpoints of the reports API",
+	status:      "To Do",
+	issueType:   "Task",
+	epicKey:     "SE-10",
+	epic:        "Reporting",
+	labels:      []string{"backend"},
+	components:  []string{"API"},
+	storyPoints: 2,
+	priority:    "Low",
+}
+
+// SampleUpdate assembles a sprint update from built-in sample issues instead
+// of fetching them from Jira, so templates can be rendered and validated
+// without any network calls. The configured grouping, ordering, and
+// subtask settings are applied to the sample issues, and the optional
+// sections, like the pull requests or the kudos suggestions, are filled in
+// if they are enabled. When no sprint is configured, a sample sprint is used.
+func SampleUpdate(config Config) (*report.Update, error) {
+	if err := report.ValidateSubtaskMode(config.Subtasks); err != nil {
+		return nil, err
+	}
+
+	if err := report.ValidateGroupBy(config.GroupBy); err != nil {
+		return nil, err
+	}
+
+	if err := report.ValidateSortBy(config.SortBy); err != nil {
+		return nil, err
+	}
+
+	if err := jira.ValidateOptionalFields(config.IssueFields); err != nil {
+		return nil, err
+	}
+
+	if err := report.ValidateAnnotations(config.Annotations); err != nil {
+		return nil, err
+	}
+
+	if err := report.ValidateSections(config.Sections, config.SectionTitles); err != nil {
+		return nil, err
+	}
+
+	if err := render.ValidateFormats(config.PlainFormats); err != nil {
+		return nil, err
+	}
+
+	if err := i18n.Validate(config.Language); err != nil {
+		return nil, err
+	}
+
+	if err := config.validatePeriod(); err != nil {
+		return nil, err
+	}
+
+	if config.Assignee != "" && len(config.Assignees) > 0 {
+		return nil, ErrAssigneeAndTeam
+	}
+
+	if config.Sprint == "" && !config.isPeriod() {
+		config.Sprint = sampleSprint
+	}
+
+	if config.ServerURL == "" {
+		config.ServerURL = sampleServerURL
+	}
+
+	if config.Assignee != "" {
+		config.assigneeName = config.Assignee
+	}
+
+	startDate := time.Now().Truncate(24 * time.Hour).Add(-sampleSprintLength / 2)
+	endDate := startDate.Add(sampleSprintLength)
+	if config.isPeriod() {
+		startDate, endDate = config.From, config.until()
+	}
+
+	config.sprint = &jira.Sprint{
+		ID:        1,
+		Name:      config.Name(),
+		State:     "active",
+		StartDate: &startDate,
+		EndDate:   &endDate,
+	}
+
+	if err := config.CheckTemplate(); err != nil {
+		return nil, err
+	}
+
+	title, err := config.Title()
+	if err != nil {
+		return nil, err
+	}
+
+	issues, members := config.filterIssues(config.sampleIssues())
+
+	update := report.NewUpdate(title, issues, members, config.updateOptions())
+	update.Sprint = config.Name()
+	update.Assignee = config.assigneeName
+
+	titleData := config.TitleData()
+	update.StartDate = titleData.StartDate
+	update.EndDate = titleData.EndDate
+	update.DaysRemaining = titleData.DaysRemaining
+
+	if !config.EndOfSprint && !config.isConsolidated() {
+		carriedOver := config.newSampleIssue(&sampleCarriedOverIssue)
+		carriedOver.Sprints = []jira.Sprint{{Name: samplePreviousSprint, State: "closed"}}
+
+		update.SetCarriedOver(samplePreviousSprint, report.Issues{
+			sampleCarriedOverIssue.status: {carriedOver},
+		})
+		update.CarriedOver.Truncate(config.SummaryLength)
+	}
+
+	if config.Tempo != nil {
+		update.Timesheet = report.NewTimesheet(update.Issues, config.EndOfSprint)
+	}
+
+	if report.HasSection(config.Sections, report.SectionSummary) {
+		update.Summary = sampleSummary
+	}
+
+	if report.HasSection(config.Sections, report.SectionThemes) {
+		update.Themes = update.Issues.Themes(config.ServerURL)
+		setProgress(update.Themes, sampleEpicProgress)
+	}
+
+	if config.EndOfSprint && !config.isConsolidated() {
+		update.Stats = &report.Stats{
+			CommittedPoints:    21,
+			CompletedPoints:    16,
+			CompletedIssues:    4,
+			NotCompletedIssues: 2,
+			AddedIssues:        1,
+		}
+	}
+
+	if config.EndOfSprint && config.hasAnnotation(report.AnnotationTimeline) {
+		update.CycleTime = update.Issues.CycleTime()
+	}
+
+	if config.Diff {
+		// The first issue is done since the previous update, the third one
+		// moved, the fourth one is unchanged, and the rest is new.
+		update.Diff(report.Issues{
+			"In Progress": {{Key: sampleIssues[0].key, Status: "In Progress"}},
+			"To Do":       {{Key: sampleIssues[2].key, Status: "To Do"}},
+			"In Review":   {{Key: sampleIssues[3].key, Status: "In Review"}},
+		})
+	}
+
+	if config.Calendar != nil {
+		update.TimeOff = fmt.Sprintf("Off %s for PTO.", endDate.AddDate(0, 0, -1).Format("Monday, Jan 2"))
+	}
+
+	if config.SuggestKudos {
+		update.SuggestedKudos = []report.KudosSuggestion{
+			{Name: "Alice", Commented: []string{"SE-103"}},
+			{Name: "Bob", Unblocked: []string{"SE-104"}},
+		}
+	}
+
+	if len(config.CodeHosts) > 0 {
+		mergedAt := startDate.Add(3 * 24 * time.Hour)
+		update.PullRequests = report.NewPullRequests(config.ServerURL, []codehost.PullRequest{
+			{
+				Repository: "example/reports",
+				Number:     42,
+				Title:      "Export the reports as CSV",
+				URL:        "https://github.com/example/reports/pull/42",
+				Branch:     "se-101-csv-export",
+				State:      "closed",
+				CreatedAt:  startDate.Add(24 * time.Hour),
+				MergedAt:   &mergedAt,
+			},
+			{
+				Repository: "example/search",
+				Number:     7,
+				Title:      "SE-103: Fix the pagination when filtering by date",
+				URL:        "https://github.com/example/search/pull/7",
+				Branch:     "fix-pagination",
+				State:      "open",
+				CreatedAt:  startDate.Add(5 * 24 * time.Hour),
+			},
+		})
+	}
+
+	if len(config.GitRepositories) > 0 && report.HasSection(config.Sections, report.SectionCommits) {
+		update.SetCommits(config.ServerURL, []git.Commit{
+			{
+				Repository: "example/search",
+				Hash:       "9f2c4e1b7a3d5c8e0f6a2b4d1c3e5f7a9b0c2d4e",
+				Subject:    "Keep the date filter when paging the results",
+				Ref:        "se-103-pagination",
+				AuthoredAt: startDate.Add(4 * 24 * time.Hour),
+			},
+			{
+				Repository: "example/infrastructure",
+				Hash:       "3b8d0e2f4a6c8e1d3f5b7a9c0e2d4f6b8a1c3e5d",
+				Subject:    "Raise the memory limit of the search workers",
+				Ref:        "main",
+				AuthoredAt: startDate.Add(2 * 24 * time.Hour),
+			},
+			{
+				Repository: "example/reports",
+				Hash:       "c1e3a5b7d9f0e2c4a6b8d0f1e3c5a7b9d2f4e6a8",
+				Subject:    "SE-101: Stream the CSV rows instead of buffering them",
+				Ref:        "se-101-csv-export",
+				AuthoredAt: startDate.Add(24 * time.Hour),
+			},
+		})
+	}
+
+	update.Provenance = config.provenance(config.issueQueries())
+	return update, nil
+}
+
+// SampleIssues returns the built-in sample issues of a sprint starting at the
+// given date, with every optional attribute set, like the story points, the
+// spillover reasons, and the start dates, so the fixtures of the tests can be
+// built from the issues of the sample update.
+func SampleIssues(start time.Time) []report.Issue {
+	end := start.Add(sampleSprintLength)
+	config := Config{
+		ServerURL:        sampleServerURL,
+		StoryPointsField: "sample",
+		Worklog:          true,
+		ProgressNotes:    true,
+		SpilloverReasons: true,
+		Annotations:      []report.Annotation{report.AnnotationTimeline},
+		sprint:           &jira.Sprint{ID: 1, Name: sampleSprint, State: "active", StartDate: &start, EndDate: &end},
+	}
+
+	issues := make([]report.Issue, 0, len(sampleIssues))
+	for i := range sampleIssues {
+		issues = append(issues, config.newSampleIssue(&sampleIssues[i]))
+	}
+
+	return issues
+}
+
+// sampleIssues returns the sample issues grouped by status. In team mode, the
+// issues are distributed among the members in turn.
+func (c *Config) sampleIssues() (report.Issues, []report.Member) {
+	issues := make(report.Issues)
+	memberIssues := make([]report.Issues, len(c.Assignees))
+
+	for i := range sampleIssues {
+		issue := c.newSampleIssue(&sampleIssues[i])
+		issues[issue.Status] = append(issues[issue.Status], issue)
+
+		if len(c.Assignees) > 0 {
+			assigned := memberIssues[i%len(c.Assignees)]
+			if assigned == nil {
+				assigned = make(report.Issues)
+				memberIssues[i%len(c.Assignees)] = assigned
+			}
+
+			assigned[issue.Status] = append(assigned[issue.Status], issue)
+		}
+	}
+
+	members := make([]report.Member, 0, len(c.Assignees))
+	for i, assignee := range c.Assignees {
+		members = append(members, report.NewMember(assignee, memberIssues[i]))
+	}
+
+	return issues, members
+}
+
+// newSampleIssue returns the issue of the update from the sample issue. The
+// story points, the time spent, and the progress note are set only if they
+// are enabled.
+func (c *Config) newSampleIssue(sample *sampleIssue) report.Issue {
+	sprints := []jira.Sprint{*c.sprint}
+	if sample.carriedOver {
+		sprints = append([]jira.Sprint{{Name: samplePreviousSprint, State: "closed"}}, sprints...)
+	}
+
+	issue := report.Issue{
+		Key:           sample.key,
+		Summary:       sample.summary,
+		URL:           fmt.Sprintf("%s/browse/%s", c.ServerURL, sample.key),
+		Status:        sample.status,
+		Type:          sample.issueType,
+		BlockedBy:     sample.blockedBy,
+		Flagged:       sample.flagReason != "",
+		BlockedReason: sample.flagReason,
+		Done:          sample.done,
+		Sprints:       sprints,
+		Parent:        sample.parent,
+		Project:       "Sample project",
+		ProjectKey:    sampleProjectKey,
+		Labels:        sample.labels,
+		Components:    sample.components,
+		EpicKey:       sample.epicKey,
+		Epic:          sample.epic,
+		Priority:      sample.priority,
+	}
+
+	for _, link := range sample.links {
+		link.URL = fmt.Sprintf("%s/browse/%s", c.ServerURL, link.Key)
+		issue.Links = append(issue.Links, link)
+	}
+
+	issue.StatusCategory = sampleStatusCategories[sample.status]
+
+	if sample.resolvedDay != 0 {
+		issue.Resolved = c.sprint.StartDate.AddDate(0, 0, sample.resolvedDay)
+	}
+
+	if sample.dueDay != 0 {
+		issue.Due = c.sprint.StartDate.AddDate(0, 0, sample.dueDay)
+	}
+
+	if c.hasAnnotation(report.AnnotationTimeline) {
+		if sample.startedDay != 0 {
+			issue.Started = c.sprint.StartDate.AddDate(0, 0, sample.startedDay)
+		}
+
+		if sample.done {
+			issue.Finished = issue.Resolved
+		}
+	}
+
+	if c.StoryPointsField != "" {
+		issue.StoryPoints = sample.storyPoints
+	}
+
+	if c.Worklog || (c.Tempo != nil && !sample.unlogged) {
+		issue.TimeSpent = sample.timeSpent
+	}
+
+	if c.ProgressNotes {
+		issue.Note = sample.note
+	}
+
+	if c.SpilloverReasons {
+		issue.SpilloverReason = sample.spillReason
+	}
+
+	return issue
+}
@@ -0,0 +1,38 @@
+package sprint
+
+import (
+	"context"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// addSpilloverReasons sets the spillover reason of the spillovers to the last
+// comment of the issues. The activities already fetched are reused, the rest
+// is fetched one by one.
+func addSpilloverReasons(ctx context.Context, client *gojira.Client, update *report.Update, activities map[string]*gojira.Issue) error {
+	var keys []string
+	for _, statusIssues := range update.Spillovers {
+		for _, issue := range statusIssues {
+			keys = append(keys, issue.Key)
+		}
+	}
+
+	for _, key := range keys {
+		activity, ok := activities[key]
+		if !ok {
+			var err error
+			if activity, err = jira.FetchActivity(ctx, client, key); err != nil {
+				return err
+			}
+		}
+
+		if reason := jira.LastComment(activity); reason != "" {
+			update.SetSpilloverReason(key, reason)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,1305 @@
+// Package sprint generates sprint updates from the Jira issues of a sprint.
+//
+// The package is independent of any CLI framework, so it can be embedded in
+// other tools; the sprint-update command is a thin adapter around it. It ties
+// together the jira, report, and render packages, which can be used on their
+// own for finer control.
+package sprint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/cache"
+	"gabor-boros/sprint-update/pkg/calendar"
+	"gabor-boros/sprint-update/pkg/codehost"
+	"gabor-boros/sprint-update/pkg/history"
+	"gabor-boros/sprint-update/pkg/hook"
+	"gabor-boros/sprint-update/pkg/i18n"
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/llm"
+	"gabor-boros/sprint-update/pkg/logging"
+	"gabor-boros/sprint-update/pkg/metrics"
+	"gabor-boros/sprint-update/pkg/oauth"
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/tempo"
+	"gabor-boros/sprint-update/pkg/tracker"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// DefaultJQL represents the JQL query used to search tickets of the
+// assignee within the given sprint. The assignee and the sprint are JQL
+// values, like the quoted names returned by jira.QuoteJQL, and the sprint
+// field is the JQL name of the field returned by jira.SprintJQLField.
+const DefaultJQL string = `assignee = %s AND %s = %s`
+
+// DefaultExcludeStatuses lists the statuses of the issues left out of the
+// updates by default, like the recurring chores.
+var DefaultExcludeStatuses = []string{"Recurring"}
+
+// currentUser is the JQL function referring to the authenticated user.
+const currentUser = "currentUser()"
+
+// ErrMissingSprint is returned when neither a sprint name, a board, nor a JQL
+// query is set in the Config. In worklog mode, the sprint name or board is
+// required even with a JQL query, since the date range of the sprint is used.
+var ErrMissingSprint = errors.New("sprint name or board is required")
+
+// ErrSprintNameAndID is returned when both sprint names and a sprint ID are
+// set in the Config.
+var ErrSprintNameAndID = errors.New("a sprint name and a sprint ID cannot be used together")
+
+// Config holds every setting needed to generate a sprint update.
+type Config struct {
+	// ServerURL is the base URL of the Jira server.
+	ServerURL string
+	// AuthType is the authentication method. When empty, jira.AuthBasic is
+	// used.
+	AuthType jira.AuthType
+	// Username is the Jira user's username, or email address when using
+	// jira.AuthToken.
+	Username string
+	// Password is the Jira user's password.
+	Password string
+	// Token is the Jira Cloud API token or the Personal Access Token.
+	Token string
+	// OAuth is the source of the access tokens used by jira.AuthOAuth.
+	OAuth *oauth.TokenSource
+	// SummaryLength is the number of characters the issue summaries are
+	// truncated to. When zero, the summaries are not truncated.
+	SummaryLength int
+	// Subtasks is the way the subtasks are listed. When empty, the subtasks
+	// are listed as peers of their parents.
+	Subtasks report.SubtaskMode
+	// GroupBy is the attribute the issues are grouped by. When empty, the
+	// issues are grouped by status.
+	GroupBy report.GroupBy
+	// SortBy is the attribute the issues are sorted by within their groups.
+	// When empty, the issues are sorted by their rank.
+	SortBy report.SortBy
+	// IssueFields lists the optional issue fields requested from Jira, among
+	// jira.OptionalFields, like "labels" or "components", to keep the search
+	// results small. When nil, every optional field is requested. The fields
+	// read by the enabled features, like the labels when filtering by label,
+	// are requested regardless.
+	IssueFields []string
+	// StatusEmojis maps the statuses and display groups to the emojis they
+	// are decorated with, overriding the default emojis.
+	StatusEmojis map[string]string
+	// Annotations lists the annotations rendered on the issue lines, like
+	// the resolution or the due dates.
+	Annotations []report.Annotation
+	// Sections lists the sections of the body of the update in the order
+	// they are rendered by the built-in templates. When empty,
+	// report.DefaultSections are rendered.
+	Sections []report.Section
+	// SectionTitles maps the sections to the titles replacing their default
+	// headings, like "Shoutouts" for "kudos".
+	SectionTitles map[string]string
+	// ExpandedGroups lists the status groups, or the other groups, whose
+	// collapsible blocks are expanded by default, like "In Progress".
+	ExpandedGroups []string
+	// DetailsThreshold is the number of issues up to which the groups are
+	// listed without collapsible blocks. When zero, every group is wrapped.
+	DetailsThreshold int
+	// PlainFormats lists the formats rendering the groups without
+	// collapsible blocks, like "markdown" for the Markdown renderers not
+	// supporting HTML.
+	PlainFormats []string
+	// MaxItems maps the groups to the maximum number of issues listed in
+	// them, folding the rest of the issues into a link to the Jira search
+	// listing them. The "*" key sets the maximum of the other groups.
+	MaxItems map[string]int
+	// FoldBy is the attribute choosing the issues listed in the groups
+	// having more issues than their maximum. When empty, the issues listed
+	// first are kept.
+	FoldBy report.FoldBy
+	// Language is the language the headings and the messages of the built-in
+	// templates are translated to, like "de". When empty,
+	// i18n.DefaultLanguage is used.
+	Language string
+	// Redact strips the internal issue keys, the issue URLs, and the pull
+	// requests from the update, so it can be shared with external
+	// stakeholders. The updates redacted are neither saved to the state file
+	// nor archived.
+	Redact bool
+	// RedactAliases maps the issue and project keys to the aliases they are
+	// replaced by in redacted updates, like "SE" to "SEARCH".
+	RedactAliases map[string]string
+	// Workers is the number of result pages fetched concurrently. When zero,
+	// jira.DefaultWorkers is used.
+	Workers int
+	// MaxAttempts is the number of attempts made when Jira rate limits the
+	// requests or is temporarily unavailable. When zero,
+	// jira.DefaultMaxAttempts is used.
+	MaxAttempts int
+	// RetryTimeout is the total time spent on a Jira request, including the
+	// retries. When zero, jira.DefaultRetryTimeout is used.
+	RetryTimeout time.Duration
+	// Transport is the underlying HTTP transport of the Jira requests, like a
+	// jira.Recorder or jira.Replayer. When nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// Cache is the cache of the slow-changing Jira metadata, like the IDs of
+	// the custom fields, the sprints, and the users. When nil, the metadata is
+	// fetched on every run.
+	Cache *cache.Cache
+	// Tracker is the issue tracker the issues are fetched from instead of
+	// Jira, like Linear or Azure Boards. When nil, the issues are fetched
+	// from Jira, which supports every feature.
+	Tracker tracker.Tracker
+	// Sprint is the name of the sprint to generate the update for.
+	Sprint string
+	// SprintID is the ID of the sprint to generate the update for, instead of
+	// its name. The name of the sprint is resolved by BuildUpdate.
+	SprintID int
+	// Sprints lists further sprints covered by the update besides Sprint,
+	// producing a consolidated update, like the update of a month.
+	Sprints []string
+	// From is the start of the date range covered by the update, instead of
+	// sprints. When set, the issues assigned to the user and updated within
+	// the date range are listed.
+	From time.Time
+	// Until is the end of the date range covered by the update. When zero,
+	// the current time is used.
+	Until time.Time
+	// Board is the ID of the Jira Agile board. When Sprint is empty, the
+	// active sprint of the board is used; otherwise the sprint of the name is
+	// looked up on the board, so sprints of other boards of the same name are
+	// left out.
+	Board int
+	// EndOfSprint indicates that an end of sprint update is generated.
+	EndOfSprint bool
+	// BlockedStatuses lists the statuses considered as blocked, besides
+	// issues having an inward "is blocked by" issue link or being flagged.
+	BlockedStatuses []string
+	// BlockedLabels lists the labels marking the issues as blocked, like
+	// "needs-help".
+	BlockedLabels []string
+	// ExcludeStatuses lists the statuses of the issues left out of the
+	// update, like DefaultExcludeStatuses. The issues are filtered after they
+	// are fetched, so the filters apply to custom queries and to every
+	// tracker.
+	ExcludeStatuses []string
+	// ExcludeLabels lists the labels of the issues left out of the update,
+	// like "chore".
+	ExcludeLabels []string
+	// IncludeTypes lists the issue types the update is restricted to, like
+	// "Story" and "Bug". When empty, the issues of every type are included.
+	IncludeTypes []string
+	// Projects lists the keys of the projects the update is restricted to,
+	// like "SE" and "OPS". When empty, the issues of every project are
+	// included.
+	Projects []string
+	// SplitByProject indicates that the worked on section lists the issues
+	// per project, with the totals of every project.
+	SplitByProject bool
+	// StatusGroups maps display groups to the statuses they consist of, like
+	// "In progress" to "In Review" and "In QA".
+	StatusGroups map[string][]string
+	// StatusOrder lists the statuses or display groups in the order they are
+	// rendered. The rest follows in alphabetical order.
+	StatusOrder []string
+	// HiddenStatuses lists the statuses or display groups left out of the
+	// update.
+	HiddenStatuses []string
+	// StoryPointsField is the ID of the story points custom field, like
+	// "customfield_10016". When set, the story point totals are rendered.
+	StoryPointsField string
+	// SprintField is the ID of the sprint custom field, like
+	// customfield_10020, used when the sprint field found by its schema type
+	// is not the field of the issues. When empty, the field is discovered.
+	SprintField string
+	// Assignee is the account ID or the username of the person the update is
+	// generated for, like a teammate who is out sick. When empty, the update
+	// is generated for the authenticated user.
+	Assignee string
+	// Assignees lists the team members to generate a team update for. When
+	// empty, the update is generated for the authenticated user.
+	Assignees []string
+	// JQL overrides the query used to search issues. When empty, DefaultJQL
+	// is used with the sprint name. In team mode, the query is restricted to
+	// each assignee.
+	JQL string
+	// JQLExtra lists additional clauses restricting the query.
+	JQLExtra []string
+	// TitleTemplate overrides the template used to render the title of the
+	// update. When empty, render.DefaultTitleTemplate is used.
+	TitleTemplate string
+	// Format is the name of the output format. When empty,
+	// render.DefaultFormat is used.
+	Format string
+	// Template overrides the template used to render the update. When empty,
+	// the template is read from TemplateFile.
+	Template string
+	// TemplateFile is the path of the template file used to render the update.
+	// When both Template and TemplateFile are empty, the built-in template of
+	// the format is used.
+	TemplateFile string
+	// MidSprintTemplateFile and EndOfSprintTemplateFile are the paths of the
+	// template files used to render the mid-sprint and the end of sprint
+	// updates, like a mid-sprint template forecasting the remaining work and
+	// an end of sprint template prompting for the retrospective. When set,
+	// they override TemplateFile for their update type.
+	MidSprintTemplateFile   string
+	EndOfSprintTemplateFile string
+	// StylesheetFile is the path of the CSS file embedded in the styled HTML
+	// documents by the stylesheet template function. When empty,
+	// render.DefaultStylesheet is embedded.
+	StylesheetFile string
+	// TemplatesDir is the directory of the partial template files, replacing
+	// the templates of the same name of the built-in or custom template, like
+	// the issueLine partial rendering the issues. The partials are not used
+	// when empty.
+	TemplatesDir string
+	// Hooks lists the external commands run before fetching the issues,
+	// after fetching them, and after rendering the update.
+	Hooks hook.Hooks
+	// CodeHosts lists the code hosts, like GitHub, GitLab, and Bitbucket,
+	// the pull requests opened or merged during the sprint are listed from.
+	// When empty, no pull requests are listed.
+	CodeHosts []codehost.CodeHost
+	// GitRepositories lists the paths of the local clones, or the URLs of
+	// the remotes, of the git repositories the commits of the user authored
+	// during the sprint are listed from, so the work without issues shows up
+	// too. When empty, no commits are listed.
+	GitRepositories []string
+	// GitAuthors lists the names or email addresses of the user as the
+	// author of the commits. When empty, the email address configured for
+	// git in each repository is used.
+	GitAuthors []string
+	// GitCacheDir is the directory the remotes of GitRepositories are
+	// mirrored into.
+	GitCacheDir string
+	// Worklog indicates that the update is generated from the issues the user
+	// logged time on within the date range of the sprint, instead of the
+	// issues assigned to the user in the sprint.
+	Worklog bool
+	// SuggestKudos indicates that kudos are suggested for the colleagues who
+	// commented on the issues or resolved their blockers.
+	SuggestKudos bool
+	// ProgressNotes indicates that the last comment of the user written on
+	// the issues within the sprint is rendered under the issues.
+	ProgressNotes bool
+	// ProgressMarker is the marker of the comments used as progress notes,
+	// like "#update". When set, the last comment of anyone containing the
+	// marker is used instead of the last comment of the user.
+	ProgressMarker string
+	// SpilloverReasons indicates that the reasons the issues spilled over are
+	// rendered next to the spillovers, taken from the SpilloverReasonField,
+	// or from the last comment of the issues if no field is set.
+	SpilloverReasons bool
+	// SpilloverReasonField is the ID of the custom field holding the reason
+	// the issues spilled over, like "customfield_10050".
+	SpilloverReasonField string
+	// Calendar is the calendar the time off within the sprint is looked up
+	// in. When nil, the time off is not filled in.
+	Calendar calendar.Source
+	// Tempo is the Tempo Timesheets client the time logged within the sprint
+	// is read from, instead of the Jira worklogs. When nil, the hours are not
+	// summarized.
+	Tempo *tempo.Client
+	// LanguageModel is the language model client writing the narrative
+	// summary of the summary section. When nil, no data is sent to a
+	// language model, and the summary section cannot be rendered.
+	LanguageModel *llm.Client
+	// TimeOffKeywords are the words of the calendar event summaries marking
+	// time off. When empty, calendar.DefaultTimeOffKeywords are used.
+	TimeOffKeywords []string
+	// StateFile is the path of the file the issues left unresolved at the end
+	// of the sprint are saved to, so they are listed as carried over in the
+	// mid-sprint update of the next sprint. When empty, no state is kept.
+	StateFile string
+	// HistoryDir is the directory the generated updates are archived in. When
+	// empty, the updates are not archived.
+	HistoryDir string
+	// Diff indicates that the issues are annotated with their changes since
+	// the previous update of the sprint found in the history directory.
+	Diff bool
+	// Provenance is the name and the version of the tool rendered in the
+	// provenance footer of the update, like "sprint-update 1.2.0". When
+	// empty, the footer is not rendered.
+	Provenance string
+
+	// sprint is the resolved sprint, holding its start and end dates.
+	sprint *jira.Sprint
+	// sprintID is the ID of the sprint resolved by ResolveSprint, whose
+	// issues are fetched using the Jira Agile API instead of searching by the
+	// name of the sprint.
+	sprintID int
+	// assigneeName is the display name of the assignee, resolved by
+	// BuildUpdate.
+	assigneeName string
+	// historyEntry is the archived update, saved by SaveHistory.
+	historyEntry *history.Entry
+	// sprintField is the ID of the sprint field the JQL queries search by,
+	// resolved by BuildUpdate. When empty, the queries use the default name
+	// of the field.
+	sprintField string
+	// priorities lists the priorities of the Jira server from the highest to
+	// the lowest, fetched by BuildUpdate when sorting by priority.
+	priorities []string
+}
+
+// jql returns the JQL query used for searching the sprint's issues of the
+// given assignee. If the assignee is empty, the configured assignee or the
+// authenticated user is used.
+func (c *Config) jql(assignee string) string {
+	if assignee == "" {
+		assignee = c.Assignee
+	}
+
+	if c.JQL != "" {
+		field := "assignee"
+		if c.Worklog {
+			field = "worklogAuthor"
+		}
+
+		clauses := c.jqlClauses()
+		if assignee != "" {
+			clauses = append([]string{fmt.Sprintf("%s = %s", field, jira.QuoteJQL(assignee))}, clauses...)
+		}
+
+		return jira.JoinJQL(c.JQL, clauses...)
+	}
+
+	user := jqlUser(assignee)
+
+	if c.Worklog {
+		return jira.JoinJQL(c.worklogJQL(user), c.jqlClauses()...)
+	}
+
+	if c.isConsolidated() {
+		return jira.JoinJQL(c.consolidatedJQL(user), c.jqlClauses()...)
+	}
+
+	return jira.JoinJQL(fmt.Sprintf(DefaultJQL, user, jira.SprintJQLField(c.sprintField), jira.QuoteJQL(c.Sprint)), c.jqlClauses()...)
+}
+
+// jqlUser returns the JQL value of the assignee, or the function referring
+// to the authenticated user if the assignee is empty.
+func jqlUser(assignee string) string {
+	if assignee == "" {
+		return currentUser
+	}
+
+	return jira.QuoteJQL(assignee)
+}
+
+// fetchesBySprintID reports whether the issues are fetched by the ID of the
+// resolved sprint instead of searching by the name of the sprint. Custom
+// queries, the worklog mode and consolidated updates search by JQL.
+func (c *Config) fetchesBySprintID() bool {
+	return c.sprintID != 0 && c.JQL == "" && !c.Worklog && !c.isConsolidated()
+}
+
+// jqlClauses returns the clauses restricting the query: the extra clauses,
+// and the clause of the projects the update is restricted to.
+func (c *Config) jqlClauses() []string {
+	clauses := append([]string{}, c.JQLExtra...)
+
+	if len(c.Projects) > 0 {
+		keys := make([]string, 0, len(c.Projects))
+		for _, key := range c.Projects {
+			keys = append(keys, jira.QuoteJQL(key))
+		}
+
+		clauses = append(clauses, fmt.Sprintf("project in (%s)", strings.Join(keys, ", ")))
+	}
+
+	return clauses
+}
+
+// validateQueryValues checks the values inserted into the JQL queries: the
+// sprint names, the assignees, and the project keys.
+func (c *Config) validateQueryValues() error {
+	var assignee []string
+	if c.Assignee != "" {
+		assignee = []string{c.Assignee}
+	}
+
+	settings := []struct {
+		name   string
+		values []string
+	}{
+		{"sprint", c.sprintNames()},
+		{"assignee", assignee},
+		{"assignees", c.Assignees},
+		{"projects", c.Projects},
+	}
+
+	for _, setting := range settings {
+		for _, value := range setting.values {
+			if err := jira.CheckJQLValue(setting.name, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fetchIssues fetches the issues of the given assignee using the configured
+// number of workers: the issues of the resolved sprint by its ID, or the
+// issues matching the JQL query otherwise. If the assignee is empty, the
+// configured assignee or the authenticated user is used.
+func (c *Config) fetchIssues(ctx context.Context, client *gojira.Client, assignee string, customFields report.CustomFields) ([]gojira.Issue, error) {
+	workers := c.Workers
+	if workers == 0 {
+		workers = jira.DefaultWorkers
+	}
+
+	fields := append(c.issueFields(), customFields.IDs()...)
+
+	if c.fetchesBySprintID() {
+		return jira.FetchSprintIssues(ctx, client, c.sprintID, c.issueQuery(assignee), workers, fields...)
+	}
+
+	return jira.FetchIssuesWithWorkers(ctx, client, c.issueQuery(assignee), workers, fields...)
+}
+
+// issueQuery returns the JQL query the issues of the assignee are fetched
+// with. When fetching by the ID of the sprint, the query only restricts the
+// issues of the sprint.
+func (c *Config) issueQuery(assignee string) string {
+	if !c.fetchesBySprintID() {
+		return c.jql(assignee)
+	}
+
+	if assignee == "" {
+		assignee = c.Assignee
+	}
+
+	return jira.JoinJQL("assignee = "+jqlUser(assignee), c.jqlClauses()...)
+}
+
+// issueQueries returns the JQL queries the issues of the update are fetched
+// with, one per member in team mode.
+func (c *Config) issueQueries() []string {
+	if len(c.Assignees) == 0 {
+		return []string{c.issueQuery("")}
+	}
+
+	queries := make([]string, 0, len(c.Assignees))
+	for _, assignee := range c.Assignees {
+		queries = append(queries, c.issueQuery(assignee))
+	}
+
+	return queries
+}
+
+// provenance returns the provenance of the update fetching the issues with
+// the queries, or nil if the provenance footer is not rendered.
+func (c *Config) provenance(queries []string) *report.Provenance {
+	if c.Provenance == "" {
+		return nil
+	}
+
+	sprintID := ""
+	switch {
+	case c.sprintID != 0:
+		sprintID = strconv.Itoa(c.sprintID)
+	case c.sprint != nil && c.sprint.ID != 0:
+		sprintID = strconv.Itoa(c.sprint.ID)
+	}
+
+	return report.NewProvenance(c.Provenance, sprintID, queries)
+}
+
+// issueFields returns the optional issue fields requested from Jira: the
+// configured fields and the fields read by the enabled features.
+func (c *Config) issueFields() []string {
+	if c.IssueFields == nil {
+		return append([]string{}, jira.OptionalFields...)
+	}
+
+	required := map[string]bool{
+		"issuetype": len(c.IncludeTypes) > 0 || c.needsEpics(),
+		"labels":    len(c.BlockedLabels) > 0 || len(c.ExcludeLabels) > 0 || c.GroupBy == report.GroupByLabel,
+		"priority":  c.SortBy == report.SortByPriority || c.hasAnnotation(report.AnnotationPriority),
+		"assignee":  len(c.Assignees) > 0,
+	}
+
+	var fields []string
+	for _, field := range jira.OptionalFields {
+		if required[field] || containsFold(c.IssueFields, field) {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
+// hasAnnotation reports whether the issues are annotated with the annotation.
+func (c *Config) hasAnnotation(annotation report.Annotation) bool {
+	for _, a := range c.Annotations {
+		if a == annotation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasCustomJQL reports whether the query is customized, hence it should be
+// validated before searching.
+func (c *Config) hasCustomJQL() bool {
+	return c.JQL != "" || len(c.JQLExtra) > 0
+}
+
+// auth returns the configured credentials.
+func (c *Config) auth() jira.Auth {
+	return jira.Auth{
+		Type:     c.AuthType,
+		Username: c.Username,
+		Password: c.Password,
+		Token:    c.Token,
+		OAuth:    c.OAuth,
+		Transport: &jira.RetryTransport{
+			MaxAttempts: c.MaxAttempts,
+			Timeout:     c.RetryTimeout,
+			Transport:   &logging.Transport{Transport: &metrics.Transport{Transport: c.Transport}},
+		},
+	}
+}
+
+// JiraClient returns a new Jira client for the configured server. When using
+// jira.AuthOAuth without a server URL, the site URL of the token is used for
+// the issue links.
+func (c *Config) JiraClient() (*gojira.Client, error) {
+	auth := c.auth()
+
+	if auth.EffectiveType() == jira.AuthOAuth && c.ServerURL == "" && c.OAuth != nil {
+		token, err := c.OAuth.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+
+		c.ServerURL = token.SiteURL
+	}
+
+	return jira.NewClient(c.ServerURL, auth)
+}
+
+// jiraError redacts the credentials from the error returned by Jira, and
+// explains how to fix authentication failures.
+func (c *Config) jiraError(err error) error {
+	auth := c.auth()
+	return auth.WrapError(c.ServerURL, err)
+}
+
+// CheckConnection checks that Jira accepts the configured credentials.
+func (c *Config) CheckConnection(ctx context.Context) error {
+	client, err := c.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	if _, err = jira.FetchCurrentUser(ctx, client); err != nil {
+		return c.jiraError(err)
+	}
+
+	return nil
+}
+
+// CheckSprint checks that the configured sprint exists in Jira, or that the
+// configured board has an active sprint, which is set as the sprint of the
+// update then.
+func (c *Config) CheckSprint(ctx context.Context) error {
+	client, err := c.JiraClient()
+	if err != nil {
+		return err
+	}
+
+	if c.Sprint == "" || c.SprintID != 0 {
+		return c.ResolveSprint(ctx, client)
+	}
+
+	sprintFieldID, err := c.sprintFieldID(ctx, client)
+	if err != nil {
+		return c.jiraError(err)
+	}
+
+	if sprintFieldID == "" {
+		return fmt.Errorf("%w: %s", jira.ErrSprintNotFound, c.Sprint)
+	}
+
+	if _, err = c.findSprint(ctx, client, c.Sprint, sprintFieldID); err != nil {
+		return c.jiraError(err)
+	}
+
+	return nil
+}
+
+// ListSprints returns the future, active, and closed sprints of the given
+// board.
+func (c *Config) ListSprints(ctx context.Context, boardID int) ([]jira.Sprint, error) {
+	client, err := c.JiraClient()
+	if err != nil {
+		return nil, err
+	}
+
+	sprints, err := jira.ListSprints(ctx, client, boardID)
+	if err != nil {
+		return nil, c.jiraError(err)
+	}
+
+	return sprints, nil
+}
+
+// ResolveSprintNames returns the names of the sprints of the configured
+// board best matching the given partial sprint name, like "SE.253" for
+// "253", the most recent first. If more than one name is returned, the name
+// is ambiguous; if none, no sprint of the board matches the name.
+func (c *Config) ResolveSprintNames(ctx context.Context, name string) ([]string, error) {
+	sprints, err := c.ListSprints(ctx, c.Board)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := jira.ResolveSprintName(sprints, name)
+	names := make([]string, 0, len(resolved))
+	for i := range resolved {
+		names = append(names, resolved[i].Name)
+	}
+
+	return names, nil
+}
+
+// SimilarSprints returns the sprints of the configured board whose names are
+// similar to the configured sprint, the most similar first, like the
+// intended sprint of a misspelled name.
+func (c *Config) SimilarSprints(ctx context.Context) ([]jira.Sprint, error) {
+	if c.Board == 0 || c.Sprint == "" {
+		return nil, nil
+	}
+
+	sprints, err := c.ListSprints(ctx, c.Board)
+	if err != nil {
+		return nil, err
+	}
+
+	return jira.MatchSprints(sprints, c.Sprint), nil
+}
+
+// ActiveSprint returns the active sprint of the configured board.
+func (c *Config) ActiveSprint(ctx context.Context) (*jira.Sprint, error) {
+	client, err := c.JiraClient()
+	if err != nil {
+		return nil, err
+	}
+
+	activeSprint, err := c.boardActiveSprint(ctx, client, c.Board)
+	if err != nil {
+		return nil, c.jiraError(err)
+	}
+
+	return activeSprint, nil
+}
+
+// ResolveSprint resolves the sprint of the update by the configured sprint
+// ID, by its name on the configured board, or as the active sprint of the
+// board if no sprint is set. The issues of the resolved sprint are fetched by
+// its ID. If the sprint of the name is not found on the board, the issues are
+// searched by the name of the sprint.
+func (c *Config) ResolveSprint(ctx context.Context, client *gojira.Client) error {
+	if c.sprint != nil || c.isConsolidated() {
+		return nil
+	}
+
+	var s *jira.Sprint
+	var err error
+
+	switch {
+	case c.SprintID != 0:
+		s, err = c.fetchSprint(ctx, client, c.SprintID)
+	case c.Board == 0:
+		return nil
+	case c.Sprint == "":
+		s, err = c.boardActiveSprint(ctx, client, c.Board)
+	default:
+		s, err = c.boardSprint(ctx, client, c.Board, c.Sprint)
+		if errors.Is(err, jira.ErrSprintNotFound) {
+			return nil
+		}
+	}
+
+	if err != nil {
+		return c.jiraError(err)
+	}
+
+	c.Sprint = s.Name
+	c.sprint = s
+	c.sprintID = s.ID
+	return nil
+}
+
+// Validate checks the configuration and parses its templates, so
+// misconfiguration is reported before contacting Jira.
+func (c *Config) Validate() error {
+	if c.Tracker != nil {
+		if err := c.validateTracker(); err != nil {
+			return err
+		}
+	} else if c.Sprint == "" && c.SprintID == 0 && c.Board == 0 && !c.isPeriod() && (c.JQL == "" || c.Worklog) {
+		return ErrMissingSprint
+	}
+
+	if c.SprintID != 0 && len(c.sprintNames()) > 0 {
+		return ErrSprintNameAndID
+	}
+
+	if err := c.validatePeriod(); err != nil {
+		return err
+	}
+
+	if c.Assignee != "" && len(c.Assignees) > 0 {
+		return ErrAssigneeAndTeam
+	}
+
+	if err := c.validateQueryValues(); err != nil {
+		return err
+	}
+
+	if c.Tracker == nil {
+		auth := c.auth()
+		if err := auth.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.hasCustomJQL() {
+		if err := jira.CheckJQL(c.jql("")); err != nil {
+			return err
+		}
+	}
+
+	if err := report.ValidateSubtaskMode(c.Subtasks); err != nil {
+		return err
+	}
+
+	if err := report.ValidateGroupBy(c.GroupBy); err != nil {
+		return err
+	}
+
+	if err := report.ValidateSortBy(c.SortBy); err != nil {
+		return err
+	}
+
+	if err := report.ValidateFoldBy(c.FoldBy); err != nil {
+		return err
+	}
+
+	if err := jira.ValidateOptionalFields(c.IssueFields); err != nil {
+		return err
+	}
+
+	if err := report.ValidateAnnotations(c.Annotations); err != nil {
+		return err
+	}
+
+	if err := report.ValidateSections(c.Sections, c.SectionTitles); err != nil {
+		return err
+	}
+
+	if report.HasSection(c.Sections, report.SectionSummary) && c.LanguageModel == nil {
+		return ErrMissingLanguageModel
+	}
+
+	if err := render.ValidateFormats(c.PlainFormats); err != nil {
+		return err
+	}
+
+	if err := i18n.Validate(c.Language); err != nil {
+		return err
+	}
+
+	return c.CheckTemplate()
+}
+
+// CheckTemplate parses the template of the sprint update and the title
+// template, so template errors are reported before contacting Jira.
+func (c *Config) CheckTemplate() error {
+	if _, err := c.parseTemplate(); err != nil {
+		return err
+	}
+
+	if _, err := c.Title(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GenerateUpdate fetches the issues of the configured sprint and renders the
+// sprint update using the configured template.
+func GenerateUpdate(ctx context.Context, config Config) (string, error) {
+	update, err := BuildUpdate(ctx, config)
+	if err != nil {
+		return "", err
+	}
+
+	logging.FromContext(ctx).Verbose("rendering update", "format", config.Format, "template", config.TemplateName())
+
+	config.RedactUpdate(update)
+
+	text, err := config.Render(update)
+	if err != nil {
+		return "", err
+	}
+
+	if text, err = config.RunPostRenderHooks(ctx, text); err != nil {
+		return "", err
+	}
+
+	if err = config.SaveState(update); err != nil {
+		return "", err
+	}
+
+	if err = config.SaveHistory(update, text); err != nil {
+		return "", err
+	}
+
+	return text, nil
+}
+
+// BuildUpdate fetches the issues of the configured sprint and assembles the
+// sprint update without rendering it, so it can be reviewed before
+// rendering.
+func BuildUpdate(ctx context.Context, config Config) (*report.Update, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if config.Tracker != nil {
+		return config.buildTrackerUpdate(ctx)
+	}
+
+	client, err := config.JiraClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = config.ResolveSprint(ctx, client); err != nil {
+		return nil, err
+	}
+
+	if err = config.resolveAssignee(ctx, client); err != nil {
+		return nil, config.jiraError(err)
+	}
+
+	sprintFieldID, err := config.sprintFieldID(ctx, client)
+	if err != nil {
+		return nil, config.jiraError(err)
+	}
+
+	config.sprintField = sprintFieldID
+
+	if err = config.lookupSprint(ctx, client, sprintFieldID); err != nil {
+		return nil, config.jiraError(err)
+	}
+
+	if config.Worklog || config.Calendar != nil || config.Tempo != nil {
+		if err = config.resolveSprintDates(); err != nil {
+			return nil, err
+		}
+	}
+
+	title, err := config.Title()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.hasCustomJQL() {
+		if err = jira.ValidateJQL(ctx, client, config.jql("")); err != nil {
+			return nil, config.jiraError(err)
+		}
+	}
+
+	customFields := report.CustomFields{
+		Sprint:      sprintFieldID,
+		StoryPoints: config.StoryPointsField,
+	}
+
+	if config.SpilloverReasons {
+		customFields.SpilloverReason = config.SpilloverReasonField
+	}
+
+	if config.needsEpics() {
+		if customFields.EpicLink, err = config.fieldID(ctx, client, "epic-link", jira.FindEpicLinkFieldID); err != nil {
+			return nil, config.jiraError(err)
+		}
+	}
+
+	if customFields.Flagged, err = config.fieldID(ctx, client, "flagged", jira.FindFlaggedFieldID); err != nil {
+		return nil, config.jiraError(err)
+	}
+
+	switch config.SortBy {
+	case "", report.SortByRank:
+		if customFields.Rank, err = config.fieldID(ctx, client, "rank", jira.FindRankFieldID); err != nil {
+			return nil, config.jiraError(err)
+		}
+	case report.SortByPriority:
+		if config.priorities, err = config.priorityNames(ctx, client); err != nil {
+			return nil, config.jiraError(err)
+		}
+	}
+
+	if err = config.runPreFetchHooks(ctx); err != nil {
+		return nil, err
+	}
+
+	var rawIssues []gojira.Issue
+	var members []report.Member
+
+	if len(config.Assignees) == 0 {
+		rawIssues, err = config.fetchIssues(ctx, client, "", customFields)
+	} else {
+		rawIssues, members, err = config.fetchTeamIssues(ctx, client, customFields)
+	}
+
+	if err != nil {
+		return nil, config.jiraError(err)
+	}
+
+	issues, members := config.filterIssues(report.NewIssues(config.ServerURL, rawIssues, customFields), members)
+
+	if config.needsEpics() {
+		if err = config.resolveEpics(ctx, client, issues, members); err != nil {
+			return nil, config.jiraError(err)
+		}
+	}
+
+	var approved bool
+	switch {
+	case config.Tempo != nil:
+		if approved, err = config.addTempoTime(ctx, client, rawIssues, issues, members); err != nil {
+			return nil, config.jiraError(err)
+		}
+	case config.Worklog:
+		if err = config.addTimeSpent(ctx, client, issues, members); err != nil {
+			return nil, config.jiraError(err)
+		}
+	}
+
+	if config.hasAnnotation(report.AnnotationTimeline) {
+		if err = config.addTimeline(ctx, client, issues, members); err != nil {
+			return nil, config.jiraError(err)
+		}
+	}
+
+	if issues, members, err = config.runPostFetchHooks(ctx, issues, members); err != nil {
+		return nil, err
+	}
+
+	update := report.NewUpdate(title, issues, members, config.updateOptions())
+	update.Sprint = config.Name()
+	update.Assignee = config.assigneeName
+
+	if config.Tempo != nil {
+		update.Timesheet = report.NewTimesheet(update.Issues, approved)
+	}
+
+	if report.HasSection(config.Sections, report.SectionThemes) {
+		if update.Themes, err = config.themes(ctx, client, update.Issues, customFields.EpicLink); err != nil {
+			return nil, config.jiraError(err)
+		}
+	}
+
+	titleData := config.TitleData()
+	update.StartDate = titleData.StartDate
+	update.EndDate = titleData.EndDate
+	update.DaysRemaining = titleData.DaysRemaining
+
+	if err = config.loadCarriedOver(update); err != nil {
+		return nil, err
+	}
+
+	// The velocity is optional, hence the update is rendered without it if
+	// the sprint report cannot be read.
+	if update.Stats, err = config.sprintStats(ctx, client); err != nil {
+		if ctx.Err() != nil {
+			return nil, config.jiraError(err)
+		}
+
+		err = config.jiraError(err)
+		logging.FromContext(ctx).Verbose("rendering the update without the velocity", "error", err)
+		update.Warnings = append(update.Warnings, fmt.Sprintf("the velocity is not rendered: %v", err))
+	}
+
+	if config.EndOfSprint && config.hasAnnotation(report.AnnotationTimeline) {
+		update.CycleTime = update.Issues.CycleTime()
+	}
+
+	if config.Diff {
+		if err = config.diffPrevious(update); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Calendar != nil {
+		if update.TimeOff, err = config.timeOff(ctx, client); err != nil {
+			return nil, err
+		}
+	}
+
+	var activities map[string]*gojira.Issue
+	if config.SuggestKudos || config.ProgressNotes {
+		if activities, err = fetchActivities(ctx, client, rawIssues); err != nil {
+			return nil, config.jiraError(err)
+		}
+
+		if config.SuggestKudos {
+			if update.SuggestedKudos, err = config.suggestKudos(ctx, client, rawIssues, activities); err != nil {
+				return nil, config.jiraError(err)
+			}
+		}
+
+		if config.ProgressNotes {
+			if err = config.addProgressNotes(ctx, client, update, activities); err != nil {
+				return nil, config.jiraError(err)
+			}
+		}
+	}
+
+	if err = addBlockedReasons(ctx, client, update, activities); err != nil {
+		return nil, config.jiraError(err)
+	}
+
+	if config.SpilloverReasons && config.SpilloverReasonField == "" {
+		if err = addSpilloverReasons(ctx, client, update, activities); err != nil {
+			return nil, config.jiraError(err)
+		}
+	}
+
+	if len(config.CodeHosts) > 0 {
+		if update.PullRequests, err = config.fetchPullRequests(ctx, issues); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(config.GitRepositories) > 0 && report.HasSection(config.Sections, report.SectionCommits) {
+		if err = config.addCommits(ctx, update, issues); err != nil {
+			return nil, err
+		}
+	}
+
+	if report.HasSection(config.Sections, report.SectionSummary) {
+		if update.Summary, err = config.summary(ctx, update); err != nil {
+			return nil, err
+		}
+	}
+
+	update.Provenance = config.provenance(config.issueQueries())
+	return update, nil
+}
+
+// updateOptions returns the options of assembling the sprint update.
+func (c *Config) updateOptions() report.Options {
+	return report.Options{
+		Sprint:           c.Sprint,
+		Sprints:          c.Sprints,
+		Period:           c.isPeriod(),
+		EndOfSprint:      c.EndOfSprint,
+		BlockedStatuses:  c.BlockedStatuses,
+		BlockedLabels:    c.BlockedLabels,
+		StatusGroups:     c.StatusGroups,
+		StatusOrder:      c.StatusOrder,
+		HiddenStatuses:   c.HiddenStatuses,
+		StoryPoints:      c.StoryPointsField != "",
+		SummaryLength:    c.SummaryLength,
+		Subtasks:         c.Subtasks,
+		GroupBy:          c.GroupBy,
+		SortBy:           c.SortBy,
+		Priorities:       c.priorities,
+		StatusEmojis:     c.StatusEmojis,
+		Annotations:      c.Annotations,
+		Sections:         c.Sections,
+		SectionTitles:    c.SectionTitles,
+		SplitByProject:   c.SplitByProject,
+		ExpandedGroups:   c.ExpandedGroups,
+		PlainFormats:     c.PlainFormats,
+		DetailsThreshold: c.DetailsThreshold,
+		MaxItems:         c.MaxItems,
+		FoldBy:           c.FoldBy,
+		Decorated:        strings.EqualFold(c.Format, render.DecoratedFormat),
+		Language:         c.Language,
+	}
+}
+
+// RedactUpdate redacts the update if redaction is enabled.
+func (c *Config) RedactUpdate(update *report.Update) {
+	if c.Redact {
+		update.Redact(c.RedactAliases)
+	}
+}
+
+// Render renders the sprint update using the configured template, or encodes
+// it when a structured format is configured.
+func (c *Config) Render(update *report.Update) (string, error) {
+	format, err := render.LookupFormat(c.Format)
+	if err != nil {
+		return "", err
+	}
+
+	if format.Document {
+		return render.Encode(format, update)
+	}
+
+	if format.IsStructured() {
+		return render.Encode(format, update.Export())
+	}
+
+	tmpl, err := c.parseTemplate()
+	if err != nil {
+		return "", err
+	}
+
+	return render.Render(tmpl, update)
+}
+
+// TemplateName describes the template used for rendering the sprint update,
+// like the path of the template file or the built-in template of the format.
+func (c *Config) TemplateName() string {
+	name := c.Format
+	if format, err := render.LookupFormat(c.Format); err == nil {
+		if format.IsStructured() {
+			return "none, encoded as " + format.Name
+		}
+
+		name = format.Name
+	}
+
+	switch {
+	case c.Template != "":
+		name = "inline template"
+	case c.templateFile() != "":
+		name = c.templateFile()
+	default:
+		name = "built-in " + name + " template"
+	}
+
+	if c.TemplatesDir != "" {
+		name += " with the partials of " + c.TemplatesDir
+	}
+
+	return name
+}
+
+// templateFile returns the path of the template file of the update type,
+// falling back to the template file of every update type.
+func (c *Config) templateFile() string {
+	if c.EndOfSprint && c.EndOfSprintTemplateFile != "" {
+		return c.EndOfSprintTemplateFile
+	}
+
+	if !c.EndOfSprint && c.MidSprintTemplateFile != "" {
+		return c.MidSprintTemplateFile
+	}
+
+	return c.TemplateFile
+}
+
+// parseTemplate parses the template used for rendering the sprint update.
+// The structured formats have no template, hence nil is returned for them.
+func (c *Config) parseTemplate() (*template.Template, error) {
+	format, err := render.LookupFormat(c.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if format.IsStructured() {
+		if c.Template != "" || c.templateFile() != "" {
+			return nil, fmt.Errorf("%w: %s", render.ErrNoTemplate, format.Name)
+		}
+
+		return nil, nil
+	}
+
+	var tmpl *template.Template
+	switch templateFile := c.templateFile(); {
+	case c.Template != "":
+		tmpl, err = render.ParseCustomTemplate("description", c.Template, format)
+	case templateFile != "":
+		tmpl, err = render.ParseCustomTemplateFile(templateFile, format)
+	default:
+		tmpl, err = render.ParseTemplate(format.Name, format.Template, format)
+	}
+
+	if err == nil && c.TemplatesDir != "" {
+		err = render.ParsePartials(tmpl, c.TemplatesDir)
+	}
+
+	if err != nil || c.StylesheetFile == "" {
+		return tmpl, err
+	}
+
+	css, err := os.ReadFile(filepath.Clean(c.StylesheetFile))
+	if err != nil {
+		return nil, err
+	}
+
+	render.SetStylesheet(tmpl, string(css))
+	return tmpl, nil
+}
+
+// Title renders the title of the sprint update using the configured title
+// template.
+func (c *Config) Title() (string, error) {
+	tmpl, err := render.ParseTitleTemplate(c.TitleTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	return render.NewTitle(tmpl, c.TitleData())
+}
+
+// TitleData returns the input of the title template. The sprint dates are
+// set once the sprint is resolved by BuildUpdate or ResolveSprint.
+func (c *Config) TitleData() render.TitleData {
+	data := render.NewTitleData(c.Name(), c.EndOfSprint)
+	data.Type = i18n.Translate(c.Language, data.Type)
+	data.Assignee = c.assigneeName
+
+	if c.sprint != nil {
+		data.SetDates(c.sprint.StartDate, c.sprint.EndDate, time.Now())
+	}
+
+	return data
+}
+
+// lookupSprint looks up the details of the sprint, unless they are already
+// known from resolving the active sprint of the board. If the sprint field
+// lacks the dates of the sprint, they are fetched from the Jira Agile API. If
+// no issue of the sprint is found, the details of the sprint remain unknown.
+func (c *Config) lookupSprint(ctx context.Context, client *gojira.Client, sprintFieldID string) error {
+	if c.isConsolidated() {
+		return c.lookupPeriod(ctx, client, sprintFieldID)
+	}
+
+	if c.sprint != nil || c.Sprint == "" || sprintFieldID == "" {
+		return nil
+	}
+
+	s, err := c.findSprint(ctx, client, c.Sprint, sprintFieldID)
+	if errors.Is(err, jira.ErrSprintNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.sprint = s
+	return nil
+}
@@ -0,0 +1,44 @@
+package sprint
+
+import (
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/state"
+)
+
+// loadCarriedOver lists the issues left unresolved at the end of the previous
+// sprint in the mid-sprint update, based on the configured state file. The
+// issues spilling over to the sprint are listed as spillovers only.
+// Consolidated updates cover more than one sprint, hence they are skipped.
+func (c *Config) loadCarriedOver(update *report.Update) error {
+	if c.StateFile == "" || c.EndOfSprint || c.isConsolidated() {
+		return nil
+	}
+
+	s, err := state.Load(c.StateFile)
+	if err != nil {
+		return err
+	}
+
+	if s.Sprint != "" && s.Sprint != c.Sprint && len(s.Unresolved) > 0 {
+		update.SetCarriedOver(s.Sprint, s.Unresolved)
+	}
+
+	return nil
+}
+
+// SaveState saves the issues left unresolved by the end of sprint update to
+// the configured state file, so the next sprint's mid-sprint update can list
+// them. It does nothing for mid-sprint, consolidated, and redacted updates, or
+// if no state file is set.
+func (c *Config) SaveState(update *report.Update) error {
+	if c.StateFile == "" || !c.EndOfSprint || c.isConsolidated() || c.Redact {
+		return nil
+	}
+
+	return state.Save(c.StateFile, &state.State{
+		Sprint: c.Sprint,
+		Unresolved: update.Issues.Filter(func(issue *report.Issue) bool {
+			return !issue.Done
+		}),
+	})
+}
@@ -0,0 +1,41 @@
+package sprint
+
+import (
+	"context"
+	"fmt"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// hasStats reports whether the velocity of the sprint is read from the sprint
+// report of the board, which is done for the end of sprint updates of a
+// single sprint of a known board.
+func (c *Config) hasStats() bool {
+	return c.EndOfSprint && c.Board != 0 && !c.isConsolidated() && c.sprint != nil && c.sprint.ID != 0
+}
+
+// sprintStats returns the velocity of the sprint read from the sprint report
+// of the board, or nil if it is not read. The sprint report is read from an
+// internal endpoint, which is not available on every Jira instance.
+func (c *Config) sprintStats(ctx context.Context, client *gojira.Client) (*report.Stats, error) {
+	if !c.hasStats() {
+		return nil, nil
+	}
+
+	sprintReport, err := jira.FetchSprintReport(ctx, client, c.Board, c.sprint.ID)
+	if err != nil {
+		return nil, fmt.Errorf("reading the sprint report of board %d: %w", c.Board, err)
+	}
+
+	return &report.Stats{
+		CommittedPoints:    sprintReport.CommittedPoints,
+		CompletedPoints:    sprintReport.CompletedPoints,
+		CompletedIssues:    sprintReport.CompletedIssues,
+		NotCompletedIssues: sprintReport.NotCompletedIssues,
+		RemovedIssues:      sprintReport.RemovedIssues,
+		AddedIssues:        sprintReport.AddedIssues,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gabor-boros/sprint-update/pkg/i18n"
+	"gabor-boros/sprint-update/pkg/llm"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// ErrMissingLanguageModel is returned when the summary section is rendered,
+// but no language model is configured to write it.
+var ErrMissingLanguageModel = errors.New("the summary section requires a language model")
+
+// summaryInstructions is the system prompt of the narrative summary.
+const summaryInstructions = `You write the summary at the top of a sprint update of a software engineer, read by their team and stakeholders.
+Summarize the work in 3 to 5 sentences of plain prose, in the first person plural for teams and in the first person otherwise.
+Mention the progress, the blockers, and the risks, like the spillovers, without listing every issue.
+Only use the facts of the update. Do not use headings, lists, Markdown, issue keys, or links.`
+
+// summary asks the language model to write the narrative summary of the
+// update. Only the summaries, types, statuses, and story points of the issues
+// are sent, along with the names of the team members in team mode, the
+// blocked and spillover reasons, and the planned time off.
+func (c *Config) summary(ctx context.Context, update *report.Update) (string, error) {
+	instructions := summaryInstructions
+	if c.Language != "" && c.Language != i18n.DefaultLanguage {
+		instructions += fmt.Sprintf("\nWrite the summary in the language of the ISO 639-1 code %q.", c.Language)
+	}
+
+	return c.LanguageModel.Complete(ctx, []llm.Message{
+		{Role: "system", Content: instructions},
+		{Role: "user", Content: summaryPrompt(update)},
+	})
+}
+
+// summaryPrompt describes the update to the language model as plain text.
+func summaryPrompt(update *report.Update) string {
+	var b strings.Builder
+
+	kind := "mid-sprint update"
+	if update.EndOfSprint {
+		kind = "end of sprint update"
+	}
+
+	fmt.Fprintf(&b, "%s (%s)\n", update.Title, kind)
+
+	if update.DaysRemaining > 0 && !update.EndOfSprint {
+		fmt.Fprintf(&b, "Days remaining in the sprint: %d\n", update.DaysRemaining)
+	}
+
+	if update.StoryPoints {
+		fmt.Fprintf(&b, "Story points done: %s of %s committed\n", report.FormatPoints(update.DonePoints()), report.FormatPoints(update.CommittedPoints()))
+	}
+
+	b.WriteString("\nWorked on:\n")
+
+	if len(update.Members) > 0 {
+		for _, member := range update.Members {
+			writeSummaryIssues(&b, update, member.Issues, member.Name)
+		}
+	} else {
+		writeSummaryIssues(&b, update, update.Issues, "")
+	}
+
+	if len(update.Blocked) > 0 {
+		b.WriteString("\nBlocked:\n")
+		writeSummaryIssues(&b, update, update.Blocked, "")
+	}
+
+	if len(update.Spillovers) > 0 {
+		b.WriteString("\nSpillovers:\n")
+		writeSummaryIssues(&b, update, update.Spillovers, "")
+	}
+
+	if update.TimeOff != "" {
+		fmt.Fprintf(&b, "\nTime off: %s\n", update.TimeOff)
+	}
+
+	return b.String()
+}
+
+// writeSummaryIssues writes a line per issue in the order they are rendered,
+// attributed to the team member if the name is set.
+func writeSummaryIssues(b *strings.Builder, update *report.Update, issues report.Issues, member string) {
+	for _, group := range update.Groups(issues) {
+		for i := range group.Issues {
+			writeSummaryIssue(b, &group.Issues[i], member)
+		}
+	}
+}
+
+// writeSummaryIssue writes the line of the issue.
+func writeSummaryIssue(b *strings.Builder, issue *report.Issue, member string) {
+	details := []string{issue.Status}
+	if issue.Type != "" {
+		details = append(details, issue.Type)
+	}
+
+	if issue.StoryPoints > 0 {
+		details = append(details, report.FormatPoints(issue.StoryPoints)+" pts")
+	}
+
+	if member != "" {
+		details = append(details, member)
+	}
+
+	fmt.Fprintf(b, "- %s (%s)", issue.Summary, strings.Join(details, ", "))
+
+	switch {
+	case issue.BlockedReason != "":
+		fmt.Fprintf(b, ": %s", issue.BlockedReason)
+	case issue.SpilloverReason != "":
+		fmt.Fprintf(b, ": %s", issue.SpilloverReason)
+	}
+
+	b.WriteString("\n")
+}
@@ -0,0 +1,72 @@
+package sprint
+
+import (
+	"context"
+	"sync"
+
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// fetchTeamIssues concurrently fetches the issues of every assignee, and
+// returns all issues together with the members of the team in the order of
+// the assignees. The first error stops fetching the issues of the other
+// assignees.
+func (c *Config) fetchTeamIssues(ctx context.Context, client *gojira.Client, customFields report.CustomFields) ([]gojira.Issue, []report.Member, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	memberIssues := make([][]gojira.Issue, len(c.Assignees))
+
+	for i, assignee := range c.Assignees {
+		wg.Add(1)
+
+		go func(i int, assignee string) {
+			defer wg.Done()
+
+			issues, err := c.fetchIssues(ctx, client, assignee, customFields)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+
+				return
+			}
+
+			memberIssues[i] = issues
+		}(i, assignee)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+
+	var allIssues []gojira.Issue
+	members := make([]report.Member, 0, len(c.Assignees))
+	seen := make(map[string]bool)
+
+	for i, issues := range memberIssues {
+		// In worklog mode, multiple members may have logged time on the same
+		// issue.
+		for _, issue := range issues {
+			if !seen[issue.Key] {
+				seen[issue.Key] = true
+				allIssues = append(allIssues, issue)
+			}
+		}
+
+		members = append(members, report.NewMember(c.Assignees[i], report.NewIssues(c.ServerURL, issues, customFields)))
+	}
+
+	return allIssues, members, nil
+}
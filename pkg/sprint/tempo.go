@@ -0,0 +1,66 @@
+package sprint
+
+import (
+	"context"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// addTempoTime sets the time logged in Tempo within the sprint on every issue,
+// like addTimeSpent does for the Jira worklogs, and reports whether the
+// timesheets of every user are approved for the sprint. Tempo identifies the
+// users by their Jira account IDs.
+func (c *Config) addTempoTime(ctx context.Context, client *gojira.Client, rawIssues []gojira.Issue, issues report.Issues, members []report.Member) (bool, error) {
+	userIDs := c.Assignees
+	if len(userIDs) == 0 {
+		currentUserID, err := c.userID(ctx, client)
+		if err != nil {
+			return false, err
+		}
+
+		userIDs = []string{currentUserID}
+	}
+
+	// The Tempo worklogs refer to the issues by their IDs.
+	keys := make(map[string]string, len(rawIssues))
+	for _, issue := range rawIssues {
+		keys[issue.ID] = issue.Key
+	}
+
+	since, until := c.window()
+	spent := make(map[string]map[string]time.Duration)
+	approved := true
+
+	for _, userID := range userIDs {
+		worklogs, err := c.Tempo.Worklogs(ctx, userID, since, until)
+		if err != nil {
+			return false, err
+		}
+
+		for _, worklog := range worklogs {
+			key, ok := keys[worklog.IssueID]
+			if !ok {
+				continue
+			}
+
+			if spent[key] == nil {
+				spent[key] = make(map[string]time.Duration, len(userIDs))
+			}
+
+			spent[key][userID] += worklog.TimeSpent
+		}
+
+		userApproved, err := c.Tempo.IsApproved(ctx, userID, since, until)
+		if err != nil {
+			return false, err
+		}
+
+		approved = approved && userApproved
+	}
+
+	setTimeSpent(spent, userIDs, issues, members)
+	return approved, nil
+}
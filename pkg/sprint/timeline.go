@@ -0,0 +1,60 @@
+package sprint
+
+import (
+	"context"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// addTimeline sets the times the issues were started and done at, read from
+// their changelog until the end of the sprint, on the issues and the issues
+// of the team members.
+func (c *Config) addTimeline(ctx context.Context, client *gojira.Client, issues report.Issues, members []report.Member) error {
+	categories, err := c.statusCategories(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	_, until := c.window()
+	fetched := make(map[string]bool)
+
+	for _, statusIssues := range issues {
+		for _, issue := range statusIssues {
+			if fetched[issue.Key] {
+				continue
+			}
+
+			changes, err := jira.FetchStatusChanges(ctx, client, issue.Key)
+			if err != nil {
+				return err
+			}
+
+			fetched[issue.Key] = true
+
+			started, finished := jira.Timeline(changes, categories, until)
+			setTimeline(issue.Key, started, finished, issues, members)
+		}
+	}
+
+	return nil
+}
+
+// setTimeline sets the times the issue having the given key was started and
+// done at, on the issues and the issues of the team members.
+func setTimeline(key string, started time.Time, finished time.Time, issues report.Issues, members []report.Member) {
+	set := func(issue *report.Issue) {
+		issue.Started = started
+		if issue.Done {
+			issue.Finished = finished
+		}
+	}
+
+	issues.Update(key, set)
+	for i := range members {
+		members[i].Issues.Update(key, set)
+	}
+}
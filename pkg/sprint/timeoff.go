@@ -0,0 +1,54 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+
+	"gabor-boros/sprint-update/pkg/calendar"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// ErrUnknownSprintEnd is returned when the end date of the sprint is not
+// known, hence the time off within the sprint cannot be looked up.
+var ErrUnknownSprintEnd = errors.New("cannot determine the end date of the sprint")
+
+// timeOff describes the time off found in the calendar within the sprint. The
+// sprint dates must be resolved already. The calendars of Jira users, like
+// the leave planned in Tempo, are read for the user the update is generated
+// for, which needs the Jira client.
+func (c *Config) timeOff(ctx context.Context, client *gojira.Client) (string, error) {
+	if c.sprint == nil || c.sprint.StartDate == nil {
+		return "", ErrUnknownSprintWindow
+	}
+
+	if c.sprint.EndDate == nil {
+		return "", ErrUnknownSprintEnd
+	}
+
+	since, until := *c.sprint.StartDate, *c.sprint.EndDate
+
+	source := c.Calendar
+	if accountSource, ok := source.(calendar.AccountSource); ok && client != nil {
+		accountID, err := c.userID(ctx, client)
+		if err != nil {
+			return "", err
+		}
+
+		source = accountSource.ForAccount(accountID)
+	}
+
+	events, err := source.Events(ctx, since, until)
+	if err != nil {
+		return "", err
+	}
+
+	return calendar.TimeOff(events, since, until, c.TimeOffKeywords), nil
+}
+
+// isAccountSource reports whether the calendar lists the events of a Jira
+// user, hence it cannot be read without Jira.
+func isAccountSource(source calendar.Source) bool {
+	_, ok := source.(calendar.AccountSource)
+	return ok
+}
@@ -0,0 +1,145 @@
+package sprint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// ErrUnsupportedByTracker is returned when a feature relying on Jira is
+// enabled while the issues are fetched from another tracker.
+var ErrUnsupportedByTracker = errors.New("not supported by the issue tracker")
+
+// validateTracker checks that only the features supported by every tracker
+// are enabled.
+func (c *Config) validateTracker() error {
+	features := []struct {
+		name    string
+		enabled bool
+	}{
+		{"consolidated updates", c.isConsolidated()},
+		{"sprint IDs", c.SprintID != 0},
+		{"team updates", len(c.Assignees) > 0},
+		{"custom queries", c.JQL != "" || len(c.JQLExtra) > 0},
+		{"worklog mode", c.Worklog},
+		{"tempo timesheets", c.Tempo != nil},
+		{"kudos suggestions", c.SuggestKudos},
+		{"progress notes", c.ProgressNotes},
+		{"spillover reasons", c.SpilloverReasons},
+		{"grouping by epic", c.GroupBy == report.GroupByEpic},
+		{"themes", report.HasSection(c.Sections, report.SectionThemes)},
+		{"project restrictions", len(c.Projects) > 0},
+		{"tempo leave", isAccountSource(c.Calendar)},
+	}
+
+	for _, feature := range features {
+		if feature.enabled {
+			return fmt.Errorf("%w: %s (%s)", ErrUnsupportedByTracker, feature.name, c.Tracker.Name())
+		}
+	}
+
+	return nil
+}
+
+// buildTrackerUpdate fetches the issues of the sprint from the configured
+// tracker and assembles the sprint update. The features relying on Jira are
+// rejected by Validate.
+func (c *Config) buildTrackerUpdate(ctx context.Context) (*report.Update, error) {
+	s, err := c.Tracker.Sprint(ctx, c.Sprint)
+	if err != nil {
+		return nil, err
+	}
+
+	state := "active"
+	if s.Closed {
+		state = "closed"
+	}
+
+	c.Sprint = s.Name
+	c.sprint = &jira.Sprint{Name: s.Name, State: state, StartDate: s.StartDate, EndDate: s.EndDate}
+	c.assigneeName = c.Assignee
+
+	if c.Calendar != nil {
+		if err = c.resolveSprintDates(); err != nil {
+			return nil, err
+		}
+	}
+
+	title, err := c.Title()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.runPreFetchHooks(ctx); err != nil {
+		return nil, err
+	}
+
+	fetched, err := c.Tracker.Issues(ctx, s, c.Assignee)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make(report.Issues)
+	for _, issue := range fetched {
+		issues[issue.Status] = append(issues[issue.Status], issue)
+	}
+
+	issues, members := c.filterIssues(issues, nil)
+	if issues, members, err = c.runPostFetchHooks(ctx, issues, members); err != nil {
+		return nil, err
+	}
+
+	update := report.NewUpdate(title, issues, members, c.updateOptions())
+	update.Sprint = c.Name()
+	update.Assignee = c.assigneeName
+
+	titleData := c.TitleData()
+	update.StartDate = titleData.StartDate
+	update.EndDate = titleData.EndDate
+	update.DaysRemaining = titleData.DaysRemaining
+
+	if err = c.loadCarriedOver(update); err != nil {
+		return nil, err
+	}
+
+	if c.Diff {
+		if err = c.diffPrevious(update); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.Calendar != nil {
+		if update.TimeOff, err = c.timeOff(ctx, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.CodeHosts) > 0 {
+		if update.PullRequests, err = c.fetchPullRequests(ctx, issues); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.GitRepositories) > 0 && report.HasSection(c.Sections, report.SectionCommits) {
+		if err = c.addCommits(ctx, update, issues); err != nil {
+			return nil, err
+		}
+	}
+
+	if report.HasSection(c.Sections, report.SectionSummary) {
+		if update.Summary, err = c.summary(ctx, update); err != nil {
+			return nil, err
+		}
+	}
+
+	// The trackers are not queried by JQL, so the hash covers the sprint and
+	// the assignee the issues are fetched for.
+	if c.Provenance != "" {
+		update.Provenance = report.NewProvenance(c.Provenance, s.ID, []string{s.ID, c.Assignee})
+	}
+
+	return update, nil
+}
@@ -0,0 +1,118 @@
+package sprint
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+// WorklogJQL represents the JQL query used to search the tickets the user
+// logged time on within the date range of the sprint.
+const WorklogJQL string = `worklogAuthor = %s AND worklogDate >= "%s" AND worklogDate <= "%s"`
+
+// jqlDateLayout is the layout of the dates in JQL queries.
+const jqlDateLayout = "2006-01-02"
+
+// window returns the start and end of the resolved sprint. If the sprint has
+// not ended yet, the current time is used as its end.
+func (c *Config) window() (time.Time, time.Time) {
+	var since time.Time
+	until := time.Now()
+
+	if c.sprint != nil && c.sprint.StartDate != nil {
+		since = *c.sprint.StartDate
+	}
+
+	if c.sprint != nil && c.sprint.EndDate != nil && c.sprint.EndDate.Before(until) {
+		until = *c.sprint.EndDate
+	}
+
+	return since, until
+}
+
+// worklogJQL returns the JQL query used for searching the issues the given
+// user logged time on within the sprint.
+func (c *Config) worklogJQL(user string) string {
+	since, until := c.window()
+	return fmt.Sprintf(WorklogJQL, user, since.Format(jqlDateLayout), until.Format(jqlDateLayout))
+}
+
+// resolveSprintDates ensures the start date of the sprint is known, which is
+// required for the date range of the worklogs and the time off.
+func (c *Config) resolveSprintDates() error {
+	if c.sprint == nil || c.sprint.StartDate == nil {
+		return ErrUnknownSprintWindow
+	}
+
+	return nil
+}
+
+// addTimeSpent sets the time logged within the sprint on every issue. In team
+// mode, the time of the members is set on their own issues, and the total time
+// of the team is set on the rest of the issues.
+func (c *Config) addTimeSpent(ctx context.Context, client *gojira.Client, issues report.Issues, members []report.Member) error {
+	userIDs := c.Assignees
+	if len(userIDs) == 0 {
+		currentUserID, err := c.userID(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		userIDs = []string{currentUserID}
+	}
+
+	since, until := c.window()
+	spent := make(map[string]map[string]time.Duration)
+
+	for _, statusIssues := range issues {
+		for _, issue := range statusIssues {
+			if _, ok := spent[issue.Key]; ok {
+				continue
+			}
+
+			worklogs, err := jira.FetchWorklogs(ctx, client, issue.Key)
+			if err != nil {
+				return err
+			}
+
+			spent[issue.Key] = make(map[string]time.Duration, len(userIDs))
+			for _, userID := range userIDs {
+				spent[issue.Key][userID] = jira.TimeSpent(worklogs, userID, since, until)
+			}
+		}
+	}
+
+	setTimeSpent(spent, userIDs, issues, members)
+	return nil
+}
+
+// setTimeSpent sets the time spent by the users on every issue, keyed by the
+// issue keys and the user IDs. In team mode, the time of the members is set
+// on their own issues, and the total time of the team is set on the rest of
+// the issues.
+func setTimeSpent(spent map[string]map[string]time.Duration, userIDs []string, issues report.Issues, members []report.Member) {
+	for key, userSpent := range spent {
+		var total time.Duration
+		for _, d := range userSpent {
+			total += d
+		}
+
+		issues.Update(key, func(issue *report.Issue) {
+			issue.TimeSpent = total
+		})
+	}
+
+	for i := range members {
+		for key, userSpent := range spent {
+			d := userSpent[userIDs[i]]
+			members[i].Issues.Update(key, func(issue *report.Issue) {
+				issue.TimeSpent = d
+			})
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package state persists the issues left unresolved at the end of a sprint,
+// so they can be listed in the updates of the next sprint.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// State is the state saved by the end of sprint update.
+type State struct {
+	// Sprint is the name of the sprint the state was saved for.
+	Sprint string `json:"sprint"`
+	// Unresolved lists the issues left unresolved at the end of the sprint,
+	// grouped by status.
+	Unresolved report.Issues `json:"unresolved"`
+}
+
+// Load reads the state from the file at the given path. If the file does not
+// exist, an empty state is returned.
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &State{}, nil
+		}
+
+		return nil, err
+	}
+
+	var s State
+	if err = json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+
+	return &s, nil
+}
+
+// Save writes the state to the file at the given path, creating its directory
+// if necessary.
+func Save(path string, s *State) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,108 @@
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/calendar"
+)
+
+// Leave lists the leave of a user planned in Tempo Planner. The plans on the
+// leave issues are out of office events, and the other plans are time off
+// only if their description says so, like "Vacation".
+type Leave struct {
+	Client *Client
+	// AccountID is the Jira account ID of the user.
+	AccountID string
+	// LeaveIssueIDs lists the IDs of the Jira issues the leave is planned on,
+	// which the Tempo API returns instead of their keys.
+	LeaveIssueIDs []string
+}
+
+// plansResponse is a page of plans returned by the API.
+type plansResponse struct {
+	Metadata struct {
+		Next string `json:"next"`
+	} `json:"metadata"`
+	Results []struct {
+		StartDate   string `json:"startDate"`
+		EndDate     string `json:"endDate"`
+		Description string `json:"description"`
+		PlanItem    struct {
+			ID   json.Number `json:"id"`
+			Type string      `json:"type"`
+		} `json:"planItem"`
+		PlanApproval *struct {
+			Status string `json:"status"`
+		} `json:"planApproval"`
+	} `json:"results"`
+}
+
+// ForAccount returns the leave of the user having the Jira account ID.
+func (l *Leave) ForAccount(accountID string) calendar.Source {
+	return &Leave{Client: l.Client, AccountID: accountID, LeaveIssueIDs: l.LeaveIssueIDs}
+}
+
+// Events returns the plans of the user overlapping the date range as all
+// day events. The plans awaiting approval or rejected are left out.
+func (l *Leave) Events(ctx context.Context, since time.Time, until time.Time) ([]calendar.Event, error) {
+	query := url.Values{}
+	query.Set("from", since.Format(dateLayout))
+	query.Set("to", until.Format(dateLayout))
+	query.Set("limit", fmt.Sprint(pageSize))
+
+	requestURL := l.Client.baseURL() + "/plans/user/" + url.PathEscape(l.AccountID) + "?" + query.Encode()
+
+	var events []calendar.Event
+	for requestURL != "" {
+		var page plansResponse
+		if err := l.Client.get(ctx, requestURL, &page); err != nil {
+			return nil, err
+		}
+
+		for _, plan := range page.Results {
+			if plan.PlanApproval != nil && plan.PlanApproval.Status != approvedStatus {
+				continue
+			}
+
+			start, err := time.ParseInLocation(dateLayout, plan.StartDate, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("tempo plan has an invalid start date: %w", err)
+			}
+
+			end, err := time.ParseInLocation(dateLayout, plan.EndDate, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("tempo plan has an invalid end date: %w", err)
+			}
+
+			// The end date of the plans is inclusive, unlike the end of the
+			// all day events.
+			events = append(events, calendar.Event{
+				Summary:     plan.Description,
+				Start:       start,
+				End:         end.AddDate(0, 0, 1),
+				AllDay:      true,
+				OutOfOffice: strings.EqualFold(plan.PlanItem.Type, "ISSUE") && l.isLeaveIssue(plan.PlanItem.ID.String()),
+			})
+		}
+
+		requestURL = page.Metadata.Next
+	}
+
+	return events, nil
+}
+
+// isLeaveIssue reports whether the issue having the ID is a leave issue.
+func (l *Leave) isLeaveIssue(issueID string) bool {
+	for _, id := range l.LeaveIssueIDs {
+		if id == issueID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,171 @@
+// Package tempo reads the worklogs and the timesheet approvals of Tempo
+// Timesheets, for the organizations logging their time in Tempo instead of
+// Jira, and the leave planned in Tempo Planner.
+package tempo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultURL is the base URL of the Tempo Cloud REST API.
+const DefaultURL = "https://api.tempo.io/4"
+
+// dateLayout is the layout of the dates of the Tempo API.
+const dateLayout = "2006-01-02"
+
+// approvedStatus is the status of the approved timesheets.
+const approvedStatus = "APPROVED"
+
+// pageSize is the number of worklogs fetched by a single request.
+const pageSize = 1000
+
+// ErrMissingToken is returned when no Tempo API token is set.
+var ErrMissingToken = errors.New("tempo API token is required")
+
+// Worklog is the time logged by a user on an issue.
+type Worklog struct {
+	// IssueID is the ID of the issue, which the Tempo API returns instead of
+	// its key.
+	IssueID string
+	// TimeSpent is the logged time.
+	TimeSpent time.Duration
+	// StartDate is the day the work was done on.
+	StartDate time.Time
+}
+
+// Client is a client of the Tempo REST API.
+type Client struct {
+	// URL is the base URL of the API. When empty, DefaultURL is used.
+	URL string
+	// Token is the API token of the user.
+	Token string
+	// HTTPClient is the HTTP client used for the requests. When nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// worklogsResponse is a page of worklogs returned by the API.
+type worklogsResponse struct {
+	Metadata struct {
+		Next string `json:"next"`
+	} `json:"metadata"`
+	Results []struct {
+		Issue struct {
+			ID json.Number `json:"id"`
+		} `json:"issue"`
+		TimeSpentSeconds int    `json:"timeSpentSeconds"`
+		StartDate        string `json:"startDate"`
+	} `json:"results"`
+}
+
+// approvalResponse is the approval of a timesheet returned by the API.
+type approvalResponse struct {
+	Status struct {
+		Key string `json:"key"`
+	} `json:"status"`
+}
+
+// Worklogs returns the worklogs of the user having the given Jira account ID
+// within the date range, including both days.
+func (c *Client) Worklogs(ctx context.Context, accountID string, since time.Time, until time.Time) ([]Worklog, error) {
+	query := url.Values{}
+	query.Set("from", since.Format(dateLayout))
+	query.Set("to", until.Format(dateLayout))
+	query.Set("limit", fmt.Sprint(pageSize))
+
+	requestURL := c.baseURL() + "/worklogs/user/" + url.PathEscape(accountID) + "?" + query.Encode()
+
+	var worklogs []Worklog
+	for requestURL != "" {
+		var page worklogsResponse
+		if err := c.get(ctx, requestURL, &page); err != nil {
+			return nil, err
+		}
+
+		for _, result := range page.Results {
+			startDate, err := time.ParseInLocation(dateLayout, result.StartDate, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("tempo worklog has an invalid start date: %w", err)
+			}
+
+			worklogs = append(worklogs, Worklog{
+				IssueID:   result.Issue.ID.String(),
+				TimeSpent: time.Duration(result.TimeSpentSeconds) * time.Second,
+				StartDate: startDate,
+			})
+		}
+
+		requestURL = page.Metadata.Next
+	}
+
+	return worklogs, nil
+}
+
+// IsApproved reports whether the timesheet of the user having the given Jira
+// account ID is approved for the date range.
+func (c *Client) IsApproved(ctx context.Context, accountID string, since time.Time, until time.Time) (bool, error) {
+	query := url.Values{}
+	query.Set("from", since.Format(dateLayout))
+	query.Set("to", until.Format(dateLayout))
+
+	var approval approvalResponse
+	if err := c.get(ctx, c.baseURL()+"/timesheet-approvals/user/"+url.PathEscape(accountID)+"?"+query.Encode(), &approval); err != nil {
+		return false, err
+	}
+
+	return approval.Status.Key == approvedStatus, nil
+}
+
+// baseURL returns the base URL of the API without a trailing slash.
+func (c *Client) baseURL() string {
+	if c.URL == "" {
+		return DefaultURL
+	}
+
+	return strings.TrimSuffix(c.URL, "/")
+}
+
+// get sends the GET request and decodes the response into v.
+func (c *Client) get(ctx context.Context, requestURL string, v interface{}) error {
+	if c.Token == "" {
+		return ErrMissingToken
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("tempo request failed with status %d", resp.StatusCode)
+	}
+
+	return json.Unmarshal(body, v)
+}
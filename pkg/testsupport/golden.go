@@ -0,0 +1,64 @@
+package testsupport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gabor-boros/sprint-update/pkg/sprint"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a non-empty
+// value, makes AssertGolden write the rendered updates to the golden files
+// instead of comparing them.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// Render generates the sprint update of the configuration, like the one
+// returned by Config, without saving the state or the history of the
+// update.
+func Render(ctx context.Context, config sprint.Config) (string, error) {
+	config.StateFile = ""
+	config.HistoryDir = ""
+
+	return sprint.GenerateUpdate(ctx, config)
+}
+
+// RenderSample renders the sample update of the configuration, so templates
+// can be checked without starting the fake Jira server.
+func RenderSample(config sprint.Config) (string, error) {
+	update, err := sprint.SampleUpdate(config)
+	if err != nil {
+		return "", err
+	}
+
+	return config.Render(update)
+}
+
+// AssertGolden compares the rendered update with the content of the golden
+// file, failing the test if they differ. When UpdateGoldenEnv is set, the
+// golden file is written instead.
+func AssertGolden(t testing.TB, path string, got string) {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating the directory of the golden file: %v", err)
+		}
+
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("writing the golden file: %v", err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading the golden file (set %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+
+	if string(want) != got {
+		t.Errorf("the update differs from %s (set %s=1 to update it)\n--- want\n%s\n--- got\n%s", path, UpdateGoldenEnv, want, got)
+	}
+}
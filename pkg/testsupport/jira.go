@@ -0,0 +1,526 @@
+// Package testsupport provides a fake Jira server serving fixture issues and
+// helpers comparing the rendered updates with golden files, so custom
+// templates and the tools embedding the sprint package can be tested without
+// a Jira server.
+package testsupport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/jira"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/sprint"
+
+	gojira "github.com/andygrunwald/go-jira"
+)
+
+const (
+	// ServerURL is the URL of the Jira server the fixture issues link to.
+	// The requests to it are sent to the fake server, so the rendered updates
+	// don't depend on the address of the fake server.
+	ServerURL = "https://jira.example.com"
+	// SprintFieldID is the ID of the sprint custom field of the fake server.
+	SprintFieldID = "customfield_10020"
+	// StoryPointsFieldID is the ID of the story points custom field of the
+	// fake server.
+	StoryPointsFieldID = "customfield_10016"
+	// FlaggedFieldID is the ID of the flagged custom field of the fake
+	// server.
+	FlaggedFieldID = "customfield_10021"
+	// RankFieldID is the ID of the rank custom field of the fake server.
+	RankFieldID = "customfield_10019"
+)
+
+// Issue is an issue served by the fake Jira server.
+type Issue struct {
+	Key         string
+	Summary     string
+	Status      string
+	Type        string
+	Done        bool
+	Assignee    string
+	Labels      []string
+	Components  []string
+	Priority    string
+	StoryPoints float64
+	Parent      string
+	// BlockedBy lists the keys of the issues blocking the issue, unless they
+	// are linked by Links already.
+	BlockedBy []string
+	// Links are the issues linked to the issue. The relations starting with
+	// "is", like "is blocked by", are the inward links.
+	Links   []report.LinkedIssue
+	Flagged bool
+	// Comments are the bodies of the comments of the issue, from the oldest
+	// to the newest.
+	Comments []string
+	Resolved time.Time
+	Due      time.Time
+	Updated  time.Time
+	// Sprints are the names of the previous sprints of the issue. Every issue
+	// is part of the sprint of the fixture too.
+	Sprints []string
+	// Added indicates that the issue was added to the sprint after it
+	// started, hence it is not counted as committed by the sprint report.
+	Added bool
+}
+
+// Fixture describes the sprint and the issues served by the fake Jira
+// server.
+type Fixture struct {
+	// Sprint is the sprint of the issues. Its dates are fixed, so the
+	// rendered updates are the same from run to run.
+	Sprint jira.Sprint
+	// User is the display name of the authenticated user.
+	User string
+	// Project is the key of the project of the issues.
+	Project    string
+	Issues     []Issue
+	Priorities []string
+}
+
+// DefaultFixture returns the fixture of a completed two-week sprint with the
+// issues of the sample update: a done issue and its subtask, an issue
+// spilling over, an issue in review, and a blocked and flagged issue.
+func DefaultFixture() *Fixture {
+	start := time.Date(2021, time.October, 4, 9, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 11)
+
+	samples := sprint.SampleIssues(start)
+	issues := make([]Issue, 0, len(samples))
+
+	for i := range samples {
+		issues = append(issues, fixtureIssue(&samples[i]))
+	}
+
+	return &Fixture{
+		Sprint: jira.Sprint{
+			ID:        253,
+			Name:      "SE.253",
+			State:     "closed",
+			StartDate: &start,
+			EndDate:   &end,
+		},
+		User:       "Jane Doe",
+		Project:    "SE",
+		Priorities: []string{"Highest", "High", "Medium", "Low", "Lowest"},
+		Issues:     issues,
+	}
+}
+
+// fixtureIssue returns the fixture issue of the sample issue. The reasons of
+// the flags and the spillovers become the comments they are read from, and
+// the issues are last updated when resolved, or else when started.
+func fixtureIssue(sample *report.Issue) Issue {
+	issue := Issue{
+		Key:         sample.Key,
+		Summary:     sample.Summary,
+		Status:      sample.Status,
+		Type:        sample.Type,
+		Done:        sample.Done,
+		Labels:      sample.Labels,
+		Components:  sample.Components,
+		Priority:    sample.Priority,
+		StoryPoints: sample.StoryPoints,
+		Parent:      sample.Parent,
+		Flagged:     sample.Flagged,
+		Links:       sample.Links,
+		Resolved:    sample.Resolved,
+		Due:         sample.Due,
+		Updated:     sample.Resolved,
+	}
+
+	if issue.Updated.IsZero() {
+		issue.Updated = sample.Started
+	}
+
+	if sample.SpilloverReason != "" {
+		issue.Comments = append(issue.Comments, sample.SpilloverReason)
+	}
+
+	if sample.Flagged && sample.BlockedReason != "" {
+		issue.Comments = append(issue.Comments, "(flag) Flag added\n\n"+sample.BlockedReason)
+	}
+
+	for _, s := range sample.Sprints {
+		if s.IsClosed() {
+			issue.Sprints = append(issue.Sprints, s.Name)
+		}
+	}
+
+	return issue
+}
+
+// Config returns the configuration of the sprint updates of the fixture
+// served by the fake Jira server, which can be adjusted before generating
+// the update, like setting the template or the format.
+func Config(server *httptest.Server, fixture *Fixture) sprint.Config {
+	// The URL of the test servers is always valid.
+	serverURL, _ := url.Parse(server.URL)
+
+	return sprint.Config{
+		ServerURL:        ServerURL,
+		Username:         "fixture",
+		Password:         "fixture",
+		Sprint:           fixture.Sprint.Name,
+		StoryPointsField: StoryPointsFieldID,
+		Transport:        &serverTransport{URL: serverURL, Transport: server.Client().Transport},
+	}
+}
+
+// serverTransport sends the requests to the fake server instead of their
+// host.
+type serverTransport struct {
+	URL       *url.URL
+	Transport http.RoundTripper
+}
+
+// RoundTrip sends the request to the fake server.
+func (t *serverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.URL.Scheme
+	req.URL.Host = t.URL.Host
+	req.Host = t.URL.Host
+
+	return t.Transport.RoundTrip(req)
+}
+
+// NewJiraServer starts a fake Jira server serving the issues and the sprint
+// of the fixture, which must be closed when no longer used. The fake server
+// does not evaluate the JQL queries: every search, and every request of the
+// issues of the sprint, returns every issue of the fixture, paginated as
+// requested.
+func NewJiraServer(fixture *Fixture) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/rest/api/2/field", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []gojira.Field{
+			{ID: "summary", Name: "Summary"},
+			{ID: SprintFieldID, Name: "Sprint", Custom: true, Schema: gojira.FieldSchema{Custom: "com.pyxis.greenhopper.jira:gh-sprint"}},
+			{ID: StoryPointsFieldID, Name: "Story Points", Custom: true},
+			{ID: FlaggedFieldID, Name: "Flagged", Custom: true},
+			{ID: RankFieldID, Name: "Rank", Custom: true},
+		})
+	})
+
+	mux.HandleFunc("/rest/api/2/search", fixture.writeIssues)
+
+	mux.HandleFunc("/rest/api/2/issue/", func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+		for i := range fixture.Issues {
+			if fixture.Issues[i].Key == key {
+				writeJSON(w, fixture.issue(&fixture.Issues[i], r))
+				return
+			}
+		}
+
+		writeError(w, http.StatusNotFound, "Issue does not exist or you do not have permission to see it.")
+	})
+
+	mux.HandleFunc("/rest/api/2/myself", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fixture.user())
+	})
+
+	mux.HandleFunc("/rest/api/2/user", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fixture.user())
+	})
+
+	mux.HandleFunc("/rest/api/2/priority", func(w http.ResponseWriter, r *http.Request) {
+		priorities := make([]gojira.Priority, 0, len(fixture.Priorities))
+		for i, name := range fixture.Priorities {
+			priorities = append(priorities, gojira.Priority{ID: strconv.Itoa(i + 1), Name: name})
+		}
+
+		writeJSON(w, priorities)
+	})
+
+	mux.HandleFunc("/rest/agile/1.0/sprint/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/rest/agile/1.0/sprint/"), "/")
+		if parts[0] != strconv.Itoa(fixture.Sprint.ID) {
+			writeError(w, http.StatusNotFound, "The requested sprint does not exist.")
+			return
+		}
+
+		switch {
+		case len(parts) == 1:
+			writeJSON(w, fixture.sprint())
+		case len(parts) == 2 && parts[1] == "issue":
+			fixture.writeIssues(w, r)
+		default:
+			writeError(w, http.StatusNotFound, "Unsupported by the fake Jira server: "+r.URL.Path)
+		}
+	})
+
+	mux.HandleFunc("/rest/greenhopper/1.0/rapid/charts/sprintreport", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, fixture.sprintReport())
+	})
+
+	mux.HandleFunc("/rest/agile/1.0/board/", func(w http.ResponseWriter, r *http.Request) {
+		var sprints []gojira.Sprint
+		if state := r.URL.Query().Get("state"); state == "" || containsFold(strings.Split(state, ","), fixture.Sprint.State) {
+			sprints = append(sprints, fixture.sprint())
+		}
+
+		writeJSON(w, map[string]interface{}{"isLast": true, "values": sprints})
+	})
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "Unsupported by the fake Jira server: "+r.URL.Path)
+	})
+
+	return httptest.NewServer(mux)
+}
+
+// writeIssues writes the page of the issues of the fixture requested by the
+// search or the sprint issues request, ignoring their JQL query.
+func (f *Fixture) writeIssues(w http.ResponseWriter, r *http.Request) {
+	startAt, _ := strconv.Atoi(r.URL.Query().Get("startAt"))
+	maxResults, _ := strconv.Atoi(r.URL.Query().Get("maxResults"))
+
+	issues := make([]*gojira.Issue, 0, len(f.Issues))
+	for i := range f.Issues {
+		if i >= startAt && (maxResults <= 0 || len(issues) < maxResults) {
+			issues = append(issues, f.issue(&f.Issues[i], r))
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"startAt":    startAt,
+		"maxResults": maxResults,
+		"total":      len(f.Issues),
+		"issues":     issues,
+	})
+}
+
+// issue returns the Jira issue of the fixture issue. The comments are only
+// returned when requested, like when fetching the activity of the issue.
+func (f *Fixture) issue(issue *Issue, r *http.Request) *gojira.Issue {
+	fields := &gojira.IssueFields{
+		Summary: issue.Summary,
+		Status: &gojira.Status{
+			Name:           issue.Status,
+			StatusCategory: gojira.StatusCategory{Key: statusCategory(issue.Done)},
+		},
+		Type:           gojira.IssueType{Name: issue.Type, Subtask: issue.Parent != "" && strings.EqualFold(issue.Type, "Sub-task")},
+		Project:        gojira.Project{Key: f.Project},
+		Labels:         issue.Labels,
+		Resolutiondate: gojira.Time(issue.Resolved),
+		Duedate:        gojira.Date(issue.Due),
+		Updated:        gojira.Time(issue.Updated),
+		Unknowns: map[string]interface{}{
+			SprintFieldID:      f.sprints(issue),
+			StoryPointsFieldID: issue.StoryPoints,
+			FlaggedFieldID:     flagged(issue.Flagged),
+			RankFieldID:        f.rank(issue),
+		},
+	}
+
+	if issue.Assignee != "" {
+		fields.Assignee = &gojira.User{AccountID: issue.Assignee, DisplayName: issue.Assignee}
+	}
+
+	if issue.Priority != "" {
+		fields.Priority = &gojira.Priority{Name: issue.Priority}
+	}
+
+	for _, component := range issue.Components {
+		fields.Components = append(fields.Components, &gojira.Component{Name: component})
+	}
+
+	if issue.Parent != "" {
+		fields.Parent = &gojira.Parent{Key: issue.Parent}
+	}
+
+	linked := make(map[string]bool)
+	for _, link := range issue.Links {
+		fields.IssueLinks = append(fields.IssueLinks, issueLink(&link))
+		linked[link.Key] = true
+	}
+
+	for _, key := range issue.BlockedBy {
+		if !linked[key] {
+			fields.IssueLinks = append(fields.IssueLinks, issueLink(&report.LinkedIssue{Relation: "is blocked by", Key: key, Status: "To Do"}))
+		}
+	}
+
+	for i := range f.Issues {
+		if f.Issues[i].Parent == issue.Key {
+			fields.Subtasks = append(fields.Subtasks, &gojira.Subtasks{
+				Key: f.Issues[i].Key,
+				Fields: gojira.IssueFields{Status: &gojira.Status{
+					Name:           f.Issues[i].Status,
+					StatusCategory: gojira.StatusCategory{Key: statusCategory(f.Issues[i].Done)},
+				}},
+			})
+		}
+	}
+
+	if strings.Contains(r.URL.Query().Get("fields"), "comment") {
+		fields.Comments = &gojira.Comments{}
+		for i, body := range issue.Comments {
+			fields.Comments.Comments = append(fields.Comments.Comments, &gojira.Comment{
+				ID:     strconv.Itoa(i + 1),
+				Author: *f.user(),
+				Body:   body,
+			})
+		}
+	}
+
+	return &gojira.Issue{Key: issue.Key, Fields: fields}
+}
+
+// sprints returns the value of the sprint field of the issue: the previous
+// sprints of the issue and the sprint of the fixture.
+func (f *Fixture) sprints(issue *Issue) []interface{} {
+	var sprints []interface{}
+	for i, name := range issue.Sprints {
+		sprints = append(sprints, map[string]interface{}{"id": i + 1, "name": name, "state": "closed"})
+	}
+
+	s := map[string]interface{}{"id": f.Sprint.ID, "name": f.Sprint.Name, "state": f.Sprint.State}
+	if f.Sprint.StartDate != nil {
+		s["startDate"] = f.Sprint.StartDate.Format(time.RFC3339)
+	}
+
+	if f.Sprint.EndDate != nil {
+		s["endDate"] = f.Sprint.EndDate.Format(time.RFC3339)
+	}
+
+	return append(sprints, s)
+}
+
+// rank returns the rank of the issue, which follows the order of the issues
+// of the fixture.
+func (f *Fixture) rank(issue *Issue) string {
+	for i := range f.Issues {
+		if f.Issues[i].Key == issue.Key {
+			return "0|i" + strconv.FormatInt(int64(1000+i), 36) + ":"
+		}
+	}
+
+	return ""
+}
+
+// sprint returns the Jira sprint of the fixture.
+func (f *Fixture) sprint() gojira.Sprint {
+	return gojira.Sprint{
+		ID:        f.Sprint.ID,
+		Name:      f.Sprint.Name,
+		State:     f.Sprint.State,
+		StartDate: f.Sprint.StartDate,
+		EndDate:   f.Sprint.EndDate,
+	}
+}
+
+// issueLink returns the Jira issue link of the linked issue. The "blocks" and
+// "is blocked by" relations are the directions of the "Blocks" link type,
+// the other relations are link types of their own.
+func issueLink(link *report.LinkedIssue) *gojira.IssueLink {
+	linked := &gojira.Issue{Key: link.Key, Fields: &gojira.IssueFields{
+		Summary: link.Summary,
+		Status:  &gojira.Status{Name: link.Status, StatusCategory: gojira.StatusCategory{Key: statusCategory(link.Done)}},
+	}}
+
+	if strings.EqualFold(link.Relation, "blocks") || strings.EqualFold(link.Relation, "is blocked by") {
+		blocks := gojira.IssueLinkType{Name: "Blocks", Inward: "is blocked by", Outward: "blocks"}
+		if strings.EqualFold(link.Relation, "blocks") {
+			return &gojira.IssueLink{Type: blocks, OutwardIssue: linked}
+		}
+
+		return &gojira.IssueLink{Type: blocks, InwardIssue: linked}
+	}
+
+	linkType := gojira.IssueLinkType{Name: link.Relation, Inward: link.Relation, Outward: link.Relation}
+	if strings.HasPrefix(strings.ToLower(link.Relation), "is ") {
+		return &gojira.IssueLink{Type: linkType, InwardIssue: linked}
+	}
+
+	return &gojira.IssueLink{Type: linkType, OutwardIssue: linked}
+}
+
+// sprintReport returns the sprint report of the board, listing the done issues
+// as completed, and the other issues as not completed.
+func (f *Fixture) sprintReport() map[string]interface{} {
+	completed := make([]interface{}, 0, len(f.Issues))
+	notCompleted := make([]interface{}, 0, len(f.Issues))
+	added := make(map[string]bool)
+
+	for i := range f.Issues {
+		issue := &f.Issues[i]
+		estimate := map[string]interface{}{"statFieldValue": map[string]interface{}{"value": issue.StoryPoints}}
+		entry := map[string]interface{}{"key": issue.Key, "estimateStatistic": estimate, "currentEstimateStatistic": estimate}
+
+		if issue.Done {
+			completed = append(completed, entry)
+		} else {
+			notCompleted = append(notCompleted, entry)
+		}
+
+		if issue.Added {
+			added[issue.Key] = true
+		}
+	}
+
+	return map[string]interface{}{
+		"contents": map[string]interface{}{
+			"completedIssues":                   completed,
+			"issuesNotCompletedInCurrentSprint": notCompleted,
+			"puntedIssues":                      []interface{}{},
+			"issueKeysAddedDuringSprint":        added,
+		},
+	}
+}
+
+// user returns the authenticated user.
+func (f *Fixture) user() *gojira.User {
+	return &gojira.User{AccountID: "fixture", Name: "fixture", DisplayName: f.User, Active: true}
+}
+
+// statusCategory returns the key of the status category of the done and the
+// unresolved issues.
+func statusCategory(done bool) string {
+	if done {
+		return gojira.StatusCategoryComplete
+	}
+
+	return gojira.StatusCategoryInProgress
+}
+
+// flagged returns the value of the flagged field of the issue.
+func flagged(isFlagged bool) []interface{} {
+	if !isFlagged {
+		return nil
+	}
+
+	return []interface{}{map[string]interface{}{"value": "Impediment"}}
+}
+
+// containsFold reports whether the values contain the value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeJSON writes the value as the JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error response in the format of Jira.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"errorMessages": []string{message}})
+}
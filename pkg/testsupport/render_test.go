@@ -0,0 +1,86 @@
+package testsupport_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gabor-boros/sprint-update/pkg/render"
+	"gabor-boros/sprint-update/pkg/report"
+	"gabor-boros/sprint-update/pkg/testsupport"
+)
+
+// goldenSections are the sections rendered by the golden files, leaving out
+// the ones read from git, the code hosts, or a language model.
+var goldenSections = []report.Section{
+	report.SectionWorkedOn,
+	report.SectionBlocked,
+	report.SectionDependencies,
+	report.SectionHours,
+	report.SectionSpillovers,
+	report.SectionKudos,
+	report.SectionTimeOff,
+	report.SectionStats,
+}
+
+func TestRenderFormats(t *testing.T) {
+	fixture := testsupport.DefaultFixture()
+	server := testsupport.NewJiraServer(fixture)
+	defer server.Close()
+
+	for _, format := range render.Formats() {
+		// The PDF documents are binary, hence not compared as text.
+		if format == "pdf" {
+			continue
+		}
+
+		t.Run(format, func(t *testing.T) {
+			config := testsupport.Config(server, fixture)
+			config.Format = format
+
+			text, err := testsupport.Render(context.Background(), config)
+			if err != nil {
+				t.Fatalf("rendering the update: %v", err)
+			}
+
+			testsupport.AssertGolden(t, filepath.Join("testdata", format+".golden"), text)
+		})
+	}
+}
+
+func TestRenderEndOfSprint(t *testing.T) {
+	fixture := testsupport.DefaultFixture()
+	server := testsupport.NewJiraServer(fixture)
+	defer server.Close()
+
+	config := testsupport.Config(server, fixture)
+	config.Format = "markdown"
+	config.Board = 1
+	config.EndOfSprint = true
+	config.Sections = goldenSections
+
+	text, err := testsupport.Render(context.Background(), config)
+	if err != nil {
+		t.Fatalf("rendering the update: %v", err)
+	}
+
+	testsupport.AssertGolden(t, filepath.Join("testdata", "end-of-sprint.golden"), text)
+}
+
+func TestRenderLanguage(t *testing.T) {
+	fixture := testsupport.DefaultFixture()
+	server := testsupport.NewJiraServer(fixture)
+	defer server.Close()
+
+	config := testsupport.Config(server, fixture)
+	config.Format = "markdown"
+	config.Language = "de"
+	config.Sections = goldenSections
+
+	text, err := testsupport.Render(context.Background(), config)
+	if err != nil {
+		t.Fatalf("rendering the update: %v", err)
+	}
+
+	testsupport.AssertGolden(t, filepath.Join("testdata", "language-de.golden"), text)
+}
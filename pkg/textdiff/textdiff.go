@@ -0,0 +1,201 @@
+// Package textdiff compares the rendered sprint updates line by line,
+// producing unified diffs, so a draft update can be reviewed against the one
+// it was revised from.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines printed around the changes.
+const DefaultContext = 3
+
+// op is the kind of an edit turning the old text into the new one.
+type op byte
+
+const (
+	opEqual  op = ' '
+	opDelete op = '-'
+	opInsert op = '+'
+)
+
+// edit is a line of the diff.
+type edit struct {
+	op   op
+	line string
+	// oldPos and newPos are the numbers of the lines of the old and the new
+	// text preceding the edit.
+	oldPos int
+	newPos int
+}
+
+// Unified returns the unified diff of the old and the new text, labeled with
+// their names, printing the given number of unchanged lines around the
+// changes. If the texts are the same, an empty string is returned.
+func Unified(oldName string, newName string, oldText string, newText string, context int) string {
+	edits := diffLines(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	for _, h := range hunks(edits, context) {
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+		}
+
+		writeHunk(&b, edits[h[0]:h[1]])
+	}
+
+	return b.String()
+}
+
+// Changes returns the number of removed and added lines between the old and
+// the new text.
+func Changes(oldText string, newText string) (int, int) {
+	removed, added := 0, 0
+	for _, e := range diffLines(splitLines(oldText), splitLines(newText)) {
+		switch e.op {
+		case opDelete:
+			removed++
+		case opInsert:
+			added++
+		}
+	}
+
+	return removed, added
+}
+
+// splitLines splits the text into lines, leaving out the line break at the
+// end of the text.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns the edits turning the old lines into the new ones, based
+// on their longest common subsequence. The removed lines are listed before
+// the added lines they are replaced by.
+func diffLines(oldLines []string, newLines []string) []edit {
+	// common[i][j] is the length of the longest common subsequence of
+	// oldLines[i:] and newLines[j:].
+	common := make([][]int, len(oldLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(newLines)+1)
+	}
+
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			switch {
+			case oldLines[i] == newLines[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	edits := make([]edit, 0, len(oldLines)+len(newLines))
+	i, j := 0, 0
+
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			edits = append(edits, edit{op: opEqual, line: oldLines[i], oldPos: i, newPos: j})
+			i++
+			j++
+		case j == len(newLines) || (i < len(oldLines) && common[i+1][j] >= common[i][j+1]):
+			edits = append(edits, edit{op: opDelete, line: oldLines[i], oldPos: i, newPos: j})
+			i++
+		default:
+			edits = append(edits, edit{op: opInsert, line: newLines[j], oldPos: i, newPos: j})
+			j++
+		}
+	}
+
+	return edits
+}
+
+// hunks returns the start and end indexes of the edits of every hunk: the
+// changed lines and the unchanged lines around them. Changes closer than
+// twice the context are merged into the same hunk.
+func hunks(edits []edit, context int) [][2]int {
+	var result [][2]int
+
+	for i := 0; i < len(edits); i++ {
+		if edits[i].op == opEqual {
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// The hunk ends once more than twice the context of unchanged lines
+		// follow the last change.
+		end, unchanged := i, 0
+		for ; end < len(edits) && unchanged <= 2*context; end++ {
+			if edits[end].op == opEqual {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+
+		end -= unchanged - context
+		if end > len(edits) {
+			end = len(edits)
+		}
+
+		if n := len(result); n > 0 && start <= result[n-1][1] {
+			result[n-1][1] = end
+		} else {
+			result = append(result, [2]int{start, end})
+		}
+
+		i = end - 1
+	}
+
+	return result
+}
+
+// writeHunk writes the header of the hunk, giving the ranges of its lines in
+// the old and the new text, followed by its lines.
+func writeHunk(b *strings.Builder, edits []edit) {
+	oldCount, newCount := 0, 0
+	for _, e := range edits {
+		if e.op != opInsert {
+			oldCount++
+		}
+
+		if e.op != opDelete {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(edits[0].oldPos, oldCount), hunkRange(edits[0].newPos, newCount))
+
+	for _, e := range edits {
+		b.WriteByte(byte(e.op))
+		b.WriteString(e.line)
+		b.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the range of the lines of the hunk following the given
+// number of lines, leaving out the count if it is one. Empty ranges start at
+// the line preceding them, as in the unified format of diff.
+func hunkRange(preceding int, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", preceding)
+	case 1:
+		return fmt.Sprintf("%d", preceding+1)
+	default:
+		return fmt.Sprintf("%d,%d", preceding+1, count)
+	}
+}
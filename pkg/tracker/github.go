@@ -0,0 +1,281 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/github"
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+const (
+	// DefaultGitHubIterationField is the name of the iteration field of the
+	// projects created by GitHub.
+	DefaultGitHubIterationField = "Iteration"
+	// DefaultGitHubStatusField is the name of the status field of the
+	// projects created by GitHub, whose values are the columns of the board.
+	DefaultGitHubStatusField = "Status"
+)
+
+// ErrMissingGitHubSource is returned when neither the repository of the
+// milestones nor the project of the iterations is set.
+var ErrMissingGitHubSource = errors.New("github repository or project is required")
+
+// GitHub fetches the issues of GitHub, treating either the milestones of a
+// repository or the iterations of a project as the sprints.
+type GitHub struct {
+	// Client is the GitHub API client.
+	Client *github.Client
+	// Repository is the full name of the repository whose milestones are the
+	// sprints, like "owner/repo". It is ignored if the project is set.
+	Repository string
+	// Project is the project whose iterations are the sprints.
+	Project *github.Project
+	// IterationField is the name of the iteration field of the project. When
+	// empty, DefaultGitHubIterationField is used.
+	IterationField string
+	// StatusField is the name of the field of the project the issues are
+	// grouped by. When empty, DefaultGitHubStatusField is used.
+	StatusField string
+}
+
+var _ Tracker = (*GitHub)(nil)
+
+// ParseGitHubProject parses the project given as the login of its owner and
+// its number, like "owner/5".
+func ParseGitHubProject(value string) (*github.Project, error) {
+	separator := strings.LastIndex(value, "/")
+	if separator <= 0 {
+		return nil, fmt.Errorf("invalid github project %q, expected owner/number", value)
+	}
+
+	number, err := strconv.Atoi(value[separator+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid github project %q, expected owner/number", value)
+	}
+
+	return &github.Project{Owner: value[:separator], Number: number}, nil
+}
+
+// Name returns the name of the tracker.
+func (g *GitHub) Name() string {
+	return "GitHub"
+}
+
+// Sprint returns the iteration of the project, or the milestone of the
+// repository, of the given title. If the title is empty, the current iteration
+// or the open milestone due next is returned.
+func (g *GitHub) Sprint(ctx context.Context, name string) (*Sprint, error) {
+	switch {
+	case g.Project != nil:
+		return g.iteration(ctx, name)
+	case g.Repository != "":
+		return g.milestone(ctx, name)
+	default:
+		return nil, ErrMissingGitHubSource
+	}
+}
+
+// Issues returns the issues of the sprint assigned to the authenticated user,
+// or to the user of the given login. The issues of a project are grouped by
+// their status field, and the issues of a milestone by their state.
+func (g *GitHub) Issues(ctx context.Context, sprint *Sprint, assignee string) ([]report.Issue, error) {
+	if assignee == "" {
+		login, err := g.Client.CurrentUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		assignee = login
+	}
+
+	if g.Project == nil {
+		number, err := strconv.Atoi(sprint.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		found, err := g.Client.MilestoneIssues(ctx, g.Repository, number, assignee)
+		if err != nil {
+			return nil, err
+		}
+
+		issues := make([]report.Issue, 0, len(found))
+		for i := range found {
+			issues = append(issues, g.newIssue(&found[i], ""))
+		}
+
+		return issues, nil
+	}
+
+	items, err := g.Client.ProjectItems(ctx, *g.Project, g.iterationField(), g.statusField())
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []report.Issue
+	for i := range items {
+		if items[i].IterationID == sprint.ID && isAssigned(&items[i].Issue, assignee) {
+			issues = append(issues, g.newIssue(&items[i].Issue, items[i].Status))
+		}
+	}
+
+	return issues, nil
+}
+
+// iteration returns the iteration of the project of the given title, or the
+// current iteration if the title is empty.
+func (g *GitHub) iteration(ctx context.Context, name string) (*Sprint, error) {
+	iterations, err := g.Client.ProjectIterations(ctx, *g.Project, g.iterationField())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range iterations {
+		iteration := &iterations[i]
+		endDate := iteration.EndDate()
+
+		if name == "" {
+			if iteration.StartDate.After(now) || !now.Before(endDate.AddDate(0, 0, 1)) {
+				continue
+			}
+		} else if !strings.EqualFold(iteration.Title, name) {
+			continue
+		}
+
+		return &Sprint{
+			ID:        iteration.ID,
+			Name:      iteration.Title,
+			StartDate: &iteration.StartDate,
+			EndDate:   &endDate,
+			Closed:    iteration.Completed,
+		}, nil
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("%w: the project has no current iteration", ErrSprintNotFound)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrSprintNotFound, name)
+}
+
+// milestone returns the milestone of the repository of the given title, or the
+// open milestone due next if the title is empty.
+func (g *GitHub) milestone(ctx context.Context, name string) (*Sprint, error) {
+	milestones, err := g.Client.Milestones(ctx, g.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	year, month, day := time.Now().Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+
+	var found *github.Milestone
+	for i := range milestones {
+		milestone := &milestones[i]
+
+		if name != "" {
+			if strings.EqualFold(milestone.Title, name) {
+				found = milestone
+				break
+			}
+
+			continue
+		}
+
+		if milestone.State != "open" || milestone.DueOn == nil || milestone.DueOn.Before(today) {
+			continue
+		}
+
+		if found == nil || milestone.DueOn.Before(*found.DueOn) {
+			found = milestone
+		}
+	}
+
+	if found == nil {
+		if name == "" {
+			return nil, fmt.Errorf("%w: the repository has no open milestone due", ErrSprintNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrSprintNotFound, name)
+	}
+
+	return &Sprint{
+		ID:      strconv.Itoa(found.Number),
+		Name:    found.Title,
+		EndDate: found.DueOn,
+		Closed:  found.State == "closed",
+	}, nil
+}
+
+// newIssue returns the issue of the update from the GitHub issue. If the
+// status is empty, the state of the issue is used, like "Open".
+func (g *GitHub) newIssue(issue *github.Issue, status string) report.Issue {
+	key := fmt.Sprintf("%s#%d", issue.Repository, issue.Number)
+	if strings.EqualFold(issue.Repository, g.Repository) {
+		key = fmt.Sprintf("#%d", issue.Number)
+	}
+
+	switch {
+	case status != "":
+	case issue.IsClosed():
+		status = "Closed"
+	default:
+		status = "Open"
+	}
+
+	transformedIssue := report.Issue{
+		Key:      key,
+		Summary:  issue.Title,
+		URL:      issue.URL,
+		Status:   status,
+		Done:     issue.IsClosed() && issue.StateReason != "not_planned",
+		Assignee: strings.Join(issue.Assignees, ", "),
+		Project:  issue.Repository,
+		Labels:   issue.Labels,
+	}
+
+	if issue.IsClosed() {
+		transformedIssue.StatusCategory = report.StatusCategoryDone
+	}
+
+	if issue.ClosedAt != nil {
+		transformedIssue.Resolved = *issue.ClosedAt
+	}
+
+	return transformedIssue
+}
+
+// iterationField returns the name of the iteration field of the project.
+func (g *GitHub) iterationField() string {
+	if g.IterationField == "" {
+		return DefaultGitHubIterationField
+	}
+
+	return g.IterationField
+}
+
+// statusField returns the name of the status field of the project.
+func (g *GitHub) statusField() string {
+	if g.StatusField == "" {
+		return DefaultGitHubStatusField
+	}
+
+	return g.StatusField
+}
+
+// isAssigned reports whether the issue is assigned to the user of the login.
+func isAssigned(issue *github.Issue, login string) bool {
+	for _, assignee := range issue.Assignees {
+		if strings.EqualFold(assignee, login) {
+			return true
+		}
+	}
+
+	return false
+}
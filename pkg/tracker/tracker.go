@@ -0,0 +1,43 @@
+// Package tracker defines the issue trackers the sprint updates can be
+// generated from instead of Jira, like Linear, Azure Boards, or GitHub.
+package tracker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gabor-boros/sprint-update/pkg/report"
+)
+
+// ErrSprintNotFound is returned when the sprint of the tracker, like the
+// Linear cycle or the Azure Boards iteration, cannot be found.
+var ErrSprintNotFound = errors.New("sprint not found")
+
+// Sprint is the sprint of a tracker, like a Linear cycle or an Azure Boards
+// iteration.
+type Sprint struct {
+	// ID identifies the sprint within the tracker, like the ID of the Linear
+	// cycle or the path of the Azure Boards iteration.
+	ID string
+	// Name is the name of the sprint used in the update.
+	Name string
+	// StartDate and EndDate are the first and the last day of the sprint.
+	// They are nil if the sprint has no dates.
+	StartDate *time.Time
+	EndDate   *time.Time
+	// Closed indicates that the sprint is completed.
+	Closed bool
+}
+
+// Tracker fetches the sprints and the issues of an issue tracker.
+type Tracker interface {
+	// Name returns the name of the tracker, like "Linear".
+	Name() string
+	// Sprint returns the sprint of the given name, or the current sprint if
+	// the name is empty.
+	Sprint(ctx context.Context, name string) (*Sprint, error)
+	// Issues returns the issues of the sprint assigned to the authenticated
+	// user, or to the given assignee if it is set.
+	Issues(ctx context.Context, sprint *Sprint, assignee string) ([]report.Issue, error)
+}